@@ -0,0 +1,1127 @@
+// Automation API for driving Converzen as a conversion engine from other
+// tools, served on localhost by internal/grpcapi when grpc_port is set.
+// Clients authenticate with the api_token as a bearer token in the
+// "authorization" metadata.
+//
+// The Go code in api/converzenv1 is generated; regenerate it after editing
+// this file with:
+//
+//   protoc --go_out=. --go_opt=module=converzen \
+//     --go-grpc_out=. --go-grpc_opt=module=converzen \
+//     api/proto/converzen/v1/converzen.proto
+//
+// Messages mirror the JSON models in internal/models; field names follow
+// their JSON names.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.12
+// 	protoc        v5.29.3
+// source: api/proto/converzen/v1/converzen.proto
+
+package converzenv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type BatchConversionRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Files           []string               `protobuf:"bytes,1,rep,name=files,proto3" json:"files,omitempty"`
+	OutputFormat    string                 `protobuf:"bytes,2,opt,name=output_format,json=outputFormat,proto3" json:"output_format,omitempty"`
+	OutputDirectory string                 `protobuf:"bytes,3,opt,name=output_directory,json=outputDirectory,proto3" json:"output_directory,omitempty"` // Empty writes next to the inputs or to the format's route
+	NamingMode      string                 `protobuf:"bytes,4,opt,name=naming_mode,json=namingMode,proto3" json:"naming_mode,omitempty"`                // "original", "custom" or "template"
+	CustomNames     []string               `protobuf:"bytes,5,rep,name=custom_names,json=customNames,proto3" json:"custom_names,omitempty"`
+	NameTemplate    string                 `protobuf:"bytes,6,opt,name=name_template,json=nameTemplate,proto3" json:"name_template,omitempty"`
+	MakeCopies      bool                   `protobuf:"varint,7,opt,name=make_copies,json=makeCopies,proto3" json:"make_copies,omitempty"`
+	// Remaining options as the JSON encoding of models.BatchConversionRequest,
+	// so new options don't need a schema change. Fields set above take
+	// precedence.
+	OptionsJson   string `protobuf:"bytes,15,opt,name=options_json,json=optionsJson,proto3" json:"options_json,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchConversionRequest) Reset() {
+	*x = BatchConversionRequest{}
+	mi := &file_api_proto_converzen_v1_converzen_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchConversionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchConversionRequest) ProtoMessage() {}
+
+func (x *BatchConversionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_converzen_v1_converzen_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchConversionRequest.ProtoReflect.Descriptor instead.
+func (*BatchConversionRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_converzen_v1_converzen_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *BatchConversionRequest) GetFiles() []string {
+	if x != nil {
+		return x.Files
+	}
+	return nil
+}
+
+func (x *BatchConversionRequest) GetOutputFormat() string {
+	if x != nil {
+		return x.OutputFormat
+	}
+	return ""
+}
+
+func (x *BatchConversionRequest) GetOutputDirectory() string {
+	if x != nil {
+		return x.OutputDirectory
+	}
+	return ""
+}
+
+func (x *BatchConversionRequest) GetNamingMode() string {
+	if x != nil {
+		return x.NamingMode
+	}
+	return ""
+}
+
+func (x *BatchConversionRequest) GetCustomNames() []string {
+	if x != nil {
+		return x.CustomNames
+	}
+	return nil
+}
+
+func (x *BatchConversionRequest) GetNameTemplate() string {
+	if x != nil {
+		return x.NameTemplate
+	}
+	return ""
+}
+
+func (x *BatchConversionRequest) GetMakeCopies() bool {
+	if x != nil {
+		return x.MakeCopies
+	}
+	return false
+}
+
+func (x *BatchConversionRequest) GetOptionsJson() string {
+	if x != nil {
+		return x.OptionsJson
+	}
+	return ""
+}
+
+type BatchEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Event:
+	//
+	//	*BatchEvent_Progress
+	//	*BatchEvent_Result
+	Event         isBatchEvent_Event `protobuf_oneof:"event"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchEvent) Reset() {
+	*x = BatchEvent{}
+	mi := &file_api_proto_converzen_v1_converzen_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchEvent) ProtoMessage() {}
+
+func (x *BatchEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_converzen_v1_converzen_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchEvent.ProtoReflect.Descriptor instead.
+func (*BatchEvent) Descriptor() ([]byte, []int) {
+	return file_api_proto_converzen_v1_converzen_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *BatchEvent) GetEvent() isBatchEvent_Event {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+func (x *BatchEvent) GetProgress() *ConversionProgress {
+	if x != nil {
+		if x, ok := x.Event.(*BatchEvent_Progress); ok {
+			return x.Progress
+		}
+	}
+	return nil
+}
+
+func (x *BatchEvent) GetResult() *BatchConversionResult {
+	if x != nil {
+		if x, ok := x.Event.(*BatchEvent_Result); ok {
+			return x.Result
+		}
+	}
+	return nil
+}
+
+type isBatchEvent_Event interface {
+	isBatchEvent_Event()
+}
+
+type BatchEvent_Progress struct {
+	Progress *ConversionProgress `protobuf:"bytes,1,opt,name=progress,proto3,oneof"`
+}
+
+type BatchEvent_Result struct {
+	Result *BatchConversionResult `protobuf:"bytes,2,opt,name=result,proto3,oneof"`
+}
+
+func (*BatchEvent_Progress) isBatchEvent_Event() {}
+
+func (*BatchEvent_Result) isBatchEvent_Event() {}
+
+type WatchProgressRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchProgressRequest) Reset() {
+	*x = WatchProgressRequest{}
+	mi := &file_api_proto_converzen_v1_converzen_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchProgressRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchProgressRequest) ProtoMessage() {}
+
+func (x *WatchProgressRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_converzen_v1_converzen_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchProgressRequest.ProtoReflect.Descriptor instead.
+func (*WatchProgressRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_converzen_v1_converzen_proto_rawDescGZIP(), []int{2}
+}
+
+type ConversionProgress struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	InputPath     string                 `protobuf:"bytes,2,opt,name=input_path,json=inputPath,proto3" json:"input_path,omitempty"`
+	Progress      float64                `protobuf:"fixed64,3,opt,name=progress,proto3" json:"progress,omitempty"` // 0-100
+	Status        string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	Result        *ConversionResult      `protobuf:"bytes,5,opt,name=result,proto3" json:"result,omitempty"` // Set on the final event for a file
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConversionProgress) Reset() {
+	*x = ConversionProgress{}
+	mi := &file_api_proto_converzen_v1_converzen_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConversionProgress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConversionProgress) ProtoMessage() {}
+
+func (x *ConversionProgress) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_converzen_v1_converzen_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConversionProgress.ProtoReflect.Descriptor instead.
+func (*ConversionProgress) Descriptor() ([]byte, []int) {
+	return file_api_proto_converzen_v1_converzen_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ConversionProgress) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ConversionProgress) GetInputPath() string {
+	if x != nil {
+		return x.InputPath
+	}
+	return ""
+}
+
+func (x *ConversionProgress) GetProgress() float64 {
+	if x != nil {
+		return x.Progress
+	}
+	return 0
+}
+
+func (x *ConversionProgress) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ConversionProgress) GetResult() *ConversionResult {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+type ConversionResult struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Success        bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	InputPath      string                 `protobuf:"bytes,2,opt,name=input_path,json=inputPath,proto3" json:"input_path,omitempty"`
+	OutputPath     string                 `protobuf:"bytes,3,opt,name=output_path,json=outputPath,proto3" json:"output_path,omitempty"`
+	OutputSize     int64                  `protobuf:"varint,4,opt,name=output_size,json=outputSize,proto3" json:"output_size,omitempty"`
+	ErrorMessage   string                 `protobuf:"bytes,5,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	Duration       int64                  `protobuf:"varint,6,opt,name=duration,proto3" json:"duration,omitempty"` // Milliseconds
+	Method         string                 `protobuf:"bytes,7,opt,name=method,proto3" json:"method,omitempty"`
+	Warnings       []string               `protobuf:"bytes,8,rep,name=warnings,proto3" json:"warnings,omitempty"`
+	Skipped        bool                   `protobuf:"varint,9,opt,name=skipped,proto3" json:"skipped,omitempty"`
+	InputSize      int64                  `protobuf:"varint,10,opt,name=input_size,json=inputSize,proto3" json:"input_size,omitempty"`
+	SavingsPercent float64                `protobuf:"fixed64,11,opt,name=savings_percent,json=savingsPercent,proto3" json:"savings_percent,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ConversionResult) Reset() {
+	*x = ConversionResult{}
+	mi := &file_api_proto_converzen_v1_converzen_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConversionResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConversionResult) ProtoMessage() {}
+
+func (x *ConversionResult) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_converzen_v1_converzen_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConversionResult.ProtoReflect.Descriptor instead.
+func (*ConversionResult) Descriptor() ([]byte, []int) {
+	return file_api_proto_converzen_v1_converzen_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ConversionResult) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *ConversionResult) GetInputPath() string {
+	if x != nil {
+		return x.InputPath
+	}
+	return ""
+}
+
+func (x *ConversionResult) GetOutputPath() string {
+	if x != nil {
+		return x.OutputPath
+	}
+	return ""
+}
+
+func (x *ConversionResult) GetOutputSize() int64 {
+	if x != nil {
+		return x.OutputSize
+	}
+	return 0
+}
+
+func (x *ConversionResult) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+func (x *ConversionResult) GetDuration() int64 {
+	if x != nil {
+		return x.Duration
+	}
+	return 0
+}
+
+func (x *ConversionResult) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *ConversionResult) GetWarnings() []string {
+	if x != nil {
+		return x.Warnings
+	}
+	return nil
+}
+
+func (x *ConversionResult) GetSkipped() bool {
+	if x != nil {
+		return x.Skipped
+	}
+	return false
+}
+
+func (x *ConversionResult) GetInputSize() int64 {
+	if x != nil {
+		return x.InputSize
+	}
+	return 0
+}
+
+func (x *ConversionResult) GetSavingsPercent() float64 {
+	if x != nil {
+		return x.SavingsPercent
+	}
+	return 0
+}
+
+type BatchConversionResult struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	BatchId        string                 `protobuf:"bytes,1,opt,name=batch_id,json=batchId,proto3" json:"batch_id,omitempty"`
+	TotalFiles     int32                  `protobuf:"varint,2,opt,name=total_files,json=totalFiles,proto3" json:"total_files,omitempty"`
+	SuccessCount   int32                  `protobuf:"varint,3,opt,name=success_count,json=successCount,proto3" json:"success_count,omitempty"`
+	FailCount      int32                  `protobuf:"varint,4,opt,name=fail_count,json=failCount,proto3" json:"fail_count,omitempty"`
+	SkippedCount   int32                  `protobuf:"varint,5,opt,name=skipped_count,json=skippedCount,proto3" json:"skipped_count,omitempty"`
+	Results        []*ConversionResult    `protobuf:"bytes,6,rep,name=results,proto3" json:"results,omitempty"`
+	HasMoreResults bool                   `protobuf:"varint,7,opt,name=has_more_results,json=hasMoreResults,proto3" json:"has_more_results,omitempty"`
+	TotalDuration  int64                  `protobuf:"varint,8,opt,name=total_duration,json=totalDuration,proto3" json:"total_duration,omitempty"` // Milliseconds
+	Warnings       []string               `protobuf:"bytes,9,rep,name=warnings,proto3" json:"warnings,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *BatchConversionResult) Reset() {
+	*x = BatchConversionResult{}
+	mi := &file_api_proto_converzen_v1_converzen_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchConversionResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchConversionResult) ProtoMessage() {}
+
+func (x *BatchConversionResult) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_converzen_v1_converzen_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchConversionResult.ProtoReflect.Descriptor instead.
+func (*BatchConversionResult) Descriptor() ([]byte, []int) {
+	return file_api_proto_converzen_v1_converzen_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *BatchConversionResult) GetBatchId() string {
+	if x != nil {
+		return x.BatchId
+	}
+	return ""
+}
+
+func (x *BatchConversionResult) GetTotalFiles() int32 {
+	if x != nil {
+		return x.TotalFiles
+	}
+	return 0
+}
+
+func (x *BatchConversionResult) GetSuccessCount() int32 {
+	if x != nil {
+		return x.SuccessCount
+	}
+	return 0
+}
+
+func (x *BatchConversionResult) GetFailCount() int32 {
+	if x != nil {
+		return x.FailCount
+	}
+	return 0
+}
+
+func (x *BatchConversionResult) GetSkippedCount() int32 {
+	if x != nil {
+		return x.SkippedCount
+	}
+	return 0
+}
+
+func (x *BatchConversionResult) GetResults() []*ConversionResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *BatchConversionResult) GetHasMoreResults() bool {
+	if x != nil {
+		return x.HasMoreResults
+	}
+	return false
+}
+
+func (x *BatchConversionResult) GetTotalDuration() int64 {
+	if x != nil {
+		return x.TotalDuration
+	}
+	return 0
+}
+
+func (x *BatchConversionResult) GetWarnings() []string {
+	if x != nil {
+		return x.Warnings
+	}
+	return nil
+}
+
+type CancelConversionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelConversionRequest) Reset() {
+	*x = CancelConversionRequest{}
+	mi := &file_api_proto_converzen_v1_converzen_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelConversionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelConversionRequest) ProtoMessage() {}
+
+func (x *CancelConversionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_converzen_v1_converzen_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelConversionRequest.ProtoReflect.Descriptor instead.
+func (*CancelConversionRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_converzen_v1_converzen_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *CancelConversionRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type CancelConversionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelConversionResponse) Reset() {
+	*x = CancelConversionResponse{}
+	mi := &file_api_proto_converzen_v1_converzen_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelConversionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelConversionResponse) ProtoMessage() {}
+
+func (x *CancelConversionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_converzen_v1_converzen_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelConversionResponse.ProtoReflect.Descriptor instead.
+func (*CancelConversionResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_converzen_v1_converzen_proto_rawDescGZIP(), []int{7}
+}
+
+type HistoryFilter struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Statuses        []string               `protobuf:"bytes,1,rep,name=statuses,proto3" json:"statuses,omitempty"`
+	FileType        string                 `protobuf:"bytes,2,opt,name=file_type,json=fileType,proto3" json:"file_type,omitempty"`
+	OutputFormat    string                 `protobuf:"bytes,3,opt,name=output_format,json=outputFormat,proto3" json:"output_format,omitempty"`
+	From            *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=from,proto3" json:"from,omitempty"` // Created at or after
+	To              *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=to,proto3" json:"to,omitempty"`     // Created before
+	Search          string                 `protobuf:"bytes,6,opt,name=search,proto3" json:"search,omitempty"`
+	Limit           int32                  `protobuf:"varint,7,opt,name=limit,proto3" json:"limit,omitempty"` // 0 returns all matches
+	IncludeArchived bool                   `protobuf:"varint,8,opt,name=include_archived,json=includeArchived,proto3" json:"include_archived,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *HistoryFilter) Reset() {
+	*x = HistoryFilter{}
+	mi := &file_api_proto_converzen_v1_converzen_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HistoryFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HistoryFilter) ProtoMessage() {}
+
+func (x *HistoryFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_converzen_v1_converzen_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HistoryFilter.ProtoReflect.Descriptor instead.
+func (*HistoryFilter) Descriptor() ([]byte, []int) {
+	return file_api_proto_converzen_v1_converzen_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *HistoryFilter) GetStatuses() []string {
+	if x != nil {
+		return x.Statuses
+	}
+	return nil
+}
+
+func (x *HistoryFilter) GetFileType() string {
+	if x != nil {
+		return x.FileType
+	}
+	return ""
+}
+
+func (x *HistoryFilter) GetOutputFormat() string {
+	if x != nil {
+		return x.OutputFormat
+	}
+	return ""
+}
+
+func (x *HistoryFilter) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *HistoryFilter) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *HistoryFilter) GetSearch() string {
+	if x != nil {
+		return x.Search
+	}
+	return ""
+}
+
+func (x *HistoryFilter) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *HistoryFilter) GetIncludeArchived() bool {
+	if x != nil {
+		return x.IncludeArchived
+	}
+	return false
+}
+
+type ConversionRecord struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	BatchId       string                 `protobuf:"bytes,2,opt,name=batch_id,json=batchId,proto3" json:"batch_id,omitempty"`
+	InputPath     string                 `protobuf:"bytes,3,opt,name=input_path,json=inputPath,proto3" json:"input_path,omitempty"`
+	OutputPath    string                 `protobuf:"bytes,4,opt,name=output_path,json=outputPath,proto3" json:"output_path,omitempty"`
+	InputFormat   string                 `protobuf:"bytes,5,opt,name=input_format,json=inputFormat,proto3" json:"input_format,omitempty"`
+	OutputFormat  string                 `protobuf:"bytes,6,opt,name=output_format,json=outputFormat,proto3" json:"output_format,omitempty"`
+	FileType      string                 `protobuf:"bytes,7,opt,name=file_type,json=fileType,proto3" json:"file_type,omitempty"`
+	FileSize      int64                  `protobuf:"varint,8,opt,name=file_size,json=fileSize,proto3" json:"file_size,omitempty"`
+	OutputSize    int64                  `protobuf:"varint,9,opt,name=output_size,json=outputSize,proto3" json:"output_size,omitempty"`
+	Status        string                 `protobuf:"bytes,10,opt,name=status,proto3" json:"status,omitempty"`
+	ErrorMessage  string                 `protobuf:"bytes,11,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	CompletedAt   *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=completed_at,json=completedAt,proto3" json:"completed_at,omitempty"`
+	Backend       string                 `protobuf:"bytes,14,opt,name=backend,proto3" json:"backend,omitempty"`
+	Note          string                 `protobuf:"bytes,15,opt,name=note,proto3" json:"note,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConversionRecord) Reset() {
+	*x = ConversionRecord{}
+	mi := &file_api_proto_converzen_v1_converzen_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConversionRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConversionRecord) ProtoMessage() {}
+
+func (x *ConversionRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_converzen_v1_converzen_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConversionRecord.ProtoReflect.Descriptor instead.
+func (*ConversionRecord) Descriptor() ([]byte, []int) {
+	return file_api_proto_converzen_v1_converzen_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ConversionRecord) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ConversionRecord) GetBatchId() string {
+	if x != nil {
+		return x.BatchId
+	}
+	return ""
+}
+
+func (x *ConversionRecord) GetInputPath() string {
+	if x != nil {
+		return x.InputPath
+	}
+	return ""
+}
+
+func (x *ConversionRecord) GetOutputPath() string {
+	if x != nil {
+		return x.OutputPath
+	}
+	return ""
+}
+
+func (x *ConversionRecord) GetInputFormat() string {
+	if x != nil {
+		return x.InputFormat
+	}
+	return ""
+}
+
+func (x *ConversionRecord) GetOutputFormat() string {
+	if x != nil {
+		return x.OutputFormat
+	}
+	return ""
+}
+
+func (x *ConversionRecord) GetFileType() string {
+	if x != nil {
+		return x.FileType
+	}
+	return ""
+}
+
+func (x *ConversionRecord) GetFileSize() int64 {
+	if x != nil {
+		return x.FileSize
+	}
+	return 0
+}
+
+func (x *ConversionRecord) GetOutputSize() int64 {
+	if x != nil {
+		return x.OutputSize
+	}
+	return 0
+}
+
+func (x *ConversionRecord) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ConversionRecord) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+func (x *ConversionRecord) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *ConversionRecord) GetCompletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CompletedAt
+	}
+	return nil
+}
+
+func (x *ConversionRecord) GetBackend() string {
+	if x != nil {
+		return x.Backend
+	}
+	return ""
+}
+
+func (x *ConversionRecord) GetNote() string {
+	if x != nil {
+		return x.Note
+	}
+	return ""
+}
+
+type ListHistoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Conversions   []*ConversionRecord    `protobuf:"bytes,1,rep,name=conversions,proto3" json:"conversions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListHistoryResponse) Reset() {
+	*x = ListHistoryResponse{}
+	mi := &file_api_proto_converzen_v1_converzen_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListHistoryResponse) ProtoMessage() {}
+
+func (x *ListHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_converzen_v1_converzen_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListHistoryResponse.ProtoReflect.Descriptor instead.
+func (*ListHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_converzen_v1_converzen_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ListHistoryResponse) GetConversions() []*ConversionRecord {
+	if x != nil {
+		return x.Conversions
+	}
+	return nil
+}
+
+var File_api_proto_converzen_v1_converzen_proto protoreflect.FileDescriptor
+
+const file_api_proto_converzen_v1_converzen_proto_rawDesc = "" +
+	"\n" +
+	"&api/proto/converzen/v1/converzen.proto\x12\fconverzen.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xab\x02\n" +
+	"\x16BatchConversionRequest\x12\x14\n" +
+	"\x05files\x18\x01 \x03(\tR\x05files\x12#\n" +
+	"\routput_format\x18\x02 \x01(\tR\foutputFormat\x12)\n" +
+	"\x10output_directory\x18\x03 \x01(\tR\x0foutputDirectory\x12\x1f\n" +
+	"\vnaming_mode\x18\x04 \x01(\tR\n" +
+	"namingMode\x12!\n" +
+	"\fcustom_names\x18\x05 \x03(\tR\vcustomNames\x12#\n" +
+	"\rname_template\x18\x06 \x01(\tR\fnameTemplate\x12\x1f\n" +
+	"\vmake_copies\x18\a \x01(\bR\n" +
+	"makeCopies\x12!\n" +
+	"\foptions_json\x18\x0f \x01(\tR\voptionsJson\"\x94\x01\n" +
+	"\n" +
+	"BatchEvent\x12>\n" +
+	"\bprogress\x18\x01 \x01(\v2 .converzen.v1.ConversionProgressH\x00R\bprogress\x12=\n" +
+	"\x06result\x18\x02 \x01(\v2#.converzen.v1.BatchConversionResultH\x00R\x06resultB\a\n" +
+	"\x05event\"\x16\n" +
+	"\x14WatchProgressRequest\"\xaf\x01\n" +
+	"\x12ConversionProgress\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x1d\n" +
+	"\n" +
+	"input_path\x18\x02 \x01(\tR\tinputPath\x12\x1a\n" +
+	"\bprogress\x18\x03 \x01(\x01R\bprogress\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x126\n" +
+	"\x06result\x18\x05 \x01(\v2\x1e.converzen.v1.ConversionResultR\x06result\"\xe4\x02\n" +
+	"\x10ConversionResult\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x1d\n" +
+	"\n" +
+	"input_path\x18\x02 \x01(\tR\tinputPath\x12\x1f\n" +
+	"\voutput_path\x18\x03 \x01(\tR\n" +
+	"outputPath\x12\x1f\n" +
+	"\voutput_size\x18\x04 \x01(\x03R\n" +
+	"outputSize\x12#\n" +
+	"\rerror_message\x18\x05 \x01(\tR\ferrorMessage\x12\x1a\n" +
+	"\bduration\x18\x06 \x01(\x03R\bduration\x12\x16\n" +
+	"\x06method\x18\a \x01(\tR\x06method\x12\x1a\n" +
+	"\bwarnings\x18\b \x03(\tR\bwarnings\x12\x18\n" +
+	"\askipped\x18\t \x01(\bR\askipped\x12\x1d\n" +
+	"\n" +
+	"input_size\x18\n" +
+	" \x01(\x03R\tinputSize\x12'\n" +
+	"\x0fsavings_percent\x18\v \x01(\x01R\x0esavingsPercent\"\xe3\x02\n" +
+	"\x15BatchConversionResult\x12\x19\n" +
+	"\bbatch_id\x18\x01 \x01(\tR\abatchId\x12\x1f\n" +
+	"\vtotal_files\x18\x02 \x01(\x05R\n" +
+	"totalFiles\x12#\n" +
+	"\rsuccess_count\x18\x03 \x01(\x05R\fsuccessCount\x12\x1d\n" +
+	"\n" +
+	"fail_count\x18\x04 \x01(\x05R\tfailCount\x12#\n" +
+	"\rskipped_count\x18\x05 \x01(\x05R\fskippedCount\x128\n" +
+	"\aresults\x18\x06 \x03(\v2\x1e.converzen.v1.ConversionResultR\aresults\x12(\n" +
+	"\x10has_more_results\x18\a \x01(\bR\x0ehasMoreResults\x12%\n" +
+	"\x0etotal_duration\x18\b \x01(\x03R\rtotalDuration\x12\x1a\n" +
+	"\bwarnings\x18\t \x03(\tR\bwarnings\")\n" +
+	"\x17CancelConversionRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\"\x1a\n" +
+	"\x18CancelConversionResponse\"\xa2\x02\n" +
+	"\rHistoryFilter\x12\x1a\n" +
+	"\bstatuses\x18\x01 \x03(\tR\bstatuses\x12\x1b\n" +
+	"\tfile_type\x18\x02 \x01(\tR\bfileType\x12#\n" +
+	"\routput_format\x18\x03 \x01(\tR\foutputFormat\x12.\n" +
+	"\x04from\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x12*\n" +
+	"\x02to\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x02to\x12\x16\n" +
+	"\x06search\x18\x06 \x01(\tR\x06search\x12\x14\n" +
+	"\x05limit\x18\a \x01(\x05R\x05limit\x12)\n" +
+	"\x10include_archived\x18\b \x01(\bR\x0fincludeArchived\"\x85\x04\n" +
+	"\x10ConversionRecord\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x19\n" +
+	"\bbatch_id\x18\x02 \x01(\tR\abatchId\x12\x1d\n" +
+	"\n" +
+	"input_path\x18\x03 \x01(\tR\tinputPath\x12\x1f\n" +
+	"\voutput_path\x18\x04 \x01(\tR\n" +
+	"outputPath\x12!\n" +
+	"\finput_format\x18\x05 \x01(\tR\vinputFormat\x12#\n" +
+	"\routput_format\x18\x06 \x01(\tR\foutputFormat\x12\x1b\n" +
+	"\tfile_type\x18\a \x01(\tR\bfileType\x12\x1b\n" +
+	"\tfile_size\x18\b \x01(\x03R\bfileSize\x12\x1f\n" +
+	"\voutput_size\x18\t \x01(\x03R\n" +
+	"outputSize\x12\x16\n" +
+	"\x06status\x18\n" +
+	" \x01(\tR\x06status\x12#\n" +
+	"\rerror_message\x18\v \x01(\tR\ferrorMessage\x129\n" +
+	"\n" +
+	"created_at\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12=\n" +
+	"\fcompleted_at\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\vcompletedAt\x12\x18\n" +
+	"\abackend\x18\x0e \x01(\tR\abackend\x12\x12\n" +
+	"\x04note\x18\x0f \x01(\tR\x04note\"W\n" +
+	"\x13ListHistoryResponse\x12@\n" +
+	"\vconversions\x18\x01 \x03(\v2\x1e.converzen.v1.ConversionRecordR\vconversions2\xe8\x02\n" +
+	"\n" +
+	"Conversion\x12O\n" +
+	"\vSubmitBatch\x12$.converzen.v1.BatchConversionRequest\x1a\x18.converzen.v1.BatchEvent0\x01\x12W\n" +
+	"\rWatchProgress\x12\".converzen.v1.WatchProgressRequest\x1a .converzen.v1.ConversionProgress0\x01\x12a\n" +
+	"\x10CancelConversion\x12%.converzen.v1.CancelConversionRequest\x1a&.converzen.v1.CancelConversionResponse\x12M\n" +
+	"\vListHistory\x12\x1b.converzen.v1.HistoryFilter\x1a!.converzen.v1.ListHistoryResponseB\x1bZ\x19converzen/api/converzenv1b\x06proto3"
+
+var (
+	file_api_proto_converzen_v1_converzen_proto_rawDescOnce sync.Once
+	file_api_proto_converzen_v1_converzen_proto_rawDescData []byte
+)
+
+func file_api_proto_converzen_v1_converzen_proto_rawDescGZIP() []byte {
+	file_api_proto_converzen_v1_converzen_proto_rawDescOnce.Do(func() {
+		file_api_proto_converzen_v1_converzen_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_api_proto_converzen_v1_converzen_proto_rawDesc), len(file_api_proto_converzen_v1_converzen_proto_rawDesc)))
+	})
+	return file_api_proto_converzen_v1_converzen_proto_rawDescData
+}
+
+var file_api_proto_converzen_v1_converzen_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_api_proto_converzen_v1_converzen_proto_goTypes = []any{
+	(*BatchConversionRequest)(nil),   // 0: converzen.v1.BatchConversionRequest
+	(*BatchEvent)(nil),               // 1: converzen.v1.BatchEvent
+	(*WatchProgressRequest)(nil),     // 2: converzen.v1.WatchProgressRequest
+	(*ConversionProgress)(nil),       // 3: converzen.v1.ConversionProgress
+	(*ConversionResult)(nil),         // 4: converzen.v1.ConversionResult
+	(*BatchConversionResult)(nil),    // 5: converzen.v1.BatchConversionResult
+	(*CancelConversionRequest)(nil),  // 6: converzen.v1.CancelConversionRequest
+	(*CancelConversionResponse)(nil), // 7: converzen.v1.CancelConversionResponse
+	(*HistoryFilter)(nil),            // 8: converzen.v1.HistoryFilter
+	(*ConversionRecord)(nil),         // 9: converzen.v1.ConversionRecord
+	(*ListHistoryResponse)(nil),      // 10: converzen.v1.ListHistoryResponse
+	(*timestamppb.Timestamp)(nil),    // 11: google.protobuf.Timestamp
+}
+var file_api_proto_converzen_v1_converzen_proto_depIdxs = []int32{
+	3,  // 0: converzen.v1.BatchEvent.progress:type_name -> converzen.v1.ConversionProgress
+	5,  // 1: converzen.v1.BatchEvent.result:type_name -> converzen.v1.BatchConversionResult
+	4,  // 2: converzen.v1.ConversionProgress.result:type_name -> converzen.v1.ConversionResult
+	4,  // 3: converzen.v1.BatchConversionResult.results:type_name -> converzen.v1.ConversionResult
+	11, // 4: converzen.v1.HistoryFilter.from:type_name -> google.protobuf.Timestamp
+	11, // 5: converzen.v1.HistoryFilter.to:type_name -> google.protobuf.Timestamp
+	11, // 6: converzen.v1.ConversionRecord.created_at:type_name -> google.protobuf.Timestamp
+	11, // 7: converzen.v1.ConversionRecord.completed_at:type_name -> google.protobuf.Timestamp
+	9,  // 8: converzen.v1.ListHistoryResponse.conversions:type_name -> converzen.v1.ConversionRecord
+	0,  // 9: converzen.v1.Conversion.SubmitBatch:input_type -> converzen.v1.BatchConversionRequest
+	2,  // 10: converzen.v1.Conversion.WatchProgress:input_type -> converzen.v1.WatchProgressRequest
+	6,  // 11: converzen.v1.Conversion.CancelConversion:input_type -> converzen.v1.CancelConversionRequest
+	8,  // 12: converzen.v1.Conversion.ListHistory:input_type -> converzen.v1.HistoryFilter
+	1,  // 13: converzen.v1.Conversion.SubmitBatch:output_type -> converzen.v1.BatchEvent
+	3,  // 14: converzen.v1.Conversion.WatchProgress:output_type -> converzen.v1.ConversionProgress
+	7,  // 15: converzen.v1.Conversion.CancelConversion:output_type -> converzen.v1.CancelConversionResponse
+	10, // 16: converzen.v1.Conversion.ListHistory:output_type -> converzen.v1.ListHistoryResponse
+	13, // [13:17] is the sub-list for method output_type
+	9,  // [9:13] is the sub-list for method input_type
+	9,  // [9:9] is the sub-list for extension type_name
+	9,  // [9:9] is the sub-list for extension extendee
+	0,  // [0:9] is the sub-list for field type_name
+}
+
+func init() { file_api_proto_converzen_v1_converzen_proto_init() }
+func file_api_proto_converzen_v1_converzen_proto_init() {
+	if File_api_proto_converzen_v1_converzen_proto != nil {
+		return
+	}
+	file_api_proto_converzen_v1_converzen_proto_msgTypes[1].OneofWrappers = []any{
+		(*BatchEvent_Progress)(nil),
+		(*BatchEvent_Result)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_proto_converzen_v1_converzen_proto_rawDesc), len(file_api_proto_converzen_v1_converzen_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   11,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_api_proto_converzen_v1_converzen_proto_goTypes,
+		DependencyIndexes: file_api_proto_converzen_v1_converzen_proto_depIdxs,
+		MessageInfos:      file_api_proto_converzen_v1_converzen_proto_msgTypes,
+	}.Build()
+	File_api_proto_converzen_v1_converzen_proto = out.File
+	file_api_proto_converzen_v1_converzen_proto_goTypes = nil
+	file_api_proto_converzen_v1_converzen_proto_depIdxs = nil
+}
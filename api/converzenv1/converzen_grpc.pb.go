@@ -0,0 +1,269 @@
+// Automation API for driving Converzen as a conversion engine from other
+// tools, served on localhost by internal/grpcapi when grpc_port is set.
+// Clients authenticate with the api_token as a bearer token in the
+// "authorization" metadata.
+//
+// The Go code in api/converzenv1 is generated; regenerate it after editing
+// this file with:
+//
+//   protoc --go_out=. --go_opt=module=converzen \
+//     --go-grpc_out=. --go-grpc_opt=module=converzen \
+//     api/proto/converzen/v1/converzen.proto
+//
+// Messages mirror the JSON models in internal/models; field names follow
+// their JSON names.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.2
+// - protoc             v5.29.3
+// source: api/proto/converzen/v1/converzen.proto
+
+package converzenv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Conversion_SubmitBatch_FullMethodName      = "/converzen.v1.Conversion/SubmitBatch"
+	Conversion_WatchProgress_FullMethodName    = "/converzen.v1.Conversion/WatchProgress"
+	Conversion_CancelConversion_FullMethodName = "/converzen.v1.Conversion/CancelConversion"
+	Conversion_ListHistory_FullMethodName      = "/converzen.v1.Conversion/ListHistory"
+)
+
+// ConversionClient is the client API for Conversion service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ConversionClient interface {
+	// SubmitBatch starts converting a batch and streams its progress. The
+	// last message carries the batch result.
+	SubmitBatch(ctx context.Context, in *BatchConversionRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[BatchEvent], error)
+	// WatchProgress streams the progress of every conversion, including ones
+	// started from the app, until the client disconnects
+	WatchProgress(ctx context.Context, in *WatchProgressRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ConversionProgress], error)
+	// CancelConversion cancels a running conversion by history ID
+	CancelConversion(ctx context.Context, in *CancelConversionRequest, opts ...grpc.CallOption) (*CancelConversionResponse, error)
+	// ListHistory returns conversion history records matching a filter
+	ListHistory(ctx context.Context, in *HistoryFilter, opts ...grpc.CallOption) (*ListHistoryResponse, error)
+}
+
+type conversionClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewConversionClient(cc grpc.ClientConnInterface) ConversionClient {
+	return &conversionClient{cc}
+}
+
+func (c *conversionClient) SubmitBatch(ctx context.Context, in *BatchConversionRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[BatchEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Conversion_ServiceDesc.Streams[0], Conversion_SubmitBatch_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[BatchConversionRequest, BatchEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Conversion_SubmitBatchClient = grpc.ServerStreamingClient[BatchEvent]
+
+func (c *conversionClient) WatchProgress(ctx context.Context, in *WatchProgressRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ConversionProgress], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Conversion_ServiceDesc.Streams[1], Conversion_WatchProgress_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchProgressRequest, ConversionProgress]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Conversion_WatchProgressClient = grpc.ServerStreamingClient[ConversionProgress]
+
+func (c *conversionClient) CancelConversion(ctx context.Context, in *CancelConversionRequest, opts ...grpc.CallOption) (*CancelConversionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CancelConversionResponse)
+	err := c.cc.Invoke(ctx, Conversion_CancelConversion_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *conversionClient) ListHistory(ctx context.Context, in *HistoryFilter, opts ...grpc.CallOption) (*ListHistoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListHistoryResponse)
+	err := c.cc.Invoke(ctx, Conversion_ListHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ConversionServer is the server API for Conversion service.
+// All implementations must embed UnimplementedConversionServer
+// for forward compatibility.
+type ConversionServer interface {
+	// SubmitBatch starts converting a batch and streams its progress. The
+	// last message carries the batch result.
+	SubmitBatch(*BatchConversionRequest, grpc.ServerStreamingServer[BatchEvent]) error
+	// WatchProgress streams the progress of every conversion, including ones
+	// started from the app, until the client disconnects
+	WatchProgress(*WatchProgressRequest, grpc.ServerStreamingServer[ConversionProgress]) error
+	// CancelConversion cancels a running conversion by history ID
+	CancelConversion(context.Context, *CancelConversionRequest) (*CancelConversionResponse, error)
+	// ListHistory returns conversion history records matching a filter
+	ListHistory(context.Context, *HistoryFilter) (*ListHistoryResponse, error)
+	mustEmbedUnimplementedConversionServer()
+}
+
+// UnimplementedConversionServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedConversionServer struct{}
+
+func (UnimplementedConversionServer) SubmitBatch(*BatchConversionRequest, grpc.ServerStreamingServer[BatchEvent]) error {
+	return status.Error(codes.Unimplemented, "method SubmitBatch not implemented")
+}
+func (UnimplementedConversionServer) WatchProgress(*WatchProgressRequest, grpc.ServerStreamingServer[ConversionProgress]) error {
+	return status.Error(codes.Unimplemented, "method WatchProgress not implemented")
+}
+func (UnimplementedConversionServer) CancelConversion(context.Context, *CancelConversionRequest) (*CancelConversionResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method CancelConversion not implemented")
+}
+func (UnimplementedConversionServer) ListHistory(context.Context, *HistoryFilter) (*ListHistoryResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListHistory not implemented")
+}
+func (UnimplementedConversionServer) mustEmbedUnimplementedConversionServer() {}
+func (UnimplementedConversionServer) testEmbeddedByValue()                    {}
+
+// UnsafeConversionServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ConversionServer will
+// result in compilation errors.
+type UnsafeConversionServer interface {
+	mustEmbedUnimplementedConversionServer()
+}
+
+func RegisterConversionServer(s grpc.ServiceRegistrar, srv ConversionServer) {
+	// If the following call panics, it indicates UnimplementedConversionServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Conversion_ServiceDesc, srv)
+}
+
+func _Conversion_SubmitBatch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(BatchConversionRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ConversionServer).SubmitBatch(m, &grpc.GenericServerStream[BatchConversionRequest, BatchEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Conversion_SubmitBatchServer = grpc.ServerStreamingServer[BatchEvent]
+
+func _Conversion_WatchProgress_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchProgressRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ConversionServer).WatchProgress(m, &grpc.GenericServerStream[WatchProgressRequest, ConversionProgress]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Conversion_WatchProgressServer = grpc.ServerStreamingServer[ConversionProgress]
+
+func _Conversion_CancelConversion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelConversionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConversionServer).CancelConversion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Conversion_CancelConversion_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConversionServer).CancelConversion(ctx, req.(*CancelConversionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Conversion_ListHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HistoryFilter)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConversionServer).ListHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Conversion_ListHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConversionServer).ListHistory(ctx, req.(*HistoryFilter))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Conversion_ServiceDesc is the grpc.ServiceDesc for Conversion service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Conversion_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "converzen.v1.Conversion",
+	HandlerType: (*ConversionServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CancelConversion",
+			Handler:    _Conversion_CancelConversion_Handler,
+		},
+		{
+			MethodName: "ListHistory",
+			Handler:    _Conversion_ListHistory_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubmitBatch",
+			Handler:       _Conversion_SubmitBatch_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "WatchProgress",
+			Handler:       _Conversion_WatchProgress_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "api/proto/converzen/v1/converzen.proto",
+}
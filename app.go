@@ -3,16 +3,28 @@ package main
 import (
 	"context"
 	"fmt"
+	"path/filepath"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/wailsapp/wails/v2/pkg/runtime"
 
+	"converzen/internal/api"
 	"converzen/internal/config"
 	"converzen/internal/database"
+	"converzen/internal/events"
+	"converzen/internal/grpcapi"
 	"converzen/internal/logger"
 	"converzen/internal/models"
-	"converzen/internal/repository"
 	"converzen/internal/services"
+	"converzen/pkg/calibre"
+	"converzen/pkg/ffmpeg"
+	"converzen/pkg/htmlpdf"
+	"converzen/pkg/httpclient"
+	"converzen/pkg/libreoffice"
+	"converzen/pkg/scratch"
 )
 
 // App struct holds the application state and dependencies
@@ -25,24 +37,68 @@ type App struct {
 	// Logger
 	log *logger.Logger
 
-	// Database
-	db *database.Database
+	// Database, and whether it could be used
+	db       *database.Database
+	dbStatus DatabaseStatusResponse
 
 	// Services
 	fileService       services.FileService
 	conversionService services.ConversionService
 	settingsService   services.SettingsService
+	recentService     services.RecentService
+	bookmarkService   services.BookmarkService
+	analysisService   services.AnalysisService
+	thumbnailService  services.ThumbnailService
+	benchmarkService  services.EncoderBenchmarkService
 	formatProvider    services.FormatProvider
+	updateService     services.UpdateService
+
+	// HTTP clients for network features, honoring the proxy settings
+	httpClients *httpclient.Factory
+
+	// Progress and status events for the frontend and API clients
+	events *events.Bus
+
+	// HTTP API, nil unless enabled in the configuration file
+	apiServer *api.Server
+
+	// gRPC automation API, nil unless enabled in the configuration file
+	grpcServer *grpcapi.Server
+
+	// Worker instance conversions are sent to, nil unless configured
+	remoteWorker *services.RemoteWorker
+
+	// Converters shared by all profiles, and the backend preference they
+	// were created for
+	converters       converterSet
+	converterBackend models.ConverterBackend
+
+	// Document and e-book conversion backends
+	office  *libreoffice.LibreOffice
+	calibre *calibre.Calibre
+
+	// Window state reported by the frontend
+	windowHidden  atomic.Bool
+	windowBlurred atomic.Bool
+
+	// Guards the throttle level and the reason last reported to the UI
+	throttleMu     sync.Mutex
+	throttleReason string
+
+	// System notifications, set up when the first one is sent
+	notificationsOnce  sync.Once
+	notificationsReady atomic.Bool
 }
 
 // NewApp creates a new App application struct
 func NewApp() *App {
-	return &App{}
+	return &App{events: events.NewBus()}
 }
 
 // startup is called when the app starts
 func (a *App) startup(ctx context.Context) {
 	a.ctx = ctx
+	a.events.Add(events.NewWailsSink(ctx))
 
 	// Initialize configuration
 	cfg, err := config.New()
@@ -65,36 +121,168 @@ func (a *App) startup(ctx context.Context) {
 	a.log = log
 
 	log.Info("app", "Starting %s v%s", cfg.AppName, cfg.Version)
+	log.Debug("app", "Config directory: %s", cfg.ConfigDir)
 	log.Debug("app", "Data directory: %s", cfg.DataDir)
+	log.Debug("app", "Cache directory: %s", cfg.CacheDir)
+	for _, migration := range cfg.Migrations {
+		log.Info("app", "Migrated install: %s", migration)
+	}
 	log.Debug("app", "Log file: %s", cfg.LogFile)
+	if cfg.ConfigFile != "" {
+		log.Info("app", "Loaded configuration from %s", cfg.ConfigFile)
+	}
+	if cfg.ConfigFileError != "" {
+		log.Warn("app", "Ignoring configuration file: %s", cfg.ConfigFileError)
+	}
+
+	// Apply concurrency limits from the configuration file
+	if len(cfg.WorkerLimits) > 0 {
+		limits := make(map[models.FileType]int, len(cfg.WorkerLimits))
+		for fileType, limit := range cfg.WorkerLimits {
+			limits[models.FileType(fileType)] = limit
+		}
+		services.SetWorkerLimits(limits)
+	}
+
+	// Initialize converters, which all profiles share
+	a.fileService = services.NewFileService(log)
+	a.converters = converterSet{
+		video:    a.initVideoConverter(log),
+		image:    services.NewImageConverter(log),
+		audio:    a.initAudioConverter(log),
+		subtitle: services.NewSubtitleConverter(log),
+		ebook:    a.initEbookConverter(log),
+		document: a.initDocumentConverter(log),
+	}
+	a.analysisService = services.NewAnalysisService(a.fileService, ffmpegInstance, log)
+
+	// Open the active profile's database and services
+	a.httpClients, _ = httpclient.NewFactory(httpclient.Options{})
+	a.initRemoteWorker()
+	if err := a.openProfile(cfg.Profile); err != nil {
+		log.Error("app", "Failed to open profile %s: %v", cfg.Profile, err)
+		return
+	}
+	go a.cleanTempDir()
+
+	a.thumbnailService = services.NewThumbnailService(filepath.Join(cfg.CacheDir, "thumbnails"), ffmpegInstance, a.fileService, log)
+	a.benchmarkService = services.NewEncoderBenchmarkService(filepath.Join(cfg.DataDir, "encoder_benchmark.json"), ffmpegInstance, log)
+	a.refreshFormatProvider()
+
+	a.updateService = services.NewUpdateService(cfg.UpdateFeedURL, cfg.Version, a.httpClients, log)
+
+	// Throttle conversions while hidden, on battery or overheating
+	go a.monitorBackground()
+
+	// Check for new releases unless disabled in settings
+	go a.monitorUpdates()
+
+	// Offer copied media for conversion when enabled in settings
+	go a.monitorClipboard()
+
+	a.startAPI()
+	a.startGRPC()
+
+	log.Info("app", "Application startup complete")
+}
+
+// refreshFormatProvider recreates the format provider for the current
+// converters
+func (a *App) refreshFormatProvider() {
+	a.formatProvider = services.NewFormatProvider(
+		a.converters.video,
+		a.converters.image,
+		a.converters.audio,
+		a.converters.subtitle,
+		a.converters.ebook,
+		a.converters.document,
+		a.getConverterBackend(),
+	)
+}
+
+// staleTempAge is how old a scratch file must be before startup removes it
+const staleTempAge = 24 * time.Hour
 
-	// Initialize database
-	db, err := database.New(cfg.DatabaseURL, log)
+// cleanTempDir removes scratch workspaces left behind by earlier runs
+func (a *App) cleanTempDir() {
+	removed, err := scratch.CleanStale(staleTempAge)
 	if err != nil {
-		log.Error("app", "Failed to initialize database: %v", err)
+		a.log.Warn("app", "Failed to clean temp directory: %v", err)
 		return
 	}
-	a.db = db
+	if removed > 0 {
+		a.log.Info("app", "Removed %d stale temp files from %s", removed, scratch.Dir())
+	}
+}
 
-	// Initialize repositories
-	conversionRepo := repository.NewConversionRepository(db.DB, log)
-	settingsRepo := repository.NewSettingsRepository(db.DB, log)
+// applyTempDir switches the scratch directory, falling back to the default
+// when the chosen one isn't writable
+func (a *App) applyTempDir(dir string) {
+	if dir != "" {
+		if err := scratch.Validate(dir); err != nil {
+			a.log.Warn("app", "Temp directory %s is not usable, using %s: %v", dir, a.config.TempDir, err)
+			dir = ""
+		}
+	}
+	if dir == "" {
+		dir = a.config.TempDir
+	}
+	scratch.SetDir(dir)
+	a.log.Debug("app", "Temp directory: %s", dir)
+}
 
-	// Initialize services
-	a.fileService = services.NewFileService(log)
-	videoConverter := a.initVideoConverter(log)
-	imageConverter := services.NewImageConverter(log)
-	a.conversionService = services.NewConversionService(
-		a.fileService,
-		videoConverter,
-		imageConverter,
-		conversionRepo,
-		log,
+// networkOptions returns the HTTP client options of the settings
+func networkOptions(settings models.UserSettings) httpclient.Options {
+	return httpclient.Options{
+		ProxyURL: settings.ProxyURL,
+		CAFile:   settings.CAFile,
+	}
+}
+
+// applyNetworkSettings applies the proxy and CA settings to the HTTP clients
+// of network features, keeping the system defaults if they're invalid
+func (a *App) applyNetworkSettings(settings models.UserSettings) {
+	if err := a.httpClients.SetOptions(networkOptions(settings)); err != nil {
+		a.log.Warn("app", "Ignoring network settings: %v", err)
+	}
+}
+
+// initDocumentConverter initializes the document converters: LibreOffice for
+// office documents, and wkhtmltopdf (or LibreOffice) for Markdown and HTML
+func (a *App) initDocumentConverter(log *logger.Logger) services.Converter {
+	a.office = libreoffice.New(a.config.SofficePath, log)
+	if a.office.IsAvailable() {
+		if version, err := a.office.GetVersion(); err == nil {
+			log.Info("app", "LibreOffice version: %s", version)
+		}
+	} else {
+		log.Warn("app", "LibreOffice not found - document conversion will not work")
+	}
+
+	renderer := htmlpdf.New(a.config.WkhtmlPath, log)
+	if version, err := renderer.GetVersion(); err == nil {
+		log.Info("app", "wkhtmltopdf version: %s", version)
+	}
+
+	return services.NewConverterGroup(
+		services.NewDocumentConverter(a.office, log),
+		services.NewMarkupConverter(renderer, a.office, log),
 	)
-	a.settingsService = services.NewSettingsService(settingsRepo, log)
-	a.formatProvider = services.NewFormatProvider(videoConverter, imageConverter, a.getConverterBackend())
+}
 
-	log.Info("app", "Application startup complete")
+// initEbookConverter initializes the e-book converter using Calibre's ebook-convert
+func (a *App) initEbookConverter(log *logger.Logger) services.Converter {
+	a.calibre = calibre.New(a.config.CalibrePath, log)
+	if a.calibre.IsAvailable() {
+		if version, err := a.calibre.GetVersion(); err == nil {
+			log.Info("app", "Calibre version: %s", version)
+		}
+	} else {
+		log.Warn("app", "Calibre not found - e-book conversion will not work")
+		return nil
+	}
+
+	return services.NewEbookConverter(a.calibre, log)
 }
 
 // shutdown is called when the app is closing
@@ -103,6 +291,25 @@ func (a *App) shutdown(ctx context.Context) {
 		a.log.Info("app", "Application shutting down")
 	}
 
+	if a.apiServer != nil {
+		a.events.Remove(a.apiServer)
+		a.apiServer.Stop()
+	}
+	if a.grpcServer != nil {
+		a.events.Remove(a.grpcServer)
+		a.grpcServer.Stop()
+	}
+
+	if a.notificationsReady.Load() {
+		runtime.CleanupNotifications(a.ctx)
+	}
+
+	if a.conversionService != nil {
+		if err := a.conversionService.DiscardPreviews(); err != nil {
+			a.log.Warn("app", "%v", err)
+		}
+	}
+
 	if a.db != nil {
 		a.db.Close()
 	}
@@ -149,10 +356,18 @@ func (a *App) SelectFiles() ([]models.FileInfo, error) {
 func (a *App) buildFileFilters() []runtime.FileFilter {
 	videoFormats := a.formatProvider.GetSupportedVideoInputFormats()
 	imageFormats := a.formatProvider.GetSupportedImageInputFormats()
+	audioFormats := a.formatProvider.GetSupportedAudioInputFormats()
+	subtitleFormats := a.formatProvider.GetSupportedSubtitleInputFormats()
+	ebookFormats := a.formatProvider.GetSupportedEbookInputFormats()
+	documentFormats := a.formatProvider.GetSupportedDocumentInputFormats()
 
 	// Build pattern strings (e.g., "*.mp4;*.mov;*.m4v")
 	videoPattern := buildPatternFromFormats(videoFormats)
 	imagePattern := buildPatternFromFormats(imageFormats)
+	audioPattern := buildPatternFromFormats(audioFormats)
+	subtitlePattern := buildPatternFromFormats(subtitleFormats)
+	ebookPattern := buildPatternFromFormats(ebookFormats)
+	documentPattern := buildPatternFromFormats(documentFormats)
 
 	var filters []runtime.FileFilter
 
@@ -170,17 +385,45 @@ func (a *App) buildFileFilters() []runtime.FileFilter {
 		})
 	}
 
+	if audioPattern != "" {
+		filters = append(filters, runtime.FileFilter{
+			DisplayName: "Audio Files",
+			Pattern:     audioPattern,
+		})
+	}
+
+	if subtitlePattern != "" {
+		filters = append(filters, runtime.FileFilter{
+			DisplayName: "Subtitle Files",
+			Pattern:     subtitlePattern,
+		})
+	}
+
+	if ebookPattern != "" {
+		filters = append(filters, runtime.FileFilter{
+			DisplayName: "E-book Files",
+			Pattern:     ebookPattern,
+		})
+	}
+
+	if documentPattern != "" {
+		filters = append(filters, runtime.FileFilter{
+			DisplayName: "Document Files",
+			Pattern:     documentPattern,
+		})
+	}
+
 	// Add "All Supported Files" option if we have formats
-	if videoPattern != "" || imagePattern != "" {
-		allPatterns := videoPattern
-		if allPatterns != "" && imagePattern != "" {
-			allPatterns += ";" + imagePattern
-		} else if imagePattern != "" {
-			allPatterns = imagePattern
+	var allPatterns []string
+	for _, pattern := range []string{videoPattern, imagePattern, audioPattern, subtitlePattern, ebookPattern, documentPattern} {
+		if pattern != "" {
+			allPatterns = append(allPatterns, pattern)
 		}
+	}
+	if len(allPatterns) > 0 {
 		filters = append(filters, runtime.FileFilter{
 			DisplayName: "All Supported Files",
-			Pattern:     allPatterns,
+			Pattern:     strings.Join(allPatterns, ";"),
 		})
 	}
 
@@ -215,13 +458,117 @@ func (a *App) SelectDirectory() (string, error) {
 
 	if dir != "" {
 		a.log.Info("app", "Selected output directory: %s", dir)
-		// Save as last used directory
+		// Save as last used directory, keeping access to it after a
+		// restart in sandboxed builds
 		a.settingsService.SetSetting(models.SettingLastOutputDir, dir)
+		a.bookmarkService.Remember(dir)
 	}
 
 	return dir, nil
 }
 
+// presetFileFilter matches .zenpreset files in file dialogs
+var presetFileFilter = runtime.FileFilter{
+	DisplayName: "Converzen Presets (*" + models.PresetFileExtension + ")",
+	Pattern:     "*" + models.PresetFileExtension,
+}
+
+// ExportPreset asks where to save a preset and writes it as a .zenpreset
+// file. It returns the saved path, or "" if the dialog was cancelled.
+func (a *App) ExportPreset(preset models.Preset) (string, error) {
+	name := preset.Name
+	if name == "" {
+		name = "preset"
+	}
+
+	path, err := runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+		Title:           "Export Preset",
+		DefaultFilename: name + models.PresetFileExtension,
+		Filters:         []runtime.FileFilter{presetFileFilter},
+	})
+	if err != nil {
+		a.log.Error("app", "Preset export dialog error: %v", err)
+		return "", err
+	}
+	if path == "" {
+		return "", nil
+	}
+
+	path, err = services.WritePresetFile(path, preset)
+	if err != nil {
+		a.log.Error("app", "Preset export error: %v", err)
+		return "", err
+	}
+
+	a.log.Info("app", "Exported preset %q to %s", preset.Name, path)
+	return path, nil
+}
+
+// ImportPreset asks for a .zenpreset file and reads it. It returns nil if
+// the dialog was cancelled.
+func (a *App) ImportPreset() (*models.Preset, error) {
+	path, err := runtime.OpenFileDialog(a.ctx, runtime.OpenDialogOptions{
+		Title:   "Import Preset",
+		Filters: []runtime.FileFilter{presetFileFilter},
+	})
+	if err != nil {
+		a.log.Error("app", "Preset import dialog error: %v", err)
+		return nil, err
+	}
+	if path == "" {
+		return nil, nil
+	}
+	return a.ImportPresetFile(path)
+}
+
+// ImportPresetFile reads a .zenpreset file, e.g. one dropped onto the window
+func (a *App) ImportPresetFile(path string) (*models.Preset, error) {
+	preset, err := services.ReadPresetFile(path)
+	if err != nil {
+		a.log.Error("app", "Preset import error: %v", err)
+		return nil, err
+	}
+
+	a.log.Info("app", "Imported preset %q from %s", preset.Name, path)
+	if err := a.settingsService.RecordPresetChange(preset.Name, "", "imported from "+path); err != nil {
+		a.log.Warn("app", "Failed to record preset import: %v", err)
+	}
+	return preset, nil
+}
+
+// ImportManifest asks for a CSV or JSON manifest listing files with their
+// output formats and options, and reads it into a batch request for the
+// frontend to review and start. It returns nil if the dialog was cancelled.
+func (a *App) ImportManifest() (*models.BatchConversionRequest, error) {
+	path, err := runtime.OpenFileDialog(a.ctx, runtime.OpenDialogOptions{
+		Title: "Import Batch Manifest",
+		Filters: []runtime.FileFilter{{
+			DisplayName: "Batch Manifests (*.csv, *.json)",
+			Pattern:     "*.csv;*.json",
+		}},
+	})
+	if err != nil {
+		a.log.Error("app", "Manifest import dialog error: %v", err)
+		return nil, err
+	}
+	if path == "" {
+		return nil, nil
+	}
+	return a.ImportManifestFile(path)
+}
+
+// ImportManifestFile reads a batch manifest, e.g. one dropped onto the window
+func (a *App) ImportManifestFile(path string) (*models.BatchConversionRequest, error) {
+	request, err := services.ReadManifest(path)
+	if err != nil {
+		a.log.Error("app", "Manifest import error: %v", err)
+		return nil, err
+	}
+
+	a.log.Info("app", "Imported %d files from manifest %s", len(request.Files), path)
+	return request, nil
+}
+
 // GetOutputFormats returns available output formats for a file type
 func (a *App) GetOutputFormats(fileType string) []string {
 	ft := models.FileType(fileType)
@@ -234,9 +581,29 @@ func (a *App) GetOutputFormats(fileType string) []string {
 func (a *App) ConvertFiles(request models.BatchConversionRequest) (*models.BatchConversionResult, error) {
 	a.log.Info("app", "Starting batch conversion: %d files to %s", len(request.Files), request.OutputFormat)
 
+	if err := a.recentService.RecordBatch(request); err != nil {
+		a.log.Warn("app", "Failed to record recent paths: %v", err)
+	}
+
+	notifier := a.newBatchNotifier(len(request.Files))
 	result, err := a.conversionService.ConvertBatch(request, func(progress models.ConversionProgress) {
+		// Stalled jobs get their own event so the frontend can tell them apart from slow ones
+		if progress.Status == string(models.StatusStalled) {
+			a.events.Publish("conversion:stalled", progress)
+			return
+		}
+
+		// Stream each finished file's result as it completes, unless the
+		// batch is large enough to be summarized instead
+		if progress.Result != nil {
+			if notifier.fileFinished(*progress.Result) {
+				a.events.Publish("conversion:result", progress.Result)
+			}
+			progress.Result = nil
+		}
+
 		// Emit progress event to frontend
-		runtime.EventsEmit(a.ctx, "conversion:progress", progress)
+		a.events.Publish("conversion:progress", progress)
 	})
 
 	if err != nil {
@@ -244,8 +611,9 @@ func (a *App) ConvertFiles(request models.BatchConversionRequest) (*models.Batch
 		return nil, err
 	}
 
-	// Emit completion event
-	runtime.EventsEmit(a.ctx, "conversion:complete", result)
+	// Emit completion event and the batch's digest
+	a.events.Publish("conversion:complete", result)
+	notifier.finish(result)
 
 	a.log.Info("app", "Batch conversion complete: %d success, %d failed",
 		result.SuccessCount, result.FailCount)
@@ -253,12 +621,316 @@ func (a *App) ConvertFiles(request models.BatchConversionRequest) (*models.Batch
 	return result, nil
 }
 
+// CancelPowerAction cancels the sleep or shutdown waiting to run after a
+// batch, reporting whether one was pending
+func (a *App) CancelPowerAction() bool {
+	return a.conversionService.CancelPowerAction()
+}
+
+// RerunConversion converts a history entry again with the options it was
+// converted with. Progress and the result are emitted like a batch's.
+func (a *App) RerunConversion(id uint) (*models.Conversion, error) {
+	a.log.Info("app", "Re-running conversion %d", id)
+
+	conversion, err := a.conversionService.RerunConversion(id, func(progress models.ConversionProgress) {
+		if progress.Status == string(models.StatusStalled) {
+			a.events.Publish("conversion:stalled", progress)
+			return
+		}
+		if progress.Result != nil {
+			a.events.Publish("conversion:result", progress.Result)
+			progress.Result = nil
+		}
+		a.events.Publish("conversion:progress", progress)
+	})
+	if err != nil {
+		a.log.Error("app", "Re-run error: %v", err)
+		return nil, err
+	}
+	return conversion, nil
+}
+
+// FindDuplicateConversions returns the files of a batch that were already
+// converted with the same settings, so the user can skip them or convert anyway
+func (a *App) FindDuplicateConversions(request models.BatchConversionRequest) ([]models.DuplicateConversion, error) {
+	return a.conversionService.FindDuplicates(request)
+}
+
+// AnalyzeFiles probes files without converting them and reports their codecs,
+// resolution, duration, size and any problems found. Progress is emitted as
+// analysis:progress events.
+func (a *App) AnalyzeFiles(files []string) *models.AnalysisReport {
+	return a.analysisService.AnalyzeFiles(files, func(done, total int) {
+		a.events.Publish("analysis:progress", map[string]int{"done": done, "total": total})
+	})
+}
+
+// RunEncoderBenchmark encodes a short sample with each H.264 and HEVC
+// encoder available on this machine and reports their speed, output size
+// and quality. The fastest encoder of each codec whose quality holds up
+// becomes the default for new conversions. Progress is emitted as
+// benchmark:progress events.
+func (a *App) RunEncoderBenchmark() (*models.EncoderBenchmarkReport, error) {
+	return a.benchmarkService.RunEncoderBenchmark(a.ctx, func(done, total int) {
+		a.events.Publish("benchmark:progress", map[string]int{"done": done, "total": total})
+	})
+}
+
+// GetEncoderBenchmark returns the results of the last encoder benchmark,
+// or nil if it was never run on this machine
+func (a *App) GetEncoderBenchmark() (*models.EncoderBenchmarkReport, error) {
+	return a.benchmarkService.GetEncoderBenchmark()
+}
+
+// RecommendSettings suggests an output format, codec and quality for a file
+// given what it's for: "share", "archive", "edit" or "web"
+func (a *App) RecommendSettings(path string, goal string) (*models.Recommendation, error) {
+	return a.analysisService.RecommendSettings(path, models.RecommendationGoal(goal))
+}
+
+// ExportAnalysisReport asks where to save an analysis report and writes it
+// as CSV. It returns the saved path, or "" if the dialog was cancelled.
+func (a *App) ExportAnalysisReport(report models.AnalysisReport) (string, error) {
+	path, err := runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+		Title:           "Export Analysis Report",
+		DefaultFilename: "analysis.csv",
+		Filters: []runtime.FileFilter{{
+			DisplayName: "CSV Files (*.csv)",
+			Pattern:     "*.csv",
+		}},
+	})
+	if err != nil {
+		a.log.Error("app", "Report export dialog error: %v", err)
+		return "", err
+	}
+	if path == "" {
+		return "", nil
+	}
+
+	path, err = services.WriteAnalysisCSV(path, report)
+	if err != nil {
+		a.log.Error("app", "Report export error: %v", err)
+		return "", err
+	}
+
+	a.log.Info("app", "Exported analysis of %d files to %s", len(report.Files), path)
+	return path, nil
+}
+
+// RenameFiles renames files in place with a naming template without
+// converting them. With Preview set it only returns the new names.
+func (a *App) RenameFiles(request models.RenameRequest) (*models.RenameResult, error) {
+	return a.conversionService.RenameFiles(request)
+}
+
+// UndoRename reverts a batch rename from history
+func (a *App) UndoRename(batchID string) (*models.RenameResult, error) {
+	return a.conversionService.UndoRename(batchID)
+}
+
+// SplitVideo cuts a video into fixed-length segments
+func (a *App) SplitVideo(request models.SplitRequest) (*models.BatchConversionResult, error) {
+	a.log.Info("app", "Splitting %s into %d-minute segments", request.InputPath, request.SegmentMinutes)
+
+	result, err := a.conversionService.SplitFile(request, func(progress models.ConversionProgress) {
+		a.events.Publish("conversion:progress", progress)
+	})
+	if err != nil {
+		a.log.Error("app", "Split error: %v", err)
+		return nil, err
+	}
+
+	a.events.Publish("conversion:complete", result)
+	return result, nil
+}
+
+// StreamVideo packages a video as HLS and/or DASH for self-hosted streaming
+func (a *App) StreamVideo(request models.StreamingRequest) (*models.ConversionResult, error) {
+	a.log.Info("app", "Packaging %s for streaming", request.InputPath)
+
+	result, err := a.conversionService.PackageForStreaming(request, func(progress models.ConversionProgress) {
+		a.events.Publish("conversion:progress", progress)
+	})
+	if err != nil {
+		a.log.Error("app", "Packaging error: %v", err)
+		return nil, err
+	}
+	return result, nil
+}
+
+// ConvertImageSequence encodes numbered image files as a video, e.g. a
+// rendered overlay to webm or ProRes 4444 keeping its transparency
+func (a *App) ConvertImageSequence(request models.ImageSequenceRequest) (*models.ConversionResult, error) {
+	a.log.Info("app", "Encoding image sequence from %s as %s", request.FirstFrame, request.OutputFormat)
+
+	result, err := a.conversionService.ConvertImageSequence(request, func(progress models.ConversionProgress) {
+		a.events.Publish("conversion:progress", progress)
+	})
+	if err != nil {
+		a.log.Error("app", "Image sequence error: %v", err)
+		return nil, err
+	}
+	return result, nil
+}
+
+// PreviewConversion converts a few seconds of a file with the chosen settings
+// and returns the path of the temporary preview file
+func (a *App) PreviewConversion(job models.ConversionJob, seconds int) (string, error) {
+	a.log.Info("app", "Previewing %s as %s (%ds)", job.InputPath, job.OutputFormat, seconds)
+
+	path, err := a.conversionService.PreviewConversion(job, seconds)
+	if err != nil {
+		a.log.Error("app", "Preview error: %v", err)
+		return "", err
+	}
+	return path, nil
+}
+
+// EstimateOutputSize predicts a file's output size with the chosen settings
+func (a *App) EstimateOutputSize(job models.ConversionJob) (*models.SizeEstimate, error) {
+	a.log.Debug("app", "Estimating output size for %s as %s", job.InputPath, job.OutputFormat)
+	return a.conversionService.EstimateOutputSize(job)
+}
+
+// GetQueueState returns the queued, running, paused, completed and failed conversions
+func (a *App) GetQueueState() (*models.QueueState, error) {
+	return a.conversionService.GetQueueState()
+}
+
+// GetRecentInputs returns the most recently converted files
+func (a *App) GetRecentInputs(limit int) ([]models.RecentPath, error) {
+	return a.recentService.GetRecentInputs(limit)
+}
+
+// GetRecentOutputDirs returns the most recently used output directories
+func (a *App) GetRecentOutputDirs(limit int) ([]models.RecentPath, error) {
+	return a.recentService.GetRecentOutputDirs(limit)
+}
+
+// GetThumbnail returns a small preview of an image or video as a data URL,
+// or "" for file types without one
+func (a *App) GetThumbnail(path string) (string, error) {
+	return a.thumbnailService.GetThumbnail(path)
+}
+
+// GrabFrame saves the frame of a video at a timestamp ("83.5" or "1:23.5")
+// as a png, jpg, bmp, tiff or webp image and returns the saved path. An
+// empty outputPath saves next to the video.
+func (a *App) GrabFrame(path, timestamp, format, outputPath string) (string, error) {
+	savedPath, err := a.thumbnailService.GrabFrame(path, timestamp, format, outputPath)
+	if err != nil {
+		a.log.Error("app", "Frame grab error: %v", err)
+		return "", err
+	}
+	return savedPath, nil
+}
+
+// CreateContactSheet saves a grid of evenly spaced frames of a video, with
+// optional timestamps, as a single image for reviewing or cataloging long
+// recordings. It returns the saved path.
+func (a *App) CreateContactSheet(path string, options models.ContactSheetOptions) (string, error) {
+	savedPath, err := a.thumbnailService.CreateContactSheet(path, options)
+	if err != nil {
+		a.log.Error("app", "Contact sheet error: %v", err)
+		return "", err
+	}
+	return savedPath, nil
+}
+
+// ClearThumbnailCache deletes every cached thumbnail
+func (a *App) ClearThumbnailCache() error {
+	return a.thumbnailService.ClearThumbnailCache()
+}
+
 // GetConversionHistory retrieves the conversion history
 func (a *App) GetConversionHistory(limit int) ([]models.Conversion, error) {
 	a.log.Debug("app", "Getting conversion history (limit: %d)", limit)
 	return a.conversionService.GetConversionHistory(limit)
 }
 
+// FilterConversionHistory retrieves the conversion history matching a filter
+// of statuses, file type, output format and date range
+func (a *App) FilterConversionHistory(filter models.HistoryFilter) ([]models.Conversion, error) {
+	a.log.Debug("app", "Getting filtered conversion history: %+v", filter)
+	return a.conversionService.FilterConversionHistory(filter)
+}
+
+// UpdateConversionNote annotates a history record, e.g. "sent to client"
+func (a *App) UpdateConversionNote(id uint, note string) error {
+	return a.conversionService.UpdateConversionNote(id, note)
+}
+
+// AnalyzeLoudness measures the integrated loudness (LUFS), true peak and
+// loudness range of a video or audio file
+func (a *App) AnalyzeLoudness(path string) (*models.Loudness, error) {
+	return a.conversionService.AnalyzeLoudness(path)
+}
+
+// MeasureConversionLoudness measures the loudness of a history record's input
+// and output, e.g. to check a normalized podcast episode, and stores it
+func (a *App) MeasureConversionLoudness(id uint) (*models.Conversion, error) {
+	return a.conversionService.MeasureConversionLoudness(id)
+}
+
+// ArchiveConversions hides history records without losing their statistics
+func (a *App) ArchiveConversions(ids []uint) error {
+	return a.conversionService.ArchiveConversions(ids)
+}
+
+// RestoreConversions brings archived history records back
+func (a *App) RestoreConversions(ids []uint) error {
+	return a.conversionService.RestoreConversions(ids)
+}
+
+// DeleteConversion removes a history record, keeping its statistics until purged
+func (a *App) DeleteConversion(id uint) error {
+	return a.conversionService.DeleteConversion(id)
+}
+
+// PurgeConversions permanently deletes archived and deleted history records
+// and returns how many were removed
+func (a *App) PurgeConversions() (int64, error) {
+	return a.conversionService.PurgeConversions()
+}
+
+// GetConversionLog returns the FFmpeg/AVFoundation output captured while a
+// conversion ran, for diagnosing failures
+func (a *App) GetConversionLog(id uint) (string, error) {
+	return a.conversionService.GetConversionLog(id)
+}
+
+// GetBatchResults retrieves a page of results for a finished batch conversion
+func (a *App) GetBatchResults(batchID string, offset int, limit int) ([]models.ConversionResult, error) {
+	a.log.Debug("app", "Getting results for batch %s (offset: %d, limit: %d)", batchID, offset, limit)
+	return a.conversionService.GetBatchResults(batchID, offset, limit)
+}
+
+// GetBatchHistory retrieves a page of conversion history grouped by batch,
+// e.g. for showing "Batch of 42 files" entries with expandable rows
+func (a *App) GetBatchHistory(offset int, limit int) ([]models.BatchSummary, error) {
+	a.log.Debug("app", "Getting batch history (offset: %d, limit: %d)", offset, limit)
+	return a.conversionService.GetBatchHistory(offset, limit)
+}
+
+// GetBatchConversions retrieves a page of a batch's conversion records
+func (a *App) GetBatchConversions(batchID string, offset int, limit int) ([]models.Conversion, error) {
+	a.log.Debug("app", "Getting conversions for batch %s (offset: %d, limit: %d)", batchID, offset, limit)
+	return a.conversionService.GetBatchConversions(batchID, offset, limit)
+}
+
+// GetSpaceSavings returns how much disk space conversions have saved per
+// period ("week" or "month"), with a running total
+func (a *App) GetSpaceSavings(period string) ([]models.SpaceSavings, error) {
+	a.log.Debug("app", "Getting space savings per %s", period)
+	return a.conversionService.GetSpaceSavings(models.SavingsPeriod(period))
+}
+
+// GetSettingChanges returns the most recent changes of settings and presets,
+// newest first, e.g. to explain why defaults changed after an import
+func (a *App) GetSettingChanges(limit int) ([]models.SettingChange, error) {
+	return a.settingsService.GetSettingChanges(limit)
+}
+
 // GetSettings retrieves user settings
 func (a *App) GetSettings() (*models.UserSettings, error) {
 	return a.settingsService.GetSettings()
@@ -266,7 +938,24 @@ func (a *App) GetSettings() (*models.UserSettings, error) {
 
 // SaveSettings saves user settings
 func (a *App) SaveSettings(settings models.UserSettings) error {
-	return a.settingsService.SaveSettings(settings)
+	if settings.TempDirectory != "" {
+		if err := scratch.Validate(settings.TempDirectory); err != nil {
+			return fmt.Errorf("temp directory is not writable: %w", err)
+		}
+	}
+	if err := httpclient.Validate(networkOptions(settings)); err != nil {
+		return err
+	}
+	if err := checkConverterBackend(settings.ConverterBackend); err != nil {
+		return err
+	}
+	if err := a.settingsService.SaveSettings(settings); err != nil {
+		return err
+	}
+	a.applyTempDir(settings.TempDirectory)
+	a.applyNetworkSettings(settings)
+	a.applyConverterBackend(settings.ConverterBackend)
+	return nil
 }
 
 // CheckFFmpeg checks if the video converter backend is available
@@ -274,19 +963,68 @@ func (a *App) CheckFFmpeg() bool {
 	return a.isFFmpegAvailable()
 }
 
+// CheckLibreOffice checks if LibreOffice is available for document conversion
+func (a *App) CheckLibreOffice() bool {
+	return a.office != nil && a.office.IsAvailable()
+}
+
+// CheckCalibre checks if Calibre is available for e-book conversion
+func (a *App) CheckCalibre() bool {
+	return a.calibre != nil && a.calibre.IsAvailable()
+}
+
 // GetFFmpegVersion returns the video converter backend version string
 func (a *App) GetFFmpegVersion() (string, error) {
 	return a.getConverterVersion()
 }
 
+// GetStreams lists the video, audio and subtitle streams of a media file
+func (a *App) GetStreams(path string) ([]ffmpeg.Stream, error) {
+	a.log.Debug("app", "Listing streams for: %s", path)
+	return a.getStreams(path)
+}
+
+// GetMediaInfo returns container, stream and codec details of a media file
+func (a *App) GetMediaInfo(path string) (*ffmpeg.MediaInfo, error) {
+	a.log.Debug("app", "Getting media info for: %s", path)
+	return a.getMediaInfo(path)
+}
+
+// ProbeMedia returns the codecs, pixel format, bitrate, frame rate and
+// audio channels of a media file, with details of every stream
+func (a *App) ProbeMedia(path string) (*ffmpeg.Probe, error) {
+	a.log.Debug("app", "Probing media: %s", path)
+	return a.probeMedia(path)
+}
+
+// CheckIntegrity decodes a media file in full and reports the decode errors
+// found, e.g. in a truncated download or a damaged recording. Progress is
+// emitted as "integrity:progress" events.
+func (a *App) CheckIntegrity(path string) (*ffmpeg.IntegrityReport, error) {
+	a.log.Debug("app", "Checking integrity of: %s", path)
+	return a.checkIntegrity(path, func(progress float64) {
+		a.events.Publish("integrity:progress", map[string]interface{}{
+			"path":     path,
+			"progress": progress,
+		})
+	})
+}
+
+// GetSocialPresets returns the built-in social platform export presets
+func (a *App) GetSocialPresets() []models.SocialPreset {
+	return models.SocialPresets
+}
+
 // AppInfoResponse contains application information for the frontend
 type AppInfoResponse struct {
-	Name             string `json:"name"`
-	Version          string `json:"version"`
-	DataDir          string `json:"dataDir"`
-	LogFile          string `json:"logFile"`
-	ConverterBackend string `json:"converterBackend"`
-	FFmpegVersion    string `json:"ffmpegVersion,omitempty"`
+	Name               string `json:"name"`
+	Version            string `json:"version"`
+	DataDir            string `json:"dataDir"`
+	LogFile            string `json:"logFile"`
+	ConverterBackend   string `json:"converterBackend"`
+	FFmpegVersion      string `json:"ffmpegVersion,omitempty"`
+	LibreOfficeVersion string `json:"libreOfficeVersion,omitempty"`
+	CalibreVersion     string `json:"calibreVersion,omitempty"`
 }
 
 // GetAppInfo returns application information
@@ -302,24 +1040,38 @@ func (a *App) GetAppInfo() AppInfoResponse {
 	if version, err := a.getConverterVersion(); err == nil {
 		info.FFmpegVersion = version
 	}
+	if version, err := a.office.GetVersion(); err == nil {
+		info.LibreOfficeVersion = version
+	}
+	if version, err := a.calibre.GetVersion(); err == nil {
+		info.CalibreVersion = version
+	}
 
 	return info
 }
 
 // SupportedFormatsResponse contains the supported formats for the frontend
 type SupportedFormatsResponse struct {
-	VideoFormats []string `json:"videoFormats"`
-	ImageFormats []string `json:"imageFormats"`
-	Backend      string   `json:"backend"`
+	VideoFormats    []string `json:"videoFormats"`
+	ImageFormats    []string `json:"imageFormats"`
+	AudioFormats    []string `json:"audioFormats"`
+	SubtitleFormats []string `json:"subtitleFormats"`
+	EbookFormats    []string `json:"ebookFormats"`
+	DocumentFormats []string `json:"documentFormats"`
+	Backend         string   `json:"backend"`
 }
 
 // GetSupportedFormats returns the supported output formats for the current backend
 // This allows the frontend to dynamically show only formats that the backend supports
 func (a *App) GetSupportedFormats() SupportedFormatsResponse {
 	return SupportedFormatsResponse{
-		VideoFormats: a.formatProvider.GetSupportedVideoOutputFormats(),
-		ImageFormats: a.formatProvider.GetSupportedImageOutputFormats(),
-		Backend:      a.formatProvider.GetBackendName(),
+		VideoFormats:    a.formatProvider.GetSupportedVideoOutputFormats(),
+		ImageFormats:    a.formatProvider.GetSupportedImageOutputFormats(),
+		AudioFormats:    a.formatProvider.GetSupportedAudioOutputFormats(),
+		SubtitleFormats: a.formatProvider.GetSupportedSubtitleOutputFormats(),
+		EbookFormats:    a.formatProvider.GetSupportedEbookOutputFormats(),
+		DocumentFormats: a.formatProvider.GetSupportedDocumentOutputFormats(),
+		Backend:         a.formatProvider.GetBackendName(),
 	}
 }
 
@@ -333,6 +1085,17 @@ func (a *App) GetSupportedImageFormats() []string {
 	return a.formatProvider.GetSupportedImageOutputFormats()
 }
 
+// GetSupportedAudioFormats returns the supported audio output formats
+func (a *App) GetSupportedAudioFormats() []string {
+	return a.formatProvider.GetSupportedAudioOutputFormats()
+}
+
+// GetSupportedVideoCodecs returns the professional video codecs (e.g. "prores")
+// the current backend can encode for an output format
+func (a *App) GetSupportedVideoCodecs(outputFormat string) []models.VideoCodec {
+	return a.formatProvider.GetSupportedVideoCodecs(outputFormat)
+}
+
 // CanConvert checks if conversion from input to output format is supported
 func (a *App) CanConvert(fileType string, outputFormat string) bool {
 	switch models.FileType(fileType) {
@@ -340,6 +1103,14 @@ func (a *App) CanConvert(fileType string, outputFormat string) bool {
 		return a.formatProvider.CanConvertVideo(outputFormat)
 	case models.FileTypeImage:
 		return a.formatProvider.CanConvertImage(outputFormat)
+	case models.FileTypeAudio:
+		return a.formatProvider.CanConvertAudio(outputFormat)
+	case models.FileTypeSubtitle:
+		return a.formatProvider.CanConvertSubtitle(outputFormat)
+	case models.FileTypeEbook:
+		return a.formatProvider.CanConvertEbook(outputFormat)
+	case models.FileTypeDocument:
+		return a.formatProvider.CanConvertDocument(outputFormat)
 	default:
 		return false
 	}
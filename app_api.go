@@ -0,0 +1,92 @@
+package main
+
+import (
+	"converzen/internal/api"
+	"converzen/internal/grpcapi"
+	"converzen/internal/models"
+	"converzen/internal/services"
+)
+
+// startAPI starts the HTTP API if the configuration file enables it
+func (a *App) startAPI() {
+	if a.config.APIPort == 0 {
+		if a.config.Worker {
+			a.log.Warn("app", "Worker mode needs the HTTP API; set api_port to enable it")
+		}
+		return
+	}
+
+	server, err := api.New(a.config.APIAddress, a.config.APIPort, a.config.APIToken, a.log)
+	if err != nil {
+		a.log.Warn("app", "HTTP API disabled: %v", err)
+		return
+	}
+	if a.config.APITLSCert != "" {
+		if err := server.UseTLS(a.config.APITLSCert, a.config.APITLSKey); err != nil {
+			a.log.Error("app", "HTTP API disabled: %v", err)
+			return
+		}
+	}
+	server.AllowOrigins(a.config.APIAllowedOrigins)
+	if a.config.Worker {
+		server.EnableWorker(a.converters.forType, api.WorkerOptions{
+			SharedStorage:  a.config.WorkerSharedStorage,
+			Roots:          a.config.WorkerRoots,
+			AllowOverwrite: a.config.WorkerAllowOverwrite,
+		})
+	}
+	if err := server.Start(); err != nil {
+		a.log.Error("app", "Failed to start HTTP API: %v", err)
+		return
+	}
+	a.apiServer = server
+	a.events.Add(server)
+}
+
+// startGRPC starts the gRPC automation API if the configuration file
+// enables it
+func (a *App) startGRPC() {
+	if a.config.GRPCPort == 0 {
+		return
+	}
+
+	server, err := grpcapi.New(a.config.GRPCPort, a.config.APIToken, func() services.ConversionService {
+		return a.conversionService
+	}, a.events, a.log)
+	if err != nil {
+		a.log.Warn("app", "gRPC API disabled: %v", err)
+		return
+	}
+	if err := server.Start(); err != nil {
+		a.log.Error("app", "Failed to start gRPC API: %v", err)
+		return
+	}
+	a.grpcServer = server
+	a.events.Add(server)
+}
+
+// initRemoteWorker sets up the worker instance conversions are sent to, if
+// the configuration file names one
+func (a *App) initRemoteWorker() {
+	if a.config.RemoteWorkerURL == "" {
+		return
+	}
+
+	fileTypes := make([]models.FileType, len(a.config.RemoteWorkerTypes))
+	for i, fileType := range a.config.RemoteWorkerTypes {
+		fileTypes[i] = models.FileType(fileType)
+	}
+
+	worker, err := services.NewRemoteWorker(services.RemoteWorkerOptions{
+		URL:           a.config.RemoteWorkerURL,
+		Token:         a.config.RemoteWorkerToken,
+		SharedStorage: a.config.RemoteWorkerSharedStorage,
+		FileTypes:     fileTypes,
+	}, a.httpClients, a.log)
+	if err != nil {
+		a.log.Warn("app", "Remote worker disabled: %v", err)
+		return
+	}
+	a.remoteWorker = worker
+	a.log.Info("app", "Sending %v conversions to worker %s", a.config.RemoteWorkerTypes, a.config.RemoteWorkerURL)
+}
@@ -3,10 +3,13 @@
 package main
 
 import (
+	"fmt"
 	"os"
 	"os/exec"
 
+	"converzen/internal/config"
 	"converzen/internal/logger"
+	"converzen/internal/models"
 	"converzen/internal/services"
 	"converzen/pkg/ffmpeg"
 )
@@ -39,14 +42,30 @@ func findSystemFFmpeg() string {
 	return ""
 }
 
+// cliFFmpegPath returns the FFmpeg used by command-line subcommands, which
+// App Store builds only have when one is installed on the system
+func cliFFmpegPath(cfg *config.Config) string {
+	return findSystemFFmpeg()
+}
+
 // initVideoConverter initializes the video converter for App Store builds
-// Prioritizes system FFmpeg if available, falls back to AVFoundation
+// with the automatic backend; openProfile then applies the profile's choice
 func (a *App) initVideoConverter(log *logger.Logger) services.Converter {
-	// First, try to use system FFmpeg if available
-	ffmpegPath := findSystemFFmpeg()
-	if ffmpegPath != "" {
-		ffmpegInstance = ffmpeg.New(ffmpegPath, log)
-		if ffmpegInstance.IsAvailable() {
+	a.converterBackend = models.ConverterBackendAuto
+	return videoConverterFor(models.ConverterBackendAuto, log)
+}
+
+// videoConverterFor creates the video converter for a backend preference.
+// Unless AVFoundation is chosen, a system FFmpeg is used when installed,
+// falling back to AVFoundation otherwise.
+func videoConverterFor(backend models.ConverterBackend, log *logger.Logger) services.Converter {
+	if backend != models.ConverterBackendAVFoundation {
+		if ffmpegInstance == nil {
+			if ffmpegPath := findSystemFFmpeg(); ffmpegPath != "" {
+				ffmpegInstance = ffmpeg.New(ffmpegPath, log)
+			}
+		}
+		if ffmpegInstance != nil && ffmpegInstance.IsAvailable() {
 			if version, err := ffmpegInstance.GetVersion(); err == nil {
 				log.Info("app", "Using system FFmpeg for video conversion: %s", version)
 			}
@@ -55,13 +74,60 @@ func (a *App) initVideoConverter(log *logger.Logger) services.Converter {
 		}
 	}
 
-	// Fall back to AVFoundation
-	log.Info("app", "Using AVFoundation for video conversion (App Store build, no system FFmpeg found)")
+	if backend == models.ConverterBackendAVFoundation {
+		log.Info("app", "Using AVFoundation for video conversion (selected in settings)")
+	} else {
+		log.Info("app", "Using AVFoundation for video conversion (App Store build, no system FFmpeg found)")
+	}
 	activeBackend = "avfoundation"
 	// Pass nil for ffmpeg parameter - AVFoundation doesn't need it
 	return services.NewVideoConverter(nil, log)
 }
 
+// checkConverterBackend reports whether a backend preference can be applied
+func checkConverterBackend(backend models.ConverterBackend) error {
+	switch backend {
+	case "", models.ConverterBackendAuto, models.ConverterBackendAVFoundation:
+		return nil
+	case models.ConverterBackendFFmpeg:
+		if findSystemFFmpeg() == "" {
+			return fmt.Errorf("no system FFmpeg is installed; install one, e.g. with Homebrew, to convert with FFmpeg")
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown converter backend %q", backend)
+}
+
+// applyConverterBackend switches the video and audio converters to a
+// backend preference without restarting. Running conversions finish with
+// the backend they started with.
+func (a *App) applyConverterBackend(backend models.ConverterBackend) {
+	if backend == "" {
+		backend = models.ConverterBackendAuto
+	}
+	if backend == a.converterBackend {
+		return
+	}
+
+	a.converterBackend = backend
+	a.converters.video = videoConverterFor(backend, a.log)
+	a.converters.audio = a.initAudioConverter(a.log)
+	if a.conversionService != nil {
+		a.conversionService.SetMediaConverters(a.converters.video, a.converters.audio)
+	}
+	a.refreshFormatProvider()
+}
+
+// initAudioConverter initializes the audio converter. Audio conversion
+// requires FFmpeg, so it is only available with the FFmpeg backend.
+func (a *App) initAudioConverter(log *logger.Logger) services.Converter {
+	if activeBackend != "ffmpeg" || ffmpegInstance == nil {
+		log.Warn("app", "Not using a system FFmpeg - audio conversion will not work")
+		return nil
+	}
+	return services.NewAudioConverter(ffmpegInstance, log)
+}
+
 // isFFmpegAvailable returns true - either FFmpeg or AVFoundation is available
 func (a *App) isFFmpegAvailable() bool {
 	return true // Either FFmpeg or AVFoundation is always available on macOS
@@ -80,3 +146,39 @@ func (a *App) getConverterVersion() (string, error) {
 	// AVFoundation doesn't have a version string like FFmpeg
 	return "AVFoundation (macOS native)", nil
 }
+
+// getMediaInfo returns detailed media file information. Like stream
+// listing, it requires a system FFmpeg.
+func (a *App) getMediaInfo(path string) (*ffmpeg.MediaInfo, error) {
+	if activeBackend != "ffmpeg" || ffmpegInstance == nil {
+		return nil, fmt.Errorf("media info requires FFmpeg")
+	}
+	return ffmpegInstance.GetMediaInfo(path)
+}
+
+// probeMedia probes a media file's streams and codecs. Like media info,
+// it requires a system FFmpeg.
+func (a *App) probeMedia(path string) (*ffmpeg.Probe, error) {
+	if activeBackend != "ffmpeg" || ffmpegInstance == nil {
+		return nil, fmt.Errorf("media probing requires FFmpeg")
+	}
+	return ffmpegInstance.ProbeFile(path)
+}
+
+// checkIntegrity decodes a media file in full. AVFoundation doesn't report
+// decode errors, so it requires a system FFmpeg.
+func (a *App) checkIntegrity(path string, progressCallback ffmpeg.ProgressCallback) (*ffmpeg.IntegrityReport, error) {
+	if activeBackend != "ffmpeg" || ffmpegInstance == nil {
+		return nil, fmt.Errorf("integrity checks require FFmpeg")
+	}
+	return ffmpegInstance.CheckIntegrity(a.ctx, path, progressCallback)
+}
+
+// getStreams lists the streams of a media file. Stream listing requires
+// FFmpeg, so it is only available when a system FFmpeg was found.
+func (a *App) getStreams(path string) ([]ffmpeg.Stream, error) {
+	if activeBackend != "ffmpeg" || ffmpegInstance == nil {
+		return nil, fmt.Errorf("stream listing requires FFmpeg")
+	}
+	return ffmpegInstance.GetStreams(path)
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+
+	"converzen/internal/models"
+	"converzen/internal/power"
+)
+
+// backgroundCheckInterval is how often the power source, thermal state and window state are checked
+const backgroundCheckInterval = 15 * time.Second
+
+// throttleRank orders the throttle levels from least to most restrictive
+var throttleRank = map[models.ThrottleLevel]int{
+	models.ThrottleNone:    0,
+	models.ThrottleReduced: 1,
+	models.ThrottlePaused:  2,
+}
+
+// BackgroundStateResponse describes why conversions are or aren't being throttled
+type BackgroundStateResponse struct {
+	Level        models.ThrottleLevel `json:"level"`
+	OnBattery    bool                 `json:"onBattery"`
+	WindowHidden bool                 `json:"windowHidden"`
+	Thermal      power.Thermal        `json:"thermal"`
+	Reason       string               `json:"reason,omitempty"` // Explains the level to the user
+}
+
+// monitorBackground periodically re-evaluates the conversion throttle until the app shuts down
+func (a *App) monitorBackground() {
+	ticker := time.NewTicker(backgroundCheckInterval)
+	defer ticker.Stop()
+
+	a.updateThrottle()
+	for {
+		select {
+		case <-a.ctx.Done():
+			return
+		case <-ticker.C:
+			a.updateThrottle()
+			a.updatePriority()
+		}
+	}
+}
+
+// updateThrottle applies the background and thermal mode settings to the
+// current power, thermal and window state. The UI is told whenever the level
+// or the reason for it changes.
+func (a *App) updateThrottle() {
+	a.throttleMu.Lock()
+	defer a.throttleMu.Unlock()
+
+	state := a.backgroundState()
+	if state.Level == a.conversionService.GetThrottle() && state.Reason == a.throttleReason {
+		return
+	}
+
+	a.log.Info("app", "Throttle %s (battery: %t, window hidden: %t, thermal: %s)", state.Level, state.OnBattery, state.WindowHidden, state.Thermal)
+	a.conversionService.SetThrottle(state.Level)
+	a.throttleReason = state.Reason
+	a.events.Publish("conversion:throttle", state)
+}
+
+// backgroundState determines the throttle level from the power source,
+// thermal state, window state and settings. The most restrictive applicable
+// mode wins.
+func (a *App) backgroundState() BackgroundStateResponse {
+	thermal, err := power.GetThermal()
+	if err != nil {
+		thermal = power.ThermalUnknown
+	}
+	state := BackgroundStateResponse{
+		Level:        models.ThrottleNone,
+		OnBattery:    power.OnBattery(),
+		WindowHidden: a.windowHidden.Load() || runtime.WindowIsMinimised(a.ctx),
+		Thermal:      thermal,
+	}
+
+	settings := models.DefaultUserSettings()
+	if saved, err := a.settingsService.GetSettings(); err == nil {
+		settings = *saved
+	}
+
+	apply := func(mode models.BackgroundMode, reason string) {
+		level := models.ThrottleNone
+		switch mode {
+		case models.BackgroundModeReduce:
+			level = models.ThrottleReduced
+		case models.BackgroundModePause:
+			level = models.ThrottlePaused
+		}
+		if throttleRank[level] > throttleRank[state.Level] {
+			state.Level = level
+			state.Reason = reason
+		}
+	}
+
+	if thermal == power.ThermalSerious || thermal == power.ThermalCritical {
+		apply(settings.ThermalMode, "The computer is running hot")
+	}
+	if state.OnBattery {
+		apply(settings.BackgroundMode, "The computer is running on battery")
+	}
+	if state.WindowHidden {
+		apply(settings.BackgroundMode, "The window is hidden")
+	}
+
+	return state
+}
+
+// SetWindowVisible is called by the frontend when the page becomes hidden or visible
+func (a *App) SetWindowVisible(visible bool) {
+	a.windowHidden.Store(!visible)
+	a.updateThrottle()
+	a.updatePriority()
+}
+
+// SetWindowFocused is called by the frontend when the window gains or loses focus
+func (a *App) SetWindowFocused(focused bool) {
+	a.windowBlurred.Store(!focused)
+	a.updatePriority()
+}
+
+// updatePriority lowers the OS priority of running FFmpeg processes while the
+// window is unfocused or hidden, and restores it when the user returns
+func (a *App) updatePriority() {
+	if ffmpegInstance == nil {
+		return
+	}
+
+	settings := models.DefaultUserSettings()
+	if saved, err := a.settingsService.GetSettings(); err == nil {
+		settings = *saved
+	}
+	ffmpegInstance.SetBackground(settings.LowWhenUnfocused && (a.windowBlurred.Load() || a.windowHidden.Load()))
+}
+
+// GetBackgroundState returns the current throttle level and the reason for it
+func (a *App) GetBackgroundState() BackgroundStateResponse {
+	state := a.backgroundState()
+	state.Level = a.conversionService.GetThrottle()
+	return state
+}
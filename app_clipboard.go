@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+
+	"converzen/internal/models"
+	"converzen/internal/services"
+)
+
+// clipboardPollInterval is how often the clipboard is read while watched
+const clipboardPollInterval = time.Second
+
+// monitorClipboard watches the clipboard while enabled in settings until the
+// app shuts down, emitting clipboard:media when media files or links are
+// copied so the frontend can offer to convert them
+func (a *App) monitorClipboard() {
+	ticker := time.NewTicker(clipboardPollInterval)
+	defer ticker.Stop()
+
+	watching := false
+	last := ""
+	for {
+		select {
+		case <-a.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if !a.clipboardWatchEnabled() {
+			watching = false
+			continue
+		}
+		text, err := runtime.ClipboardGetText(a.ctx)
+		if err != nil {
+			continue
+		}
+
+		// What was copied before watching started isn't offered
+		if !watching {
+			watching = true
+			last = text
+			continue
+		}
+		if text == last {
+			continue
+		}
+		last = text
+
+		if media := services.ParseClipboardMedia(text); media != nil {
+			a.log.Debug("app", "Clipboard has %d files and %d links", len(media.Paths), len(media.URLs))
+			a.events.Publish("clipboard:media", media)
+		}
+	}
+}
+
+// clipboardWatchEnabled reports whether the user enabled clipboard watching
+func (a *App) clipboardWatchEnabled() bool {
+	value, err := a.settingsService.GetSetting(models.SettingWatchClipboard)
+	return err == nil && value == "true"
+}
+
+// DownloadMedia downloads a media link offered from the clipboard into the
+// Downloads folder so it can be queued for conversion
+func (a *App) DownloadMedia(url string) (*models.FileInfo, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find the Downloads folder: %w", err)
+	}
+
+	dir := filepath.Join(home, "Downloads")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create the Downloads folder: %w", err)
+	}
+
+	a.log.Info("app", "Downloading %s", url)
+	path, err := services.DownloadMedia(a.ctx, a.httpClients, url, dir)
+	if err != nil {
+		a.log.Error("app", "Download error: %v", err)
+		return nil, err
+	}
+	return a.fileService.GetFileInfo(path)
+}
@@ -3,7 +3,11 @@
 package main
 
 import (
+	"fmt"
+
+	"converzen/internal/config"
 	"converzen/internal/logger"
+	"converzen/internal/models"
 	"converzen/internal/services"
 	"converzen/pkg/ffmpeg"
 )
@@ -13,12 +17,27 @@ var ffmpegInstance *ffmpeg.FFmpeg
 
 // initVideoConverter initializes the video converter for non-App Store builds (using FFmpeg)
 func (a *App) initVideoConverter(log *logger.Logger) services.Converter {
+	if status := ffmpeg.EmbeddedFFmpegStatus(); status != "" {
+		log.Info("app", "Embedded FFmpeg: %s", status)
+	}
+
 	// Initialize FFmpeg
 	ffmpegInstance = ffmpeg.New(a.config.FFmpegPath, log)
 	if ffmpegInstance.IsAvailable() {
 		if version, err := ffmpegInstance.GetVersion(); err == nil {
 			log.Info("app", "FFmpeg version: %s", version)
 		}
+
+		// Detect hardware encoders in the background; the first conversion
+		// wanting one waits for detection to finish
+		mode := a.config.HardwareAcceleration
+		if mode == "" {
+			mode = ffmpeg.HWAccelAuto
+		}
+		ffmpegInstance.SetHardwareAcceleration(mode)
+		if mode != ffmpeg.HWAccelNone {
+			go ffmpegInstance.DetectHardwareAcceleration()
+		}
 	} else {
 		log.Warn("app", "FFmpeg not found - video conversion will not work")
 	}
@@ -26,6 +45,33 @@ func (a *App) initVideoConverter(log *logger.Logger) services.Converter {
 	return services.NewVideoConverter(ffmpegInstance, log)
 }
 
+// initAudioConverter initializes the audio converter, sharing the FFmpeg
+// instance set up by initVideoConverter
+func (a *App) initAudioConverter(log *logger.Logger) services.Converter {
+	return services.NewAudioConverter(ffmpegInstance, log)
+}
+
+// cliFFmpegPath returns the FFmpeg used by command-line subcommands
+func cliFFmpegPath(cfg *config.Config) string {
+	return cfg.FFmpegPath
+}
+
+// checkConverterBackend reports whether a backend preference can be
+// applied. Builds outside the App Store always convert with FFmpeg.
+func checkConverterBackend(backend models.ConverterBackend) error {
+	switch backend {
+	case "", models.ConverterBackendAuto, models.ConverterBackendFFmpeg:
+		return nil
+	case models.ConverterBackendAVFoundation:
+		return fmt.Errorf("AVFoundation conversion is only available in the Mac App Store version")
+	}
+	return fmt.Errorf("unknown converter backend %q", backend)
+}
+
+// applyConverterBackend does nothing, as builds outside the App Store only
+// have FFmpeg
+func (a *App) applyConverterBackend(backend models.ConverterBackend) {}
+
 // isFFmpegAvailable returns whether FFmpeg is available (for non-App Store builds)
 func (a *App) isFFmpegAvailable() bool {
 	return ffmpegInstance != nil && ffmpegInstance.IsAvailable()
@@ -43,3 +89,35 @@ func (a *App) getConverterVersion() (string, error) {
 	}
 	return "", nil
 }
+
+// getMediaInfo returns detailed media file information using FFmpeg
+func (a *App) getMediaInfo(path string) (*ffmpeg.MediaInfo, error) {
+	if ffmpegInstance == nil {
+		return nil, fmt.Errorf("FFmpeg is not available")
+	}
+	return ffmpegInstance.GetMediaInfo(path)
+}
+
+// probeMedia probes a media file's streams and codecs using FFmpeg
+func (a *App) probeMedia(path string) (*ffmpeg.Probe, error) {
+	if ffmpegInstance == nil {
+		return nil, fmt.Errorf("FFmpeg is not available")
+	}
+	return ffmpegInstance.ProbeFile(path)
+}
+
+// checkIntegrity decodes a media file in full using FFmpeg
+func (a *App) checkIntegrity(path string, progressCallback ffmpeg.ProgressCallback) (*ffmpeg.IntegrityReport, error) {
+	if ffmpegInstance == nil {
+		return nil, fmt.Errorf("FFmpeg is not available")
+	}
+	return ffmpegInstance.CheckIntegrity(a.ctx, path, progressCallback)
+}
+
+// getStreams lists the streams of a media file using FFmpeg
+func (a *App) getStreams(path string) ([]ffmpeg.Stream, error) {
+	if ffmpegInstance == nil {
+		return nil, fmt.Errorf("FFmpeg is not available")
+	}
+	return ffmpegInstance.GetStreams(path)
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+
+	"converzen/internal/models"
+)
+
+// batchNotifier reports the progress of one batch. Small batches stream
+// each file's result; batches of at least the summary threshold send a
+// conversion:summary event every summary interval instead. Either way a
+// final digest is sent when the batch finishes. Summaries and the digest
+// are also shown as system notifications while the window isn't focused.
+type batchNotifier struct {
+	app      *App
+	id       string // Notification ID, so each summary replaces the last
+	coalesce bool
+	interval time.Duration
+	notify   bool
+
+	mu          sync.Mutex
+	summary     models.BatchProgressSummary
+	lastSummary time.Time
+}
+
+// newBatchNotifier creates a notifier for a batch of total files, using
+// the notification settings
+func (a *App) newBatchNotifier(total int) *batchNotifier {
+	settings := models.DefaultUserSettings()
+	if saved, err := a.settingsService.GetSettings(); err == nil {
+		settings = *saved
+	}
+
+	return &batchNotifier{
+		app:         a,
+		id:          fmt.Sprintf("batch-%d", time.Now().UnixNano()),
+		coalesce:    settings.SummaryThreshold > 0 && total >= settings.SummaryThreshold,
+		interval:    time.Duration(settings.SummaryIntervalSeconds) * time.Second,
+		notify:      settings.Notifications,
+		summary:     models.BatchProgressSummary{Total: total},
+		lastSummary: time.Now(),
+	}
+}
+
+// fileFinished counts a file's result. It reports whether the result
+// should be sent on its own, which it isn't while summaries replace it.
+func (n *batchNotifier) fileFinished(result models.ConversionResult) bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.summary.Done++
+	switch {
+	case result.Skipped:
+		n.summary.Skipped++
+	case result.Success:
+		n.summary.Succeeded++
+	default:
+		n.summary.Failed++
+	}
+
+	if !n.coalesce {
+		return true
+	}
+	if time.Since(n.lastSummary) >= n.interval && n.summary.Done < n.summary.Total {
+		n.lastSummary = time.Now()
+		n.send(n.summary)
+	}
+	return false
+}
+
+// finish sends the final digest of a finished batch
+func (n *batchNotifier) finish(result *models.BatchConversionResult) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.summary.BatchID = result.BatchID
+	n.summary.Total = result.TotalFiles
+	n.summary.Done = result.TotalFiles
+	n.summary.Succeeded = result.SuccessCount
+	n.summary.Failed = result.FailCount
+	n.summary.Skipped = result.SkippedCount
+	n.summary.Final = true
+	n.send(n.summary)
+}
+
+// send publishes a summary and shows it as a notification unless the user
+// is looking at the window
+func (n *batchNotifier) send(summary models.BatchProgressSummary) {
+	n.app.events.Publish("conversion:summary", summary)
+
+	if !n.notify || (!n.app.windowBlurred.Load() && !n.app.windowHidden.Load()) {
+		return
+	}
+	n.app.sendNotification(n.id, summaryTitle(summary), summaryBody(summary))
+}
+
+// summaryTitle returns the notification title for a batch summary
+func summaryTitle(summary models.BatchProgressSummary) string {
+	if summary.Final {
+		if summary.Failed > 0 {
+			return "Conversion finished with errors"
+		}
+		return "Conversion finished"
+	}
+	return fmt.Sprintf("Converting %d files", summary.Total)
+}
+
+// summaryBody describes a batch summary, e.g. "120/500 done, 2 failed"
+func summaryBody(summary models.BatchProgressSummary) string {
+	var parts []string
+	if summary.Final {
+		parts = append(parts, fmt.Sprintf("%d of %d converted", summary.Succeeded, summary.Total))
+	} else {
+		parts = append(parts, fmt.Sprintf("%d/%d done", summary.Done, summary.Total))
+	}
+	if summary.Failed > 0 {
+		parts = append(parts, fmt.Sprintf("%d failed", summary.Failed))
+	}
+	if summary.Skipped > 0 {
+		parts = append(parts, fmt.Sprintf("%d skipped", summary.Skipped))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// sendNotification shows a system notification, replacing an earlier one
+// with the same ID, and sets up notifications on first use. Failures are
+// logged; notifications are a convenience.
+func (a *App) sendNotification(id, title, body string) {
+	a.notificationsOnce.Do(func() {
+		if !runtime.IsNotificationAvailable(a.ctx) {
+			a.log.Info("app", "System notifications are not available")
+			return
+		}
+		if err := runtime.InitializeNotifications(a.ctx); err != nil {
+			a.log.Warn("app", "Failed to set up notifications: %v", err)
+			return
+		}
+		if allowed, err := runtime.RequestNotificationAuthorization(a.ctx); err != nil || !allowed {
+			a.log.Info("app", "Notifications are not allowed")
+			return
+		}
+		a.notificationsReady.Store(true)
+	})
+	if !a.notificationsReady.Load() {
+		return
+	}
+
+	err := runtime.SendNotification(a.ctx, runtime.NotificationOptions{
+		ID:    id,
+		Title: title,
+		Body:  body,
+	})
+	if err != nil {
+		a.log.Warn("app", "Failed to send notification: %v", err)
+	}
+}
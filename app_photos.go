@@ -0,0 +1,35 @@
+package main
+
+import (
+	"path/filepath"
+	"time"
+
+	"converzen/internal/models"
+	"converzen/internal/photos"
+)
+
+// PhotosImportSupported reports whether files can be imported from the
+// Photos library on this system
+func (a *App) PhotosImportSupported() bool {
+	return photos.Supported()
+}
+
+// ImportFromPhotos shows the system photo picker and returns the exported
+// originals of the chosen photos and videos, ready to be converted. Each
+// import gets its own folder in the data directory. It returns no files if
+// the picker was cancelled.
+func (a *App) ImportFromPhotos() ([]models.FileInfo, error) {
+	dir := filepath.Join(a.config.DataDir, "Photos Imports", time.Now().Format("2006-01-02 15.04.05"))
+
+	paths, err := photos.Pick(dir)
+	if err != nil {
+		a.log.Error("app", "Photos import error: %v", err)
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, nil
+	}
+
+	a.log.Info("app", "Imported %d files from Photos to %s", len(paths), dir)
+	return a.fileService.ValidateFiles(paths)
+}
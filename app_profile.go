@@ -0,0 +1,296 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"converzen/internal/config"
+	"converzen/internal/database"
+	"converzen/internal/models"
+	"converzen/internal/repository"
+	"converzen/internal/services"
+)
+
+// converterSet holds the converters, which don't depend on the profile
+type converterSet struct {
+	video    services.Converter
+	image    services.Converter
+	audio    services.Converter
+	subtitle services.Converter
+	ebook    services.Converter
+	document services.Converter
+}
+
+// forType returns the converter for a file type, nil if there is none
+func (c converterSet) forType(fileType models.FileType) services.Converter {
+	switch fileType {
+	case models.FileTypeVideo:
+		return c.video
+	case models.FileTypeImage:
+		return c.image
+	case models.FileTypeAudio:
+		return c.audio
+	case models.FileTypeSubtitle:
+		return c.subtitle
+	case models.FileTypeEbook:
+		return c.ebook
+	case models.FileTypeDocument:
+		return c.document
+	}
+	return nil
+}
+
+// DatabaseStatusResponse describes whether the profile's database could
+// be used. Without it conversions still work, but nothing is kept.
+type DatabaseStatusResponse struct {
+	HistoryAvailable bool   `json:"historyAvailable"`
+	Recovered        bool   `json:"recovered"`            // A corrupt database was replaced by an empty one
+	BackupPath       string `json:"backupPath,omitempty"` // Where the corrupt database was moved
+	Encrypted        bool   `json:"encrypted"`            // Paths and notes are encrypted with a key in the OS keychain
+	Shared           bool   `json:"shared"`               // History is kept in a network database shared with other workstations
+	Error            string `json:"error,omitempty"`      // Why the database is unavailable or was replaced
+}
+
+// openDatabase opens a profile's database, which for the default profile
+// may be a shared network database. A corrupt database is moved aside and
+// replaced by an empty one; if the database still can't be opened, e.g.
+// because the network database is unreachable or the key of an encrypted
+// database is missing from the keychain, an in-memory database keeps the
+// app working without history.
+func (a *App) openDatabase(name string) (*database.Database, DatabaseStatusResponse, error) {
+	path := a.config.ProfileDatabaseURL(name)
+	status := DatabaseStatusResponse{HistoryAvailable: true}
+
+	var db *database.Database
+	var err error
+	if name == config.DefaultProfile && a.config.SharedDatabase() {
+		status.Shared = true
+		db, err = database.NewShared(a.config.DatabaseDriver, a.config.DatabaseDSN, a.log)
+	} else {
+		db, err = database.New(path, a.log)
+	}
+	if errors.Is(err, database.ErrCorrupt) {
+		a.log.Error("app", "Database of profile %s is corrupt, rebuilding it: %v", name, err)
+		status.Recovered = true
+		status.Error = err.Error()
+
+		db, status.BackupPath, err = database.Rebuild(path, a.log)
+	}
+	if err == nil {
+		status.Encrypted = db.Encrypted()
+		return db, status, nil
+	}
+
+	a.log.Error("app", "History is unavailable, the database of profile %s can't be opened: %v", name, err)
+	db, memErr := database.NewInMemory(a.log)
+	if memErr != nil {
+		return nil, status, err
+	}
+	status.HistoryAvailable = false
+	status.Error = err.Error()
+	return db, status, nil
+}
+
+// GetDatabaseStatus reports whether history is available and whether the
+// database was rebuilt after being found corrupt
+func (a *App) GetDatabaseStatus() DatabaseStatusResponse {
+	return a.dbStatus
+}
+
+// SetDatabaseEncryption encrypts or decrypts the profile's database. The
+// key is created in the OS keychain when a database is first encrypted;
+// without it an encrypted database can't be read.
+func (a *App) SetDatabaseEncryption(enabled bool) error {
+	if !a.dbStatus.HistoryAvailable {
+		return fmt.Errorf("history is unavailable: %s", a.dbStatus.Error)
+	}
+	if a.dbStatus.Shared {
+		// Other workstations couldn't read it without this machine's key
+		return fmt.Errorf("a shared database can't be encrypted")
+	}
+
+	active, err := a.conversionService.ActiveConversionCount()
+	if err != nil {
+		return err
+	}
+	if active > 0 {
+		return fmt.Errorf("finish or cancel %d running conversions before changing database encryption", active)
+	}
+
+	if err := a.db.SetEncryption(enabled); err != nil {
+		a.log.Error("app", "Failed to change database encryption: %v", err)
+		return err
+	}
+
+	// Reopen the profile so every repository reads through the new cipher
+	if err := a.openProfile(a.config.Profile); err != nil {
+		a.log.Error("app", "Failed to reopen profile %s: %v", a.config.Profile, err)
+		return err
+	}
+	a.log.Info("app", "Database encryption enabled: %v", enabled)
+	a.events.Publish("database:status", a.dbStatus)
+	return nil
+}
+
+// openProfile opens a profile's database and creates the services that use
+// it, replacing those of the previous profile
+func (a *App) openProfile(name string) error {
+	db, dbStatus, err := a.openDatabase(name)
+	if err != nil {
+		return err
+	}
+
+	// Initialize repositories
+	conversionRepo := repository.NewConversionRepository(db.DB, a.log)
+	settingsRepo := repository.NewSettingsRepository(db.DB, a.log)
+	recentRepo := repository.NewRecentRepository(db.DB, a.log)
+	bookmarkRepo := repository.NewBookmarkRepository(db.DB, a.log)
+
+	// Initialize services
+	settingsService := services.NewSettingsService(settingsRepo, a.log)
+	recentService := services.NewRecentService(recentRepo, a.log)
+	bookmarkService := services.NewBookmarkService(bookmarkRepo, a.log)
+	conversionService := services.NewConversionService(
+		a.fileService,
+		a.converters.video,
+		a.converters.image,
+		a.converters.audio,
+		a.converters.subtitle,
+		a.converters.ebook,
+		a.converters.document,
+		conversionRepo,
+		settingsService,
+		a.analysisService,
+		a.config.ProfileLogDir(name),
+		a.log,
+	)
+	conversionService.SetRemoteWorker(a.remoteWorker)
+
+	previous := a.db
+	a.db = db
+	a.dbStatus = dbStatus
+	a.settingsService = settingsService
+	a.recentService = recentService
+	a.bookmarkService = bookmarkService
+	a.conversionService = conversionService
+	a.config.Profile = name
+	if previous != nil {
+		previous.Close()
+	}
+
+	// Regain access to the profile's folders in sandboxed builds before
+	// its settings refer to them
+	bookmarkService.RestoreAll()
+
+	// The temp directory and network settings are per-profile settings
+	settings, err := settingsService.GetSettings()
+	if err != nil {
+		defaults := models.DefaultUserSettings()
+		settings = &defaults
+	}
+	a.applyTempDir(settings.TempDirectory)
+	a.applyNetworkSettings(*settings)
+
+	// So is the converter backend, which falls back to the automatic choice
+	// when the chosen one is no longer available
+	backend := settings.ConverterBackend
+	if err := checkConverterBackend(backend); err != nil {
+		a.log.Warn("app", "Using the automatic converter backend: %v", err)
+		backend = models.ConverterBackendAuto
+	}
+	a.applyConverterBackend(backend)
+
+	if dbStatus.Recovered || !dbStatus.HistoryAvailable {
+		a.events.Publish("database:status", dbStatus)
+	}
+
+	a.log.Info("app", "Using profile %s", name)
+	return nil
+}
+
+// GetProfiles returns all profiles, marking the one in use
+func (a *App) GetProfiles() ([]models.Profile, error) {
+	names, err := a.config.ListProfiles()
+	if err != nil {
+		return nil, err
+	}
+
+	profiles := make([]models.Profile, 0, len(names))
+	for _, name := range names {
+		profiles = append(profiles, models.Profile{Name: name, Active: name == a.config.Profile})
+	}
+	return profiles, nil
+}
+
+// CreateProfile creates an empty profile with default settings
+func (a *App) CreateProfile(name string) error {
+	if err := config.ValidateProfileName(name); err != nil {
+		return err
+	}
+	if a.config.ProfileExists(name) {
+		return fmt.Errorf("profile %s already exists", name)
+	}
+
+	db, err := database.New(a.config.ProfileDatabaseURL(name), a.log)
+	if err != nil {
+		return err
+	}
+	a.log.Info("app", "Created profile %s", name)
+	return db.Close()
+}
+
+// SwitchProfile closes the current profile and opens another one, which is
+// also opened at the next startup. Conversions must finish first.
+func (a *App) SwitchProfile(name string) error {
+	if name == a.config.Profile {
+		return nil
+	}
+	if err := config.ValidateProfileName(name); err != nil {
+		return err
+	}
+	if !a.config.ProfileExists(name) {
+		return fmt.Errorf("profile %s doesn't exist", name)
+	}
+
+	active, err := a.conversionService.ActiveConversionCount()
+	if err != nil {
+		return err
+	}
+	if active > 0 {
+		return fmt.Errorf("finish or cancel %d running conversions before switching profiles", active)
+	}
+
+	if err := a.openProfile(name); err != nil {
+		a.log.Error("app", "Failed to open profile %s: %v", name, err)
+		return err
+	}
+	if err := a.config.SaveActiveProfile(name); err != nil {
+		a.log.Warn("app", "Failed to remember profile %s: %v", name, err)
+	}
+
+	// The new conversion service starts unthrottled
+	a.updateThrottle()
+	a.events.Publish("profile:changed", name)
+	return nil
+}
+
+// DeleteProfile deletes a profile with its settings and history. The profile
+// in use and the default profile can't be deleted.
+func (a *App) DeleteProfile(name string) error {
+	if err := config.ValidateProfileName(name); err != nil {
+		return err
+	}
+	if name == a.config.Profile {
+		return fmt.Errorf("switch to another profile before deleting %s", name)
+	}
+	if !a.config.ProfileExists(name) {
+		return fmt.Errorf("profile %s doesn't exist", name)
+	}
+
+	if err := a.config.DeleteProfile(name); err != nil {
+		a.log.Error("app", "Failed to delete profile %s: %v", name, err)
+		return fmt.Errorf("failed to delete profile: %w", err)
+	}
+	a.log.Info("app", "Deleted profile %s", name)
+	return nil
+}
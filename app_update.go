@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"converzen/internal/models"
+)
+
+const (
+	// updateCheckDelay postpones the first update check so it doesn't slow down startup
+	updateCheckDelay = time.Minute
+
+	// updateCheckInterval is how often the release feed is checked
+	updateCheckInterval = 24 * time.Hour
+)
+
+// monitorUpdates periodically checks for a new release until the app shuts
+// down, emitting update:available the first time a version is found
+func (a *App) monitorUpdates() {
+	timer := time.NewTimer(updateCheckDelay)
+	defer timer.Stop()
+
+	notified := ""
+	for {
+		select {
+		case <-a.ctx.Done():
+			return
+		case <-timer.C:
+			timer.Reset(updateCheckInterval)
+		}
+
+		if !a.updateChecksEnabled() {
+			continue
+		}
+		info, err := a.updateService.CheckForUpdates(a.ctx)
+		if err != nil || !info.Available || info.LatestVersion == notified {
+			continue
+		}
+		notified = info.LatestVersion
+		a.events.Publish("update:available", info)
+	}
+}
+
+// updateChecksEnabled reports whether the periodic update check is enabled.
+// The configuration file can disable it for every user.
+func (a *App) updateChecksEnabled() bool {
+	if !a.config.UpdateChecks {
+		return false
+	}
+	settings, err := a.settingsService.GetSettings()
+	return err != nil || settings.CheckForUpdates
+}
+
+// CheckForUpdates checks the release feed now, even when the user disabled
+// periodic checks, unless the configuration file disables update checks
+func (a *App) CheckForUpdates() (*models.UpdateInfo, error) {
+	if !a.config.UpdateChecks {
+		return nil, fmt.Errorf("update checks are disabled by the configuration file")
+	}
+	return a.updateService.CheckForUpdates(a.ctx)
+}
+
+// GetUpdateInfo returns the result of the last update check, or nil if none
+// has completed
+func (a *App) GetUpdateInfo() *models.UpdateInfo {
+	return a.updateService.GetUpdateInfo()
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+
+	"converzen/internal/config"
+	"converzen/internal/logger"
+	"converzen/pkg/ffmpeg"
+)
+
+// cliUsage describes the convert command
+const cliUsage = `Usage: converzen convert [--from FORMAT] [--to FORMAT] [-y] INPUT [OUTPUT]
+
+Converts INPUT to OUTPUT with FFmpeg. Either may be "-" for stdin or
+stdout, or a named pipe; OUTPUT defaults to stdout. --to is required unless
+OUTPUT is a file with an extension.
+
+  cat input.mov | converzen convert --from mov --to mp4 - > out.mp4
+`
+
+// runCLI runs a command-line subcommand in place of the app, reporting
+// false when args don't name one
+func runCLI(args []string) (int, bool) {
+	if len(args) == 0 || args[0] != "convert" {
+		return 0, false
+	}
+	if err := runConvert(args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 2, true
+		}
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1, true
+	}
+	return 0, true
+}
+
+// runConvert converts a single input between files and pipes, bypassing
+// the app's file validation, history and settings
+func runConvert(args []string) error {
+	flags := flag.NewFlagSet("convert", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), cliUsage)
+	}
+	from := flags.String("from", "", "input container format, e.g. mov (detected when omitted)")
+	to := flags.String("to", "", "output container format, e.g. mp4")
+	overwrite := flags.Bool("y", false, "overwrite an existing output file")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() < 1 || flags.NArg() > 2 {
+		flags.Usage()
+		return flag.ErrHelp
+	}
+	input := flags.Arg(0)
+	output := ffmpeg.StdioPath
+	if flags.NArg() == 2 {
+		output = flags.Arg(1)
+	}
+
+	outputFormat := *to
+	if outputFormat == "" && output != ffmpeg.StdioPath {
+		outputFormat = strings.TrimPrefix(filepath.Ext(output), ".")
+	}
+	if outputFormat == "" {
+		return fmt.Errorf("--to is required when writing to stdout")
+	}
+	inputFormat := *from
+	if inputFormat == "" && input != ffmpeg.StdioPath {
+		inputFormat = strings.TrimPrefix(filepath.Ext(input), ".")
+	}
+
+	cfg, err := config.New()
+	if err != nil {
+		return fmt.Errorf("failed to initialize config: %w", err)
+	}
+	logLevel := logger.INFO
+	if cfg.Debug {
+		logLevel = logger.DEBUG
+	}
+
+	// stdout may carry the output, so log only to the file
+	log, err := logger.NewWithConsole(cfg.LogFile, logLevel, nil)
+	if err != nil {
+		return fmt.Errorf("failed to initialize logger: %w", err)
+	}
+	defer log.Close()
+
+	ffmpegPath := cliFFmpegPath(cfg)
+	if ffmpegPath == "" {
+		return fmt.Errorf("FFmpeg is not available")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	// FFmpeg's warnings and errors go to stderr, where a script can see why
+	// it failed
+	return ffmpeg.New(ffmpegPath, log).ConvertStream(ctx, ffmpeg.StreamOptions{
+		InputPath:    input,
+		OutputPath:   output,
+		Input:        os.Stdin,
+		Output:       os.Stdout,
+		Overwrite:    *overwrite,
+		InputFormat:  inputFormat,
+		OutputFormat: outputFormat,
+		Log:          os.Stderr,
+	})
+}
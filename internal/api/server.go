@@ -0,0 +1,180 @@
+// Package api serves the optional HTTP API, which lets scripts and
+// dashboards follow conversions from outside the app and other instances
+// dispatch conversions to this one
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"converzen/internal/logger"
+)
+
+// shutdownTimeout bounds how long Stop waits for open requests
+const shutdownTimeout = 5 * time.Second
+
+// Server is the HTTP API. Every request must carry the
+// configured token, as a bearer token or, for WebSocket clients that can't
+// set headers, a "token" query parameter. Requests from web pages are
+// only accepted from the server's own origin or an allowed one.
+type Server struct {
+	token    string
+	origins  map[string]bool
+	hub      *hub
+	upgrader websocket.Upgrader
+	mux      *http.ServeMux
+	server   *http.Server
+	log      *logger.ComponentLogger
+
+	// Worker mode: finished outputs waiting to be downloaded, by ID
+	converterFor  ConverterFunc
+	workerOptions WorkerOptions
+	outputsMu     sync.Mutex
+	outputs       map[string]workerOutput
+}
+
+// New creates a server for port on address, e.g. 127.0.0.1 for local use
+// only. It fails without a token, since any process or web page that can
+// reach the port could otherwise connect.
+func New(address string, port int, token string, log *logger.Logger) (*Server, error) {
+	if token == "" {
+		return nil, fmt.Errorf("the HTTP API requires an api_token")
+	}
+
+	s := &Server{
+		token:   token,
+		origins: make(map[string]bool),
+		hub:     newHub(),
+		mux:     http.NewServeMux(),
+		log:     log.WithComponent("api"),
+	}
+	s.upgrader = websocket.Upgrader{CheckOrigin: s.originAllowed}
+	s.mux.HandleFunc("/ws", s.authorized(s.handleWebSocket))
+
+	s.server = &http.Server{
+		Addr:              net.JoinHostPort(address, fmt.Sprint(port)),
+		Handler:           s.mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	return s, nil
+}
+
+// UseTLS serves HTTPS with a certificate and key in PEM files. Without it
+// the token and converted files cross the network in the clear, which is
+// only safe on localhost or a trusted network.
+func (s *Server) UseTLS(certFile, keyFile string) error {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load TLS certificate: %w", err)
+	}
+	s.server.TLSConfig = &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}
+	return nil
+}
+
+// AllowOrigins lets web pages from origins, e.g. "https://dash.example.com",
+// call the API besides pages it serves itself
+func (s *Server) AllowOrigins(origins []string) {
+	for _, origin := range origins {
+		s.origins[strings.ToLower(strings.TrimSuffix(origin, "/"))] = true
+	}
+}
+
+// Start listens and serves in the background
+func (s *Server) Start() error {
+	listener, err := net.Listen("tcp", s.server.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", s.server.Addr, err)
+	}
+	if s.server.TLSConfig != nil {
+		listener = tls.NewListener(listener, s.server.TLSConfig)
+	} else if host, _, _ := net.SplitHostPort(s.server.Addr); !isLoopback(host) {
+		s.log.Warn("HTTP API on %s without TLS sends the token in the clear; set api_tls_cert and api_tls_key", host)
+	}
+
+	s.log.Info("HTTP API listening on %s", listener.Addr())
+	go func() {
+		if err := s.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.log.Error("HTTP API stopped: %v", err)
+		}
+	}()
+	return nil
+}
+
+// Stop closes the listener and every WebSocket connection
+func (s *Server) Stop() {
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	s.hub.closeAll()
+	if err := s.server.Shutdown(ctx); err != nil {
+		s.log.Warn("Failed to stop HTTP API cleanly: %v", err)
+	}
+}
+
+// Publish sends an event to every connected WebSocket client, making the
+// server an events.Sink
+func (s *Server) Publish(event string, data interface{}) {
+	s.hub.broadcast(event, data)
+}
+
+// isLoopback reports whether host only accepts local connections
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// originAllowed reports whether a request may come from its Origin.
+// Scripts and other instances send none; browsers send the page's origin,
+// which must be the server's own or one allowed with AllowOrigins.
+func (s *Server) originAllowed(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	parsed, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(parsed.Host, r.Host) || s.origins[strings.ToLower(origin)]
+}
+
+// authorized rejects requests from other origins and without the server's
+// token. The "token" query parameter is only read for WebSocket upgrades,
+// since browsers can't set headers on them, so it doesn't end up in the
+// URLs of other requests.
+func (s *Server) authorized(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !s.originAllowed(r) {
+			s.log.Warn("Rejected request from origin %s", r.Header.Get("Origin"))
+			http.Error(w, "origin not allowed", http.StatusForbidden)
+			return
+		}
+
+		var token string
+		if websocket.IsWebSocketUpgrade(r) {
+			token = r.URL.Query().Get("token")
+		}
+		if header := r.Header.Get("Authorization"); strings.HasPrefix(header, "Bearer ") {
+			token = strings.TrimPrefix(header, "Bearer ")
+		}
+		if subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next(w, r)
+	}
+}
@@ -0,0 +1,152 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"converzen/internal/events"
+)
+
+const (
+	// clientBuffer is the number of events queued for a client; clients
+	// that fall further behind are disconnected rather than slowing
+	// conversions down
+	clientBuffer = 256
+
+	// Keepalive: pings are sent every pingInterval and a client that
+	// hasn't answered within pongTimeout is disconnected
+	pingInterval = 30 * time.Second
+	pongTimeout  = 60 * time.Second
+	writeTimeout = 10 * time.Second
+)
+
+// hub tracks the connected WebSocket clients
+type hub struct {
+	mu      sync.Mutex
+	clients map[*client]bool
+}
+
+// client is a connected WebSocket client with its queue of encoded events
+type client struct {
+	conn *websocket.Conn
+	send chan []byte
+	once sync.Once
+}
+
+func newHub() *hub {
+	return &hub{clients: make(map[*client]bool)}
+}
+
+// broadcast queues an event for every client, dropping clients whose
+// queue is full
+func (h *hub) broadcast(event string, data interface{}) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if len(h.clients) == 0 {
+		return
+	}
+
+	message, err := json.Marshal(events.Message{Event: event, Data: data})
+	if err != nil {
+		return
+	}
+	for c := range h.clients {
+		select {
+		case c.send <- message:
+		default:
+			delete(h.clients, c)
+			c.close()
+		}
+	}
+}
+
+func (h *hub) add(c *client) {
+	h.mu.Lock()
+	h.clients[c] = true
+	h.mu.Unlock()
+}
+
+func (h *hub) remove(c *client) {
+	h.mu.Lock()
+	if h.clients[c] {
+		delete(h.clients, c)
+		c.close()
+	}
+	h.mu.Unlock()
+}
+
+func (h *hub) closeAll() {
+	h.mu.Lock()
+	for c := range h.clients {
+		delete(h.clients, c)
+		c.close()
+	}
+	h.mu.Unlock()
+}
+
+// close ends the client's write loop, which closes the connection
+func (c *client) close() {
+	c.once.Do(func() { close(c.send) })
+}
+
+// handleWebSocket streams the app's events to a client until it
+// disconnects. Messages from the client are ignored.
+func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		s.log.Warn("WebSocket upgrade failed: %v", err)
+		return
+	}
+
+	c := &client{conn: conn, send: make(chan []byte, clientBuffer)}
+	s.hub.add(c)
+	s.log.Info("WebSocket client connected: %s", r.RemoteAddr)
+
+	go c.writeLoop()
+
+	conn.SetReadDeadline(time.Now().Add(pongTimeout))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(pongTimeout))
+	})
+	for {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			break
+		}
+	}
+
+	s.hub.remove(c)
+	s.log.Info("WebSocket client disconnected: %s", r.RemoteAddr)
+}
+
+// writeLoop sends queued events and keepalive pings until the client is
+// closed
+func (c *client) writeLoop() {
+	ticker := time.NewTicker(pingInterval)
+	defer func() {
+		ticker.Stop()
+		c.conn.Close()
+	}()
+
+	for {
+		select {
+		case message, ok := <-c.send:
+			c.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+			if !ok {
+				c.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, ""))
+				return
+			}
+			if err := c.conn.WriteMessage(websocket.TextMessage, message); err != nil {
+				return
+			}
+		case <-ticker.C:
+			c.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,334 @@
+package api
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"converzen/internal/models"
+	"converzen/internal/services"
+	"converzen/pkg/scratch"
+)
+
+// ConverterFunc returns the converter for a file type, nil if there is none
+type ConverterFunc func(fileType models.FileType) services.Converter
+
+// WorkerOptions configures worker mode
+type WorkerOptions struct {
+	// SharedStorage accepts jobs that name their input and output by path
+	// instead of uploading the input, for machines sharing storage. Only
+	// paths under Roots are accepted.
+	SharedStorage bool
+	Roots         []string
+
+	// AllowOverwrite lets shared-storage jobs replace existing outputs
+	AllowOverwrite bool
+}
+
+// workerOutput is a converted upload waiting to be downloaded
+type workerOutput struct {
+	path      string
+	workspace string
+}
+
+// EnableWorker lets other instances dispatch conversions to this one,
+// converted with the converters converterFor returns.
+//
+// POST /worker/jobs takes a multipart form with the job as JSON in a "job"
+// field and the input file in an "input" field, which may only be left out
+// when options allow shared storage. The response streams
+// models.WorkerMessages as the job converts.
+// GET /worker/outputs/{id} then downloads an uploaded job's output once;
+// outputs that are never downloaded are removed with other stale temp files.
+func (s *Server) EnableWorker(converterFor ConverterFunc, options WorkerOptions) {
+	s.converterFor = converterFor
+	s.outputs = make(map[string]workerOutput)
+
+	// Roots are compared after resolving symlinks, like the job's paths
+	s.workerOptions = options
+	s.workerOptions.Roots = nil
+	for _, root := range options.Roots {
+		resolved, err := filepath.EvalSymlinks(filepath.Clean(root))
+		if err != nil {
+			s.log.Warn("Ignoring shared storage root %s: %v", root, err)
+			continue
+		}
+		s.workerOptions.Roots = append(s.workerOptions.Roots, resolved)
+	}
+	if options.SharedStorage && len(s.workerOptions.Roots) == 0 {
+		s.log.Warn("Shared storage has no usable roots; only uploaded jobs are accepted")
+	}
+
+	s.mux.HandleFunc("POST /worker/jobs", s.authorized(s.handleWorkerJob))
+	s.mux.HandleFunc("GET /worker/outputs/{id}", s.authorized(s.handleWorkerOutput))
+	s.log.Info("Worker mode enabled")
+}
+
+// handleWorkerJob converts a dispatched job, streaming its progress
+func (s *Server) handleWorkerJob(w http.ResponseWriter, r *http.Request) {
+	workspace, err := scratch.MkdirTemp("worker-*")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	keepWorkspace := false
+	defer func() {
+		if !keepWorkspace {
+			os.RemoveAll(workspace)
+		}
+	}()
+
+	job, uploaded, err := readWorkerJob(r, workspace)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := s.checkJobPaths(&job, uploaded); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	fileType := models.GetFileType(strings.ToLower(filepath.Ext(job.InputPath)))
+	converter := s.converterFor(fileType)
+	if converter == nil {
+		http.Error(w, fmt.Sprintf("no converter available for %s files", fileType), http.StatusUnprocessableEntity)
+		return
+	}
+	if _, err := os.Stat(job.InputPath); err != nil {
+		http.Error(w, fmt.Sprintf("input not found: %v", err), http.StatusUnprocessableEntity)
+		return
+	}
+
+	s.log.Info("Converting %s for %s", filepath.Base(job.InputPath), r.RemoteAddr)
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+	stream := newMessageStream(w)
+
+	var lastProgress float64 = -1
+	result, err := converter.Convert(r.Context(), job, func(progress float64) {
+		// Whole percents are plenty for a progress bar
+		if progress-lastProgress < 1 && progress < 100 {
+			return
+		}
+		lastProgress = progress
+		stream.send(models.WorkerMessage{Progress: &progress})
+	})
+
+	message := models.WorkerMessage{Result: result}
+	if err != nil {
+		message.Error = err.Error()
+	}
+	if err == nil && result != nil && result.Success && uploaded {
+		id, idErr := randomID()
+		if idErr != nil {
+			message.Error = idErr.Error()
+		} else {
+			s.outputsMu.Lock()
+			s.outputs[id] = workerOutput{path: result.OutputPath, workspace: workspace}
+			s.outputsMu.Unlock()
+			message.Output = id
+			keepWorkspace = true
+		}
+	}
+	stream.send(message)
+}
+
+// handleWorkerOutput sends a converted upload and deletes it
+func (s *Server) handleWorkerOutput(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	s.outputsMu.Lock()
+	output, ok := s.outputs[id]
+	delete(s.outputs, id)
+	s.outputsMu.Unlock()
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	defer os.RemoveAll(output.workspace)
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	http.ServeFile(w, r, output.path)
+}
+
+// readWorkerJob reads a dispatched job. An uploaded input is saved in
+// workspace and the job rewritten to convert it to workspace's output
+// directory; otherwise the job's paths are left for checkJobPaths.
+func readWorkerJob(r *http.Request, workspace string) (models.ConversionJob, bool, error) {
+	var job models.ConversionJob
+
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return job, false, fmt.Errorf("failed to read job: %w", err)
+	}
+
+	haveJob, uploaded := false, false
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return job, false, fmt.Errorf("failed to read job: %w", err)
+		}
+
+		switch part.FormName() {
+		case "job":
+			if err := json.NewDecoder(part).Decode(&job); err != nil {
+				return job, false, fmt.Errorf("failed to parse job: %w", err)
+			}
+			haveJob = true
+		case "input":
+			if !haveJob {
+				return job, false, fmt.Errorf("the job must come before the input")
+			}
+			if err := saveUpload(part, workspace, &job); err != nil {
+				return job, false, err
+			}
+			uploaded = true
+		}
+		part.Close()
+	}
+
+	if !haveJob {
+		return job, false, fmt.Errorf("missing job")
+	}
+	if job.InputPath == "" || job.OutputPath == "" {
+		return job, false, fmt.Errorf("the job needs an input and output path")
+	}
+	if len(job.Pipeline) > 0 || job.PreScript != "" {
+		return job, false, fmt.Errorf("pipelines and pre-conversion scripts run on the dispatching instance")
+	}
+	return job, uploaded, nil
+}
+
+// checkJobPaths confines a job to the paths this worker may touch. An
+// uploaded job only reads its upload; a shared-storage job must be allowed
+// and keep its input, output and overlay under a shared storage root, and
+// only replaces existing outputs if the worker allows it.
+func (s *Server) checkJobPaths(job *models.ConversionJob, uploaded bool) error {
+	if uploaded {
+		if job.Overlay != nil {
+			return fmt.Errorf("overlays need shared storage")
+		}
+		return nil
+	}
+	if !s.workerOptions.SharedStorage {
+		return fmt.Errorf("this worker only accepts uploaded inputs")
+	}
+
+	inputPath, err := s.sharedPath(job.InputPath, true)
+	if err != nil {
+		return err
+	}
+	outputPath, err := s.sharedPath(job.OutputPath, false)
+	if err != nil {
+		return err
+	}
+	if job.Overlay != nil {
+		overlay := *job.Overlay
+		if overlay.Path, err = s.sharedPath(overlay.Path, true); err != nil {
+			return err
+		}
+		job.Overlay = &overlay
+	}
+
+	job.InputPath = inputPath
+	job.OutputPath = outputPath
+	job.OverwriteOutput = job.OverwriteOutput && s.workerOptions.AllowOverwrite
+	return nil
+}
+
+// sharedPath resolves a path of a shared-storage job and checks it is under
+// a shared storage root. An output that doesn't exist yet is resolved
+// through its directory.
+func (s *Server) sharedPath(path string, mustExist bool) (string, error) {
+	path = filepath.Clean(path)
+	if !filepath.IsAbs(path) {
+		return "", fmt.Errorf("shared storage paths must be absolute: %s", path)
+	}
+
+	resolved, err := filepath.EvalSymlinks(path)
+	if os.IsNotExist(err) && !mustExist {
+		var dir string
+		if dir, err = filepath.EvalSymlinks(filepath.Dir(path)); err == nil {
+			resolved = filepath.Join(dir, filepath.Base(path))
+		}
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", path, err)
+	}
+
+	for _, root := range s.workerOptions.Roots {
+		if rel, err := filepath.Rel(root, resolved); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return resolved, nil
+		}
+	}
+	return "", fmt.Errorf("%s is outside the worker's shared storage", path)
+}
+
+// saveUpload saves an uploaded input in workspace and points job at it
+func saveUpload(part *multipart.Part, workspace string, job *models.ConversionJob) error {
+	inputDir := filepath.Join(workspace, "input")
+	outputDir := filepath.Join(workspace, "output")
+	for _, dir := range []string{inputDir, outputDir} {
+		if err := os.Mkdir(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create workspace: %w", err)
+		}
+	}
+
+	// Keep the original names, which converters may use in their output
+	inputPath := filepath.Join(inputDir, filepath.Base(filepath.FromSlash(job.InputPath)))
+	file, err := os.Create(inputPath)
+	if err != nil {
+		return fmt.Errorf("failed to save input: %w", err)
+	}
+	_, err = io.Copy(file, part)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save input: %w", err)
+	}
+
+	job.InputPath = inputPath
+	job.OutputPath = filepath.Join(outputDir, filepath.Base(filepath.FromSlash(job.OutputPath)))
+	job.OverwriteOutput = true
+	return nil
+}
+
+// messageStream writes worker messages as JSON lines, flushing each
+type messageStream struct {
+	mu      sync.Mutex
+	w       http.ResponseWriter
+	encoder *json.Encoder
+}
+
+func newMessageStream(w http.ResponseWriter) *messageStream {
+	return &messageStream{w: w, encoder: json.NewEncoder(w)}
+}
+
+func (m *messageStream) send(message models.WorkerMessage) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.encoder.Encode(message) == nil {
+		http.NewResponseController(m.w).Flush()
+	}
+}
+
+// randomID returns an unguessable ID for a finished output
+func randomID() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate output ID: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,11 @@
+// Package bookmark keeps access to user-selected folders across restarts in
+// sandboxed macOS builds. The sandbox only grants access to folders picked
+// in a dialog for the current session; a security-scoped bookmark created
+// then can be resolved at the next launch to regain that access.
+package bookmark
+
+import "errors"
+
+// ErrUnsupported is returned by Create and Resolve in builds that aren't
+// sandboxed and so don't need bookmarks
+var ErrUnsupported = errors.New("security-scoped bookmarks are only used in sandboxed macOS builds")
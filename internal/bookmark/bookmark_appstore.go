@@ -0,0 +1,100 @@
+//go:build darwin && appstore
+
+package bookmark
+
+/*
+#cgo CFLAGS: -x objective-c
+#cgo LDFLAGS: -framework Foundation
+
+#import <Foundation/Foundation.h>
+#include <stdlib.h>
+#include <string.h>
+
+// createBookmark returns security-scoped bookmark data for a path, which the
+// caller frees, or NULL on failure
+static void* createBookmark(const char* path, int* length) {
+    @autoreleasepool {
+        NSURL* url = [NSURL fileURLWithPath:[NSString stringWithUTF8String:path]];
+        NSError* error = nil;
+        NSData* data = [url bookmarkDataWithOptions:NSURLBookmarkCreationWithSecurityScope
+                     includingResourceValuesForKeys:nil
+                                      relativeToURL:nil
+                                              error:&error];
+        if (!data) {
+            return NULL;
+        }
+
+        void* bytes = malloc(data.length);
+        memcpy(bytes, data.bytes, data.length);
+        *length = (int)data.length;
+        return bytes;
+    }
+}
+
+// resolveBookmark resolves bookmark data and starts accessing the folder it
+// points to. It returns the folder's path, which the caller frees, or NULL
+// on failure, and sets stale when the bookmark should be recreated.
+static char* resolveBookmark(const void* bytes, int length, int* stale) {
+    @autoreleasepool {
+        NSData* data = [NSData dataWithBytes:bytes length:length];
+        BOOL isStale = NO;
+        NSError* error = nil;
+        NSURL* url = [NSURL URLByResolvingBookmarkData:data
+                                               options:NSURLBookmarkResolutionWithSecurityScope
+                                         relativeToURL:nil
+                                   bookmarkDataIsStale:&isStale
+                                                 error:&error];
+        if (!url || ![url startAccessingSecurityScopedResource]) {
+            return NULL;
+        }
+
+        *stale = isStale ? 1 : 0;
+        return strdup([[url path] UTF8String]);
+    }
+}
+*/
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// Supported reports whether bookmarks are needed in this build
+func Supported() bool {
+	return true
+}
+
+// Create creates a security-scoped bookmark for a folder the user selected
+// in this session
+func Create(path string) ([]byte, error) {
+	cPath := C.CString(path)
+	defer C.free(unsafe.Pointer(cPath))
+
+	var length C.int
+	bytes := C.createBookmark(cPath, &length)
+	if bytes == nil {
+		return nil, fmt.Errorf("failed to create bookmark for %s", path)
+	}
+	defer C.free(bytes)
+
+	return C.GoBytes(bytes, length), nil
+}
+
+// Resolve resolves a bookmark and regains access to its folder for the rest
+// of the session. It returns the folder's current path and whether the
+// bookmark is stale and should be recreated with Create.
+func Resolve(data []byte) (string, bool, error) {
+	if len(data) == 0 {
+		return "", false, fmt.Errorf("empty bookmark")
+	}
+
+	var stale C.int
+	cPath := C.resolveBookmark(unsafe.Pointer(&data[0]), C.int(len(data)), &stale)
+	if cPath == nil {
+		return "", false, fmt.Errorf("failed to resolve bookmark")
+	}
+	defer C.free(unsafe.Pointer(cPath))
+
+	return C.GoString(cPath), stale != 0, nil
+}
@@ -0,0 +1,21 @@
+//go:build !(darwin && appstore)
+
+package bookmark
+
+// Supported reports whether bookmarks are needed in this build
+func Supported() bool {
+	return false
+}
+
+// Create creates a security-scoped bookmark for a folder the user selected
+// in this session
+func Create(path string) ([]byte, error) {
+	return nil, ErrUnsupported
+}
+
+// Resolve resolves a bookmark and regains access to its folder for the rest
+// of the session. It returns the folder's current path and whether the
+// bookmark is stale and should be recreated with Create.
+func Resolve(data []byte) (string, bool, error) {
+	return "", false, ErrUnsupported
+}
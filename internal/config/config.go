@@ -1,55 +1,216 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"runtime"
+
+	"converzen/pkg/calibre"
+	"converzen/pkg/htmlpdf"
+	"converzen/pkg/libreoffice"
 )
 
 // Config holds the application configuration
 type Config struct {
 	AppName     string
 	Version     string
+	ConfigDir   string
 	DataDir     string
 	LogDir      string
 	LogFile     string
+	CacheDir    string
+	TempDir     string
 	DatabaseDir string
 	DatabaseURL string
 	FFmpegPath  string
+	SofficePath string
+	WkhtmlPath  string
+	CalibrePath string
 	Debug       bool
+
+	// ConfigFile is the configuration file that was loaded, if any, and
+	// ConfigFileError why it couldn't be
+	ConfigFile      string
+	ConfigFileError string
+
+	// WorkerLimits caps concurrent conversions per file type
+	WorkerLimits map[string]int
+
+	// HardwareAcceleration selects the hardware encoder ("" = automatic)
+	HardwareAcceleration string
+
+	// Telemetry allows sending anonymous usage statistics
+	Telemetry bool
+
+	// UpdateChecks allows periodic checks of UpdateFeedURL for new releases
+	UpdateChecks  bool
+	UpdateFeedURL string
+
+	// APIPort enables the HTTP API on this port (0 = disabled), listening
+	// on APIAddress; clients authenticate with APIToken. It serves HTTPS
+	// with APITLSCert and APITLSKey. Browser pages may open the WebSocket
+	// only from the API's own origin or APIAllowedOrigins.
+	APIPort           int
+	APIToken          string
+	APIAddress        string
+	APITLSCert        string
+	APITLSKey         string
+	APIAllowedOrigins []string
+
+	// GRPCPort enables the gRPC automation API on this localhost port
+	// (0 = disabled); clients authenticate with APIToken
+	GRPCPort int
+
+	// Worker accepts conversion jobs from other instances over the HTTP API.
+	// With WorkerSharedStorage, jobs may name paths under WorkerRoots
+	// instead of uploading the input, and replace existing outputs only
+	// with WorkerAllowOverwrite.
+	Worker               bool
+	WorkerSharedStorage  bool
+	WorkerRoots          []string
+	WorkerAllowOverwrite bool
+
+	// RemoteWorker, if set, is the worker instance conversions of
+	// RemoteWorkerTypes are sent to. With RemoteWorkerSharedStorage inputs
+	// and outputs are read and written by path rather than transferred.
+	RemoteWorkerURL           string
+	RemoteWorkerToken         string
+	RemoteWorkerSharedStorage bool
+	RemoteWorkerTypes         []string
+
+	// DatabaseDriver and DatabaseDSN select a shared network database for
+	// the default profile; an empty driver or "sqlite" keeps DatabaseURL
+	DatabaseDriver string
+	DatabaseDSN    string
+
+	// Profile is the profile whose database and settings are in use
+	Profile string
+
+	// Migrations describes directories moved from an older layout at startup
+	Migrations []string
 }
 
-// New creates a new Config with default values
+// New creates a new Config with default values, overridden by the optional
+// configuration file and then by environment variables
 func New() (*Config, error) {
 	dataDir, err := getDataDir()
 	if err != nil {
 		return nil, err
 	}
+	configDir, err := getConfigDir(dataDir)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		return nil, err
+	}
 
-	logDir := filepath.Join(dataDir, "logs")
-	dbDir := filepath.Join(dataDir, "data")
+	// A broken configuration file is reported and ignored rather than
+	// keeping the app from starting
+	configFile := configFilePath(configDir)
+	file, loaded, fileErr := loadConfigFile(configFile)
+	if !loaded {
+		configFile = ""
+	}
 
-	// Create directories if they don't exist
-	if err := os.MkdirAll(logDir, 0755); err != nil {
+	dataDir = firstNonEmpty(file.DataDir, dataDir)
+	cacheDir, err := getCacheDir(dataDir)
+	if err != nil {
 		return nil, err
 	}
-	if err := os.MkdirAll(dbDir, 0755); err != nil {
-		return nil, err
+	cacheDir = firstNonEmpty(file.CacheDir, cacheDir)
+
+	logDir := firstNonEmpty(file.LogDir, filepath.Join(dataDir, "logs"))
+	dbDir := filepath.Join(dataDir, "data")
+
+	// Create directories if they don't exist
+	for _, dir := range []string{logDir, dbDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, err
+		}
 	}
 
-	return &Config{
+	migrations := migrateLegacyCache(filepath.Join(dataDir, "cache"), cacheDir)
+
+	cfg := &Config{
 		AppName:     "Converzen",
 		Version:     "1.0.0",
+		ConfigDir:   configDir,
 		DataDir:     dataDir,
 		LogDir:      logDir,
 		LogFile:     filepath.Join(logDir, "app.log"),
+		CacheDir:    cacheDir,
+		TempDir:     firstNonEmpty(os.Getenv("CONVERZEN_TEMP_DIR"), file.TempDir, os.TempDir()),
 		DatabaseDir: dbDir,
 		DatabaseURL: filepath.Join(dbDir, "converzen.db"),
-		FFmpegPath:  findFFmpeg(dataDir),
-		Debug:       os.Getenv("DEBUG") == "true",
-	}, nil
+		FFmpegPath:  file.FFmpegPath,
+		SofficePath: file.SofficePath,
+		WkhtmlPath:  file.WkhtmlPath,
+		CalibrePath: file.CalibrePath,
+		Debug:       file.Debug != nil && *file.Debug,
+		Migrations:  migrations,
+
+		ConfigFile:           configFile,
+		WorkerLimits:         file.Concurrency,
+		HardwareAcceleration: file.HardwareAcceleration,
+		Telemetry:            file.Telemetry != nil && *file.Telemetry,
+		UpdateChecks:         file.UpdateChecks == nil || *file.UpdateChecks,
+		UpdateFeedURL:        firstNonEmpty(os.Getenv("CONVERZEN_UPDATE_FEED"), file.UpdateFeed, defaultUpdateFeedURL),
+		APIPort:              file.APIPort,
+		APIToken:             firstNonEmpty(os.Getenv("CONVERZEN_API_TOKEN"), file.APIToken),
+		APIAddress:           firstNonEmpty(file.APIAddress, "127.0.0.1"),
+		APITLSCert:           file.APITLSCert,
+		APITLSKey:            file.APITLSKey,
+		APIAllowedOrigins:    file.APIAllowedOrigins,
+		GRPCPort:             file.GRPCPort,
+		Worker:               file.Worker,
+		WorkerSharedStorage:  file.WorkerSharedStorage.Enabled,
+		WorkerRoots:          file.WorkerSharedStorage.Roots,
+		WorkerAllowOverwrite: file.WorkerSharedStorage.AllowOverwrite,
+
+		RemoteWorkerURL:           file.RemoteWorker.URL,
+		RemoteWorkerToken:         firstNonEmpty(os.Getenv("CONVERZEN_REMOTE_WORKER_TOKEN"), file.RemoteWorker.Token),
+		RemoteWorkerSharedStorage: file.RemoteWorker.SharedStorage,
+		RemoteWorkerTypes:         file.RemoteWorker.FileTypes,
+
+		DatabaseDriver: file.Database.Driver,
+		DatabaseDSN:    firstNonEmpty(os.Getenv("CONVERZEN_DATABASE_DSN"), file.Database.DSN),
+	}
+	if len(cfg.RemoteWorkerTypes) == 0 {
+		cfg.RemoteWorkerTypes = []string{"video"}
+	}
+	if fileErr != nil {
+		cfg.ConfigFileError = fileErr.Error()
+	}
+
+	// Search for the tools the configuration file doesn't locate
+	if cfg.FFmpegPath == "" {
+		cfg.FFmpegPath = findFFmpeg(dataDir)
+	}
+	if cfg.SofficePath == "" {
+		cfg.SofficePath = libreoffice.Find()
+	}
+	if cfg.WkhtmlPath == "" {
+		cfg.WkhtmlPath = htmlpdf.Find()
+	}
+	if cfg.CalibrePath == "" {
+		cfg.CalibrePath = calibre.Find()
+	}
+
+	// Environment variables override the configuration file
+	if debug := os.Getenv("DEBUG"); debug != "" {
+		cfg.Debug = debug == "true"
+	}
+
+	cfg.Profile = cfg.getActiveProfile()
+
+	return cfg, nil
 }
 
+// defaultUpdateFeedURL is the latest release of the app on GitHub
+const defaultUpdateFeedURL = "https://api.github.com/repos/zenfulcode/converzen/releases/latest"
+
 // getDataDir returns the appropriate data directory for the current OS
 func getDataDir() (string, error) {
 	var baseDir string
@@ -79,3 +240,67 @@ func getDataDir() (string, error) {
 
 	return filepath.Join(baseDir, "Converzen"), nil
 }
+
+// getConfigDir returns the directory for configuration files. On Linux this
+// is XDG_CONFIG_HOME; elsewhere configuration lives in the data directory.
+func getConfigDir(dataDir string) (string, error) {
+	if runtime.GOOS == "windows" || runtime.GOOS == "darwin" {
+		return dataDir, nil
+	}
+
+	baseDir := os.Getenv("XDG_CONFIG_HOME")
+	if baseDir == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		baseDir = filepath.Join(homeDir, ".config")
+	}
+	return filepath.Join(baseDir, "Converzen"), nil
+}
+
+// getCacheDir returns the directory for caches such as thumbnails. On Linux
+// this is XDG_CACHE_HOME; elsewhere caches live in the data directory.
+func getCacheDir(dataDir string) (string, error) {
+	if runtime.GOOS == "windows" || runtime.GOOS == "darwin" {
+		return filepath.Join(dataDir, "cache"), nil
+	}
+
+	baseDir := os.Getenv("XDG_CACHE_HOME")
+	if baseDir == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		baseDir = filepath.Join(homeDir, ".cache")
+	}
+	return filepath.Join(baseDir, "Converzen"), nil
+}
+
+// migrateLegacyCache moves the cache of installs that kept it in the data
+// directory to its own location. Caches can be rebuilt, so one that can't be
+// moved (e.g. across filesystems) is deleted instead. It returns a
+// description of what was done, if anything.
+func migrateLegacyCache(legacyDir, cacheDir string) []string {
+	if legacyDir == cacheDir {
+		return nil
+	}
+	if _, err := os.Stat(legacyDir); err != nil {
+		return nil
+	}
+
+	// Keep a cache that already exists at the new location
+	if _, err := os.Stat(cacheDir); err == nil {
+		os.RemoveAll(legacyDir)
+		return []string{fmt.Sprintf("removed old cache %s", legacyDir)}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(cacheDir), 0755); err == nil {
+		if err := os.Rename(legacyDir, cacheDir); err == nil {
+			return []string{fmt.Sprintf("moved cache from %s to %s", legacyDir, cacheDir)}
+		}
+	}
+
+	os.RemoveAll(legacyDir)
+	return []string{fmt.Sprintf("removed old cache %s, it could not be moved to %s", legacyDir, cacheDir)}
+}
@@ -0,0 +1,129 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// DefaultProfile is the profile used until another one is selected. It keeps
+// the database of installs that predate profiles.
+const DefaultProfile = "default"
+
+// profileNamePattern limits profile names to ones that are safe as file names
+var profileNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]{0,31}$`)
+
+// ValidateProfileName checks that a profile name can be used
+func ValidateProfileName(name string) error {
+	if !profileNamePattern.MatchString(name) {
+		return fmt.Errorf("profile names must be 1-32 letters, digits, dashes or underscores")
+	}
+	return nil
+}
+
+// profilesDir returns the directory holding the databases of non-default profiles
+func (c *Config) profilesDir() string {
+	return filepath.Join(c.DatabaseDir, "profiles")
+}
+
+// ProfileDatabaseURL returns the database path of a profile
+func (c *Config) ProfileDatabaseURL(name string) string {
+	if name == DefaultProfile {
+		return c.DatabaseURL
+	}
+	return filepath.Join(c.profilesDir(), name+".db")
+}
+
+// SharedDatabase reports whether the default profile uses a network
+// database instead of its local one
+func (c *Config) SharedDatabase() bool {
+	return c.DatabaseDriver != "" && c.DatabaseDriver != "sqlite"
+}
+
+// ProfileLogDir returns the directory for a profile's conversion logs, which
+// are named after conversion IDs and so can't be shared between profiles
+func (c *Config) ProfileLogDir(name string) string {
+	if name == DefaultProfile {
+		return filepath.Join(c.LogDir, "conversions")
+	}
+	return filepath.Join(c.LogDir, "conversions", "profiles", name)
+}
+
+// ProfileExists reports whether a profile has a database
+func (c *Config) ProfileExists(name string) bool {
+	if name == DefaultProfile {
+		return true
+	}
+	_, err := os.Stat(c.ProfileDatabaseURL(name))
+	return err == nil
+}
+
+// ListProfiles returns the names of all profiles, the default one first
+func (c *Config) ListProfiles() ([]string, error) {
+	names := []string{DefaultProfile}
+
+	entries, err := os.ReadDir(c.profilesDir())
+	if os.IsNotExist(err) {
+		return names, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var others []string
+	for _, entry := range entries {
+		name := strings.TrimSuffix(entry.Name(), ".db")
+		if entry.IsDir() || name == entry.Name() || name == DefaultProfile || ValidateProfileName(name) != nil {
+			continue
+		}
+		others = append(others, name)
+	}
+	sort.Strings(others)
+	return append(names, others...), nil
+}
+
+// DeleteProfile removes a profile's database and conversion logs
+func (c *Config) DeleteProfile(name string) error {
+	if name == DefaultProfile {
+		return fmt.Errorf("the default profile can't be deleted")
+	}
+
+	dbPath := c.ProfileDatabaseURL(name)
+	for _, path := range []string{dbPath, dbPath + "-wal", dbPath + "-shm", dbPath + "-journal"} {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return os.RemoveAll(c.ProfileLogDir(name))
+}
+
+// activeProfileFile stores the profile selected last
+func (c *Config) activeProfileFile() string {
+	return filepath.Join(c.ConfigDir, "profile")
+}
+
+// SaveActiveProfile remembers the profile to open at the next startup
+func (c *Config) SaveActiveProfile(name string) error {
+	return os.WriteFile(c.activeProfileFile(), []byte(name+"\n"), 0644)
+}
+
+// getActiveProfile returns the profile to open at startup: CONVERZEN_PROFILE
+// if set, otherwise the one selected last, otherwise the default profile
+func (c *Config) getActiveProfile() string {
+	if name := os.Getenv("CONVERZEN_PROFILE"); name != "" && ValidateProfileName(name) == nil {
+		return name
+	}
+
+	data, err := os.ReadFile(c.activeProfileFile())
+	if err != nil {
+		return DefaultProfile
+	}
+	name := strings.TrimSpace(string(data))
+	if ValidateProfileName(name) != nil || !c.ProfileExists(name) {
+		return DefaultProfile
+	}
+	return name
+}
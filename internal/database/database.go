@@ -19,7 +19,9 @@ type Database struct {
 	log *logger.ComponentLogger
 }
 
-// New creates a new database connection
+// New creates a new database connection. It fails with ErrCorrupt when the
+// file is damaged, which Rebuild can recover from, and with
+// ErrKeyUnavailable when it is encrypted and its key can't be read.
 func New(dbPath string, log *logger.Logger) (*Database, error) {
 	componentLog := log.WithComponent("database")
 	componentLog.Info("Initializing database at: %s", dbPath)
@@ -31,15 +33,54 @@ func New(dbPath string, log *logger.Logger) (*Database, error) {
 		return nil, fmt.Errorf("failed to create database directory: %w", err)
 	}
 
+	return open(sqlite.Open(dbPath), componentLog)
+}
+
+// NewShared connects to a network database, e.g. Postgres, shared by
+// several workstations. driver must be one of Drivers.
+func NewShared(driver, dsn string, log *logger.Logger) (*Database, error) {
+	componentLog := log.WithComponent("database")
+	componentLog.Info("Connecting to %s database", driver)
+
+	dialector, err := dialector(driver, dsn)
+	if err != nil {
+		componentLog.Error("Failed to open database: %v", err)
+		return nil, err
+	}
+	return open(dialector, componentLog)
+}
+
+// NewInMemory creates an empty database that only lasts while the app
+// runs, so conversions keep working when the database file can't be used
+func NewInMemory(log *logger.Logger) (*Database, error) {
+	componentLog := log.WithComponent("database")
+	componentLog.Warn("Initializing in-memory database, history will not be kept")
+
+	// A single connection keeps every query on the same in-memory database
+	database, err := open(sqlite.Open("file:converzen?mode=memory&cache=shared"), componentLog)
+	if err != nil {
+		return nil, err
+	}
+	if sqlDB, err := database.DB.DB(); err == nil {
+		sqlDB.SetMaxOpenConns(1)
+	}
+	return database, nil
+}
+
+// open opens a database, checks its integrity, migrates it and loads its
+// key if it is encrypted
+func open(dialector gorm.Dialector, componentLog *logger.ComponentLogger) (*Database, error) {
 	// Configure GORM logger
 	gormConfig := &gorm.Config{
 		Logger: gormlogger.Default.LogMode(gormlogger.Silent),
 	}
 
-	// Open SQLite database
-	db, err := gorm.Open(sqlite.Open(dbPath), gormConfig)
+	db, err := gorm.Open(dialector, gormConfig)
 	if err != nil {
 		componentLog.Error("Failed to open database: %v", err)
+		if isCorruption(err) {
+			return nil, fmt.Errorf("%w: %v", ErrCorrupt, err)
+		}
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
 
@@ -48,12 +89,29 @@ func New(dbPath string, log *logger.Logger) (*Database, error) {
 		log: componentLog,
 	}
 
+	// Check the file before relying on it
+	if err := database.checkIntegrity(); err != nil {
+		componentLog.Error("Database integrity check failed: %v", err)
+		database.Close()
+		return nil, err
+	}
+
 	// Run migrations
 	if err := database.migrate(); err != nil {
 		componentLog.Error("Failed to run migrations: %v", err)
+		database.Close()
+		if isCorruption(err) {
+			return nil, fmt.Errorf("%w: %v", ErrCorrupt, err)
+		}
 		return nil, fmt.Errorf("failed to run migrations: %w", err)
 	}
 
+	if err := database.loadEncryption(); err != nil {
+		componentLog.Error("Failed to load database encryption: %v", err)
+		database.Close()
+		return nil, err
+	}
+
 	componentLog.Info("Database initialized successfully")
 	return database, nil
 }
@@ -65,6 +123,9 @@ func (d *Database) migrate() error {
 	err := d.DB.AutoMigrate(
 		&models.Conversion{},
 		&models.Setting{},
+		&models.RecentPath{},
+		&models.SettingChange{},
+		&models.Bookmark{},
 	)
 	if err != nil {
 		return fmt.Errorf("migration failed: %w", err)
@@ -0,0 +1,9 @@
+//go:build mysql
+
+package database
+
+import "gorm.io/driver/mysql"
+
+func init() {
+	RegisterDriver(DriverMySQL, mysql.Open)
+}
@@ -0,0 +1,67 @@
+package database
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// Driver names
+const (
+	DriverSQLite   = "sqlite"
+	DriverPostgres = "postgres"
+	DriverMySQL    = "mysql"
+)
+
+// Opener returns the GORM dialector of a driver for a DSN
+type Opener func(dsn string) gorm.Dialector
+
+var (
+	driversMu sync.RWMutex
+	drivers   = map[string]Opener{
+		DriverSQLite: sqlite.Open,
+	}
+)
+
+// RegisterDriver makes a database driver available to Open. SQLite is
+// always available; Postgres and MySQL register themselves in builds with
+// the postgres and mysql tags, which keep their dependencies out of
+// default builds.
+func RegisterDriver(name string, opener Opener) {
+	driversMu.Lock()
+	defer driversMu.Unlock()
+	drivers[name] = opener
+}
+
+// Drivers returns the names of the available drivers
+func Drivers() []string {
+	driversMu.RLock()
+	defer driversMu.RUnlock()
+
+	names := make([]string, 0, len(drivers))
+	for name := range drivers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// dialector returns the dialector of a driver for a DSN
+func dialector(driver, dsn string) (gorm.Dialector, error) {
+	driversMu.RLock()
+	opener, ok := drivers[driver]
+	driversMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("database driver %s is not available in this build", driver)
+	}
+	return opener(dsn), nil
+}
+
+// Dialect returns the name of the database system behind db, e.g. "sqlite"
+// or "postgres", for the few queries that differ between them
+func Dialect(db *gorm.DB) string {
+	return db.Dialector.Name()
+}
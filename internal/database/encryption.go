@@ -0,0 +1,370 @@
+package database
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+
+	"converzen/pkg/keychain"
+)
+
+const (
+	// Keychain item holding the hex-encoded key of encrypted databases
+	keychainService = "Converzen"
+	keychainAccount = "database-key"
+
+	// encryptedPrefix marks an encrypted value, so plaintext values written
+	// before a database was encrypted still read back
+	encryptedPrefix = "enc1:"
+
+	// encryptionMarker is the setting that marks a database as encrypted.
+	// It is read before the key is loaded, so it is never encrypted itself.
+	encryptionMarker = "database_encrypted"
+)
+
+// ErrKeyUnavailable is returned when a database is encrypted but its key
+// can't be read from the keychain
+var ErrKeyUnavailable = errors.New("database encryption key is unavailable")
+
+// encryptedColumns lists the columns that hold file paths, notes and other
+// text worth protecting, by table. They are the fields tagged
+// serializer:encrypted in the models.
+var encryptedColumns = map[string][]string{
+	"conversions":     {"input_path", "output_path", "error_message", "settings", "note"},
+	"recent_paths":    {"path"},
+	"bookmarks":       {"path"},
+	"settings":        {"value"},
+	"setting_changes": {"old_value", "new_value"},
+}
+
+// fieldCipher encrypts column values with AES-256-GCM. The nonce is derived
+// from the value, so equal values encrypt equally and lookups, unique
+// indexes and upserts on encrypted columns keep working.
+type fieldCipher struct {
+	aead  cipher.AEAD
+	ivKey []byte
+}
+
+// newFieldCipher derives the encryption and nonce keys from a 32-byte key
+func newFieldCipher(key []byte) (*fieldCipher, error) {
+	if len(key) != 32 {
+		return nil, fmt.Errorf("invalid database key length %d", len(key))
+	}
+
+	block, err := aes.NewCipher(deriveKey(key, "converzen field encryption"))
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &fieldCipher{aead: aead, ivKey: deriveKey(key, "converzen field nonce")}, nil
+}
+
+// deriveKey derives a purpose-specific key from the database key
+func deriveKey(key []byte, purpose string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(purpose))
+	return mac.Sum(nil)
+}
+
+// seal encrypts a value. Empty and already encrypted values are returned
+// as they are.
+func (c *fieldCipher) seal(value string) string {
+	if value == "" || strings.HasPrefix(value, encryptedPrefix) {
+		return value
+	}
+
+	mac := hmac.New(sha256.New, c.ivKey)
+	mac.Write([]byte(value))
+	nonce := mac.Sum(nil)[:c.aead.NonceSize()]
+
+	sealed := c.aead.Seal(nonce, nonce, []byte(value), nil)
+	return encryptedPrefix + base64.StdEncoding.EncodeToString(sealed)
+}
+
+// open decrypts a value. Values without the encrypted prefix are returned
+// as they are.
+func (c *fieldCipher) open(value string) (string, error) {
+	if !strings.HasPrefix(value, encryptedPrefix) {
+		return value, nil
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, encryptedPrefix))
+	if err != nil || len(sealed) < c.aead.NonceSize() {
+		return "", fmt.Errorf("invalid encrypted value")
+	}
+	nonce, ciphertext := sealed[:c.aead.NonceSize()], sealed[c.aead.NonceSize():]
+	plaintext, err := c.aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt value: %w", err)
+	}
+	return string(plaintext), nil
+}
+
+// cipherKey is the context key of the cipher of an encrypted database. The
+// cipher travels in the context of Database.DB, so every session, query and
+// transaction derived from it encrypts and decrypts.
+type cipherKey struct{}
+
+// cipherFrom returns the cipher carried by a context, or nil
+func cipherFrom(ctx context.Context) *fieldCipher {
+	if ctx == nil {
+		return nil
+	}
+	c, _ := ctx.Value(cipherKey{}).(*fieldCipher)
+	return c
+}
+
+// Encrypted reports whether db belongs to an encrypted database. Queries
+// that match encrypted columns by pattern, which can't be done in SQL,
+// filter in Go instead when it does.
+func Encrypted(db *gorm.DB) bool {
+	return cipherFrom(db.Statement.Context) != nil
+}
+
+// Seal returns value as it is stored in an encrypted column of db, for
+// equality conditions on the column. It is value itself unless db belongs
+// to an encrypted database.
+func Seal(db *gorm.DB, value string) string {
+	if c := cipherFrom(db.Statement.Context); c != nil {
+		return c.seal(value)
+	}
+	return value
+}
+
+// SealAll returns values as Seal does
+func SealAll(db *gorm.DB, values []string) []string {
+	c := cipherFrom(db.Statement.Context)
+	if c == nil {
+		return values
+	}
+	sealed := make([]string, len(values))
+	for i, value := range values {
+		sealed[i] = c.seal(value)
+	}
+	return sealed
+}
+
+// encryptedSerializer is the "encrypted" gorm serializer of string fields.
+// It encrypts when the statement's context carries a cipher and decrypts
+// any encrypted value it reads.
+type encryptedSerializer struct{}
+
+func init() {
+	schema.RegisterSerializer("encrypted", encryptedSerializer{})
+}
+
+// Scan decrypts a column value into a string field
+func (encryptedSerializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) error {
+	var value string
+	switch v := dbValue.(type) {
+	case nil:
+	case string:
+		value = v
+	case []byte:
+		value = string(v)
+	default:
+		return fmt.Errorf("unsupported value %T for encrypted field %s", dbValue, field.Name)
+	}
+
+	if strings.HasPrefix(value, encryptedPrefix) {
+		c := cipherFrom(ctx)
+		if c == nil {
+			return ErrKeyUnavailable
+		}
+		plaintext, err := c.open(value)
+		if err != nil {
+			return err
+		}
+		value = plaintext
+	}
+
+	field.ReflectValueOf(ctx, dst).SetString(value)
+	return nil
+}
+
+// Value encrypts a string field for storage
+func (encryptedSerializer) Value(ctx context.Context, field *schema.Field, dst reflect.Value, fieldValue interface{}) (interface{}, error) {
+	value := reflect.ValueOf(fieldValue).String()
+	if c := cipherFrom(ctx); c != nil {
+		return c.seal(value), nil
+	}
+	return value, nil
+}
+
+// Encrypted reports whether the database is encrypted at rest
+func (d *Database) Encrypted() bool {
+	return Encrypted(d.DB)
+}
+
+// loadEncryption makes an encrypted database encrypt and decrypt through
+// Database.DB, with the key from the keychain
+func (d *Database) loadEncryption() error {
+	encrypted, err := d.markedEncrypted()
+	if err != nil || !encrypted {
+		return err
+	}
+
+	key, err := loadKey(false)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrKeyUnavailable, err)
+	}
+	c, err := newFieldCipher(key)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrKeyUnavailable, err)
+	}
+
+	d.DB = d.DB.WithContext(context.WithValue(context.Background(), cipherKey{}, c))
+	d.log.Info("Database is encrypted")
+	return nil
+}
+
+// markedEncrypted reports whether the database has the encryption marker
+func (d *Database) markedEncrypted() (bool, error) {
+	var values []string
+	query := fmt.Sprintf("SELECT value FROM settings WHERE %s = ? AND deleted_at IS NULL", d.DB.Statement.Quote("key"))
+	err := d.DB.Raw(query, encryptionMarker).
+		Scan(&values).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to read encryption marker: %w", err)
+	}
+	return len(values) > 0 && values[0] == "true", nil
+}
+
+// SetEncryption encrypts or decrypts the stored values of the database,
+// creating the key in the keychain when a database is first encrypted.
+// Repositories created from Database.DB before the change must be
+// recreated, as the cipher travels in its context.
+func (d *Database) SetEncryption(enabled bool) error {
+	if enabled == d.Encrypted() {
+		return nil
+	}
+
+	key, err := loadKey(enabled)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrKeyUnavailable, err)
+	}
+	c, err := newFieldCipher(key)
+	if err != nil {
+		return err
+	}
+
+	if enabled {
+		d.log.Info("Encrypting database")
+	} else {
+		d.log.Info("Decrypting database")
+	}
+
+	// Raw SQL reads and writes the stored values, whatever the context
+	err = d.DB.Session(&gorm.Session{NewDB: true}).WithContext(context.Background()).Transaction(func(tx *gorm.DB) error {
+		for table, columns := range encryptedColumns {
+			for _, column := range columns {
+				if err := convertColumn(tx, c, table, column, enabled); err != nil {
+					return err
+				}
+			}
+		}
+
+		key := tx.Statement.Quote("key")
+		if err := tx.Exec("DELETE FROM settings WHERE "+key+" = ?", encryptionMarker).Error; err != nil {
+			return fmt.Errorf("failed to clear encryption marker: %w", err)
+		}
+		if enabled {
+			err := tx.Exec("INSERT INTO settings (created_at, updated_at, "+key+", value) VALUES (CURRENT_TIMESTAMP, CURRENT_TIMESTAMP, ?, ?)",
+				encryptionMarker, "true").Error
+			if err != nil {
+				return fmt.Errorf("failed to set encryption marker: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		d.log.Error("Failed to change database encryption: %v", err)
+		return err
+	}
+
+	// Rewrite the file so no plaintext is left in free pages
+	if Dialect(d.DB) == DriverSQLite {
+		if err := d.DB.Exec("VACUUM").Error; err != nil {
+			d.log.Warn("Failed to compact database: %v", err)
+		}
+	}
+
+	if enabled {
+		d.DB = d.DB.WithContext(context.WithValue(context.Background(), cipherKey{}, c))
+	} else {
+		d.DB = d.DB.WithContext(context.Background())
+	}
+	d.log.Info("Database encryption changed")
+	return nil
+}
+
+// convertColumn encrypts or decrypts every value of a column, including
+// those of soft-deleted rows
+func convertColumn(tx *gorm.DB, c *fieldCipher, table, column string, encrypt bool) error {
+	type row struct {
+		ID    uint
+		Value string
+	}
+
+	var rows []row
+	query := fmt.Sprintf("SELECT id, %s AS value FROM %s WHERE %s IS NOT NULL AND %s != ''", column, table, column, column)
+	if table == "settings" {
+		query += fmt.Sprintf(" AND %s != '%s'", tx.Statement.Quote("key"), encryptionMarker)
+	}
+	if err := tx.Raw(query).Scan(&rows).Error; err != nil {
+		return fmt.Errorf("failed to read %s.%s: %w", table, column, err)
+	}
+
+	update := fmt.Sprintf("UPDATE %s SET %s = ? WHERE id = ?", table, column)
+	for _, r := range rows {
+		value := c.seal(r.Value)
+		if !encrypt {
+			var err error
+			if value, err = c.open(r.Value); err != nil {
+				return fmt.Errorf("failed to decrypt %s.%s of row %d: %w", table, column, r.ID, err)
+			}
+		}
+		if value == r.Value {
+			continue
+		}
+		if err := tx.Exec(update, value, r.ID).Error; err != nil {
+			return fmt.Errorf("failed to update %s.%s: %w", table, column, err)
+		}
+	}
+	return nil
+}
+
+// loadKey reads the database key from the keychain, creating and storing
+// a new one when there is none and create is set
+func loadKey(create bool) ([]byte, error) {
+	stored, err := keychain.Get(keychainService, keychainAccount)
+	if errors.Is(err, keychain.ErrNotFound) && create {
+		key := make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return nil, fmt.Errorf("failed to generate database key: %w", err)
+		}
+		if err := keychain.Set(keychainService, keychainAccount, hex.EncodeToString(key)); err != nil {
+			return nil, err
+		}
+		return key, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return hex.DecodeString(stored)
+}
@@ -0,0 +1,68 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"converzen/internal/logger"
+)
+
+// ErrCorrupt is returned when a database file is damaged or isn't a
+// SQLite database
+var ErrCorrupt = errors.New("database is corrupt")
+
+// journalSuffixes are the files SQLite keeps next to a database, moved
+// along with it when it is rebuilt
+var journalSuffixes = []string{"-wal", "-shm", "-journal"}
+
+// checkIntegrity runs SQLite's quick check, which finds damaged pages and
+// indexes in a fraction of the time of a full integrity check. Network
+// databases look after their own integrity.
+func (d *Database) checkIntegrity() error {
+	if Dialect(d.DB) != DriverSQLite {
+		return nil
+	}
+
+	var problems []string
+	if err := d.DB.Raw("PRAGMA quick_check").Scan(&problems).Error; err != nil {
+		if isCorruption(err) {
+			return fmt.Errorf("%w: %v", ErrCorrupt, err)
+		}
+		return fmt.Errorf("integrity check failed: %w", err)
+	}
+	if len(problems) == 1 && problems[0] == "ok" {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrCorrupt, strings.Join(problems, "; "))
+}
+
+// isCorruption reports whether err is SQLite reporting a damaged file
+func isCorruption(err error) bool {
+	msg := err.Error()
+	return strings.Contains(msg, "malformed") || strings.Contains(msg, "not a database")
+}
+
+// Rebuild moves a corrupt database file aside, with its journal files, and
+// creates an empty database in its place. It returns the new database and
+// the path the corrupt file was moved to, which is kept for recovery by hand.
+func Rebuild(dbPath string, log *logger.Logger) (*Database, string, error) {
+	backupPath := fmt.Sprintf("%s.corrupt-%s", dbPath, time.Now().Format("20060102-150405"))
+	if err := os.Rename(dbPath, backupPath); err != nil {
+		return nil, "", fmt.Errorf("failed to back up corrupt database: %w", err)
+	}
+	for _, suffix := range journalSuffixes {
+		if err := os.Rename(dbPath+suffix, backupPath+suffix); err != nil && !os.IsNotExist(err) {
+			return nil, "", fmt.Errorf("failed to back up corrupt database: %w", err)
+		}
+	}
+	log.Warn("database", "Moved corrupt database to %s", backupPath)
+
+	database, err := New(dbPath, log)
+	if err != nil {
+		return nil, backupPath, err
+	}
+	return database, backupPath, nil
+}
@@ -0,0 +1,26 @@
+// Package desktop performs actions on the user's desktop session: opening
+// folders, moving files to the trash and putting the computer to sleep or
+// shutting it down.
+package desktop
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// ErrUnsupported is returned on platforms without an implementation of an action
+var ErrUnsupported = errors.New("not supported on this platform")
+
+// run runs a command, including its output in the error if it fails
+func run(cmd *exec.Cmd) error {
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		if message := strings.TrimSpace(string(output)); message != "" {
+			return fmt.Errorf("%s: %w: %s", cmd.Args[0], err, message)
+		}
+		return fmt.Errorf("%s: %w", cmd.Args[0], err)
+	}
+	return nil
+}
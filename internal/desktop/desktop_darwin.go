@@ -0,0 +1,29 @@
+//go:build darwin
+
+package desktop
+
+import "os/exec"
+
+// OpenFolder shows a folder in Finder
+func OpenFolder(path string) error {
+	return run(exec.Command("open", path))
+}
+
+// Trash moves a file to the Trash through Finder, so it can be put back
+func Trash(path string) error {
+	return run(exec.Command("osascript",
+		"-e", "on run argv",
+		"-e", "tell application \"Finder\" to delete POSIX file (item 1 of argv)",
+		"-e", "end run",
+		path))
+}
+
+// Sleep puts the computer to sleep
+func Sleep() error {
+	return run(exec.Command("pmset", "sleepnow"))
+}
+
+// Shutdown shuts the computer down, letting apps ask to save their documents
+func Shutdown() error {
+	return run(exec.Command("osascript", "-e", "tell application \"System Events\" to shut down"))
+}
@@ -0,0 +1,25 @@
+//go:build linux
+
+package desktop
+
+import "os/exec"
+
+// OpenFolder opens a folder in the default file manager
+func OpenFolder(path string) error {
+	return run(exec.Command("xdg-open", path))
+}
+
+// Trash moves a file to the desktop's trash using gio
+func Trash(path string) error {
+	return run(exec.Command("gio", "trash", path))
+}
+
+// Sleep suspends the computer through systemd
+func Sleep() error {
+	return run(exec.Command("systemctl", "suspend"))
+}
+
+// Shutdown powers the computer off through systemd
+func Shutdown() error {
+	return run(exec.Command("systemctl", "poweroff"))
+}
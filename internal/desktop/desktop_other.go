@@ -0,0 +1,23 @@
+//go:build !linux && !darwin && !windows
+
+package desktop
+
+// OpenFolder returns ErrUnsupported on platforms without a desktop integration
+func OpenFolder(path string) error {
+	return ErrUnsupported
+}
+
+// Trash returns ErrUnsupported on platforms without a desktop integration
+func Trash(path string) error {
+	return ErrUnsupported
+}
+
+// Sleep returns ErrUnsupported on platforms without a desktop integration
+func Sleep() error {
+	return ErrUnsupported
+}
+
+// Shutdown returns ErrUnsupported on platforms without a desktop integration
+func Shutdown() error {
+	return ErrUnsupported
+}
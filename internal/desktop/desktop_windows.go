@@ -0,0 +1,36 @@
+//go:build windows
+
+package desktop
+
+import (
+	"os"
+	"os/exec"
+)
+
+// trashScript moves the file named by CONVERZEN_TRASH_PATH to the Recycle
+// Bin. The path is passed in the environment to avoid quoting it.
+const trashScript = `Add-Type -AssemblyName Microsoft.VisualBasic; ` +
+	`[Microsoft.VisualBasic.FileIO.FileSystem]::DeleteFile($env:CONVERZEN_TRASH_PATH, 'OnlyErrorDialogs', 'SendToRecycleBin')`
+
+// OpenFolder opens a folder in Explorer. Explorer's exit code is
+// meaningless, so only a failure to start it is reported.
+func OpenFolder(path string) error {
+	return exec.Command("explorer", path).Start()
+}
+
+// Trash moves a file to the Recycle Bin
+func Trash(path string) error {
+	cmd := exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", trashScript)
+	cmd.Env = append(os.Environ(), "CONVERZEN_TRASH_PATH="+path)
+	return run(cmd)
+}
+
+// Sleep suspends the computer. It hibernates instead when hibernation is enabled.
+func Sleep() error {
+	return run(exec.Command("rundll32.exe", "powrprof.dll,SetSuspendState", "0,1,0"))
+}
+
+// Shutdown shuts the computer down, letting apps ask to save their documents
+func Shutdown() error {
+	return run(exec.Command("shutdown", "/s", "/t", "0"))
+}
@@ -0,0 +1,59 @@
+// Package events delivers the app's progress and status events, like
+// "conversion:progress", to whatever is listening: the Wails frontend,
+// stdout in headless use, or clients of the HTTP API
+package events
+
+import "sync"
+
+// Sink receives published events
+type Sink interface {
+	Publish(event string, data interface{})
+}
+
+// Bus publishes events to a changing set of sinks. The zero value has no
+// sinks and is ready to use.
+type Bus struct {
+	mu    sync.RWMutex
+	sinks []Sink
+}
+
+// NewBus creates a bus publishing to sinks
+func NewBus(sinks ...Sink) *Bus {
+	return &Bus{sinks: sinks}
+}
+
+// Add starts publishing to sink
+func (b *Bus) Add(sink Sink) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.sinks = append(b.sinks, sink)
+}
+
+// Remove stops publishing to sink
+func (b *Bus) Remove(sink Sink) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for i, s := range b.sinks {
+		if s == sink {
+			b.sinks = append(b.sinks[:i:i], b.sinks[i+1:]...)
+			return
+		}
+	}
+}
+
+// Publish sends an event to every sink
+func (b *Bus) Publish(event string, data interface{}) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, sink := range b.sinks {
+		sink.Publish(event, data)
+	}
+}
+
+// SinkFunc adapts a function to a Sink
+type SinkFunc func(event string, data interface{})
+
+// Publish calls f
+func (f SinkFunc) Publish(event string, data interface{}) {
+	f(event, data)
+}
@@ -0,0 +1,50 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// wailsSink emits events to the Wails frontend
+type wailsSink struct {
+	ctx context.Context
+}
+
+// NewWailsSink creates a sink for the frontend of the Wails app running
+// with ctx
+func NewWailsSink(ctx context.Context) Sink {
+	return &wailsSink{ctx: ctx}
+}
+
+func (s *wailsSink) Publish(event string, data interface{}) {
+	runtime.EventsEmit(s.ctx, event, data)
+}
+
+// Message is an event encoded for sinks outside the app
+type Message struct {
+	Event string      `json:"event"`
+	Data  interface{} `json:"data"`
+}
+
+// writerSink writes events as JSON lines
+type writerSink struct {
+	mu      sync.Mutex
+	encoder *json.Encoder
+}
+
+// NewWriterSink creates a sink writing each event to w as a line of JSON,
+// e.g. stdout for command-line use. Events that fail to encode or write
+// are dropped.
+func NewWriterSink(w io.Writer) Sink {
+	return &writerSink{encoder: json.NewEncoder(w)}
+}
+
+func (s *writerSink) Publish(event string, data interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.encoder.Encode(Message{Event: event, Data: data})
+}
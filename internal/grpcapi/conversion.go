@@ -0,0 +1,155 @@
+package grpcapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	converzenv1 "converzen/api/converzenv1"
+	"converzen/internal/models"
+)
+
+// SubmitBatch converts a batch, streaming each file's progress and then the
+// batch result. The batch keeps converting if the client disconnects; its
+// files can be cancelled with CancelConversion.
+func (s *Server) SubmitBatch(req *converzenv1.BatchConversionRequest, stream grpc.ServerStreamingServer[converzenv1.BatchEvent]) error {
+	request, err := batchRequest(req)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	s.log.Info("Starting batch conversion: %d files to %s", len(request.Files), request.OutputFormat)
+
+	// Files convert in parallel, and a stream takes one Send at a time
+	var sendMu sync.Mutex
+	result, err := s.serviceOf().ConvertBatch(request, func(progress models.ConversionProgress) {
+		sendMu.Lock()
+		stream.Send(&converzenv1.BatchEvent{
+			Event: &converzenv1.BatchEvent_Progress{Progress: progressMessage(progress)},
+		})
+		sendMu.Unlock()
+		s.publishProgress(progress)
+	})
+	if err != nil {
+		s.log.Error("Batch conversion error: %v", err)
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	s.events.Publish("conversion:complete", result)
+
+	s.log.Info("Batch conversion complete: %d success, %d failed", result.SuccessCount, result.FailCount)
+	sendMu.Lock()
+	defer sendMu.Unlock()
+	return stream.Send(&converzenv1.BatchEvent{
+		Event: &converzenv1.BatchEvent_Result{Result: batchResultMessage(result)},
+	})
+}
+
+// publishProgress publishes a submitted batch's progress as the app does
+// its own, so the window and other API clients follow it too
+func (s *Server) publishProgress(progress models.ConversionProgress) {
+	if progress.Status == string(models.StatusStalled) {
+		s.events.Publish("conversion:stalled", progress)
+		return
+	}
+	if progress.Result != nil {
+		s.events.Publish("conversion:result", progress.Result)
+		progress.Result = nil
+	}
+	s.events.Publish("conversion:progress", progress)
+}
+
+// WatchProgress streams the progress of every conversion until the client
+// disconnects or the server stops
+func (s *Server) WatchProgress(_ *converzenv1.WatchProgressRequest, stream grpc.ServerStreamingServer[converzenv1.ConversionProgress]) error {
+	watcher := make(chan *converzenv1.ConversionProgress, watchBuffer)
+	s.watchersMu.Lock()
+	s.watchers[watcher] = true
+	s.watchersMu.Unlock()
+	defer func() {
+		s.watchersMu.Lock()
+		if s.watchers[watcher] {
+			delete(s.watchers, watcher)
+			close(watcher)
+		}
+		s.watchersMu.Unlock()
+	}()
+
+	for {
+		select {
+		case progress, ok := <-watcher:
+			if !ok {
+				return nil
+			}
+			if err := stream.Send(progress); err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			return nil
+		}
+	}
+}
+
+// CancelConversion cancels a running conversion
+func (s *Server) CancelConversion(_ context.Context, req *converzenv1.CancelConversionRequest) (*converzenv1.CancelConversionResponse, error) {
+	if err := s.serviceOf().CancelConversion(uint(req.GetId())); err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	return &converzenv1.CancelConversionResponse{}, nil
+}
+
+// ListHistory returns the history records matching a filter
+func (s *Server) ListHistory(_ context.Context, req *converzenv1.HistoryFilter) (*converzenv1.ListHistoryResponse, error) {
+	conversions, err := s.serviceOf().FilterConversionHistory(historyFilter(req))
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	response := &converzenv1.ListHistoryResponse{
+		Conversions: make([]*converzenv1.ConversionRecord, len(conversions)),
+	}
+	for i := range conversions {
+		response.Conversions[i] = conversionMessage(&conversions[i])
+	}
+	return response, nil
+}
+
+// batchRequest builds a batch request from its message: the JSON options
+// first, then the fields the message sets
+func batchRequest(req *converzenv1.BatchConversionRequest) (models.BatchConversionRequest, error) {
+	var request models.BatchConversionRequest
+	if req.GetOptionsJson() != "" {
+		if err := json.Unmarshal([]byte(req.GetOptionsJson()), &request); err != nil {
+			return request, fmt.Errorf("failed to parse options_json: %w", err)
+		}
+	}
+
+	if len(req.GetFiles()) > 0 {
+		request.Files = req.GetFiles()
+	}
+	if req.GetOutputFormat() != "" {
+		request.OutputFormat = req.GetOutputFormat()
+	}
+	if req.GetOutputDirectory() != "" {
+		request.OutputDirectory = req.GetOutputDirectory()
+	}
+	if req.GetNamingMode() != "" {
+		request.NamingMode = models.FileNamingMode(req.GetNamingMode())
+	}
+	if len(req.GetCustomNames()) > 0 {
+		request.CustomNames = req.GetCustomNames()
+	}
+	if req.GetNameTemplate() != "" {
+		request.NameTemplate = req.GetNameTemplate()
+	}
+	if req.GetMakeCopies() {
+		request.MakeCopies = true
+	}
+	if request.NamingMode == "" {
+		request.NamingMode = models.NamingModeOriginal
+	}
+	return request, nil
+}
@@ -0,0 +1,135 @@
+package grpcapi
+
+import (
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	converzenv1 "converzen/api/converzenv1"
+	"converzen/internal/models"
+)
+
+// progressEvent converts an app event to a progress message for
+// WatchProgress clients, or returns nil for events that aren't progress
+func progressEvent(event string, data interface{}) *converzenv1.ConversionProgress {
+	switch event {
+	case "conversion:progress", "conversion:stalled":
+		if progress, ok := data.(models.ConversionProgress); ok {
+			return progressMessage(progress)
+		}
+	case "conversion:result":
+		if result, ok := data.(*models.ConversionResult); ok && result != nil {
+			status := models.StatusCompleted
+			switch {
+			case result.Skipped:
+				status = models.StatusSkipped
+			case !result.Success:
+				status = models.StatusFailed
+			}
+			return &converzenv1.ConversionProgress{
+				InputPath: result.InputPath,
+				Progress:  100,
+				Status:    string(status),
+				Result:    resultMessage(result),
+			}
+		}
+	}
+	return nil
+}
+
+func progressMessage(progress models.ConversionProgress) *converzenv1.ConversionProgress {
+	return &converzenv1.ConversionProgress{
+		Id:        uint64(progress.ID),
+		InputPath: progress.InputPath,
+		Progress:  progress.Progress,
+		Status:    progress.Status,
+		Result:    resultMessage(progress.Result),
+	}
+}
+
+func resultMessage(result *models.ConversionResult) *converzenv1.ConversionResult {
+	if result == nil {
+		return nil
+	}
+	return &converzenv1.ConversionResult{
+		Success:        result.Success,
+		InputPath:      result.InputPath,
+		OutputPath:     result.OutputPath,
+		OutputSize:     result.OutputSize,
+		ErrorMessage:   result.ErrorMessage,
+		Duration:       result.Duration,
+		Method:         string(result.Method),
+		Warnings:       result.Warnings,
+		Skipped:        result.Skipped,
+		InputSize:      result.InputSize,
+		SavingsPercent: result.SavingsPercent,
+	}
+}
+
+func batchResultMessage(result *models.BatchConversionResult) *converzenv1.BatchConversionResult {
+	message := &converzenv1.BatchConversionResult{
+		BatchId:        result.BatchID,
+		TotalFiles:     int32(result.TotalFiles),
+		SuccessCount:   int32(result.SuccessCount),
+		FailCount:      int32(result.FailCount),
+		SkippedCount:   int32(result.SkippedCount),
+		Results:        make([]*converzenv1.ConversionResult, len(result.Results)),
+		HasMoreResults: result.HasMoreResults,
+		TotalDuration:  result.TotalDuration,
+		Warnings:       result.Warnings,
+	}
+	for i := range result.Results {
+		message.Results[i] = resultMessage(&result.Results[i])
+	}
+	return message
+}
+
+func conversionMessage(conversion *models.Conversion) *converzenv1.ConversionRecord {
+	return &converzenv1.ConversionRecord{
+		Id:           uint64(conversion.ID),
+		BatchId:      conversion.BatchID,
+		InputPath:    conversion.InputPath,
+		OutputPath:   conversion.OutputPath,
+		InputFormat:  conversion.InputFormat,
+		OutputFormat: conversion.OutputFormat,
+		FileType:     string(conversion.FileType),
+		FileSize:     conversion.FileSize,
+		OutputSize:   conversion.OutputSize,
+		Status:       string(conversion.Status),
+		ErrorMessage: conversion.ErrorMessage,
+		CreatedAt:    timestamppb.New(conversion.CreatedAt),
+		CompletedAt:  timestamp(conversion.CompletedAt),
+		Backend:      conversion.Backend,
+		Note:         conversion.Note,
+	}
+}
+
+func historyFilter(message *converzenv1.HistoryFilter) models.HistoryFilter {
+	filter := models.HistoryFilter{
+		FileType:        models.FileType(message.GetFileType()),
+		OutputFormat:    message.GetOutputFormat(),
+		Search:          message.GetSearch(),
+		Limit:           int(message.GetLimit()),
+		IncludeArchived: message.GetIncludeArchived(),
+	}
+	for _, status := range message.GetStatuses() {
+		filter.Statuses = append(filter.Statuses, models.ConversionStatus(status))
+	}
+	if message.GetFrom() != nil {
+		from := message.GetFrom().AsTime()
+		filter.From = &from
+	}
+	if message.GetTo() != nil {
+		to := message.GetTo().AsTime()
+		filter.To = &to
+	}
+	return filter
+}
+
+// timestamp converts an optional time, nil when unset
+func timestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
@@ -0,0 +1,169 @@
+// Package grpcapi serves the optional gRPC automation API defined in
+// api/proto/converzen/v1, which lets other tools submit conversions,
+// follow their progress and query history on this machine
+package grpcapi
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	converzenv1 "converzen/api/converzenv1"
+	"converzen/internal/events"
+	"converzen/internal/logger"
+	"converzen/internal/services"
+)
+
+const (
+	// address is the only address the server listens on, as the API
+	// controls conversions of local paths
+	address = "127.0.0.1"
+
+	// shutdownTimeout bounds how long Stop waits for open calls
+	shutdownTimeout = 5 * time.Second
+
+	// watchBuffer is the number of events queued for a WatchProgress
+	// client; clients that fall further behind miss events rather than
+	// slowing conversions down
+	watchBuffer = 256
+)
+
+// ServiceFunc returns the conversion service of the current profile
+type ServiceFunc func() services.ConversionService
+
+// Server is the gRPC automation API. Every call must carry the configured
+// token as a bearer token in the "authorization" metadata.
+type Server struct {
+	converzenv1.UnimplementedConversionServer
+
+	token     string
+	addr      string
+	serviceOf ServiceFunc
+	events    events.Sink
+	server    *grpc.Server
+	log       *logger.ComponentLogger
+
+	// WatchProgress clients, each with its queue of events
+	watchersMu sync.Mutex
+	watchers   map[chan *converzenv1.ConversionProgress]bool
+}
+
+// New creates a server for port on localhost. Conversions submitted through
+// it are converted with the service serviceOf returns, and their events
+// published to sink like the app's own. It fails without a token, since any
+// local process could otherwise connect.
+func New(port int, token string, serviceOf ServiceFunc, sink events.Sink, log *logger.Logger) (*Server, error) {
+	if token == "" {
+		return nil, fmt.Errorf("the gRPC API requires an api_token")
+	}
+
+	s := &Server{
+		token:     token,
+		addr:      net.JoinHostPort(address, fmt.Sprint(port)),
+		serviceOf: serviceOf,
+		events:    sink,
+		log:       log.WithComponent("grpc"),
+		watchers:  make(map[chan *converzenv1.ConversionProgress]bool),
+	}
+	s.server = grpc.NewServer(
+		grpc.UnaryInterceptor(s.authorizedUnary),
+		grpc.StreamInterceptor(s.authorizedStream),
+	)
+	converzenv1.RegisterConversionServer(s.server, s)
+	return s, nil
+}
+
+// Start listens and serves in the background
+func (s *Server) Start() error {
+	listener, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", s.addr, err)
+	}
+
+	s.log.Info("gRPC API listening on %s", listener.Addr())
+	go func() {
+		if err := s.server.Serve(listener); err != nil {
+			s.log.Error("gRPC API stopped: %v", err)
+		}
+	}()
+	return nil
+}
+
+// Stop ends every WatchProgress stream and stops the server, waiting up
+// to shutdownTimeout for submitted batches to be answered
+func (s *Server) Stop() {
+	s.watchersMu.Lock()
+	for watcher := range s.watchers {
+		delete(s.watchers, watcher)
+		close(watcher)
+	}
+	s.watchersMu.Unlock()
+
+	stopped := make(chan struct{})
+	go func() {
+		s.server.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(shutdownTimeout):
+		s.log.Warn("Failed to stop gRPC API cleanly, closing open calls")
+		s.server.Stop()
+	}
+}
+
+// Publish sends the app's progress events to WatchProgress clients, making
+// the server an events.Sink
+func (s *Server) Publish(event string, data interface{}) {
+	progress := progressEvent(event, data)
+	if progress == nil {
+		return
+	}
+
+	s.watchersMu.Lock()
+	defer s.watchersMu.Unlock()
+	for watcher := range s.watchers {
+		select {
+		case watcher <- progress:
+		default:
+		}
+	}
+}
+
+// authorize rejects calls without the server's token
+func (s *Server) authorize(ctx context.Context) error {
+	md, _ := metadata.FromIncomingContext(ctx)
+	token := ""
+	for _, header := range md.Get("authorization") {
+		if strings.HasPrefix(header, "Bearer ") {
+			token = strings.TrimPrefix(header, "Bearer ")
+		}
+	}
+	if subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+		return status.Error(codes.Unauthenticated, "unauthorized")
+	}
+	return nil
+}
+
+func (s *Server) authorizedUnary(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := s.authorize(ctx); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (s *Server) authorizedStream(srv interface{}, stream grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := s.authorize(stream.Context()); err != nil {
+		return err
+	}
+	return handler(srv, stream)
+}
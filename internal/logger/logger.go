@@ -45,8 +45,14 @@ type Logger struct {
 	filePath string
 }
 
-// New creates a new Logger instance
+// New creates a new Logger instance writing to filePath and stdout
 func New(filePath string, level Level) (*Logger, error) {
+	return NewWithConsole(filePath, level, os.Stdout)
+}
+
+// NewWithConsole creates a Logger writing to filePath and console, or only
+// to filePath when console is nil, e.g. when stdout carries a CLI's output
+func NewWithConsole(filePath string, level Level, console io.Writer) (*Logger, error) {
 	// Ensure the directory exists
 	dir := filepath.Dir(filePath)
 	if err := os.MkdirAll(dir, 0755); err != nil {
@@ -59,9 +65,12 @@ func New(filePath string, level Level) (*Logger, error) {
 		return nil, fmt.Errorf("failed to open log file: %w", err)
 	}
 
-	// Write to both file and stdout
-	multiWriter := io.MultiWriter(os.Stdout, file)
-	logger := log.New(multiWriter, "", 0)
+	// Write to both file and console
+	var output io.Writer = file
+	if console != nil {
+		output = io.MultiWriter(console, file)
+	}
+	logger := log.New(output, "", 0)
 
 	return &Logger{
 		level:    level,
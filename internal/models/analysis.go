@@ -0,0 +1,29 @@
+package models
+
+// FileAnalysis describes one file probed by an analyze-only batch
+type FileAnalysis struct {
+	Path       string   `json:"path"`
+	Name       string   `json:"name"`
+	FileType   FileType `json:"fileType"`
+	Container  string   `json:"container,omitempty"` // e.g. "matroska,webm"; the extension for non-media files
+	Size       int64    `json:"size"`
+	Duration   float64  `json:"duration,omitempty"` // Seconds
+	Width      int      `json:"width,omitempty"`
+	Height     int      `json:"height,omitempty"`
+	VideoCodec string   `json:"videoCodec,omitempty"`
+	AudioCodec string   `json:"audioCodec,omitempty"`
+	Bitrate    int64    `json:"bitrate,omitempty"` // Overall bits per second
+	FrameRate  float64  `json:"frameRate,omitempty"`
+	HDR        string   `json:"hdr,omitempty"`
+
+	// Problems found that may affect conversion, e.g. a missing video stream
+	Problems []string `json:"problems,omitempty"`
+}
+
+// AnalysisReport is the result of probing a batch of files without
+// converting them
+type AnalysisReport struct {
+	Files        []FileAnalysis `json:"files"`
+	TotalSize    int64          `json:"totalSize"`
+	ProblemCount int            `json:"problemCount"` // Files with at least one problem
+}
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// EncoderBenchmark is one encoder's result in an encoder benchmark
+type EncoderBenchmark struct {
+	Encoder  string  `json:"encoder"`  // FFmpeg encoder name, e.g. "h264_videotoolbox"
+	Codec    string  `json:"codec"`    // "h264" or "hevc"
+	Hardware bool    `json:"hardware"` // Runs on a GPU or media engine
+	FPS      float64 `json:"fps,omitempty"`
+	Speed    float64 `json:"speed,omitempty"` // Multiple of real time, e.g. 4 encodes a minute in 15 seconds
+	Size     int64   `json:"size,omitempty"`  // Bytes written for the sample
+	SSIM     float64 `json:"ssim,omitempty"`  // Similarity to the sample, 0-1 (1 = identical)
+
+	// Error is why the encoder couldn't encode the sample, e.g. missing hardware
+	Error string `json:"error,omitempty"`
+}
+
+// EncoderBenchmarkReport is the result of benchmarking the encoders
+// available on this machine
+type EncoderBenchmarkReport struct {
+	Results []EncoderBenchmark `json:"results"`
+
+	// Recommended maps each codec to the encoder picked as its default
+	Recommended map[string]string `json:"recommended"`
+	RanAt       time.Time         `json:"ranAt"`
+}
@@ -0,0 +1,11 @@
+package models
+
+import "gorm.io/gorm"
+
+// Bookmark is a security-scoped bookmark that restores access to a folder
+// the user selected, in sandboxed macOS builds
+type Bookmark struct {
+	gorm.Model
+	Path string `json:"path" gorm:"size:512;uniqueIndex;not null;serializer:encrypted"`
+	Data []byte `json:"-" gorm:"not null"`
+}
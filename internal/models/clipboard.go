@@ -0,0 +1,8 @@
+package models
+
+// ClipboardMedia is media copied to the clipboard that the user may want to
+// convert: local files of supported types and links to media files
+type ClipboardMedia struct {
+	Paths []string `json:"paths,omitempty"`
+	URLs  []string `json:"urls,omitempty"`
+}
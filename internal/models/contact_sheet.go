@@ -0,0 +1,15 @@
+package models
+
+// ContactSheetOptions lays out a contact sheet of a video. Zero values use
+// the defaults: 4 columns, 4 rows, 320 pixel wide frames and jpg output.
+type ContactSheetOptions struct {
+	Columns    int    `json:"columns,omitempty"`
+	Rows       int    `json:"rows,omitempty"`
+	Width      int    `json:"width,omitempty"`      // Width of each frame in pixels
+	Timestamps bool   `json:"timestamps,omitempty"` // Draw each frame's position on it
+	Format     string `json:"format,omitempty"`     // png, jpg, bmp, tiff or webp
+
+	// OutputPath is the image to write, or a folder to save it in; empty
+	// saves it next to the video
+	OutputPath string `json:"outputPath,omitempty"`
+}
@@ -1,6 +1,8 @@
 package models
 
 import (
+	"io"
+	"math"
 	"time"
 
 	"gorm.io/gorm"
@@ -15,41 +17,441 @@ const (
 	StatusCompleted  ConversionStatus = "completed"
 	StatusFailed     ConversionStatus = "failed"
 	StatusCancelled  ConversionStatus = "cancelled"
+	StatusSkipped    ConversionStatus = "skipped" // Left unconverted, e.g. by a pre-conversion script
+
+	// StatusStalled is a transient status reported through progress events when a
+	// running conversion stops making progress. It is never persisted.
+	StatusStalled ConversionStatus = "stalled"
+
+	// StatusCopying is a transient status reported through progress events
+	// while a cached job's input or output is copied. It is never persisted.
+	StatusCopying ConversionStatus = "copying"
+
+	// StatusDownloading is a transient status reported through progress
+	// events while an online-only input is downloaded by its cloud storage
+	// provider. It is never persisted.
+	StatusDownloading ConversionStatus = "downloading"
 )
 
 // Conversion represents a file conversion record in the database
 type Conversion struct {
 	gorm.Model
-	InputPath    string           `json:"inputPath" gorm:"not null"`
-	OutputPath   string           `json:"outputPath" gorm:"not null"`
-	InputFormat  string           `json:"inputFormat" gorm:"not null"`
-	OutputFormat string           `json:"outputFormat" gorm:"not null"`
-	FileType     FileType         `json:"fileType" gorm:"not null"`
-	FileSize     int64            `json:"fileSize"`
-	OutputSize   int64            `json:"outputSize"`
-	Status       ConversionStatus `json:"status" gorm:"not null;default:'pending'"`
-	ErrorMessage string           `json:"errorMessage,omitempty"`
-	Progress     float64          `json:"progress" gorm:"default:0"`
-	StartedAt    *time.Time       `json:"startedAt,omitempty"`
-	CompletedAt  *time.Time       `json:"completedAt,omitempty"`
+	BatchID       string           `json:"batchId,omitempty" gorm:"size:64;index"`
+	InputPath     string           `json:"inputPath" gorm:"not null;serializer:encrypted"`
+	OutputPath    string           `json:"outputPath" gorm:"not null;serializer:encrypted"`
+	InputFormat   string           `json:"inputFormat" gorm:"not null"`
+	OutputFormat  string           `json:"outputFormat" gorm:"not null"`
+	FileType      FileType         `json:"fileType" gorm:"not null"`
+	FileSize      int64            `json:"fileSize"`
+	OutputSize    int64            `json:"outputSize"`
+	EstimatedSize int64            `json:"estimatedSize,omitempty"` // Predicted output size, 0 if none was made
+	Status        ConversionStatus `json:"status" gorm:"not null;default:'pending'"`
+	ErrorMessage  string           `json:"errorMessage,omitempty" gorm:"serializer:encrypted"`
+	Progress      float64          `json:"progress" gorm:"default:0"`
+	StartedAt     *time.Time       `json:"startedAt,omitempty"`
+	CompletedAt   *time.Time       `json:"completedAt,omitempty"`
+
+	// Settings is a snapshot of the ConversionJob as it was run, encoded as
+	// JSON, so the conversion can be re-run or audited with the options used
+	Settings string `json:"settings,omitempty" gorm:"type:text;serializer:encrypted"`
+	Backend  string `json:"backend,omitempty"` // Tool that converted the file, e.g. "ffmpeg"
+
+	// ArchivedAt hides the record from history while keeping it in
+	// statistics. Deleted records (gorm's DeletedAt) are kept the same way
+	// until archived and deleted records are purged.
+	ArchivedAt *time.Time `json:"archivedAt,omitempty" gorm:"index"`
+
+	// Note is the user's annotation, e.g. "sent to client"
+	Note string `json:"note,omitempty" gorm:"serializer:encrypted"`
+
+	// Loudness of the input and output, stored when measured so the effect
+	// of a conversion, e.g. normalization, can be checked
+	InputLoudness  *Loudness `json:"inputLoudness,omitempty" gorm:"type:text;serializer:json"`
+	OutputLoudness *Loudness `json:"outputLoudness,omitempty" gorm:"type:text;serializer:json"`
+
+	// Steps records the steps of a pipeline job, empty for single conversions
+	Steps []PipelineStepResult `json:"steps,omitempty" gorm:"type:text;serializer:json"`
 }
 
+// ThrottleLevel describes how conversions are currently being held back
+type ThrottleLevel string
+
+const (
+	ThrottleNone    ThrottleLevel = "none"    // Full speed
+	ThrottleReduced ThrottleLevel = "reduced" // One job at a time, low OS priority
+	ThrottlePaused  ThrottleLevel = "paused"  // No new jobs are started
+)
+
+// DuplicatePolicy decides what happens to an output that is byte-identical
+// to a file already in its destination folder
+type DuplicatePolicy string
+
+const (
+	DuplicatesAllow    DuplicatePolicy = ""          // Don't check for duplicates
+	DuplicatesSkip     DuplicatePolicy = "skip"      // Discard the output and keep the existing file
+	DuplicatesReplace  DuplicatePolicy = "replace"   // Write the output and delete the existing file
+	DuplicatesKeepBoth DuplicatePolicy = "keep_both" // Write the output, renamed if the existing file has its name
+)
+
+// PreScriptSkipCode is the exit code with which a pre-conversion script skips a file
+const PreScriptSkipCode = 99
+
 // ConversionJob represents a conversion request from the frontend
 type ConversionJob struct {
 	InputPath       string `json:"inputPath"`
 	OutputPath      string `json:"outputPath"`
 	OutputFormat    string `json:"outputFormat"`
 	OverwriteOutput bool   `json:"overwriteOutput"`
+
+	// Streams selects which input streams to keep, by stream index.
+	// Empty keeps FFmpeg's default selection.
+	Streams []int `json:"streams,omitempty"`
+
+	// KeepAllAudio keeps every audio track, transcoding each where necessary,
+	// when the output container supports multiple tracks
+	KeepAllAudio bool `json:"keepAllAudio,omitempty"`
+
+	// Metadata overrides container tags in the output; nil keeps the source's tags
+	Metadata *OutputMetadata `json:"metadata,omitempty"`
+
+	// Deinterlace removes combing from interlaced sources; empty leaves the video as-is
+	Deinterlace DeinterlaceMode `json:"deinterlace,omitempty"`
+
+	// Speed multiplies playback speed, e.g. 4 for a timelapse or 0.5 for slow
+	// motion. 0 or 1 keeps the original speed.
+	Speed float64 `json:"speed,omitempty"`
+
+	// Reverse plays the video and audio backwards
+	Reverse bool `json:"reverse,omitempty"`
+
+	// Overlay composites a second video (e.g. a webcam recording) in a corner
+	Overlay *OverlayOptions `json:"overlay,omitempty"`
+
+	// BurnIn draws a running timecode and/or the source file name onto the
+	// video, e.g. for review copies; nil draws nothing
+	BurnIn *BurnInOptions `json:"burnIn,omitempty"`
+
+	// TrimStart and TrimEnd limit the conversion to part of the input, in
+	// seconds from its start. TrimEnd 0 converts to the end. Outputs copied
+	// without re-encoding are cut at the nearest keyframes.
+	TrimStart float64 `json:"trimStart,omitempty"`
+	TrimEnd   float64 `json:"trimEnd,omitempty"`
+
+	// Stabilize runs a two-pass stabilization for shaky footage.
+	// StabilizeStrength ranges from 1 (subtle) to 10 (aggressive); 0 uses the default.
+	Stabilize         bool `json:"stabilize,omitempty"`
+	StabilizeStrength int  `json:"stabilizeStrength,omitempty"`
+
+	// VideoCodec selects a professional codec instead of the output format's
+	// default; empty uses the default. The profile of the chosen codec applies.
+	VideoCodec    VideoCodec    `json:"videoCodec,omitempty"`
+	ProResProfile ProResProfile `json:"proResProfile,omitempty"`
+	DNxHRProfile  DNxHRProfile  `json:"dnxhrProfile,omitempty"`
+
+	// Quality encodes video at a constant quality rather than a bitrate.
+	// CRF sets the exact constant rate factor instead, from 1 (best) to 51
+	// on x264's scale, and is mapped to other encoders' scales. Bitrates
+	// from target sizes and social presets take precedence.
+	Quality VideoQuality `json:"quality,omitempty"`
+	CRF     int          `json:"crf,omitempty"`
+
+	// PreserveAlpha keeps the source's alpha channel, encoding webm as VP9
+	// yuva420p and mov as ProRes 4444. Other output formats are rejected.
+	PreserveAlpha bool `json:"preserveAlpha,omitempty"`
+
+	// Salvage recovers what it can from a damaged source by decoding past
+	// corrupt data. It always re-encodes, and the output may skip or freeze
+	// where the source is broken.
+	Salvage bool `json:"salvage,omitempty"`
+
+	// TargetSizeMB fits the output under a size in megabytes (1,000,000
+	// bytes), e.g. an attachment limit, by deriving the bitrate from the
+	// duration. 0 keeps the encoder's default quality. TwoPass hits the size
+	// more closely at the cost of a second encode.
+	TargetSizeMB float64 `json:"targetSizeMB,omitempty"`
+	TwoPass      bool    `json:"twoPass,omitempty"`
+
+	// SocialPreset fits the video to a platform's upload specs, by
+	// SocialPreset ID; empty converts without a preset
+	SocialPreset string `json:"socialPreset,omitempty"`
+
+	// SubtitleCharset is the charset non-Unicode subtitle files are read as,
+	// e.g. "windows-1252". Empty assumes windows-1250. Output is always UTF-8.
+	SubtitleCharset string `json:"subtitleCharset,omitempty"`
+
+	// Image resize options (0 keeps the original dimension)
+	MaxWidth  int         `json:"maxWidth,omitempty"`
+	MaxHeight int         `json:"maxHeight,omitempty"`
+	Scaler    ImageScaler `json:"scaler,omitempty"`
+
+	// ColorProfile decides what happens to an image's embedded ICC profile
+	ColorProfile ColorProfileMode `json:"colorProfile,omitempty"`
+
+	// Video downscale limits, keeping the aspect ratio. Smaller videos
+	// aren't upscaled. (0 keeps the original dimension)
+	VideoMaxWidth  int `json:"videoMaxWidth,omitempty"`
+	VideoMaxHeight int `json:"videoMaxHeight,omitempty"`
+
+	// EstimatedSize is the output size predicted by EstimateOutputSize,
+	// recorded in history for comparison with the actual size
+	EstimatedSize int64 `json:"estimatedSize,omitempty"`
+
+	// DuplicateOutputs hash-compares the output against the files in its
+	// destination folder before it is written there
+	DuplicateOutputs DuplicatePolicy `json:"duplicateOutputs,omitempty"`
+
+	// PreScript is run before converting, with the input and output paths as
+	// arguments. Exiting with PreScriptSkipCode skips the file, any other
+	// failure fails the job.
+	PreScript string `json:"preScript,omitempty"`
+
+	// Pipeline runs the job as a chain of steps, e.g. trim, stabilize and
+	// convert, each step converting the previous step's output. The job's
+	// own conversion options are ignored; its paths, format and resource
+	// limits apply to the pipeline as a whole.
+	Pipeline []PipelineStep `json:"pipeline,omitempty"`
+
+	// PreviewLength limits the conversion to a slice of this length, taken
+	// from the middle of the input when it is long enough. Set by previews;
+	// 0 converts the whole input.
+	PreviewLength time.Duration `json:"-"`
+
+	// Log receives the converter's tool output for the conversion log; nil
+	// discards it
+	Log io.Writer `json:"-"`
+
+	// Resource limits for the conversion process
+	Threads     int  `json:"threads,omitempty"`     // Maximum encoder threads (0 = automatic)
+	LowPriority bool `json:"lowPriority,omitempty"` // Run at low OS priority
+
+	// IOLimitMBps limits how fast the input is read and the output written,
+	// in megabytes per second, e.g. for network shares (0 = unlimited)
+	IOLimitMBps float64 `json:"ioLimitMBps,omitempty"`
+
+	// CacheInput converts from a copy of the input in the scratch directory
+	// and copies the output back when done, so a slow or unreliable network
+	// share can't interrupt a long encode
+	CacheInput bool `json:"cacheInput,omitempty"`
+}
+
+// IOLimit returns the job's I/O rate limit in bytes per second, 0 if unlimited
+func (j ConversionJob) IOLimit() int64 {
+	return int64(j.IOLimitMBps * 1_000_000)
+}
+
+// OutputMetadata holds container tags written to the output file.
+// Empty fields leave the corresponding source tag untouched.
+type OutputMetadata struct {
+	Title   string `json:"title,omitempty"`
+	Artist  string `json:"artist,omitempty"`
+	Comment string `json:"comment,omitempty"`
+}
+
+// Tags returns the non-empty fields keyed by FFmpeg metadata tag name
+func (m *OutputMetadata) Tags() map[string]string {
+	tags := make(map[string]string)
+	if m == nil {
+		return tags
+	}
+	if m.Title != "" {
+		tags["title"] = m.Title
+	}
+	if m.Artist != "" {
+		tags["artist"] = m.Artist
+	}
+	if m.Comment != "" {
+		tags["comment"] = m.Comment
+	}
+	return tags
+}
+
+// OverlayOptions places a picture-in-picture video over the main video
+type OverlayOptions struct {
+	Path   string  `json:"path"`
+	Corner string  `json:"corner,omitempty"` // top-left, top-right, bottom-left or bottom-right (default)
+	Scale  float64 `json:"scale,omitempty"`  // Width relative to the main video, e.g. 0.25 (default)
+	Margin *int    `json:"margin,omitempty"` // Pixels from the frame edge (default 16)
+}
+
+// BurnInOptions selects the text burned into the video. The timecode is
+// drawn at the bottom and the file name at the top.
+type BurnInOptions struct {
+	Timecode bool `json:"timecode,omitempty"` // Running source timecode, HH:MM:SS:FF
+	Filename bool `json:"filename,omitempty"` // Source file name
 }
 
+// DeinterlaceMode selects how interlaced video is handled
+type DeinterlaceMode string
+
+const (
+	DeinterlaceAuto  DeinterlaceMode = "auto"  // Deinterlace with yadif only when the source is detected as interlaced
+	DeinterlaceYadif DeinterlaceMode = "yadif" // Always deinterlace with yadif
+	DeinterlaceBwdif DeinterlaceMode = "bwdif" // Always deinterlace with bwdif, sharper motion at a higher cost
+)
+
+// VideoQuality selects a constant quality level for video encodes instead
+// of a bitrate
+type VideoQuality string
+
+const (
+	VideoQualityDefault VideoQuality = ""       // The encoder's default
+	VideoQualityHigh    VideoQuality = "high"   // Visually transparent, large files
+	VideoQualityMedium  VideoQuality = "medium" // Good quality at a reasonable size
+	VideoQualityLow     VideoQuality = "low"    // Small files with visible artifacts
+)
+
+// VideoCodec selects the video codec used instead of an output format's default
+type VideoCodec string
+
+const (
+	CodecDefault VideoCodec = ""       // The output format's default codec
+	CodecProRes  VideoCodec = "prores" // Apple ProRes, .mov output only
+	CodecDNxHR   VideoCodec = "dnxhr"  // Avid DNxHR, .mov or .mkv output
+)
+
+// ProResProfile selects the Apple ProRes flavour, from smallest to highest quality
+type ProResProfile string
+
+const (
+	ProResProxy    ProResProfile = "proxy"
+	ProResLT       ProResProfile = "lt"
+	ProResStandard ProResProfile = "standard"
+	ProResHQ       ProResProfile = "hq"
+	ProRes4444     ProResProfile = "4444"
+	ProRes4444XQ   ProResProfile = "4444xq"
+)
+
+// DNxHRProfile selects the Avid DNxHR flavour, from smallest to highest quality
+type DNxHRProfile string
+
+const (
+	DNxHRLB  DNxHRProfile = "lb"  // Low bandwidth, 8-bit 4:2:2 (offline editing)
+	DNxHRSQ  DNxHRProfile = "sq"  // Standard quality, 8-bit 4:2:2
+	DNxHRHQ  DNxHRProfile = "hq"  // High quality, 8-bit 4:2:2
+	DNxHRHQX DNxHRProfile = "hqx" // High quality, 10-bit 4:2:2
+	DNxHR444 DNxHRProfile = "444" // Finishing quality, 10-bit 4:4:4
+)
+
+// ImageScaler selects the algorithm used to resize images. Every scaler runs
+// in pure Go on the CPU; there is no GPU or SIMD path, so the choice trades
+// sharpness for CPU time (see BenchmarkScaleImage).
+type ImageScaler string
+
+const (
+	ScalerQuality  ImageScaler = "quality"  // Catmull-Rom, sharpest and slowest
+	ScalerBalanced ImageScaler = "balanced" // Bilinear
+	ScalerFast     ImageScaler = "fast"     // Approximate bilinear, fastest for bulk resizing
+)
+
+// ConversionMethod describes how a conversion produced its output
+type ConversionMethod string
+
+const (
+	MethodRemux    ConversionMethod = "remux"    // Streams copied into the new container, no quality loss
+	MethodReencode ConversionMethod = "reencode" // Streams decoded and encoded again
+
+	// MethodVideoCopy copies the video stream and re-encodes only the audio,
+	// e.g. AC-3 camcorder audio to AAC for broad mp4 playback
+	MethodVideoCopy ConversionMethod = "video-copy"
+)
+
+// ChapterStatus describes what happened to the source's chapter markers
+type ChapterStatus string
+
+const (
+	ChaptersPreserved ChapterStatus = "preserved" // Chapters were copied into the output
+	ChaptersDropped   ChapterStatus = "dropped"   // The output format or conversion lost the chapters
+)
+
+// ColorProfileMode decides how an image's embedded ICC color profile, e.g.
+// Display P3 or Adobe RGB, is handled
+type ColorProfileMode string
+
+const (
+	// ColorProfileKeep embeds the profile in the output, or converts the
+	// colors to sRGB when the output format can't carry a profile
+	ColorProfileKeep ColorProfileMode = ""
+	// ColorProfileSRGB converts the colors to sRGB, which every viewer
+	// assumes for images without a profile
+	ColorProfileSRGB ColorProfileMode = "srgb"
+)
+
+// ColorProfileStatus describes what happened to an image's color profile
+type ColorProfileStatus string
+
+const (
+	ColorProfileEmbedded  ColorProfileStatus = "embedded"  // The profile was embedded in the output
+	ColorProfileConverted ColorProfileStatus = "converted" // The colors were converted to sRGB
+	ColorProfileDropped   ColorProfileStatus = "dropped"   // The profile was lost, so colors may shift
+)
+
 // ConversionResult represents the result of a conversion
 type ConversionResult struct {
-	Success      bool   `json:"success"`
-	InputPath    string `json:"inputPath"`
-	OutputPath   string `json:"outputPath"`
-	OutputSize   int64  `json:"outputSize"`
-	ErrorMessage string `json:"errorMessage,omitempty"`
-	Duration     int64  `json:"duration"` // Duration in milliseconds
+	Success      bool               `json:"success"`
+	InputPath    string             `json:"inputPath"`
+	OutputPath   string             `json:"outputPath"`
+	OutputSize   int64              `json:"outputSize"`
+	ErrorMessage string             `json:"errorMessage,omitempty"`
+	Duration     int64              `json:"duration"` // Duration in milliseconds
+	Method       ConversionMethod   `json:"method,omitempty"`
+	Chapters     ChapterStatus      `json:"chapters,omitempty"`     // Empty when the source had no chapters
+	ColorProfile ColorProfileStatus `json:"colorProfile,omitempty"` // Empty when the image had no color profile
+	Warnings     []string           `json:"warnings,omitempty"`     // Problems that didn't stop the conversion
+	Skipped      bool               `json:"skipped,omitempty"`      // Left unconverted on purpose, not a failure
+
+	// Steps of a pipeline job, in the order they ran
+	Steps []PipelineStepResult `json:"steps,omitempty"`
+
+	// Before/after comparison. Bitrates are in bits per second and
+	// resolutions like "1920x1080"; empty or 0 when unknown.
+	InputSize        int64   `json:"inputSize"`
+	InputBitrate     int64   `json:"inputBitrate,omitempty"`
+	OutputBitrate    int64   `json:"outputBitrate,omitempty"`
+	InputResolution  string  `json:"inputResolution,omitempty"`
+	OutputResolution string  `json:"outputResolution,omitempty"`
+	SavingsPercent   float64 `json:"savingsPercent"` // Size reduction, negative when the output grew
+}
+
+// SetSizes records the input size and the resulting savings percentage
+func (r *ConversionResult) SetSizes(inputSize int64) {
+	r.InputSize = inputSize
+	if inputSize > 0 && r.OutputSize > 0 {
+		r.SavingsPercent = math.Round(float64(inputSize-r.OutputSize)/float64(inputSize)*1000) / 10
+	}
+}
+
+// QueueJob summarizes a conversion in the queue
+type QueueJob struct {
+	ID           uint             `json:"id"`
+	BatchID      string           `json:"batchId,omitempty"`
+	InputPath    string           `json:"inputPath"`
+	OutputPath   string           `json:"outputPath"`
+	OutputFormat string           `json:"outputFormat"`
+	FileType     FileType         `json:"fileType"`
+	Status       ConversionStatus `json:"status"`
+	Progress     float64          `json:"progress"` // 0-100
+	ErrorMessage string           `json:"errorMessage,omitempty"`
+}
+
+// QueueState is a snapshot of the conversion queue since the app started.
+// Queued jobs are listed under Paused while the throttle is paused, and
+// cancelled jobs are listed with the failed ones.
+type QueueState struct {
+	Throttle  ThrottleLevel `json:"throttle"`
+	Queued    []QueueJob    `json:"queued"`
+	Running   []QueueJob    `json:"running"`
+	Paused    []QueueJob    `json:"paused"`
+	Completed []QueueJob    `json:"completed"` // Most recent first, capped
+	Failed    []QueueJob    `json:"failed"`    // Most recent first, capped
+}
+
+// SizeEstimate is a predicted output size for a file
+type SizeEstimate struct {
+	InputPath     string           `json:"inputPath"`
+	InputSize     int64            `json:"inputSize"`
+	EstimatedSize int64            `json:"estimatedSize"`
+	SampleSeconds float64          `json:"sampleSeconds"`    // Length of the trial encode the estimate was scaled from
+	Method        ConversionMethod `json:"method,omitempty"` // How the sample was converted
 }
 
 // ConversionProgress represents the progress of an ongoing conversion
@@ -58,8 +460,25 @@ type ConversionProgress struct {
 	InputPath string  `json:"inputPath"`
 	Progress  float64 `json:"progress"` // 0-100
 	Status    string  `json:"status"`
+
+	// Result is set on the final event for a file in a batch, so results can be
+	// streamed to the frontend as they complete
+	Result *ConversionResult `json:"result,omitempty"`
 }
 
+// OverwritePolicy decides what happens when a batch's output file already
+// exists
+type OverwritePolicy string
+
+const (
+	// OverwriteDefault replaces the existing file, as batches did before the
+	// policy was introduced, when MakeCopies decided it implicitly
+	OverwriteDefault OverwritePolicy = ""
+	OverwriteNever   OverwritePolicy = "never"   // Fail the file and keep the existing one
+	OverwriteReplace OverwritePolicy = "replace" // Replace the existing file
+	OverwriteRename  OverwritePolicy = "rename"  // Write to a free name next to it, e.g. "clip (2).mp4"
+)
+
 // BatchConversionRequest represents a request to convert multiple files
 type BatchConversionRequest struct {
 	Files           []string       `json:"files"`
@@ -67,7 +486,163 @@ type BatchConversionRequest struct {
 	OutputDirectory string         `json:"outputDirectory"`
 	NamingMode      FileNamingMode `json:"namingMode"`
 	CustomNames     []string       `json:"customNames,omitempty"`
-	MakeCopies      bool           `json:"makeCopies"`
+	NameTemplate    string         `json:"nameTemplate,omitempty"` // Used with NamingModeTemplate, e.g. "{name}-{n:3}"
+	MakeCopies      bool           `json:"makeCopies"`             // Deprecated: no longer affects conversion; use Overwrite
+
+	// Overwrite decides what happens when an output file already exists.
+	// FileSettings can override it per file.
+	Overwrite OverwritePolicy `json:"overwrite,omitempty"`
+
+	// EstimatedSizes holds the predicted output size of each file, parallel
+	// to Files, for comparison against the actual sizes in history
+	EstimatedSizes []int64 `json:"estimatedSizes,omitempty"`
+
+	// StreamSelections holds the stream indexes to keep for each file,
+	// parallel to Files. Missing or empty entries keep the default streams.
+	StreamSelections [][]int `json:"streamSelections,omitempty"`
+
+	// KeepAllAudioTracks keeps every audio track where the output container supports it
+	KeepAllAudioTracks bool `json:"keepAllAudioTracks,omitempty"`
+
+	// Professional video codec and profiles for every video file; empty
+	// values fall back to the advanced settings
+	VideoCodec    VideoCodec    `json:"videoCodec,omitempty"`
+	ProResProfile ProResProfile `json:"proResProfile,omitempty"`
+	DNxHRProfile  DNxHRProfile  `json:"dnxhrProfile,omitempty"`
+
+	// Deinterlacing applied to every video file
+	Deinterlace DeinterlaceMode `json:"deinterlace,omitempty"`
+
+	// Playback speed multiplier applied to every video file
+	Speed float64 `json:"speed,omitempty"`
+
+	// Reverse plays every video file backwards
+	Reverse bool `json:"reverse,omitempty"`
+
+	// Video stabilization applied to every video file
+	Stabilize         bool `json:"stabilize,omitempty"`
+	StabilizeStrength int  `json:"stabilizeStrength,omitempty"`
+
+	// Constant quality level, or exact CRF, for every video file
+	Quality VideoQuality `json:"quality,omitempty"`
+	CRF     int          `json:"crf,omitempty"`
+
+	// PreserveAlpha keeps transparency in every video file (webm and mov output only)
+	PreserveAlpha bool `json:"preserveAlpha,omitempty"`
+
+	// Salvage recovers what it can from damaged video files
+	Salvage bool `json:"salvage,omitempty"`
+
+	// Size limit in megabytes and two-pass encoding for every video file
+	TargetSizeMB float64 `json:"targetSizeMB,omitempty"`
+	TwoPass      bool    `json:"twoPass,omitempty"`
+
+	// Timecode and file name burn-in applied to every video file
+	BurnIn *BurnInOptions `json:"burnIn,omitempty"`
+
+	// Social platform preset applied to every video file
+	SocialPreset string `json:"socialPreset,omitempty"`
+
+	// Charset non-Unicode subtitle files are read as (empty assumes windows-1250)
+	SubtitleCharset string `json:"subtitleCharset,omitempty"`
+
+	// Image resize options applied to every file (0 keeps the original dimension)
+	MaxWidth  int         `json:"maxWidth,omitempty"`
+	MaxHeight int         `json:"maxHeight,omitempty"`
+	Scaler    ImageScaler `json:"scaler,omitempty"` // Empty uses the image scaler setting
+
+	// What to do with the ICC profiles of images
+	ColorProfile ColorProfileMode `json:"colorProfile,omitempty"`
+
+	// What to do with outputs identical to files already in the destination
+	DuplicateOutputs DuplicatePolicy `json:"duplicateOutputs,omitempty"`
+
+	// Read/write rate limit in megabytes per second for every file (0 = unlimited)
+	IOLimitMBps float64 `json:"ioLimitMBps,omitempty"`
+
+	// CacheInput converts every file from a local copy; see ConversionJob.CacheInput
+	CacheInput bool `json:"cacheInput,omitempty"`
+
+	// Script run before converting each file; see ConversionJob.PreScript
+	PreScript string `json:"preScript,omitempty"`
+
+	// Actions run after the last file, e.g. shutting down after an overnight batch
+	PostActions *PostActions `json:"postActions,omitempty"`
+
+	// Rules adjust the settings of each file from its analysis, e.g. to
+	// downscale only the files larger than 1080p
+	Rules []ConversionRule `json:"rules,omitempty"`
+
+	// FileSettings overrides settings per file, indexed like Files, e.g. for
+	// a batch imported from a manifest. They take precedence over Rules.
+	FileSettings []FileSettings `json:"fileSettings,omitempty"`
+}
+
+// FileSettings overrides a batch's settings for one of its files. Empty
+// fields keep the batch's settings.
+type FileSettings struct {
+	RuleSettings
+	OutputDirectory string `json:"outputDirectory,omitempty"`
+	OutputName      string `json:"outputName,omitempty"` // Without extension
+
+	Overwrite OverwritePolicy `json:"overwrite,omitempty"`
+}
+
+// SplitRequest represents a request to cut a video into fixed-length segments
+type SplitRequest struct {
+	InputPath       string `json:"inputPath"`
+	OutputFormat    string `json:"outputFormat"`
+	OutputDirectory string `json:"outputDirectory"` // Empty writes next to the input
+	SegmentMinutes  int    `json:"segmentMinutes"`
+	OverwriteOutput bool   `json:"overwriteOutput"`
+}
+
+// StreamingFormat is a manifest format for adaptive streaming
+type StreamingFormat string
+
+const (
+	StreamingHLS  StreamingFormat = "hls"
+	StreamingDASH StreamingFormat = "dash"
+)
+
+// Rendition is one quality level of a streaming package
+type Rendition struct {
+	Height       int `json:"height"`                 // 0 keeps the input's
+	VideoBitrate int `json:"videoBitrate,omitempty"` // kbit/s; 0 picks one for the height
+	AudioBitrate int `json:"audioBitrate,omitempty"` // kbit/s; 0 uses 128
+}
+
+// StreamingRequest represents a request to package a video for self-hosted
+// adaptive streaming. The package is written to a "<name>_stream" folder.
+type StreamingRequest struct {
+	InputPath       string            `json:"inputPath"`
+	OutputDirectory string            `json:"outputDirectory"` // Empty writes next to the input
+	Formats         []StreamingFormat `json:"formats"`         // Empty writes HLS only
+	SegmentSeconds  int               `json:"segmentSeconds"`  // 0 uses 6
+	Renditions      []Rendition       `json:"renditions"`      // Empty uses a ladder up to the input's height
+	OverwriteOutput bool              `json:"overwriteOutput"`
+}
+
+// ImageSequenceRequest represents a request to encode numbered image files,
+// e.g. an overlay rendered to PNGs, as a video. The frames are found from
+// the first one, counting up until a number is missing. With PreserveAlpha
+// their transparency is kept in webm (VP9) or mov (ProRes 4444) output.
+type ImageSequenceRequest struct {
+	FirstFrame      string  `json:"firstFrame"` // e.g. "renders/frame_0001.png"
+	FrameRate       float64 `json:"frameRate"`  // Frames per second; 0 uses 30
+	OutputFormat    string  `json:"outputFormat"`
+	OutputDirectory string  `json:"outputDirectory"` // Empty writes next to the frames
+	PreserveAlpha   bool    `json:"preserveAlpha"`
+	OverwriteOutput bool    `json:"overwriteOutput"`
+}
+
+// ImageSequence is a run of numbered image files found on disk
+type ImageSequence struct {
+	Pattern     string  `json:"pattern"` // printf pattern naming the frames, e.g. "frame_%04d.png"
+	StartNumber int     `json:"startNumber"`
+	Frames      int     `json:"frames"`
+	Size        int64   `json:"size"` // Total size of the frames in bytes
+	FrameRate   float64 `json:"frameRate"`
 }
 
 // FileNamingMode defines how output files should be named
@@ -76,13 +651,109 @@ type FileNamingMode string
 const (
 	NamingModeOriginal FileNamingMode = "original" // Keep original filename
 	NamingModeCustom   FileNamingMode = "custom"   // Use custom names
+	NamingModeTemplate FileNamingMode = "template" // Build names from a naming template
 )
 
 // BatchConversionResult represents the result of a batch conversion
+// Only the first page of results is included; the rest can be fetched by BatchID.
 type BatchConversionResult struct {
-	TotalFiles    int                `json:"totalFiles"`
-	SuccessCount  int                `json:"successCount"`
-	FailCount     int                `json:"failCount"`
-	Results       []ConversionResult `json:"results"`
-	TotalDuration int64              `json:"totalDuration"` // Total duration in milliseconds
+	BatchID        string             `json:"batchId"`
+	TotalFiles     int                `json:"totalFiles"`
+	SuccessCount   int                `json:"successCount"`
+	FailCount      int                `json:"failCount"`
+	SkippedCount   int                `json:"skippedCount"`
+	Results        []ConversionResult `json:"results"`
+	HasMoreResults bool               `json:"hasMoreResults"`
+	TotalDuration  int64              `json:"totalDuration"`      // Total duration in milliseconds
+	Warnings       []string           `json:"warnings,omitempty"` // Post-conversion actions that failed
+
+	// PowerActionAt is when the batch's sleep or shutdown runs unless it is
+	// cancelled with CancelPowerAction; nil when none is pending
+	PowerActionAt *time.Time `json:"powerActionAt,omitempty"`
+}
+
+// BatchProgressSummary reports the progress of a batch as counts, sent
+// periodically for large batches instead of each file's result, and once
+// as a digest when the batch finishes
+type BatchProgressSummary struct {
+	BatchID   string `json:"batchId,omitempty"` // Set on the final digest
+	Total     int    `json:"total"`
+	Done      int    `json:"done"` // Files finished, whatever their outcome
+	Succeeded int    `json:"succeeded"`
+	Failed    int    `json:"failed"`
+	Skipped   int    `json:"skipped"`
+	Final     bool   `json:"final"` // The batch has finished
+}
+
+// HistoryFilter narrows a history query. Zero fields don't filter.
+type HistoryFilter struct {
+	Statuses     []ConversionStatus `json:"statuses,omitempty"`
+	FileType     FileType           `json:"fileType,omitempty"`
+	OutputFormat string             `json:"outputFormat,omitempty"` // With or without the leading dot
+	From         *time.Time         `json:"from,omitempty"`         // Created at or after
+	To           *time.Time         `json:"to,omitempty"`           // Created before
+	Search       string             `json:"search,omitempty"`       // Matches input and output paths and notes
+	Limit        int                `json:"limit,omitempty"`        // 0 returns all matches
+
+	IncludeArchived bool `json:"includeArchived,omitempty"`
+}
+
+// DuplicateConversion reports an input that was already converted
+// successfully with the same settings
+type DuplicateConversion struct {
+	InputPath    string    `json:"inputPath"`
+	ConversionID uint      `json:"conversionId"` // The earlier conversion
+	OutputPath   string    `json:"outputPath"`
+	ConvertedAt  time.Time `json:"convertedAt"`
+	OutputExists bool      `json:"outputExists"` // Whether the earlier output is still on disk
+}
+
+// BatchSummary summarizes one entry of grouped history: a batch of
+// conversions, or a single conversion made outside a batch
+type BatchSummary struct {
+	BatchID        string    `json:"batchId,omitempty"` // Empty for a single conversion
+	ConversionID   uint      `json:"conversionId"`      // First conversion of the entry
+	FileCount      int       `json:"fileCount"`
+	CompletedCount int       `json:"completedCount"`
+	FailedCount    int       `json:"failedCount"`
+	CancelledCount int       `json:"cancelledCount"`
+	SkippedCount   int       `json:"skippedCount"`
+	InputSize      int64     `json:"inputSize"`  // Total bytes of the input files
+	OutputSize     int64     `json:"outputSize"` // Total bytes written
+	CreatedAt      time.Time `json:"createdAt"`  // When the entry was started
+}
+
+// SavingsPeriod is the length of the periods space savings are summed over
+type SavingsPeriod string
+
+const (
+	SavingsWeekly  SavingsPeriod = "week"  // Weeks starting on Monday
+	SavingsMonthly SavingsPeriod = "month" // Calendar months
+)
+
+// SpaceSavings sums the completed conversions of one week or month
+type SpaceSavings struct {
+	PeriodStart string `json:"periodStart"` // First day of the period, YYYY-MM-DD in local time
+	FileCount   int    `json:"fileCount"`
+	InputSize   int64  `json:"inputSize"`
+	OutputSize  int64  `json:"outputSize"`
+	SavedBytes  int64  `json:"savedBytes"` // Negative when outputs were larger than their inputs
+
+	// CumulativeSaved is the bytes saved in this and all earlier periods
+	CumulativeSaved int64 `json:"cumulativeSaved"`
+}
+
+// ToResult converts a stored conversion record into a ConversionResult
+func (c *Conversion) ToResult() ConversionResult {
+	result := ConversionResult{
+		Success:      c.Status == StatusCompleted,
+		InputPath:    c.InputPath,
+		OutputPath:   c.OutputPath,
+		OutputSize:   c.OutputSize,
+		ErrorMessage: c.ErrorMessage,
+	}
+	if c.StartedAt != nil && c.CompletedAt != nil {
+		result.Duration = c.CompletedAt.Sub(*c.StartedAt).Milliseconds()
+	}
+	return result
 }
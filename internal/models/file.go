@@ -1,12 +1,16 @@
 package models
 
-// FileType represents the type of file (video or image)
+// FileType represents the type of file (video, image, audio, subtitle, e-book or document)
 type FileType string
 
 const (
-	FileTypeVideo   FileType = "video"
-	FileTypeImage   FileType = "image"
-	FileTypeUnknown FileType = "unknown"
+	FileTypeVideo    FileType = "video"
+	FileTypeImage    FileType = "image"
+	FileTypeAudio    FileType = "audio"
+	FileTypeSubtitle FileType = "subtitle"
+	FileTypeEbook    FileType = "ebook"
+	FileTypeDocument FileType = "document"
+	FileTypeUnknown  FileType = "unknown"
 )
 
 // FileInfo contains information about a file
@@ -16,6 +20,10 @@ type FileInfo struct {
 	Extension string   `json:"extension"`
 	Size      int64    `json:"size"`
 	Type      FileType `json:"type"`
+
+	// Placeholder is set for online-only cloud files whose contents must
+	// be downloaded before they can be converted
+	Placeholder bool `json:"placeholder,omitempty"`
 }
 
 // VideoFormats lists supported video formats
@@ -41,6 +49,7 @@ var VideoFormats = map[string]bool{
 	".asf":  true,
 	".divx": true,
 	".f4v":  true,
+	".mxf":  true,
 }
 
 // ImageFormats lists supported image formats
@@ -57,6 +66,61 @@ var ImageFormats = map[string]bool{
 	".svg":  true,
 }
 
+// AudioFormats lists supported audio formats
+var AudioFormats = map[string]bool{
+	".mp3":  true,
+	".wav":  true,
+	".flac": true,
+	".aac":  true,
+	".m4a":  true,
+	".ogg":  true,
+	".oga":  true,
+	".opus": true,
+	".wma":  true,
+	".aiff": true,
+	".aif":  true,
+}
+
+// SubtitleFormats lists supported subtitle formats
+var SubtitleFormats = map[string]bool{
+	".srt": true,
+	".vtt": true,
+	".ass": true,
+	".ssa": true,
+	".sub": true,
+}
+
+// EbookFormats lists supported e-book formats
+var EbookFormats = map[string]bool{
+	".epub": true,
+	".mobi": true,
+	".azw3": true,
+	".azw":  true,
+	".fb2":  true,
+}
+
+// OfficeDocumentFormats lists supported office document formats
+var OfficeDocumentFormats = map[string]bool{
+	".doc":  true,
+	".docx": true,
+	".odt":  true,
+	".rtf":  true,
+	".xls":  true,
+	".xlsx": true,
+	".ods":  true,
+	".ppt":  true,
+	".pptx": true,
+	".odp":  true,
+}
+
+// MarkupFormats lists supported Markdown and HTML formats
+var MarkupFormats = map[string]bool{
+	".md":       true,
+	".markdown": true,
+	".html":     true,
+	".htm":      true,
+}
+
 // VideoOutputFormats lists available output formats for videos
 var VideoOutputFormats = []string{
 	"mp4",
@@ -78,6 +142,38 @@ var ImageOutputFormats = []string{
 	"tiff",
 }
 
+// AudioOutputFormats lists available output formats for audio
+var AudioOutputFormats = []string{
+	"mp3",
+	"wav",
+	"flac",
+	"aac",
+	"m4a",
+	"ogg",
+	"opus",
+}
+
+// SubtitleOutputFormats lists available output formats for subtitles
+var SubtitleOutputFormats = []string{
+	"srt",
+	"vtt",
+	"ass",
+	"sub",
+}
+
+// EbookOutputFormats lists available output formats for e-books
+var EbookOutputFormats = []string{
+	"epub",
+	"mobi",
+	"azw3",
+	"pdf",
+}
+
+// DocumentOutputFormats lists available output formats for documents
+var DocumentOutputFormats = []string{
+	"pdf",
+}
+
 // GetFileType returns the type of file based on extension
 func GetFileType(extension string) FileType {
 	if VideoFormats[extension] {
@@ -86,6 +182,18 @@ func GetFileType(extension string) FileType {
 	if ImageFormats[extension] {
 		return FileTypeImage
 	}
+	if AudioFormats[extension] {
+		return FileTypeAudio
+	}
+	if SubtitleFormats[extension] {
+		return FileTypeSubtitle
+	}
+	if EbookFormats[extension] {
+		return FileTypeEbook
+	}
+	if OfficeDocumentFormats[extension] || MarkupFormats[extension] {
+		return FileTypeDocument
+	}
 	return FileTypeUnknown
 }
 
@@ -96,6 +204,14 @@ func GetOutputFormats(fileType FileType) []string {
 		return VideoOutputFormats
 	case FileTypeImage:
 		return ImageOutputFormats
+	case FileTypeAudio:
+		return AudioOutputFormats
+	case FileTypeSubtitle:
+		return SubtitleOutputFormats
+	case FileTypeEbook:
+		return EbookOutputFormats
+	case FileTypeDocument:
+		return DocumentOutputFormats
 	default:
 		return []string{}
 	}
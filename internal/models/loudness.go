@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// Loudness is an EBU R128 loudness measurement of a file's audio, as
+// podcast and broadcast loudness specs are written against
+type Loudness struct {
+	IntegratedLUFS float64   `json:"integratedLufs"` // Integrated loudness, e.g. -16 for podcasts
+	TruePeakDBTP   float64   `json:"truePeakDbtp"`   // True peak, usually required to stay below -1
+	RangeLU        float64   `json:"rangeLu"`        // Loudness range
+	MeasuredAt     time.Time `json:"measuredAt"`
+}
@@ -0,0 +1,29 @@
+package models
+
+// PipelineStep is one operation of a multi-step job. Each step converts
+// the output of the step before it, the first step the job's input.
+type PipelineStep struct {
+	// Name describes the step in history, e.g. "Trim" or "GIF preview"
+	Name string `json:"name"`
+
+	// Options are the step's conversion options. The pipeline sets the
+	// paths; an empty OutputFormat keeps the format of the step's input.
+	// The last step that isn't a branch writes the job's output, in the
+	// job's format.
+	Options ConversionJob `json:"options"`
+
+	// Branch writes the step's output next to the job's output, named after
+	// the step, as an extra file. The following step converts the same
+	// input the branch did.
+	Branch bool `json:"branch,omitempty"`
+}
+
+// PipelineStepResult records how a pipeline step went
+type PipelineStepResult struct {
+	Name         string           `json:"name"`
+	OutputPath   string           `json:"outputPath,omitempty"` // Empty for intermediate steps, whose output is discarded
+	Success      bool             `json:"success"`
+	ErrorMessage string           `json:"errorMessage,omitempty"`
+	Duration     int64            `json:"duration"` // Duration in milliseconds
+	Method       ConversionMethod `json:"method,omitempty"`
+}
@@ -0,0 +1,18 @@
+package models
+
+// PowerAction puts the computer to sleep or shuts it down after a batch
+type PowerAction string
+
+const (
+	PowerActionNone     PowerAction = ""
+	PowerActionSleep    PowerAction = "sleep"
+	PowerActionShutdown PowerAction = "shutdown"
+)
+
+// PostActions are run once every file of a batch has been converted
+type PostActions struct {
+	OpenFolder     bool        `json:"openFolder,omitempty"`     // Open the folders the outputs were written to
+	TrashOriginals bool        `json:"trashOriginals,omitempty"` // Move successfully converted inputs to the trash
+	Script         string      `json:"script,omitempty"`         // Executable run once per output, with the output path as argument
+	Power          PowerAction `json:"power,omitempty"`          // Runs last, a minute later; skipped when no file was converted
+}
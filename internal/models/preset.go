@@ -0,0 +1,49 @@
+package models
+
+// PresetFileExtension is the extension of exported preset files
+const PresetFileExtension = ".zenpreset"
+
+// PresetFileVersion is the version of the preset file format written by this build
+const PresetFileVersion = 1
+
+// Preset is a reusable set of conversion options that can be shared as a
+// .zenpreset file. Zero values leave the corresponding option at its default.
+type Preset struct {
+	Name         string          `json:"name"`
+	Description  string          `json:"description,omitempty"`
+	OutputFormat string          `json:"outputFormat"`
+	NamingMode   FileNamingMode  `json:"namingMode,omitempty"`
+	NameTemplate string          `json:"nameTemplate,omitempty"` // Used with NamingModeTemplate
+	MakeCopies   bool            `json:"makeCopies,omitempty"`   // Deprecated: use Overwrite
+	Overwrite    OverwritePolicy `json:"overwrite,omitempty"`
+
+	// Video options
+	VideoCodec         VideoCodec      `json:"videoCodec,omitempty"`
+	ProResProfile      ProResProfile   `json:"proResProfile,omitempty"`
+	DNxHRProfile       DNxHRProfile    `json:"dnxhrProfile,omitempty"`
+	Deinterlace        DeinterlaceMode `json:"deinterlace,omitempty"`
+	Speed              float64         `json:"speed,omitempty"`
+	Stabilize          bool            `json:"stabilize,omitempty"`
+	StabilizeStrength  int             `json:"stabilizeStrength,omitempty"`
+	PreserveAlpha      bool            `json:"preserveAlpha,omitempty"`
+	KeepAllAudioTracks bool            `json:"keepAllAudioTracks,omitempty"`
+	Quality            VideoQuality    `json:"quality,omitempty"`
+	CRF                int             `json:"crf,omitempty"`
+
+	// Image resize options (0 keeps the original dimension)
+	MaxWidth  int         `json:"maxWidth,omitempty"`
+	MaxHeight int         `json:"maxHeight,omitempty"`
+	Scaler    ImageScaler `json:"scaler,omitempty"`
+
+	// ColorProfile decides how images' ICC profiles are handled
+	ColorProfile ColorProfileMode `json:"colorProfile,omitempty"`
+
+	// Charset non-Unicode subtitle files are read as
+	SubtitleCharset string `json:"subtitleCharset,omitempty"`
+}
+
+// PresetFile is the on-disk form of a .zenpreset file
+type PresetFile struct {
+	Version int    `json:"version"`
+	Preset  Preset `json:"preset"`
+}
@@ -0,0 +1,8 @@
+package models
+
+// Profile is a named set of settings, presets and history kept in its own
+// database, e.g. to separate work and personal conversions
+type Profile struct {
+	Name   string `json:"name"`
+	Active bool   `json:"active"`
+}
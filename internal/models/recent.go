@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// RecentKind distinguishes the kinds of recently used paths
+type RecentKind string
+
+const (
+	RecentInput     RecentKind = "input"      // A file that was converted
+	RecentOutputDir RecentKind = "output_dir" // A directory converted files were written to
+)
+
+// RecentPath is a recently used input file or output directory
+type RecentPath struct {
+	gorm.Model
+	Kind       RecentKind `json:"kind" gorm:"size:32;uniqueIndex:idx_recent_kind_path;not null"`
+	Path       string     `json:"path" gorm:"size:512;uniqueIndex:idx_recent_kind_path;not null;serializer:encrypted"`
+	LastUsedAt time.Time  `json:"lastUsedAt" gorm:"index"`
+	UseCount   int        `json:"useCount" gorm:"default:1"`
+}
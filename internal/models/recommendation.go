@@ -0,0 +1,37 @@
+package models
+
+// RecommendationGoal is what the user wants to do with converted files
+type RecommendationGoal string
+
+const (
+	GoalShare   RecommendationGoal = "share"   // Send to others, play anywhere
+	GoalArchive RecommendationGoal = "archive" // Keep long-term without losing anything
+	GoalEdit    RecommendationGoal = "edit"    // Import into an editing application
+	GoalWeb     RecommendationGoal = "web"     // Embed in a web page
+)
+
+// RecommendedQuality hints at the quality a recommendation aims for
+type RecommendedQuality string
+
+const (
+	QualityLossless RecommendedQuality = "lossless" // No quality loss, large files
+	QualityHigh     RecommendedQuality = "high"     // Visually transparent
+	QualityBalanced RecommendedQuality = "balanced" // Good quality at a reasonable size
+)
+
+// Recommendation suggests conversion options for a file and goal. The option
+// fields match BatchConversionRequest's, so the frontend can pre-select them.
+type Recommendation struct {
+	Path               string             `json:"path"`
+	Goal               RecommendationGoal `json:"goal"`
+	OutputFormat       string             `json:"outputFormat"`
+	VideoCodec         VideoCodec         `json:"videoCodec,omitempty"`
+	ProResProfile      ProResProfile      `json:"proResProfile,omitempty"`
+	DNxHRProfile       DNxHRProfile       `json:"dnxhrProfile,omitempty"`
+	PreserveAlpha      bool               `json:"preserveAlpha,omitempty"`
+	KeepAllAudioTracks bool               `json:"keepAllAudioTracks,omitempty"`
+	Quality            RecommendedQuality `json:"quality"`
+
+	// Reasons explains the suggestion, one sentence each
+	Reasons []string `json:"reasons"`
+}
@@ -0,0 +1,31 @@
+package models
+
+// BackendRename is the backend recorded on history records of renamed
+// files, which were renamed in place rather than converted
+const BackendRename = "rename"
+
+// RenameRequest represents a request to rename files in place with a naming
+// template, without converting them
+type RenameRequest struct {
+	Files      []string `json:"files"`
+	Template   string   `json:"template"`   // e.g. "{parent}-{n:3}"; the extension is kept
+	StartIndex int      `json:"startIndex"` // Value of {n} for the first file
+	Preview    bool     `json:"preview"`    // Only compute the new names
+}
+
+// RenameItem is the outcome of renaming one file
+type RenameItem struct {
+	OldPath string `json:"oldPath"`
+	NewPath string `json:"newPath"`
+	Renamed bool   `json:"renamed"`
+	Error   string `json:"error,omitempty"`
+}
+
+// RenameResult represents the result of a batch rename. Renames are
+// recorded in history under BatchID so they can be undone.
+type RenameResult struct {
+	BatchID      string       `json:"batchId,omitempty"`
+	Items        []RenameItem `json:"items"`
+	RenamedCount int          `json:"renamedCount"`
+	FailCount    int          `json:"failCount"`
+}
@@ -0,0 +1,71 @@
+package models
+
+// RuleField is a file property tested by a rule condition, taken from the
+// file's analysis
+type RuleField string
+
+const (
+	RuleResolution RuleField = "resolution"  // Shorter side of the frame in pixels; values may be written "1080p" or "4k"
+	RuleWidth      RuleField = "width"       // Pixels
+	RuleHeight     RuleField = "height"      // Pixels
+	RuleVideoCodec RuleField = "video_codec" // e.g. "h264" or "hevc"
+	RuleAudioCodec RuleField = "audio_codec" // e.g. "aac" or "ac3"
+	RuleFrameRate  RuleField = "frame_rate"  // Frames per second
+	RuleBitrate    RuleField = "bitrate"     // Overall kilobits per second
+	RuleDuration   RuleField = "duration"    // Seconds
+	RuleSize       RuleField = "size"        // Megabytes (1,000,000 bytes)
+	RuleFormat     RuleField = "format"      // Input extension without the dot, e.g. "mkv"
+	RuleFileType   RuleField = "file_type"   // A FileType, e.g. "video"
+	RuleHDR        RuleField = "hdr"         // HDR format, e.g. "HDR10"; "" for SDR
+)
+
+// RuleOperator compares a file property with a condition's value
+type RuleOperator string
+
+const (
+	RuleEquals         RuleOperator = "=="
+	RuleNotEquals      RuleOperator = "!="
+	RuleGreater        RuleOperator = ">"
+	RuleGreaterOrEqual RuleOperator = ">="
+	RuleLess           RuleOperator = "<"
+	RuleLessOrEqual    RuleOperator = "<="
+	RuleIn             RuleOperator = "in" // Value is a comma-separated list
+)
+
+// RuleCondition tests one property of a file, e.g. resolution > 1080p.
+// Text is compared case-insensitively. A condition on a property the file
+// doesn't have, e.g. the video codec of an audio file, never matches.
+type RuleCondition struct {
+	Field    RuleField    `json:"field"`
+	Operator RuleOperator `json:"operator"`
+	Value    string       `json:"value"`
+}
+
+// RuleSettings are the settings a rule applies to a file. Empty fields keep
+// the batch's setting.
+type RuleSettings struct {
+	Skip         bool            `json:"skip,omitempty"` // Leave the file unconverted, e.g. when it is already in the wanted codec
+	OutputFormat string          `json:"outputFormat,omitempty"`
+	VideoCodec   VideoCodec      `json:"videoCodec,omitempty"`
+	Deinterlace  DeinterlaceMode `json:"deinterlace,omitempty"`
+	TargetSizeMB float64         `json:"targetSizeMB,omitempty"`
+
+	// Downscale to fit these dimensions, keeping the aspect ratio. Applies
+	// to images and video; smaller files are left at their size.
+	MaxWidth  int `json:"maxWidth,omitempty"`
+	MaxHeight int `json:"maxHeight,omitempty"`
+}
+
+// ConversionRule decides settings per file from its analysis, e.g. "if
+// resolution > 1080p then downscale to 1080p". Rules are applied in order,
+// so a later rule's settings override an earlier one's.
+type ConversionRule struct {
+	Name string `json:"name,omitempty"`
+
+	// Conditions must all match for Then to apply; a rule without
+	// conditions matches every file
+	Conditions []RuleCondition `json:"conditions,omitempty"`
+
+	Then RuleSettings  `json:"then"`
+	Else *RuleSettings `json:"else,omitempty"` // Applied to files that don't match; nil leaves them unchanged
+}
@@ -1,22 +1,90 @@
 package models
 
 import (
+	"time"
+
 	"gorm.io/gorm"
 )
 
 // Setting represents a user setting stored in the database
 type Setting struct {
 	gorm.Model
-	Key   string `json:"key" gorm:"uniqueIndex;not null"`
-	Value string `json:"value" gorm:"not null"`
+	Key   string `json:"key" gorm:"size:255;uniqueIndex;not null"`
+	Value string `json:"value" gorm:"not null;serializer:encrypted"`
+}
+
+// SettingChange is an audit entry recording one change of a setting or preset
+type SettingChange struct {
+	ID        uint      `json:"id" gorm:"primarykey"`
+	Key       string    `json:"key" gorm:"size:255;index;not null"`
+	OldValue  string    `json:"oldValue" gorm:"serializer:encrypted"` // Empty when the setting was first set
+	NewValue  string    `json:"newValue" gorm:"serializer:encrypted"`
+	ChangedAt time.Time `json:"changedAt" gorm:"autoCreateTime"`
 }
 
+// PresetChangePrefix prefixes the keys of audit entries for presets, followed
+// by the preset's name
+const PresetChangePrefix = "preset:"
+
 // SettingKey constants for common settings
 const (
-	SettingLastOutputDir   = "last_output_directory"
-	SettingDefaultNaming   = "default_naming_mode"
-	SettingDefaultMakeCopy = "default_make_copies"
-	SettingTheme           = "theme"
+	SettingLastOutputDir    = "last_output_directory"
+	SettingDefaultNaming    = "default_naming_mode"
+	SettingDefaultMakeCopy  = "default_make_copies"
+	SettingTheme            = "theme"
+	SettingStallTimeout     = "stall_timeout_minutes"
+	SettingStallAutoRetry   = "stall_auto_retry"
+	SettingStallMaxRetries  = "stall_max_retries"
+	SettingImageScaler      = "image_scaler"
+	SettingFFmpegThreads    = "ffmpeg_threads"
+	SettingLowPriority      = "low_priority_conversions"
+	SettingLowUnfocused     = "low_priority_unfocused"
+	SettingBackgroundMode   = "background_mode"
+	SettingThermalMode      = "thermal_mode"
+	SettingMovVideoCodec    = "mov_video_codec"
+	SettingProResProfile    = "prores_profile"
+	SettingDNxHRProfile     = "dnxhr_profile"
+	SettingTempDirectory    = "temp_directory"
+	SettingCheckForUpdates  = "check_for_updates"
+	SettingProxyURL         = "proxy_url"
+	SettingCAFile           = "ca_file"
+	SettingOutputRoutes     = "output_routes"
+	SettingWatchClipboard   = "watch_clipboard"
+	SettingConverterBackend = "converter_backend"
+	SettingLanguage         = "language"
+	SettingNotifications    = "notifications"
+	SettingSummaryThreshold = "summary_threshold"
+	SettingSummaryInterval  = "summary_interval_seconds"
+)
+
+// ConverterBackend selects the video and audio converter of App Store
+// builds, which can convert with a system FFmpeg or with AVFoundation
+type ConverterBackend string
+
+const (
+	ConverterBackendAuto         ConverterBackend = "auto"         // System FFmpeg when installed, else AVFoundation
+	ConverterBackendFFmpeg       ConverterBackend = "ffmpeg"       // System FFmpeg only
+	ConverterBackendAVFoundation ConverterBackend = "avfoundation" // AVFoundation, even with FFmpeg installed
+)
+
+// Language is a language messages from the backend, such as explanations
+// of failed conversions, are written in
+type Language string
+
+const (
+	LanguageEnglish Language = "en"
+	LanguageDanish  Language = "da"
+	LanguageGerman  Language = "de"
+)
+
+// BackgroundMode controls what happens to conversions while the app window is
+// hidden, the machine is running on battery or it is overheating
+type BackgroundMode string
+
+const (
+	BackgroundModeOff    BackgroundMode = "off"    // Always run at full speed
+	BackgroundModeReduce BackgroundMode = "reduce" // Reduce concurrency and process priority
+	BackgroundModePause  BackgroundMode = "pause"  // Pause starting new conversions
 )
 
 // UserSettings represents the user's preferences
@@ -25,14 +93,104 @@ type UserSettings struct {
 	DefaultNamingMode   FileNamingMode `json:"defaultNamingMode"`
 	DefaultMakeCopies   bool           `json:"defaultMakeCopies"`
 	Theme               string         `json:"theme"`
+
+	// Stalled conversion handling
+	StallTimeoutMinutes int  `json:"stallTimeoutMinutes"` // Minutes without progress before a job counts as stalled
+	StallAutoRetry      bool `json:"stallAutoRetry"`      // Kill and retry stalled jobs automatically
+	StallMaxRetries     int  `json:"stallMaxRetries"`     // Maximum automatic retries per job
+
+	// ImageScaler selects the resize algorithm for image conversions, all of
+	// which run on the CPU
+	ImageScaler ImageScaler `json:"imageScaler"`
+
+	// Resource limits for conversion processes
+	FFmpegThreads int  `json:"ffmpegThreads"` // Maximum FFmpeg threads (0 = automatic)
+	LowPriority   bool `json:"lowPriority"`   // Run conversions at low OS priority
+
+	// LowWhenUnfocused lowers the priority of running conversions while
+	// another app has focus
+	LowWhenUnfocused bool `json:"lowWhenUnfocused"`
+
+	// BackgroundMode applies while the window is hidden or on battery power
+	BackgroundMode BackgroundMode `json:"backgroundMode"`
+
+	// ThermalMode applies while the machine is slowing down to cool off
+	ThermalMode BackgroundMode `json:"thermalMode"`
+
+	// Advanced: codec used for .mov output instead of H.264, e.g. ProRes or
+	// DNxHR for editing, with the profile of each codec
+	MovVideoCodec VideoCodec    `json:"movVideoCodec"`
+	ProResProfile ProResProfile `json:"proResProfile"`
+	DNxHRProfile  DNxHRProfile  `json:"dnxhrProfile"`
+
+	// TempDirectory holds scratch files such as preview clips and expanded
+	// archives (empty = the OS temp directory)
+	TempDirectory string `json:"tempDirectory"`
+
+	// CheckForUpdates enables the periodic check for new releases
+	CheckForUpdates bool `json:"checkForUpdates"`
+
+	// Network features: an HTTP, HTTPS or SOCKS5 proxy (empty = system
+	// proxy) and a PEM file of extra trusted certificate authorities
+	ProxyURL string `json:"proxyUrl"`
+	CAFile   string `json:"caFile"`
+
+	// OutputRoutes maps output formats to the directories their files are
+	// written to when a batch doesn't choose an output directory, e.g. "gif"
+	// to "~/Pictures/gifs". Other formats are written next to their input.
+	OutputRoutes map[string]string `json:"outputRoutes"`
+
+	// WatchClipboard offers to convert media files and URLs when they are
+	// copied to the clipboard
+	WatchClipboard bool `json:"watchClipboard"`
+
+	// ConverterBackend selects the video backend of App Store builds. Other
+	// builds always use FFmpeg.
+	ConverterBackend ConverterBackend `json:"converterBackend"`
+
+	// Language of messages explaining failed conversions; languages without
+	// translations fall back to English
+	Language Language `json:"language"`
+
+	// Notifications shows a system notification when a batch finishes, and
+	// periodic summaries of long batches, while the window isn't focused
+	Notifications bool `json:"notifications"`
+
+	// Batches of at least SummaryThreshold files report their progress as
+	// a summary every SummaryIntervalSeconds instead of file by file
+	SummaryThreshold       int `json:"summaryThreshold"`
+	SummaryIntervalSeconds int `json:"summaryIntervalSeconds"`
 }
 
 // DefaultUserSettings returns the default user settings
 func DefaultUserSettings() UserSettings {
 	return UserSettings{
-		LastOutputDirectory: "",
-		DefaultNamingMode:   NamingModeOriginal,
-		DefaultMakeCopies:   true,
-		Theme:               "system",
+		LastOutputDirectory:    "",
+		DefaultNamingMode:      NamingModeOriginal,
+		DefaultMakeCopies:      true,
+		Theme:                  "system",
+		StallTimeoutMinutes:    5,
+		StallAutoRetry:         false,
+		StallMaxRetries:        1,
+		ImageScaler:            ScalerBalanced,
+		FFmpegThreads:          0,
+		LowPriority:            false,
+		LowWhenUnfocused:       true,
+		BackgroundMode:         BackgroundModeReduce,
+		ThermalMode:            BackgroundModeReduce,
+		MovVideoCodec:          CodecDefault,
+		ProResProfile:          ProResHQ,
+		DNxHRProfile:           DNxHRHQ,
+		TempDirectory:          "",
+		CheckForUpdates:        true,
+		ProxyURL:               "",
+		CAFile:                 "",
+		OutputRoutes:           map[string]string{},
+		WatchClipboard:         false,
+		ConverterBackend:       ConverterBackendAuto,
+		Language:               LanguageEnglish,
+		Notifications:          true,
+		SummaryThreshold:       20,
+		SummaryIntervalSeconds: 30,
 	}
 }
@@ -0,0 +1,66 @@
+package models
+
+// SocialPreset encodes a platform's published upload specs. Output is H.264
+// and AAC in mp4, fitted and padded to the frame size.
+type SocialPreset struct {
+	ID           string  `json:"id"`
+	Name         string  `json:"name"`
+	Width        int     `json:"width"`
+	Height       int     `json:"height"`
+	MaxFrameRate int     `json:"maxFrameRate"`        // Faster sources are reduced to this rate
+	VideoBitrate int64   `json:"videoBitrate"`        // Bits per second
+	AudioBitrate int64   `json:"audioBitrate"`        // Bits per second
+	MaxDuration  int     `json:"maxDuration"`         // Longest upload in seconds, 0 if unlimited
+	MaxSizeMB    float64 `json:"maxSizeMB,omitempty"` // Largest upload in megabytes, 0 if unlimited
+}
+
+// SocialPresets lists the built-in platform presets. Platforms change their
+// limits every so often; update the numbers here when they do.
+var SocialPresets = []SocialPreset{
+	{
+		ID: "youtube-1080p", Name: "YouTube 1080p",
+		Width: 1920, Height: 1080, MaxFrameRate: 60,
+		VideoBitrate: 12_000_000, AudioBitrate: 384_000,
+		MaxDuration: 12 * 60 * 60,
+	},
+	{
+		ID: "youtube-shorts", Name: "YouTube Shorts",
+		Width: 1080, Height: 1920, MaxFrameRate: 60,
+		VideoBitrate: 12_000_000, AudioBitrate: 384_000,
+		MaxDuration: 3 * 60,
+	},
+	{
+		ID: "instagram-reels", Name: "Instagram Reels",
+		Width: 1080, Height: 1920, MaxFrameRate: 30,
+		VideoBitrate: 5_000_000, AudioBitrate: 128_000,
+		MaxDuration: 3 * 60, MaxSizeMB: 4000,
+	},
+	{
+		ID: "instagram-stories", Name: "Instagram Stories",
+		Width: 1080, Height: 1920, MaxFrameRate: 30,
+		VideoBitrate: 5_000_000, AudioBitrate: 128_000,
+		MaxDuration: 60, MaxSizeMB: 4000,
+	},
+	{
+		ID: "tiktok", Name: "TikTok",
+		Width: 1080, Height: 1920, MaxFrameRate: 60,
+		VideoBitrate: 8_000_000, AudioBitrate: 128_000,
+		MaxDuration: 10 * 60, MaxSizeMB: 4000,
+	},
+	{
+		ID: "twitter", Name: "Twitter / X",
+		Width: 1280, Height: 720, MaxFrameRate: 40,
+		VideoBitrate: 5_000_000, AudioBitrate: 128_000,
+		MaxDuration: 140, MaxSizeMB: 512,
+	},
+}
+
+// GetSocialPreset returns the built-in preset with the given ID
+func GetSocialPreset(id string) (SocialPreset, bool) {
+	for _, preset := range SocialPresets {
+		if preset.ID == id {
+			return preset, true
+		}
+	}
+	return SocialPreset{}, false
+}
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// UpdateInfo describes the newest release found by the update checker
+type UpdateInfo struct {
+	CurrentVersion string    `json:"currentVersion"`
+	LatestVersion  string    `json:"latestVersion"`
+	Available      bool      `json:"available"`             // LatestVersion is newer than CurrentVersion
+	ReleaseURL     string    `json:"releaseUrl"`            // Release page
+	DownloadURL    string    `json:"downloadUrl,omitempty"` // Installer for this platform, if the release has one
+	Notes          string    `json:"notes,omitempty"`
+	PublishedAt    time.Time `json:"publishedAt"`
+	CheckedAt      time.Time `json:"checkedAt"`
+}
@@ -0,0 +1,12 @@
+package models
+
+// WorkerMessage is a line of the newline-delimited JSON a worker streams
+// while converting a dispatched job: progress updates, then the result.
+// Output identifies the converted file to download when the input was
+// uploaded rather than read from shared storage.
+type WorkerMessage struct {
+	Progress *float64          `json:"progress,omitempty"`
+	Result   *ConversionResult `json:"result,omitempty"`
+	Output   string            `json:"output,omitempty"`
+	Error    string            `json:"error,omitempty"`
+}
@@ -0,0 +1,11 @@
+// Package photos imports photos and videos from the macOS Photos library.
+// Library assets aren't files that open dialogs can reach, least of all in
+// sandboxed builds, so the system photo picker exports their originals
+// (HEIC and HEVC included) to a folder the app then converts from.
+package photos
+
+import "errors"
+
+// ErrUnsupported is returned by Pick where the system photo picker isn't
+// available: on other platforms and before macOS 13
+var ErrUnsupported = errors.New("importing from Photos requires macOS 13 or later")
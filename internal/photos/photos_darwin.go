@@ -0,0 +1,187 @@
+//go:build darwin && cgo
+
+package photos
+
+/*
+#cgo CFLAGS: -x objective-c -fobjc-arc
+#cgo LDFLAGS: -framework AppKit -framework PhotosUI -framework UniformTypeIdentifiers
+
+#import <AppKit/AppKit.h>
+#import <PhotosUI/PhotosUI.h>
+#import <UniformTypeIdentifiers/UniformTypeIdentifiers.h>
+#include <stdlib.h>
+#include <string.h>
+
+enum {
+    pickOK = 0,
+    pickUnsupported = 1,
+    pickNoWindow = 2,
+};
+
+// CZPhotoPicker shows the picker as a sheet on the app window and copies the
+// originals of the chosen assets to a directory
+API_AVAILABLE(macos(13.0))
+@interface CZPhotoPicker : NSObject <PHPickerViewControllerDelegate>
+@property (strong) NSString* directory;
+@property (strong) NSMutableArray* paths;
+@property (strong) NSWindow* parent;
+@property (strong) NSWindow* sheet;
+@property (strong) dispatch_semaphore_t done;
+@end
+
+@implementation CZPhotoPicker
+
+- (void)picker:(PHPickerViewController*)picker didFinishPicking:(NSArray<PHPickerResult*>*)results {
+    [self.parent endSheet:self.sheet];
+
+    // Copies finish in any order; keep the order the assets were picked in
+    self.paths = [NSMutableArray arrayWithCapacity:results.count];
+    for (NSUInteger i = 0; i < results.count; i++) {
+        [self.paths addObject:[NSNull null]];
+    }
+
+    dispatch_group_t group = dispatch_group_create();
+    [results enumerateObjectsUsingBlock:^(PHPickerResult* result, NSUInteger index, BOOL* stop) {
+        NSItemProvider* provider = result.itemProvider;
+        NSString* type = nil;
+        for (NSString* identifier in provider.registeredTypeIdentifiers) {
+            UTType* utType = [UTType typeWithIdentifier:identifier];
+            if ([utType conformsToType:UTTypeMovie] || [utType conformsToType:UTTypeImage]) {
+                type = identifier;
+                break;
+            }
+        }
+        if (!type) {
+            return;
+        }
+
+        dispatch_group_enter(group);
+        [provider loadFileRepresentationForTypeIdentifier:type completionHandler:^(NSURL* url, NSError* error) {
+            // The file is deleted when this handler returns
+            NSString* path = url ? [self copyFile:url name:provider.suggestedName] : nil;
+            if (path) {
+                @synchronized (self.paths) {
+                    self.paths[index] = path;
+                }
+            }
+            dispatch_group_leave(group);
+        }];
+    }];
+
+    dispatch_group_notify(group, dispatch_get_global_queue(QOS_CLASS_USER_INITIATED, 0), ^{
+        dispatch_semaphore_signal(self.done);
+    });
+}
+
+// copyFile copies an exported original into the directory under the
+// asset's name, numbered if the name is taken
+- (NSString*)copyFile:(NSURL*)url name:(NSString*)name {
+    NSString* base = name.length > 0 ? name : url.lastPathComponent.stringByDeletingPathExtension;
+    base = [base stringByReplacingOccurrencesOfString:@"/" withString:@"-"];
+    NSString* ext = url.pathExtension;
+
+    NSFileManager* files = NSFileManager.defaultManager;
+    for (int n = 1; n <= 1000; n++) {
+        NSString* fileName = n == 1 ? base : [NSString stringWithFormat:@"%@ (%d)", base, n];
+        NSString* path = [self.directory stringByAppendingPathComponent:fileName];
+        if (ext.length > 0) {
+            path = [path stringByAppendingPathExtension:ext];
+        }
+        if ([files fileExistsAtPath:path]) {
+            continue;
+        }
+        return [files copyItemAtPath:url.path toPath:path error:nil] ? path : nil;
+    }
+    return nil;
+}
+
+@end
+
+// pickPhotos shows the photo picker and copies the chosen originals to
+// directory. It returns their paths separated by newlines, which the caller
+// frees, and sets status when the picker can't be shown.
+static char* pickPhotos(const char* directory, int* status) {
+    if (@available(macOS 13.0, *)) {
+        CZPhotoPicker* delegate = [CZPhotoPicker new];
+        delegate.directory = [NSString stringWithUTF8String:directory];
+        delegate.done = dispatch_semaphore_create(0);
+        __block int result = pickOK;
+
+        dispatch_async(dispatch_get_main_queue(), ^{
+            NSWindow* parent = NSApp.mainWindow ?: NSApp.windows.firstObject;
+            if (!parent) {
+                result = pickNoWindow;
+                dispatch_semaphore_signal(delegate.done);
+                return;
+            }
+
+            // The current representation keeps HEIC and HEVC originals
+            // rather than transcoding them to JPEG and H.264
+            PHPickerConfiguration* config = [[PHPickerConfiguration alloc] init];
+            config.selectionLimit = 0;
+            config.preferredAssetRepresentationMode = PHPickerConfigurationAssetRepresentationModeCurrent;
+            config.filter = [PHPickerFilter anyFilterMatchingSubfilters:@[PHPickerFilter.imagesFilter, PHPickerFilter.videosFilter]];
+
+            PHPickerViewController* picker = [[PHPickerViewController alloc] initWithConfiguration:config];
+            picker.delegate = delegate;
+            delegate.parent = parent;
+            delegate.sheet = [NSWindow windowWithContentViewController:picker];
+            [parent beginSheet:delegate.sheet completionHandler:nil];
+        });
+        dispatch_semaphore_wait(delegate.done, DISPATCH_TIME_FOREVER);
+
+        *status = result;
+        NSMutableArray* paths = [NSMutableArray array];
+        for (id path in delegate.paths) {
+            if (path != [NSNull null]) {
+                [paths addObject:path];
+            }
+        }
+        return strdup([[paths componentsJoinedByString:@"\n"] UTF8String]);
+    }
+
+    *status = pickUnsupported;
+    return NULL;
+}
+*/
+import "C"
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"unsafe"
+)
+
+// Supported reports whether Pick can show the photo picker
+func Supported() bool {
+	return true
+}
+
+// Pick shows the system photo picker and exports the originals of the
+// chosen photos and videos to dir, returning their paths. It returns no
+// paths if the picker was cancelled.
+func Pick(dir string) ([]string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create import folder: %w", err)
+	}
+
+	cDir := C.CString(dir)
+	defer C.free(unsafe.Pointer(cDir))
+
+	var status C.int
+	cPaths := C.pickPhotos(cDir, &status)
+	switch status {
+	case C.pickUnsupported:
+		return nil, ErrUnsupported
+	case C.pickNoWindow:
+		return nil, fmt.Errorf("the photo picker needs an open window")
+	}
+	defer C.free(unsafe.Pointer(cPaths))
+
+	list := C.GoString(cPaths)
+	if list == "" {
+		return nil, nil
+	}
+	return strings.Split(list, "\n"), nil
+}
@@ -0,0 +1,14 @@
+//go:build !(darwin && cgo)
+
+package photos
+
+// Supported reports whether Pick can show the photo picker
+func Supported() bool {
+	return false
+}
+
+// Pick shows the system photo picker and exports the originals of the
+// chosen photos and videos to dir, returning their paths
+func Pick(dir string) ([]string, error) {
+	return nil, ErrUnsupported
+}
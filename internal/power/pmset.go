@@ -0,0 +1,44 @@
+package power
+
+import (
+	"regexp"
+	"strconv"
+)
+
+var (
+	// speedLimitPattern matches the CPU speed limit in percent reported by pmset
+	speedLimitPattern = regexp.MustCompile(`CPU_Speed_Limit\s*=\s*(\d+)`)
+
+	// warningLevelPattern matches a thermal warning level reported by pmset,
+	// where level 0 means no warning
+	warningLevelPattern = regexp.MustCompile(`(?i)thermal warning level set to:?\s*(\d+)`)
+)
+
+// parsePmsetThermal returns the thermal state from the output of
+// "pmset -g therm". It is parsed on every platform so it can be tested
+// anywhere.
+func parsePmsetThermal(output []byte) Thermal {
+	thermal := ThermalNominal
+	if match := speedLimitPattern.FindSubmatch(output); match != nil {
+		limit, _ := strconv.Atoi(string(match[1]))
+		switch {
+		case limit < 50:
+			thermal = ThermalCritical
+		case limit < 80:
+			thermal = ThermalSerious
+		case limit < 100:
+			thermal = ThermalFair
+		}
+	}
+
+	// Apple silicon doesn't report a speed limit, only a warning level. The
+	// last level reported is the current one.
+	matches := warningLevelPattern.FindAllSubmatch(output, -1)
+	if len(matches) > 0 && thermal != ThermalCritical {
+		level, _ := strconv.Atoi(string(matches[len(matches)-1][1]))
+		if level > 0 {
+			thermal = ThermalSerious
+		}
+	}
+	return thermal
+}
@@ -0,0 +1,48 @@
+package power
+
+import "testing"
+
+func TestParsePmsetThermal(t *testing.T) {
+	for _, test := range []struct {
+		name, output string
+		want         Thermal
+	}{
+		{"intel cool", `Note: No thermal warning level has been recorded
+Note: No performance warning level has been recorded
+2024-03-11 09:14:02 +0100 CPU Power notify
+	CPU_Scheduler_Limit 	= 100
+	CPU_Available_CPUs 	= 8
+	CPU_Speed_Limit 	= 100
+`, ThermalNominal},
+		{"intel throttled", `2024-03-11 09:20:45 +0100 CPU Power notify
+	CPU_Scheduler_Limit 	= 100
+	CPU_Available_CPUs 	= 8
+	CPU_Speed_Limit 	= 67
+`, ThermalSerious},
+		{"intel hot", `2024-03-11 09:31:10 +0100 CPU Power notify
+	CPU_Scheduler_Limit 	= 100
+	CPU_Available_CPUs 	= 8
+	CPU_Speed_Limit 	= 42
+2024-03-11 09:31:10 +0100 Thermal Warning Level Set To: 2
+`, ThermalCritical},
+		{"apple silicon none recorded", `Note: No thermal warning level has been recorded
+Note: No performance warning level has been recorded
+Note: No CPU power status has been recorded
+`, ThermalNominal},
+		{"apple silicon level 0", `2024-03-11 10:02:31 +0100 Thermal Warning Level Set To: 0
+Note: No performance warning level has been recorded
+Note: No CPU power status has been recorded
+`, ThermalNominal},
+		{"apple silicon warning", `2024-03-11 10:05:12 +0100 Thermal Warning Level Set To: 2
+Note: No performance warning level has been recorded
+Note: No CPU power status has been recorded
+`, ThermalSerious},
+		{"apple silicon cooled down", `2024-03-11 10:05:12 +0100 Thermal Warning Level Set To: 2
+2024-03-11 10:09:40 +0100 Thermal Warning Level Set To: 0
+`, ThermalNominal},
+	} {
+		if got := parsePmsetThermal([]byte(test.output)); got != test.want {
+			t.Errorf("%s: parsePmsetThermal = %s, want %s", test.name, got, test.want)
+		}
+	}
+}
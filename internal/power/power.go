@@ -0,0 +1,30 @@
+package power
+
+// Source identifies what the machine is currently running on
+type Source string
+
+const (
+	SourceAC      Source = "ac"
+	SourceBattery Source = "battery"
+	SourceUnknown Source = "unknown"
+)
+
+// OnBattery reports whether the machine is running on battery power.
+// Desktops and platforms where the power source can't be determined
+// report false.
+func OnBattery() bool {
+	source, err := GetSource()
+	return err == nil && source == SourceBattery
+}
+
+// Thermal describes how hard the machine is being pushed by heat, using the
+// same levels as macOS
+type Thermal string
+
+const (
+	ThermalNominal  Thermal = "nominal"  // No thermal pressure
+	ThermalFair     Thermal = "fair"     // Warming up, nothing is throttled yet
+	ThermalSerious  Thermal = "serious"  // The system is slowing down to cool off
+	ThermalCritical Thermal = "critical" // The system is close to shutting down
+	ThermalUnknown  Thermal = "unknown"
+)
@@ -0,0 +1,25 @@
+//go:build darwin
+
+package power
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// GetSource returns the current power source reported by pmset
+func GetSource() (Source, error) {
+	output, err := exec.Command("pmset", "-g", "batt").Output()
+	if err != nil {
+		return SourceUnknown, err
+	}
+
+	switch {
+	case strings.Contains(string(output), "'Battery Power'"):
+		return SourceBattery, nil
+	case strings.Contains(string(output), "'AC Power'"):
+		return SourceAC, nil
+	default:
+		return SourceUnknown, nil
+	}
+}
@@ -0,0 +1,51 @@
+//go:build linux
+
+package power
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// powerSupplyDir is where the kernel exposes power supply state
+const powerSupplyDir = "/sys/class/power_supply"
+
+// GetSource returns the current power source from sysfs
+func GetSource() (Source, error) {
+	supplies, err := os.ReadDir(powerSupplyDir)
+	if err != nil {
+		return SourceUnknown, err
+	}
+
+	hasBattery := false
+	for _, supply := range supplies {
+		dir := filepath.Join(powerSupplyDir, supply.Name())
+
+		switch readValue(filepath.Join(dir, "type")) {
+		case "Mains", "USB":
+			if readValue(filepath.Join(dir, "online")) == "1" {
+				return SourceAC, nil
+			}
+		case "Battery":
+			hasBattery = true
+			if readValue(filepath.Join(dir, "status")) == "Discharging" {
+				return SourceBattery, nil
+			}
+		}
+	}
+
+	if !hasBattery {
+		return SourceAC, nil
+	}
+	return SourceUnknown, nil
+}
+
+// readValue reads a single sysfs attribute
+func readValue(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
@@ -0,0 +1,8 @@
+//go:build !linux && !darwin && !windows
+
+package power
+
+// GetSource returns SourceUnknown on platforms without power source detection
+func GetSource() (Source, error) {
+	return SourceUnknown, nil
+}
@@ -0,0 +1,38 @@
+//go:build windows
+
+package power
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+var procGetSystemPowerStatus = syscall.NewLazyDLL("kernel32.dll").NewProc("GetSystemPowerStatus")
+
+// systemPowerStatus mirrors the Win32 SYSTEM_POWER_STATUS structure
+type systemPowerStatus struct {
+	ACLineStatus        byte
+	BatteryFlag         byte
+	BatteryLifePercent  byte
+	SystemStatusFlag    byte
+	BatteryLifeTime     uint32
+	BatteryFullLifeTime uint32
+}
+
+// GetSource returns the current power source from GetSystemPowerStatus
+func GetSource() (Source, error) {
+	var status systemPowerStatus
+	ret, _, err := procGetSystemPowerStatus.Call(uintptr(unsafe.Pointer(&status)))
+	if ret == 0 {
+		return SourceUnknown, err
+	}
+
+	switch status.ACLineStatus {
+	case 0:
+		return SourceBattery, nil
+	case 1:
+		return SourceAC, nil
+	default:
+		return SourceUnknown, nil
+	}
+}
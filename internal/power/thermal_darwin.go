@@ -0,0 +1,15 @@
+//go:build darwin
+
+package power
+
+import "os/exec"
+
+// GetThermal returns the thermal state from the CPU speed limit and thermal
+// warnings reported by pmset
+func GetThermal() (Thermal, error) {
+	output, err := exec.Command("pmset", "-g", "therm").Output()
+	if err != nil {
+		return ThermalUnknown, err
+	}
+	return parsePmsetThermal(output), nil
+}
@@ -0,0 +1,88 @@
+//go:build linux
+
+package power
+
+import (
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	// thermalDir is where the kernel exposes thermal zones
+	thermalDir = "/sys/class/thermal"
+
+	// fairMargin is how close (millidegrees Celsius) a zone may get to its
+	// first trip point before it counts as warming up
+	fairMargin = 10000
+)
+
+// thermalRank orders the thermal states from coolest to hottest
+var thermalRank = map[Thermal]int{
+	ThermalNominal:  1,
+	ThermalFair:     2,
+	ThermalSerious:  3,
+	ThermalCritical: 4,
+}
+
+// GetThermal returns the hottest state of the thermal zones in sysfs,
+// comparing each zone's temperature to its trip points. Zones without trip
+// points are ignored.
+func GetThermal() (Thermal, error) {
+	zones, err := filepath.Glob(filepath.Join(thermalDir, "thermal_zone*"))
+	if err != nil {
+		return ThermalUnknown, err
+	}
+
+	hottest := ThermalUnknown
+	for _, zone := range zones {
+		thermal := zoneThermal(zone)
+		if thermalRank[thermal] > thermalRank[hottest] {
+			hottest = thermal
+		}
+	}
+	return hottest, nil
+}
+
+// zoneThermal returns the state of a single thermal zone
+func zoneThermal(zone string) Thermal {
+	temp, err := strconv.Atoi(readValue(filepath.Join(zone, "temp")))
+	if err != nil {
+		return ThermalUnknown
+	}
+
+	trips, _ := filepath.Glob(filepath.Join(zone, "trip_point_*_type"))
+	thermal := ThermalUnknown
+	lowest := 0
+	for _, trip := range trips {
+		tripTemp, err := strconv.Atoi(readValue(strings.TrimSuffix(trip, "_type") + "_temp"))
+		if err != nil || tripTemp <= 0 {
+			continue
+		}
+
+		reached := ThermalNominal
+		switch readValue(trip) {
+		case "passive":
+			if temp >= tripTemp {
+				reached = ThermalSerious
+			}
+		case "hot", "critical":
+			if temp >= tripTemp {
+				reached = ThermalCritical
+			}
+		default:
+			continue
+		}
+		if lowest == 0 || tripTemp < lowest {
+			lowest = tripTemp
+		}
+		if thermalRank[reached] > thermalRank[thermal] {
+			thermal = reached
+		}
+	}
+
+	if thermal == ThermalNominal && temp >= lowest-fairMargin {
+		thermal = ThermalFair
+	}
+	return thermal
+}
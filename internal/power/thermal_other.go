@@ -0,0 +1,8 @@
+//go:build !linux && !darwin
+
+package power
+
+// GetThermal returns ThermalUnknown on platforms without thermal state detection
+func GetThermal() (Thermal, error) {
+	return ThermalUnknown, nil
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"converzen/internal/database"
+	"converzen/internal/logger"
+	"converzen/internal/models"
+)
+
+// bookmarkRepoImpl implements BookmarkRepository
+type bookmarkRepoImpl struct {
+	db  *gorm.DB
+	log *logger.ComponentLogger
+}
+
+// NewBookmarkRepository creates a new BookmarkRepository
+func NewBookmarkRepository(db *gorm.DB, log *logger.Logger) BookmarkRepository {
+	return &bookmarkRepoImpl{
+		db:  db,
+		log: log.WithComponent("bookmark-repo"),
+	}
+}
+
+// Save stores the bookmark of a path, replacing an earlier one
+func (r *bookmarkRepoImpl) Save(path string, data []byte) error {
+	bookmark := models.Bookmark{Path: path, Data: data}
+	err := r.db.Unscoped().Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "path"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"data":       data,
+			"updated_at": time.Now(),
+			"deleted_at": nil,
+		}),
+	}).Create(&bookmark).Error
+	if err != nil {
+		r.log.Error("Failed to save bookmark: %v", err)
+		return fmt.Errorf("failed to save bookmark: %w", err)
+	}
+	return nil
+}
+
+// List retrieves all bookmarks
+func (r *bookmarkRepoImpl) List() ([]models.Bookmark, error) {
+	var bookmarks []models.Bookmark
+	if err := r.db.Order("id").Find(&bookmarks).Error; err != nil {
+		r.log.Error("Failed to get bookmarks: %v", err)
+		return nil, fmt.Errorf("failed to get bookmarks: %w", err)
+	}
+	return bookmarks, nil
+}
+
+// Delete removes the bookmark of a path
+func (r *bookmarkRepoImpl) Delete(path string) error {
+	if err := r.db.Unscoped().Where("path = ?", database.Seal(r.db, path)).Delete(&models.Bookmark{}).Error; err != nil {
+		r.log.Error("Failed to delete bookmark: %v", err)
+		return fmt.Errorf("failed to delete bookmark: %w", err)
+	}
+	return nil
+}
@@ -2,14 +2,19 @@ package repository
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
 
+	"converzen/internal/database"
 	"converzen/internal/logger"
 	"converzen/internal/models"
 )
 
+// createBatchSize is the number of rows inserted per statement by CreateBatch
+const createBatchSize = 500
+
 // conversionRepoImpl implements ConversionRepository
 type conversionRepoImpl struct {
 	db  *gorm.DB
@@ -37,6 +42,27 @@ func (r *conversionRepoImpl) Create(conversion *models.Conversion) error {
 	return nil
 }
 
+// CreateBatch creates multiple conversion records in a single transaction,
+// inserting them in chunks so large batches don't need one statement per row
+func (r *conversionRepoImpl) CreateBatch(conversions []*models.Conversion) error {
+	if len(conversions) == 0 {
+		return nil
+	}
+
+	r.log.Debug("Creating %d conversion records", len(conversions))
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		return tx.CreateInBatches(conversions, createBatchSize).Error
+	})
+	if err != nil {
+		r.log.Error("Failed to create conversion records: %v", err)
+		return fmt.Errorf("failed to create conversion records: %w", err)
+	}
+
+	r.log.Debug("Created %d conversion records", len(conversions))
+	return nil
+}
+
 // Update updates an existing conversion record
 func (r *conversionRepoImpl) Update(conversion *models.Conversion) error {
 	r.log.Debug("Updating conversion record ID: %d", conversion.ID)
@@ -49,6 +75,30 @@ func (r *conversionRepoImpl) Update(conversion *models.Conversion) error {
 	return nil
 }
 
+// UpdateBatch updates multiple conversion records in a single transaction
+func (r *conversionRepoImpl) UpdateBatch(conversions []*models.Conversion) error {
+	if len(conversions) == 0 {
+		return nil
+	}
+
+	r.log.Debug("Updating %d conversion records", len(conversions))
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		for _, conversion := range conversions {
+			if err := tx.Save(conversion).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		r.log.Error("Failed to update conversion records: %v", err)
+		return fmt.Errorf("failed to update conversion records: %w", err)
+	}
+
+	return nil
+}
+
 // GetByID retrieves a conversion by ID
 func (r *conversionRepoImpl) GetByID(id uint) (*models.Conversion, error) {
 	r.log.Debug("Getting conversion by ID: %d", id)
@@ -65,26 +115,207 @@ func (r *conversionRepoImpl) GetByID(id uint) (*models.Conversion, error) {
 	return &conversion, nil
 }
 
-// GetHistory retrieves conversion history with a limit
-func (r *conversionRepoImpl) GetHistory(limit int) ([]models.Conversion, error) {
-	r.log.Debug("Getting conversion history (limit: %d)", limit)
+// GetHistory retrieves conversion history matching a filter
+func (r *conversionRepoImpl) GetHistory(filter models.HistoryFilter) ([]models.Conversion, error) {
+	r.log.Debug("Getting conversion history (filter: %+v)", filter)
 
 	var conversions []models.Conversion
 	query := r.db.Order("created_at DESC")
 
-	if limit > 0 {
-		query = query.Limit(limit)
+	if !filter.IncludeArchived {
+		query = query.Where("archived_at IS NULL")
+	}
+	if len(filter.Statuses) > 0 {
+		query = query.Where("status IN ?", filter.Statuses)
+	}
+	if filter.FileType != "" {
+		query = query.Where("file_type = ?", filter.FileType)
+	}
+	if filter.OutputFormat != "" {
+		format := strings.TrimPrefix(strings.ToLower(filter.OutputFormat), ".")
+		query = query.Where("LOWER(output_format) IN ?", []string{format, "." + format})
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+	// Encrypted paths and notes can't be matched in SQL, so they are
+	// searched after decryption
+	searchInGo := filter.Search != "" && database.Encrypted(r.db)
+	if filter.Search != "" && !searchInGo {
+		// LIKE is case-sensitive in Postgres, so both sides are lowercased
+		pattern := "%" + strings.ToLower(filter.Search) + "%"
+		query = query.Where("LOWER(input_path) LIKE ? OR LOWER(output_path) LIKE ? OR LOWER(note) LIKE ?", pattern, pattern, pattern)
+	}
+	if filter.Limit > 0 && !searchInGo {
+		query = query.Limit(filter.Limit)
 	}
 
 	if err := query.Find(&conversions).Error; err != nil {
 		r.log.Error("Failed to get conversion history: %v", err)
 		return nil, fmt.Errorf("failed to get conversion history: %w", err)
 	}
+	if searchInGo {
+		conversions = searchConversions(conversions, filter.Search, filter.Limit)
+	}
 
 	r.log.Debug("Retrieved %d conversion records", len(conversions))
 	return conversions, nil
 }
 
+// searchConversions keeps the conversions whose paths or note contain
+// search, ignoring case like SQLite's LIKE, up to limit (0 = all)
+func searchConversions(conversions []models.Conversion, search string, limit int) []models.Conversion {
+	search = strings.ToLower(search)
+	matches := conversions[:0]
+	for _, c := range conversions {
+		if strings.Contains(strings.ToLower(c.InputPath), search) ||
+			strings.Contains(strings.ToLower(c.OutputPath), search) ||
+			strings.Contains(strings.ToLower(c.Note), search) {
+			matches = append(matches, c)
+			if limit > 0 && len(matches) == limit {
+				break
+			}
+		}
+	}
+	return matches
+}
+
+// GetByBatch retrieves a page of a batch's conversions in submission order
+func (r *conversionRepoImpl) GetByBatch(batchID string, offset, limit int) ([]models.Conversion, error) {
+	r.log.Debug("Getting conversions for batch %s (offset: %d, limit: %d)", batchID, offset, limit)
+
+	var conversions []models.Conversion
+	query := r.db.Where("batch_id = ?", batchID).Order("id ASC").Offset(offset)
+
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	if err := query.Find(&conversions).Error; err != nil {
+		r.log.Error("Failed to get batch conversions: %v", err)
+		return nil, fmt.Errorf("failed to get batch conversions: %w", err)
+	}
+
+	return conversions, nil
+}
+
+// GetBatchSummaries retrieves a page of history grouped by batch
+func (r *conversionRepoImpl) GetBatchSummaries(offset, limit int) ([]models.BatchSummary, error) {
+	r.log.Debug("Getting batch summaries (offset: %d, limit: %d)", offset, limit)
+
+	groups := r.db.Model(&models.Conversion{}).
+		Select(`MIN(id) AS conversion_id, COALESCE(batch_id, '') AS batch_id, COUNT(*) AS file_count,
+			SUM(CASE WHEN status = ? THEN 1 ELSE 0 END) AS completed_count,
+			SUM(CASE WHEN status = ? THEN 1 ELSE 0 END) AS failed_count,
+			SUM(CASE WHEN status = ? THEN 1 ELSE 0 END) AS cancelled_count,
+			SUM(CASE WHEN status = ? THEN 1 ELSE 0 END) AS skipped_count,
+			SUM(file_size) AS input_size, SUM(output_size) AS output_size`,
+			models.StatusCompleted, models.StatusFailed, models.StatusCancelled, models.StatusSkipped).
+		Where("archived_at IS NULL").
+		Group("CASE WHEN batch_id IS NULL OR batch_id = '' THEN id ELSE 0 END, COALESCE(batch_id, '')")
+
+	// The start time is read from the first conversion itself, as SQLite
+	// returns aggregated timestamps as plain text
+	query := r.db.Table("(?) AS g", groups).
+		Select("g.*, conversions.created_at").
+		Joins("JOIN conversions ON conversions.id = g.conversion_id").
+		Order("g.conversion_id DESC").
+		Offset(offset)
+
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	var summaries []models.BatchSummary
+	if err := query.Scan(&summaries).Error; err != nil {
+		r.log.Error("Failed to get batch summaries: %v", err)
+		return nil, fmt.Errorf("failed to get batch summaries: %w", err)
+	}
+
+	return summaries, nil
+}
+
+// GetSpaceSavings sums input and output sizes of completed conversions per
+// week or month. Periods are in local time; SavedBytes and CumulativeSaved
+// are left for the caller. Archived and deleted records are included until
+// they are purged.
+func (r *conversionRepoImpl) GetSpaceSavings(period models.SavingsPeriod) ([]models.SpaceSavings, error) {
+	r.log.Debug("Getting space savings per %s", period)
+
+	start, err := periodStart(database.Dialect(r.db), period)
+	if err != nil {
+		return nil, err
+	}
+
+	var savings []models.SpaceSavings
+	err = r.db.Unscoped().Model(&models.Conversion{}).
+		Select(start+" AS period_start, COUNT(*) AS file_count, SUM(file_size) AS input_size, SUM(output_size) AS output_size").
+		Where("status = ? AND output_size > 0 AND COALESCE(backend, '') <> ?", models.StatusCompleted, models.BackendRename).
+		Group("period_start").
+		Order("period_start ASC").
+		Scan(&savings).Error
+	if err != nil {
+		r.log.Error("Failed to get space savings: %v", err)
+		return nil, fmt.Errorf("failed to get space savings: %w", err)
+	}
+
+	return savings, nil
+}
+
+// periodStart returns the SQL expression for the first day of the week
+// (from Monday) or month of created_at, as YYYY-MM-DD, in the database's
+// dialect. Postgres and MySQL use the time zone of the connection.
+func periodStart(dialect string, period models.SavingsPeriod) (string, error) {
+	switch period {
+	case models.SavingsWeekly:
+		switch dialect {
+		case database.DriverPostgres:
+			return "to_char(date_trunc('week', created_at), 'YYYY-MM-DD')", nil
+		case database.DriverMySQL:
+			return "DATE_FORMAT(created_at - INTERVAL WEEKDAY(created_at) DAY, '%Y-%m-%d')", nil
+		}
+		return "date(created_at, 'localtime', 'weekday 0', '-6 days')", nil
+	case models.SavingsMonthly:
+		switch dialect {
+		case database.DriverPostgres:
+			return "to_char(date_trunc('month', created_at), 'YYYY-MM-DD')", nil
+		case database.DriverMySQL:
+			return "DATE_FORMAT(created_at, '%Y-%m-01')", nil
+		}
+		return "date(created_at, 'localtime', 'start of month')", nil
+	}
+	return "", fmt.Errorf("unknown savings period: %s", period)
+}
+
+// GetCompletedByInputs retrieves the completed conversions of the given input
+// files, querying in chunks to stay within SQLite's variable limit
+func (r *conversionRepoImpl) GetCompletedByInputs(inputPaths []string) ([]models.Conversion, error) {
+	r.log.Debug("Getting completed conversions of %d inputs", len(inputPaths))
+
+	var conversions []models.Conversion
+	for start := 0; start < len(inputPaths); start += createBatchSize {
+		end := start + createBatchSize
+		if end > len(inputPaths) {
+			end = len(inputPaths)
+		}
+
+		var chunk []models.Conversion
+		err := r.db.Where("status = ? AND input_path IN ?", models.StatusCompleted, database.SealAll(r.db, inputPaths[start:end])).
+			Order("created_at DESC").
+			Find(&chunk).Error
+		if err != nil {
+			r.log.Error("Failed to get completed conversions: %v", err)
+			return nil, fmt.Errorf("failed to get completed conversions: %w", err)
+		}
+		conversions = append(conversions, chunk...)
+	}
+
+	return conversions, nil
+}
+
 // GetPending retrieves all pending conversions
 func (r *conversionRepoImpl) GetPending() ([]models.Conversion, error) {
 	r.log.Debug("Getting pending conversions")
@@ -98,6 +329,25 @@ func (r *conversionRepoImpl) GetPending() ([]models.Conversion, error) {
 	return conversions, nil
 }
 
+// GetByStatus retrieves conversions in the given statuses created since the given time
+func (r *conversionRepoImpl) GetByStatus(statuses []models.ConversionStatus, since time.Time, limit int) ([]models.Conversion, error) {
+	r.log.Debug("Getting conversions with status %v since %s", statuses, since.Format(time.RFC3339))
+
+	var conversions []models.Conversion
+	query := r.db.Where("status IN ? AND created_at >= ?", statuses, since).Order("updated_at DESC")
+
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	if err := query.Find(&conversions).Error; err != nil {
+		r.log.Error("Failed to get conversions by status: %v", err)
+		return nil, fmt.Errorf("failed to get conversions by status: %w", err)
+	}
+
+	return conversions, nil
+}
+
 // Delete deletes a conversion record
 func (r *conversionRepoImpl) Delete(id uint) error {
 	r.log.Debug("Deleting conversion record ID: %d", id)
@@ -110,6 +360,79 @@ func (r *conversionRepoImpl) Delete(id uint) error {
 	return nil
 }
 
+// UpdateNote sets the note of a conversion record
+func (r *conversionRepoImpl) UpdateNote(id uint, note string) error {
+	r.log.Debug("Updating note of conversion record ID: %d", id)
+
+	result := r.db.Model(&models.Conversion{}).Where("id = ?", id).Update("note", database.Seal(r.db, note))
+	if result.Error != nil {
+		r.log.Error("Failed to update conversion note: %v", result.Error)
+		return fmt.Errorf("failed to update conversion note: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("conversion %d not found", id)
+	}
+
+	return nil
+}
+
+// UpdateLoudness sets the input and output loudness of a conversion record
+func (r *conversionRepoImpl) UpdateLoudness(id uint, input, output *models.Loudness) error {
+	r.log.Debug("Updating loudness of conversion record ID: %d", id)
+
+	result := r.db.Model(&models.Conversion{}).Where("id = ?", id).
+		Select("input_loudness", "output_loudness").
+		Updates(&models.Conversion{InputLoudness: input, OutputLoudness: output})
+	if result.Error != nil {
+		r.log.Error("Failed to update conversion loudness: %v", result.Error)
+		return fmt.Errorf("failed to update conversion loudness: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("conversion %d not found", id)
+	}
+
+	return nil
+}
+
+// SetArchived archives or restores conversion records
+func (r *conversionRepoImpl) SetArchived(ids []uint, archived bool) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	r.log.Debug("Setting archived=%v on %d conversion records", archived, len(ids))
+
+	var archivedAt *time.Time
+	if archived {
+		now := time.Now()
+		archivedAt = &now
+	}
+
+	err := r.db.Model(&models.Conversion{}).Where("id IN ?", ids).Update("archived_at", archivedAt).Error
+	if err != nil {
+		r.log.Error("Failed to archive conversion records: %v", err)
+		return fmt.Errorf("failed to archive conversion records: %w", err)
+	}
+
+	return nil
+}
+
+// Purge permanently deletes archived and deleted conversion records
+func (r *conversionRepoImpl) Purge() (int64, error) {
+	r.log.Info("Purging archived and deleted conversion records")
+
+	result := r.db.Unscoped().
+		Where("archived_at IS NOT NULL OR deleted_at IS NOT NULL").
+		Delete(&models.Conversion{})
+	if result.Error != nil {
+		r.log.Error("Failed to purge conversion records: %v", result.Error)
+		return 0, fmt.Errorf("failed to purge conversion records: %w", result.Error)
+	}
+
+	r.log.Info("Purged %d conversion records", result.RowsAffected)
+	return result.RowsAffected, nil
+}
+
 // DeleteOlderThan deletes conversions older than the given number of days
 func (r *conversionRepoImpl) DeleteOlderThan(days int) error {
 	r.log.Info("Deleting conversions older than %d days", days)
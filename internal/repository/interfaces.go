@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"time"
+
 	"converzen/internal/models"
 )
 
@@ -9,25 +11,88 @@ type ConversionRepository interface {
 	// Create creates a new conversion record
 	Create(conversion *models.Conversion) error
 
+	// CreateBatch creates multiple conversion records in a single transaction
+	CreateBatch(conversions []*models.Conversion) error
+
 	// Update updates an existing conversion record
 	Update(conversion *models.Conversion) error
 
+	// UpdateBatch updates multiple conversion records in a single transaction
+	UpdateBatch(conversions []*models.Conversion) error
+
 	// GetByID retrieves a conversion by ID
 	GetByID(id uint) (*models.Conversion, error)
 
-	// GetHistory retrieves conversion history with a limit
-	GetHistory(limit int) ([]models.Conversion, error)
+	// GetHistory retrieves conversion history matching a filter, newest first
+	GetHistory(filter models.HistoryFilter) ([]models.Conversion, error)
+
+	// GetByBatch retrieves a page of a batch's conversions in submission order
+	GetByBatch(batchID string, offset, limit int) ([]models.Conversion, error)
+
+	// GetBatchSummaries retrieves a page of history grouped by batch, newest
+	// first. Conversions without a batch each form their own entry.
+	GetBatchSummaries(offset, limit int) ([]models.BatchSummary, error)
+
+	// GetSpaceSavings sums input and output sizes of completed conversions
+	// per week or month, oldest period first
+	GetSpaceSavings(period models.SavingsPeriod) ([]models.SpaceSavings, error)
+
+	// GetCompletedByInputs retrieves the completed conversions of the given
+	// input files, newest first
+	GetCompletedByInputs(inputPaths []string) ([]models.Conversion, error)
 
 	// GetPending retrieves all pending conversions
 	GetPending() ([]models.Conversion, error)
 
-	// Delete deletes a conversion record
+	// GetByStatus retrieves conversions in the given statuses created since
+	// the given time, most recently updated first. limit <= 0 returns all.
+	GetByStatus(statuses []models.ConversionStatus, since time.Time, limit int) ([]models.Conversion, error)
+
+	// Delete deletes a conversion record. The row is kept for statistics
+	// until purged.
 	Delete(id uint) error
 
+	// UpdateNote sets the note of a conversion record
+	UpdateNote(id uint, note string) error
+
+	// UpdateLoudness sets the input and output loudness of a conversion record
+	UpdateLoudness(id uint, input, output *models.Loudness) error
+
+	// SetArchived archives or restores conversion records
+	SetArchived(ids []uint, archived bool) error
+
+	// Purge permanently deletes archived and deleted conversion records,
+	// returning the number removed
+	Purge() (int64, error)
+
 	// DeleteOlderThan deletes conversions older than the given number of days
 	DeleteOlderThan(days int) error
 }
 
+// RecentRepository tracks recently used input files and output directories
+type RecentRepository interface {
+	// Touch marks paths of a kind as used now
+	Touch(kind models.RecentKind, paths []string) error
+
+	// List retrieves the most recently used paths of a kind
+	List(kind models.RecentKind, limit int) ([]models.RecentPath, error)
+
+	// Trim deletes all but the keep most recently used paths of a kind
+	Trim(kind models.RecentKind, keep int) error
+}
+
+// BookmarkRepository stores security-scoped bookmarks of selected folders
+type BookmarkRepository interface {
+	// Save stores the bookmark of a path, replacing an earlier one
+	Save(path string, data []byte) error
+
+	// List retrieves all bookmarks
+	List() ([]models.Bookmark, error)
+
+	// Delete removes the bookmark of a path
+	Delete(path string) error
+}
+
 // SettingsRepository handles settings persistence
 type SettingsRepository interface {
 	// Get retrieves a setting by key
@@ -41,4 +106,10 @@ type SettingsRepository interface {
 
 	// Delete deletes a setting
 	Delete(key string) error
+
+	// RecordChange adds an entry to the settings audit trail
+	RecordChange(key, oldValue, newValue string) error
+
+	// GetChanges retrieves the most recent audit trail entries, newest first
+	GetChanges(limit int) ([]models.SettingChange, error)
 }
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"converzen/internal/logger"
+	"converzen/internal/models"
+)
+
+// recentRepoImpl implements RecentRepository
+type recentRepoImpl struct {
+	db  *gorm.DB
+	log *logger.ComponentLogger
+}
+
+// NewRecentRepository creates a new RecentRepository
+func NewRecentRepository(db *gorm.DB, log *logger.Logger) RecentRepository {
+	return &recentRepoImpl{
+		db:  db,
+		log: log.WithComponent("recent-repo"),
+	}
+}
+
+// Touch marks paths as used now, adding new ones and bumping the use count of known ones
+func (r *recentRepoImpl) Touch(kind models.RecentKind, paths []string) error {
+	if len(paths) == 0 {
+		return nil
+	}
+
+	r.log.Debug("Recording %d recent %s paths", len(paths), kind)
+
+	now := time.Now()
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		for _, path := range paths {
+			recent := models.RecentPath{Kind: kind, Path: path, LastUsedAt: now, UseCount: 1}
+			err := tx.Clauses(clause.OnConflict{
+				Columns: []clause.Column{{Name: "kind"}, {Name: "path"}},
+				DoUpdates: clause.Assignments(map[string]interface{}{
+					"last_used_at": now,
+					"updated_at":   now,
+					"deleted_at":   nil,
+					"use_count":    gorm.Expr("use_count + 1"),
+				}),
+			}).Create(&recent).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		r.log.Error("Failed to record recent paths: %v", err)
+		return fmt.Errorf("failed to record recent paths: %w", err)
+	}
+
+	return nil
+}
+
+// List retrieves the most recently used paths of a kind
+func (r *recentRepoImpl) List(kind models.RecentKind, limit int) ([]models.RecentPath, error) {
+	r.log.Debug("Getting recent %s paths (limit: %d)", kind, limit)
+
+	var recent []models.RecentPath
+	query := r.db.Where("kind = ?", kind).Order("last_used_at DESC")
+
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	if err := query.Find(&recent).Error; err != nil {
+		r.log.Error("Failed to get recent paths: %v", err)
+		return nil, fmt.Errorf("failed to get recent paths: %w", err)
+	}
+
+	return recent, nil
+}
+
+// Trim deletes all but the keep most recently used paths of a kind
+func (r *recentRepoImpl) Trim(kind models.RecentKind, keep int) error {
+	keepIDs := r.db.Model(&models.RecentPath{}).Select("id").
+		Where("kind = ?", kind).Order("last_used_at DESC").Limit(keep)
+
+	// The derived table lets MySQL, which allows neither LIMIT in an IN
+	// subquery nor reading the table being deleted from, run the query
+	result := r.db.Unscoped().Where("kind = ? AND id NOT IN (SELECT id FROM (?) AS keep)", kind, keepIDs).Delete(&models.RecentPath{})
+	if result.Error != nil {
+		r.log.Error("Failed to trim recent paths: %v", result.Error)
+		return fmt.Errorf("failed to trim recent paths: %w", result.Error)
+	}
+
+	if result.RowsAffected > 0 {
+		r.log.Debug("Trimmed %d recent %s paths", result.RowsAffected, kind)
+	}
+	return nil
+}
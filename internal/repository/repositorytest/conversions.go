@@ -0,0 +1,406 @@
+// Package repositorytest provides in-memory repositories for testing
+// services without a database, and OpenDB for tests that need the real
+// repositories' queries.
+package repositorytest
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+
+	"converzen/internal/models"
+	"converzen/internal/repository"
+)
+
+// ConversionRepository is an in-memory repository.ConversionRepository.
+// Records are stored as copies, as the database would. Aggregates that
+// depend on SQLite's date functions aren't supported; use OpenDB for those.
+type ConversionRepository struct {
+	mu      sync.Mutex
+	records map[uint]models.Conversion
+	nextID  uint
+
+	// Err, when set, is returned by every method, to test error handling
+	Err error
+}
+
+var _ repository.ConversionRepository = (*ConversionRepository)(nil)
+
+// NewConversionRepository creates an empty ConversionRepository
+func NewConversionRepository() *ConversionRepository {
+	return &ConversionRepository{records: make(map[uint]models.Conversion), nextID: 1}
+}
+
+// Create stores a new record, assigning its ID and timestamps
+func (r *ConversionRepository) Create(conversion *models.Conversion) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.Err != nil {
+		return r.Err
+	}
+	r.create(conversion)
+	return nil
+}
+
+func (r *ConversionRepository) create(conversion *models.Conversion) {
+	now := time.Now()
+	conversion.ID = r.nextID
+	r.nextID++
+	if conversion.CreatedAt.IsZero() {
+		conversion.CreatedAt = now
+	}
+	conversion.UpdatedAt = now
+	r.records[conversion.ID] = *conversion
+}
+
+// CreateBatch stores several new records
+func (r *ConversionRepository) CreateBatch(conversions []*models.Conversion) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.Err != nil {
+		return r.Err
+	}
+	for _, conversion := range conversions {
+		r.create(conversion)
+	}
+	return nil
+}
+
+// Update replaces a record, creating it if it has no ID yet
+func (r *ConversionRepository) Update(conversion *models.Conversion) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.Err != nil {
+		return r.Err
+	}
+	r.update(conversion)
+	return nil
+}
+
+func (r *ConversionRepository) update(conversion *models.Conversion) {
+	if conversion.ID == 0 {
+		r.create(conversion)
+		return
+	}
+	conversion.UpdatedAt = time.Now()
+	r.records[conversion.ID] = *conversion
+}
+
+// UpdateBatch replaces several records
+func (r *ConversionRepository) UpdateBatch(conversions []*models.Conversion) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.Err != nil {
+		return r.Err
+	}
+	for _, conversion := range conversions {
+		r.update(conversion)
+	}
+	return nil
+}
+
+// GetByID returns a record, or nil if there is none
+func (r *ConversionRepository) GetByID(id uint) (*models.Conversion, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.Err != nil {
+		return nil, r.Err
+	}
+	conversion, ok := r.records[id]
+	if !ok || conversion.DeletedAt.Valid {
+		return nil, nil
+	}
+	return &conversion, nil
+}
+
+// find returns the live records matching keep, ordered by less
+func (r *ConversionRepository) find(keep func(models.Conversion) bool, less func(a, b models.Conversion) int) []models.Conversion {
+	var conversions []models.Conversion
+	for _, conversion := range r.records {
+		if !conversion.DeletedAt.Valid && keep(conversion) {
+			conversions = append(conversions, conversion)
+		}
+	}
+	slices.SortFunc(conversions, less)
+	return conversions
+}
+
+// newestFirst orders records by creation time, then ID, descending
+func newestFirst(a, b models.Conversion) int {
+	if c := b.CreatedAt.Compare(a.CreatedAt); c != 0 {
+		return c
+	}
+	return int(b.ID) - int(a.ID)
+}
+
+// GetHistory returns the records matching a filter, newest first
+func (r *ConversionRepository) GetHistory(filter models.HistoryFilter) ([]models.Conversion, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.Err != nil {
+		return nil, r.Err
+	}
+
+	format := strings.TrimPrefix(strings.ToLower(filter.OutputFormat), ".")
+	search := strings.ToLower(filter.Search)
+	conversions := r.find(func(c models.Conversion) bool {
+		switch {
+		case !filter.IncludeArchived && c.ArchivedAt != nil:
+		case len(filter.Statuses) > 0 && !slices.Contains(filter.Statuses, c.Status):
+		case filter.FileType != "" && c.FileType != filter.FileType:
+		case format != "" && strings.TrimPrefix(strings.ToLower(c.OutputFormat), ".") != format:
+		case filter.From != nil && c.CreatedAt.Before(*filter.From):
+		case filter.To != nil && !c.CreatedAt.Before(*filter.To):
+		case search != "" && !strings.Contains(strings.ToLower(c.InputPath+"\x00"+c.OutputPath+"\x00"+c.Note), search):
+		default:
+			return true
+		}
+		return false
+	}, newestFirst)
+
+	if filter.Limit > 0 && len(conversions) > filter.Limit {
+		conversions = conversions[:filter.Limit]
+	}
+	return conversions, nil
+}
+
+// GetByBatch returns a page of a batch's records in submission order
+func (r *ConversionRepository) GetByBatch(batchID string, offset, limit int) ([]models.Conversion, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.Err != nil {
+		return nil, r.Err
+	}
+
+	conversions := r.find(func(c models.Conversion) bool { return c.BatchID == batchID }, func(a, b models.Conversion) int {
+		return int(a.ID) - int(b.ID)
+	})
+	return page(conversions, offset, limit), nil
+}
+
+// GetBatchSummaries returns a page of unarchived records grouped by batch,
+// newest first
+func (r *ConversionRepository) GetBatchSummaries(offset, limit int) ([]models.BatchSummary, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.Err != nil {
+		return nil, r.Err
+	}
+
+	conversions := r.find(func(c models.Conversion) bool { return c.ArchivedAt == nil }, func(a, b models.Conversion) int {
+		return int(a.ID) - int(b.ID)
+	})
+
+	var summaries []models.BatchSummary
+	batches := make(map[string]int)
+	for _, c := range conversions {
+		i, ok := batches[c.BatchID]
+		if !ok || c.BatchID == "" {
+			i = len(summaries)
+			summaries = append(summaries, models.BatchSummary{ConversionID: c.ID, BatchID: c.BatchID, CreatedAt: c.CreatedAt})
+			if c.BatchID != "" {
+				batches[c.BatchID] = i
+			}
+		}
+
+		summary := &summaries[i]
+		summary.FileCount++
+		summary.InputSize += c.FileSize
+		summary.OutputSize += c.OutputSize
+		switch c.Status {
+		case models.StatusCompleted:
+			summary.CompletedCount++
+		case models.StatusFailed:
+			summary.FailedCount++
+		case models.StatusCancelled:
+			summary.CancelledCount++
+		case models.StatusSkipped:
+			summary.SkippedCount++
+		}
+	}
+
+	slices.Reverse(summaries)
+	return page(summaries, offset, limit), nil
+}
+
+// GetSpaceSavings isn't supported, as periods follow SQLite's date functions
+func (r *ConversionRepository) GetSpaceSavings(period models.SavingsPeriod) ([]models.SpaceSavings, error) {
+	return nil, fmt.Errorf("space savings aren't supported by the in-memory repository; use OpenDB")
+}
+
+// GetCompletedByInputs returns the completed records of the given inputs,
+// newest first
+func (r *ConversionRepository) GetCompletedByInputs(inputPaths []string) ([]models.Conversion, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.Err != nil {
+		return nil, r.Err
+	}
+
+	return r.find(func(c models.Conversion) bool {
+		return c.Status == models.StatusCompleted && slices.Contains(inputPaths, c.InputPath)
+	}, newestFirst), nil
+}
+
+// GetPending returns the pending records
+func (r *ConversionRepository) GetPending() ([]models.Conversion, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.Err != nil {
+		return nil, r.Err
+	}
+
+	return r.find(func(c models.Conversion) bool { return c.Status == models.StatusPending }, func(a, b models.Conversion) int {
+		return int(a.ID) - int(b.ID)
+	}), nil
+}
+
+// GetByStatus returns the records in the given statuses created since the
+// given time, most recently updated first
+func (r *ConversionRepository) GetByStatus(statuses []models.ConversionStatus, since time.Time, limit int) ([]models.Conversion, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.Err != nil {
+		return nil, r.Err
+	}
+
+	conversions := r.find(func(c models.Conversion) bool {
+		return slices.Contains(statuses, c.Status) && !c.CreatedAt.Before(since)
+	}, func(a, b models.Conversion) int {
+		return b.UpdatedAt.Compare(a.UpdatedAt)
+	})
+	return page(conversions, 0, limit), nil
+}
+
+// Delete soft-deletes a record, keeping it until purged
+func (r *ConversionRepository) Delete(id uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.Err != nil {
+		return r.Err
+	}
+
+	if conversion, ok := r.records[id]; ok {
+		conversion.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+		r.records[id] = conversion
+	}
+	return nil
+}
+
+// modify applies change to a live record
+func (r *ConversionRepository) modify(id uint, change func(*models.Conversion)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.Err != nil {
+		return r.Err
+	}
+
+	conversion, ok := r.records[id]
+	if !ok || conversion.DeletedAt.Valid {
+		return fmt.Errorf("conversion %d not found", id)
+	}
+	change(&conversion)
+	conversion.UpdatedAt = time.Now()
+	r.records[id] = conversion
+	return nil
+}
+
+// UpdateNote sets the note of a record
+func (r *ConversionRepository) UpdateNote(id uint, note string) error {
+	return r.modify(id, func(c *models.Conversion) { c.Note = note })
+}
+
+// UpdateLoudness sets the input and output loudness of a record
+func (r *ConversionRepository) UpdateLoudness(id uint, input, output *models.Loudness) error {
+	return r.modify(id, func(c *models.Conversion) {
+		c.InputLoudness = input
+		c.OutputLoudness = output
+	})
+}
+
+// SetArchived archives or restores records
+func (r *ConversionRepository) SetArchived(ids []uint, archived bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.Err != nil {
+		return r.Err
+	}
+
+	var archivedAt *time.Time
+	if archived {
+		now := time.Now()
+		archivedAt = &now
+	}
+	for _, id := range ids {
+		if conversion, ok := r.records[id]; ok && !conversion.DeletedAt.Valid {
+			conversion.ArchivedAt = archivedAt
+			r.records[id] = conversion
+		}
+	}
+	return nil
+}
+
+// Purge removes archived and deleted records, returning the number removed
+func (r *ConversionRepository) Purge() (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.Err != nil {
+		return 0, r.Err
+	}
+
+	var purged int64
+	for id, conversion := range r.records {
+		if conversion.ArchivedAt != nil || conversion.DeletedAt.Valid {
+			delete(r.records, id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+// DeleteOlderThan soft-deletes records older than the given number of days
+func (r *ConversionRepository) DeleteOlderThan(days int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.Err != nil {
+		return r.Err
+	}
+
+	cutoff := time.Now().AddDate(0, 0, -days)
+	for id, conversion := range r.records {
+		if conversion.CreatedAt.Before(cutoff) && !conversion.DeletedAt.Valid {
+			conversion.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+			r.records[id] = conversion
+		}
+	}
+	return nil
+}
+
+// All returns every stored record, including deleted ones, in ID order
+func (r *ConversionRepository) All() []models.Conversion {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	conversions := make([]models.Conversion, 0, len(r.records))
+	for _, conversion := range r.records {
+		conversions = append(conversions, conversion)
+	}
+	slices.SortFunc(conversions, func(a, b models.Conversion) int { return int(a.ID) - int(b.ID) })
+	return conversions
+}
+
+// page returns items[offset:offset+limit], all from offset when limit <= 0
+func page[T any](items []T, offset, limit int) []T {
+	if offset >= len(items) {
+		return nil
+	}
+	items = items[offset:]
+	if limit > 0 && len(items) > limit {
+		items = items[:limit]
+	}
+	return items
+}
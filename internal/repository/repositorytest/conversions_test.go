@@ -0,0 +1,138 @@
+package repositorytest_test
+
+import (
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"converzen/internal/logger"
+	"converzen/internal/models"
+	"converzen/internal/repository"
+	"converzen/internal/repository/repositorytest"
+)
+
+// errTest is the error repositories are made to fail with
+var errTest = errors.New("test error")
+
+// seed stores the same records in each repository, a minute apart
+func seed(t *testing.T, repos ...repository.ConversionRepository) {
+	t.Helper()
+
+	start := time.Now().Add(-time.Hour).Truncate(time.Second)
+	records := []models.Conversion{
+		{InputPath: "/in/a.mov", OutputPath: "/out/a.mp4", InputFormat: ".mov", OutputFormat: ".mp4", FileType: models.FileTypeVideo, Status: models.StatusCompleted, BatchID: "one"},
+		{InputPath: "/in/b.png", OutputPath: "/out/b.webp", InputFormat: ".png", OutputFormat: ".webp", FileType: models.FileTypeImage, Status: models.StatusFailed, BatchID: "one"},
+		{InputPath: "/in/c.mkv", OutputPath: "/out/c.mp4", InputFormat: ".mkv", OutputFormat: ".mp4", FileType: models.FileTypeVideo, Status: models.StatusCompleted, Note: "sent to client"},
+		{InputPath: "/in/d.wav", OutputPath: "/out/d.mp3", InputFormat: ".wav", OutputFormat: ".mp3", FileType: models.FileTypeAudio, Status: models.StatusCancelled, BatchID: "two"},
+	}
+	for _, repo := range repos {
+		for i, record := range records {
+			record.CreatedAt = start.Add(time.Duration(i) * time.Minute)
+			if err := repo.Create(&record); err != nil {
+				t.Fatalf("failed to seed: %v", err)
+			}
+		}
+	}
+}
+
+// inputs returns the input paths of records, in order
+func inputs(conversions []models.Conversion) []string {
+	paths := make([]string, len(conversions))
+	for i, c := range conversions {
+		paths[i] = c.InputPath
+	}
+	return paths
+}
+
+// TestConversionRepositoryMatchesDatabase checks the in-memory repository
+// answers history queries like the database repository
+func TestConversionRepositoryMatchesDatabase(t *testing.T) {
+	log, err := logger.NewWithConsole(filepath.Join(t.TempDir(), "test.log"), logger.DEBUG, nil)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	t.Cleanup(func() { log.Close() })
+
+	memory := repositorytest.NewConversionRepository()
+	database := repository.NewConversionRepository(repositorytest.OpenDB(t), log)
+	seed(t, memory, database)
+
+	since := time.Now().Add(-time.Hour).Add(90 * time.Second)
+	filters := map[string]models.HistoryFilter{
+		"all":       {},
+		"statuses":  {Statuses: []models.ConversionStatus{models.StatusCompleted, models.StatusFailed}},
+		"file type": {FileType: models.FileTypeVideo},
+		"format":    {OutputFormat: "mp4"},
+		"from":      {From: &since},
+		"search":    {Search: "CLIENT"},
+		"limit":     {Limit: 2},
+	}
+	for name, filter := range filters {
+		want, err := database.GetHistory(filter)
+		if err != nil {
+			t.Fatalf("%s: database GetHistory failed: %v", name, err)
+		}
+		got, err := memory.GetHistory(filter)
+		if err != nil {
+			t.Fatalf("%s: GetHistory failed: %v", name, err)
+		}
+		if !slices.Equal(inputs(got), inputs(want)) {
+			t.Errorf("%s: GetHistory = %v, database returns %v", name, inputs(got), inputs(want))
+		}
+	}
+
+	want, err := database.GetByBatch("one", 0, 0)
+	if err != nil {
+		t.Fatalf("database GetByBatch failed: %v", err)
+	}
+	got, err := memory.GetByBatch("one", 0, 0)
+	if err != nil {
+		t.Fatalf("GetByBatch failed: %v", err)
+	}
+	if !slices.Equal(inputs(got), inputs(want)) {
+		t.Errorf("GetByBatch = %v, database returns %v", inputs(got), inputs(want))
+	}
+}
+
+func TestConversionRepositoryArchiveAndPurge(t *testing.T) {
+	repo := repositorytest.NewConversionRepository()
+	seed(t, repo)
+
+	if err := repo.SetArchived([]uint{1}, true); err != nil {
+		t.Fatalf("SetArchived failed: %v", err)
+	}
+	if err := repo.Delete(2); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+
+	history, _ := repo.GetHistory(models.HistoryFilter{})
+	if got := inputs(history); !slices.Equal(got, []string{"/in/d.wav", "/in/c.mkv"}) {
+		t.Errorf("history = %v, want the records neither archived nor deleted", got)
+	}
+	archived, _ := repo.GetHistory(models.HistoryFilter{IncludeArchived: true})
+	if len(archived) != 3 {
+		t.Errorf("history with archived records has %d records, want 3", len(archived))
+	}
+
+	purged, err := repo.Purge()
+	if err != nil || purged != 2 {
+		t.Errorf("Purge = %d, %v; want 2 records removed", purged, err)
+	}
+	if all := repo.All(); len(all) != 2 {
+		t.Errorf("%d records left after purging, want 2", len(all))
+	}
+}
+
+func TestConversionRepositoryErr(t *testing.T) {
+	repo := repositorytest.NewConversionRepository()
+	repo.Err = errTest
+
+	if err := repo.Create(&models.Conversion{}); err != errTest {
+		t.Errorf("Create = %v, want Err", err)
+	}
+	if _, err := repo.GetHistory(models.HistoryFilter{}); err != errTest {
+		t.Errorf("GetHistory = %v, want Err", err)
+	}
+}
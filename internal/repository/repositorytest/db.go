@@ -0,0 +1,31 @@
+package repositorytest
+
+import (
+	"path/filepath"
+	"testing"
+
+	"gorm.io/gorm"
+
+	"converzen/internal/database"
+	"converzen/internal/logger"
+)
+
+// OpenDB opens a migrated SQLite database in a temporary directory, closed
+// when the test ends, for testing the database repositories
+func OpenDB(tb testing.TB) *gorm.DB {
+	tb.Helper()
+
+	dir := tb.TempDir()
+	log, err := logger.NewWithConsole(filepath.Join(dir, "test.log"), logger.DEBUG, nil)
+	if err != nil {
+		tb.Fatalf("failed to create logger: %v", err)
+	}
+	tb.Cleanup(func() { log.Close() })
+
+	db, err := database.New(filepath.Join(dir, "test.db"), log)
+	if err != nil {
+		tb.Fatalf("failed to open database: %v", err)
+	}
+	tb.Cleanup(func() { db.Close() })
+	return db.DB
+}
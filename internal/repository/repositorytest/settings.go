@@ -0,0 +1,127 @@
+package repositorytest
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"converzen/internal/models"
+	"converzen/internal/repository"
+)
+
+// SettingsRepository is an in-memory repository.SettingsRepository. Set
+// records changes in the audit trail like the database repository does.
+type SettingsRepository struct {
+	mu       sync.Mutex
+	settings map[string]string
+	changes  []models.SettingChange
+
+	// Err, when set, is returned by every method, to test error handling
+	Err error
+}
+
+var _ repository.SettingsRepository = (*SettingsRepository)(nil)
+
+// NewSettingsRepository creates a SettingsRepository holding values
+func NewSettingsRepository(values map[string]string) *SettingsRepository {
+	settings := make(map[string]string, len(values))
+	for key, value := range values {
+		settings[key] = value
+	}
+	return &SettingsRepository{settings: settings}
+}
+
+// Get returns a setting, or nil if it isn't set
+func (r *SettingsRepository) Get(key string) (*models.Setting, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.Err != nil {
+		return nil, r.Err
+	}
+
+	value, ok := r.settings[key]
+	if !ok {
+		return nil, nil
+	}
+	return &models.Setting{Key: key, Value: value}, nil
+}
+
+// Set sets a setting, recording the change when the value differs
+func (r *SettingsRepository) Set(key, value string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.Err != nil {
+		return r.Err
+	}
+
+	oldValue, ok := r.settings[key]
+	r.settings[key] = value
+	if !ok || oldValue != value {
+		r.recordChange(key, oldValue, value)
+	}
+	return nil
+}
+
+// GetAll returns every setting, ordered by key
+func (r *SettingsRepository) GetAll() ([]models.Setting, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.Err != nil {
+		return nil, r.Err
+	}
+
+	settings := make([]models.Setting, 0, len(r.settings))
+	for key, value := range r.settings {
+		settings = append(settings, models.Setting{Key: key, Value: value})
+	}
+	slices.SortFunc(settings, func(a, b models.Setting) int { return strings.Compare(a.Key, b.Key) })
+	return settings, nil
+}
+
+// Delete removes a setting
+func (r *SettingsRepository) Delete(key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.Err != nil {
+		return r.Err
+	}
+
+	delete(r.settings, key)
+	return nil
+}
+
+// RecordChange adds an entry to the audit trail
+func (r *SettingsRepository) RecordChange(key, oldValue, newValue string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.Err != nil {
+		return r.Err
+	}
+
+	r.recordChange(key, oldValue, newValue)
+	return nil
+}
+
+func (r *SettingsRepository) recordChange(key, oldValue, newValue string) {
+	r.changes = append(r.changes, models.SettingChange{
+		ID:        uint(len(r.changes) + 1),
+		Key:       key,
+		OldValue:  oldValue,
+		NewValue:  newValue,
+		ChangedAt: time.Now(),
+	})
+}
+
+// GetChanges returns the most recent audit trail entries, newest first
+func (r *SettingsRepository) GetChanges(limit int) ([]models.SettingChange, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.Err != nil {
+		return nil, r.Err
+	}
+
+	changes := slices.Clone(r.changes)
+	slices.Reverse(changes)
+	return page(changes, 0, limit), nil
+}
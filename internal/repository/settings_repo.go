@@ -4,11 +4,15 @@ import (
 	"fmt"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"converzen/internal/logger"
 	"converzen/internal/models"
 )
 
+// maxSettingChanges is the number of audit trail entries kept
+const maxSettingChanges = 1000
+
 // settingsRepoImpl implements SettingsRepository
 type settingsRepoImpl struct {
 	db  *gorm.DB
@@ -23,12 +27,18 @@ func NewSettingsRepository(db *gorm.DB, log *logger.Logger) SettingsRepository {
 	}
 }
 
+// keyIs matches a setting by key. The column is quoted, as KEY is a
+// reserved word in MySQL.
+func keyIs(key string) clause.Eq {
+	return clause.Eq{Column: clause.Column{Name: "key"}, Value: key}
+}
+
 // Get retrieves a setting by key
 func (r *settingsRepoImpl) Get(key string) (*models.Setting, error) {
 	r.log.Debug("Getting setting: %s", key)
 
 	var setting models.Setting
-	if err := r.db.Where("key = ?", key).First(&setting).Error; err != nil {
+	if err := r.db.Where(keyIs(key)).First(&setting).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, nil
 		}
@@ -39,37 +49,46 @@ func (r *settingsRepoImpl) Get(key string) (*models.Setting, error) {
 	return &setting, nil
 }
 
-// Set sets a setting value (creates or updates)
+// Set sets a setting value (creates or updates), recording the change in the
+// audit trail when the value differs
 func (r *settingsRepoImpl) Set(key, value string) error {
 	r.log.Debug("Setting: %s = %s", key, value)
 
-	// Try to find existing setting
-	var setting models.Setting
-	result := r.db.Where("key = ?", key).First(&setting)
-
-	if result.Error == gorm.ErrRecordNotFound {
-		// Create new setting
-		setting = models.Setting{
-			Key:   key,
-			Value: value,
-		}
-		if err := r.db.Create(&setting).Error; err != nil {
-			r.log.Error("Failed to create setting: %v", err)
-			return fmt.Errorf("failed to create setting: %w", err)
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// Try to find existing setting
+		var setting models.Setting
+		result := tx.Where(keyIs(key)).First(&setting)
+
+		var oldValue string
+		if result.Error == gorm.ErrRecordNotFound {
+			// Create new setting
+			setting = models.Setting{
+				Key:   key,
+				Value: value,
+			}
+			if err := tx.Create(&setting).Error; err != nil {
+				r.log.Error("Failed to create setting: %v", err)
+				return fmt.Errorf("failed to create setting: %w", err)
+			}
+		} else if result.Error != nil {
+			r.log.Error("Failed to query setting: %v", result.Error)
+			return fmt.Errorf("failed to query setting: %w", result.Error)
+		} else {
+			if setting.Value == value {
+				return nil
+			}
+
+			// Update existing setting
+			oldValue = setting.Value
+			setting.Value = value
+			if err := tx.Save(&setting).Error; err != nil {
+				r.log.Error("Failed to update setting: %v", err)
+				return fmt.Errorf("failed to update setting: %w", err)
+			}
 		}
-	} else if result.Error != nil {
-		r.log.Error("Failed to query setting: %v", result.Error)
-		return fmt.Errorf("failed to query setting: %w", result.Error)
-	} else {
-		// Update existing setting
-		setting.Value = value
-		if err := r.db.Save(&setting).Error; err != nil {
-			r.log.Error("Failed to update setting: %v", err)
-			return fmt.Errorf("failed to update setting: %w", err)
-		}
-	}
 
-	return nil
+		return recordChange(tx, key, oldValue, value)
+	})
 }
 
 // GetAll retrieves all settings
@@ -89,10 +108,60 @@ func (r *settingsRepoImpl) GetAll() ([]models.Setting, error) {
 func (r *settingsRepoImpl) Delete(key string) error {
 	r.log.Debug("Deleting setting: %s", key)
 
-	if err := r.db.Where("key = ?", key).Delete(&models.Setting{}).Error; err != nil {
+	if err := r.db.Where(keyIs(key)).Delete(&models.Setting{}).Error; err != nil {
 		r.log.Error("Failed to delete setting: %v", err)
 		return fmt.Errorf("failed to delete setting: %w", err)
 	}
 
 	return nil
 }
+
+// RecordChange adds an entry to the settings audit trail
+func (r *settingsRepoImpl) RecordChange(key, oldValue, newValue string) error {
+	r.log.Debug("Recording change of %s", key)
+
+	if err := recordChange(r.db, key, oldValue, newValue); err != nil {
+		r.log.Error("Failed to record setting change: %v", err)
+		return err
+	}
+	return nil
+}
+
+// GetChanges retrieves the most recent audit trail entries
+func (r *settingsRepoImpl) GetChanges(limit int) ([]models.SettingChange, error) {
+	r.log.Debug("Getting setting changes (limit: %d)", limit)
+
+	var changes []models.SettingChange
+	query := r.db.Order("id DESC")
+
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	if err := query.Find(&changes).Error; err != nil {
+		r.log.Error("Failed to get setting changes: %v", err)
+		return nil, fmt.Errorf("failed to get setting changes: %w", err)
+	}
+
+	return changes, nil
+}
+
+// recordChange inserts an audit trail entry and drops entries beyond
+// maxSettingChanges
+func recordChange(db *gorm.DB, key, oldValue, newValue string) error {
+	change := models.SettingChange{
+		Key:      key,
+		OldValue: oldValue,
+		NewValue: newValue,
+	}
+	if err := db.Create(&change).Error; err != nil {
+		return fmt.Errorf("failed to record setting change: %w", err)
+	}
+
+	if change.ID > maxSettingChanges {
+		if err := db.Where("id <= ?", change.ID-maxSettingChanges).Delete(&models.SettingChange{}).Error; err != nil {
+			return fmt.Errorf("failed to trim setting changes: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,250 @@
+package services
+
+import (
+	"encoding/csv"
+	"fmt"
+	"image"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"converzen/internal/logger"
+	"converzen/internal/models"
+	"converzen/pkg/ffmpeg"
+)
+
+// maxAnalysisWorkers caps concurrent probes of an analysis batch
+const maxAnalysisWorkers = 4
+
+// analysisServiceImpl implements AnalysisService
+type analysisServiceImpl struct {
+	fileService FileService
+	ffmpeg      *ffmpeg.FFmpeg
+	log         *logger.ComponentLogger
+}
+
+// NewAnalysisService creates a new AnalysisService. ff may be nil, in which
+// case media files are reported without stream details.
+func NewAnalysisService(fileService FileService, ff *ffmpeg.FFmpeg, log *logger.Logger) AnalysisService {
+	return &analysisServiceImpl{
+		fileService: fileService,
+		ffmpeg:      ff,
+		log:         log.WithComponent("analysis-service"),
+	}
+}
+
+// AnalyzeFiles probes files without converting them, reporting their format
+// details and any problems found. progressCallback, if set, is called with
+// the number of files probed so far.
+func (s *analysisServiceImpl) AnalyzeFiles(files []string, progressCallback func(done, total int)) *models.AnalysisReport {
+	s.log.Info("Analyzing %d files", len(files))
+
+	report := &models.AnalysisReport{Files: make([]models.FileAnalysis, len(files))}
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		done int
+	)
+	slots := make(chan struct{}, maxAnalysisWorkers)
+	for i, path := range files {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(i int, path string) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			report.Files[i] = s.analyzeFile(path)
+
+			mu.Lock()
+			done++
+			if progressCallback != nil {
+				progressCallback(done, len(files))
+			}
+			mu.Unlock()
+		}(i, path)
+	}
+	wg.Wait()
+
+	for _, file := range report.Files {
+		report.TotalSize += file.Size
+		if len(file.Problems) > 0 {
+			report.ProblemCount++
+		}
+	}
+
+	s.log.Info("Analyzed %d files, %d with problems", len(files), report.ProblemCount)
+	return report
+}
+
+// analyzeFile probes a single file
+func (s *analysisServiceImpl) analyzeFile(path string) models.FileAnalysis {
+	analysis := models.FileAnalysis{Path: path, Name: filepath.Base(path), FileType: models.FileTypeUnknown}
+
+	info, err := s.fileService.GetFileInfo(path)
+	if err != nil {
+		analysis.Problems = append(analysis.Problems, fmt.Sprintf("can't be read: %v", err))
+		return analysis
+	}
+	analysis.FileType = info.Type
+	analysis.Size = info.Size
+	analysis.Container = strings.TrimPrefix(info.Extension, ".")
+
+	if info.Size == 0 {
+		analysis.Problems = append(analysis.Problems, "file is empty")
+		return analysis
+	}
+
+	switch info.Type {
+	case models.FileTypeVideo, models.FileTypeAudio:
+		s.analyzeMedia(&analysis)
+	case models.FileTypeImage:
+		analyzeImage(&analysis)
+	case models.FileTypeUnknown:
+		analysis.Problems = append(analysis.Problems, "unsupported file type")
+	}
+	return analysis
+}
+
+// analyzeMedia fills in the stream details of a video or audio file
+func (s *analysisServiceImpl) analyzeMedia(analysis *models.FileAnalysis) {
+	if s.ffmpeg == nil {
+		return
+	}
+
+	media, err := s.ffmpeg.GetMediaInfo(analysis.Path)
+	if err != nil {
+		analysis.Problems = append(analysis.Problems, fmt.Sprintf("can't be probed, the file may be damaged: %v", err))
+		return
+	}
+	analysis.Container = media.Container
+	analysis.Duration = media.Duration
+	analysis.Bitrate = media.Bitrate
+
+	for _, stream := range media.Streams {
+		switch {
+		case stream.Type == "video" && analysis.VideoCodec == "":
+			analysis.VideoCodec = stream.Codec
+			analysis.Width = stream.Width
+			analysis.Height = stream.Height
+			analysis.FrameRate = stream.FrameRate
+			analysis.HDR = stream.HDR
+		case stream.Type == "audio" && analysis.AudioCodec == "":
+			analysis.AudioCodec = stream.Codec
+		}
+	}
+
+	if len(media.Streams) == 0 {
+		analysis.Problems = append(analysis.Problems, "no streams found")
+		return
+	}
+	if analysis.FileType == models.FileTypeVideo && analysis.VideoCodec == "" {
+		analysis.Problems = append(analysis.Problems, "no video stream")
+	}
+	if analysis.FileType == models.FileTypeAudio && analysis.AudioCodec == "" {
+		analysis.Problems = append(analysis.Problems, "no audio stream")
+	}
+	if media.Duration <= 0 {
+		analysis.Problems = append(analysis.Problems, "duration unknown, progress can't be reported")
+	}
+	if analysis.Width%2 != 0 || analysis.Height%2 != 0 {
+		analysis.Problems = append(analysis.Problems, "odd dimensions, H.264 and HEVC encoders may reject them")
+	}
+	if analysis.HDR != "" {
+		analysis.Problems = append(analysis.Problems, analysis.HDR+" video, colors change when converted to SDR formats")
+	}
+}
+
+// decodableImageFormats are the image extensions with a Go decoder, whose
+// files are damaged or mislabeled if they can't be decoded
+var decodableImageFormats = map[string]bool{
+	".png": true, ".jpg": true, ".jpeg": true, ".gif": true,
+	".bmp": true, ".tif": true, ".tiff": true, ".webp": true,
+}
+
+// analyzeImage fills in the dimensions of an image
+func analyzeImage(analysis *models.FileAnalysis) {
+	file, err := os.Open(analysis.Path)
+	if err != nil {
+		analysis.Problems = append(analysis.Problems, fmt.Sprintf("can't be read: %v", err))
+		return
+	}
+	defer file.Close()
+
+	config, format, err := image.DecodeConfig(file)
+	if err != nil {
+		// Formats without a Go decoder, e.g. HEIC, are converted with
+		// other tools and aren't a problem
+		if err != image.ErrFormat || decodableImageFormats[strings.ToLower(filepath.Ext(analysis.Path))] {
+			analysis.Problems = append(analysis.Problems, fmt.Sprintf("can't be decoded, the file may be damaged: %v", err))
+		}
+		return
+	}
+	analysis.Container = format
+	analysis.Width = config.Width
+	analysis.Height = config.Height
+}
+
+// analysisCSVHeader lists the columns of an exported analysis report
+var analysisCSVHeader = []string{
+	"Path", "Type", "Container", "Size (bytes)", "Duration (s)", "Width", "Height",
+	"Video codec", "Audio codec", "Bitrate (bit/s)", "Frame rate", "HDR", "Problems",
+}
+
+// WriteAnalysisCSV saves an analysis report as a CSV file, adding the
+// extension to path if it's missing
+func WriteAnalysisCSV(path string, report models.AnalysisReport) (string, error) {
+	if !strings.EqualFold(filepath.Ext(path), ".csv") {
+		path += ".csv"
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to create report: %w", err)
+	}
+	defer file.Close()
+
+	w := csv.NewWriter(file)
+	w.Write(analysisCSVHeader)
+	for _, f := range report.Files {
+		w.Write([]string{
+			f.Path,
+			string(f.FileType),
+			f.Container,
+			strconv.FormatInt(f.Size, 10),
+			formatOptionalFloat(f.Duration, 2),
+			formatOptionalInt(int64(f.Width)),
+			formatOptionalInt(int64(f.Height)),
+			f.VideoCodec,
+			f.AudioCodec,
+			formatOptionalInt(f.Bitrate),
+			formatOptionalFloat(f.FrameRate, 3),
+			f.HDR,
+			strings.Join(f.Problems, "; "),
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return "", fmt.Errorf("failed to write report: %w", err)
+	}
+	return path, nil
+}
+
+// formatOptionalInt formats n, leaving unknown (zero) values empty
+func formatOptionalInt(n int64) string {
+	if n == 0 {
+		return ""
+	}
+	return strconv.FormatInt(n, 10)
+}
+
+// formatOptionalFloat formats f with the given precision, leaving unknown
+// (zero) values empty
+func formatOptionalFloat(f float64, precision int) string {
+	if f == 0 {
+		return ""
+	}
+	return strconv.FormatFloat(f, 'f', precision, 64)
+}
@@ -0,0 +1,172 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"converzen/internal/logger"
+	"converzen/internal/models"
+	"converzen/pkg/ffmpeg"
+)
+
+// audioConverter handles audio file conversion using FFmpeg
+type audioConverter struct {
+	ffmpeg *ffmpeg.FFmpeg
+	log    *logger.ComponentLogger
+}
+
+// NewAudioConverter creates a new audio converter using FFmpeg
+func NewAudioConverter(ff *ffmpeg.FFmpeg, log *logger.Logger) Converter {
+	return &audioConverter{
+		ffmpeg: ff,
+		log:    log.WithComponent("audio-converter"),
+	}
+}
+
+// Convert converts an audio file to another format
+func (c *audioConverter) Convert(ctx context.Context, job models.ConversionJob, progressCallback func(progress float64)) (*models.ConversionResult, error) {
+	c.log.Info("Starting audio conversion: %s -> %s", job.InputPath, job.OutputPath)
+	startTime := time.Now()
+
+	result := &models.ConversionResult{
+		InputPath:  job.InputPath,
+		OutputPath: job.OutputPath,
+	}
+
+	// Validate input file exists
+	if _, err := os.Stat(job.InputPath); os.IsNotExist(err) {
+		result.ErrorMessage = fmt.Sprintf("Input file not found: %s", job.InputPath)
+		c.log.Error("%s", result.ErrorMessage)
+		return result, fmt.Errorf("%s", result.ErrorMessage)
+	}
+
+	// Check output directory exists
+	outputDir := filepath.Dir(job.OutputPath)
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		result.ErrorMessage = fmt.Sprintf("Failed to create output directory: %v", err)
+		c.log.Error("%s", result.ErrorMessage)
+		return result, fmt.Errorf("%s", result.ErrorMessage)
+	}
+
+	// Check if output file already exists
+	if !job.OverwriteOutput {
+		if _, err := os.Stat(job.OutputPath); err == nil {
+			result.ErrorMessage = fmt.Sprintf("Output file already exists: %s", job.OutputPath)
+			c.log.Error("%s", result.ErrorMessage)
+			return result, fmt.Errorf("%s", result.ErrorMessage)
+		}
+	}
+
+	if err := checkTrim(job); err != nil {
+		result.ErrorMessage = err.Error()
+		c.log.Error("%s", result.ErrorMessage)
+		return result, err
+	}
+
+	outputFormat := strings.TrimPrefix(strings.ToLower(filepath.Ext(job.OutputPath)), ".")
+
+	// Embedded cover art is dropped, since most audio containers can't hold it
+	opts := ffmpeg.ConvertOptions{
+		InputPath:   job.InputPath,
+		OutputPath:  job.OutputPath,
+		Overwrite:   job.OverwriteOutput,
+		Metadata:    job.Metadata.Tags(),
+		NoVideo:     true,
+		AudioCodec:  ffmpeg.GetDefaultAudioCodec(outputFormat),
+		AudioFilter: strings.Join(audioFilters(job), ","),
+		Threads:     job.Threads,
+		LowPriority: job.LowPriority,
+		ReadLimit:   job.IOLimit(),
+		Log:         job.Log,
+	}
+	if speed := speedFactor(job.Speed); speed != 1 {
+		opts.TimeScale = 1 / speed
+	}
+	if job.PreviewLength > 0 || job.TrimStart > 0 || job.TrimEnd > 0 {
+		duration, _ := c.ffmpeg.GetDuration(job.InputPath)
+		opts.StartTime, opts.MaxDuration = jobSpan(job, time.Duration(duration*float64(time.Second)))
+	}
+
+	if err := c.ffmpeg.Convert(ctx, opts, progressCallback); err != nil {
+		result.ErrorMessage = err.Error()
+		c.log.Error("Audio conversion failed: %v", err)
+		return result, err
+	}
+
+	if stat, err := os.Stat(job.OutputPath); err == nil {
+		result.OutputSize = stat.Size()
+	}
+	describeMedia(c.ffmpeg, result)
+
+	result.Success = true
+	result.Method = models.MethodReencode
+	result.Duration = time.Since(startTime).Milliseconds()
+
+	c.log.Info("Audio conversion completed in %dms: %s", result.Duration, job.OutputPath)
+	return result, nil
+}
+
+// Preview converts a short slice of an audio file using FFmpeg
+func (c *audioConverter) Preview(ctx context.Context, job models.ConversionJob, length time.Duration, progressCallback func(progress float64)) (*models.ConversionResult, error) {
+	job.PreviewLength = length
+	return c.Convert(ctx, job, progressCallback)
+}
+
+// EstimateSize predicts the output size of an audio file from a short trial encode
+func (c *audioConverter) EstimateSize(ctx context.Context, job models.ConversionJob) (*models.SizeEstimate, error) {
+	return estimateSizeBySample(ctx, c.ffmpeg, job, c.Preview)
+}
+
+// MeasureLoudness returns the EBU R128 loudness of a file's first audio stream
+func (c *audioConverter) MeasureLoudness(ctx context.Context, path string) (*models.Loudness, error) {
+	loudness, err := c.ffmpeg.MeasureLoudness(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	return &models.Loudness{
+		IntegratedLUFS: loudness.Integrated,
+		TruePeakDBTP:   loudness.TruePeak,
+		RangeLU:        loudness.Range,
+		MeasuredAt:     time.Now(),
+	}, nil
+}
+
+// SupportedInputFormats returns the list of supported input audio formats
+func (c *audioConverter) SupportedInputFormats() []string {
+	formats := make([]string, 0, len(models.AudioFormats))
+	for format := range models.AudioFormats {
+		formats = append(formats, strings.TrimPrefix(format, "."))
+	}
+	return formats
+}
+
+// SupportedOutputFormats returns the list of supported output formats for audio
+func (c *audioConverter) SupportedOutputFormats(inputFormat string) []string {
+	return models.AudioOutputFormats
+}
+
+// CanConvert checks if conversion is possible between formats
+func (c *audioConverter) CanConvert(inputFormat, outputFormat string) bool {
+	inputFormat = strings.ToLower(strings.TrimPrefix(inputFormat, "."))
+	outputFormat = strings.ToLower(strings.TrimPrefix(outputFormat, "."))
+
+	if !models.AudioFormats["."+inputFormat] {
+		return false
+	}
+
+	for _, format := range models.AudioOutputFormats {
+		if format == outputFormat {
+			return true
+		}
+	}
+	return false
+}
+
+// Backend reports that jobs are converted with FFmpeg
+func (c *audioConverter) Backend(job models.ConversionJob) string {
+	return "ffmpeg"
+}
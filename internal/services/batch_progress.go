@@ -0,0 +1,104 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"runtime"
+	"sync"
+
+	"converzen/internal/models"
+)
+
+const (
+	// batchChunkSize is the number of files prepared and converted together.
+	// Bounding it keeps memory flat for batches of thousands of files.
+	batchChunkSize = 500
+
+	// batchResultsPageSize is the number of results returned with a finished
+	// batch; the remainder is fetched with GetBatchResults
+	batchResultsPageSize = 100
+)
+
+// maxVideoWorkers caps concurrent video conversions, since each FFmpeg
+// process already uses several cores on its own
+const maxVideoWorkers = 2
+
+// maxDocumentWorkers caps concurrent document conversions, since each one
+// starts a full LibreOffice instance
+const maxDocumentWorkers = 2
+
+// maxEbookWorkers caps concurrent e-book conversions, since each one starts
+// a Calibre process that is slow to start and memory hungry
+const maxEbookWorkers = 2
+
+// maxAudioWorkers caps concurrent audio conversions. Audio encoders are
+// mostly single-threaded, so more of them can run side by side than videos.
+const maxAudioWorkers = 4
+
+// imageWorkers returns the number of concurrent image conversions.
+// Image conversions are cheap and single-threaded, so use every core.
+func imageWorkers() int {
+	return runtime.NumCPU()
+}
+
+// workerLimitOverrides replaces the built-in concurrency limits, e.g. from
+// the configuration file
+var workerLimitOverrides map[models.FileType]int
+
+// SetWorkerLimits overrides the concurrency limits of file types. It must be
+// called before conversion services are created.
+func SetWorkerLimits(limits map[models.FileType]int) {
+	workerLimitOverrides = limits
+}
+
+// workerLimit returns the concurrency limit for a file type
+func workerLimit(fileType models.FileType) int {
+	if limit, ok := workerLimitOverrides[fileType]; ok && limit > 0 {
+		return limit
+	}
+	switch fileType {
+	case models.FileTypeVideo:
+		return maxVideoWorkers
+	case models.FileTypeAudio:
+		return maxAudioWorkers
+	case models.FileTypeEbook:
+		return maxEbookWorkers
+	case models.FileTypeDocument:
+		return maxDocumentWorkers
+	}
+	return imageWorkers()
+}
+
+// batchProgress aggregates per-file progress from concurrent workers into
+// a single overall progress value for the batch
+type batchProgress struct {
+	mu      sync.Mutex
+	perFile []float64
+	total   float64
+}
+
+// newBatchProgress creates an aggregator for a batch of n files
+func newBatchProgress(n int) *batchProgress {
+	return &batchProgress{perFile: make([]float64, n)}
+}
+
+// Set records the progress (0-100) of file i and returns the overall progress (0-100)
+func (b *batchProgress) Set(i int, progress float64) float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if progress > 100 {
+		progress = 100
+	}
+	b.total += progress - b.perFile[i]
+	b.perFile[i] = progress
+
+	return b.total / float64(len(b.perFile))
+}
+
+// newBatchID returns a random identifier grouping a batch's history records
+func newBatchID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,171 @@
+package services
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"converzen/internal/models"
+)
+
+// maxRenameSuffix bounds the " (n)" suffixes tried for a name that is taken
+const maxRenameSuffix = 1000
+
+// RenameFiles renames files in place with a naming template, keeping their
+// extensions. Names that are taken, on disk or earlier in the batch, get a
+// " (2)", " (3)", ... suffix. Renamed files are recorded in history as one
+// batch, which UndoRename reverts.
+func (s *conversionServiceImpl) RenameFiles(request models.RenameRequest) (*models.RenameResult, error) {
+	if len(request.Files) == 0 {
+		return nil, fmt.Errorf("no files to rename")
+	}
+	if _, err := renderNameTemplate(request.Template, request.Files[0], request.StartIndex); err != nil {
+		return nil, err
+	}
+
+	renames := make([][2]string, 0, len(request.Files))
+	for i, oldPath := range request.Files {
+		name, err := renderNameTemplate(request.Template, oldPath, request.StartIndex+i)
+		if err != nil {
+			renames = append(renames, [2]string{oldPath, ""})
+			continue
+		}
+		newPath := filepath.Join(filepath.Dir(oldPath), name+filepath.Ext(oldPath))
+		renames = append(renames, [2]string{oldPath, newPath})
+	}
+
+	if request.Preview {
+		return s.planRenames(renames), nil
+	}
+	s.log.Info("Renaming %d files with template %q", len(request.Files), request.Template)
+	return s.renameFiles(renames)
+}
+
+// UndoRename reverts the renames of a batch recorded by RenameFiles. The
+// undo is recorded as a batch of its own, so it can be undone in turn.
+func (s *conversionServiceImpl) UndoRename(batchID string) (*models.RenameResult, error) {
+	records, err := s.repo.GetByBatch(batchID, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var renames [][2]string
+	for _, record := range records {
+		if record.Backend == models.BackendRename && record.Status == models.StatusCompleted {
+			renames = append(renames, [2]string{record.OutputPath, record.InputPath})
+		}
+	}
+	if len(renames) == 0 {
+		return nil, fmt.Errorf("batch %s has no renamed files", batchID)
+	}
+
+	s.log.Info("Undoing %d renames of batch %s", len(renames), batchID)
+	return s.renameFiles(renames)
+}
+
+// planRenames resolves the target names of renames without renaming anything
+func (s *conversionServiceImpl) planRenames(renames [][2]string) *models.RenameResult {
+	result := &models.RenameResult{Items: make([]models.RenameItem, 0, len(renames))}
+	claimed := make(map[string]bool)
+
+	for _, rename := range renames {
+		item := models.RenameItem{OldPath: rename[0]}
+		if rename[1] == "" {
+			item.Error = "naming template can't be applied to this file"
+		} else if newPath, err := availableName(rename[0], rename[1], claimed); err != nil {
+			item.Error = err.Error()
+		} else {
+			item.NewPath = newPath
+			claimed[strings.ToLower(newPath)] = true
+		}
+
+		if item.Error != "" {
+			result.FailCount++
+		}
+		result.Items = append(result.Items, item)
+	}
+	return result
+}
+
+// renameFiles renames files and records the renames in history
+func (s *conversionServiceImpl) renameFiles(renames [][2]string) (*models.RenameResult, error) {
+	result := s.planRenames(renames)
+	result.BatchID = newBatchID()
+
+	var records []*models.Conversion
+	for i := range result.Items {
+		item := &result.Items[i]
+		if item.Error != "" || item.NewPath == item.OldPath {
+			continue
+		}
+
+		fileInfo, err := s.fileService.GetFileInfo(item.OldPath)
+		if err == nil {
+			err = os.Rename(item.OldPath, item.NewPath)
+		}
+		if err != nil {
+			s.log.Warn("Failed to rename %s: %v", item.OldPath, err)
+			item.Error = err.Error()
+			result.FailCount++
+			continue
+		}
+		item.Renamed = true
+		result.RenamedCount++
+
+		now := time.Now()
+		records = append(records, &models.Conversion{
+			BatchID:      result.BatchID,
+			InputPath:    item.OldPath,
+			OutputPath:   item.NewPath,
+			InputFormat:  fileInfo.Extension,
+			OutputFormat: fileInfo.Extension,
+			FileType:     fileInfo.Type,
+			FileSize:     fileInfo.Size,
+			OutputSize:   fileInfo.Size,
+			Status:       models.StatusCompleted,
+			Backend:      models.BackendRename,
+			Progress:     100,
+			StartedAt:    &now,
+			CompletedAt:  &now,
+		})
+	}
+
+	// The files are renamed either way, so a failure to record them is
+	// reported without failing the batch
+	if len(records) > 0 {
+		if err := s.repo.CreateBatch(records); err != nil {
+			s.log.Error("Failed to record renames of batch %s: %v", result.BatchID, err)
+		}
+	} else {
+		result.BatchID = ""
+	}
+
+	s.log.Info("Renamed %d files, %d failed", result.RenamedCount, result.FailCount)
+	return result, nil
+}
+
+// availableName returns newPath, or newPath with a " (n)" suffix if it is
+// taken on disk or claimed earlier in the batch. Renaming a file to its own
+// name, or to a different case of it, isn't a collision.
+func availableName(oldPath, newPath string, claimed map[string]bool) (string, error) {
+	oldInfo, err := os.Stat(oldPath)
+	if err != nil {
+		return "", err
+	}
+
+	ext := filepath.Ext(newPath)
+	base := strings.TrimSuffix(newPath, ext)
+	candidate := newPath
+	for n := 2; n <= maxRenameSuffix; n++ {
+		if !claimed[strings.ToLower(candidate)] {
+			info, err := os.Stat(candidate)
+			if os.IsNotExist(err) || (err == nil && os.SameFile(info, oldInfo)) {
+				return candidate, nil
+			}
+		}
+		candidate = fmt.Sprintf("%s (%d)%s", base, n, ext)
+	}
+	return "", fmt.Errorf("no free name found for %s", filepath.Base(newPath))
+}
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"converzen/internal/bookmark"
+	"converzen/internal/logger"
+	"converzen/internal/repository"
+)
+
+// bookmarkServiceImpl implements BookmarkService
+type bookmarkServiceImpl struct {
+	repo repository.BookmarkRepository
+	log  *logger.ComponentLogger
+}
+
+// NewBookmarkService creates a new BookmarkService
+func NewBookmarkService(repo repository.BookmarkRepository, log *logger.Logger) BookmarkService {
+	return &bookmarkServiceImpl{
+		repo: repo,
+		log:  log.WithComponent("bookmark-service"),
+	}
+}
+
+// Remember stores a bookmark for a folder the user just selected. Builds
+// that aren't sandboxed don't need bookmarks and store nothing.
+func (s *bookmarkServiceImpl) Remember(path string) error {
+	if !bookmark.Supported() || path == "" {
+		return nil
+	}
+
+	data, err := bookmark.Create(path)
+	if err != nil {
+		s.log.Warn("Failed to bookmark %s: %v", path, err)
+		return err
+	}
+	s.log.Debug("Bookmarked %s", path)
+	return s.repo.Save(path, data)
+}
+
+// RestoreAll resolves the stored bookmarks to regain access to their folders
+// for this session. Bookmarks that no longer resolve are deleted, and those
+// of moved folders or stale ones are recreated. It returns the number of
+// folders restored.
+func (s *bookmarkServiceImpl) RestoreAll() int {
+	if !bookmark.Supported() {
+		return 0
+	}
+
+	bookmarks, err := s.repo.List()
+	if err != nil {
+		return 0
+	}
+
+	restored := 0
+	for _, stored := range bookmarks {
+		path, stale, err := bookmark.Resolve(stored.Data)
+		if err != nil {
+			s.log.Warn("Dropping bookmark of %s: %v", stored.Path, err)
+			s.repo.Delete(stored.Path)
+			continue
+		}
+		restored++
+
+		if path != stored.Path {
+			s.log.Info("Bookmarked folder %s moved to %s", stored.Path, path)
+			s.repo.Delete(stored.Path)
+			stale = true
+		}
+		if stale {
+			if data, err := bookmark.Create(path); err == nil {
+				s.repo.Save(path, data)
+			}
+		}
+	}
+
+	s.log.Info("Restored access to %d of %d bookmarked folders", restored, len(bookmarks))
+	return restored
+}
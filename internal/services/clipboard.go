@@ -0,0 +1,139 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"converzen/internal/models"
+	"converzen/pkg/httpclient"
+)
+
+const (
+	// maxClipboardText bounds the clipboard text searched for media; larger
+	// copies are documents, not lists of files
+	maxClipboardText = 64 << 10 // 64 KiB
+
+	// maxClipboardItems bounds the files and URLs offered from one copy
+	maxClipboardItems = 500
+)
+
+// ParseClipboardMedia finds the media in copied text: existing files of
+// supported types, one per line, and http(s) links to media files. It
+// returns nil if the text has none.
+func ParseClipboardMedia(text string) *models.ClipboardMedia {
+	if len(text) > maxClipboardText {
+		return nil
+	}
+
+	media := &models.ClipboardMedia{}
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(text, "\n") {
+		// File managers quote copied paths containing spaces
+		line = strings.Trim(strings.TrimSpace(line), `"'`)
+		if line == "" {
+			continue
+		}
+
+		if mediaURL, ok := clipboardURL(line); ok && !seen[mediaURL] {
+			seen[mediaURL] = true
+			media.URLs = append(media.URLs, mediaURL)
+		} else if path, ok := clipboardPath(line); ok && !seen[path] {
+			seen[path] = true
+			media.Paths = append(media.Paths, path)
+		}
+		if len(media.Paths)+len(media.URLs) >= maxClipboardItems {
+			break
+		}
+	}
+
+	if len(media.Paths) == 0 && len(media.URLs) == 0 {
+		return nil
+	}
+	return media
+}
+
+// clipboardURL reports whether text is an http(s) link to a supported media file
+func clipboardURL(text string) (string, bool) {
+	if !strings.HasPrefix(text, "http://") && !strings.HasPrefix(text, "https://") {
+		return "", false
+	}
+	parsed, err := url.Parse(text)
+	if err != nil || parsed.Host == "" {
+		return "", false
+	}
+	if models.GetFileType(strings.ToLower(path.Ext(parsed.Path))) == models.FileTypeUnknown {
+		return "", false
+	}
+	return parsed.String(), true
+}
+
+// clipboardPath reports whether text is an existing file of a supported
+// type, given as an absolute path or a file:// URL
+func clipboardPath(text string) (string, bool) {
+	if strings.HasPrefix(text, "file://") {
+		parsed, err := url.Parse(text)
+		if err != nil {
+			return "", false
+		}
+		text = filepath.FromSlash(parsed.Path)
+	}
+	if !filepath.IsAbs(text) {
+		return "", false
+	}
+	if models.GetFileType(strings.ToLower(filepath.Ext(text))) == models.FileTypeUnknown {
+		return "", false
+	}
+	if info, err := os.Stat(text); err != nil || !info.Mode().IsRegular() {
+		return "", false
+	}
+	return filepath.Clean(text), true
+}
+
+// DownloadMedia downloads a media URL into dir under the file name of the
+// URL, numbered if a file of that name exists, and returns the saved path
+func DownloadMedia(ctx context.Context, clients *httpclient.Factory, mediaURL, dir string) (string, error) {
+	normalized, ok := clipboardURL(mediaURL)
+	if !ok {
+		return "", fmt.Errorf("not a link to a supported media file: %s", mediaURL)
+	}
+	parsed, _ := url.Parse(normalized)
+	name := filepath.Base(path.Base(parsed.Path))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, mediaURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create download request: %w", err)
+	}
+	resp, err := clients.Client(0).Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to download %s: %w", name, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to download %s: %s", name, resp.Status)
+	}
+
+	outputPath, err := freePath(filepath.Join(dir, name))
+	if err != nil {
+		return "", err
+	}
+	file, err := os.OpenFile(outputPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return "", fmt.Errorf("failed to save %s: %w", name, err)
+	}
+	_, err = io.Copy(file, resp.Body)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(file.Name())
+		return "", fmt.Errorf("failed to download %s: %w", name, err)
+	}
+	return file.Name(), nil
+}
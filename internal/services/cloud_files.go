@@ -0,0 +1,45 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"converzen/internal/models"
+	"converzen/pkg/cloudfile"
+)
+
+// materializeInput has the cloud storage provider download a job's
+// online-only input, reporting progress with the downloading status. The
+// download can be cancelled with CancelConversion.
+func (s *conversionServiceImpl) materializeInput(id uint, job models.ConversionJob, progressCallback func(progress models.ConversionProgress)) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s.mu.Lock()
+	s.activeConversions[id] = cancel
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.activeConversions, id)
+		s.mu.Unlock()
+	}()
+
+	s.log.Info("Downloading online-only file: %s", job.InputPath)
+	err := cloudfile.Materialize(ctx, job.InputPath, func(fraction float64) {
+		if progressCallback != nil {
+			progressCallback(models.ConversionProgress{
+				ID:        id,
+				InputPath: job.InputPath,
+				Progress:  fraction * 100,
+				Status:    string(models.StatusDownloading),
+			})
+		}
+	})
+	if err == nil || errors.Is(err, context.Canceled) {
+		return err
+	}
+
+	s.log.Error("Failed to download %s: %v", job.InputPath, err)
+	return fmt.Errorf("%s is an online-only file that couldn't be downloaded (%v); make it available offline in your cloud storage app and try again", job.InputPath, err)
+}
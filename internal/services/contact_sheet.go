@@ -0,0 +1,86 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"converzen/internal/models"
+	"converzen/pkg/ffmpeg"
+)
+
+const (
+	// Contact sheet defaults and limits
+	defaultSheetColumns = 4
+	defaultSheetRows    = 4
+	defaultSheetWidth   = 320
+	maxSheetColumns     = 10
+	maxSheetRows        = 10
+	minSheetWidth       = 80
+	maxSheetWidth       = 960
+
+	// contactSheetTimeout bounds creating a contact sheet, which seeks once
+	// per frame
+	contactSheetTimeout = 2 * time.Minute
+)
+
+// CreateContactSheet saves a grid of evenly spaced frames of a video as a
+// single image and returns the saved path
+func (s *thumbnailServiceImpl) CreateContactSheet(path string, options models.ContactSheetOptions) (string, error) {
+	if s.ffmpeg == nil {
+		return "", fmt.Errorf("contact sheets require FFmpeg")
+	}
+
+	fileInfo, err := s.fileService.GetFileInfo(path)
+	if err != nil {
+		return "", err
+	}
+	if fileInfo.Type != models.FileTypeVideo {
+		return "", fmt.Errorf("contact sheets can only be made from video files")
+	}
+
+	if options.Columns == 0 {
+		options.Columns = defaultSheetColumns
+	}
+	if options.Rows == 0 {
+		options.Rows = defaultSheetRows
+	}
+	if options.Width == 0 {
+		options.Width = defaultSheetWidth
+	}
+	if options.Columns < 1 || options.Columns > maxSheetColumns || options.Rows < 1 || options.Rows > maxSheetRows {
+		return "", fmt.Errorf("contact sheets have 1-%d columns and 1-%d rows", maxSheetColumns, maxSheetRows)
+	}
+	if options.Width < minSheetWidth || options.Width > maxSheetWidth {
+		return "", fmt.Errorf("contact sheet frames are %d-%d pixels wide", minSheetWidth, maxSheetWidth)
+	}
+
+	format := strings.TrimPrefix(strings.ToLower(options.Format), ".")
+	if format == "" {
+		format = "jpg"
+	}
+	if !frameGrabFormats[format] {
+		return "", fmt.Errorf("unsupported image format for contact sheets: %s", format)
+	}
+
+	outputPath, err := stillPath(path, options.OutputPath, "contact-sheet", format)
+	if err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), contactSheetTimeout)
+	defer cancel()
+	err = s.ffmpeg.ContactSheet(ctx, path, outputPath, ffmpeg.ContactSheetOptions{
+		Columns:    options.Columns,
+		Rows:       options.Rows,
+		Width:      options.Width,
+		Timestamps: options.Timestamps,
+	})
+	if err != nil {
+		os.Remove(outputPath)
+		return "", err
+	}
+	return outputPath, nil
+}
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"converzen/internal/models"
+)
+
+// converterGroup presents several converters for one file type as a single
+// Converter, routing each job to the first member that can convert it
+type converterGroup struct {
+	converters []Converter
+}
+
+// NewConverterGroup creates a Converter that delegates to the given converters
+func NewConverterGroup(converters ...Converter) Converter {
+	return &converterGroup{converters: converters}
+}
+
+// Convert converts a file with the first member that supports its formats
+func (g *converterGroup) Convert(ctx context.Context, job models.ConversionJob, progressCallback func(progress float64)) (*models.ConversionResult, error) {
+	inputFormat := filepath.Ext(job.InputPath)
+	outputFormat := job.OutputFormat
+	if outputFormat == "" {
+		outputFormat = filepath.Ext(job.OutputPath)
+	}
+
+	for _, converter := range g.converters {
+		if converter.CanConvert(inputFormat, outputFormat) {
+			return converter.Convert(ctx, job, progressCallback)
+		}
+	}
+
+	result := &models.ConversionResult{
+		InputPath:    job.InputPath,
+		OutputPath:   job.OutputPath,
+		ErrorMessage: fmt.Sprintf("Cannot convert %s to %s", inputFormat, strings.TrimPrefix(outputFormat, ".")),
+	}
+	return result, fmt.Errorf("%s", result.ErrorMessage)
+}
+
+// SupportedInputFormats returns the input formats supported by any member
+func (g *converterGroup) SupportedInputFormats() []string {
+	var formats []string
+	for _, converter := range g.converters {
+		formats = appendUnique(formats, converter.SupportedInputFormats()...)
+	}
+	return formats
+}
+
+// SupportedOutputFormats returns the output formats supported by any member
+func (g *converterGroup) SupportedOutputFormats(inputFormat string) []string {
+	var formats []string
+	for _, converter := range g.converters {
+		formats = appendUnique(formats, converter.SupportedOutputFormats(inputFormat)...)
+	}
+	return formats
+}
+
+// CanConvert checks if any member can convert between the formats
+func (g *converterGroup) CanConvert(inputFormat, outputFormat string) bool {
+	for _, converter := range g.converters {
+		if converter.CanConvert(inputFormat, outputFormat) {
+			return true
+		}
+	}
+	return false
+}
+
+// Backend names the backend of the member that would convert job
+func (g *converterGroup) Backend(job models.ConversionJob) string {
+	inputFormat := filepath.Ext(job.InputPath)
+	outputFormat := job.OutputFormat
+	if outputFormat == "" {
+		outputFormat = filepath.Ext(job.OutputPath)
+	}
+
+	for _, converter := range g.converters {
+		if converter.CanConvert(inputFormat, outputFormat) {
+			return converterBackend(converter, job)
+		}
+	}
+	return ""
+}
+
+// appendUnique appends the values not already in list
+func appendUnique(list []string, values ...string) []string {
+	for _, value := range values {
+		found := false
+		for _, existing := range list {
+			if existing == value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			list = append(list, value)
+		}
+	}
+	return list
+}
@@ -2,26 +2,66 @@ package services
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
+	"unicode/utf8"
 
 	"converzen/internal/logger"
 	"converzen/internal/models"
 	"converzen/internal/repository"
+	"converzen/pkg/scratch"
 )
 
 // conversionServiceImpl orchestrates file conversions
 type conversionServiceImpl struct {
-	fileService    FileService
-	videoConverter Converter
-	imageConverter Converter
-	repo           repository.ConversionRepository
-	log            *logger.ComponentLogger
+	fileService       FileService
+	videoConverter    Converter
+	imageConverter    Converter
+	audioConverter    Converter
+	subtitleConverter Converter
+	ebookConverter    Converter
+	documentConverter Converter
+	repo              repository.ConversionRepository
+	settings          SettingsService
+	analysis          AnalysisService
+	log               *logger.ComponentLogger
+
+	// Worker instance conversions are sent to, nil to convert locally
+	remote atomic.Pointer[RemoteWorker]
+
+	// Guards videoConverter and audioConverter, which SetMediaConverters
+	// replaces when the backend is switched
+	convertersMu sync.RWMutex
 
 	// Active conversions tracking
 	activeConversions map[uint]context.CancelFunc
 	mu                sync.Mutex
+
+	// Background/low-power throttling
+	throttle *throttle
+
+	// startedAt bounds the queue state to this session's conversions
+	startedAt time.Time
+
+	// Per-conversion tool output, kept for diagnosing failures later
+	jobLogDir     string
+	pruneLogsOnce sync.Once
+
+	// Sleep or shutdown waiting to run after a batch
+	powerMu    sync.Mutex
+	powerTimer *time.Timer
+
+	// Latest preview file, replaced by the next preview
+	previewMu sync.Mutex
+	preview   string
 }
 
 // NewConversionService creates a new ConversionService
@@ -29,21 +69,120 @@ func NewConversionService(
 	fileService FileService,
 	videoConverter Converter,
 	imageConverter Converter,
+	audioConverter Converter,
+	subtitleConverter Converter,
+	ebookConverter Converter,
+	documentConverter Converter,
 	repo repository.ConversionRepository,
+	settings SettingsService,
+	analysis AnalysisService,
+	jobLogDir string,
 	log *logger.Logger,
 ) ConversionService {
 	return &conversionServiceImpl{
 		fileService:       fileService,
 		videoConverter:    videoConverter,
 		imageConverter:    imageConverter,
+		audioConverter:    audioConverter,
+		subtitleConverter: subtitleConverter,
+		ebookConverter:    ebookConverter,
+		documentConverter: documentConverter,
 		repo:              repo,
+		settings:          settings,
+		analysis:          analysis,
 		log:               log.WithComponent("conversion-service"),
 		activeConversions: make(map[uint]context.CancelFunc),
+		throttle:          newThrottle(),
+		startedAt:         time.Now(),
+		jobLogDir:         jobLogDir,
 	}
 }
 
 // ConvertFile converts a single file
 func (s *conversionServiceImpl) ConvertFile(job models.ConversionJob) (*models.ConversionResult, error) {
+	settings := s.userSettings()
+	applyJobSettings(&job, settings)
+
+	if s.throttle.Acquire() {
+		job.LowPriority = true
+	}
+	defer s.throttle.Release()
+
+	return s.convertFile(job, nil, stallOptionsFromSettings(settings), nil)
+}
+
+// newConversionRecord builds a pending history record for a job
+func newConversionRecord(job models.ConversionJob, fileInfo *models.FileInfo) *models.Conversion {
+	encoded, _ := json.Marshal(job)
+	return &models.Conversion{
+		Settings:      string(encoded),
+		InputPath:     job.InputPath,
+		OutputPath:    job.OutputPath,
+		InputFormat:   fileInfo.Extension,
+		OutputFormat:  job.OutputFormat,
+		FileType:      fileInfo.Type,
+		FileSize:      fileInfo.Size,
+		EstimatedSize: job.EstimatedSize,
+		Status:        models.StatusPending,
+	}
+}
+
+// converterFor returns the converter for a file type
+func (s *conversionServiceImpl) converterFor(fileType models.FileType) (Converter, error) {
+	var converter Converter
+	switch fileType {
+	case models.FileTypeVideo:
+		converter = s.video()
+	case models.FileTypeImage:
+		converter = s.imageConverter
+	case models.FileTypeAudio:
+		converter = s.audio()
+	case models.FileTypeSubtitle:
+		converter = s.subtitleConverter
+	case models.FileTypeEbook:
+		converter = s.ebookConverter
+	case models.FileTypeDocument:
+		converter = s.documentConverter
+	default:
+		return nil, fmt.Errorf("unsupported file type: %s", fileType)
+	}
+	if converter == nil {
+		return nil, fmt.Errorf("no converter available for %s files", fileType)
+	}
+	return converter, nil
+}
+
+// converters returns the service's available converters
+func (s *conversionServiceImpl) converters() []Converter {
+	var converters []Converter
+	for _, converter := range []Converter{
+		s.video(), s.imageConverter, s.audio(),
+		s.subtitleConverter, s.ebookConverter, s.documentConverter,
+	} {
+		if converter != nil {
+			converters = append(converters, converter)
+		}
+	}
+	return converters
+}
+
+// converterBackend names the tool converter uses for job, or "" if it can't tell
+func converterBackend(converter Converter, job models.ConversionJob) string {
+	if namer, ok := converter.(BackendNamer); ok {
+		return namer.Backend(job)
+	}
+	return ""
+}
+
+// convertFile converts a single file, reporting per-file progress and stalls
+// through progressCallback and retrying stalled jobs when enabled in settings.
+// conversion is the file's pre-created history record, or nil to create one.
+func (s *conversionServiceImpl) convertFile(
+	job models.ConversionJob,
+	conversion *models.Conversion,
+	opts stallOptions,
+	progressCallback func(progress models.ConversionProgress),
+) (*models.ConversionResult, error) {
 	s.log.Info("Converting file: %s", job.InputPath)
 
 	// Get file info to determine converter
@@ -52,50 +191,172 @@ func (s *conversionServiceImpl) ConvertFile(job models.ConversionJob) (*models.C
 		return nil, err
 	}
 
-	// Create database record
+	// Create or claim the database record
 	now := time.Now()
-	conversion := &models.Conversion{
-		InputPath:    job.InputPath,
-		OutputPath:   job.OutputPath,
-		InputFormat:  fileInfo.Extension,
-		OutputFormat: job.OutputFormat,
-		FileType:     fileInfo.Type,
-		FileSize:     fileInfo.Size,
-		Status:       models.StatusProcessing,
-		StartedAt:    &now,
-	}
+	if conversion == nil {
+		conversion = newConversionRecord(job, fileInfo)
+		conversion.Status = models.StatusProcessing
+		conversion.StartedAt = &now
 
-	if err := s.repo.Create(conversion); err != nil {
-		s.log.Error("Failed to create conversion record: %v", err)
+		if err := s.repo.Create(conversion); err != nil {
+			s.log.Error("Failed to create conversion record: %v", err)
+		}
+	} else {
+		conversion.Status = models.StatusProcessing
+		conversion.StartedAt = &now
+
+		if err := s.repo.Update(conversion); err != nil {
+			s.log.Error("Failed to update conversion record: %v", err)
+		}
 	}
 
 	// Select appropriate converter
-	var converter Converter
-	switch fileInfo.Type {
-	case models.FileTypeVideo:
-		converter = s.videoConverter
-	case models.FileTypeImage:
-		converter = s.imageConverter
-	default:
-		return nil, fmt.Errorf("unsupported file type: %s", fileInfo.Type)
+	converter, err := s.converterFor(fileInfo.Type)
+	if err != nil {
+		return nil, err
 	}
+	conversion.Backend = converterBackend(converter, job)
 
-	// Perform conversion
-	result, err := converter.Convert(job, func(progress float64) {
-		conversion.Progress = progress
-		s.repo.Update(conversion)
-	})
+	// Pipelines pick a converter for each step
+	if len(job.Pipeline) > 0 {
+		if err := validatePipeline(job); err != nil {
+			return s.finishUnconverted(conversion, job, nil, err)
+		}
+		converter = &pipelineConverter{service: s}
+	} else if remote := s.remote.Load(); remote.handles(fileInfo.Type) {
+		converter = &remoteConverter{Converter: converter, worker: remote}
+		conversion.Backend = remote.backend()
+	}
+
+	jobLog := s.openJobLog(conversion.ID)
+	if jobLog != nil {
+		defer jobLog.Close()
+		jobLog.Printf("Converting %s -> %s with %s", job.InputPath, job.OutputPath, conversion.Backend)
+		job.Log = jobLog
+	}
+
+	// The pre-conversion script may skip or fail the job, or replace the
+	// input, e.g. fetching the full file for a placeholder. It can be
+	// cancelled with CancelConversion like the conversion itself.
+	if job.PreScript != "" {
+		ctx, cancel := context.WithCancel(context.Background())
+		s.mu.Lock()
+		s.activeConversions[conversion.ID] = cancel
+		s.mu.Unlock()
+
+		skip, err := s.runPreScript(ctx, job)
+
+		s.mu.Lock()
+		delete(s.activeConversions, conversion.ID)
+		s.mu.Unlock()
+		cancel()
+
+		if err == nil && !skip {
+			fileInfo, err = s.fileService.GetFileInfo(job.InputPath)
+		}
+		if err != nil || skip {
+			return s.finishUnconverted(conversion, job, jobLog, err)
+		}
+		conversion.FileSize = fileInfo.Size
+	}
+
+	// Online-only cloud files are downloaded before converting, as
+	// converters may fail or time out reading a placeholder
+	if fileInfo.Placeholder {
+		if err := s.materializeInput(conversion.ID, job, progressCallback); err != nil {
+			return s.finishUnconverted(conversion, job, jobLog, err)
+		}
+	}
+
+	// Outputs checked for duplicates are written to a hidden file first
+	outputPath := job.OutputPath
+	if job.DuplicateOutputs != models.DuplicatesAllow {
+		job.OutputPath = partialOutputPath(outputPath)
+	}
+
+	// Inputs on slow or unreliable shares are converted from a local copy
+	runJob := job
+	var cache *inputCache
+	if job.CacheInput {
+		if !job.OverwriteOutput && s.fileService.FileExists(job.OutputPath) {
+			return s.finishUnconverted(conversion, job, jobLog, fmt.Errorf("output file already exists: %s", job.OutputPath))
+		}
+		if cache, err = s.cacheInput(conversion.ID, job, progressCallback); err != nil {
+			return s.finishUnconverted(conversion, job, jobLog, err)
+		}
+		defer cache.remove()
+		runJob = cache.job
+	}
+
+	outputExisted := s.fileService.FileExists(runJob.OutputPath)
+
+	// Perform conversion, retrying when a stalled attempt was killed
+	var result *models.ConversionResult
+	var outcome attemptOutcome
+	for attempt := 0; ; attempt++ {
+		result, outcome, err = s.runConversion(converter, conversion, runJob, opts, progressCallback)
+		if outcome != attemptStalled || attempt >= opts.MaxRetries {
+			break
+		}
+
+		s.log.Warn("Retrying stalled conversion %d (attempt %d of %d): %s",
+			conversion.ID, attempt+2, opts.MaxRetries+1, job.InputPath)
+		if jobLog != nil {
+			jobLog.Printf("Stalled, retrying (attempt %d of %d)", attempt+2, opts.MaxRetries+1)
+		}
+
+		// Remove the partial output left behind by the killed attempt
+		if !outputExisted {
+			os.Remove(runJob.OutputPath)
+		}
+		conversion.Progress = 0
+	}
+
+	if cache != nil && err == nil {
+		err = s.writeBack(conversion.ID, cache, job, result, progressCallback)
+	}
+
+	if job.OutputPath != outputPath {
+		if err == nil {
+			if err = s.settleOutput(job, outputPath, result); err == nil {
+				conversion.OutputPath = result.OutputPath
+			}
+		} else {
+			os.Remove(job.OutputPath)
+		}
+	}
 
 	// Update database record
 	completedAt := time.Now()
 	conversion.CompletedAt = &completedAt
+	if result != nil {
+		conversion.Steps = result.Steps
+	}
 
 	if err != nil {
+		err = explainFailure(err, s.userSettings().Language)
+		if result != nil {
+			result.ErrorMessage = err.Error()
+		}
 		conversion.Status = models.StatusFailed
+		if outcome == attemptCancelled {
+			conversion.Status = models.StatusCancelled
+		}
 		conversion.ErrorMessage = err.Error()
 	} else {
 		conversion.Status = models.StatusCompleted
+		if result.Skipped {
+			conversion.Status = models.StatusSkipped
+		}
 		conversion.OutputSize = result.OutputSize
+		result.SetSizes(fileInfo.Size)
+	}
+	if jobLog != nil {
+		if err != nil {
+			jobLog.Printf("Finished with status %s: %v", conversion.Status, err)
+		} else {
+			jobLog.Printf("Completed, wrote %d bytes", conversion.OutputSize)
+		}
 	}
 
 	if updateErr := s.repo.Update(conversion); updateErr != nil {
@@ -105,86 +366,924 @@ func (s *conversionServiceImpl) ConvertFile(job models.ConversionJob) (*models.C
 	return result, err
 }
 
-// ConvertBatch converts multiple files
+// finishUnconverted records a job that ended before converting, skipped when
+// err is nil and failed otherwise
+func (s *conversionServiceImpl) finishUnconverted(conversion *models.Conversion, job models.ConversionJob, jobLog *jobLog, err error) (*models.ConversionResult, error) {
+	completedAt := time.Now()
+	conversion.CompletedAt = &completedAt
+	conversion.Status = models.StatusSkipped
+	if err != nil {
+		conversion.Status = models.StatusFailed
+		if errors.Is(err, context.Canceled) {
+			conversion.Status = models.StatusCancelled
+		}
+		conversion.ErrorMessage = err.Error()
+	}
+	if jobLog != nil {
+		jobLog.Printf("Finished with status %s without converting", conversion.Status)
+	}
+	if updateErr := s.repo.Update(conversion); updateErr != nil {
+		s.log.Error("Failed to update conversion record: %v", updateErr)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	return &models.ConversionResult{
+		InputPath:  job.InputPath,
+		OutputPath: job.OutputPath,
+		Skipped:    true,
+	}, nil
+}
+
+// attemptOutcome describes how a single conversion attempt ended
+type attemptOutcome int
+
+const (
+	attemptFinished  attemptOutcome = iota // Converter returned on its own
+	attemptStalled                         // Killed because it stopped progressing
+	attemptCancelled                       // Cancelled through CancelConversion
+)
+
+// runConversion runs a single conversion attempt while watching it for stalls
+func (s *conversionServiceImpl) runConversion(
+	converter Converter,
+	conversion *models.Conversion,
+	job models.ConversionJob,
+	opts stallOptions,
+	progressCallback func(progress models.ConversionProgress),
+) (*models.ConversionResult, attemptOutcome, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s.mu.Lock()
+	s.activeConversions[conversion.ID] = cancel
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.activeConversions, conversion.ID)
+		s.mu.Unlock()
+	}()
+
+	tracker := newProgressTracker()
+	done := make(chan struct{})
+	var killed atomic.Bool
+
+	go watchStall(tracker, opts.Timeout, done, func(progress float64) {
+		s.log.Warn("Conversion %d stalled at %.1f%% (no progress for %s): %s",
+			conversion.ID, progress, opts.Timeout, job.InputPath)
+
+		if progressCallback != nil {
+			progressCallback(models.ConversionProgress{
+				ID:        conversion.ID,
+				InputPath: job.InputPath,
+				Progress:  progress,
+				Status:    string(models.StatusStalled),
+			})
+		}
+
+		if opts.AutoRetry {
+			killed.Store(true)
+			cancel()
+		}
+	})
+
+	result, err := converter.Convert(ctx, job, func(progress float64) {
+		tracker.Update(progress)
+		conversion.Progress = progress
+		s.repo.Update(conversion)
+
+		if progressCallback != nil {
+			progressCallback(models.ConversionProgress{
+				ID:        conversion.ID,
+				InputPath: job.InputPath,
+				Progress:  progress,
+				Status:    string(models.StatusProcessing),
+			})
+		}
+	})
+	close(done)
+
+	if err != nil && ctx.Err() != nil {
+		if killed.Load() {
+			return result, attemptStalled, err
+		}
+		return result, attemptCancelled, err
+	}
+	return result, attemptFinished, err
+}
+
+// userSettings returns the user's settings, falling back to defaults if they can't be loaded
+func (s *conversionServiceImpl) userSettings() *models.UserSettings {
+	if s.settings != nil {
+		settings, err := s.settings.GetSettings()
+		if err == nil {
+			return settings
+		}
+		s.log.Warn("Failed to load settings, using defaults: %v", err)
+	}
+
+	defaults := models.DefaultUserSettings()
+	return &defaults
+}
+
+// applyJobSettings fills in the job options that come from user settings
+func applyJobSettings(job *models.ConversionJob, settings *models.UserSettings) {
+	if job.Scaler == "" {
+		job.Scaler = settings.ImageScaler
+	}
+	job.Threads = settings.FFmpegThreads
+	job.LowPriority = settings.LowPriority
+
+	// Professional .mov codec chosen in advanced settings
+	if job.VideoCodec == models.CodecDefault && jobOutputFormat(*job) == "mov" {
+		job.VideoCodec = settings.MovVideoCodec
+	}
+	if job.ProResProfile == "" {
+		job.ProResProfile = settings.ProResProfile
+	}
+	if job.DNxHRProfile == "" {
+		job.DNxHRProfile = settings.DNxHRProfile
+	}
+}
+
+// jobOutputFormat returns a job's output format without a leading dot
+func jobOutputFormat(job models.ConversionJob) string {
+	format := job.OutputFormat
+	if format == "" {
+		format = filepath.Ext(job.OutputPath)
+	}
+	return strings.TrimPrefix(strings.ToLower(format), ".")
+}
+
+// batchItem is a single file of a batch conversion, prepared up front so the
+// batch's history records can be inserted in one transaction
+type batchItem struct {
+	job        models.ConversionJob
+	fileType   models.FileType
+	conversion *models.Conversion
+	skipped    bool // Left unconverted by a rule or the file's settings
+	err        error
+}
+
+// ConvertBatch converts multiple files using a worker pool. Image files are
+// converted with one worker per CPU core, video files with a lower cap.
+// Files are processed in chunks so memory stays flat for very large batches;
+// each result is streamed through progressCallback as it completes and only
+// the first page of results is returned.
 func (s *conversionServiceImpl) ConvertBatch(request models.BatchConversionRequest, progressCallback func(progress models.ConversionProgress)) (*models.BatchConversionResult, error) {
 	s.log.Info("Starting batch conversion of %d files", len(request.Files))
 	startTime := time.Now()
+	settings := s.userSettings()
+
+	// Check every destination before converting anything
+	if err := validateFileSettings(request); err != nil {
+		return nil, err
+	}
+	if err := validateOverwrite(request); err != nil {
+		return nil, err
+	}
+	if err := validateQuality(request.Quality, request.CRF); err != nil {
+		return nil, err
+	}
+	for _, dir := range batchOutputDirs(request, settings.OutputRoutes) {
+		if err := s.fileService.CheckOutputDirectory(dir); err != nil {
+			s.log.Error("Output directory check failed: %v", err)
+			return nil, err
+		}
+	}
+	if request.PreScript != "" {
+		if err := checkScript(request.PreScript); err != nil {
+			return nil, err
+		}
+	}
+	if request.PostActions != nil {
+		if err := validatePostActions(*request.PostActions); err != nil {
+			return nil, err
+		}
+	}
+	if len(request.Rules) > 0 {
+		if s.analysis == nil {
+			return nil, fmt.Errorf("rules require file analysis, which isn't available")
+		}
+		if err := validateRules(request.Rules); err != nil {
+			return nil, err
+		}
+	}
 
+	total := len(request.Files)
 	result := &models.BatchConversionResult{
-		TotalFiles: len(request.Files),
-		Results:    make([]models.ConversionResult, 0, len(request.Files)),
+		BatchID:    newBatchID(),
+		TotalFiles: total,
+		Results:    make([]models.ConversionResult, min(total, batchResultsPageSize)),
 	}
 
-	for i, inputPath := range request.Files {
+	opts := stallOptionsFromSettings(settings)
+	aggregate := newBatchProgress(total)
+	limits := map[models.FileType]chan struct{}{
+		models.FileTypeVideo:    make(chan struct{}, workerLimit(models.FileTypeVideo)),
+		models.FileTypeImage:    make(chan struct{}, workerLimit(models.FileTypeImage)),
+		models.FileTypeAudio:    make(chan struct{}, workerLimit(models.FileTypeAudio)),
+		models.FileTypeEbook:    make(chan struct{}, workerLimit(models.FileTypeEbook)),
+		models.FileTypeDocument: make(chan struct{}, workerLimit(models.FileTypeDocument)),
+	}
+
+	var mu sync.Mutex
+	var completed int
+	var outputs []batchOutput
+	claimed := make(map[string]bool)
+
+	for start := 0; start < total; start += batchChunkSize {
+		end := min(start+batchChunkSize, total)
+		items, records := s.prepareBatch(request, result.BatchID, settings, claimed, start, end)
+
+		workers := max(workerLimit(models.FileTypeVideo), workerLimit(models.FileTypeImage))
+		workers = min(workers, len(items))
+
+		indexes := make(chan int)
+		var wg sync.WaitGroup
+
+		for w := 0; w < workers; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := range indexes {
+					convResult := s.convertBatchItem(items[i-start], limits, opts, func(progress models.ConversionProgress) {
+						if progressCallback == nil {
+							return
+						}
+						// Per-file progress is folded into the overall batch progress
+						if progress.Status == string(models.StatusProcessing) {
+							progress.Progress = aggregate.Set(i, progress.Progress)
+						}
+						progressCallback(progress)
+					})
+
+					status := models.StatusCompleted
+					mu.Lock()
+					switch {
+					case convResult.Success:
+						result.SuccessCount++
+						if request.PostActions != nil {
+							outputs = append(outputs, batchOutput{inputPath: convResult.InputPath, outputPath: convResult.OutputPath})
+						}
+					case convResult.Skipped:
+						result.SkippedCount++
+						status = models.StatusSkipped
+					default:
+						result.FailCount++
+						status = models.StatusFailed
+					}
+					if i < len(result.Results) {
+						result.Results[i] = convResult
+					}
+					completed++
+					s.log.Debug("Batch progress: %d/%d files completed", completed, total)
+					mu.Unlock()
+
+					// Report progress along with the file's result
+					overall := aggregate.Set(i, 100)
+					if progressCallback != nil {
+						progressCallback(models.ConversionProgress{
+							InputPath: convResult.InputPath,
+							Progress:  overall,
+							Status:    string(status),
+							Result:    &convResult,
+						})
+					}
+				}
+			}()
+		}
+
+		for i := start; i < end; i++ {
+			indexes <- i
+		}
+		close(indexes)
+		wg.Wait()
+
+		s.finalizeBatch(records)
+	}
+
+	result.HasMoreResults = total > len(result.Results)
+	result.TotalDuration = time.Since(startTime).Milliseconds()
+
+	s.log.Info("Batch conversion completed: %d success, %d failed, %d skipped, %dms total",
+		result.SuccessCount, result.FailCount, result.SkippedCount, result.TotalDuration)
+
+	if request.PostActions != nil {
+		result.Warnings, result.PowerActionAt = s.runPostActions(*request.PostActions, outputs)
+	}
+
+	return result, nil
+}
+
+// batchJob builds the job for file i of a batch request
+func batchJob(request models.BatchConversionRequest, i int, outputPath string) models.ConversionJob {
+	job := models.ConversionJob{
+		InputPath:         request.Files[i],
+		OutputPath:        outputPath,
+		OutputFormat:      request.OutputFormat,
+		KeepAllAudio:      request.KeepAllAudioTracks,
+		VideoCodec:        request.VideoCodec,
+		ProResProfile:     request.ProResProfile,
+		DNxHRProfile:      request.DNxHRProfile,
+		Deinterlace:       request.Deinterlace,
+		Speed:             request.Speed,
+		Reverse:           request.Reverse,
+		Stabilize:         request.Stabilize,
+		StabilizeStrength: request.StabilizeStrength,
+		PreserveAlpha:     request.PreserveAlpha,
+		Quality:           request.Quality,
+		CRF:               request.CRF,
+		Salvage:           request.Salvage,
+		TargetSizeMB:      request.TargetSizeMB,
+		TwoPass:           request.TwoPass,
+		SocialPreset:      request.SocialPreset,
+		BurnIn:            request.BurnIn,
+		SubtitleCharset:   request.SubtitleCharset,
+		PreScript:         request.PreScript,
+		DuplicateOutputs:  request.DuplicateOutputs,
+		IOLimitMBps:       request.IOLimitMBps,
+		CacheInput:        request.CacheInput,
+		MaxWidth:          request.MaxWidth,
+		MaxHeight:         request.MaxHeight,
+		Scaler:            request.Scaler,
+		ColorProfile:      request.ColorProfile,
+	}
+	if i < len(request.StreamSelections) {
+		job.Streams = request.StreamSelections[i]
+	}
+	if i < len(request.EstimatedSizes) {
+		job.EstimatedSize = request.EstimatedSizes[i]
+	}
+	return job
+}
+
+// prepareBatch validates files [start, end) of a batch, builds their jobs and
+// inserts a history record for every file in a single transaction. Files that
+// fail validation are recorded as failed so the batch's history is complete.
+// claimed collects the batch's output paths for the rename overwrite policy.
+func (s *conversionServiceImpl) prepareBatch(request models.BatchConversionRequest, batchID string, settings *models.UserSettings, claimed map[string]bool, start, end int) ([]batchItem, []*models.Conversion) {
+	items := make([]batchItem, 0, end-start)
+	records := make([]*models.Conversion, 0, end-start)
+
+	// Rules decide each file's settings from its analysis
+	var analyses []models.FileAnalysis
+	if len(request.Rules) > 0 {
+		analyses = s.analysis.AnalyzeFiles(request.Files[start:end], nil).Files
+	}
+
+	for i := start; i < end; i++ {
+		inputPath := request.Files[i]
+
 		// Validate file exists
-		if _, err := s.fileService.GetFileInfo(inputPath); err != nil {
-			result.Results = append(result.Results, models.ConversionResult{
+		fileInfo, err := s.fileService.GetFileInfo(inputPath)
+		if err != nil {
+			now := time.Now()
+			conversion := &models.Conversion{
+				BatchID:      batchID,
 				InputPath:    inputPath,
+				InputFormat:  strings.ToLower(filepath.Ext(inputPath)),
+				OutputFormat: request.OutputFormat,
+				FileType:     models.FileTypeUnknown,
+				Status:       models.StatusFailed,
 				ErrorMessage: err.Error(),
-			})
-			result.FailCount++
+				CompletedAt:  &now,
+			}
+			items = append(items, batchItem{job: models.ConversionJob{InputPath: inputPath}, err: err})
+			records = append(records, conversion)
 			continue
 		}
 
 		// Generate output path
+		namingMode := request.NamingMode
 		var customName string
-		if request.NamingMode == models.NamingModeCustom && i < len(request.CustomNames) {
-			customName = request.CustomNames[i]
+		switch namingMode {
+		case models.NamingModeCustom:
+			if i < len(request.CustomNames) {
+				customName = request.CustomNames[i]
+			}
+		case models.NamingModeTemplate:
+			// A template that can't be applied keeps the original name
+			name, err := renderNameTemplate(request.NameTemplate, inputPath, i+1)
+			if err != nil {
+				s.log.Warn("Keeping original name of %s: %v", inputPath, err)
+			}
+			customName = name
+		}
+		var rules models.RuleSettings
+		if analyses != nil {
+			rules = evaluateRules(request.Rules, analyses[i-start])
+		}
+		// The file's own settings override what the rules decided
+		var fileSettings models.FileSettings
+		if i < len(request.FileSettings) {
+			fileSettings = request.FileSettings[i]
+			mergeRuleSettings(&rules, fileSettings.RuleSettings)
+		}
+		if fileSettings.OutputName != "" {
+			namingMode = models.NamingModeCustom
+			customName = fileSettings.OutputName
+		}
+		outputFormat := request.OutputFormat
+		if rules.OutputFormat != "" {
+			outputFormat = rules.OutputFormat
+		}
+		// Without an output directory, formats with a route go to theirs
+		// and the rest next to their input
+		outputDir := fileSettings.OutputDirectory
+		if outputDir == "" {
+			outputDir = request.OutputDirectory
+		}
+		if outputDir == "" {
+			outputDir = routedDirectory(settings.OutputRoutes, outputFormat)
+		}
+		if outputDir == "" {
+			outputDir = filepath.Dir(inputPath)
 		}
 		outputPath := s.fileService.GenerateOutputPath(
 			inputPath,
-			request.OutputDirectory,
-			request.OutputFormat,
-			request.NamingMode,
+			outputDir,
+			outputFormat,
+			namingMode,
 			customName,
 		)
 
 		// Create conversion job
-		job := models.ConversionJob{
-			InputPath:       inputPath,
-			OutputPath:      outputPath,
-			OutputFormat:    request.OutputFormat,
-			OverwriteOutput: !request.MakeCopies,
+		job := batchJob(request, i, outputPath)
+		job.OutputFormat = outputFormat
+		applyRuleSettings(&job, fileInfo.Type, rules)
+		applyJobSettings(&job, settings)
+
+		overwrite := request.Overwrite
+		if fileSettings.Overwrite != "" {
+			overwrite = fileSettings.Overwrite
+		}
+		if err := applyOverwritePolicy(&job, overwrite, claimed); err != nil {
+			s.log.Warn("Not overwriting the output of %s: %v", inputPath, err)
+		}
+
+		conversion := newConversionRecord(job, fileInfo)
+		conversion.BatchID = batchID
+		if rules.Skip {
+			now := time.Now()
+			conversion.Status = models.StatusSkipped
+			conversion.CompletedAt = &now
+			s.log.Info("Skipping %s, its rules or settings leave it unconverted", inputPath)
 		}
+		items = append(items, batchItem{job: job, fileType: fileInfo.Type, conversion: conversion, skipped: rules.Skip})
+		records = append(records, conversion)
+	}
 
-		// For copies, we always create new files, so allow overwrite if needed
-		if request.MakeCopies {
-			job.OverwriteOutput = true
+	if err := s.repo.CreateBatch(records); err != nil {
+		// Fall back to creating records one at a time as files are converted
+		s.log.Error("Failed to create batch conversion records: %v", err)
+		for i := range items {
+			items[i].conversion = nil
 		}
+		return items, nil
+	}
 
-		// Convert file
-		convResult, err := s.ConvertFile(job)
+	return items, records
+}
 
-		if err != nil {
-			result.Results = append(result.Results, models.ConversionResult{
-				InputPath:    inputPath,
-				OutputPath:   outputPath,
-				ErrorMessage: err.Error(),
-			})
-			result.FailCount++
-		} else {
-			result.Results = append(result.Results, *convResult)
-			result.SuccessCount++
+// finalizeBatch marks records that never reached a final status as failed,
+// in a single transaction, so an interrupted batch leaves consistent history
+func (s *conversionServiceImpl) finalizeBatch(records []*models.Conversion) {
+	var unfinished []*models.Conversion
+	for _, conversion := range records {
+		if conversion.Status == models.StatusPending || conversion.Status == models.StatusProcessing {
+			conversion.Status = models.StatusFailed
+			conversion.ErrorMessage = "batch ended before the conversion finished"
+			unfinished = append(unfinished, conversion)
+		}
+	}
+
+	if err := s.repo.UpdateBatch(unfinished); err != nil {
+		s.log.Error("Failed to finalize batch conversion records: %v", err)
+	}
+}
+
+// convertBatchItem converts a prepared batch item, holding a slot of the
+// per-type worker limit while converting
+func (s *conversionServiceImpl) convertBatchItem(
+	item batchItem,
+	limits map[models.FileType]chan struct{},
+	opts stallOptions,
+	progressCallback func(progress models.ConversionProgress),
+) models.ConversionResult {
+	if item.err != nil {
+		return models.ConversionResult{
+			InputPath:    item.job.InputPath,
+			ErrorMessage: item.err.Error(),
+		}
+	}
+	if item.skipped {
+		return models.ConversionResult{
+			InputPath:  item.job.InputPath,
+			OutputPath: item.job.OutputPath,
+			Skipped:    true,
 		}
+	}
+
+	if limit, exists := limits[item.fileType]; exists {
+		limit <- struct{}{}
+		defer func() { <-limit }()
+	}
+
+	// Wait for the background/low-power throttle
+	if s.throttle.Acquire() {
+		item.job.LowPriority = true
+	}
+	defer s.throttle.Release()
+
+	// Convert file
+	convResult, err := s.convertFile(item.job, item.conversion, opts, progressCallback)
+	if err != nil {
+		return models.ConversionResult{
+			InputPath:    item.job.InputPath,
+			OutputPath:   item.job.OutputPath,
+			ErrorMessage: err.Error(),
+		}
+	}
+
+	return *convResult
+}
+
+// SplitFile cuts a video into segments of request.SegmentMinutes. The split
+// runs as a single cancellable job; once finished, each segment gets its own
+// history record grouped under one batch ID.
+func (s *conversionServiceImpl) SplitFile(request models.SplitRequest, progressCallback func(progress models.ConversionProgress)) (*models.BatchConversionResult, error) {
+	startTime := time.Now()
+
+	if request.SegmentMinutes <= 0 {
+		return nil, fmt.Errorf("segment length must be at least one minute")
+	}
+
+	splitter, ok := s.video().(Splitter)
+	if !ok {
+		return nil, fmt.Errorf("splitting videos requires FFmpeg")
+	}
+
+	fileInfo, err := s.fileService.GetFileInfo(request.InputPath)
+	if err != nil {
+		return nil, err
+	}
+	if fileInfo.Type != models.FileTypeVideo {
+		return nil, fmt.Errorf("only video files can be split")
+	}
+
+	// Segments are numbered after the original name, e.g. "clip_001.mp4"
+	outputDir := request.OutputDirectory
+	if outputDir == "" {
+		outputDir = filepath.Dir(request.InputPath)
+	}
+	if err := s.fileService.CheckOutputDirectory(outputDir); err != nil {
+		return nil, err
+	}
+	outputPath := s.fileService.GenerateOutputPath(request.InputPath, outputDir, request.OutputFormat, models.NamingModeOriginal, "")
+	ext := filepath.Ext(outputPath)
+	pattern := strings.ReplaceAll(strings.TrimSuffix(outputPath, ext), "%", "%%") + "_%03d" + ext
+
+	job := models.ConversionJob{
+		InputPath:       request.InputPath,
+		OutputPath:      pattern,
+		OutputFormat:    request.OutputFormat,
+		OverwriteOutput: request.OverwriteOutput,
+	}
+	settings := s.userSettings()
+	applyJobSettings(&job, settings)
+
+	if s.throttle.Acquire() {
+		job.LowPriority = true
+	}
+	defer s.throttle.Release()
+
+	// The whole split is tracked by one record while it runs
+	now := time.Now()
+	conversion := newConversionRecord(job, fileInfo)
+	conversion.BatchID = newBatchID()
+	conversion.Backend = converterBackend(s.video(), job)
+	conversion.Status = models.StatusProcessing
+	conversion.StartedAt = &now
+	if err := s.repo.Create(conversion); err != nil {
+		s.log.Error("Failed to create conversion record: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s.mu.Lock()
+	s.activeConversions[conversion.ID] = cancel
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.activeConversions, conversion.ID)
+		s.mu.Unlock()
+	}()
+
+	if jobLog := s.openJobLog(conversion.ID); jobLog != nil {
+		defer jobLog.Close()
+		jobLog.Printf("Splitting %s into %d-minute segments with %s", job.InputPath, request.SegmentMinutes, conversion.Backend)
+		job.Log = jobLog
+	}
+
+	results, err := splitter.Split(ctx, job, time.Duration(request.SegmentMinutes)*time.Minute, func(progress float64) {
+		conversion.Progress = progress
+		s.repo.Update(conversion)
 
-		// Report progress
 		if progressCallback != nil {
 			progressCallback(models.ConversionProgress{
-				InputPath: inputPath,
-				Progress:  float64(i+1) / float64(len(request.Files)) * 100,
-				Status:    string(models.StatusCompleted),
+				ID:        conversion.ID,
+				InputPath: job.InputPath,
+				Progress:  progress,
+				Status:    string(models.StatusProcessing),
 			})
 		}
+	})
 
-		s.log.Debug("Batch progress: %d/%d files completed", i+1, len(request.Files))
+	completedAt := time.Now()
+	conversion.CompletedAt = &completedAt
+
+	batch := &models.BatchConversionResult{
+		BatchID:    conversion.BatchID,
+		TotalFiles: 1,
 	}
 
-	result.TotalDuration = time.Since(startTime).Milliseconds()
+	if err != nil || len(results) == 0 {
+		if err == nil {
+			err = fmt.Errorf("split produced no segments")
+		}
+		err = explainFailure(err, settings.Language)
+		conversion.Status = models.StatusFailed
+		if ctx.Err() != nil {
+			conversion.Status = models.StatusCancelled
+		}
+		conversion.ErrorMessage = err.Error()
+		if updateErr := s.repo.Update(conversion); updateErr != nil {
+			s.log.Error("Failed to update conversion record: %v", updateErr)
+		}
 
-	s.log.Info("Batch conversion completed: %d success, %d failed, %dms total",
-		result.SuccessCount, result.FailCount, result.TotalDuration)
+		batch.FailCount = 1
+		batch.Results = []models.ConversionResult{{
+			InputPath:    job.InputPath,
+			ErrorMessage: err.Error(),
+		}}
+		batch.TotalDuration = time.Since(startTime).Milliseconds()
+		return batch, nil
+	}
 
-	return result, nil
+	// The running record becomes the first segment; the rest get new records
+	conversion.Status = models.StatusCompleted
+	conversion.Progress = 100
+	conversion.OutputPath = results[0].OutputPath
+	conversion.OutputSize = results[0].OutputSize
+	if err := s.repo.Update(conversion); err != nil {
+		s.log.Error("Failed to update conversion record: %v", err)
+	}
+
+	records := make([]*models.Conversion, 0, len(results)-1)
+	for _, segment := range results[1:] {
+		record := newConversionRecord(job, fileInfo)
+		record.BatchID = conversion.BatchID
+		record.Backend = conversion.Backend
+		record.OutputPath = segment.OutputPath
+		record.OutputSize = segment.OutputSize
+		record.Status = models.StatusCompleted
+		record.Progress = 100
+		record.StartedAt = conversion.StartedAt
+		record.CompletedAt = conversion.CompletedAt
+		records = append(records, record)
+	}
+	if err := s.repo.CreateBatch(records); err != nil {
+		s.log.Error("Failed to create segment conversion records: %v", err)
+	}
+
+	batch.TotalFiles = len(results)
+	batch.SuccessCount = len(results)
+	batch.Results = results[:min(len(results), batchResultsPageSize)]
+	batch.HasMoreResults = len(results) > len(batch.Results)
+	batch.TotalDuration = time.Since(startTime).Milliseconds()
+
+	s.log.Info("Split %s into %d segments in %dms", job.InputPath, len(results), batch.TotalDuration)
+	return batch, nil
+}
+
+const (
+	// defaultPreviewSeconds is the preview length used when none is given
+	defaultPreviewSeconds = 10
+
+	// maxPreviewSeconds caps preview length, since previews aren't cancellable
+	maxPreviewSeconds = 60
+)
+
+// PreviewConversion converts a slice of job.InputPath with the job's settings
+// to a temporary file and returns its path. The slice is taken from the middle
+// of long inputs, where it is more representative than an intro. Previews
+// aren't recorded in history. Only the latest preview is kept: making one
+// deletes the previous file.
+func (s *conversionServiceImpl) PreviewConversion(job models.ConversionJob, seconds int) (string, error) {
+	if seconds <= 0 {
+		seconds = defaultPreviewSeconds
+	}
+	seconds = min(seconds, maxPreviewSeconds)
+	if job.OutputFormat == "" {
+		return "", fmt.Errorf("an output format is required for previews")
+	}
+
+	fileInfo, err := s.fileService.GetFileInfo(job.InputPath)
+	if err != nil {
+		return "", err
+	}
+
+	var converter Converter
+	switch fileInfo.Type {
+	case models.FileTypeVideo:
+		converter = s.video()
+	case models.FileTypeAudio:
+		converter = s.audio()
+	default:
+		return "", fmt.Errorf("previews are only available for video and audio files")
+	}
+	previewer, ok := converter.(Previewer)
+	if !ok {
+		return "", fmt.Errorf("previews require FFmpeg")
+	}
+
+	// Write into a fresh temp file named after the target format
+	previewDir := scratch.Path("previews")
+	if err := os.MkdirAll(previewDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create preview directory: %w", err)
+	}
+	output, err := os.CreateTemp(previewDir, "preview-*."+strings.TrimPrefix(job.OutputFormat, "."))
+	if err != nil {
+		return "", fmt.Errorf("failed to create preview file: %w", err)
+	}
+	output.Close()
+
+	job.OutputPath = output.Name()
+	job.OverwriteOutput = true
+	applyJobSettings(&job, s.userSettings())
+
+	if s.throttle.Acquire() {
+		job.LowPriority = true
+	}
+	defer s.throttle.Release()
+
+	s.log.Info("Previewing %ds of %s as %s", seconds, job.InputPath, job.OutputFormat)
+
+	if _, err := previewer.Preview(context.Background(), job, time.Duration(seconds)*time.Second, nil); err != nil {
+		os.Remove(job.OutputPath)
+		return "", err
+	}
+
+	s.previewMu.Lock()
+	previous := s.preview
+	s.preview = job.OutputPath
+	s.previewMu.Unlock()
+	if previous != "" {
+		os.Remove(previous)
+	}
+	return job.OutputPath, nil
+}
+
+// DiscardPreviews deletes the preview files, including any left behind by
+// earlier sessions
+func (s *conversionServiceImpl) DiscardPreviews() error {
+	s.previewMu.Lock()
+	defer s.previewMu.Unlock()
+
+	s.preview = ""
+	if err := os.RemoveAll(scratch.Path("previews")); err != nil {
+		return fmt.Errorf("failed to delete previews: %w", err)
+	}
+	return nil
+}
+
+// EstimateOutputSize predicts the output size of a video or audio job from a
+// short trial encode with the job's settings
+func (s *conversionServiceImpl) EstimateOutputSize(job models.ConversionJob) (*models.SizeEstimate, error) {
+	if job.OutputFormat == "" {
+		return nil, fmt.Errorf("an output format is required for size estimates")
+	}
+
+	fileInfo, err := s.fileService.GetFileInfo(job.InputPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var converter Converter
+	switch fileInfo.Type {
+	case models.FileTypeVideo:
+		converter = s.video()
+	case models.FileTypeAudio:
+		converter = s.audio()
+	default:
+		return nil, fmt.Errorf("size estimates are only available for video and audio files")
+	}
+	estimator, ok := converter.(SizeEstimator)
+	if !ok {
+		return nil, fmt.Errorf("size estimates require FFmpeg")
+	}
+
+	applyJobSettings(&job, s.userSettings())
+
+	if s.throttle.Acquire() {
+		job.LowPriority = true
+	}
+	defer s.throttle.Release()
+
+	estimate, err := estimator.EstimateSize(context.Background(), job)
+	if err != nil {
+		return nil, err
+	}
+
+	s.log.Info("Estimated %s as %s: %d bytes (input %d bytes)", job.InputPath, job.OutputFormat, estimate.EstimatedSize, estimate.InputSize)
+	return estimate, nil
+}
+
+// RerunConversion converts a history entry's input again with the options
+// stored on its record, replacing the earlier output. The new conversion gets
+// its own record, which is returned while it runs in the background; its
+// progress and result are reported through progressCallback. If the earlier
+// output directory can no longer be used, the output is written next to the
+// input instead.
+func (s *conversionServiceImpl) RerunConversion(id uint, progressCallback func(progress models.ConversionProgress)) (*models.Conversion, error) {
+	previous, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if previous == nil {
+		return nil, fmt.Errorf("conversion %d not found", id)
+	}
+
+	// Records from before jobs were stored only know their paths and format
+	job := models.ConversionJob{
+		InputPath:    previous.InputPath,
+		OutputPath:   previous.OutputPath,
+		OutputFormat: previous.OutputFormat,
+	}
+	if previous.Settings != "" {
+		if err := json.Unmarshal([]byte(previous.Settings), &job); err != nil {
+			s.log.Warn("Failed to decode stored job of conversion %d, using its paths only: %v", id, err)
+		}
+	}
+	job.OverwriteOutput = true
+	job.EstimatedSize = 0
+	job.Threads = 0
+	job.LowPriority = false
+
+	fileInfo, err := s.fileService.GetFileInfo(job.InputPath)
+	if err != nil {
+		return nil, fmt.Errorf("the original file was moved or deleted: %s", job.InputPath)
+	}
+
+	if err := s.fileService.CheckOutputDirectory(filepath.Dir(job.OutputPath)); err != nil {
+		s.log.Warn("Earlier output directory can't be used, writing next to the input: %v", err)
+		job.OutputPath = s.fileService.GenerateOutputPath(
+			job.InputPath, filepath.Dir(job.InputPath), job.OutputFormat, models.NamingModeOriginal, "")
+	}
+
+	settings := s.userSettings()
+	applyJobSettings(&job, settings)
+
+	conversion := newConversionRecord(job, fileInfo)
+	conversion.BatchID = previous.BatchID
+	if err := s.repo.Create(conversion); err != nil {
+		return nil, fmt.Errorf("failed to create conversion record: %w", err)
+	}
+
+	s.log.Info("Re-running conversion %d as %d: %s", id, conversion.ID, job.InputPath)
+
+	go func() {
+		if s.throttle.Acquire() {
+			job.LowPriority = true
+		}
+		defer s.throttle.Release()
+
+		result, err := s.convertFile(job, conversion, stallOptionsFromSettings(settings), progressCallback)
+		status := models.StatusCompleted
+		if err != nil {
+			status = models.StatusFailed
+			result = &models.ConversionResult{
+				InputPath:    job.InputPath,
+				OutputPath:   job.OutputPath,
+				ErrorMessage: err.Error(),
+			}
+		}
+
+		if progressCallback != nil {
+			progressCallback(models.ConversionProgress{
+				ID:        conversion.ID,
+				InputPath: job.InputPath,
+				Progress:  100,
+				Status:    string(status),
+				Result:    result,
+			})
+		}
+	}()
+
+	return conversion, nil
 }
 
 // CancelConversion cancels an ongoing conversion
@@ -210,7 +1309,228 @@ func (s *conversionServiceImpl) CancelConversion(id uint) error {
 	return fmt.Errorf("conversion %d not found or already completed", id)
 }
 
+// SetThrottle changes how aggressively conversions run. Reduced runs one job
+// at a time at low priority, paused starts no new jobs until resumed.
+func (s *conversionServiceImpl) SetThrottle(level models.ThrottleLevel) {
+	if s.throttle.Level() == level {
+		return
+	}
+	s.log.Info("Conversion throttle changed to: %s", level)
+	s.throttle.SetLevel(level)
+}
+
+// SetMediaConverters replaces the video and audio converters, e.g. when
+// the backend is switched. Running conversions finish with the converters
+// they started with.
+func (s *conversionServiceImpl) SetMediaConverters(video, audio Converter) {
+	s.convertersMu.Lock()
+	defer s.convertersMu.Unlock()
+	s.videoConverter = video
+	s.audioConverter = audio
+}
+
+// video returns the current video converter
+func (s *conversionServiceImpl) video() Converter {
+	s.convertersMu.RLock()
+	defer s.convertersMu.RUnlock()
+	return s.videoConverter
+}
+
+// audio returns the current audio converter
+func (s *conversionServiceImpl) audio() Converter {
+	s.convertersMu.RLock()
+	defer s.convertersMu.RUnlock()
+	return s.audioConverter
+}
+
+// SetRemoteWorker sends conversions of the worker's file types to it, or
+// converts everything locally again when worker is nil
+func (s *conversionServiceImpl) SetRemoteWorker(worker *RemoteWorker) {
+	s.remote.Store(worker)
+}
+
+// queueHistoryLimit caps the completed and failed jobs in the queue state
+const queueHistoryLimit = 50
+
+// GetQueueState returns a snapshot of this session's conversion queue
+func (s *conversionServiceImpl) GetQueueState() (*models.QueueState, error) {
+	active, err := s.repo.GetByStatus([]models.ConversionStatus{
+		models.StatusPending, models.StatusProcessing,
+	}, s.startedAt, 0)
+	if err != nil {
+		return nil, err
+	}
+	completed, err := s.repo.GetByStatus([]models.ConversionStatus{models.StatusCompleted}, s.startedAt, queueHistoryLimit)
+	if err != nil {
+		return nil, err
+	}
+	failed, err := s.repo.GetByStatus([]models.ConversionStatus{
+		models.StatusFailed, models.StatusCancelled,
+	}, s.startedAt, queueHistoryLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	state := &models.QueueState{
+		Throttle:  s.throttle.Level(),
+		Queued:    []models.QueueJob{},
+		Running:   []models.QueueJob{},
+		Paused:    []models.QueueJob{},
+		Completed: queueJobs(completed),
+		Failed:    queueJobs(failed),
+	}
+
+	// Active jobs are listed in submission order
+	sort.Slice(active, func(i, j int) bool { return active[i].ID < active[j].ID })
+	for _, conversion := range active {
+		job := queueJob(conversion)
+		switch {
+		case job.Status == models.StatusProcessing:
+			state.Running = append(state.Running, job)
+		case state.Throttle == models.ThrottlePaused:
+			state.Paused = append(state.Paused, job)
+		default:
+			state.Queued = append(state.Queued, job)
+		}
+	}
+	return state, nil
+}
+
+// queueJob summarizes a conversion record for the queue state
+func queueJob(conversion models.Conversion) models.QueueJob {
+	return models.QueueJob{
+		ID:           conversion.ID,
+		BatchID:      conversion.BatchID,
+		InputPath:    conversion.InputPath,
+		OutputPath:   conversion.OutputPath,
+		OutputFormat: conversion.OutputFormat,
+		FileType:     conversion.FileType,
+		Status:       conversion.Status,
+		Progress:     conversion.Progress,
+		ErrorMessage: conversion.ErrorMessage,
+	}
+}
+
+// queueJobs summarizes conversion records for the queue state
+func queueJobs(conversions []models.Conversion) []models.QueueJob {
+	jobs := make([]models.QueueJob, 0, len(conversions))
+	for _, conversion := range conversions {
+		jobs = append(jobs, queueJob(conversion))
+	}
+	return jobs
+}
+
+// GetThrottle returns the current throttle level
+func (s *conversionServiceImpl) GetThrottle() models.ThrottleLevel {
+	return s.throttle.Level()
+}
+
+// GetBatchResults retrieves a page of results for a batch conversion
+func (s *conversionServiceImpl) GetBatchResults(batchID string, offset, limit int) ([]models.ConversionResult, error) {
+	conversions, err := s.repo.GetByBatch(batchID, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]models.ConversionResult, len(conversions))
+	for i := range conversions {
+		results[i] = conversions[i].ToResult()
+	}
+	return results, nil
+}
+
 // GetConversionHistory retrieves conversion history
 func (s *conversionServiceImpl) GetConversionHistory(limit int) ([]models.Conversion, error) {
-	return s.repo.GetHistory(limit)
+	return s.repo.GetHistory(models.HistoryFilter{Limit: limit})
+}
+
+// FilterConversionHistory retrieves conversion history matching a filter
+func (s *conversionServiceImpl) FilterConversionHistory(filter models.HistoryFilter) ([]models.Conversion, error) {
+	return s.repo.GetHistory(filter)
+}
+
+// GetBatchHistory retrieves a page of history grouped by batch. Conversions
+// made outside a batch are entries of their own.
+func (s *conversionServiceImpl) GetBatchHistory(offset, limit int) ([]models.BatchSummary, error) {
+	return s.repo.GetBatchSummaries(offset, limit)
+}
+
+// maxNoteLength bounds the length of a conversion note, in characters
+const maxNoteLength = 2000
+
+// UpdateConversionNote sets the note of a history record. An empty note
+// removes it.
+func (s *conversionServiceImpl) UpdateConversionNote(id uint, note string) error {
+	note = strings.TrimSpace(note)
+	if utf8.RuneCountInString(note) > maxNoteLength {
+		return fmt.Errorf("note is longer than %d characters", maxNoteLength)
+	}
+	return s.repo.UpdateNote(id, note)
+}
+
+// ArchiveConversions hides history records while keeping their statistics
+func (s *conversionServiceImpl) ArchiveConversions(ids []uint) error {
+	s.log.Info("Archiving %d conversions", len(ids))
+	return s.repo.SetArchived(ids, true)
+}
+
+// RestoreConversions brings archived history records back
+func (s *conversionServiceImpl) RestoreConversions(ids []uint) error {
+	s.log.Info("Restoring %d archived conversions", len(ids))
+	return s.repo.SetArchived(ids, false)
+}
+
+// ActiveConversionCount returns the number of conversions of this session
+// that are running or waiting to start
+func (s *conversionServiceImpl) ActiveConversionCount() (int, error) {
+	active, err := s.repo.GetByStatus([]models.ConversionStatus{
+		models.StatusPending, models.StatusProcessing,
+	}, s.startedAt, 0)
+	if err != nil {
+		return 0, err
+	}
+	return len(active), nil
+}
+
+// DeleteConversion removes a history record. Running conversions must be
+// cancelled first.
+func (s *conversionServiceImpl) DeleteConversion(id uint) error {
+	s.mu.Lock()
+	_, active := s.activeConversions[id]
+	s.mu.Unlock()
+	if active {
+		return fmt.Errorf("conversion %d is still running", id)
+	}
+
+	s.log.Info("Deleting conversion %d", id)
+	return s.repo.Delete(id)
+}
+
+// PurgeConversions permanently deletes archived and deleted history records,
+// removing them from statistics too
+func (s *conversionServiceImpl) PurgeConversions() (int64, error) {
+	return s.repo.Purge()
+}
+
+// GetSpaceSavings returns the bytes saved by completed conversions per week
+// or month, oldest first, with a running total across periods
+func (s *conversionServiceImpl) GetSpaceSavings(period models.SavingsPeriod) ([]models.SpaceSavings, error) {
+	savings, err := s.repo.GetSpaceSavings(period)
+	if err != nil {
+		return nil, err
+	}
+
+	var total int64
+	for i := range savings {
+		savings[i].SavedBytes = savings[i].InputSize - savings[i].OutputSize
+		total += savings[i].SavedBytes
+		savings[i].CumulativeSaved = total
+	}
+	return savings, nil
+}
+
+// GetBatchConversions retrieves a page of a batch's conversion records in
+// submission order
+func (s *conversionServiceImpl) GetBatchConversions(batchID string, offset, limit int) ([]models.Conversion, error) {
+	return s.repo.GetByBatch(batchID, offset, limit)
 }
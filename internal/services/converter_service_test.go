@@ -0,0 +1,241 @@
+package services_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"converzen/internal/models"
+	"converzen/internal/services/servicestest"
+)
+
+func TestConvertBatch(t *testing.T) {
+	f := servicestest.NewFixture(t)
+	inputs := []string{
+		f.WriteFile(t, "in/first.mov", "first"),
+		f.WriteFile(t, "in/second.mov", "second"),
+	}
+	outputDir := filepath.Join(f.Dir, "out")
+
+	var mu sync.Mutex
+	results := make(map[string]models.ConversionResult)
+	result, err := f.Service.ConvertBatch(models.BatchConversionRequest{
+		Files:           inputs,
+		OutputFormat:    "mp4",
+		OutputDirectory: outputDir,
+		NamingMode:      models.NamingModeOriginal,
+	}, func(progress models.ConversionProgress) {
+		if progress.Result != nil {
+			mu.Lock()
+			results[progress.Result.InputPath] = *progress.Result
+			mu.Unlock()
+		}
+	})
+	if err != nil {
+		t.Fatalf("ConvertBatch failed: %v", err)
+	}
+
+	if result.TotalFiles != 2 || result.SuccessCount != 2 || result.FailCount != 0 {
+		t.Errorf("result = %d total, %d succeeded, %d failed; want 2, 2, 0",
+			result.TotalFiles, result.SuccessCount, result.FailCount)
+	}
+	for _, input := range inputs {
+		name := strings.TrimSuffix(filepath.Base(input), ".mov")
+		output := filepath.Join(outputDir, name+".mp4")
+		if data, err := os.ReadFile(output); err != nil || string(data) != name {
+			t.Errorf("%s = %q, %v; want the converted %s", output, data, err, name)
+		}
+		if !results[input].Success {
+			t.Errorf("no successful result reported for %s", input)
+		}
+	}
+
+	jobs := f.Video.Jobs()
+	if len(jobs) != 2 {
+		t.Fatalf("video converter got %d jobs, want 2", len(jobs))
+	}
+	for _, job := range jobs {
+		if job.OutputFormat != "mp4" {
+			t.Errorf("job output format = %q, want mp4", job.OutputFormat)
+		}
+	}
+	if len(f.Image.Jobs()) != 0 {
+		t.Errorf("image converter got jobs for a video batch")
+	}
+
+	records := f.Conversions.All()
+	if len(records) != 2 {
+		t.Fatalf("history has %d records, want 2", len(records))
+	}
+	for _, record := range records {
+		if record.Status != models.StatusCompleted || record.BatchID != result.BatchID {
+			t.Errorf("record %s is %s in batch %q, want completed in %q",
+				record.InputPath, record.Status, record.BatchID, result.BatchID)
+		}
+	}
+}
+
+func TestConvertBatchFailure(t *testing.T) {
+	f := servicestest.NewFixture(t)
+	good := f.WriteFile(t, "good.mov", "good")
+	bad := f.WriteFile(t, "bad.mov", "bad")
+
+	f.Video.ConvertFunc = func(ctx context.Context, job models.ConversionJob, progressCallback func(float64)) (*models.ConversionResult, error) {
+		if job.InputPath == bad {
+			err := fmt.Errorf("moov atom not found")
+			return &models.ConversionResult{InputPath: job.InputPath, OutputPath: job.OutputPath, ErrorMessage: err.Error()}, err
+		}
+		return servicestest.CopyConversion(ctx, job, progressCallback)
+	}
+
+	result, err := f.Service.ConvertBatch(models.BatchConversionRequest{
+		Files:        []string{good, bad},
+		OutputFormat: "mp4",
+		NamingMode:   models.NamingModeOriginal,
+	}, nil)
+	if err != nil {
+		t.Fatalf("ConvertBatch failed: %v", err)
+	}
+	if result.SuccessCount != 1 || result.FailCount != 1 {
+		t.Errorf("result = %d succeeded, %d failed; want 1, 1", result.SuccessCount, result.FailCount)
+	}
+
+	for _, record := range f.Conversions.All() {
+		switch record.InputPath {
+		case good:
+			if record.Status != models.StatusCompleted {
+				t.Errorf("%s is %s, want completed", record.InputPath, record.Status)
+			}
+		case bad:
+			if record.Status != models.StatusFailed || record.ErrorMessage == "" {
+				t.Errorf("%s is %s with error %q, want failed with an error", record.InputPath, record.Status, record.ErrorMessage)
+			}
+		}
+	}
+}
+
+func TestConvertBatchOverwriteNever(t *testing.T) {
+	f := servicestest.NewFixture(t)
+	input := f.WriteFile(t, "clip.mov", "new")
+	existing := f.WriteFile(t, "clip.mp4", "old")
+
+	result, err := f.Service.ConvertBatch(models.BatchConversionRequest{
+		Files:        []string{input},
+		OutputFormat: "mp4",
+		NamingMode:   models.NamingModeOriginal,
+		Overwrite:    models.OverwriteNever,
+	}, nil)
+	if err != nil {
+		t.Fatalf("ConvertBatch failed: %v", err)
+	}
+	if result.FailCount != 1 {
+		t.Errorf("FailCount = %d, want 1 for an existing output", result.FailCount)
+	}
+	if data, _ := os.ReadFile(existing); string(data) != "old" {
+		t.Errorf("existing output = %q, want it left alone", data)
+	}
+}
+
+func TestConvertBatchRejectsInvalidRequest(t *testing.T) {
+	f := servicestest.NewFixture(t)
+	input := f.WriteFile(t, "clip.mov", "clip")
+
+	for name, request := range map[string]models.BatchConversionRequest{
+		"overwrite policy": {Files: []string{input}, OutputFormat: "mp4", Overwrite: "sometimes"},
+		"quality":          {Files: []string{input}, OutputFormat: "mp4", Quality: "ultra"},
+		"CRF":              {Files: []string{input}, OutputFormat: "mp4", CRF: 99},
+	} {
+		if _, err := f.Service.ConvertBatch(request, nil); err == nil {
+			t.Errorf("ConvertBatch accepted an invalid %s", name)
+		}
+	}
+	if jobs := f.Video.Jobs(); len(jobs) != 0 {
+		t.Errorf("invalid requests converted %d files", len(jobs))
+	}
+	if records := f.Conversions.All(); len(records) != 0 {
+		t.Errorf("invalid requests left %d history records", len(records))
+	}
+}
+
+func TestConvertBatchSkipsPowerActionWithoutOutputs(t *testing.T) {
+	f := servicestest.NewFixture(t)
+	input := f.WriteFile(t, "bad.mov", "bad")
+
+	f.Video.ConvertFunc = func(ctx context.Context, job models.ConversionJob, progressCallback func(float64)) (*models.ConversionResult, error) {
+		err := fmt.Errorf("moov atom not found")
+		return &models.ConversionResult{InputPath: job.InputPath, OutputPath: job.OutputPath, ErrorMessage: err.Error()}, err
+	}
+
+	result, err := f.Service.ConvertBatch(models.BatchConversionRequest{
+		Files:        []string{input},
+		OutputFormat: "mp4",
+		NamingMode:   models.NamingModeOriginal,
+		PostActions:  &models.PostActions{Power: models.PowerActionShutdown},
+	}, nil)
+	if err != nil {
+		t.Fatalf("ConvertBatch failed: %v", err)
+	}
+	if result.PowerActionAt != nil || f.Service.CancelPowerAction() {
+		t.Errorf("a shutdown was scheduled after a batch that converted nothing")
+	}
+	if len(result.Warnings) != 1 {
+		t.Errorf("warnings = %v, want one about the skipped shutdown", result.Warnings)
+	}
+}
+
+func TestConvertBatchCancelsPreScript(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the pre-conversion script is a shell script")
+	}
+	f := servicestest.NewFixture(t)
+	input := f.WriteFile(t, "clip.mov", "clip")
+	script := f.WriteFile(t, "fetch.sh", "#!/bin/sh\nsleep 30\n")
+	if err := os.Chmod(script, 0755); err != nil {
+		t.Fatalf("failed to make the script executable: %v", err)
+	}
+
+	done := make(chan *models.BatchConversionResult)
+	go func() {
+		result, err := f.Service.ConvertBatch(models.BatchConversionRequest{
+			Files:        []string{input},
+			OutputFormat: "mp4",
+			NamingMode:   models.NamingModeOriginal,
+			PreScript:    script,
+		}, nil)
+		if err != nil {
+			t.Errorf("ConvertBatch failed: %v", err)
+		}
+		done <- result
+	}()
+
+	// Cancel the job once its script is running
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		records := f.Conversions.All()
+		if len(records) == 1 && records[0].Status == models.StatusProcessing && f.Service.CancelConversion(records[0].ID) == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("the pre-conversion script couldn't be cancelled")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatalf("ConvertBatch still running after the job was cancelled")
+	}
+	if records := f.Conversions.All(); records[0].Status != models.StatusCancelled {
+		t.Errorf("record is %s, want cancelled", records[0].Status)
+	}
+	if jobs := f.Video.Jobs(); len(jobs) != 0 {
+		t.Errorf("a cancelled job was converted")
+	}
+}
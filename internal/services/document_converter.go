@@ -0,0 +1,125 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"converzen/internal/logger"
+	"converzen/internal/models"
+	"converzen/pkg/libreoffice"
+)
+
+// documentConverter handles office document conversion using LibreOffice
+type documentConverter struct {
+	office *libreoffice.LibreOffice
+	log    *logger.ComponentLogger
+}
+
+// NewDocumentConverter creates a new document converter
+func NewDocumentConverter(office *libreoffice.LibreOffice, log *logger.Logger) Converter {
+	return &documentConverter{
+		office: office,
+		log:    log.WithComponent("document-converter"),
+	}
+}
+
+// Convert converts an office document to another format
+func (c *documentConverter) Convert(ctx context.Context, job models.ConversionJob, progressCallback func(progress float64)) (*models.ConversionResult, error) {
+	c.log.Info("Starting document conversion: %s -> %s", job.InputPath, job.OutputPath)
+	startTime := time.Now()
+
+	result := &models.ConversionResult{
+		InputPath:  job.InputPath,
+		OutputPath: job.OutputPath,
+	}
+
+	// Validate input file exists
+	if _, err := os.Stat(job.InputPath); os.IsNotExist(err) {
+		result.ErrorMessage = fmt.Sprintf("Input file not found: %s", job.InputPath)
+		c.log.Error("%s", result.ErrorMessage)
+		return result, fmt.Errorf("%s", result.ErrorMessage)
+	}
+
+	// Check output directory exists
+	outputDir := filepath.Dir(job.OutputPath)
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		result.ErrorMessage = fmt.Sprintf("Failed to create output directory: %v", err)
+		c.log.Error("%s", result.ErrorMessage)
+		return result, fmt.Errorf("%s", result.ErrorMessage)
+	}
+
+	// Check if output file already exists
+	if !job.OverwriteOutput {
+		if _, err := os.Stat(job.OutputPath); err == nil {
+			result.ErrorMessage = fmt.Sprintf("Output file already exists: %s", job.OutputPath)
+			c.log.Error("%s", result.ErrorMessage)
+			return result, fmt.Errorf("%s", result.ErrorMessage)
+		}
+	}
+
+	// LibreOffice reports no progress, so mark the start and end only
+	if progressCallback != nil {
+		progressCallback(10)
+	}
+
+	outputFormat := strings.TrimPrefix(strings.ToLower(filepath.Ext(job.OutputPath)), ".")
+	if err := c.office.Convert(ctx, job.InputPath, job.OutputPath, outputFormat); err != nil {
+		result.ErrorMessage = err.Error()
+		c.log.Error("Document conversion failed: %v", err)
+		return result, err
+	}
+
+	if progressCallback != nil {
+		progressCallback(100)
+	}
+
+	if stat, err := os.Stat(job.OutputPath); err == nil {
+		result.OutputSize = stat.Size()
+	}
+
+	result.Success = true
+	result.Duration = time.Since(startTime).Milliseconds()
+
+	c.log.Info("Document conversion completed in %dms: %s", result.Duration, job.OutputPath)
+	return result, nil
+}
+
+// SupportedInputFormats returns the list of supported input document formats
+func (c *documentConverter) SupportedInputFormats() []string {
+	formats := make([]string, 0, len(models.OfficeDocumentFormats))
+	for format := range models.OfficeDocumentFormats {
+		formats = append(formats, strings.TrimPrefix(format, "."))
+	}
+	return formats
+}
+
+// SupportedOutputFormats returns the list of supported output formats for documents
+func (c *documentConverter) SupportedOutputFormats(inputFormat string) []string {
+	return models.DocumentOutputFormats
+}
+
+// CanConvert checks if conversion is possible between formats
+func (c *documentConverter) CanConvert(inputFormat, outputFormat string) bool {
+	inputFormat = strings.ToLower(strings.TrimPrefix(inputFormat, "."))
+	outputFormat = strings.ToLower(strings.TrimPrefix(outputFormat, "."))
+
+	if !models.OfficeDocumentFormats["."+inputFormat] {
+		return false
+	}
+
+	for _, format := range models.DocumentOutputFormats {
+		if format == outputFormat {
+			return true
+		}
+	}
+	return false
+}
+
+// Backend reports that jobs are converted with LibreOffice
+func (c *documentConverter) Backend(job models.ConversionJob) string {
+	return "libreoffice"
+}
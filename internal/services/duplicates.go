@@ -0,0 +1,96 @@
+package services
+
+import (
+	"encoding/json"
+	"os"
+
+	"converzen/internal/models"
+)
+
+// FindDuplicates returns the files of a batch request that were already
+// converted successfully with the same settings, so the user can be warned
+// before converting them again. A file counts as the same input when its path
+// and size match and it hasn't been modified since the earlier conversion.
+func (s *conversionServiceImpl) FindDuplicates(request models.BatchConversionRequest) ([]models.DuplicateConversion, error) {
+	if len(request.Files) == 0 {
+		return nil, nil
+	}
+
+	previous, err := s.repo.GetCompletedByInputs(request.Files)
+	if err != nil {
+		return nil, err
+	}
+	if len(previous) == 0 {
+		return nil, nil
+	}
+
+	byInput := make(map[string][]models.Conversion)
+	for _, conversion := range previous {
+		byInput[conversion.InputPath] = append(byInput[conversion.InputPath], conversion)
+	}
+
+	settings := s.userSettings()
+	var duplicates []models.DuplicateConversion
+	for i, inputPath := range request.Files {
+		candidates := byInput[inputPath]
+		if len(candidates) == 0 {
+			continue
+		}
+		stat, err := os.Stat(inputPath)
+		if err != nil {
+			continue
+		}
+
+		job := batchJob(request, i, "")
+		applyJobSettings(&job, settings)
+		fingerprint := jobFingerprint(job)
+
+		// Candidates are newest first, so the most recent match is reported
+		for _, conversion := range candidates {
+			if conversion.FileSize != stat.Size() || stat.ModTime().After(conversion.CreatedAt) {
+				continue
+			}
+			if conversion.Settings == "" || storedJobFingerprint(conversion.Settings) != fingerprint {
+				continue
+			}
+
+			duplicates = append(duplicates, models.DuplicateConversion{
+				InputPath:    inputPath,
+				ConversionID: conversion.ID,
+				OutputPath:   conversion.OutputPath,
+				ConvertedAt:  conversion.CreatedAt,
+				OutputExists: s.fileService.FileExists(conversion.OutputPath),
+			})
+			break
+		}
+	}
+
+	if len(duplicates) > 0 {
+		s.log.Info("%d of %d files were already converted with the same settings", len(duplicates), len(request.Files))
+	}
+	return duplicates, nil
+}
+
+// jobFingerprint encodes the options of a job that affect its output, leaving
+// out paths, resource limits and other per-run details
+func jobFingerprint(job models.ConversionJob) string {
+	job.InputPath = ""
+	job.OutputPath = ""
+	job.OutputFormat = jobOutputFormat(job)
+	job.OverwriteOutput = false
+	job.EstimatedSize = 0
+	job.Threads = 0
+	job.LowPriority = false
+
+	encoded, _ := json.Marshal(job)
+	return string(encoded)
+}
+
+// storedJobFingerprint fingerprints a job stored on a conversion record
+func storedJobFingerprint(settings string) string {
+	var job models.ConversionJob
+	if err := json.Unmarshal([]byte(settings), &job); err != nil {
+		return ""
+	}
+	return jobFingerprint(job)
+}
@@ -0,0 +1,119 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"converzen/internal/logger"
+	"converzen/internal/models"
+	"converzen/pkg/calibre"
+)
+
+// ebookConverter handles e-book conversion using Calibre's ebook-convert
+type ebookConverter struct {
+	calibre *calibre.Calibre
+	log     *logger.ComponentLogger
+}
+
+// NewEbookConverter creates a new e-book converter
+func NewEbookConverter(cal *calibre.Calibre, log *logger.Logger) Converter {
+	return &ebookConverter{
+		calibre: cal,
+		log:     log.WithComponent("ebook-converter"),
+	}
+}
+
+// Convert converts an e-book to another format
+func (c *ebookConverter) Convert(ctx context.Context, job models.ConversionJob, progressCallback func(progress float64)) (*models.ConversionResult, error) {
+	c.log.Info("Starting e-book conversion: %s -> %s", job.InputPath, job.OutputPath)
+	startTime := time.Now()
+
+	result := &models.ConversionResult{
+		InputPath:  job.InputPath,
+		OutputPath: job.OutputPath,
+	}
+
+	// Validate input file exists
+	if _, err := os.Stat(job.InputPath); os.IsNotExist(err) {
+		result.ErrorMessage = fmt.Sprintf("Input file not found: %s", job.InputPath)
+		c.log.Error("%s", result.ErrorMessage)
+		return result, fmt.Errorf("%s", result.ErrorMessage)
+	}
+
+	// Check output directory exists
+	outputDir := filepath.Dir(job.OutputPath)
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		result.ErrorMessage = fmt.Sprintf("Failed to create output directory: %v", err)
+		c.log.Error("%s", result.ErrorMessage)
+		return result, fmt.Errorf("%s", result.ErrorMessage)
+	}
+
+	// Check if output file already exists
+	if !job.OverwriteOutput {
+		if _, err := os.Stat(job.OutputPath); err == nil {
+			result.ErrorMessage = fmt.Sprintf("Output file already exists: %s", job.OutputPath)
+			c.log.Error("%s", result.ErrorMessage)
+			return result, fmt.Errorf("%s", result.ErrorMessage)
+		}
+	}
+
+	if err := c.calibre.Convert(ctx, job.InputPath, job.OutputPath, progressCallback); err != nil {
+		result.ErrorMessage = err.Error()
+		c.log.Error("E-book conversion failed: %v", err)
+		return result, err
+	}
+
+	if progressCallback != nil {
+		progressCallback(100)
+	}
+
+	if stat, err := os.Stat(job.OutputPath); err == nil {
+		result.OutputSize = stat.Size()
+	}
+
+	result.Success = true
+	result.Duration = time.Since(startTime).Milliseconds()
+
+	c.log.Info("E-book conversion completed in %dms: %s", result.Duration, job.OutputPath)
+	return result, nil
+}
+
+// SupportedInputFormats returns the list of supported input e-book formats
+func (c *ebookConverter) SupportedInputFormats() []string {
+	formats := make([]string, 0, len(models.EbookFormats))
+	for format := range models.EbookFormats {
+		formats = append(formats, strings.TrimPrefix(format, "."))
+	}
+	return formats
+}
+
+// SupportedOutputFormats returns the list of supported output formats for e-books
+func (c *ebookConverter) SupportedOutputFormats(inputFormat string) []string {
+	return models.EbookOutputFormats
+}
+
+// CanConvert checks if conversion is possible between formats
+func (c *ebookConverter) CanConvert(inputFormat, outputFormat string) bool {
+	inputFormat = strings.ToLower(strings.TrimPrefix(inputFormat, "."))
+	outputFormat = strings.ToLower(strings.TrimPrefix(outputFormat, "."))
+
+	if !models.EbookFormats["."+inputFormat] {
+		return false
+	}
+
+	for _, format := range models.EbookOutputFormats {
+		if format == outputFormat {
+			return true
+		}
+	}
+	return false
+}
+
+// Backend reports that jobs are converted with Calibre
+func (c *ebookConverter) Backend(job models.ConversionJob) string {
+	return "calibre"
+}
@@ -0,0 +1,187 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"converzen/internal/logger"
+	"converzen/internal/models"
+	"converzen/pkg/ffmpeg"
+	"converzen/pkg/scratch"
+)
+
+const (
+	// benchmarkSSIMTolerance is how much lower an encoder's SSIM may be than
+	// the codec's software encoder for it to be picked as the faster choice
+	benchmarkSSIMTolerance = 0.01
+
+	// benchmarkMinSSIM is the lowest SSIM an encoder is picked with when the
+	// codec's software encoder couldn't be benchmarked
+	benchmarkMinSSIM = 0.95
+)
+
+// encoderBenchmarkServiceImpl implements EncoderBenchmarkService
+type encoderBenchmarkServiceImpl struct {
+	resultsPath string
+	ffmpeg      *ffmpeg.FFmpeg
+	log         *logger.ComponentLogger
+}
+
+// NewEncoderBenchmarkService creates a new EncoderBenchmarkService saving
+// its results to resultsPath. Results saved by an earlier run are applied
+// to ff right away. ff may be nil, in which case benchmarks fail.
+func NewEncoderBenchmarkService(resultsPath string, ff *ffmpeg.FFmpeg, log *logger.Logger) EncoderBenchmarkService {
+	s := &encoderBenchmarkServiceImpl{
+		resultsPath: resultsPath,
+		ffmpeg:      ff,
+		log:         log.WithComponent("encoder-benchmark"),
+	}
+
+	if report, err := s.GetEncoderBenchmark(); err != nil {
+		s.log.Warn("Ignoring saved encoder benchmark: %v", err)
+	} else if report != nil && ff != nil {
+		ff.SetPreferredEncoders(report.Recommended)
+		s.log.Info("Using benchmarked encoders: %v", report.Recommended)
+	}
+	return s
+}
+
+// RunEncoderBenchmark encodes a generated sample with each H.264 and HEVC
+// encoder FFmpeg was built with, then saves the results and makes the
+// recommended encoders the defaults. progressCallback, if set, is called
+// with the number of encoders benchmarked so far.
+func (s *encoderBenchmarkServiceImpl) RunEncoderBenchmark(ctx context.Context, progressCallback func(done, total int)) (*models.EncoderBenchmarkReport, error) {
+	if s.ffmpeg == nil {
+		return nil, fmt.Errorf("FFmpeg is not available")
+	}
+
+	var encoders []string
+	for _, encoder := range ffmpeg.BenchmarkEncoders {
+		if s.ffmpeg.HasEncoder(encoder) {
+			encoders = append(encoders, encoder)
+		}
+	}
+	if len(encoders) == 0 {
+		return nil, fmt.Errorf("this FFmpeg build has no H.264 or HEVC encoder")
+	}
+	s.log.Info("Benchmarking %d encoders", len(encoders))
+
+	dir, err := scratch.MkdirTemp("benchmark-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create benchmark directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	samplePath := filepath.Join(dir, "sample.mkv")
+	if err := s.ffmpeg.CreateBenchmarkSample(ctx, samplePath); err != nil {
+		return nil, err
+	}
+
+	report := &models.EncoderBenchmarkReport{RanAt: time.Now()}
+	for i, encoder := range encoders {
+		result := models.EncoderBenchmark{
+			Encoder:  encoder,
+			Codec:    ffmpeg.EncoderCodec(encoder),
+			Hardware: ffmpeg.IsHardwareEncoder(encoder),
+		}
+
+		measured, err := s.ffmpeg.BenchmarkEncoder(ctx, samplePath, filepath.Join(dir, encoder+".mp4"), encoder)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			s.log.Info("Encoder %s is unusable: %v", encoder, err)
+			result.Error = err.Error()
+		} else {
+			result.FPS = measured.FPS
+			result.Speed = measured.Speed
+			result.Size = measured.Size
+			result.SSIM = measured.SSIM
+			s.log.Info("Encoder %s: %.1f fps, %d bytes, SSIM %.4f", encoder, result.FPS, result.Size, result.SSIM)
+		}
+		report.Results = append(report.Results, result)
+
+		if progressCallback != nil {
+			progressCallback(i+1, len(encoders))
+		}
+	}
+	report.Recommended = recommendEncoders(report.Results)
+
+	if err := s.save(report); err != nil {
+		return nil, err
+	}
+	s.ffmpeg.SetPreferredEncoders(report.Recommended)
+	s.log.Info("Recommended encoders: %v", report.Recommended)
+	return report, nil
+}
+
+// GetEncoderBenchmark returns the results of the last benchmark, or nil if
+// none was run on this machine
+func (s *encoderBenchmarkServiceImpl) GetEncoderBenchmark() (*models.EncoderBenchmarkReport, error) {
+	data, err := os.ReadFile(s.resultsPath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read encoder benchmark: %w", err)
+	}
+
+	var report models.EncoderBenchmarkReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse encoder benchmark: %w", err)
+	}
+	return &report, nil
+}
+
+// save writes a benchmark report to the results file
+func (s *encoderBenchmarkServiceImpl) save(report *models.EncoderBenchmarkReport) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode encoder benchmark: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.resultsPath), 0755); err != nil {
+		return fmt.Errorf("failed to create directory for encoder benchmark: %w", err)
+	}
+	if err := os.WriteFile(s.resultsPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to save encoder benchmark: %w", err)
+	}
+	return nil
+}
+
+// recommendEncoders picks the fastest encoder of each codec whose quality
+// is close to the codec's software encoder. Hardware encoders are usually
+// faster, but some produce visibly worse output at their default settings.
+func recommendEncoders(results []models.EncoderBenchmark) map[string]string {
+	byCodec := make(map[string][]models.EncoderBenchmark)
+	for _, result := range results {
+		if result.Error == "" {
+			byCodec[result.Codec] = append(byCodec[result.Codec], result)
+		}
+	}
+
+	recommended := make(map[string]string)
+	for codec, candidates := range byCodec {
+		minSSIM := benchmarkMinSSIM
+		for _, candidate := range candidates {
+			if !candidate.Hardware {
+				minSSIM = candidate.SSIM - benchmarkSSIMTolerance
+			}
+		}
+
+		sort.Slice(candidates, func(i, j int) bool {
+			return candidates[i].FPS > candidates[j].FPS
+		})
+		for _, candidate := range candidates {
+			if candidate.SSIM >= minSSIM {
+				recommended[codec] = candidate.Encoder
+				break
+			}
+		}
+	}
+	return recommended
+}
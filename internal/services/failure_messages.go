@@ -0,0 +1,131 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"converzen/internal/models"
+	"converzen/pkg/ffmpeg"
+)
+
+// failureExplanation translates one common conversion failure into a
+// message that says what went wrong and what to try
+type failureExplanation struct {
+	pattern *regexp.Regexp
+
+	// messages by language. A %s verb receives the pattern's first
+	// submatch, e.g. the missing encoder's name.
+	messages map[models.Language]string
+}
+
+// failureExplanations are checked in order against the error and FFmpeg's
+// output, so more specific causes come before generic ones
+var failureExplanations = []failureExplanation{
+	{
+		// Missing encoder
+		pattern: regexp.MustCompile(`(?:Unknown encoder|Encoder) '([^']+)'(?: not found)?`),
+		messages: map[models.Language]string{
+			models.LanguageEnglish: "This FFmpeg build has no %s encoder. Choose another output format or codec, or install an FFmpeg build that includes it.",
+			models.LanguageDanish:  "Denne FFmpeg-version har ingen %s-encoder. Vælg et andet outputformat eller codec, eller installer en FFmpeg-version, der indeholder den.",
+			models.LanguageGerman:  "Diese FFmpeg-Version hat keinen %s-Encoder. Wähle ein anderes Ausgabeformat oder einen anderen Codec, oder installiere eine FFmpeg-Version, die ihn enthält.",
+		},
+	},
+	{
+		// Unsupported pixel format
+		pattern: regexp.MustCompile(`(?i)pixel format '?(\w+)'? is (?:invalid or )?not supported|does not support (?:the )?pixel format '?(\w+)`),
+		messages: map[models.Language]string{
+			models.LanguageEnglish: "The encoder can't write the video's color format (%s). Turn off transparency or choose a different codec, e.g. H.264 instead of a hardware or ProRes 4444 encoder.",
+			models.LanguageDanish:  "Encoderen kan ikke skrive videoens farveformat (%s). Slå gennemsigtighed fra, eller vælg et andet codec, f.eks. H.264 i stedet for en hardware- eller ProRes 4444-encoder.",
+			models.LanguageGerman:  "Der Encoder kann das Farbformat des Videos (%s) nicht schreiben. Schalte Transparenz aus oder wähle einen anderen Codec, z. B. H.264 statt eines Hardware- oder ProRes-4444-Encoders.",
+		},
+	},
+	{
+		// Moov atom not found
+		pattern: regexp.MustCompile(`moov atom not found`),
+		messages: map[models.Language]string{
+			models.LanguageEnglish: "The file is incomplete: its index (the moov atom) is missing, usually because a recording or download was interrupted. Copy or download the file again, or rebuild it with a repair tool such as untrunc.",
+			models.LanguageDanish:  "Filen er ufuldstændig: dens indeks (moov-atomet) mangler, som regel fordi en optagelse eller download blev afbrudt. Kopiér eller download filen igen, eller genopbyg den med et reparationsværktøj som untrunc.",
+			models.LanguageGerman:  "Die Datei ist unvollständig: Ihr Index (das moov-Atom) fehlt, meist weil eine Aufnahme oder ein Download abgebrochen wurde. Kopiere oder lade die Datei erneut herunter oder stelle sie mit einem Reparaturwerkzeug wie untrunc wieder her.",
+		},
+	},
+	{
+		// Permission denied
+		pattern: regexp.MustCompile(`(?i)permission denied|operation not permitted|access is denied`),
+		messages: map[models.Language]string{
+			models.LanguageEnglish: "The app isn't allowed to read the input or write to the output folder. Check the file and folder permissions, or choose the output folder again to grant access to it.",
+			models.LanguageDanish:  "Appen har ikke adgang til at læse inputfilen eller skrive til outputmappen. Tjek fil- og mappetilladelserne, eller vælg outputmappen igen for at give adgang til den.",
+			models.LanguageGerman:  "Die App darf die Eingabedatei nicht lesen oder nicht in den Ausgabeordner schreiben. Prüfe die Datei- und Ordnerberechtigungen oder wähle den Ausgabeordner erneut aus, um Zugriff zu gewähren.",
+		},
+	},
+	{
+		// Disk full
+		pattern: regexp.MustCompile(`(?i)no space left on device|not enough space on the disk`),
+		messages: map[models.Language]string{
+			models.LanguageEnglish: "The disk is full. Free up space or choose an output folder on another drive.",
+			models.LanguageDanish:  "Disken er fuld. Frigør plads, eller vælg en outputmappe på et andet drev.",
+			models.LanguageGerman:  "Der Datenträger ist voll. Gib Speicherplatz frei oder wähle einen Ausgabeordner auf einem anderen Laufwerk.",
+		},
+	},
+	{
+		// Invalid data
+		pattern: regexp.MustCompile(`Invalid data found when processing input`),
+		messages: map[models.Language]string{
+			models.LanguageEnglish: "FFmpeg can't read the input: it isn't a supported media file or it is damaged. Check that it plays in a media player, or convert it with salvage turned on.",
+			models.LanguageDanish:  "FFmpeg kan ikke læse inputfilen: den er ikke en understøttet mediefil, eller den er beskadiget. Tjek, at den kan afspilles i en medieafspiller, eller konvertér den med redning slået til.",
+			models.LanguageGerman:  "FFmpeg kann die Eingabedatei nicht lesen: Sie ist keine unterstützte Mediendatei oder beschädigt. Prüfe, ob sie sich in einem Mediaplayer abspielen lässt, oder konvertiere sie mit aktivierter Rettung.",
+		},
+	},
+}
+
+// explainedError is a conversion error replaced by a user-facing
+// explanation. The original error, with FFmpeg's output, stays available
+// through errors.Unwrap and in the conversion log.
+type explainedError struct {
+	message string
+	err     error
+}
+
+func (e *explainedError) Error() string { return e.message }
+func (e *explainedError) Unwrap() error { return e.err }
+
+// explainFailure returns err explained in language when it is a common
+// failure with a known fix, and err itself otherwise
+func explainFailure(err error, language models.Language) error {
+	if err == nil {
+		return nil
+	}
+
+	text := err.Error()
+	var ffmpegErr *ffmpeg.Error
+	if errors.As(err, &ffmpegErr) {
+		text += "\n" + ffmpegErr.Output
+	}
+
+	for _, explanation := range failureExplanations {
+		matches := explanation.pattern.FindStringSubmatch(text)
+		if matches == nil {
+			continue
+		}
+
+		message, ok := explanation.messages[language]
+		if !ok {
+			message = explanation.messages[models.LanguageEnglish]
+		}
+
+		// Fill in the first submatch that matched, if the message uses one
+		detail := "?"
+		for _, submatch := range matches[1:] {
+			if submatch != "" {
+				detail = submatch
+				break
+			}
+		}
+		if strings.Contains(message, "%s") {
+			message = fmt.Sprintf(message, detail)
+		}
+		return &explainedError{message: message, err: err}
+	}
+	return err
+}
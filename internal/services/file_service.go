@@ -4,10 +4,12 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 
 	"converzen/internal/logger"
 	"converzen/internal/models"
+	"converzen/pkg/cloudfile"
 )
 
 // fileServiceImpl implements FileService
@@ -61,6 +63,9 @@ func (s *fileServiceImpl) GetFileInfo(path string) (*models.FileInfo, error) {
 		Size:      stat.Size(),
 		Type:      fileType,
 	}
+	if placeholder, err := cloudfile.IsPlaceholder(path); err == nil {
+		info.Placeholder = placeholder
+	}
 
 	s.log.Debug("File info retrieved: %s (type: %s, size: %d bytes)", info.Name, info.Type, info.Size)
 	return info, nil
@@ -123,7 +128,7 @@ func (s *fileServiceImpl) GenerateOutputPath(inputPath, outputDir, outputFormat
 	var baseName string
 
 	switch namingMode {
-	case models.NamingModeCustom:
+	case models.NamingModeCustom, models.NamingModeTemplate:
 		if customName != "" {
 			baseName = customName
 		} else {
@@ -147,3 +152,68 @@ func (s *fileServiceImpl) FileExists(path string) bool {
 	_, err := os.Stat(path)
 	return err == nil
 }
+
+// CheckOutputDirectory verifies converted files can be written to dir by
+// creating and removing a probe file, so a read-only destination fails once
+// up front instead of once per file. Cloud-synced folders whose files may be
+// online-only placeholders are rejected as well.
+func (s *fileServiceImpl) CheckOutputDirectory(dir string) error {
+	dir = filepath.Clean(dir)
+	s.log.Debug("Checking output directory: %s", dir)
+
+	for _, root := range cloudStorageRoots() {
+		if rel, err := filepath.Rel(root, dir); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return fmt.Errorf("output directory %s is in a cloud-synced folder; choose a local folder instead", dir)
+		}
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("cannot create output directory %s: %w", dir, err)
+	}
+
+	probe, err := os.CreateTemp(dir, ".converzen-write-test-*")
+	if err != nil {
+		return fmt.Errorf("output directory %s is not writable: %w", dir, err)
+	}
+	probe.Close()
+	if err := os.Remove(probe.Name()); err != nil {
+		s.log.Warn("Failed to remove write test file %s: %v", probe.Name(), err)
+	}
+	return nil
+}
+
+// cloudStorageRoots returns the folders synced by cloud storage providers,
+// where files can be online-only placeholders
+func cloudStorageRoots() []string {
+	var roots []string
+	switch runtime.GOOS {
+	case "darwin":
+		if home, err := os.UserHomeDir(); err == nil {
+			roots = append(roots,
+				filepath.Join(home, "Library", "Mobile Documents"), // iCloud Drive
+				filepath.Join(home, "Library", "CloudStorage"),     // File Provider (OneDrive, Dropbox, Google Drive)
+			)
+		}
+	case "windows":
+		for _, env := range []string{"OneDrive", "OneDriveConsumer", "OneDriveCommercial"} {
+			if root := os.Getenv(env); root != "" {
+				roots = append(roots, root)
+			}
+		}
+	}
+	return roots
+}
+
+// uniqueDirs returns the distinct parent directories of paths, in order
+func uniqueDirs(paths []string) []string {
+	seen := make(map[string]bool)
+	var dirs []string
+	for _, path := range paths {
+		dir := filepath.Dir(path)
+		if !seen[dir] {
+			seen[dir] = true
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
@@ -11,12 +11,39 @@ type FormatProvider interface {
 	// GetSupportedImageInputFormats returns the list of supported image input formats
 	GetSupportedImageInputFormats() []string
 
+	// GetSupportedAudioInputFormats returns the list of supported audio input formats
+	GetSupportedAudioInputFormats() []string
+
+	// GetSupportedSubtitleInputFormats returns the list of supported subtitle input formats
+	GetSupportedSubtitleInputFormats() []string
+
+	// GetSupportedEbookInputFormats returns the list of supported e-book input formats
+	GetSupportedEbookInputFormats() []string
+
+	// GetSupportedDocumentInputFormats returns the list of supported document input formats
+	GetSupportedDocumentInputFormats() []string
+
 	// GetSupportedVideoOutputFormats returns the list of supported video output formats
 	GetSupportedVideoOutputFormats() []string
 
 	// GetSupportedImageOutputFormats returns the list of supported image output formats
 	GetSupportedImageOutputFormats() []string
 
+	// GetSupportedAudioOutputFormats returns the list of supported audio output formats
+	GetSupportedAudioOutputFormats() []string
+
+	// GetSupportedSubtitleOutputFormats returns the list of supported subtitle output formats
+	GetSupportedSubtitleOutputFormats() []string
+
+	// GetSupportedEbookOutputFormats returns the list of supported e-book output formats
+	GetSupportedEbookOutputFormats() []string
+
+	// GetSupportedDocumentOutputFormats returns the list of supported document output formats
+	GetSupportedDocumentOutputFormats() []string
+
+	// GetSupportedVideoCodecs returns the professional video codecs available for an output format
+	GetSupportedVideoCodecs(outputFormat string) []models.VideoCodec
+
 	// GetSupportedFormats returns all supported formats for a given file type
 	GetSupportedFormats(fileType models.FileType) []string
 
@@ -26,6 +53,18 @@ type FormatProvider interface {
 	// CanConvertImage checks if image conversion to the specified format is supported
 	CanConvertImage(outputFormat string) bool
 
+	// CanConvertAudio checks if audio conversion to the specified format is supported
+	CanConvertAudio(outputFormat string) bool
+
+	// CanConvertSubtitle checks if subtitle conversion to the specified format is supported
+	CanConvertSubtitle(outputFormat string) bool
+
+	// CanConvertEbook checks if e-book conversion to the specified format is supported
+	CanConvertEbook(outputFormat string) bool
+
+	// CanConvertDocument checks if document conversion to the specified format is supported
+	CanConvertDocument(outputFormat string) bool
+
 	// GetBackendName returns the name of the conversion backend (e.g., "ffmpeg", "avfoundation")
 	GetBackendName() string
 }
@@ -33,18 +72,26 @@ type FormatProvider interface {
 // formatProvider implements FormatProvider by delegating to the actual converters
 // This follows the Single Responsibility Principle (SRP) - only handles format queries
 type formatProvider struct {
-	videoConverter Converter
-	imageConverter Converter
-	backendName    string
+	videoConverter    Converter
+	imageConverter    Converter
+	audioConverter    Converter
+	subtitleConverter Converter
+	ebookConverter    Converter
+	documentConverter Converter
+	backendName       string
 }
 
 // NewFormatProvider creates a new FormatProvider
 // This follows the Dependency Inversion Principle (DIP) - depends on Converter interface, not concrete implementations
-func NewFormatProvider(videoConverter Converter, imageConverter Converter, backendName string) FormatProvider {
+func NewFormatProvider(videoConverter Converter, imageConverter Converter, audioConverter Converter, subtitleConverter Converter, ebookConverter Converter, documentConverter Converter, backendName string) FormatProvider {
 	return &formatProvider{
-		videoConverter: videoConverter,
-		imageConverter: imageConverter,
-		backendName:    backendName,
+		videoConverter:    videoConverter,
+		imageConverter:    imageConverter,
+		audioConverter:    audioConverter,
+		subtitleConverter: subtitleConverter,
+		ebookConverter:    ebookConverter,
+		documentConverter: documentConverter,
+		backendName:       backendName,
 	}
 }
 
@@ -64,6 +111,38 @@ func (p *formatProvider) GetSupportedImageInputFormats() []string {
 	return p.imageConverter.SupportedInputFormats()
 }
 
+// GetSupportedAudioInputFormats returns the list of supported audio input formats
+func (p *formatProvider) GetSupportedAudioInputFormats() []string {
+	if p.audioConverter == nil {
+		return []string{}
+	}
+	return p.audioConverter.SupportedInputFormats()
+}
+
+// GetSupportedSubtitleInputFormats returns the list of supported subtitle input formats
+func (p *formatProvider) GetSupportedSubtitleInputFormats() []string {
+	if p.subtitleConverter == nil {
+		return []string{}
+	}
+	return p.subtitleConverter.SupportedInputFormats()
+}
+
+// GetSupportedEbookInputFormats returns the list of supported e-book input formats
+func (p *formatProvider) GetSupportedEbookInputFormats() []string {
+	if p.ebookConverter == nil {
+		return []string{}
+	}
+	return p.ebookConverter.SupportedInputFormats()
+}
+
+// GetSupportedDocumentInputFormats returns the list of supported document input formats
+func (p *formatProvider) GetSupportedDocumentInputFormats() []string {
+	if p.documentConverter == nil {
+		return []string{}
+	}
+	return p.documentConverter.SupportedInputFormats()
+}
+
 // GetSupportedVideoOutputFormats returns the list of supported video output formats
 func (p *formatProvider) GetSupportedVideoOutputFormats() []string {
 	if p.videoConverter == nil {
@@ -80,6 +159,48 @@ func (p *formatProvider) GetSupportedImageOutputFormats() []string {
 	return p.imageConverter.SupportedOutputFormats("")
 }
 
+// GetSupportedAudioOutputFormats returns the list of supported audio output formats
+func (p *formatProvider) GetSupportedAudioOutputFormats() []string {
+	if p.audioConverter == nil {
+		return []string{}
+	}
+	return p.audioConverter.SupportedOutputFormats("")
+}
+
+// GetSupportedSubtitleOutputFormats returns the list of supported subtitle output formats
+func (p *formatProvider) GetSupportedSubtitleOutputFormats() []string {
+	if p.subtitleConverter == nil {
+		return []string{}
+	}
+	return p.subtitleConverter.SupportedOutputFormats("")
+}
+
+// GetSupportedEbookOutputFormats returns the list of supported e-book output formats
+func (p *formatProvider) GetSupportedEbookOutputFormats() []string {
+	if p.ebookConverter == nil {
+		return []string{}
+	}
+	return p.ebookConverter.SupportedOutputFormats("")
+}
+
+// GetSupportedDocumentOutputFormats returns the list of supported document output formats
+func (p *formatProvider) GetSupportedDocumentOutputFormats() []string {
+	if p.documentConverter == nil {
+		return []string{}
+	}
+	return p.documentConverter.SupportedOutputFormats("")
+}
+
+// GetSupportedVideoCodecs returns the professional video codecs the video
+// backend can encode for an output format
+func (p *formatProvider) GetSupportedVideoCodecs(outputFormat string) []models.VideoCodec {
+	codecs, ok := p.videoConverter.(CodecProvider)
+	if !ok {
+		return []models.VideoCodec{}
+	}
+	return codecs.SupportedVideoCodecs(outputFormat)
+}
+
 // GetSupportedFormats returns all supported formats for a given file type
 func (p *formatProvider) GetSupportedFormats(fileType models.FileType) []string {
 	switch fileType {
@@ -87,6 +208,14 @@ func (p *formatProvider) GetSupportedFormats(fileType models.FileType) []string
 		return p.GetSupportedVideoOutputFormats()
 	case models.FileTypeImage:
 		return p.GetSupportedImageOutputFormats()
+	case models.FileTypeAudio:
+		return p.GetSupportedAudioOutputFormats()
+	case models.FileTypeSubtitle:
+		return p.GetSupportedSubtitleOutputFormats()
+	case models.FileTypeEbook:
+		return p.GetSupportedEbookOutputFormats()
+	case models.FileTypeDocument:
+		return p.GetSupportedDocumentOutputFormats()
 	default:
 		return []string{}
 	}
@@ -118,6 +247,58 @@ func (p *formatProvider) CanConvertImage(outputFormat string) bool {
 	return false
 }
 
+// CanConvertAudio checks if audio conversion to the specified format is supported
+func (p *formatProvider) CanConvertAudio(outputFormat string) bool {
+	if p.audioConverter == nil {
+		return false
+	}
+	for _, format := range p.GetSupportedAudioOutputFormats() {
+		if format == outputFormat {
+			return true
+		}
+	}
+	return false
+}
+
+// CanConvertSubtitle checks if subtitle conversion to the specified format is supported
+func (p *formatProvider) CanConvertSubtitle(outputFormat string) bool {
+	if p.subtitleConverter == nil {
+		return false
+	}
+	for _, format := range p.GetSupportedSubtitleOutputFormats() {
+		if format == outputFormat {
+			return true
+		}
+	}
+	return false
+}
+
+// CanConvertEbook checks if e-book conversion to the specified format is supported
+func (p *formatProvider) CanConvertEbook(outputFormat string) bool {
+	if p.ebookConverter == nil {
+		return false
+	}
+	for _, format := range p.GetSupportedEbookOutputFormats() {
+		if format == outputFormat {
+			return true
+		}
+	}
+	return false
+}
+
+// CanConvertDocument checks if document conversion to the specified format is supported
+func (p *formatProvider) CanConvertDocument(outputFormat string) bool {
+	if p.documentConverter == nil {
+		return false
+	}
+	for _, format := range p.GetSupportedDocumentOutputFormats() {
+		if format == outputFormat {
+			return true
+		}
+	}
+	return false
+}
+
 // GetBackendName returns the name of the conversion backend
 func (p *formatProvider) GetBackendName() string {
 	return p.backendName
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"converzen/internal/models"
+)
+
+// frameGrabTimeout bounds extracting a single full-size frame
+const frameGrabTimeout = 30 * time.Second
+
+// frameGrabFormats are the image formats a frame can be saved as
+var frameGrabFormats = map[string]bool{
+	"png": true, "jpg": true, "jpeg": true, "bmp": true, "tiff": true, "webp": true,
+}
+
+// GrabFrame saves the frame of a video at a timestamp as an image and
+// returns the saved path. The timestamp is in seconds ("83.5") or
+// [HH:]MM:SS[.mmm] form ("1:23.5"). An empty outputPath, or a folder,
+// saves next to the input as "<name>-<timestamp>.<format>" without
+// replacing an existing file; an explicit file path is replaced.
+func (s *thumbnailServiceImpl) GrabFrame(path, timestamp, format, outputPath string) (string, error) {
+	if s.ffmpeg == nil {
+		return "", fmt.Errorf("frame grabs require FFmpeg")
+	}
+
+	fileInfo, err := s.fileService.GetFileInfo(path)
+	if err != nil {
+		return "", err
+	}
+	if fileInfo.Type != models.FileTypeVideo {
+		return "", fmt.Errorf("frames can only be grabbed from video files")
+	}
+
+	format = strings.TrimPrefix(strings.ToLower(format), ".")
+	if format == "" {
+		format = "png"
+	}
+	if !frameGrabFormats[format] {
+		return "", fmt.Errorf("unsupported image format for frame grabs: %s", format)
+	}
+
+	at, err := parseTimestamp(timestamp)
+	if err != nil {
+		return "", err
+	}
+	if probe, err := s.ffmpeg.ProbeFile(path); err == nil && probe.Duration > 0 && at >= probe.Duration {
+		return "", fmt.Errorf("%s is past the end of the video (%s)", timestamp, probe.Duration.Round(time.Millisecond))
+	}
+
+	stamp := strings.ReplaceAll(ffmpegTimecode(at), ":", "-")
+	outputPath, err = stillPath(path, outputPath, stamp, format)
+	if err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), frameGrabTimeout)
+	defer cancel()
+	if err := s.ffmpeg.GrabFrame(ctx, path, outputPath, at); err != nil {
+		return "", err
+	}
+
+	// FFmpeg succeeds without writing anything when the seek lands past the
+	// last frame, e.g. when the duration couldn't be probed
+	if stat, err := os.Stat(outputPath); err != nil || stat.Size() == 0 {
+		os.Remove(outputPath)
+		return "", fmt.Errorf("no frame found at %s", timestamp)
+	}
+	return outputPath, nil
+}
+
+// stillPath resolves where an image made from a video is saved. An explicit
+// file path is used as-is, with the format's extension added if it has none.
+// Otherwise the image is named "<name>-<suffix>.<format>" in the folder
+// given, or next to the input, with a " (n)" suffix if the name is taken.
+func stillPath(inputPath, outputPath, suffix, format string) (string, error) {
+	if outputPath != "" {
+		if stat, err := os.Stat(outputPath); err != nil || !stat.IsDir() {
+			if filepath.Ext(outputPath) == "" {
+				outputPath += "." + format
+			}
+			return outputPath, nil
+		}
+	}
+
+	dir := filepath.Dir(inputPath)
+	if outputPath != "" {
+		dir = outputPath
+	}
+	name := strings.TrimSuffix(filepath.Base(inputPath), filepath.Ext(inputPath))
+	return freePath(filepath.Join(dir, fmt.Sprintf("%s-%s.%s", name, suffix, format)))
+}
+
+// ffmpegTimecode formats a position as HH:MM:SS.mmm
+func ffmpegTimecode(d time.Duration) string {
+	ms := d.Milliseconds()
+	return fmt.Sprintf("%02d:%02d:%02d.%03d", ms/3600000, ms/60000%60, ms/1000%60, ms%1000)
+}
+
+// parseTimestamp parses a position in seconds ("83.5") or [HH:]MM:SS[.mmm]
+// form ("1:23.5")
+func parseTimestamp(timestamp string) (time.Duration, error) {
+	timestamp = strings.TrimSpace(timestamp)
+	parts := strings.Split(timestamp, ":")
+	if timestamp == "" || len(parts) > 3 {
+		return 0, fmt.Errorf("invalid timestamp %q, use seconds or HH:MM:SS", timestamp)
+	}
+
+	var seconds float64
+	for i, part := range parts {
+		value, err := strconv.ParseFloat(part, 64)
+		if err != nil || value < 0 || (i > 0 && value >= 60) || (i < len(parts)-1 && strings.Contains(part, ".")) {
+			return 0, fmt.Errorf("invalid timestamp %q, use seconds or HH:MM:SS", timestamp)
+		}
+		seconds = seconds*60 + value
+	}
+	return time.Duration(seconds * float64(time.Second)), nil
+}
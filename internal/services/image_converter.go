@@ -1,11 +1,14 @@
 package services
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"image"
 	"image/gif"
 	"image/jpeg"
 	"image/png"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -17,6 +20,8 @@ import (
 
 	"converzen/internal/logger"
 	"converzen/internal/models"
+	"converzen/pkg/icc"
+	"converzen/pkg/iolimit"
 )
 
 // imageConverter handles image file conversion
@@ -32,7 +37,7 @@ func NewImageConverter(log *logger.Logger) Converter {
 }
 
 // Convert converts an image file to another format
-func (c *imageConverter) Convert(job models.ConversionJob, progressCallback func(progress float64)) (*models.ConversionResult, error) {
+func (c *imageConverter) Convert(ctx context.Context, job models.ConversionJob, progressCallback func(progress float64)) (*models.ConversionResult, error) {
 	c.log.Info("Starting image conversion: %s -> %s", job.InputPath, job.OutputPath)
 	startTime := time.Now()
 
@@ -54,31 +59,41 @@ func (c *imageConverter) Convert(job models.ConversionJob, progressCallback func
 		return result, fmt.Errorf("%s", result.ErrorMessage)
 	}
 	defer inputFile.Close()
+	input := iolimit.Reader(inputFile, job.IOLimit())
 
 	if progressCallback != nil {
 		progressCallback(20)
 	}
 
+	// Read the whole file, as the color profile is read from it as well
+	data, err := io.ReadAll(input)
+	if err != nil {
+		result.ErrorMessage = fmt.Sprintf("Failed to read input file: %v", err)
+		c.log.Error("%s", result.ErrorMessage)
+		return result, fmt.Errorf("%s", result.ErrorMessage)
+	}
+
 	// Decode input image
 	var img image.Image
 	inputFormat := strings.ToLower(strings.TrimPrefix(filepath.Ext(job.InputPath), "."))
+	reader := bytes.NewReader(data)
 
 	switch inputFormat {
 	case "png":
-		img, err = png.Decode(inputFile)
+		img, err = png.Decode(reader)
 	case "jpg", "jpeg":
-		img, err = jpeg.Decode(inputFile)
+		img, err = jpeg.Decode(reader)
 	case "gif":
-		img, err = gif.Decode(inputFile)
+		img, err = gif.Decode(reader)
 	case "webp":
-		img, err = webp.Decode(inputFile)
+		img, err = webp.Decode(reader)
 	case "bmp":
-		img, err = bmp.Decode(inputFile)
+		img, err = bmp.Decode(reader)
 	case "tiff", "tif":
-		img, err = tiff.Decode(inputFile)
+		img, err = tiff.Decode(reader)
 	default:
 		// Try generic decode
-		img, _, err = image.Decode(inputFile)
+		img, _, err = image.Decode(reader)
 	}
 
 	if err != nil {
@@ -91,6 +106,28 @@ func (c *imageConverter) Convert(job models.ConversionJob, progressCallback func
 		progressCallback(50)
 	}
 
+	if err := ctx.Err(); err != nil {
+		result.ErrorMessage = "Conversion was cancelled"
+		c.log.Warn("%s: %s", result.ErrorMessage, job.InputPath)
+		return result, err
+	}
+
+	// Resize if requested
+	if job.MaxWidth > 0 || job.MaxHeight > 0 {
+		bounds := img.Bounds()
+		img = scaleImage(img, job.MaxWidth, job.MaxHeight, job.Scaler)
+		c.log.Debug("Resized image from %dx%d to %dx%d (scaler: %s)",
+			bounds.Dx(), bounds.Dy(), img.Bounds().Dx(), img.Bounds().Dy(), job.Scaler)
+	}
+
+	outputFormat := strings.ToLower(strings.TrimPrefix(filepath.Ext(job.OutputPath), "."))
+
+	// Carry the color profile over, or convert the colors to sRGB
+	var profile []byte
+	if embedded := icc.Extract(inputFormat, data); embedded != nil {
+		img, profile = c.applyColorProfile(img, embedded, outputFormat, job.ColorProfile, result)
+	}
+
 	// Check output directory exists
 	outputDir := filepath.Dir(job.OutputPath)
 	if err := os.MkdirAll(outputDir, 0755); err != nil {
@@ -116,25 +153,31 @@ func (c *imageConverter) Convert(job models.ConversionJob, progressCallback func
 		return result, fmt.Errorf("%s", result.ErrorMessage)
 	}
 	defer outputFile.Close()
+	output := iolimit.Writer(outputFile, job.IOLimit())
 
 	if progressCallback != nil {
 		progressCallback(70)
 	}
 
-	// Encode to output format
-	outputFormat := strings.ToLower(strings.TrimPrefix(filepath.Ext(job.OutputPath), "."))
+	// Encode to output format. Images with a profile are encoded in memory
+	// first, to embed it.
+	encoded := output
+	var buffer bytes.Buffer
+	if profile != nil {
+		encoded = &buffer
+	}
 
 	switch outputFormat {
 	case "png":
-		err = png.Encode(outputFile, img)
+		err = png.Encode(encoded, img)
 	case "jpg", "jpeg":
-		err = jpeg.Encode(outputFile, img, &jpeg.Options{Quality: 90})
+		err = jpeg.Encode(encoded, img, &jpeg.Options{Quality: 90})
 	case "gif":
-		err = gif.Encode(outputFile, img, nil)
+		err = gif.Encode(encoded, img, nil)
 	case "bmp":
-		err = bmp.Encode(outputFile, img)
+		err = bmp.Encode(encoded, img)
 	case "tiff", "tif":
-		err = tiff.Encode(outputFile, img, nil)
+		err = tiff.Encode(encoded, img, nil)
 	case "webp":
 		// WebP encoding requires a different library, fall back to PNG for now
 		// In production, use github.com/chai2010/webp or similar
@@ -147,6 +190,12 @@ func (c *imageConverter) Convert(job models.ConversionJob, progressCallback func
 		return result, fmt.Errorf("%s", result.ErrorMessage)
 	}
 
+	if err == nil && profile != nil {
+		var withProfile []byte
+		if withProfile, err = icc.Embed(outputFormat, buffer.Bytes(), profile); err == nil {
+			_, err = output.Write(withProfile)
+		}
+	}
 	if err != nil {
 		result.ErrorMessage = fmt.Sprintf("Failed to encode image: %v", err)
 		c.log.Error("%s", result.ErrorMessage)
@@ -169,6 +218,50 @@ func (c *imageConverter) Convert(job models.ConversionJob, progressCallback func
 	return result, nil
 }
 
+// applyColorProfile handles an image's embedded ICC profile per mode and
+// notes what happened in the result. It returns the image, converted to
+// sRGB if need be, and the profile to embed in the output, if any.
+func (c *imageConverter) applyColorProfile(img image.Image, data []byte, outputFormat string, mode models.ColorProfileMode, result *models.ConversionResult) (image.Image, []byte) {
+	profile, err := icc.Parse(data)
+	if err != nil {
+		c.log.Warn("Ignoring unreadable color profile: %v", err)
+		return img, nil
+	}
+
+	drop := func(reason string) (image.Image, []byte) {
+		result.ColorProfile = models.ColorProfileDropped
+		result.Warnings = append(result.Warnings, fmt.Sprintf("The %s color profile was dropped, so colors may shift: %s", profile.Name(), reason))
+		return img, nil
+	}
+
+	// The encoders write RGB, which only an RGB profile describes
+	if profile.ColorSpace != "RGB" {
+		return drop(fmt.Sprintf("%s profiles can't be carried over", profile.ColorSpace))
+	}
+
+	if mode != models.ColorProfileSRGB && icc.CanEmbed(outputFormat) {
+		c.log.Debug("Embedding color profile %s", profile.Name())
+		result.ColorProfile = models.ColorProfileEmbedded
+		return img, data
+	}
+
+	if profile.IsSRGB() {
+		result.ColorProfile = models.ColorProfileConverted
+		return img, nil
+	}
+	if !profile.CanConvert() {
+		return drop("it can't be converted to sRGB")
+	}
+
+	converted, err := profile.ToSRGB(img)
+	if err != nil {
+		return drop(err.Error())
+	}
+	c.log.Debug("Converted colors from %s to sRGB", profile.Name())
+	result.ColorProfile = models.ColorProfileConverted
+	return converted, nil
+}
+
 // SupportedInputFormats returns the list of supported input image formats
 func (c *imageConverter) SupportedInputFormats() []string {
 	formats := make([]string, 0, len(models.ImageFormats))
@@ -210,3 +303,8 @@ func (c *imageConverter) CanConvert(inputFormat, outputFormat string) bool {
 
 	return false
 }
+
+// Backend reports that jobs are converted with Go's built-in image codecs
+func (c *imageConverter) Backend(job models.ConversionJob) string {
+	return "builtin"
+}
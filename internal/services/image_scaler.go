@@ -0,0 +1,55 @@
+package services
+
+import (
+	"image"
+
+	"golang.org/x/image/draw"
+
+	"converzen/internal/models"
+)
+
+// interpolatorFor returns the draw interpolator for a scaler setting.
+// All scalers write into an *image.RGBA, which hits the specialised fast
+// paths in x/image/draw for the decoders' native image types.
+func interpolatorFor(scaler models.ImageScaler) draw.Interpolator {
+	switch scaler {
+	case models.ScalerQuality:
+		return draw.CatmullRom
+	case models.ScalerFast:
+		return draw.ApproxBiLinear
+	default:
+		return draw.BiLinear
+	}
+}
+
+// fitDimensions returns the size of a width x height image scaled down to fit
+// within maxWidth x maxHeight, preserving aspect ratio. A zero limit leaves
+// that dimension unconstrained and images are never scaled up.
+func fitDimensions(width, height, maxWidth, maxHeight int) (int, int) {
+	scale := 1.0
+	if maxWidth > 0 && width > maxWidth {
+		scale = float64(maxWidth) / float64(width)
+	}
+	if maxHeight > 0 && height > maxHeight {
+		scale = min(scale, float64(maxHeight)/float64(height))
+	}
+	if scale >= 1 {
+		return width, height
+	}
+
+	return max(1, int(float64(width)*scale+0.5)), max(1, int(float64(height)*scale+0.5))
+}
+
+// scaleImage resizes img to fit within maxWidth x maxHeight using the given
+// scaler. The original image is returned when no resize is needed.
+func scaleImage(img image.Image, maxWidth, maxHeight int, scaler models.ImageScaler) image.Image {
+	bounds := img.Bounds()
+	width, height := fitDimensions(bounds.Dx(), bounds.Dy(), maxWidth, maxHeight)
+	if width == bounds.Dx() && height == bounds.Dy() {
+		return img
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	interpolatorFor(scaler).Scale(dst, dst.Bounds(), img, bounds, draw.Src, nil)
+	return dst
+}
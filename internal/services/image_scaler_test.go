@@ -0,0 +1,76 @@
+package services
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"testing"
+
+	"converzen/internal/models"
+)
+
+// photo returns a width x height image of the type decoders produce for a
+// format, filled with a gradient so scalers can't take shortcuts
+func photo(width, height int, format string) image.Image {
+	rect := image.Rect(0, 0, width, height)
+	switch format {
+	case "jpeg":
+		img := image.NewYCbCr(rect, image.YCbCrSubsampleRatio420)
+		for i := range img.Y {
+			img.Y[i] = uint8(i)
+		}
+		for i := range img.Cb {
+			img.Cb[i], img.Cr[i] = uint8(i/3), uint8(i/5)
+		}
+		return img
+	default:
+		img := image.NewNRGBA(rect)
+		for y := 0; y < height; y++ {
+			for x := 0; x < width; x++ {
+				img.SetNRGBA(x, y, color.NRGBA{uint8(x), uint8(y), uint8(x + y), 255})
+			}
+		}
+		return img
+	}
+}
+
+// BenchmarkScaleImage compares the scalers on typical photo sizes, resized
+// to fit the longest side commonly used for sharing
+func BenchmarkScaleImage(b *testing.B) {
+	sizes := []struct {
+		name          string
+		width, height int
+		fit           int
+	}{
+		{"12MP", 4032, 3024, 2048},
+		{"24MP", 6000, 4000, 2048},
+		{"48MP", 8064, 6048, 1920},
+	}
+	scalers := []models.ImageScaler{models.ScalerFast, models.ScalerBalanced, models.ScalerQuality}
+
+	for _, format := range []string{"jpeg", "png"} {
+		for _, size := range sizes {
+			src := photo(size.width, size.height, format)
+			for _, scaler := range scalers {
+				b.Run(fmt.Sprintf("%s/%s/%s", format, size.name, scaler), func(b *testing.B) {
+					b.ReportAllocs()
+					for b.Loop() {
+						scaleImage(src, size.fit, size.fit, scaler)
+					}
+				})
+			}
+		}
+	}
+}
+
+func TestScaleImage(t *testing.T) {
+	src := photo(400, 300, "jpeg")
+	for _, scaler := range []models.ImageScaler{models.ScalerFast, models.ScalerBalanced, models.ScalerQuality, ""} {
+		if got := scaleImage(src, 200, 200, scaler).Bounds(); got != image.Rect(0, 0, 200, 150) {
+			t.Errorf("%q scaler: bounds = %v, want 200x150", scaler, got)
+		}
+	}
+	if got := scaleImage(src, 800, 0, models.ScalerQuality); got != src {
+		t.Errorf("scaling up returned a new image, want the original")
+	}
+}
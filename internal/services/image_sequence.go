@@ -0,0 +1,271 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"converzen/internal/logger"
+	"converzen/internal/models"
+	"converzen/pkg/ffmpeg"
+)
+
+const (
+	// defaultSequenceFrameRate is the frame rate used when none is given
+	defaultSequenceFrameRate = 30
+
+	// maxSequenceFrameRate caps the frame rate of image sequences
+	maxSequenceFrameRate = 240
+)
+
+// findImageSequence finds the numbered frames that follow firstFrame, e.g.
+// frame_0001.png, frame_0002.png, ... up to the first missing number. The
+// pattern pads numbers to the first frame's digits, which also matches
+// unpadded frames as printf never shortens a number.
+func findImageSequence(firstFrame string) (*models.ImageSequence, error) {
+	dir, name := filepath.Split(firstFrame)
+	ext := filepath.Ext(name)
+	stem := strings.TrimSuffix(name, ext)
+
+	prefix := strings.TrimRight(stem, "0123456789")
+	digits := stem[len(prefix):]
+	if digits == "" {
+		return nil, fmt.Errorf("%s is not a numbered frame, e.g. frame_0001%s", name, ext)
+	}
+	start, err := strconv.Atoi(digits)
+	if err != nil {
+		return nil, fmt.Errorf("frame number of %s is out of range", name)
+	}
+
+	// A literal % in the path must not be read as a printf verb
+	pattern := strings.ReplaceAll(filepath.Join(dir, prefix), "%", "%%") + fmt.Sprintf("%%0%dd", len(digits)) + strings.ReplaceAll(ext, "%", "%%")
+	sequence := &models.ImageSequence{
+		Pattern:     pattern,
+		StartNumber: start,
+	}
+	for number := start; ; number++ {
+		stat, err := os.Stat(filepath.Join(dir, prefix+fmt.Sprintf("%0*d", len(digits), number)+ext))
+		if err != nil {
+			break
+		}
+		sequence.Frames++
+		sequence.Size += stat.Size()
+	}
+	if sequence.Frames == 0 {
+		return nil, fmt.Errorf("frame not found: %s", firstFrame)
+	}
+	return sequence, nil
+}
+
+// sequenceOutputName returns the output name for a sequence's first frame:
+// the frame name without its number and trailing separators, or the folder's
+// name for frames named by number only
+func sequenceOutputName(firstFrame string) string {
+	name := filepath.Base(firstFrame)
+	name = strings.TrimSuffix(name, filepath.Ext(name))
+	name = strings.TrimRight(name, "0123456789")
+	name = strings.TrimRight(name, "_-. ")
+	if name == "" {
+		name = filepath.Base(filepath.Dir(firstFrame))
+	}
+	return name
+}
+
+// ConvertImageSequence encodes numbered image files as a video named after
+// the frames, e.g. frame_0001.png ... to frame.webm. The encode runs as a
+// single cancellable job recorded in history.
+func (s *conversionServiceImpl) ConvertImageSequence(request models.ImageSequenceRequest, progressCallback func(progress models.ConversionProgress)) (*models.ConversionResult, error) {
+	if request.FrameRate == 0 {
+		request.FrameRate = defaultSequenceFrameRate
+	}
+	if request.FrameRate < 1 || request.FrameRate > maxSequenceFrameRate {
+		return nil, fmt.Errorf("frame rate must be between 1 and %d", maxSequenceFrameRate)
+	}
+	outputFormat := strings.TrimPrefix(strings.ToLower(request.OutputFormat), ".")
+	if !slices.Contains(models.VideoOutputFormats, outputFormat) || outputFormat == "gif" {
+		return nil, fmt.Errorf("image sequences can't be encoded as %q", request.OutputFormat)
+	}
+
+	encoder, ok := s.video().(SequenceEncoder)
+	if !ok {
+		return nil, fmt.Errorf("encoding image sequences requires FFmpeg")
+	}
+
+	fileInfo, err := s.fileService.GetFileInfo(request.FirstFrame)
+	if err != nil {
+		return nil, err
+	}
+	if fileInfo.Type != models.FileTypeImage {
+		return nil, fmt.Errorf("only image files can be encoded as a sequence")
+	}
+	sequence, err := findImageSequence(request.FirstFrame)
+	if err != nil {
+		return nil, err
+	}
+	sequence.FrameRate = request.FrameRate
+
+	outputDir := request.OutputDirectory
+	if outputDir == "" {
+		outputDir = filepath.Dir(request.FirstFrame)
+	}
+	if err := s.fileService.CheckOutputDirectory(outputDir); err != nil {
+		return nil, err
+	}
+
+	job := models.ConversionJob{
+		InputPath:       sequence.Pattern,
+		OutputPath:      filepath.Join(outputDir, sequenceOutputName(request.FirstFrame)+"."+outputFormat),
+		OutputFormat:    outputFormat,
+		PreserveAlpha:   request.PreserveAlpha,
+		OverwriteOutput: request.OverwriteOutput,
+	}
+	applyJobSettings(&job, s.userSettings())
+
+	if s.throttle.Acquire() {
+		job.LowPriority = true
+	}
+	defer s.throttle.Release()
+
+	// The record describes the whole sequence, which becomes a video
+	fileInfo.Size = sequence.Size
+	fileInfo.Type = models.FileTypeVideo
+
+	now := time.Now()
+	conversion := newConversionRecord(job, fileInfo)
+	conversion.Backend = converterBackend(s.video(), job)
+	conversion.Status = models.StatusProcessing
+	conversion.StartedAt = &now
+	if err := s.repo.Create(conversion); err != nil {
+		s.log.Error("Failed to create conversion record: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s.mu.Lock()
+	s.activeConversions[conversion.ID] = cancel
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.activeConversions, conversion.ID)
+		s.mu.Unlock()
+	}()
+
+	if jobLog := s.openJobLog(conversion.ID); jobLog != nil {
+		defer jobLog.Close()
+		jobLog.Printf("Encoding %d frames of %s at %g fps with %s", sequence.Frames, job.InputPath, sequence.FrameRate, conversion.Backend)
+		job.Log = jobLog
+	}
+
+	result, err := encoder.EncodeSequence(ctx, job, *sequence, func(progress float64) {
+		conversion.Progress = progress
+		s.repo.Update(conversion)
+
+		if progressCallback != nil {
+			progressCallback(models.ConversionProgress{
+				ID:        conversion.ID,
+				InputPath: job.InputPath,
+				Progress:  progress,
+				Status:    string(models.StatusProcessing),
+			})
+		}
+	})
+
+	completedAt := time.Now()
+	conversion.CompletedAt = &completedAt
+	if err != nil {
+		err = explainFailure(err, s.userSettings().Language)
+		conversion.Status = models.StatusFailed
+		if ctx.Err() != nil {
+			conversion.Status = models.StatusCancelled
+		}
+		conversion.ErrorMessage = err.Error()
+		if updateErr := s.repo.Update(conversion); updateErr != nil {
+			s.log.Error("Failed to update conversion record: %v", updateErr)
+		}
+		return &models.ConversionResult{
+			InputPath:    job.InputPath,
+			OutputPath:   job.OutputPath,
+			ErrorMessage: err.Error(),
+		}, nil
+	}
+
+	conversion.Status = models.StatusCompleted
+	conversion.Progress = 100
+	conversion.OutputSize = result.OutputSize
+	if err := s.repo.Update(conversion); err != nil {
+		s.log.Error("Failed to update conversion record: %v", err)
+	}
+
+	s.log.Info("Encoded %d frames in %dms: %s", sequence.Frames, result.Duration, result.OutputPath)
+	return result, nil
+}
+
+// runFFmpegSequence encodes an image sequence into job.OutputPath, keeping
+// the frames' alpha channel when the job asks to. Shared by all
+// FFmpeg-backed converters.
+func runFFmpegSequence(
+	ctx context.Context,
+	ff *ffmpeg.FFmpeg,
+	log *logger.ComponentLogger,
+	job models.ConversionJob,
+	sequence models.ImageSequence,
+	progressCallback func(progress float64),
+) (*models.ConversionResult, error) {
+	startTime := time.Now()
+
+	if err := os.MkdirAll(filepath.Dir(job.OutputPath), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+	if !job.OverwriteOutput {
+		if _, err := os.Stat(job.OutputPath); err == nil {
+			return nil, fmt.Errorf("output file already exists: %s", job.OutputPath)
+		}
+	}
+
+	outputFormat := strings.TrimPrefix(strings.ToLower(filepath.Ext(job.OutputPath)), ".")
+	encoder, err := jobEncoder(ff, job, outputFormat, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := ffmpeg.ConvertOptions{
+		InputPath:  job.InputPath,
+		OutputPath: job.OutputPath,
+		Overwrite:  job.OverwriteOutput,
+		Sequence: &ffmpeg.Sequence{
+			FrameRate:   sequence.FrameRate,
+			StartNumber: sequence.StartNumber,
+			Frames:      sequence.Frames,
+		},
+		Threads:     job.Threads,
+		LowPriority: job.LowPriority,
+		Quality:     jobQuality(job),
+		Log:         job.Log,
+	}
+	encoder.apply(&opts)
+	warnQuality(log, opts)
+
+	log.Info("Encoding %d frames of %s with %s", sequence.Frames, job.InputPath, opts.VideoCodec)
+	if err := ff.Convert(ctx, opts, progressCallback); err != nil {
+		return nil, err
+	}
+
+	result := &models.ConversionResult{
+		InputPath:  job.InputPath,
+		OutputPath: job.OutputPath,
+		Success:    true,
+		Method:     models.MethodReencode,
+	}
+	if stat, err := os.Stat(job.OutputPath); err == nil {
+		result.OutputSize = stat.Size()
+	}
+	describeMedia(ff, result)
+	result.Duration = time.Since(startTime).Milliseconds()
+	return result, nil
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFindImageSequence(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"glow_0009.png", "glow_0010.png", "glow_0011.png", "glow_0013.png", "glow_12.png"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("frame"), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	sequence, err := findImageSequence(filepath.Join(dir, "glow_0009.png"))
+	if err != nil {
+		t.Fatalf("findImageSequence failed: %v", err)
+	}
+	if want := filepath.Join(dir, "glow_%04d.png"); sequence.Pattern != want {
+		t.Errorf("Pattern = %q, want %q", sequence.Pattern, want)
+	}
+	if sequence.StartNumber != 9 || sequence.Frames != 3 || sequence.Size != 15 {
+		t.Errorf("sequence starts at %d with %d frames of %d bytes, want 9, 3 and 15 up to the gap",
+			sequence.StartNumber, sequence.Frames, sequence.Size)
+	}
+
+	for _, name := range []string{"glow.png", "glow_0001.png"} {
+		if _, err := findImageSequence(filepath.Join(dir, name)); err == nil {
+			t.Errorf("findImageSequence(%s) found a sequence", name)
+		}
+	}
+
+	// A % in the folder or name is escaped in the pattern
+	percentDir := filepath.Join(dir, "50% done")
+	if err := os.Mkdir(percentDir, 0755); err != nil {
+		t.Fatalf("failed to create %s: %v", percentDir, err)
+	}
+	for _, name := range []string{"mix%_001.png", "mix%_002.png"} {
+		if err := os.WriteFile(filepath.Join(percentDir, name), []byte("frame"), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+	sequence, err = findImageSequence(filepath.Join(percentDir, "mix%_001.png"))
+	if err != nil {
+		t.Fatalf("findImageSequence failed: %v", err)
+	}
+	if want := filepath.Join(dir, "50%% done", "mix%%_%03d.png"); sequence.Pattern != want {
+		t.Errorf("Pattern = %q, want %q", sequence.Pattern, want)
+	}
+	if sequence.Frames != 2 {
+		t.Errorf("sequence has %d frames, want 2", sequence.Frames)
+	}
+}
+
+func TestSequenceOutputName(t *testing.T) {
+	for frame, want := range map[string]string{
+		"/renders/glow_0001.png":    "glow",
+		"/renders/glow.v2.0001.png": "glow.v2",
+		"/renders/0001.png":         "renders",
+	} {
+		if got := sequenceOutputName(frame); got != want {
+			t.Errorf("sequenceOutputName(%s) = %q, want %q", frame, got, want)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"converzen/internal/models"
+	"converzen/pkg/iolimit"
+	"converzen/pkg/scratch"
+)
+
+// copyBufferSize is the size of the chunks cached files are copied in
+const copyBufferSize = 1 << 20 // 1 MiB
+
+// inputCache holds local copies of a job's input and output in the scratch
+// directory, so a conversion doesn't depend on a network share while it runs
+type inputCache struct {
+	dir string
+	job models.ConversionJob // The job redirected to the local copies
+}
+
+// cacheInput copies a job's input into the scratch directory, reporting the
+// copy's progress with the copying status
+func (s *conversionServiceImpl) cacheInput(id uint, job models.ConversionJob, progressCallback func(progress models.ConversionProgress)) (*inputCache, error) {
+	dir, err := scratch.MkdirTemp("cache-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	cache := &inputCache{dir: dir, job: job}
+	cache.job.InputPath = filepath.Join(dir, filepath.Base(job.InputPath))
+	cache.job.OutputPath = filepath.Join(dir, "output", filepath.Base(job.OutputPath))
+
+	if err := os.Mkdir(filepath.Dir(cache.job.OutputPath), 0755); err != nil {
+		cache.remove()
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	s.log.Info("Copying %s to local scratch space", job.InputPath)
+	if err := s.copyCached(id, job, job.InputPath, cache.job.InputPath, progressCallback); err != nil {
+		cache.remove()
+		return nil, fmt.Errorf("failed to copy the input to local scratch space: %w", err)
+	}
+	return cache, nil
+}
+
+// writeBack copies the local output to the job's real output path and
+// points the result at the real paths
+func (s *conversionServiceImpl) writeBack(id uint, cache *inputCache, job models.ConversionJob, result *models.ConversionResult, progressCallback func(progress models.ConversionProgress)) error {
+	s.log.Info("Copying output back to %s", job.OutputPath)
+	if err := s.copyCached(id, job, cache.job.OutputPath, job.OutputPath, progressCallback); err != nil {
+		return fmt.Errorf("failed to write the output back: %w", err)
+	}
+	result.InputPath = job.InputPath
+	result.OutputPath = job.OutputPath
+	return nil
+}
+
+// remove deletes the local copies
+func (c *inputCache) remove() {
+	os.RemoveAll(c.dir)
+}
+
+// copyCached copies src to dst for a cached job, at no more than the job's
+// I/O limit. The copy can be cancelled with CancelConversion. dst is written
+// under a temporary name and renamed when complete, so an interrupted copy
+// never leaves a truncated file in its place.
+func (s *conversionServiceImpl) copyCached(id uint, job models.ConversionJob, src, dst string, progressCallback func(progress models.ConversionProgress)) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s.mu.Lock()
+	s.activeConversions[id] = cancel
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.activeConversions, id)
+		s.mu.Unlock()
+	}()
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	stat, err := in.Stat()
+	if err != nil {
+		return err
+	}
+
+	out, err := os.CreateTemp(filepath.Dir(dst), "."+filepath.Base(dst)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(out.Name())
+
+	reader := iolimit.Reader(in, job.IOLimit())
+	buffer := make([]byte, copyBufferSize)
+	var copied int64
+	for {
+		if err := ctx.Err(); err != nil {
+			out.Close()
+			return err
+		}
+
+		n, readErr := reader.Read(buffer)
+		if n > 0 {
+			if _, err := out.Write(buffer[:n]); err != nil {
+				out.Close()
+				return err
+			}
+			copied += int64(n)
+			if progressCallback != nil && stat.Size() > 0 {
+				progressCallback(models.ConversionProgress{
+					ID:        id,
+					InputPath: job.InputPath,
+					Progress:  float64(copied) / float64(stat.Size()) * 100,
+					Status:    string(models.StatusCopying),
+				})
+			}
+		}
+		if errors.Is(readErr, io.EOF) {
+			break
+		}
+		if readErr != nil {
+			out.Close()
+			return readErr
+		}
+	}
+
+	if err := out.Close(); err != nil {
+		return err
+	}
+	return os.Rename(out.Name(), dst)
+}
@@ -1,6 +1,9 @@
 package services
 
 import (
+	"context"
+	"time"
+
 	"converzen/internal/models"
 )
 
@@ -20,12 +23,16 @@ type FileService interface {
 
 	// FileExists checks if a file exists
 	FileExists(path string) bool
+
+	// CheckOutputDirectory verifies converted files can be written to dir,
+	// creating it if needed
+	CheckOutputDirectory(dir string) error
 }
 
 // Converter handles file conversion
 type Converter interface {
-	// Convert converts a single file
-	Convert(job models.ConversionJob, progressCallback func(progress float64)) (*models.ConversionResult, error)
+	// Convert converts a single file. Cancelling ctx aborts the conversion.
+	Convert(ctx context.Context, job models.ConversionJob, progressCallback func(progress float64)) (*models.ConversionResult, error)
 
 	// SupportedInputFormats returns the list of supported input formats
 	SupportedInputFormats() []string
@@ -37,6 +44,64 @@ type Converter interface {
 	CanConvert(inputFormat, outputFormat string) bool
 }
 
+// Splitter is implemented by converters that can cut a file into segments
+type Splitter interface {
+	// Split cuts job.InputPath into consecutive segments of segmentLength.
+	// job.OutputPath is a pattern with a printf verb for the segment number.
+	Split(ctx context.Context, job models.ConversionJob, segmentLength time.Duration, progressCallback func(progress float64)) ([]models.ConversionResult, error)
+}
+
+// StreamPackager is implemented by converters that can package a video for
+// adaptive streaming
+type StreamPackager interface {
+	// PackageStreaming writes the request's renditions and manifests into
+	// the directory job.OutputPath. The result's OutputPath is the first
+	// manifest written.
+	PackageStreaming(ctx context.Context, job models.ConversionJob, request models.StreamingRequest, progressCallback func(progress float64)) (*models.ConversionResult, error)
+}
+
+// SequenceEncoder is implemented by converters that can encode numbered
+// image files as the frames of a video
+type SequenceEncoder interface {
+	// EncodeSequence encodes the frames into job.OutputPath. The job's
+	// InputPath is the sequence's pattern.
+	EncodeSequence(ctx context.Context, job models.ConversionJob, sequence models.ImageSequence, progressCallback func(progress float64)) (*models.ConversionResult, error)
+}
+
+// Previewer is implemented by converters that can convert a short slice of a
+// file, so settings can be checked before a long conversion
+type Previewer interface {
+	// Preview converts a slice of job.InputPath of the given length, taken
+	// from the middle of the input when it is long enough
+	Preview(ctx context.Context, job models.ConversionJob, length time.Duration, progressCallback func(progress float64)) (*models.ConversionResult, error)
+}
+
+// SizeEstimator is implemented by converters that can predict output size
+type SizeEstimator interface {
+	// EstimateSize predicts the size of job's output from a short trial encode
+	EstimateSize(ctx context.Context, job models.ConversionJob) (*models.SizeEstimate, error)
+}
+
+// LoudnessMeter is implemented by converters that can measure audio loudness
+type LoudnessMeter interface {
+	// MeasureLoudness returns the EBU R128 loudness of a file's audio
+	MeasureLoudness(ctx context.Context, path string) (*models.Loudness, error)
+}
+
+// CodecProvider is implemented by video converters that can encode
+// professional codecs in place of an output format's default
+type CodecProvider interface {
+	// SupportedVideoCodecs returns the codecs available for an output format
+	SupportedVideoCodecs(outputFormat string) []models.VideoCodec
+}
+
+// BackendNamer is implemented by converters that can name the tool a job is
+// converted with, e.g. "ffmpeg" or "libreoffice"
+type BackendNamer interface {
+	// Backend returns the name of the tool that would convert job
+	Backend(job models.ConversionJob) string
+}
+
 // ConversionService orchestrates file conversions
 type ConversionService interface {
 	// ConvertFile converts a single file
@@ -45,11 +110,187 @@ type ConversionService interface {
 	// ConvertBatch converts multiple files
 	ConvertBatch(request models.BatchConversionRequest, progressCallback func(progress models.ConversionProgress)) (*models.BatchConversionResult, error)
 
+	// SplitFile cuts a video into fixed-length segments, recorded as one batch
+	SplitFile(request models.SplitRequest, progressCallback func(progress models.ConversionProgress)) (*models.BatchConversionResult, error)
+
+	// PackageForStreaming encodes a video into HLS and/or DASH renditions
+	// for self-hosted streaming
+	PackageForStreaming(request models.StreamingRequest, progressCallback func(progress models.ConversionProgress)) (*models.ConversionResult, error)
+
+	// ConvertImageSequence encodes numbered image files as a video,
+	// optionally keeping their alpha channel
+	ConvertImageSequence(request models.ImageSequenceRequest, progressCallback func(progress models.ConversionProgress)) (*models.ConversionResult, error)
+
+	// FindDuplicates returns the files of a batch request that were already
+	// converted successfully with the same settings
+	FindDuplicates(request models.BatchConversionRequest) ([]models.DuplicateConversion, error)
+
+	// RenameFiles renames files in place with a naming template, recording
+	// the renames in history so they can be undone
+	RenameFiles(request models.RenameRequest) (*models.RenameResult, error)
+
+	// UndoRename reverts the renames of a batch recorded by RenameFiles
+	UndoRename(batchID string) (*models.RenameResult, error)
+
+	// PreviewConversion converts a few seconds of a file with the job's
+	// settings to a temporary file and returns its path. The file is deleted
+	// by the next preview.
+	PreviewConversion(job models.ConversionJob, seconds int) (string, error)
+
+	// DiscardPreviews deletes the temporary files of previews
+	DiscardPreviews() error
+
+	// EstimateOutputSize predicts the output size of a job before converting
+	EstimateOutputSize(job models.ConversionJob) (*models.SizeEstimate, error)
+
+	// GetBatchResults retrieves a page of results for a batch conversion
+	GetBatchResults(batchID string, offset, limit int) ([]models.ConversionResult, error)
+
+	// GetQueueState returns the queued, running, paused, completed and
+	// failed conversions of this session
+	GetQueueState() (*models.QueueState, error)
+
+	// SetThrottle changes how aggressively conversions run
+	SetThrottle(level models.ThrottleLevel)
+
+	// GetThrottle returns the current throttle level
+	GetThrottle() models.ThrottleLevel
+
+	// SetMediaConverters replaces the video and audio converters, e.g. when
+	// the user switches the converter backend
+	SetMediaConverters(video, audio Converter)
+
+	// SetRemoteWorker sends conversions to a worker instance; nil converts
+	// locally
+	SetRemoteWorker(worker *RemoteWorker)
+
+	// RerunConversion converts a history entry again with its stored options,
+	// returning the new conversion's record while it runs in the background
+	RerunConversion(id uint, progressCallback func(progress models.ConversionProgress)) (*models.Conversion, error)
+
 	// CancelConversion cancels an ongoing conversion
 	CancelConversion(id uint) error
 
+	// CancelPowerAction cancels the sleep or shutdown waiting to run after
+	// a batch, reporting whether one was pending
+	CancelPowerAction() bool
+
+	// ActiveConversionCount returns the number of conversions of this
+	// session that are running or waiting to start
+	ActiveConversionCount() (int, error)
+
+	// GetConversionLog returns the tool output captured while a conversion ran
+	GetConversionLog(id uint) (string, error)
+
 	// GetConversionHistory retrieves conversion history
 	GetConversionHistory(limit int) ([]models.Conversion, error)
+
+	// FilterConversionHistory retrieves conversion history matching a filter
+	FilterConversionHistory(filter models.HistoryFilter) ([]models.Conversion, error)
+
+	// UpdateConversionNote sets the note of a history record
+	UpdateConversionNote(id uint, note string) error
+
+	// AnalyzeLoudness measures the EBU R128 loudness of a media file
+	AnalyzeLoudness(path string) (*models.Loudness, error)
+
+	// MeasureConversionLoudness measures the loudness of a history record's
+	// input and output and stores it with the record
+	MeasureConversionLoudness(id uint) (*models.Conversion, error)
+
+	// ArchiveConversions hides history records while keeping their statistics
+	ArchiveConversions(ids []uint) error
+
+	// RestoreConversions brings archived history records back
+	RestoreConversions(ids []uint) error
+
+	// DeleteConversion removes a history record, keeping its statistics until purged
+	DeleteConversion(id uint) error
+
+	// PurgeConversions permanently deletes archived and deleted history records
+	PurgeConversions() (int64, error)
+
+	// GetBatchHistory retrieves a page of history grouped by batch, newest first
+	GetBatchHistory(offset, limit int) ([]models.BatchSummary, error)
+
+	// GetBatchConversions retrieves a page of a batch's conversion records
+	GetBatchConversions(batchID string, offset, limit int) ([]models.Conversion, error)
+
+	// GetSpaceSavings returns the disk space conversions saved per week or
+	// month, with a running total
+	GetSpaceSavings(period models.SavingsPeriod) ([]models.SpaceSavings, error)
+}
+
+// RecentService tracks recently used input files and output directories
+type RecentService interface {
+	// RecordBatch records a batch's input files and output directory as used
+	RecordBatch(request models.BatchConversionRequest) error
+
+	// GetRecentInputs returns the most recently converted files
+	GetRecentInputs(limit int) ([]models.RecentPath, error)
+
+	// GetRecentOutputDirs returns the most recently used output directories
+	GetRecentOutputDirs(limit int) ([]models.RecentPath, error)
+}
+
+// AnalysisService probes files without converting them
+type AnalysisService interface {
+	// AnalyzeFiles reports the format details and problems of files,
+	// calling progressCallback as files are probed
+	AnalyzeFiles(files []string, progressCallback func(done, total int)) *models.AnalysisReport
+
+	// RecommendSettings suggests conversion options for a file and goal
+	RecommendSettings(path string, goal models.RecommendationGoal) (*models.Recommendation, error)
+}
+
+// BookmarkService keeps access to selected folders across restarts in
+// sandboxed macOS builds
+type BookmarkService interface {
+	// Remember stores a bookmark for a folder the user just selected
+	Remember(path string) error
+
+	// RestoreAll regains access to the bookmarked folders for this session,
+	// returning the number restored
+	RestoreAll() int
+}
+
+// ThumbnailService serves cached thumbnails for media files
+type ThumbnailService interface {
+	// GetThumbnail returns a small JPEG preview of an image or video as a
+	// data URL, or "" for file types without one
+	GetThumbnail(path string) (string, error)
+
+	// ClearThumbnailCache deletes every cached thumbnail
+	ClearThumbnailCache() error
+
+	// GrabFrame saves the frame of a video at a timestamp as a full-size
+	// image and returns the saved path
+	GrabFrame(path, timestamp, format, outputPath string) (string, error)
+
+	// CreateContactSheet saves a grid of evenly spaced frames of a video as
+	// a single image and returns the saved path
+	CreateContactSheet(path string, options models.ContactSheetOptions) (string, error)
+}
+
+// EncoderBenchmarkService measures the video encoders available on this
+// machine and picks the default ones
+type EncoderBenchmarkService interface {
+	// RunEncoderBenchmark encodes a sample with each available encoder,
+	// saves the results and makes the recommended encoders the defaults
+	RunEncoderBenchmark(ctx context.Context, progressCallback func(done, total int)) (*models.EncoderBenchmarkReport, error)
+
+	// GetEncoderBenchmark returns the saved results, nil if none were saved
+	GetEncoderBenchmark() (*models.EncoderBenchmarkReport, error)
+}
+
+// UpdateService checks a release feed for newer versions of the app
+type UpdateService interface {
+	// CheckForUpdates fetches the latest release and compares it with the
+	// running version
+	CheckForUpdates(ctx context.Context) (*models.UpdateInfo, error)
+
+	// GetUpdateInfo returns the result of the last successful check, or nil
+	GetUpdateInfo() *models.UpdateInfo
 }
 
 // SettingsService handles user settings
@@ -65,4 +306,10 @@ type SettingsService interface {
 
 	// SetSetting sets a single setting value
 	SetSetting(key, value string) error
+
+	// RecordPresetChange adds a preset change to the settings audit trail
+	RecordPresetChange(name, oldValue, newValue string) error
+
+	// GetSettingChanges returns the most recent settings audit trail entries
+	GetSettingChanges(limit int) ([]models.SettingChange, error)
 }
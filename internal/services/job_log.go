@@ -0,0 +1,128 @@
+package services
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// maxJobLogBytes bounds a single conversion's log; output beyond it is dropped
+	maxJobLogBytes = 1 << 20 // 1 MiB
+
+	// jobLogRetention is how long conversion logs are kept
+	jobLogRetention = 30 * 24 * time.Hour
+)
+
+// jobLog collects the tool output of one conversion in a file. Writes never
+// fail, so a full disk or a long log doesn't abort the conversion.
+type jobLog struct {
+	file    *os.File
+	written int64
+	full    bool
+	mu      sync.Mutex
+}
+
+// Write appends p to the log until it reaches maxJobLogBytes
+func (l *jobLog) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.full {
+		return len(p), nil
+	}
+	if l.written+int64(len(p)) > maxJobLogBytes {
+		l.full = true
+		l.file.WriteString("\n[log truncated]\n")
+		return len(p), nil
+	}
+
+	n, _ := l.file.Write(p)
+	l.written += int64(n)
+	return len(p), nil
+}
+
+// Printf appends a timestamped line to the log
+func (l *jobLog) Printf(format string, args ...interface{}) {
+	fmt.Fprintf(l, "[%s] %s\n", time.Now().Format("2006-01-02 15:04:05"), fmt.Sprintf(format, args...))
+}
+
+// Close closes the log file
+func (l *jobLog) Close() error {
+	return l.file.Close()
+}
+
+// jobLogPath returns the path of a conversion's log file
+func (s *conversionServiceImpl) jobLogPath(id uint) string {
+	return filepath.Join(s.jobLogDir, strconv.FormatUint(uint64(id), 10)+".log")
+}
+
+// openJobLog opens a conversion's log for appending, or returns nil if logs
+// are disabled or the file can't be created. Logs past their retention are
+// pruned the first time a log is opened.
+func (s *conversionServiceImpl) openJobLog(id uint) *jobLog {
+	if s.jobLogDir == "" || id == 0 {
+		return nil
+	}
+	s.pruneLogsOnce.Do(s.pruneJobLogs)
+
+	if err := os.MkdirAll(s.jobLogDir, 0755); err != nil {
+		s.log.Warn("Failed to create conversion log directory: %v", err)
+		return nil
+	}
+	file, err := os.OpenFile(s.jobLogPath(id), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		s.log.Warn("Failed to open log for conversion %d: %v", id, err)
+		return nil
+	}
+
+	l := &jobLog{file: file}
+	if stat, err := file.Stat(); err == nil {
+		l.written = stat.Size()
+	}
+	return l
+}
+
+// pruneJobLogs deletes conversion logs older than jobLogRetention
+func (s *conversionServiceImpl) pruneJobLogs() {
+	entries, err := os.ReadDir(s.jobLogDir)
+	if err != nil {
+		return
+	}
+
+	cutoff := time.Now().Add(-jobLogRetention)
+	removed := 0
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || entry.IsDir() || filepath.Ext(entry.Name()) != ".log" {
+			continue
+		}
+		if info.ModTime().Before(cutoff) {
+			if os.Remove(filepath.Join(s.jobLogDir, entry.Name())) == nil {
+				removed++
+			}
+		}
+	}
+	if removed > 0 {
+		s.log.Info("Pruned %d conversion logs older than %s", removed, jobLogRetention)
+	}
+}
+
+// GetConversionLog returns the tool output captured while a conversion ran
+func (s *conversionServiceImpl) GetConversionLog(id uint) (string, error) {
+	if s.jobLogDir == "" {
+		return "", fmt.Errorf("conversion logs are disabled")
+	}
+
+	data, err := os.ReadFile(s.jobLogPath(id))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf("no log was kept for conversion %d", id)
+		}
+		return "", fmt.Errorf("failed to read conversion log: %w", err)
+	}
+	return string(data), nil
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"converzen/internal/models"
+)
+
+// loudnessMeter returns the converter that measures loudness
+func (s *conversionServiceImpl) loudnessMeter() (LoudnessMeter, error) {
+	meter, ok := s.audio().(LoudnessMeter)
+	if !ok {
+		return nil, fmt.Errorf("loudness analysis requires FFmpeg")
+	}
+	return meter, nil
+}
+
+// AnalyzeLoudness measures the EBU R128 loudness of a video or audio file
+func (s *conversionServiceImpl) AnalyzeLoudness(path string) (*models.Loudness, error) {
+	fileInfo, err := s.fileService.GetFileInfo(path)
+	if err != nil {
+		return nil, err
+	}
+	if fileInfo.Type != models.FileTypeAudio && fileInfo.Type != models.FileTypeVideo {
+		return nil, fmt.Errorf("loudness can only be measured for video and audio files")
+	}
+
+	meter, err := s.loudnessMeter()
+	if err != nil {
+		return nil, err
+	}
+
+	s.log.Info("Measuring loudness of %s", path)
+	return meter.MeasureLoudness(context.Background(), path)
+}
+
+// MeasureConversionLoudness measures the loudness of a history record's
+// input and output and stores both with the record. A file that no longer
+// exists is left unmeasured.
+func (s *conversionServiceImpl) MeasureConversionLoudness(id uint) (*models.Conversion, error) {
+	conversion, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if conversion.FileType != models.FileTypeAudio && conversion.FileType != models.FileTypeVideo {
+		return nil, fmt.Errorf("loudness can only be measured for video and audio conversions")
+	}
+	if conversion.Status != models.StatusCompleted {
+		return nil, fmt.Errorf("conversion %d hasn't completed", id)
+	}
+
+	meter, err := s.loudnessMeter()
+	if err != nil {
+		return nil, err
+	}
+
+	measure := func(path string) *models.Loudness {
+		if _, err := os.Stat(path); err != nil {
+			return nil
+		}
+		loudness, err := meter.MeasureLoudness(context.Background(), path)
+		if err != nil {
+			s.log.Warn("Failed to measure loudness of %s: %v", path, err)
+			return nil
+		}
+		return loudness
+	}
+
+	conversion.InputLoudness = measure(conversion.InputPath)
+	conversion.OutputLoudness = measure(conversion.OutputPath)
+	if conversion.InputLoudness == nil && conversion.OutputLoudness == nil {
+		return nil, fmt.Errorf("neither the input nor the output of conversion %d could be measured", id)
+	}
+
+	if err := s.repo.UpdateLoudness(id, conversion.InputLoudness, conversion.OutputLoudness); err != nil {
+		return nil, err
+	}
+	return conversion, nil
+}
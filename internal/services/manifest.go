@@ -0,0 +1,225 @@
+package services
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"converzen/internal/models"
+)
+
+// maxManifestFileSize bounds the manifests read, which list one file per row
+const maxManifestFileSize = 16 << 20 // 16 MiB
+
+// manifestRow is a file listed in a manifest with its settings. JSON
+// manifests are an array of these; CSV manifests have a column per field.
+type manifestRow struct {
+	Input string `json:"input"`
+	models.FileSettings
+}
+
+// manifestColumns maps normalized CSV headers to the setting they fill.
+// Headers are matched ignoring case, spaces, underscores and hyphens, so
+// "Output Format", "output_format" and "outputFormat" are the same column.
+var manifestColumns = map[string]func(row *manifestRow, value string) error{
+	"input":           func(row *manifestRow, value string) error { row.Input = value; return nil },
+	"path":            func(row *manifestRow, value string) error { row.Input = value; return nil },
+	"file":            func(row *manifestRow, value string) error { row.Input = value; return nil },
+	"format":          func(row *manifestRow, value string) error { row.OutputFormat = value; return nil },
+	"outputformat":    func(row *manifestRow, value string) error { row.OutputFormat = value; return nil },
+	"outputdirectory": func(row *manifestRow, value string) error { row.OutputDirectory = value; return nil },
+	"outputdir":       func(row *manifestRow, value string) error { row.OutputDirectory = value; return nil },
+	"outputname":      func(row *manifestRow, value string) error { row.OutputName = value; return nil },
+	"name":            func(row *manifestRow, value string) error { row.OutputName = value; return nil },
+	"videocodec": func(row *manifestRow, value string) error {
+		row.VideoCodec = models.VideoCodec(strings.ToLower(value))
+		return nil
+	},
+	"deinterlace": func(row *manifestRow, value string) error {
+		row.Deinterlace = models.DeinterlaceMode(strings.ToLower(value))
+		return nil
+	},
+	"targetsizemb": func(row *manifestRow, value string) error {
+		return parseManifestNumber(value, &row.TargetSizeMB)
+	},
+	"maxwidth":  func(row *manifestRow, value string) error { return parseManifestInt(value, &row.MaxWidth) },
+	"maxheight": func(row *manifestRow, value string) error { return parseManifestInt(value, &row.MaxHeight) },
+	"overwrite": func(row *manifestRow, value string) error {
+		row.Overwrite = models.OverwritePolicy(strings.ToLower(value))
+		return nil
+	},
+	"skip": func(row *manifestRow, value string) error {
+		switch strings.ToLower(value) {
+		case "1", "true", "yes", "y", "x":
+			row.Skip = true
+		case "0", "false", "no", "n":
+			row.Skip = false
+		default:
+			return fmt.Errorf("skip must be yes or no, not %q", value)
+		}
+		return nil
+	},
+}
+
+// ReadManifest loads a batch from a CSV or JSON manifest listing input
+// files with per-file output formats and options. Relative paths are
+// relative to the manifest. The returned request has no batch output
+// format; rows without one use the format chosen for the batch.
+func ReadManifest(path string) (*models.BatchConversionRequest, error) {
+	stat, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("manifest not found: %s", path)
+	}
+	if stat.Size() > maxManifestFileSize {
+		return nil, fmt.Errorf("manifest is too large: %s", filepath.Base(path))
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+	data = bytes.TrimPrefix(data, []byte("\uFEFF")) // Spreadsheet apps write a BOM
+
+	var rows []manifestRow
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		rows, err = parseCSVManifest(data)
+	case ".json":
+		err = json.Unmarshal(data, &rows)
+	default:
+		return nil, fmt.Errorf("%s is not a CSV or JSON manifest", filepath.Base(path))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid manifest %s: %w", filepath.Base(path), err)
+	}
+
+	base := filepath.Dir(path)
+	request := &models.BatchConversionRequest{NamingMode: models.NamingModeOriginal}
+	for _, row := range rows {
+		input := strings.TrimSpace(row.Input)
+		if input == "" {
+			continue
+		}
+		row.OutputFormat = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(row.OutputFormat)), ".")
+		row.OutputDirectory = manifestPath(base, strings.TrimSpace(row.OutputDirectory))
+		row.OutputName = strings.TrimSpace(row.OutputName)
+
+		request.Files = append(request.Files, manifestPath(base, input))
+		request.FileSettings = append(request.FileSettings, row.FileSettings)
+	}
+	if len(request.Files) == 0 {
+		return nil, fmt.Errorf("manifest %s lists no files", filepath.Base(path))
+	}
+	return request, nil
+}
+
+// parseCSVManifest reads the rows of a CSV manifest with a header row.
+// Semicolon-separated files, as some spreadsheet locales export, are
+// accepted too.
+func parseCSVManifest(data []byte) ([]manifestRow, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	header, _, _ := bytes.Cut(data, []byte("\n"))
+	if bytes.Count(header, []byte(";")) > bytes.Count(header, []byte(",")) {
+		reader.Comma = ';'
+	}
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	columns, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	setters := make([]func(row *manifestRow, value string) error, len(columns))
+	hasInput := false
+	for i, column := range columns {
+		key := strings.NewReplacer(" ", "", "_", "", "-", "").Replace(strings.ToLower(strings.TrimSpace(column)))
+		setter, ok := manifestColumns[key]
+		if !ok {
+			return nil, fmt.Errorf("unknown column %q", column)
+		}
+		setters[i] = setter
+		hasInput = hasInput || key == "input" || key == "path" || key == "file"
+	}
+	if !hasInput {
+		return nil, fmt.Errorf("no input column")
+	}
+
+	var rows []manifestRow
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var row manifestRow
+		for i, value := range record {
+			value = strings.TrimSpace(value)
+			if i >= len(setters) || value == "" {
+				continue
+			}
+			if err := setters[i](&row, value); err != nil {
+				return nil, fmt.Errorf("row %d: %w", line, err)
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// manifestPath resolves a path relative to the manifest's directory
+func manifestPath(base, path string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(base, path)
+}
+
+// parseManifestNumber parses a positive number column
+func parseManifestNumber(value string, number *float64) error {
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil || parsed < 0 {
+		return fmt.Errorf("%q is not a positive number", value)
+	}
+	*number = parsed
+	return nil
+}
+
+// parseManifestInt parses a positive whole number column
+func parseManifestInt(value string, number *int) error {
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed < 0 {
+		return fmt.Errorf("%q is not a positive whole number", value)
+	}
+	*number = parsed
+	return nil
+}
+
+// validateFileSettings checks a batch's per-file settings before any file
+// is converted
+func validateFileSettings(request models.BatchConversionRequest) error {
+	if len(request.FileSettings) > len(request.Files) {
+		return fmt.Errorf("file settings given for %d files, but the batch has %d", len(request.FileSettings), len(request.Files))
+	}
+	if request.OutputFormat != "" || len(request.Rules) > 0 {
+		return nil
+	}
+	for i, settings := range request.FileSettings {
+		if settings.OutputFormat == "" {
+			return fmt.Errorf("no output format for %s", filepath.Base(request.Files[i]))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,272 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+
+	"converzen/internal/logger"
+	"converzen/internal/models"
+	"converzen/pkg/htmlpdf"
+	"converzen/pkg/libreoffice"
+	"converzen/pkg/scratch"
+)
+
+// markupStylesheet gives rendered Markdown readable print defaults
+const markupStylesheet = `body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; font-size: 11pt; line-height: 1.5; margin: 2em; }
+pre, code { font-family: Menlo, Consolas, monospace; font-size: 9.5pt; background: #f5f5f5; }
+pre { padding: 0.75em; white-space: pre-wrap; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 0.3em 0.6em; }
+img { max-width: 100%; }`
+
+// markupConverter renders Markdown and HTML files to PDF. Pages are printed
+// with wkhtmltopdf when installed, falling back to LibreOffice.
+type markupConverter struct {
+	renderer *htmlpdf.Renderer
+	office   *libreoffice.LibreOffice
+	markdown goldmark.Markdown
+	log      *logger.ComponentLogger
+}
+
+// NewMarkupConverter creates a new Markdown/HTML converter
+func NewMarkupConverter(renderer *htmlpdf.Renderer, office *libreoffice.LibreOffice, log *logger.Logger) Converter {
+	return &markupConverter{
+		renderer: renderer,
+		office:   office,
+		markdown: goldmark.New(goldmark.WithExtensions(extension.GFM)),
+		log:      log.WithComponent("markup-converter"),
+	}
+}
+
+// Convert renders a Markdown or HTML file to PDF
+func (c *markupConverter) Convert(ctx context.Context, job models.ConversionJob, progressCallback func(progress float64)) (*models.ConversionResult, error) {
+	c.log.Info("Starting markup conversion: %s -> %s", job.InputPath, job.OutputPath)
+	startTime := time.Now()
+
+	result := &models.ConversionResult{
+		InputPath:  job.InputPath,
+		OutputPath: job.OutputPath,
+	}
+
+	source, err := os.ReadFile(job.InputPath)
+	if err != nil {
+		result.ErrorMessage = fmt.Sprintf("Failed to read input file: %v", err)
+		c.log.Error("%s", result.ErrorMessage)
+		return result, fmt.Errorf("%s", result.ErrorMessage)
+	}
+
+	// Check output directory exists
+	outputDir := filepath.Dir(job.OutputPath)
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		result.ErrorMessage = fmt.Sprintf("Failed to create output directory: %v", err)
+		c.log.Error("%s", result.ErrorMessage)
+		return result, fmt.Errorf("%s", result.ErrorMessage)
+	}
+
+	// Check if output file already exists
+	if !job.OverwriteOutput {
+		if _, err := os.Stat(job.OutputPath); err == nil {
+			result.ErrorMessage = fmt.Sprintf("Output file already exists: %s", job.OutputPath)
+			c.log.Error("%s", result.ErrorMessage)
+			return result, fmt.Errorf("%s", result.ErrorMessage)
+		}
+	}
+
+	if progressCallback != nil {
+		progressCallback(10)
+	}
+
+	// Markdown is rendered to a standalone HTML page first. Either page is
+	// printed from a temp directory, so both get a base URL pointing back at
+	// the input's directory.
+	var page []byte
+	inputFormat := strings.ToLower(filepath.Ext(job.InputPath))
+	if inputFormat == ".md" || inputFormat == ".markdown" {
+		page, err = c.renderMarkdown(source, job.InputPath)
+		if err != nil {
+			result.ErrorMessage = fmt.Sprintf("Failed to render Markdown: %v", err)
+			c.log.Error("%s", result.ErrorMessage)
+			return result, fmt.Errorf("%s", result.ErrorMessage)
+		}
+	} else {
+		page, err = injectBaseURL(source, job.InputPath)
+		if err != nil {
+			result.ErrorMessage = fmt.Sprintf("Failed to resolve the HTML page's directory: %v", err)
+			c.log.Error("%s", result.ErrorMessage)
+			return result, fmt.Errorf("%s", result.ErrorMessage)
+		}
+	}
+
+	if progressCallback != nil {
+		progressCallback(30)
+	}
+
+	tempDir, err := scratch.MkdirTemp("markup-*")
+	if err != nil {
+		result.ErrorMessage = fmt.Sprintf("Failed to create temp directory: %v", err)
+		c.log.Error("%s", result.ErrorMessage)
+		return result, fmt.Errorf("%s", result.ErrorMessage)
+	}
+	defer os.RemoveAll(tempDir)
+
+	// Keep the original base name, since LibreOffice names its output after it
+	base := strings.TrimSuffix(filepath.Base(job.InputPath), filepath.Ext(job.InputPath))
+	pagePath := filepath.Join(tempDir, base+".html")
+	if err := os.WriteFile(pagePath, page, 0644); err != nil {
+		result.ErrorMessage = fmt.Sprintf("Failed to write HTML page: %v", err)
+		c.log.Error("%s", result.ErrorMessage)
+		return result, fmt.Errorf("%s", result.ErrorMessage)
+	}
+
+	if c.renderer.IsAvailable() {
+		err = c.renderer.Render(ctx, pagePath, job.OutputPath)
+	} else {
+		c.log.Debug("wkhtmltopdf not found, rendering with LibreOffice")
+		err = c.office.Convert(ctx, pagePath, job.OutputPath, "pdf:writer_web_pdf_Export")
+	}
+	if err != nil {
+		result.ErrorMessage = err.Error()
+		c.log.Error("Markup conversion failed: %v", err)
+		return result, err
+	}
+
+	if progressCallback != nil {
+		progressCallback(100)
+	}
+
+	if stat, err := os.Stat(job.OutputPath); err == nil {
+		result.OutputSize = stat.Size()
+	}
+
+	result.Success = true
+	result.Duration = time.Since(startTime).Milliseconds()
+
+	c.log.Info("Markup conversion completed in %dms: %s", result.Duration, job.OutputPath)
+	return result, nil
+}
+
+// renderMarkdown renders Markdown to a standalone HTML page. Relative links
+// and images resolve against the Markdown file's directory.
+func (c *markupConverter) renderMarkdown(source []byte, inputPath string) ([]byte, error) {
+	var body bytes.Buffer
+	if err := c.markdown.Convert(source, &body); err != nil {
+		return nil, err
+	}
+
+	baseURL, err := inputBaseURL(inputPath)
+	if err != nil {
+		return nil, err
+	}
+
+	title := strings.TrimSuffix(filepath.Base(inputPath), filepath.Ext(inputPath))
+
+	var page bytes.Buffer
+	fmt.Fprintf(&page, "<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<base href=\"%s\">\n<title>%s</title>\n<style>\n%s\n</style>\n</head>\n<body>\n",
+		html.EscapeString(baseURL.String()), html.EscapeString(title), markupStylesheet)
+	page.Write(body.Bytes())
+	page.WriteString("</body>\n</html>\n")
+	return page.Bytes(), nil
+}
+
+// inputBaseURL returns the file URL of the directory of inputPath, against
+// which the page's relative links and images resolve
+func inputBaseURL(inputPath string) (*url.URL, error) {
+	dir, err := filepath.Abs(filepath.Dir(inputPath))
+	if err != nil {
+		return nil, err
+	}
+	baseURL := &url.URL{Scheme: "file", Path: filepath.ToSlash(dir) + "/"}
+	if !strings.HasPrefix(baseURL.Path, "/") {
+		baseURL.Path = "/" + baseURL.Path
+	}
+	return baseURL, nil
+}
+
+var (
+	htmlBaseTag = regexp.MustCompile(`(?i)<base[\s>]`)
+
+	// htmlBaseAnchors are the tags a <base> is inserted after, in order of
+	// preference
+	htmlBaseAnchors = []*regexp.Regexp{
+		regexp.MustCompile(`(?i)<head(\s[^>]*)?>`),
+		regexp.MustCompile(`(?i)<html(\s[^>]*)?>`),
+		regexp.MustCompile(`(?i)<!doctype[^>]*>`),
+	}
+)
+
+// injectBaseURL adds a <base href> for the directory of inputPath to an HTML
+// page, unless the page sets its own base
+func injectBaseURL(source []byte, inputPath string) ([]byte, error) {
+	if htmlBaseTag.Match(source) {
+		return source, nil
+	}
+	baseURL, err := inputBaseURL(inputPath)
+	if err != nil {
+		return nil, err
+	}
+	tag := fmt.Sprintf("<base href=\"%s\">", html.EscapeString(baseURL.String()))
+
+	// Pages without any of the tags get the <base> first, which still
+	// applies to the whole page
+	at := 0
+	for _, anchor := range htmlBaseAnchors {
+		if loc := anchor.FindIndex(source); loc != nil {
+			at = loc[1]
+			break
+		}
+	}
+	page := make([]byte, 0, len(source)+len(tag))
+	page = append(page, source[:at]...)
+	page = append(page, tag...)
+	return append(page, source[at:]...), nil
+}
+
+// SupportedInputFormats returns the list of supported Markdown and HTML formats
+func (c *markupConverter) SupportedInputFormats() []string {
+	formats := make([]string, 0, len(models.MarkupFormats))
+	for format := range models.MarkupFormats {
+		formats = append(formats, strings.TrimPrefix(format, "."))
+	}
+	return formats
+}
+
+// SupportedOutputFormats returns the list of supported output formats for markup
+func (c *markupConverter) SupportedOutputFormats(inputFormat string) []string {
+	return models.DocumentOutputFormats
+}
+
+// CanConvert checks if conversion is possible between formats
+func (c *markupConverter) CanConvert(inputFormat, outputFormat string) bool {
+	inputFormat = strings.ToLower(strings.TrimPrefix(inputFormat, "."))
+	outputFormat = strings.ToLower(strings.TrimPrefix(outputFormat, "."))
+
+	if !models.MarkupFormats["."+inputFormat] {
+		return false
+	}
+
+	for _, format := range models.DocumentOutputFormats {
+		if format == outputFormat {
+			return true
+		}
+	}
+	return false
+}
+
+// Backend names the tool pages are printed with
+func (c *markupConverter) Backend(job models.ConversionJob) string {
+	if c.renderer.IsAvailable() {
+		return "wkhtmltopdf"
+	}
+	return "libreoffice"
+}
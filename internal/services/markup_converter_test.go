@@ -0,0 +1,39 @@
+package services
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestInjectBaseURL(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "page.html")
+	baseURL, err := inputBaseURL(input)
+	if err != nil {
+		t.Fatalf("inputBaseURL failed: %v", err)
+	}
+	tag := `<base href="` + baseURL.String() + `">`
+
+	for _, test := range []struct {
+		name, page, want string
+	}{
+		{"head", `<!DOCTYPE html><html><HEAD lang="en"><title>t</title></HEAD><body><img src="logo.png"></body></html>`,
+			`<!DOCTYPE html><html><HEAD lang="en">` + tag + `<title>t</title>`},
+		{"no head", `<!DOCTYPE html><html lang="en"><body><img src="logo.png"></body></html>`,
+			`<!DOCTYPE html><html lang="en">` + tag + `<body>`},
+		{"doctype only", `<!doctype html><p><a href="notes.html">notes</a></p>`,
+			`<!doctype html>` + tag + `<p>`},
+		{"fragment", `<p><img src="logo.png"></p>`, tag + `<p>`},
+		{"own base", `<html><head><base href="https://example.com/"></head></html>`,
+			`<html><head><base href="https://example.com/"></head></html>`},
+	} {
+		page, err := injectBaseURL([]byte(test.page), input)
+		if err != nil {
+			t.Fatalf("%s: injectBaseURL failed: %v", test.name, err)
+		}
+		if !strings.HasPrefix(string(page), test.want) {
+			t.Errorf("%s: page = %q, want it to start with %q", test.name, page, test.want)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package services
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// nameTemplateToken matches a placeholder such as {name} or {n:3}
+var nameTemplateToken = regexp.MustCompile(`\{([a-z]+)(?::(\d+))?\}`)
+
+// invalidNameChars are characters that can't appear in file names on every
+// supported OS
+const invalidNameChars = `/\:*?"<>|`
+
+// renderNameTemplate builds a file name (without extension) for the file at
+// inputPath from a naming template. Supported placeholders:
+//
+//	{name}   original file name without extension
+//	{ext}    original extension without the dot
+//	{parent} name of the folder containing the file
+//	{date}   modification date of the file (YYYY-MM-DD)
+//	{n}      position of the file in the batch, {n:3} zero-pads it to 3 digits
+//
+// index is the file's position, starting from the request's start index.
+func renderNameTemplate(template, inputPath string, index int) (string, error) {
+	if strings.TrimSpace(template) == "" {
+		return "", fmt.Errorf("naming template is empty")
+	}
+
+	var renderErr error
+	name := nameTemplateToken.ReplaceAllStringFunc(template, func(token string) string {
+		match := nameTemplateToken.FindStringSubmatch(token)
+		placeholder, width := match[1], match[2]
+
+		switch placeholder {
+		case "name":
+			return strings.TrimSuffix(filepath.Base(inputPath), filepath.Ext(inputPath))
+		case "ext":
+			return strings.TrimPrefix(filepath.Ext(inputPath), ".")
+		case "parent":
+			return filepath.Base(filepath.Dir(inputPath))
+		case "date":
+			stat, err := os.Stat(inputPath)
+			if err != nil {
+				renderErr = err
+				return ""
+			}
+			return stat.ModTime().Format("2006-01-02")
+		case "n":
+			if width == "" {
+				return strconv.Itoa(index)
+			}
+			digits, _ := strconv.Atoi(width)
+			return fmt.Sprintf("%0*d", digits, index)
+		}
+		renderErr = fmt.Errorf("unknown placeholder %s in naming template", token)
+		return ""
+	})
+	if renderErr != nil {
+		return "", renderErr
+	}
+
+	// Replace characters that would create folders or are invalid on Windows
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(invalidNameChars, r) || r < 0x20 {
+			return '_'
+		}
+		return r
+	}, name)
+
+	name = strings.TrimSpace(name)
+	if name == "" || name == "." || name == ".." {
+		return "", fmt.Errorf("naming template produces an empty name for %s", filepath.Base(inputPath))
+	}
+	return name, nil
+}
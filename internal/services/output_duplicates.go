@@ -0,0 +1,160 @@
+package services
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"converzen/internal/models"
+	"converzen/pkg/iolimit"
+)
+
+// maxNameSuffix bounds the " (n)" suffixes tried for a free name
+const maxNameSuffix = 1000
+
+// freePath returns path if nothing exists there, otherwise the first free
+// "<name> (n).<ext>" next to it
+func freePath(path string) (string, error) {
+	return freeUnclaimedPath(path, nil)
+}
+
+// freeUnclaimedPath is freePath skipping the paths in claimed as well
+func freeUnclaimedPath(path string, claimed map[string]bool) (string, error) {
+	ext := filepath.Ext(path)
+	base := strings.TrimSuffix(path, ext)
+
+	candidate := path
+	for n := 2; n <= maxNameSuffix; n++ {
+		if _, err := os.Stat(candidate); os.IsNotExist(err) && !claimed[candidate] {
+			return candidate, nil
+		}
+		candidate = fmt.Sprintf("%s (%d)%s", base, n, ext)
+	}
+	return "", fmt.Errorf("no free name found for %s", filepath.Base(path))
+}
+
+// partialOutputPath returns the hidden path a job writes to while its output
+// is checked for duplicates. The extension is kept so converters still pick
+// the output format from it.
+func partialOutputPath(outputPath string) string {
+	ext := filepath.Ext(outputPath)
+	name := strings.TrimSuffix(filepath.Base(outputPath), ext)
+	return filepath.Join(filepath.Dir(outputPath), "."+name+".partial"+ext)
+}
+
+// settleOutput moves a finished output from the job's partial path to
+// outputPath, applying the job's duplicate policy when the destination
+// folder already holds a byte-identical file. The result is updated with
+// where the output ended up.
+func (s *conversionServiceImpl) settleOutput(job models.ConversionJob, outputPath string, result *models.ConversionResult) error {
+	partialPath := job.OutputPath
+
+	identical, err := findIdenticalFile(partialPath, job.IOLimit())
+	if err != nil {
+		s.log.Warn("Couldn't check %s for duplicates: %v", outputPath, err)
+	}
+
+	if identical != "" {
+		s.log.Info("Output of %s is identical to %s (%s)", job.InputPath, identical, job.DuplicateOutputs)
+		switch job.DuplicateOutputs {
+		case models.DuplicatesSkip:
+			os.Remove(partialPath)
+			result.Success = false
+			result.Skipped = true
+			result.OutputPath = identical
+			result.Warnings = append(result.Warnings, fmt.Sprintf("Identical to the existing %s, nothing was written", filepath.Base(identical)))
+			return nil
+		case models.DuplicatesReplace:
+			if identical != outputPath {
+				if err := os.Remove(identical); err != nil {
+					os.Remove(partialPath)
+					return fmt.Errorf("failed to remove the identical file %s: %w", identical, err)
+				}
+			}
+			result.Warnings = append(result.Warnings, fmt.Sprintf("Replaced the identical %s", filepath.Base(identical)))
+		case models.DuplicatesKeepBoth:
+			if identical == outputPath {
+				if outputPath, err = freePath(outputPath); err != nil {
+					os.Remove(partialPath)
+					return err
+				}
+			}
+			result.Warnings = append(result.Warnings, fmt.Sprintf("Identical to the existing %s, kept both", filepath.Base(identical)))
+		}
+	}
+
+	// The identical file being replaced may be overwritten regardless
+	if !job.OverwriteOutput && outputPath != identical {
+		if _, err := os.Stat(outputPath); err == nil {
+			os.Remove(partialPath)
+			return fmt.Errorf("output file already exists: %s", outputPath)
+		}
+	}
+
+	if err := os.Rename(partialPath, outputPath); err != nil {
+		os.Remove(partialPath)
+		return fmt.Errorf("failed to move output into place: %w", err)
+	}
+	result.OutputPath = outputPath
+	return nil
+}
+
+// findIdenticalFile returns a file next to path with the same content, or ""
+// if there is none. Only files of the same size are hashed, reading at no
+// more than bytesPerSecond (0 = unlimited).
+func findIdenticalFile(path string, bytesPerSecond int64) (string, error) {
+	stat, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		return "", err
+	}
+
+	var hash []byte
+	for _, entry := range entries {
+		candidate := filepath.Join(filepath.Dir(path), entry.Name())
+		if !entry.Type().IsRegular() || candidate == path {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || info.Size() != stat.Size() {
+			continue
+		}
+
+		if hash == nil {
+			if hash, err = fileHash(path, bytesPerSecond); err != nil {
+				return "", err
+			}
+		}
+		candidateHash, err := fileHash(candidate, bytesPerSecond)
+		if err != nil {
+			continue
+		}
+		if bytes.Equal(hash, candidateHash) {
+			return candidate, nil
+		}
+	}
+	return "", nil
+}
+
+// fileHash returns the SHA-256 digest of a file's content, read at no more
+// than bytesPerSecond (0 = unlimited)
+func fileHash(path string, bytesPerSecond int64) ([]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, iolimit.Reader(file, bytesPerSecond)); err != nil {
+		return nil, err
+	}
+	return hash.Sum(nil), nil
+}
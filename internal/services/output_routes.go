@@ -0,0 +1,93 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"converzen/internal/models"
+)
+
+// routedDirectory returns the directory routes send outputFormat's files
+// to, or "" if the format has no usable route. A leading "~" is the home
+// directory; other relative routes are ignored.
+func routedDirectory(routes map[string]string, outputFormat string) string {
+	outputFormat = strings.TrimPrefix(strings.ToLower(outputFormat), ".")
+
+	var dir string
+	for format, route := range routes {
+		if strings.TrimPrefix(strings.ToLower(format), ".") == outputFormat {
+			dir = strings.TrimSpace(route)
+			break
+		}
+	}
+	if dir == "" {
+		return ""
+	}
+
+	if dir == "~" || strings.HasPrefix(dir, "~/") || strings.HasPrefix(dir, `~\`) {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, dir[1:])
+	}
+	if !filepath.IsAbs(dir) {
+		return ""
+	}
+	return filepath.Clean(dir)
+}
+
+// batchOutputDirs returns the directories a batch may write to. Each file
+// goes to its own output directory, the batch's, the routed directory of
+// each output format it may be converted to, or its input's directory for
+// formats without a route.
+func batchOutputDirs(request models.BatchConversionRequest, routes map[string]string) []string {
+	batchFormats := []string{request.OutputFormat}
+	for _, rule := range request.Rules {
+		batchFormats = append(batchFormats, rule.Then.OutputFormat)
+		if rule.Else != nil {
+			batchFormats = append(batchFormats, rule.Else.OutputFormat)
+		}
+	}
+
+	seen := make(map[string]bool)
+	var dirs []string
+	add := func(dir string) {
+		if !seen[dir] {
+			seen[dir] = true
+			dirs = append(dirs, dir)
+		}
+	}
+
+	for i, path := range request.Files {
+		var fileSettings models.FileSettings
+		if i < len(request.FileSettings) {
+			fileSettings = request.FileSettings[i]
+		}
+		if fileSettings.OutputDirectory != "" {
+			add(fileSettings.OutputDirectory)
+			continue
+		}
+		if request.OutputDirectory != "" {
+			add(request.OutputDirectory)
+			continue
+		}
+
+		formats := batchFormats
+		if fileSettings.OutputFormat != "" {
+			formats = []string{fileSettings.OutputFormat}
+		}
+		for _, format := range formats {
+			if format == "" {
+				continue
+			}
+			if dir := routedDirectory(routes, format); dir != "" {
+				add(dir)
+			} else {
+				add(filepath.Dir(path))
+			}
+		}
+	}
+	return dirs
+}
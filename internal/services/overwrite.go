@@ -0,0 +1,53 @@
+package services
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"converzen/internal/models"
+)
+
+// validateOverwrite checks a batch's overwrite policies before any file is
+// converted
+func validateOverwrite(request models.BatchConversionRequest) error {
+	if !validOverwritePolicy(request.Overwrite) {
+		return fmt.Errorf("unknown overwrite policy %q", request.Overwrite)
+	}
+	for i, settings := range request.FileSettings {
+		if !validOverwritePolicy(settings.Overwrite) {
+			return fmt.Errorf("unknown overwrite policy %q for %s", settings.Overwrite, filepath.Base(request.Files[i]))
+		}
+	}
+	return nil
+}
+
+// validOverwritePolicy reports whether a policy is one of the known ones
+func validOverwritePolicy(policy models.OverwritePolicy) bool {
+	switch policy {
+	case models.OverwriteDefault, models.OverwriteNever, models.OverwriteReplace, models.OverwriteRename:
+		return true
+	}
+	return false
+}
+
+// applyOverwritePolicy sets whether a job may replace an existing output,
+// or moves its output to a free name. claimed holds the outputs of the
+// batch's files so far, which two files renamed before either is written
+// mustn't both get.
+func applyOverwritePolicy(job *models.ConversionJob, policy models.OverwritePolicy, claimed map[string]bool) error {
+	switch policy {
+	case models.OverwriteNever:
+		job.OverwriteOutput = false
+	case models.OverwriteRename:
+		job.OverwriteOutput = false
+		path, err := freeUnclaimedPath(job.OutputPath, claimed)
+		if err != nil {
+			return err
+		}
+		job.OutputPath = path
+	default:
+		job.OverwriteOutput = true
+	}
+	claimed[job.OutputPath] = true
+	return nil
+}
@@ -0,0 +1,241 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+
+	"converzen/internal/models"
+	"converzen/pkg/scratch"
+)
+
+// pipelineConverter runs a job's pipeline steps in turn, each with the
+// converter for its input's file type. Intermediate outputs are written to
+// the scratch directory and removed when the pipeline ends. Progress is
+// combined over the steps, each weighted equally.
+type pipelineConverter struct {
+	service *conversionServiceImpl
+}
+
+// validatePipeline checks a job's pipeline before any step runs
+func validatePipeline(job models.ConversionJob) error {
+	final := -1
+	for i, step := range job.Pipeline {
+		if len(step.Options.Pipeline) > 0 {
+			return fmt.Errorf("pipeline step %d can't contain a pipeline of its own", i+1)
+		}
+		if !step.Branch {
+			final = i
+			continue
+		}
+		if step.Options.OutputFormat == "" {
+			return fmt.Errorf("pipeline step %d is a branch and needs an output format", i+1)
+		}
+		if job.CacheInput || job.DuplicateOutputs != models.DuplicatesAllow {
+			return fmt.Errorf("pipelines with branch steps can't be combined with input caching or duplicate checks")
+		}
+	}
+	if final < 0 {
+		return fmt.Errorf("a pipeline needs at least one step that isn't a branch")
+	}
+	return nil
+}
+
+// finalStep returns the index of the step that writes the job's output: the
+// last one that isn't a branch
+func finalStep(steps []models.PipelineStep) int {
+	for i := len(steps) - 1; i >= 0; i-- {
+		if !steps[i].Branch {
+			return i
+		}
+	}
+	return -1
+}
+
+// stepName returns the name a step is recorded under
+func stepName(step models.PipelineStep, i int) string {
+	if step.Name != "" {
+		return step.Name
+	}
+	return fmt.Sprintf("Step %d", i+1)
+}
+
+// branchPath returns where a branch step writes its output: next to the
+// job's output, with the step's name appended
+func branchPath(outputPath string, step models.PipelineStep, i int) string {
+	suffix := strings.Trim(strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return '-'
+	}, step.Name), "-")
+	if suffix == "" {
+		suffix = fmt.Sprintf("step%d", i+1)
+	}
+	base := strings.TrimSuffix(outputPath, filepath.Ext(outputPath))
+	return base + "-" + suffix + "." + strings.TrimPrefix(strings.ToLower(step.Options.OutputFormat), ".")
+}
+
+// stepJob builds the job of pipeline step i, converting input. Resource
+// limits and logging are the pipeline's; paths are set by the pipeline.
+func (c *pipelineConverter) stepJob(job models.ConversionJob, i int, input, dir string, settings *models.UserSettings) models.ConversionJob {
+	step := job.Pipeline[i]
+	stepJob := step.Options
+	stepJob.InputPath = input
+	stepJob.Pipeline = nil
+	stepJob.PreviewLength = 0
+	applyJobSettings(&stepJob, settings)
+	stepJob.Threads = job.Threads
+	stepJob.LowPriority = job.LowPriority
+	stepJob.IOLimitMBps = job.IOLimitMBps
+	stepJob.Log = job.Log
+
+	switch {
+	case i == finalStep(job.Pipeline):
+		stepJob.OutputPath = job.OutputPath
+		stepJob.OutputFormat = jobOutputFormat(job)
+		stepJob.OverwriteOutput = job.OverwriteOutput
+	case step.Branch:
+		stepJob.OutputPath = branchPath(job.OutputPath, step, i)
+		stepJob.OverwriteOutput = job.OverwriteOutput
+	default:
+		format := jobOutputFormat(stepJob)
+		if format == "" {
+			format = strings.TrimPrefix(strings.ToLower(filepath.Ext(input)), ".")
+		}
+		stepJob.OutputFormat = format
+		stepJob.OutputPath = filepath.Join(dir, fmt.Sprintf("step-%d.%s", i+1, format))
+		stepJob.OverwriteOutput = true
+	}
+	return stepJob
+}
+
+// Convert runs the job's pipeline
+func (c *pipelineConverter) Convert(ctx context.Context, job models.ConversionJob, progressCallback func(progress float64)) (*models.ConversionResult, error) {
+	startTime := time.Now()
+	result := &models.ConversionResult{
+		InputPath:  job.InputPath,
+		OutputPath: job.OutputPath,
+	}
+
+	fail := func(err error) (*models.ConversionResult, error) {
+		result.ErrorMessage = err.Error()
+		c.service.log.Error("Pipeline failed: %v", err)
+		return result, err
+	}
+
+	if err := validatePipeline(job); err != nil {
+		return fail(err)
+	}
+
+	// Check every output before running anything, rather than failing
+	// after the slow steps
+	if !job.OverwriteOutput {
+		for i, step := range job.Pipeline {
+			path := job.OutputPath
+			if step.Branch {
+				path = branchPath(job.OutputPath, step, i)
+			}
+			if _, err := os.Stat(path); err == nil {
+				return fail(fmt.Errorf("output file already exists: %s", path))
+			}
+		}
+	}
+
+	dir, err := scratch.MkdirTemp("pipeline-*")
+	if err != nil {
+		return fail(fmt.Errorf("failed to create pipeline directory: %w", err))
+	}
+	defer os.RemoveAll(dir)
+
+	settings := c.service.userSettings()
+	final := finalStep(job.Pipeline)
+	steps := float64(len(job.Pipeline))
+	input := job.InputPath
+
+	for i, step := range job.Pipeline {
+		stepJob := c.stepJob(job, i, input, dir, settings)
+		record := models.PipelineStepResult{Name: stepName(step, i)}
+		if i == final || step.Branch {
+			record.OutputPath = stepJob.OutputPath
+		}
+		c.service.log.Info("Pipeline step %d of %d (%s): %s -> %s", i+1, len(job.Pipeline), record.Name, stepJob.InputPath, stepJob.OutputPath)
+		if job.Log != nil {
+			fmt.Fprintf(job.Log, "Pipeline step %d of %d (%s): %s -> %s\n", i+1, len(job.Pipeline), record.Name, stepJob.InputPath, stepJob.OutputPath)
+		}
+
+		fileType := models.GetFileType(strings.ToLower(filepath.Ext(input)))
+		converter, err := c.service.converterFor(fileType)
+		if err != nil {
+			record.ErrorMessage = err.Error()
+			result.Steps = append(result.Steps, record)
+			return fail(fmt.Errorf("step %d (%s): %w", i+1, record.Name, err))
+		}
+
+		stepStart := time.Now()
+		stepResult, err := converter.Convert(ctx, stepJob, func(progress float64) {
+			if progressCallback != nil {
+				progressCallback((float64(i) + progress/100) / steps * 100)
+			}
+		})
+		record.Duration = time.Since(stepStart).Milliseconds()
+		if stepResult != nil {
+			record.Method = stepResult.Method
+			result.Warnings = append(result.Warnings, stepResult.Warnings...)
+		}
+		if err != nil {
+			record.ErrorMessage = err.Error()
+			result.Steps = append(result.Steps, record)
+			return fail(fmt.Errorf("step %d (%s): %w", i+1, record.Name, err))
+		}
+		record.Success = true
+		result.Steps = append(result.Steps, record)
+
+		// Branches leave the chain where it was
+		if !step.Branch {
+			input = stepJob.OutputPath
+		}
+	}
+
+	if stat, err := os.Stat(job.OutputPath); err == nil {
+		result.OutputSize = stat.Size()
+	}
+	result.Success = true
+	result.Duration = time.Since(startTime).Milliseconds()
+
+	c.service.log.Info("Pipeline of %d steps completed in %dms: %s", len(job.Pipeline), result.Duration, job.OutputPath)
+	return result, nil
+}
+
+// SupportedInputFormats returns the formats any of the service's converters read
+func (c *pipelineConverter) SupportedInputFormats() []string {
+	var formats []string
+	for _, converter := range c.service.converters() {
+		formats = append(formats, converter.SupportedInputFormats()...)
+	}
+	return formats
+}
+
+// SupportedOutputFormats returns the formats the converters for inputFormat
+// write. Later steps may convert to formats of other file types.
+func (c *pipelineConverter) SupportedOutputFormats(inputFormat string) []string {
+	var formats []string
+	for _, converter := range c.service.converters() {
+		formats = append(formats, converter.SupportedOutputFormats(inputFormat)...)
+	}
+	return formats
+}
+
+// CanConvert reports whether any converter converts inputFormat to outputFormat
+func (c *pipelineConverter) CanConvert(inputFormat, outputFormat string) bool {
+	for _, converter := range c.service.converters() {
+		if converter.CanConvert(inputFormat, outputFormat) {
+			return true
+		}
+	}
+	return false
+}
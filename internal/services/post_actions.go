@@ -0,0 +1,191 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"converzen/internal/desktop"
+	"converzen/internal/models"
+)
+
+const (
+	// scriptTimeout bounds each run of a pre- or post-conversion script
+	scriptTimeout = 10 * time.Minute
+
+	// maxOpenedFolders limits how many folders are opened for a batch
+	// written next to inputs spread over many folders
+	maxOpenedFolders = 5
+
+	// powerActionDelay is how long a sleep or shutdown waits after a batch,
+	// on every platform, so the user can cancel it
+	powerActionDelay = time.Minute
+)
+
+// batchOutput is a successfully converted file of a batch
+type batchOutput struct {
+	inputPath  string
+	outputPath string
+}
+
+// validatePostActions checks a batch's post-conversion actions before any
+// file is converted
+func validatePostActions(actions models.PostActions) error {
+	switch actions.Power {
+	case models.PowerActionNone, models.PowerActionSleep, models.PowerActionShutdown:
+	default:
+		return fmt.Errorf("unknown power action: %s", actions.Power)
+	}
+
+	if actions.Script != "" {
+		return checkScript(actions.Script)
+	}
+	return nil
+}
+
+// checkScript checks that a pre- or post-conversion script exists
+func checkScript(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("script not found: %w", err)
+	}
+	if info.IsDir() {
+		return fmt.Errorf("script is a directory: %s", path)
+	}
+	return nil
+}
+
+// runPostActions runs a batch's post-conversion actions over its successful
+// outputs, in the order script, trash, open folder, power. It returns a
+// warning for each action that failed, and when the sleep or shutdown will
+// run if one was scheduled. Batches that converted nothing don't sleep or
+// shut down, so a failed overnight batch can be looked into.
+func (s *conversionServiceImpl) runPostActions(actions models.PostActions, outputs []batchOutput) ([]string, *time.Time) {
+	var warnings []string
+	warn := func(format string, args ...any) {
+		message := fmt.Sprintf(format, args...)
+		s.log.Warn("Post-conversion action failed: %s", message)
+		warnings = append(warnings, message)
+	}
+
+	if actions.Script != "" {
+		for _, output := range outputs {
+			if err := s.runPostScript(actions.Script, output.outputPath); err != nil {
+				warn("Script failed for %s: %v", filepath.Base(output.outputPath), err)
+			}
+		}
+	}
+
+	if actions.TrashOriginals {
+		for _, output := range outputs {
+			// A conversion that replaced its input has no original left
+			if filepath.Clean(output.inputPath) == filepath.Clean(output.outputPath) {
+				continue
+			}
+			if err := desktop.Trash(output.inputPath); err != nil {
+				warn("Couldn't move %s to the trash: %v", filepath.Base(output.inputPath), err)
+				continue
+			}
+			s.log.Info("Moved %s to the trash", output.inputPath)
+		}
+	}
+
+	if actions.OpenFolder && len(outputs) > 0 {
+		paths := make([]string, len(outputs))
+		for i, output := range outputs {
+			paths[i] = output.outputPath
+		}
+		dirs := uniqueDirs(paths)
+		for _, dir := range dirs[:min(len(dirs), maxOpenedFolders)] {
+			if err := desktop.OpenFolder(dir); err != nil {
+				warn("Couldn't open %s: %v", dir, err)
+			}
+		}
+	}
+
+	if actions.Power == models.PowerActionNone {
+		return warnings, nil
+	}
+	if len(outputs) == 0 {
+		warn("Skipped the %s after the batch, as no file was converted", actions.Power)
+		return warnings, nil
+	}
+	runAt := s.schedulePowerAction(actions.Power)
+	return warnings, &runAt
+}
+
+// schedulePowerAction runs a sleep or shutdown after powerActionDelay,
+// replacing one already waiting, and returns when it will run
+func (s *conversionServiceImpl) schedulePowerAction(action models.PowerAction) time.Time {
+	s.powerMu.Lock()
+	defer s.powerMu.Unlock()
+
+	if s.powerTimer != nil {
+		s.powerTimer.Stop()
+	}
+	var timer *time.Timer
+	timer = time.AfterFunc(powerActionDelay, func() {
+		s.powerMu.Lock()
+		if s.powerTimer != timer {
+			s.powerMu.Unlock()
+			return
+		}
+		s.powerTimer = nil
+		s.powerMu.Unlock()
+
+		switch action {
+		case models.PowerActionSleep:
+			s.log.Info("Putting the computer to sleep after the batch")
+			if err := desktop.Sleep(); err != nil {
+				s.log.Error("Couldn't put the computer to sleep: %v", err)
+			}
+		case models.PowerActionShutdown:
+			s.log.Info("Shutting down the computer after the batch")
+			if err := desktop.Shutdown(); err != nil {
+				s.log.Error("Couldn't shut down the computer: %v", err)
+			}
+		}
+	})
+	s.powerTimer = timer
+
+	s.log.Info("Scheduled the %s after the batch in %s, unless cancelled", action, powerActionDelay)
+	return time.Now().Add(powerActionDelay)
+}
+
+// CancelPowerAction cancels the sleep or shutdown waiting to run after a
+// batch, reporting whether one was pending
+func (s *conversionServiceImpl) CancelPowerAction() bool {
+	s.powerMu.Lock()
+	defer s.powerMu.Unlock()
+
+	if s.powerTimer == nil || !s.powerTimer.Stop() {
+		return false
+	}
+	s.powerTimer = nil
+	s.log.Info("Cancelled the sleep or shutdown after the batch")
+	return true
+}
+
+// runPostScript runs a user's script with an output path as its argument,
+// logging what it printed
+func (s *conversionServiceImpl) runPostScript(script, outputPath string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), scriptTimeout)
+	defer cancel()
+
+	output, err := exec.CommandContext(ctx, script, outputPath).CombinedOutput()
+	if message := strings.TrimSpace(string(output)); message != "" {
+		s.log.Debug("Script output for %s: %s", outputPath, message)
+	}
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("timed out after %s", scriptTimeout)
+	}
+	if err != nil {
+		return err
+	}
+	s.log.Info("Ran %s for %s", filepath.Base(script), outputPath)
+	return nil
+}
@@ -0,0 +1,49 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"time"
+
+	"converzen/internal/models"
+)
+
+// scriptWaitDelay bounds how long a killed pre-conversion script's output
+// is still read
+const scriptWaitDelay = 5 * time.Second
+
+// runPreScript runs a job's pre-conversion script, sending its output to the
+// job's log. It reports whether the script asked to skip the file; any other
+// failure is returned as an error that fails the job. Cancelling ctx kills
+// the script.
+func (s *conversionServiceImpl) runPreScript(ctx context.Context, job models.ConversionJob) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, scriptTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, job.PreScript, job.InputPath, job.OutputPath)
+	if job.Log != nil {
+		cmd.Stdout = job.Log
+		cmd.Stderr = job.Log
+	}
+	// Don't wait on processes the killed script started that still hold its output
+	cmd.WaitDelay = scriptWaitDelay
+	err := cmd.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		return false, fmt.Errorf("pre-conversion script timed out after %s", scriptTimeout)
+	}
+	if ctx.Err() != nil {
+		return false, fmt.Errorf("pre-conversion script was cancelled: %w", ctx.Err())
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == models.PreScriptSkipCode {
+		s.log.Info("Pre-conversion script skipped %s", job.InputPath)
+		return true, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("pre-conversion script failed: %w", err)
+	}
+	return false, nil
+}
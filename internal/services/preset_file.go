@@ -0,0 +1,101 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"converzen/internal/models"
+)
+
+// maxPresetFileSize bounds the preset files read, since they may come from anywhere
+const maxPresetFileSize = 1 << 20 // 1 MiB
+
+// WritePresetFile saves a preset as a .zenpreset JSON file, adding the
+// extension to path if it's missing
+func WritePresetFile(path string, preset models.Preset) (string, error) {
+	if err := validatePreset(preset); err != nil {
+		return "", err
+	}
+	if !strings.EqualFold(filepath.Ext(path), models.PresetFileExtension) {
+		path += models.PresetFileExtension
+	}
+
+	data, err := json.MarshalIndent(models.PresetFile{
+		Version: models.PresetFileVersion,
+		Preset:  preset,
+	}, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode preset: %w", err)
+	}
+
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return "", fmt.Errorf("failed to write preset file: %w", err)
+	}
+	return path, nil
+}
+
+// ReadPresetFile loads a preset from a .zenpreset file. Files written by a
+// newer version of the app, or with an unknown output format, are rejected.
+func ReadPresetFile(path string) (*models.Preset, error) {
+	if !strings.EqualFold(filepath.Ext(path), models.PresetFileExtension) {
+		return nil, fmt.Errorf("%s is not a %s file", filepath.Base(path), models.PresetFileExtension)
+	}
+
+	stat, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("preset file not found: %s", path)
+	}
+	if stat.Size() > maxPresetFileSize {
+		return nil, fmt.Errorf("preset file is too large: %s", filepath.Base(path))
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read preset file: %w", err)
+	}
+
+	var file models.PresetFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid preset file %s: %w", filepath.Base(path), err)
+	}
+	if file.Version < 1 || file.Version > models.PresetFileVersion {
+		return nil, fmt.Errorf("preset file version %d is not supported, update Converzen to import it", file.Version)
+	}
+	if err := validatePreset(file.Preset); err != nil {
+		return nil, err
+	}
+
+	if file.Preset.Name == "" {
+		file.Preset.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	return &file.Preset, nil
+}
+
+// validatePreset checks a preset names an output format this app can
+// produce, a valid video quality and a naming template when it uses one
+func validatePreset(preset models.Preset) error {
+	format := strings.TrimPrefix(strings.ToLower(preset.OutputFormat), ".")
+	if format == "" {
+		return fmt.Errorf("preset has no output format")
+	}
+	if err := validateQuality(preset.Quality, preset.CRF); err != nil {
+		return fmt.Errorf("preset %w", err)
+	}
+	if preset.NamingMode == models.NamingModeTemplate && strings.TrimSpace(preset.NameTemplate) == "" {
+		return fmt.Errorf("preset names files with a template but has none")
+	}
+	for _, fileType := range []models.FileType{
+		models.FileTypeVideo, models.FileTypeImage, models.FileTypeAudio,
+		models.FileTypeSubtitle, models.FileTypeEbook, models.FileTypeDocument,
+	} {
+		for _, output := range models.GetOutputFormats(fileType) {
+			if output == format {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("preset output format %q is not supported", preset.OutputFormat)
+}
@@ -0,0 +1,35 @@
+package services
+
+import (
+	"path/filepath"
+	"testing"
+
+	"converzen/internal/models"
+)
+
+func TestPresetFileKeepsNameTemplate(t *testing.T) {
+	preset := models.Preset{
+		Name:         "Dailies",
+		OutputFormat: "mp4",
+		NamingMode:   models.NamingModeTemplate,
+		NameTemplate: "{parent}-{n:3}",
+	}
+	path, err := WritePresetFile(filepath.Join(t.TempDir(), "dailies"), preset)
+	if err != nil {
+		t.Fatalf("WritePresetFile failed: %v", err)
+	}
+
+	imported, err := ReadPresetFile(path)
+	if err != nil {
+		t.Fatalf("ReadPresetFile failed: %v", err)
+	}
+	if imported.NamingMode != models.NamingModeTemplate || imported.NameTemplate != preset.NameTemplate {
+		t.Errorf("imported naming %s %q, want %s %q",
+			imported.NamingMode, imported.NameTemplate, preset.NamingMode, preset.NameTemplate)
+	}
+
+	preset.NameTemplate = ""
+	if _, err := WritePresetFile(filepath.Join(t.TempDir(), "broken"), preset); err == nil {
+		t.Errorf("WritePresetFile accepted a template preset without a template")
+	}
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"converzen/internal/logger"
+	"converzen/internal/models"
+	"converzen/internal/repository"
+)
+
+// maxRecentPaths is the number of recent paths kept per kind
+const maxRecentPaths = 50
+
+// recentServiceImpl implements RecentService
+type recentServiceImpl struct {
+	repo repository.RecentRepository
+	log  *logger.ComponentLogger
+}
+
+// NewRecentService creates a new RecentService
+func NewRecentService(repo repository.RecentRepository, log *logger.Logger) RecentService {
+	return &recentServiceImpl{
+		repo: repo,
+		log:  log.WithComponent("recent-service"),
+	}
+}
+
+// RecordBatch records a batch's input files and output directory as used.
+// Batches without an output directory write next to their inputs, so the
+// inputs' directories are recorded instead.
+func (s *recentServiceImpl) RecordBatch(request models.BatchConversionRequest) error {
+	outputDirs := []string{request.OutputDirectory}
+	if request.OutputDirectory == "" {
+		outputDirs = uniqueDirs(request.Files)
+	}
+
+	if err := s.record(models.RecentInput, request.Files); err != nil {
+		return err
+	}
+	return s.record(models.RecentOutputDir, outputDirs)
+}
+
+// record touches paths of a kind and trims the list to maxRecentPaths.
+// Batches larger than the list only record their last maxRecentPaths files.
+func (s *recentServiceImpl) record(kind models.RecentKind, paths []string) error {
+	if len(paths) > maxRecentPaths {
+		paths = paths[len(paths)-maxRecentPaths:]
+	}
+	if err := s.repo.Touch(kind, paths); err != nil {
+		return err
+	}
+	return s.repo.Trim(kind, maxRecentPaths)
+}
+
+// GetRecentInputs returns the most recently converted files
+func (s *recentServiceImpl) GetRecentInputs(limit int) ([]models.RecentPath, error) {
+	return s.repo.List(models.RecentInput, recentLimit(limit))
+}
+
+// GetRecentOutputDirs returns the most recently used output directories
+func (s *recentServiceImpl) GetRecentOutputDirs(limit int) ([]models.RecentPath, error) {
+	return s.repo.List(models.RecentOutputDir, recentLimit(limit))
+}
+
+// recentLimit clamps a requested list length to (0, maxRecentPaths]
+func recentLimit(limit int) int {
+	if limit <= 0 || limit > maxRecentPaths {
+		return maxRecentPaths
+	}
+	return limit
+}
@@ -0,0 +1,231 @@
+package services
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"os"
+	"strings"
+
+	"converzen/internal/models"
+	"converzen/pkg/ffmpeg"
+)
+
+// lossyAudioCodecs are audio codecs whose files have already lost detail
+var lossyAudioCodecs = map[string]bool{
+	"mp3": true, "aac": true, "vorbis": true, "opus": true, "ac3": true, "eac3": true, "wmav2": true,
+}
+
+// RecommendSettings suggests an output format, codec and quality for a file
+// and goal, with the reasons behind them
+func (s *analysisServiceImpl) RecommendSettings(path string, goal models.RecommendationGoal) (*models.Recommendation, error) {
+	switch goal {
+	case models.GoalShare, models.GoalArchive, models.GoalEdit, models.GoalWeb:
+	default:
+		return nil, fmt.Errorf("unknown goal: %s", goal)
+	}
+
+	info, err := s.fileService.GetFileInfo(path)
+	if err != nil {
+		return nil, err
+	}
+
+	rec := &models.Recommendation{Path: path, Goal: goal}
+	switch info.Type {
+	case models.FileTypeVideo:
+		s.recommendVideo(rec)
+	case models.FileTypeAudio:
+		s.recommendAudio(rec)
+	case models.FileTypeImage:
+		recommendImage(rec)
+	default:
+		return nil, fmt.Errorf("no recommendations for %s files", info.Type)
+	}
+
+	s.log.Debug("Recommended %s for %s (%s)", rec.OutputFormat, path, goal)
+	return rec, nil
+}
+
+// recommendVideo fills in a recommendation for a video file. Without FFmpeg
+// the source can't be probed and only mp4 and mov are suggested.
+func (s *analysisServiceImpl) recommendVideo(rec *models.Recommendation) {
+	var probe *ffmpeg.Probe
+	if s.ffmpeg != nil {
+		if p, err := s.ffmpeg.ProbeFile(rec.Path); err == nil {
+			probe = p
+		}
+	}
+	hasEncoder := func(name string) bool {
+		return s.ffmpeg != nil && s.ffmpeg.HasEncoder(name)
+	}
+	alpha := probe != nil && probe.Alpha
+	remuxable := func(format string, allAudio bool) bool {
+		return probe != nil && ffmpeg.CanRemux(probe, format, allAudio)
+	}
+
+	switch rec.Goal {
+	case models.GoalShare:
+		rec.OutputFormat = "mp4"
+		rec.Quality = models.QualityBalanced
+		rec.Reasons = append(rec.Reasons, "MP4 with H.264 and AAC plays on virtually every phone, computer and messaging app.")
+		if remuxable("mp4", false) {
+			rec.Quality = models.QualityLossless
+			rec.Reasons = append(rec.Reasons, "The source's codecs already fit MP4, so it's repackaged without re-encoding.")
+		}
+		if alpha {
+			rec.Reasons = append(rec.Reasons, "MP4 can't carry the source's transparency, it's flattened.")
+		}
+
+	case models.GoalArchive:
+		if alpha && hasEncoder("prores_ks") {
+			rec.OutputFormat = "mov"
+			rec.PreserveAlpha = true
+			rec.Quality = models.QualityHigh
+			rec.Reasons = append(rec.Reasons, "ProRes 4444 in MOV keeps the source's transparency at near-lossless quality.")
+			break
+		}
+		if s.ffmpeg == nil {
+			rec.OutputFormat = "mov"
+			rec.Quality = models.QualityHigh
+			rec.Reasons = append(rec.Reasons, "MOV keeps the source's tracks and metadata.")
+			break
+		}
+		rec.OutputFormat = "mkv"
+		rec.KeepAllAudioTracks = true
+		rec.Quality = models.QualityHigh
+		rec.Reasons = append(rec.Reasons, "MKV holds every audio track, subtitles and chapters, and any codec, so nothing is dropped.")
+		if remuxable("mkv", true) {
+			rec.Quality = models.QualityLossless
+			rec.Reasons = append(rec.Reasons, "The source's streams are copied as-is, without quality loss.")
+		}
+
+	case models.GoalEdit:
+		rec.OutputFormat = "mov"
+		rec.Quality = models.QualityHigh
+		switch {
+		case alpha && hasEncoder("prores_ks"):
+			rec.VideoCodec = models.CodecProRes
+			rec.ProResProfile = models.ProRes4444
+			rec.PreserveAlpha = true
+			rec.Reasons = append(rec.Reasons, "ProRes 4444 keeps the source's transparency for compositing.")
+		case hasEncoder("prores_ks") || s.ffmpeg == nil:
+			rec.VideoCodec = models.CodecProRes
+			rec.ProResProfile = models.ProResHQ
+			rec.Reasons = append(rec.Reasons, "ProRes is an intra-frame codec, so editors scrub and cut it smoothly without proxies.")
+		case hasEncoder("dnxhd"):
+			rec.VideoCodec = models.CodecDNxHR
+			rec.DNxHRProfile = models.DNxHRHQ
+			if probe != nil && strings.Contains(probe.PixelFormat, "10") {
+				rec.DNxHRProfile = models.DNxHRHQX
+				rec.Reasons = append(rec.Reasons, "DNxHR HQX keeps the source's 10-bit color.")
+			}
+			rec.Reasons = append(rec.Reasons, "DNxHR is an intra-frame codec, so editors scrub and cut it smoothly without proxies.")
+		default:
+			rec.Reasons = append(rec.Reasons, "This FFmpeg build has no editing codec, MOV with H.264 is the closest fit.")
+		}
+		rec.Reasons = append(rec.Reasons, "Expect files several times larger than the source.")
+
+	case models.GoalWeb:
+		if hasEncoder("libvpx-vp9") {
+			rec.OutputFormat = "webm"
+			rec.Quality = models.QualityBalanced
+			rec.Reasons = append(rec.Reasons, "WebM with VP9 is smaller than H.264 at the same quality and plays in all current browsers.")
+			if alpha {
+				rec.PreserveAlpha = true
+				rec.Reasons = append(rec.Reasons, "VP9 keeps the source's transparency for overlays on the page.")
+			}
+			break
+		}
+		rec.OutputFormat = "mp4"
+		rec.Quality = models.QualityBalanced
+		rec.Reasons = append(rec.Reasons, "MP4 with H.264 plays in every browser.")
+	}
+}
+
+// recommendAudio fills in a recommendation for an audio file
+func (s *analysisServiceImpl) recommendAudio(rec *models.Recommendation) {
+	lossySource := false
+	if s.ffmpeg != nil {
+		if probe, err := s.ffmpeg.ProbeFile(rec.Path); err == nil {
+			lossySource = lossyAudioCodecs[probe.AudioCodec]
+		}
+	}
+
+	switch rec.Goal {
+	case models.GoalShare:
+		rec.OutputFormat = "mp3"
+		rec.Quality = models.QualityBalanced
+		rec.Reasons = append(rec.Reasons, "MP3 plays on every device and app.")
+	case models.GoalArchive:
+		rec.OutputFormat = "flac"
+		rec.Quality = models.QualityLossless
+		rec.Reasons = append(rec.Reasons, "FLAC is lossless and about half the size of WAV.")
+		if lossySource {
+			rec.Reasons = append(rec.Reasons, "The source is already compressed, so FLAC keeps its quality but can't restore what was lost; keeping the original is smaller.")
+		}
+	case models.GoalEdit:
+		rec.OutputFormat = "wav"
+		rec.Quality = models.QualityLossless
+		rec.Reasons = append(rec.Reasons, "Uncompressed WAV opens in every audio editor without decoding delays.")
+	case models.GoalWeb:
+		rec.OutputFormat = "m4a"
+		rec.Quality = models.QualityBalanced
+		rec.Reasons = append(rec.Reasons, "AAC in M4A plays in every browser at a small size.")
+	}
+}
+
+// recommendImage fills in a recommendation for an image file
+func recommendImage(rec *models.Recommendation) {
+	alpha := imageHasAlpha(rec.Path)
+
+	switch rec.Goal {
+	case models.GoalShare, models.GoalWeb:
+		if alpha {
+			rec.OutputFormat = "png"
+			rec.Quality = models.QualityLossless
+			rec.Reasons = append(rec.Reasons, "PNG keeps the image's transparency, which JPG would fill in.")
+			break
+		}
+		rec.OutputFormat = "jpg"
+		rec.Quality = models.QualityHigh
+		rec.Reasons = append(rec.Reasons, "JPG is small and opens everywhere, which suits photos.")
+	case models.GoalArchive:
+		rec.OutputFormat = "png"
+		rec.Quality = models.QualityLossless
+		rec.Reasons = append(rec.Reasons, "PNG is lossless and widely supported, so the image can be converted again later without degrading.")
+	case models.GoalEdit:
+		rec.OutputFormat = "tiff"
+		rec.Quality = models.QualityLossless
+		rec.Reasons = append(rec.Reasons, "TIFF is lossless and opens in every image editor and layout application.")
+	}
+}
+
+// imageHasAlpha reports whether an image's color model can be transparent.
+// Images that can't be decoded are treated as opaque.
+func imageHasAlpha(path string) bool {
+	file, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+
+	config, _, err := image.DecodeConfig(file)
+	if err != nil {
+		return false
+	}
+
+	switch model := config.ColorModel.(type) {
+	case color.Palette:
+		for _, c := range model {
+			if _, _, _, a := c.RGBA(); a != 0xffff {
+				return true
+			}
+		}
+		return false
+	}
+	switch config.ColorModel {
+	case color.RGBAModel, color.RGBA64Model, color.NRGBAModel, color.NRGBA64Model, color.AlphaModel, color.Alpha16Model:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,298 @@
+package services
+
+import (
+	"bufio"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"converzen/internal/logger"
+	"converzen/internal/models"
+	"converzen/pkg/httpclient"
+)
+
+// RemoteWorkerOptions configures sending conversions to another instance
+// running in worker mode, e.g. a desktop with a GPU doing the encoding for
+// a laptop
+type RemoteWorkerOptions struct {
+	URL   string // The worker's HTTP API, e.g. https://gpu-desktop:8765
+	Token string // The worker's API token, sent in the clear with http URLs
+
+	// SharedStorage sends paths instead of uploading inputs and downloading
+	// outputs, for files both machines see at the same path
+	SharedStorage bool
+
+	// FileTypes are the file types converted by the worker
+	FileTypes []models.FileType
+}
+
+// RemoteWorker sends conversion jobs to a worker instance
+type RemoteWorker struct {
+	options RemoteWorkerOptions
+	host    string
+	clients *httpclient.Factory
+	log     *logger.ComponentLogger
+}
+
+// errWorkerUnavailable marks failures to reach the worker and server errors
+// from it, after which the job is converted locally instead. Jobs the
+// worker rejects, e.g. for a wrong token, fail.
+var errWorkerUnavailable = errors.New("worker unavailable")
+
+// NewRemoteWorker creates a RemoteWorker for options
+func NewRemoteWorker(options RemoteWorkerOptions, clients *httpclient.Factory, log *logger.Logger) (*RemoteWorker, error) {
+	parsed, err := url.Parse(options.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid worker URL: %w", err)
+	}
+	if options.Token == "" {
+		return nil, fmt.Errorf("the remote worker requires a token")
+	}
+	worker := &RemoteWorker{
+		options: options,
+		host:    parsed.Host,
+		clients: clients,
+		log:     log.WithComponent("remote-worker"),
+	}
+	if ip := net.ParseIP(parsed.Hostname()); parsed.Scheme == "http" && parsed.Hostname() != "localhost" && (ip == nil || !ip.IsLoopback()) {
+		worker.log.Warn("Worker %s is reached over plain HTTP, sending the token and files in the clear", parsed.Host)
+	}
+	return worker, nil
+}
+
+// handles reports whether files of fileType are sent to the worker
+func (w *RemoteWorker) handles(fileType models.FileType) bool {
+	if w == nil {
+		return false
+	}
+	for _, handled := range w.options.FileTypes {
+		if handled == fileType {
+			return true
+		}
+	}
+	return false
+}
+
+// backend names the worker in conversion history
+func (w *RemoteWorker) backend() string {
+	return "worker " + w.host
+}
+
+// remoteConverter converts jobs on a remote worker, falling back to the
+// local converter when the worker can't be reached or fails internally
+type remoteConverter struct {
+	Converter
+	worker *RemoteWorker
+}
+
+// Convert sends job to the worker
+func (c *remoteConverter) Convert(ctx context.Context, job models.ConversionJob, progressCallback func(progress float64)) (*models.ConversionResult, error) {
+	if !c.worker.options.SharedStorage {
+		// Overlays are separate local files, which only shared storage reaches
+		if job.Overlay != nil {
+			return c.Converter.Convert(ctx, job, progressCallback)
+		}
+		if _, err := os.Stat(job.OutputPath); err == nil && !job.OverwriteOutput {
+			result := &models.ConversionResult{
+				InputPath:    job.InputPath,
+				OutputPath:   job.OutputPath,
+				ErrorMessage: fmt.Sprintf("Output file already exists: %s", job.OutputPath),
+			}
+			return result, fmt.Errorf("%s", result.ErrorMessage)
+		}
+	}
+
+	result, err := c.worker.convert(ctx, job, progressCallback)
+	if errors.Is(err, errWorkerUnavailable) && ctx.Err() == nil {
+		c.worker.log.Warn("Converting %s locally: %v", job.InputPath, err)
+		result, err = c.Converter.Convert(ctx, job, progressCallback)
+		if result != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("Converted locally because worker %s was unavailable", c.worker.host))
+		}
+	}
+	return result, err
+}
+
+// convert runs job on the worker
+func (w *RemoteWorker) convert(ctx context.Context, job models.ConversionJob, progressCallback func(progress float64)) (*models.ConversionResult, error) {
+	w.log.Info("Sending %s to %s", job.InputPath, w.host)
+
+	body, contentType := w.jobBody(job)
+	defer body.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.endpoint("worker/jobs"), body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errWorkerUnavailable, err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Authorization", "Bearer "+w.options.Token)
+
+	resp, err := w.clients.Client(0).Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errWorkerUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		message := strings.TrimSpace(string(body))
+
+		// A worker that is down or overloaded may work again later, but one
+		// refusing the token or the job would refuse a retry too
+		if resp.StatusCode >= 500 {
+			return nil, fmt.Errorf("%w: %s: %s", errWorkerUnavailable, resp.Status, message)
+		}
+		result := &models.ConversionResult{
+			InputPath:    job.InputPath,
+			OutputPath:   job.OutputPath,
+			ErrorMessage: fmt.Sprintf("Worker %s rejected the job: %s", w.host, cmp.Or(message, resp.Status)),
+		}
+		return result, fmt.Errorf("%s", result.ErrorMessage)
+	}
+
+	// Progress lines are followed by one with the result
+	var final models.WorkerMessage
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		var message models.WorkerMessage
+		if err := json.Unmarshal(scanner.Bytes(), &message); err != nil {
+			return nil, fmt.Errorf("invalid response from worker %s: %w", w.host, err)
+		}
+		if message.Progress != nil {
+			if progressCallback != nil {
+				progressCallback(*message.Progress)
+			}
+			continue
+		}
+		final = message
+		break
+	}
+	if final.Result == nil && final.Error == "" {
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("lost connection to worker %s: %w", w.host, err)
+		}
+		return nil, fmt.Errorf("worker %s ended the job without a result", w.host)
+	}
+
+	result := final.Result
+	if result == nil {
+		result = &models.ConversionResult{ErrorMessage: final.Error}
+	}
+	result.InputPath = job.InputPath
+	result.OutputPath = job.OutputPath
+	if final.Error != "" {
+		if result.ErrorMessage == "" {
+			result.ErrorMessage = final.Error
+		}
+		return result, fmt.Errorf("%s", result.ErrorMessage)
+	}
+
+	if final.Output != "" {
+		if err := w.download(ctx, final.Output, job.OutputPath); err != nil {
+			result.Success = false
+			result.ErrorMessage = err.Error()
+			return result, err
+		}
+	}
+	if info, err := os.Stat(job.OutputPath); err == nil {
+		result.OutputSize = info.Size()
+	}
+	return result, nil
+}
+
+// jobBody streams the multipart request for job: the job as JSON and,
+// without shared storage, the input file
+func (w *RemoteWorker) jobBody(job models.ConversionJob) (io.ReadCloser, string) {
+	// The pre-conversion script has already run here, and pipelines run
+	// step by step here too; workers refuse jobs that still carry them
+	job.PreScript = ""
+	job.Pipeline = nil
+
+	reader, writer := io.Pipe()
+	form := multipart.NewWriter(writer)
+
+	go func() {
+		err := func() error {
+			part, err := form.CreateFormField("job")
+			if err != nil {
+				return err
+			}
+			if err := json.NewEncoder(part).Encode(job); err != nil {
+				return err
+			}
+
+			if !w.options.SharedStorage {
+				input, err := os.Open(job.InputPath)
+				if err != nil {
+					return err
+				}
+				defer input.Close()
+
+				part, err := form.CreateFormFile("input", filepath.Base(job.InputPath))
+				if err != nil {
+					return err
+				}
+				if _, err := io.Copy(part, input); err != nil {
+					return err
+				}
+			}
+			return form.Close()
+		}()
+		writer.CloseWithError(err)
+	}()
+
+	return reader, form.FormDataContentType()
+}
+
+// download saves a converted upload from the worker to outputPath
+func (w *RemoteWorker) download(ctx context.Context, id, outputPath string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, w.endpoint("worker/outputs/"+url.PathEscape(id)), nil)
+	if err != nil {
+		return fmt.Errorf("failed to download output: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+w.options.Token)
+
+	resp, err := w.clients.Client(0).Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to download output: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to download output: %s", resp.Status)
+	}
+
+	// Write next to the output first, so a broken transfer doesn't leave a
+	// truncated file under the final name
+	file, err := os.CreateTemp(filepath.Dir(outputPath), ".converzen-download-*")
+	if err != nil {
+		return fmt.Errorf("failed to download output: %w", err)
+	}
+	_, err = io.Copy(file, resp.Body)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(file.Name(), outputPath)
+	}
+	if err != nil {
+		os.Remove(file.Name())
+		return fmt.Errorf("failed to download output: %w", err)
+	}
+	return nil
+}
+
+// endpoint returns the URL of a worker API path
+func (w *RemoteWorker) endpoint(path string) string {
+	return strings.TrimSuffix(w.options.URL, "/") + "/" + path
+}
@@ -0,0 +1,71 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"converzen/internal/logger"
+	"converzen/internal/models"
+	"converzen/pkg/httpclient"
+)
+
+// TestRemoteConverterDropsPreScript checks jobs whose pre-conversion script
+// already ran locally are accepted by a worker, which refuses pre-scripts
+func TestRemoteConverterDropsPreScript(t *testing.T) {
+	log, err := logger.NewWithConsole(filepath.Join(t.TempDir(), "test.log"), logger.DEBUG, nil)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	t.Cleanup(func() { log.Close() })
+
+	// The worker answers like the HTTP API's worker mode
+	var received models.ConversionJob
+	worker := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.Unmarshal([]byte(r.FormValue("job")), &received); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if len(received.Pipeline) > 0 || received.PreScript != "" {
+			http.Error(w, "pipelines and pre-conversion scripts run on the dispatching instance", http.StatusBadRequest)
+			return
+		}
+		result := &models.ConversionResult{Success: true}
+		json.NewEncoder(w).Encode(models.WorkerMessage{Result: result})
+	}))
+	defer worker.Close()
+
+	clients, err := httpclient.NewFactory(httpclient.Options{})
+	if err != nil {
+		t.Fatalf("failed to create HTTP clients: %v", err)
+	}
+	remote, err := NewRemoteWorker(RemoteWorkerOptions{
+		URL:           worker.URL,
+		Token:         "secret",
+		SharedStorage: true,
+		FileTypes:     []models.FileType{models.FileTypeVideo},
+	}, clients, log)
+	if err != nil {
+		t.Fatalf("NewRemoteWorker failed: %v", err)
+	}
+
+	converter := &remoteConverter{worker: remote}
+	result, err := converter.Convert(context.Background(), models.ConversionJob{
+		InputPath:  "/shared/clip.mov",
+		OutputPath: "/shared/clip.mp4",
+		PreScript:  "/scripts/fetch.sh",
+		Pipeline:   []models.PipelineStep{{}},
+	}, nil)
+	if err != nil {
+		t.Fatalf("Convert failed: %v", err)
+	}
+	if !result.Success {
+		t.Errorf("result = %+v, want a successful conversion", result)
+	}
+	if received.InputPath != "/shared/clip.mov" {
+		t.Errorf("worker got input %q, want the job's input", received.InputPath)
+	}
+}
@@ -0,0 +1,266 @@
+package services
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"converzen/internal/models"
+)
+
+// numericRuleFields are the rule fields compared as numbers
+var numericRuleFields = map[models.RuleField]bool{
+	models.RuleResolution: true,
+	models.RuleWidth:      true,
+	models.RuleHeight:     true,
+	models.RuleFrameRate:  true,
+	models.RuleBitrate:    true,
+	models.RuleDuration:   true,
+	models.RuleSize:       true,
+}
+
+// textRuleFields are the rule fields compared as text
+var textRuleFields = map[models.RuleField]bool{
+	models.RuleVideoCodec: true,
+	models.RuleAudioCodec: true,
+	models.RuleFormat:     true,
+	models.RuleFileType:   true,
+	models.RuleHDR:        true,
+}
+
+// validateRules checks a batch's rules before any file is converted, so
+// evaluating them can't fail halfway through
+func validateRules(rules []models.ConversionRule) error {
+	for i, rule := range rules {
+		name := rule.Name
+		if name == "" {
+			name = fmt.Sprintf("rule %d", i+1)
+		}
+		for _, condition := range rule.Conditions {
+			switch {
+			case numericRuleFields[condition.Field]:
+				if condition.Operator == models.RuleIn {
+					for _, value := range strings.Split(condition.Value, ",") {
+						if _, err := ruleNumber(condition.Field, value); err != nil {
+							return fmt.Errorf("%s: %w", name, err)
+						}
+					}
+					continue
+				}
+				switch condition.Operator {
+				case models.RuleEquals, models.RuleNotEquals, models.RuleGreater,
+					models.RuleGreaterOrEqual, models.RuleLess, models.RuleLessOrEqual:
+				default:
+					return fmt.Errorf("%s: unknown operator %q", name, condition.Operator)
+				}
+				if _, err := ruleNumber(condition.Field, condition.Value); err != nil {
+					return fmt.Errorf("%s: %w", name, err)
+				}
+			case textRuleFields[condition.Field]:
+				switch condition.Operator {
+				case models.RuleEquals, models.RuleNotEquals, models.RuleIn:
+				default:
+					return fmt.Errorf("%s: %s can't be compared with %q", name, condition.Field, condition.Operator)
+				}
+			default:
+				return fmt.Errorf("%s: unknown field %q", name, condition.Field)
+			}
+		}
+	}
+	return nil
+}
+
+// ruleNumber parses a numeric condition value. Resolutions may be written
+// like "1080p" or "4k".
+func ruleNumber(field models.RuleField, value string) (float64, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if field == models.RuleResolution {
+		switch value {
+		case "4k":
+			return 2160, nil
+		case "8k":
+			return 4320, nil
+		}
+		value = strings.TrimSuffix(value, "p")
+	}
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be a number, not %q", field, value)
+	}
+	return number, nil
+}
+
+// ruleNumericValue returns a file's numeric property, false if the file
+// doesn't have it
+func ruleNumericValue(file models.FileAnalysis, field models.RuleField) (float64, bool) {
+	var value float64
+	switch field {
+	case models.RuleResolution:
+		value = float64(min(file.Width, file.Height))
+	case models.RuleWidth:
+		value = float64(file.Width)
+	case models.RuleHeight:
+		value = float64(file.Height)
+	case models.RuleFrameRate:
+		value = file.FrameRate
+	case models.RuleBitrate:
+		value = float64(file.Bitrate) / 1000
+	case models.RuleDuration:
+		value = file.Duration
+	case models.RuleSize:
+		value = float64(file.Size) / 1_000_000
+	}
+	return value, value > 0
+}
+
+// ruleTextValue returns a file's text property, false if the file doesn't
+// have it. Every file has a format and type; an empty HDR format is SDR.
+func ruleTextValue(file models.FileAnalysis, field models.RuleField) (string, bool) {
+	switch field {
+	case models.RuleVideoCodec:
+		return file.VideoCodec, file.VideoCodec != ""
+	case models.RuleAudioCodec:
+		return file.AudioCodec, file.AudioCodec != ""
+	case models.RuleFormat:
+		return strings.TrimPrefix(strings.ToLower(filepath.Ext(file.Path)), "."), true
+	case models.RuleFileType:
+		return string(file.FileType), true
+	case models.RuleHDR:
+		return file.HDR, true
+	}
+	return "", false
+}
+
+// matchCondition reports whether a file matches a validated condition
+func matchCondition(file models.FileAnalysis, condition models.RuleCondition) bool {
+	if textRuleFields[condition.Field] {
+		actual, ok := ruleTextValue(file, condition.Field)
+		if !ok {
+			return false
+		}
+		switch condition.Operator {
+		case models.RuleEquals:
+			return strings.EqualFold(actual, strings.TrimSpace(condition.Value))
+		case models.RuleNotEquals:
+			return !strings.EqualFold(actual, strings.TrimSpace(condition.Value))
+		case models.RuleIn:
+			for _, value := range strings.Split(condition.Value, ",") {
+				if strings.EqualFold(actual, strings.TrimSpace(value)) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	actual, ok := ruleNumericValue(file, condition.Field)
+	if !ok {
+		return false
+	}
+	if condition.Operator == models.RuleIn {
+		for _, value := range strings.Split(condition.Value, ",") {
+			if number, _ := ruleNumber(condition.Field, value); actual == number {
+				return true
+			}
+		}
+		return false
+	}
+
+	value, _ := ruleNumber(condition.Field, condition.Value)
+	switch condition.Operator {
+	case models.RuleEquals:
+		return actual == value
+	case models.RuleNotEquals:
+		return actual != value
+	case models.RuleGreater:
+		return actual > value
+	case models.RuleGreaterOrEqual:
+		return actual >= value
+	case models.RuleLess:
+		return actual < value
+	case models.RuleLessOrEqual:
+		return actual <= value
+	}
+	return false
+}
+
+// evaluateRules applies validated rules to a file in order and returns the
+// combined settings, later rules overriding earlier ones
+func evaluateRules(rules []models.ConversionRule, file models.FileAnalysis) models.RuleSettings {
+	var settings models.RuleSettings
+	for _, rule := range rules {
+		matched := true
+		for _, condition := range rule.Conditions {
+			if !matchCondition(file, condition) {
+				matched = false
+				break
+			}
+		}
+
+		apply := &rule.Then
+		if !matched {
+			apply = rule.Else
+		}
+		if apply != nil {
+			mergeRuleSettings(&settings, *apply)
+		}
+	}
+	return settings
+}
+
+// mergeRuleSettings overrides settings with the non-empty fields of apply.
+// Once a rule skips a file it stays skipped.
+func mergeRuleSettings(settings *models.RuleSettings, apply models.RuleSettings) {
+	if apply.Skip {
+		settings.Skip = true
+	}
+	if apply.OutputFormat != "" {
+		settings.OutputFormat = apply.OutputFormat
+	}
+	if apply.VideoCodec != models.CodecDefault {
+		settings.VideoCodec = apply.VideoCodec
+	}
+	if apply.Deinterlace != "" {
+		settings.Deinterlace = apply.Deinterlace
+	}
+	if apply.TargetSizeMB > 0 {
+		settings.TargetSizeMB = apply.TargetSizeMB
+	}
+	if apply.MaxWidth > 0 {
+		settings.MaxWidth = apply.MaxWidth
+	}
+	if apply.MaxHeight > 0 {
+		settings.MaxHeight = apply.MaxHeight
+	}
+}
+
+// applyRuleSettings sets a rule's settings on a job for a file of the given type
+func applyRuleSettings(job *models.ConversionJob, fileType models.FileType, settings models.RuleSettings) {
+	if settings.VideoCodec != models.CodecDefault {
+		job.VideoCodec = settings.VideoCodec
+	}
+	if settings.Deinterlace != "" {
+		job.Deinterlace = settings.Deinterlace
+	}
+	if settings.TargetSizeMB > 0 {
+		job.TargetSizeMB = settings.TargetSizeMB
+	}
+
+	switch fileType {
+	case models.FileTypeVideo:
+		if settings.MaxWidth > 0 {
+			job.VideoMaxWidth = settings.MaxWidth
+		}
+		if settings.MaxHeight > 0 {
+			job.VideoMaxHeight = settings.MaxHeight
+		}
+	case models.FileTypeImage:
+		if settings.MaxWidth > 0 {
+			job.MaxWidth = settings.MaxWidth
+		}
+		if settings.MaxHeight > 0 {
+			job.MaxHeight = settings.MaxHeight
+		}
+	}
+}
@@ -0,0 +1,107 @@
+// Package servicestest provides fakes and fixtures for testing services
+// without FFmpeg, other conversion tools or a database
+package servicestest
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"converzen/internal/models"
+	"converzen/internal/services"
+)
+
+// Converter is a fake services.Converter recording the jobs it is given.
+// By default it copies the input to the output path and succeeds.
+type Converter struct {
+	// ConvertFunc, when set, replaces the default conversion
+	ConvertFunc func(ctx context.Context, job models.ConversionJob, progressCallback func(progress float64)) (*models.ConversionResult, error)
+
+	// Formats reported as supported; empty accepts any format
+	InputFormats  []string
+	OutputFormats []string
+
+	mu   sync.Mutex
+	jobs []models.ConversionJob
+}
+
+var _ services.Converter = (*Converter)(nil)
+
+// Convert records the job and runs ConvertFunc or copies the input
+func (c *Converter) Convert(ctx context.Context, job models.ConversionJob, progressCallback func(progress float64)) (*models.ConversionResult, error) {
+	c.mu.Lock()
+	c.jobs = append(c.jobs, job)
+	c.mu.Unlock()
+
+	if c.ConvertFunc != nil {
+		return c.ConvertFunc(ctx, job, progressCallback)
+	}
+	return CopyConversion(ctx, job, progressCallback)
+}
+
+// CopyConversion "converts" by copying the input to the output path,
+// failing like a converter would when the input is missing or the output
+// exists without OverwriteOutput
+func CopyConversion(ctx context.Context, job models.ConversionJob, progressCallback func(progress float64)) (*models.ConversionResult, error) {
+	startTime := time.Now()
+	result := &models.ConversionResult{InputPath: job.InputPath, OutputPath: job.OutputPath}
+
+	fail := func(err error) (*models.ConversionResult, error) {
+		result.ErrorMessage = err.Error()
+		return result, err
+	}
+	if err := ctx.Err(); err != nil {
+		return fail(err)
+	}
+
+	data, err := os.ReadFile(job.InputPath)
+	if err != nil {
+		return fail(fmt.Errorf("input file not found: %s", job.InputPath))
+	}
+	if _, err := os.Stat(job.OutputPath); err == nil && !job.OverwriteOutput {
+		return fail(fmt.Errorf("output file already exists: %s", job.OutputPath))
+	}
+	if err := os.MkdirAll(filepath.Dir(job.OutputPath), 0755); err != nil {
+		return fail(err)
+	}
+	if err := os.WriteFile(job.OutputPath, data, 0644); err != nil {
+		return fail(err)
+	}
+	if progressCallback != nil {
+		progressCallback(100)
+	}
+
+	result.Success = true
+	result.InputSize = int64(len(data))
+	result.OutputSize = int64(len(data))
+	result.Method = models.MethodReencode
+	result.Duration = time.Since(startTime).Milliseconds()
+	return result, nil
+}
+
+// Jobs returns the jobs converted so far, in order
+func (c *Converter) Jobs() []models.ConversionJob {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return slices.Clone(c.jobs)
+}
+
+// SupportedInputFormats returns InputFormats
+func (c *Converter) SupportedInputFormats() []string {
+	return c.InputFormats
+}
+
+// SupportedOutputFormats returns OutputFormats for any input format
+func (c *Converter) SupportedOutputFormats(inputFormat string) []string {
+	return c.OutputFormats
+}
+
+// CanConvert reports whether both formats are supported
+func (c *Converter) CanConvert(inputFormat, outputFormat string) bool {
+	return (len(c.InputFormats) == 0 || slices.Contains(c.InputFormats, inputFormat)) &&
+		(len(c.OutputFormats) == 0 || slices.Contains(c.OutputFormats, outputFormat))
+}
@@ -0,0 +1,98 @@
+package servicestest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"converzen/internal/logger"
+	"converzen/internal/repository/repositorytest"
+	"converzen/internal/services"
+	"converzen/pkg/ffmpeg"
+	"converzen/pkg/ffmpeg/ffmpegtest"
+)
+
+// Fixture is a ConversionService wired to fake converters and in-memory
+// repositories, with a temporary directory for inputs and outputs
+type Fixture struct {
+	Dir string
+	Log *logger.Logger
+
+	Conversions *repositorytest.ConversionRepository
+	Settings    *repositorytest.SettingsRepository
+
+	Video    *Converter
+	Image    *Converter
+	Audio    *Converter
+	Subtitle *Converter
+	Ebook    *Converter
+	Document *Converter
+
+	FileService     services.FileService
+	SettingsService services.SettingsService
+	Service         services.ConversionService
+}
+
+// NewFixture creates a Fixture whose temporary files are removed when the
+// test ends
+func NewFixture(tb testing.TB) *Fixture {
+	tb.Helper()
+
+	dir := tb.TempDir()
+	log, err := logger.NewWithConsole(filepath.Join(dir, "logs", "test.log"), logger.DEBUG, nil)
+	if err != nil {
+		tb.Fatalf("failed to create logger: %v", err)
+	}
+	tb.Cleanup(func() { log.Close() })
+
+	f := &Fixture{
+		Dir:         dir,
+		Log:         log,
+		Conversions: repositorytest.NewConversionRepository(),
+		Settings:    repositorytest.NewSettingsRepository(nil),
+		Video:       &Converter{},
+		Image:       &Converter{},
+		Audio:       &Converter{},
+		Subtitle:    &Converter{},
+		Ebook:       &Converter{},
+		Document:    &Converter{},
+	}
+	f.FileService = services.NewFileService(log)
+	f.SettingsService = services.NewSettingsService(f.Settings, log)
+	f.Service = services.NewConversionService(
+		f.FileService,
+		f.Video,
+		f.Image,
+		f.Audio,
+		f.Subtitle,
+		f.Ebook,
+		f.Document,
+		f.Conversions,
+		f.SettingsService,
+		services.NewAnalysisService(f.FileService, nil, log),
+		filepath.Join(dir, "logs", "jobs"),
+		log,
+	)
+	return f
+}
+
+// WriteFile creates a file in the fixture's directory and returns its path
+func (f *Fixture) WriteFile(tb testing.TB, name, content string) string {
+	tb.Helper()
+
+	path := filepath.Join(f.Dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		tb.Fatalf("failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		tb.Fatalf("failed to write %s: %v", name, err)
+	}
+	return path
+}
+
+// FFmpeg returns an FFmpeg instance running its commands through runner,
+// for testing the FFmpeg-backed converters without FFmpeg installed. The
+// test binary must call ffmpegtest.HelperMain in TestMain.
+func (f *Fixture) FFmpeg(runner *ffmpegtest.Runner) *ffmpeg.FFmpeg {
+	return ffmpeg.NewWithRunner("ffmpeg", runner, f.Log)
+}
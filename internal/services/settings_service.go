@@ -1,6 +1,8 @@
 package services
 
 import (
+	"encoding/json"
+	"fmt"
 	"strconv"
 
 	"converzen/internal/logger"
@@ -48,6 +50,113 @@ func (s *settingsServiceImpl) GetSettings() (*models.UserSettings, error) {
 		settings.Theme = setting.Value
 	}
 
+	// Get stalled conversion handling
+	if setting, err := s.repo.Get(models.SettingStallTimeout); err == nil && setting != nil {
+		if minutes, err := strconv.Atoi(setting.Value); err == nil {
+			settings.StallTimeoutMinutes = minutes
+		}
+	}
+	if setting, err := s.repo.Get(models.SettingStallAutoRetry); err == nil && setting != nil {
+		settings.StallAutoRetry = setting.Value == "true"
+	}
+	if setting, err := s.repo.Get(models.SettingStallMaxRetries); err == nil && setting != nil {
+		if retries, err := strconv.Atoi(setting.Value); err == nil {
+			settings.StallMaxRetries = retries
+		}
+	}
+
+	// Get image scaler
+	if setting, err := s.repo.Get(models.SettingImageScaler); err == nil && setting != nil {
+		settings.ImageScaler = models.ImageScaler(setting.Value)
+	}
+
+	// Get resource limits
+	if setting, err := s.repo.Get(models.SettingFFmpegThreads); err == nil && setting != nil {
+		if threads, err := strconv.Atoi(setting.Value); err == nil {
+			settings.FFmpegThreads = threads
+		}
+	}
+	if setting, err := s.repo.Get(models.SettingLowPriority); err == nil && setting != nil {
+		settings.LowPriority = setting.Value == "true"
+	}
+	if setting, err := s.repo.Get(models.SettingLowUnfocused); err == nil && setting != nil {
+		settings.LowWhenUnfocused = setting.Value == "true"
+	}
+
+	// Get background mode
+	if setting, err := s.repo.Get(models.SettingBackgroundMode); err == nil && setting != nil {
+		settings.BackgroundMode = models.BackgroundMode(setting.Value)
+	}
+	if setting, err := s.repo.Get(models.SettingThermalMode); err == nil && setting != nil {
+		settings.ThermalMode = models.BackgroundMode(setting.Value)
+	}
+
+	// Get advanced codec options
+	if setting, err := s.repo.Get(models.SettingMovVideoCodec); err == nil && setting != nil {
+		settings.MovVideoCodec = models.VideoCodec(setting.Value)
+	}
+	if setting, err := s.repo.Get(models.SettingProResProfile); err == nil && setting != nil {
+		settings.ProResProfile = models.ProResProfile(setting.Value)
+	}
+	if setting, err := s.repo.Get(models.SettingDNxHRProfile); err == nil && setting != nil {
+		settings.DNxHRProfile = models.DNxHRProfile(setting.Value)
+	}
+
+	// Get scratch directory
+	if setting, err := s.repo.Get(models.SettingTempDirectory); err == nil && setting != nil {
+		settings.TempDirectory = setting.Value
+	}
+
+	// Get update checks
+	if setting, err := s.repo.Get(models.SettingCheckForUpdates); err == nil && setting != nil {
+		settings.CheckForUpdates = setting.Value == "true"
+	}
+
+	// Get network settings
+	if setting, err := s.repo.Get(models.SettingProxyURL); err == nil && setting != nil {
+		settings.ProxyURL = setting.Value
+	}
+	if setting, err := s.repo.Get(models.SettingCAFile); err == nil && setting != nil {
+		settings.CAFile = setting.Value
+	}
+
+	// Get per-format output directories
+	if setting, err := s.repo.Get(models.SettingOutputRoutes); err == nil && setting != nil {
+		if err := json.Unmarshal([]byte(setting.Value), &settings.OutputRoutes); err != nil {
+			s.log.Warn("Ignoring invalid output routes setting: %v", err)
+		}
+	}
+
+	// Get clipboard watching
+	if setting, err := s.repo.Get(models.SettingWatchClipboard); err == nil && setting != nil {
+		settings.WatchClipboard = setting.Value == "true"
+	}
+
+	// Get converter backend
+	if setting, err := s.repo.Get(models.SettingConverterBackend); err == nil && setting != nil {
+		settings.ConverterBackend = models.ConverterBackend(setting.Value)
+	}
+
+	// Get message language
+	if setting, err := s.repo.Get(models.SettingLanguage); err == nil && setting != nil {
+		settings.Language = models.Language(setting.Value)
+	}
+
+	// Get batch notifications
+	if setting, err := s.repo.Get(models.SettingNotifications); err == nil && setting != nil {
+		settings.Notifications = setting.Value == "true"
+	}
+	if setting, err := s.repo.Get(models.SettingSummaryThreshold); err == nil && setting != nil {
+		if threshold, err := strconv.Atoi(setting.Value); err == nil {
+			settings.SummaryThreshold = threshold
+		}
+	}
+	if setting, err := s.repo.Get(models.SettingSummaryInterval); err == nil && setting != nil {
+		if seconds, err := strconv.Atoi(setting.Value); err == nil {
+			settings.SummaryIntervalSeconds = seconds
+		}
+	}
+
 	return &settings, nil
 }
 
@@ -71,6 +180,102 @@ func (s *settingsServiceImpl) SaveSettings(settings models.UserSettings) error {
 		return err
 	}
 
+	if err := s.repo.Set(models.SettingStallTimeout, strconv.Itoa(settings.StallTimeoutMinutes)); err != nil {
+		return err
+	}
+
+	if err := s.repo.Set(models.SettingStallAutoRetry, strconv.FormatBool(settings.StallAutoRetry)); err != nil {
+		return err
+	}
+
+	if err := s.repo.Set(models.SettingStallMaxRetries, strconv.Itoa(settings.StallMaxRetries)); err != nil {
+		return err
+	}
+
+	if err := s.repo.Set(models.SettingImageScaler, string(settings.ImageScaler)); err != nil {
+		return err
+	}
+
+	if err := s.repo.Set(models.SettingFFmpegThreads, strconv.Itoa(settings.FFmpegThreads)); err != nil {
+		return err
+	}
+
+	if err := s.repo.Set(models.SettingLowPriority, strconv.FormatBool(settings.LowPriority)); err != nil {
+		return err
+	}
+
+	if err := s.repo.Set(models.SettingLowUnfocused, strconv.FormatBool(settings.LowWhenUnfocused)); err != nil {
+		return err
+	}
+
+	if err := s.repo.Set(models.SettingBackgroundMode, string(settings.BackgroundMode)); err != nil {
+		return err
+	}
+
+	if err := s.repo.Set(models.SettingThermalMode, string(settings.ThermalMode)); err != nil {
+		return err
+	}
+
+	if err := s.repo.Set(models.SettingMovVideoCodec, string(settings.MovVideoCodec)); err != nil {
+		return err
+	}
+
+	if err := s.repo.Set(models.SettingProResProfile, string(settings.ProResProfile)); err != nil {
+		return err
+	}
+
+	if err := s.repo.Set(models.SettingDNxHRProfile, string(settings.DNxHRProfile)); err != nil {
+		return err
+	}
+
+	if err := s.repo.Set(models.SettingTempDirectory, settings.TempDirectory); err != nil {
+		return err
+	}
+
+	if err := s.repo.Set(models.SettingCheckForUpdates, strconv.FormatBool(settings.CheckForUpdates)); err != nil {
+		return err
+	}
+
+	if err := s.repo.Set(models.SettingProxyURL, settings.ProxyURL); err != nil {
+		return err
+	}
+
+	if err := s.repo.Set(models.SettingCAFile, settings.CAFile); err != nil {
+		return err
+	}
+
+	routes, err := json.Marshal(settings.OutputRoutes)
+	if err != nil {
+		return fmt.Errorf("failed to encode output routes: %w", err)
+	}
+	if err := s.repo.Set(models.SettingOutputRoutes, string(routes)); err != nil {
+		return err
+	}
+
+	if err := s.repo.Set(models.SettingWatchClipboard, strconv.FormatBool(settings.WatchClipboard)); err != nil {
+		return err
+	}
+
+	if err := s.repo.Set(models.SettingConverterBackend, string(settings.ConverterBackend)); err != nil {
+		return err
+	}
+
+	if err := s.repo.Set(models.SettingLanguage, string(settings.Language)); err != nil {
+		return err
+	}
+
+	if err := s.repo.Set(models.SettingNotifications, strconv.FormatBool(settings.Notifications)); err != nil {
+		return err
+	}
+
+	if err := s.repo.Set(models.SettingSummaryThreshold, strconv.Itoa(settings.SummaryThreshold)); err != nil {
+		return err
+	}
+
+	if err := s.repo.Set(models.SettingSummaryInterval, strconv.Itoa(settings.SummaryIntervalSeconds)); err != nil {
+		return err
+	}
+
 	s.log.Info("User settings saved successfully")
 	return nil
 }
@@ -91,3 +296,14 @@ func (s *settingsServiceImpl) GetSetting(key string) (string, error) {
 func (s *settingsServiceImpl) SetSetting(key, value string) error {
 	return s.repo.Set(key, value)
 }
+
+// RecordPresetChange adds a preset change to the settings audit trail, keyed
+// by the preset's name
+func (s *settingsServiceImpl) RecordPresetChange(name, oldValue, newValue string) error {
+	return s.repo.RecordChange(models.PresetChangePrefix+name, oldValue, newValue)
+}
+
+// GetSettingChanges returns the most recent settings audit trail entries
+func (s *settingsServiceImpl) GetSettingChanges(limit int) ([]models.SettingChange, error) {
+	return s.repo.GetChanges(limit)
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"converzen/internal/models"
+	"converzen/pkg/ffmpeg"
+	"converzen/pkg/scratch"
+)
+
+// estimateSampleLength is how much of the input a size estimate encodes
+const estimateSampleLength = 5 * time.Second
+
+// estimateSizeBySample predicts a job's output size by converting a short
+// slice of the input with preview and scaling its size to the full duration.
+// Remuxes and re-encodes are both measured, so the estimate reflects
+// whichever the full conversion would do.
+func estimateSizeBySample(
+	ctx context.Context,
+	ff *ffmpeg.FFmpeg,
+	job models.ConversionJob,
+	preview func(ctx context.Context, job models.ConversionJob, length time.Duration, progressCallback func(progress float64)) (*models.ConversionResult, error),
+) (*models.SizeEstimate, error) {
+	stat, err := os.Stat(job.InputPath)
+	if err != nil {
+		return nil, fmt.Errorf("input file not found: %s", job.InputPath)
+	}
+
+	seconds, err := ff.GetDuration(job.InputPath)
+	if err != nil || seconds <= 0 {
+		return nil, fmt.Errorf("could not determine the duration of %s", filepath.Base(job.InputPath))
+	}
+	duration := time.Duration(seconds * float64(time.Second))
+	sample := min(duration, estimateSampleLength)
+
+	format := strings.TrimPrefix(strings.ToLower(filepath.Ext(job.OutputPath)), ".")
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(job.OutputFormat), ".")
+	}
+	output, err := scratch.CreateTemp("estimate-*." + format)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create sample file: %w", err)
+	}
+	output.Close()
+	defer os.Remove(output.Name())
+
+	job.OutputPath = output.Name()
+	job.OverwriteOutput = true
+
+	result, err := preview(ctx, job, sample, nil)
+	if err != nil {
+		return nil, fmt.Errorf("sample encode failed: %w", err)
+	}
+
+	return &models.SizeEstimate{
+		InputPath:     job.InputPath,
+		InputSize:     stat.Size(),
+		EstimatedSize: int64(float64(result.OutputSize) * duration.Seconds() / sample.Seconds()),
+		SampleSeconds: sample.Seconds(),
+		Method:        result.Method,
+	}, nil
+}
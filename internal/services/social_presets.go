@@ -0,0 +1,90 @@
+package services
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"converzen/internal/models"
+	"converzen/pkg/ffmpeg"
+)
+
+// socialEncoder returns the encoder settings of a social preset: H.264 High
+// with 8-bit 4:2:0 video and AAC audio, which every platform accepts
+func socialEncoder(job models.ConversionJob, outputFormat string) (encoderSettings, error) {
+	preset, ok := models.GetSocialPreset(job.SocialPreset)
+	if !ok {
+		return encoderSettings{}, fmt.Errorf("unknown social preset: %s", job.SocialPreset)
+	}
+	if outputFormat != "mp4" {
+		return encoderSettings{}, fmt.Errorf("the %s preset requires mp4 output, not %s", preset.Name, outputFormat)
+	}
+	if job.VideoCodec != models.CodecDefault || job.PreserveAlpha {
+		return encoderSettings{}, fmt.Errorf("the %s preset can't be combined with a professional codec or alpha channel", preset.Name)
+	}
+	return encoderSettings{
+		videoCodec:   "libx264",
+		audioCodec:   "aac",
+		videoProfile: "high",
+		pixelFormat:  "yuv420p",
+	}, nil
+}
+
+// socialFilters returns the filters fitting video into a social preset's
+// frame, padding it to the exact size, and capping its frame rate. The probe
+// may be nil if the input couldn't be probed.
+func socialFilters(preset models.SocialPreset, probe *ffmpeg.Probe) []string {
+	filters := []string{
+		fmt.Sprintf("scale=%d:%d:force_original_aspect_ratio=decrease", preset.Width, preset.Height),
+		fmt.Sprintf("pad=%d:%d:(ow-iw)/2:(oh-ih)/2", preset.Width, preset.Height),
+		"setsar=1",
+	}
+	if probe != nil && probe.FrameRate > float64(preset.MaxFrameRate) {
+		filters = append(filters, "fps="+strconv.Itoa(preset.MaxFrameRate))
+	}
+	return filters
+}
+
+// applySocialPreset sets a social preset's bitrates on opts, lowering them
+// when the output would exceed the platform's upload size. It returns
+// warnings about limits the output still breaks. The probe may be nil if the
+// input couldn't be probed.
+func applySocialPreset(job models.ConversionJob, probe *ffmpeg.Probe, opts *ffmpeg.ConvertOptions) ([]string, error) {
+	preset, ok := models.GetSocialPreset(job.SocialPreset)
+	if !ok {
+		return nil, fmt.Errorf("unknown social preset: %s", job.SocialPreset)
+	}
+
+	opts.VideoBitrate = strconv.FormatInt(preset.VideoBitrate, 10)
+	opts.AudioBitrate = strconv.FormatInt(preset.AudioBitrate, 10)
+	opts.SampleRate = 48000
+	if probe == nil || job.PreviewLength > 0 {
+		return nil, nil
+	}
+
+	duration := probe.Duration.Seconds()
+	if opts.TimeScale > 0 {
+		duration *= opts.TimeScale
+	}
+
+	var warnings []string
+	if preset.MaxDuration > 0 && duration > float64(preset.MaxDuration) {
+		warnings = append(warnings, fmt.Sprintf("The video is %s long, %s accepts up to %s",
+			time.Duration(duration*float64(time.Second)).Round(time.Second),
+			preset.Name,
+			time.Duration(preset.MaxDuration)*time.Second))
+	}
+
+	// A target size of the job's own takes precedence over the platform's limit
+	if preset.MaxSizeMB > 0 && job.TargetSizeMB == 0 {
+		expectedMB := float64(preset.VideoBitrate+preset.AudioBitrate) * duration / 8 / 1_000_000
+		if expectedMB > preset.MaxSizeMB {
+			limited := job
+			limited.TargetSizeMB = preset.MaxSizeMB
+			if err := applyTargetSize(limited, probe, opts); err != nil {
+				warnings = append(warnings, fmt.Sprintf("The output will exceed %s's %.0f MB limit", preset.Name, preset.MaxSizeMB))
+			}
+		}
+	}
+	return warnings, nil
+}
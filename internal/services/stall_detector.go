@@ -0,0 +1,106 @@
+package services
+
+import (
+	"sync"
+	"time"
+
+	"converzen/internal/models"
+)
+
+// stallCheckInterval is how often running jobs are checked for stalled progress
+const stallCheckInterval = 10 * time.Second
+
+// stallOptions controls how stalled conversions are detected and handled
+type stallOptions struct {
+	Timeout    time.Duration
+	AutoRetry  bool
+	MaxRetries int
+}
+
+// stallOptionsFromSettings builds stallOptions from the user's settings
+func stallOptionsFromSettings(settings *models.UserSettings) stallOptions {
+	defaults := models.DefaultUserSettings()
+
+	minutes := settings.StallTimeoutMinutes
+	if minutes <= 0 {
+		minutes = defaults.StallTimeoutMinutes
+	}
+
+	retries := settings.StallMaxRetries
+	if retries < 0 {
+		retries = 0
+	}
+
+	return stallOptions{
+		Timeout:    time.Duration(minutes) * time.Minute,
+		AutoRetry:  settings.StallAutoRetry,
+		MaxRetries: retries,
+	}
+}
+
+// progressTracker records when a job last made forward progress.
+// A job whose progress value keeps being reported but never increases
+// counts as stalled, which distinguishes it from a slow job.
+type progressTracker struct {
+	mu           sync.Mutex
+	progress     float64
+	lastProgress time.Time
+	reported     bool
+}
+
+// newProgressTracker creates a tracker that starts counting from now
+func newProgressTracker() *progressTracker {
+	return &progressTracker{lastProgress: time.Now()}
+}
+
+// Update records a progress report and returns true if the job moved forward
+func (t *progressTracker) Update(progress float64) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if progress <= t.progress {
+		return false
+	}
+
+	t.progress = progress
+	t.lastProgress = time.Now()
+	t.reported = false
+	return true
+}
+
+// CheckStalled returns true the first time the job has gone longer than
+// timeout without moving. It returns false again until progress resumes.
+func (t *progressTracker) CheckStalled(timeout time.Duration) (bool, float64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.reported || time.Since(t.lastProgress) < timeout {
+		return false, t.progress
+	}
+
+	t.reported = true
+	return true, t.progress
+}
+
+// watchStall checks the tracker until done is closed and calls onStall each
+// time the job is found to be stalled
+func watchStall(tracker *progressTracker, timeout time.Duration, done <-chan struct{}, onStall func(progress float64)) {
+	interval := stallCheckInterval
+	if timeout < interval {
+		interval = timeout
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			if stalled, progress := tracker.CheckStalled(timeout); stalled {
+				onStall(progress)
+			}
+		}
+	}
+}
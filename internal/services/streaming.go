@@ -0,0 +1,322 @@
+package services
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"converzen/internal/logger"
+	"converzen/internal/models"
+	"converzen/pkg/ffmpeg"
+)
+
+const (
+	// defaultSegmentSeconds is the segment length used when none is given,
+	// a common choice for VOD that lets players switch quality quickly
+	defaultSegmentSeconds = 6
+
+	// maxSegmentSeconds caps segment length; longer segments make players
+	// slow to start and to adapt
+	maxSegmentSeconds = 60
+)
+
+// streamingLadder lists the default renditions with their video bitrates
+// in kbit/s, from the highest
+var streamingLadder = []models.Rendition{
+	{Height: 2160, VideoBitrate: 14000},
+	{Height: 1440, VideoBitrate: 9000},
+	{Height: 1080, VideoBitrate: 5000},
+	{Height: 720, VideoBitrate: 2800},
+	{Height: 480, VideoBitrate: 1400},
+	{Height: 360, VideoBitrate: 800},
+	{Height: 240, VideoBitrate: 400},
+}
+
+// defaultLadderSize is the number of default renditions, from the
+// input's height down
+const defaultLadderSize = 4
+
+// ladderBitrate returns the ladder's video bitrate for a height, that of
+// the nearest rung at or below it
+func ladderBitrate(height int) int {
+	for _, rung := range streamingLadder {
+		if height >= rung.Height {
+			return rung.VideoBitrate * height / rung.Height
+		}
+	}
+	last := streamingLadder[len(streamingLadder)-1]
+	return max(last.VideoBitrate*height/last.Height, 100)
+}
+
+// streamingRenditions resolves a request's renditions for an input of the
+// given height, dropping ones that would upscale and filling in bitrates.
+// Without requested renditions it picks rungs of the default ladder.
+func streamingRenditions(requested []models.Rendition, inputHeight int) ([]ffmpeg.Rendition, []string) {
+	var warnings []string
+	if len(requested) == 0 {
+		for _, rung := range streamingLadder {
+			if (inputHeight == 0 || rung.Height <= inputHeight) && len(requested) < defaultLadderSize {
+				requested = append(requested, models.Rendition{Height: rung.Height})
+			}
+		}
+	}
+
+	var renditions []ffmpeg.Rendition
+	seen := make(map[int]bool)
+	for _, rendition := range requested {
+		height := rendition.Height
+		if inputHeight > 0 && height > inputHeight {
+			warnings = append(warnings, fmt.Sprintf("Skipped the %dp rendition, as the video is only %dp", height, inputHeight))
+			continue
+		}
+		if height == 0 {
+			height = inputHeight
+		}
+		if seen[height] {
+			continue
+		}
+		seen[height] = true
+
+		bitrate := rendition.VideoBitrate
+		if bitrate <= 0 {
+			bitrate = ladderBitrate(cmp.Or(height, 1080))
+		}
+		renditions = append(renditions, ffmpeg.Rendition{
+			Height:       height,
+			VideoBitrate: bitrate,
+			AudioBitrate: rendition.AudioBitrate,
+		})
+	}
+
+	// An input smaller than every rendition is packaged at its own size
+	if len(renditions) == 0 {
+		renditions = []ffmpeg.Rendition{{VideoBitrate: ladderBitrate(cmp.Or(inputHeight, 1080))}}
+	}
+
+	slices.SortFunc(renditions, func(a, b ffmpeg.Rendition) int { return b.Height - a.Height })
+	return renditions, warnings
+}
+
+// PackageForStreaming encodes a video into renditions cut into segments,
+// with an HLS master playlist and/or DASH manifest, in a "<name>_stream"
+// folder. The packaging runs as a single cancellable job recorded in history.
+func (s *conversionServiceImpl) PackageForStreaming(request models.StreamingRequest, progressCallback func(progress models.ConversionProgress)) (*models.ConversionResult, error) {
+	if request.SegmentSeconds == 0 {
+		request.SegmentSeconds = defaultSegmentSeconds
+	}
+	if request.SegmentSeconds < 1 || request.SegmentSeconds > maxSegmentSeconds {
+		return nil, fmt.Errorf("segment length must be between 1 and %d seconds", maxSegmentSeconds)
+	}
+	if len(request.Formats) == 0 {
+		request.Formats = []models.StreamingFormat{models.StreamingHLS}
+	}
+	for _, format := range request.Formats {
+		if format != models.StreamingHLS && format != models.StreamingDASH {
+			return nil, fmt.Errorf("unknown streaming format %q", format)
+		}
+	}
+
+	packager, ok := s.video().(StreamPackager)
+	if !ok {
+		return nil, fmt.Errorf("packaging videos for streaming requires FFmpeg")
+	}
+
+	fileInfo, err := s.fileService.GetFileInfo(request.InputPath)
+	if err != nil {
+		return nil, err
+	}
+	if fileInfo.Type != models.FileTypeVideo {
+		return nil, fmt.Errorf("only video files can be packaged for streaming")
+	}
+
+	outputDir := request.OutputDirectory
+	if outputDir == "" {
+		outputDir = filepath.Dir(request.InputPath)
+	}
+	if err := s.fileService.CheckOutputDirectory(outputDir); err != nil {
+		return nil, err
+	}
+	name := strings.TrimSuffix(filepath.Base(request.InputPath), filepath.Ext(request.InputPath))
+	packageDir := filepath.Join(outputDir, name+"_stream")
+
+	// Manifests name the files of a single package, so an existing one is
+	// replaced as a whole or not at all
+	if entries, err := os.ReadDir(packageDir); err == nil && len(entries) > 0 {
+		if !request.OverwriteOutput {
+			return nil, fmt.Errorf("output folder already exists: %s", packageDir)
+		}
+		if err := os.RemoveAll(packageDir); err != nil {
+			return nil, fmt.Errorf("failed to replace output folder: %w", err)
+		}
+	}
+
+	outputFormat := "m3u8"
+	if !slices.Contains(request.Formats, models.StreamingHLS) {
+		outputFormat = "mpd"
+	}
+	job := models.ConversionJob{
+		InputPath:       request.InputPath,
+		OutputPath:      packageDir,
+		OutputFormat:    outputFormat,
+		OverwriteOutput: request.OverwriteOutput,
+	}
+	applyJobSettings(&job, s.userSettings())
+
+	if s.throttle.Acquire() {
+		job.LowPriority = true
+	}
+	defer s.throttle.Release()
+
+	now := time.Now()
+	conversion := newConversionRecord(job, fileInfo)
+	conversion.Backend = converterBackend(s.video(), job)
+	conversion.Status = models.StatusProcessing
+	conversion.StartedAt = &now
+	if err := s.repo.Create(conversion); err != nil {
+		s.log.Error("Failed to create conversion record: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s.mu.Lock()
+	s.activeConversions[conversion.ID] = cancel
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.activeConversions, conversion.ID)
+		s.mu.Unlock()
+	}()
+
+	if jobLog := s.openJobLog(conversion.ID); jobLog != nil {
+		defer jobLog.Close()
+		jobLog.Printf("Packaging %s for streaming with %s", job.InputPath, conversion.Backend)
+		job.Log = jobLog
+	}
+
+	result, err := packager.PackageStreaming(ctx, job, request, func(progress float64) {
+		conversion.Progress = progress
+		s.repo.Update(conversion)
+
+		if progressCallback != nil {
+			progressCallback(models.ConversionProgress{
+				ID:        conversion.ID,
+				InputPath: job.InputPath,
+				Progress:  progress,
+				Status:    string(models.StatusProcessing),
+			})
+		}
+	})
+
+	completedAt := time.Now()
+	conversion.CompletedAt = &completedAt
+	if err != nil {
+		err = explainFailure(err, s.userSettings().Language)
+		conversion.Status = models.StatusFailed
+		if ctx.Err() != nil {
+			conversion.Status = models.StatusCancelled
+		}
+		conversion.ErrorMessage = err.Error()
+		if updateErr := s.repo.Update(conversion); updateErr != nil {
+			s.log.Error("Failed to update conversion record: %v", updateErr)
+		}
+		return &models.ConversionResult{
+			InputPath:    job.InputPath,
+			OutputPath:   packageDir,
+			ErrorMessage: err.Error(),
+		}, nil
+	}
+
+	conversion.Status = models.StatusCompleted
+	conversion.Progress = 100
+	conversion.OutputPath = result.OutputPath
+	conversion.OutputSize = result.OutputSize
+	if err := s.repo.Update(conversion); err != nil {
+		s.log.Error("Failed to update conversion record: %v", err)
+	}
+
+	s.log.Info("Packaged %s for streaming in %dms: %s", job.InputPath, result.Duration, result.OutputPath)
+	return result, nil
+}
+
+// runFFmpegPackage packages a video for adaptive streaming into the
+// directory job.OutputPath. Shared by all FFmpeg-backed converters.
+func runFFmpegPackage(
+	ctx context.Context,
+	ff *ffmpeg.FFmpeg,
+	log *logger.ComponentLogger,
+	job models.ConversionJob,
+	request models.StreamingRequest,
+	progressCallback func(progress float64),
+) (*models.ConversionResult, error) {
+	startTime := time.Now()
+
+	if _, err := os.Stat(job.InputPath); os.IsNotExist(err) {
+		return nil, fmt.Errorf("input file not found: %s", job.InputPath)
+	}
+	if err := os.MkdirAll(job.OutputPath, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	probe, err := ff.ProbeFile(job.InputPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the video: %w", err)
+	}
+	renditions, warnings := streamingRenditions(request.Renditions, probe.Height)
+	for _, warning := range warnings {
+		log.Warn("%s", warning)
+	}
+
+	manifests, err := ff.PackageStreaming(ctx, ffmpeg.PackageOptions{
+		InputPath:     job.InputPath,
+		OutputDir:     job.OutputPath,
+		Overwrite:     job.OverwriteOutput,
+		HLS:           slices.Contains(request.Formats, models.StreamingHLS),
+		DASH:          slices.Contains(request.Formats, models.StreamingDASH),
+		SegmentLength: time.Duration(request.SegmentSeconds) * time.Second,
+		Renditions:    renditions,
+		Audio:         probe.AudioCodec != "",
+		Threads:       job.Threads,
+		LowPriority:   job.LowPriority,
+		ReadLimit:     job.IOLimit(),
+		Log:           job.Log,
+	}, progressCallback)
+	if err != nil {
+		log.Error("Packaging failed: %v", err)
+		return nil, err
+	}
+
+	result := &models.ConversionResult{
+		Success:    true,
+		InputPath:  job.InputPath,
+		OutputPath: manifests[0],
+		OutputSize: directorySize(job.OutputPath),
+		Duration:   time.Since(startTime).Milliseconds(),
+		Method:     models.MethodReencode,
+		Warnings:   warnings,
+	}
+	if stat, err := os.Stat(job.InputPath); err == nil {
+		result.InputSize = stat.Size()
+	}
+	return result, nil
+}
+
+// directorySize returns the total size of the files in dir
+func directorySize(dir string) int64 {
+	var size int64
+	filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err == nil && !entry.IsDir() {
+			if info, err := entry.Info(); err == nil {
+				size += info.Size()
+			}
+		}
+		return nil
+	})
+	return size
+}
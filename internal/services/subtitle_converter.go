@@ -0,0 +1,155 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"converzen/internal/logger"
+	"converzen/internal/models"
+	"converzen/pkg/subtitle"
+)
+
+// subtitleConverter converts standalone subtitle files between formats,
+// re-encoding legacy charsets and UTF-16 to UTF-8 on the way
+type subtitleConverter struct {
+	log *logger.ComponentLogger
+}
+
+// NewSubtitleConverter creates a new subtitle converter
+func NewSubtitleConverter(log *logger.Logger) Converter {
+	return &subtitleConverter{
+		log: log.WithComponent("subtitle-converter"),
+	}
+}
+
+// Convert converts a subtitle file to another format
+func (c *subtitleConverter) Convert(ctx context.Context, job models.ConversionJob, progressCallback func(progress float64)) (*models.ConversionResult, error) {
+	c.log.Info("Starting subtitle conversion: %s -> %s", job.InputPath, job.OutputPath)
+	startTime := time.Now()
+
+	result := &models.ConversionResult{
+		InputPath:  job.InputPath,
+		OutputPath: job.OutputPath,
+	}
+
+	data, err := os.ReadFile(job.InputPath)
+	if err != nil {
+		result.ErrorMessage = fmt.Sprintf("Failed to read input file: %v", err)
+		c.log.Error("%s", result.ErrorMessage)
+		return result, fmt.Errorf("%s", result.ErrorMessage)
+	}
+
+	if progressCallback != nil {
+		progressCallback(20)
+	}
+
+	text, charset, err := subtitle.DecodeText(data, job.SubtitleCharset)
+	if err != nil {
+		result.ErrorMessage = err.Error()
+		c.log.Error("%s", result.ErrorMessage)
+		return result, err
+	}
+	if charset != "utf-8" {
+		c.log.Info("Re-encoding %s subtitles to UTF-8: %s", charset, job.InputPath)
+	}
+
+	inputFormat := strings.TrimPrefix(strings.ToLower(filepath.Ext(job.InputPath)), ".")
+	cues, err := subtitle.Parse(text, inputFormat, 0)
+	if err != nil {
+		result.ErrorMessage = fmt.Sprintf("Failed to parse subtitles: %v", err)
+		c.log.Error("%s", result.ErrorMessage)
+		return result, fmt.Errorf("%s", result.ErrorMessage)
+	}
+
+	if progressCallback != nil {
+		progressCallback(60)
+	}
+
+	if err := ctx.Err(); err != nil {
+		result.ErrorMessage = "Conversion was cancelled"
+		c.log.Warn("%s: %s", result.ErrorMessage, job.InputPath)
+		return result, err
+	}
+
+	outputFormat := strings.TrimPrefix(strings.ToLower(filepath.Ext(job.OutputPath)), ".")
+	output, err := subtitle.Format(cues, outputFormat, 0)
+	if err != nil {
+		result.ErrorMessage = err.Error()
+		c.log.Error("%s", result.ErrorMessage)
+		return result, err
+	}
+
+	// Check output directory exists
+	outputDir := filepath.Dir(job.OutputPath)
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		result.ErrorMessage = fmt.Sprintf("Failed to create output directory: %v", err)
+		c.log.Error("%s", result.ErrorMessage)
+		return result, fmt.Errorf("%s", result.ErrorMessage)
+	}
+
+	// Check if output file already exists
+	if !job.OverwriteOutput {
+		if _, err := os.Stat(job.OutputPath); err == nil {
+			result.ErrorMessage = fmt.Sprintf("Output file already exists: %s", job.OutputPath)
+			c.log.Error("%s", result.ErrorMessage)
+			return result, fmt.Errorf("%s", result.ErrorMessage)
+		}
+	}
+
+	if err := os.WriteFile(job.OutputPath, []byte(output), 0644); err != nil {
+		result.ErrorMessage = fmt.Sprintf("Failed to write output file: %v", err)
+		c.log.Error("%s", result.ErrorMessage)
+		return result, fmt.Errorf("%s", result.ErrorMessage)
+	}
+
+	if progressCallback != nil {
+		progressCallback(100)
+	}
+
+	result.Success = true
+	result.OutputSize = int64(len(output))
+	result.Duration = time.Since(startTime).Milliseconds()
+
+	c.log.Info("Subtitle conversion completed in %dms (%d cues): %s", result.Duration, len(cues), job.OutputPath)
+	return result, nil
+}
+
+// SupportedInputFormats returns the list of supported input subtitle formats
+func (c *subtitleConverter) SupportedInputFormats() []string {
+	formats := make([]string, 0, len(models.SubtitleFormats))
+	for format := range models.SubtitleFormats {
+		formats = append(formats, strings.TrimPrefix(format, "."))
+	}
+	return formats
+}
+
+// SupportedOutputFormats returns the list of supported output formats for subtitles
+func (c *subtitleConverter) SupportedOutputFormats(inputFormat string) []string {
+	return models.SubtitleOutputFormats
+}
+
+// CanConvert checks if conversion is possible between formats
+func (c *subtitleConverter) CanConvert(inputFormat, outputFormat string) bool {
+	inputFormat = strings.ToLower(strings.TrimPrefix(inputFormat, "."))
+	outputFormat = strings.ToLower(strings.TrimPrefix(outputFormat, "."))
+
+	if !models.SubtitleFormats["."+inputFormat] {
+		return false
+	}
+
+	for _, format := range models.SubtitleOutputFormats {
+		if format == outputFormat {
+			return true
+		}
+	}
+	return false
+}
+
+// Backend reports that jobs are converted with the built-in subtitle parser
+func (c *subtitleConverter) Backend(job models.ConversionJob) string {
+	return "builtin"
+}
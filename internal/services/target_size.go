@@ -0,0 +1,90 @@
+package services
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"converzen/internal/models"
+	"converzen/pkg/ffmpeg"
+)
+
+const (
+	// containerOverhead is the share of a target size kept free for the
+	// container's headers and indexes
+	containerOverhead = 0.03
+
+	// Audio gets an eighth of the bitrate budget, within these bounds (bits/s)
+	minTargetAudioBitrate = 32_000
+	maxTargetAudioBitrate = 128_000
+
+	// minTargetVideoBitrate is the lowest video bitrate (bits/s) worth
+	// encoding; below it the video is unwatchable
+	minTargetVideoBitrate = 100_000
+)
+
+// targetBitrates splits a target size in megabytes into video and audio
+// bitrates (bits/s) for an output lasting duration seconds with the given
+// number of audio tracks. It fails if the target is too small to give the
+// video a usable bitrate.
+func targetBitrates(targetMB, duration float64, audioTracks int) (video, audio int64, err error) {
+	if duration <= 0 {
+		return 0, 0, fmt.Errorf("the duration is unknown, so the output can't be fitted to a size")
+	}
+
+	total := targetMB * 1_000_000 * 8 * (1 - containerOverhead) / duration
+
+	if audioTracks > 0 {
+		audio = int64(total / 8)
+		if audio < minTargetAudioBitrate {
+			audio = minTargetAudioBitrate
+		}
+		if audio > maxTargetAudioBitrate {
+			audio = maxTargetAudioBitrate
+		}
+	}
+
+	video = int64(total) - audio*int64(audioTracks)
+	if video < minTargetVideoBitrate {
+		needed := float64(minTargetVideoBitrate+minTargetAudioBitrate*int64(audioTracks)) * duration / 8 / (1 - containerOverhead) / 1_000_000
+		length := time.Duration(duration * float64(time.Second)).Round(time.Second)
+		return 0, 0, fmt.Errorf("%.1f MB is too small for %s of video, at least %.1f MB is needed",
+			targetMB, length, needed)
+	}
+	return video, audio, nil
+}
+
+// applyTargetSize sets the bitrates that fit a job's output under its target
+// size. The probe may be nil if the input couldn't be probed.
+func applyTargetSize(job models.ConversionJob, probe *ffmpeg.Probe, opts *ffmpeg.ConvertOptions) error {
+	if job.VideoCodec != models.CodecDefault || job.PreserveAlpha {
+		return fmt.Errorf("a target size can't be combined with a professional codec or alpha channel")
+	}
+	if probe == nil {
+		return fmt.Errorf("the input couldn't be probed, so the output can't be fitted to a size")
+	}
+
+	// The output is as long as the input after speed changes
+	duration := probe.Duration.Seconds()
+	if opts.TimeScale > 0 {
+		duration *= opts.TimeScale
+	}
+
+	audioTracks := 0
+	if probe.AudioCodec != "" {
+		audioTracks = 1
+		if opts.AllAudioStreams {
+			audioTracks = len(probe.AudioCodecs)
+		}
+	}
+
+	video, audio, err := targetBitrates(job.TargetSizeMB, duration, audioTracks)
+	if err != nil {
+		return err
+	}
+	opts.VideoBitrate = strconv.FormatInt(video, 10)
+	if audio > 0 {
+		opts.AudioBitrate = strconv.FormatInt(audio, 10)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"sync"
+
+	"converzen/internal/models"
+)
+
+// reducedWorkers is the number of concurrent conversions allowed while throttled
+const reducedWorkers = 1
+
+// throttle gates the start of conversions according to the current
+// ThrottleLevel. Running conversions are never interrupted; the level only
+// affects when the next one may start.
+type throttle struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	level   models.ThrottleLevel
+	running int
+}
+
+// newThrottle creates a throttle running at full speed
+func newThrottle() *throttle {
+	t := &throttle{level: models.ThrottleNone}
+	t.cond = sync.NewCond(&t.mu)
+	return t
+}
+
+// Acquire blocks until a conversion may start under the current level.
+// It returns true if the conversion should run at low OS priority.
+func (t *throttle) Acquire() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for t.level == models.ThrottlePaused ||
+		(t.level == models.ThrottleReduced && t.running >= reducedWorkers) {
+		t.cond.Wait()
+	}
+
+	t.running++
+	return t.level == models.ThrottleReduced
+}
+
+// Release marks a conversion started with Acquire as finished
+func (t *throttle) Release() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.running--
+	t.cond.Broadcast()
+}
+
+// SetLevel changes the throttle level, waking any waiting conversions
+func (t *throttle) SetLevel(level models.ThrottleLevel) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.level = level
+	t.cond.Broadcast()
+}
+
+// Level returns the current throttle level
+func (t *throttle) Level() models.ThrottleLevel {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.level
+}
@@ -0,0 +1,203 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"converzen/internal/logger"
+	"converzen/internal/models"
+	"converzen/pkg/ffmpeg"
+)
+
+const (
+	// thumbnailSize is the bounding box thumbnails are scaled to fit
+	thumbnailSize = 256
+
+	// maxThumbnailCacheBytes bounds the on-disk thumbnail cache; the least
+	// recently used thumbnails are evicted beyond it
+	maxThumbnailCacheBytes = 64 << 20 // 64 MiB
+
+	// thumbnailTimeout bounds extracting a single video frame
+	thumbnailTimeout = 15 * time.Second
+)
+
+// thumbnailServiceImpl implements ThumbnailService
+type thumbnailServiceImpl struct {
+	cacheDir    string
+	ffmpeg      *ffmpeg.FFmpeg
+	fileService FileService
+	log         *logger.ComponentLogger
+
+	// mu serializes cache writes and eviction
+	mu sync.Mutex
+}
+
+// NewThumbnailService creates a new ThumbnailService caching thumbnails in
+// cacheDir. ff may be nil, in which case videos get no thumbnail.
+func NewThumbnailService(cacheDir string, ff *ffmpeg.FFmpeg, fileService FileService, log *logger.Logger) ThumbnailService {
+	return &thumbnailServiceImpl{
+		cacheDir:    cacheDir,
+		ffmpeg:      ff,
+		fileService: fileService,
+		log:         log.WithComponent("thumbnail-service"),
+	}
+}
+
+// GetThumbnail returns a JPEG thumbnail of an image or video as a data URL.
+// Other file types return "" so the frontend can show a file type icon.
+// Thumbnails are cached by path, modification time and size.
+func (s *thumbnailServiceImpl) GetThumbnail(path string) (string, error) {
+	fileInfo, err := s.fileService.GetFileInfo(path)
+	if err != nil {
+		return "", err
+	}
+	if fileInfo.Type != models.FileTypeImage && fileInfo.Type != models.FileTypeVideo {
+		return "", nil
+	}
+	if fileInfo.Type == models.FileTypeVideo && s.ffmpeg == nil {
+		return "", nil
+	}
+
+	stat, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("file not found: %s", path)
+	}
+	cachePath := s.cachePath(path, stat)
+
+	// Serve from the cache, refreshing the entry's access time for eviction
+	if data, err := os.ReadFile(cachePath); err == nil {
+		now := time.Now()
+		os.Chtimes(cachePath, now, now)
+		return thumbnailDataURL(data), nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(s.cacheDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create thumbnail cache: %w", err)
+	}
+
+	// Write to a temp file first so a failed thumbnail never lands in the cache
+	tmpPath := cachePath + ".tmp.jpg"
+	defer os.Remove(tmpPath)
+
+	if fileInfo.Type == models.FileTypeVideo {
+		ctx, cancel := context.WithTimeout(context.Background(), thumbnailTimeout)
+		defer cancel()
+		err = s.ffmpeg.Thumbnail(ctx, path, tmpPath, thumbnailSize)
+	} else {
+		err = writeImageThumbnail(path, tmpPath)
+	}
+	if err != nil {
+		s.log.Warn("Failed to create thumbnail for %s: %v", path, err)
+		return "", err
+	}
+
+	if err := os.Rename(tmpPath, cachePath); err != nil {
+		return "", fmt.Errorf("failed to cache thumbnail: %w", err)
+	}
+	s.evict()
+
+	data, err := os.ReadFile(cachePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read thumbnail: %w", err)
+	}
+	return thumbnailDataURL(data), nil
+}
+
+// ClearThumbnailCache deletes every cached thumbnail
+func (s *thumbnailServiceImpl) ClearThumbnailCache() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.log.Info("Clearing thumbnail cache: %s", s.cacheDir)
+	if err := os.RemoveAll(s.cacheDir); err != nil {
+		return fmt.Errorf("failed to clear thumbnail cache: %w", err)
+	}
+	return nil
+}
+
+// cachePath returns the cache file for a file at its current version
+func (s *thumbnailServiceImpl) cachePath(path string, stat os.FileInfo) string {
+	key := path + "\x00" + strconv.FormatInt(stat.ModTime().UnixNano(), 10) + "\x00" + strconv.FormatInt(stat.Size(), 10)
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(s.cacheDir, hex.EncodeToString(sum[:16])+".jpg")
+}
+
+// evict deletes the least recently used thumbnails until the cache fits
+// maxThumbnailCacheBytes. The caller must hold s.mu.
+func (s *thumbnailServiceImpl) evict() {
+	entries, err := os.ReadDir(s.cacheDir)
+	if err != nil {
+		return
+	}
+
+	var files []os.FileInfo
+	var total int64
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || info.IsDir() {
+			continue
+		}
+		files = append(files, info)
+		total += info.Size()
+	}
+	if total <= maxThumbnailCacheBytes {
+		return
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].ModTime().Before(files[j].ModTime()) })
+	removed := 0
+	for _, info := range files {
+		if total <= maxThumbnailCacheBytes {
+			break
+		}
+		if err := os.Remove(filepath.Join(s.cacheDir, info.Name())); err == nil {
+			total -= info.Size()
+			removed++
+		}
+	}
+	s.log.Debug("Evicted %d thumbnails from cache", removed)
+}
+
+// writeImageThumbnail scales an image to fit thumbnailSize and writes it as JPEG
+func writeImageThumbnail(inputPath, outputPath string) error {
+	input, err := os.Open(inputPath)
+	if err != nil {
+		return err
+	}
+	defer input.Close()
+
+	img, _, err := image.Decode(input)
+	if err != nil {
+		return fmt.Errorf("failed to decode image: %w", err)
+	}
+	img = scaleImage(img, thumbnailSize, thumbnailSize, models.ScalerBalanced)
+
+	output, err := os.Create(outputPath)
+	if err != nil {
+		return err
+	}
+	if err := jpeg.Encode(output, img, &jpeg.Options{Quality: 80}); err != nil {
+		output.Close()
+		return fmt.Errorf("failed to encode thumbnail: %w", err)
+	}
+	return output.Close()
+}
+
+// thumbnailDataURL encodes JPEG data as a data URL for the frontend
+func thumbnailDataURL(data []byte) string {
+	return "data:image/jpeg;base64," + base64.StdEncoding.EncodeToString(data)
+}
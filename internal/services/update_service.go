@@ -0,0 +1,249 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"converzen/internal/logger"
+	"converzen/internal/models"
+	"converzen/pkg/httpclient"
+)
+
+// updateCheckTimeout bounds a single request to the release feed
+const updateCheckTimeout = 30 * time.Second
+
+// updateServiceImpl implements UpdateService against a GitHub-style
+// "latest release" feed
+type updateServiceImpl struct {
+	feedURL        string
+	currentVersion string
+	clients        *httpclient.Factory
+	log            *logger.ComponentLogger
+
+	mu     sync.Mutex
+	latest *models.UpdateInfo
+}
+
+// NewUpdateService creates a new UpdateService that checks feedURL for
+// releases newer than currentVersion
+func NewUpdateService(feedURL, currentVersion string, clients *httpclient.Factory, log *logger.Logger) UpdateService {
+	return &updateServiceImpl{
+		feedURL:        feedURL,
+		currentVersion: currentVersion,
+		clients:        clients,
+		log:            log.WithComponent("update-service"),
+	}
+}
+
+// releaseFeed is the part of a GitHub release the checker uses
+type releaseFeed struct {
+	TagName     string    `json:"tag_name"`
+	HTMLURL     string    `json:"html_url"`
+	Body        string    `json:"body"`
+	Draft       bool      `json:"draft"`
+	Prerelease  bool      `json:"prerelease"`
+	PublishedAt time.Time `json:"published_at"`
+	Assets      []struct {
+		Name        string `json:"name"`
+		DownloadURL string `json:"browser_download_url"`
+	} `json:"assets"`
+}
+
+// CheckForUpdates fetches the latest release and compares it with the
+// running version
+func (s *updateServiceImpl) CheckForUpdates(ctx context.Context) (*models.UpdateInfo, error) {
+	if s.feedURL == "" {
+		return nil, fmt.Errorf("no release feed is configured")
+	}
+	s.log.Debug("Checking %s for updates", s.feedURL)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.feedURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create update request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("User-Agent", "Converzen/"+s.currentVersion)
+
+	resp, err := s.clients.Client(updateCheckTimeout).Do(req)
+	if err != nil {
+		s.log.Warn("Update check failed: %v", err)
+		return nil, fmt.Errorf("failed to check for updates: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		s.log.Warn("Update check failed: %s", resp.Status)
+		return nil, fmt.Errorf("failed to check for updates: %s", resp.Status)
+	}
+
+	var release releaseFeed
+	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		return nil, fmt.Errorf("failed to read release feed: %w", err)
+	}
+	if release.TagName == "" {
+		return nil, fmt.Errorf("release feed has no version")
+	}
+
+	info := &models.UpdateInfo{
+		CurrentVersion: s.currentVersion,
+		LatestVersion:  strings.TrimPrefix(release.TagName, "v"),
+		ReleaseURL:     release.HTMLURL,
+		Notes:          release.Body,
+		PublishedAt:    release.PublishedAt,
+		CheckedAt:      time.Now(),
+	}
+	if !release.Draft && !release.Prerelease {
+		info.Available = compareVersions(info.LatestVersion, s.currentVersion) > 0
+	}
+	for _, asset := range release.Assets {
+		if assetMatchesPlatform(asset.Name) {
+			info.DownloadURL = asset.DownloadURL
+			break
+		}
+	}
+
+	s.mu.Lock()
+	s.latest = info
+	s.mu.Unlock()
+
+	if info.Available {
+		s.log.Info("Update available: %s (running %s)", info.LatestVersion, s.currentVersion)
+	}
+	return info, nil
+}
+
+// GetUpdateInfo returns the result of the last successful check, or nil if
+// there hasn't been one
+func (s *updateServiceImpl) GetUpdateInfo() *models.UpdateInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.latest
+}
+
+// assetMatchesPlatform reports whether a release asset's name looks like a
+// build for this OS and architecture. Names are compared word by word, split
+// at dashes, underscores, dots and spaces, so "darwin" doesn't match "win".
+func assetMatchesPlatform(name string) bool {
+	// x86_64 would otherwise split into two words
+	name = strings.ReplaceAll(strings.ToLower(name), "x86_64", "amd64")
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return strings.ContainsRune("-_. ", r)
+	})
+
+	var osNames []string
+	switch runtime.GOOS {
+	case "darwin":
+		osNames = []string{"darwin", "macos", "mac", "osx"}
+	case "windows":
+		osNames = []string{"windows", "win", "win64"}
+	default:
+		osNames = []string{runtime.GOOS}
+	}
+	archNames := []string{runtime.GOARCH, "universal"}
+	if runtime.GOARCH == "amd64" {
+		archNames = append(archNames, "x64", "win64")
+	}
+
+	return containsAny(words, osNames) && containsAny(words, archNames)
+}
+
+// containsAny reports whether words contains any of names
+func containsAny(words, names []string) bool {
+	for _, name := range names {
+		if slices.Contains(words, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// compareVersions compares two semantic versions, returning -1, 0 or 1.
+// A leading "v" and build metadata are ignored, and a pre-release sorts
+// before the release it precedes.
+func compareVersions(a, b string) int {
+	aCore, aPre := splitVersion(a)
+	bCore, bPre := splitVersion(b)
+
+	for i := 0; i < 3; i++ {
+		if aCore[i] != bCore[i] {
+			if aCore[i] < bCore[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+
+	switch {
+	case aPre == bPre:
+		return 0
+	case aPre == "":
+		return 1
+	case bPre == "":
+		return -1
+	}
+	return comparePrerelease(aPre, bPre)
+}
+
+// splitVersion parses "v1.2.3-beta.1+build" into its numeric core and
+// pre-release. Missing or malformed numbers count as 0.
+func splitVersion(version string) ([3]int, string) {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if i := strings.IndexByte(version, '+'); i >= 0 {
+		version = version[:i]
+	}
+	pre := ""
+	if i := strings.IndexByte(version, '-'); i >= 0 {
+		version, pre = version[:i], version[i+1:]
+	}
+
+	var core [3]int
+	for i, part := range strings.SplitN(version, ".", 3) {
+		core[i], _ = strconv.Atoi(part)
+	}
+	return core, pre
+}
+
+// comparePrerelease compares dot-separated pre-release identifiers as
+// semver does: numbers numerically and below words, words lexically
+func comparePrerelease(a, b string) int {
+	aParts := strings.Split(a, ".")
+	bParts := strings.Split(b, ".")
+
+	for i := 0; i < len(aParts) && i < len(bParts); i++ {
+		aNum, aErr := strconv.Atoi(aParts[i])
+		bNum, bErr := strconv.Atoi(bParts[i])
+		switch {
+		case aErr == nil && bErr == nil:
+			if aNum != bNum {
+				if aNum < bNum {
+					return -1
+				}
+				return 1
+			}
+		case aErr == nil:
+			return -1
+		case bErr == nil:
+			return 1
+		default:
+			if c := strings.Compare(aParts[i], bParts[i]); c != 0 {
+				return c
+			}
+		}
+	}
+
+	switch {
+	case len(aParts) < len(bParts):
+		return -1
+	case len(aParts) > len(bParts):
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,184 @@
+package services
+
+import (
+	"fmt"
+
+	"converzen/internal/models"
+	"converzen/pkg/ffmpeg"
+)
+
+// encoderSettings holds the FFmpeg codec options used to encode a job's output
+type encoderSettings struct {
+	videoCodec   string
+	audioCodec   string
+	videoProfile string
+	pixelFormat  string
+}
+
+// apply sets the encoder options on opts
+func (e encoderSettings) apply(opts *ffmpeg.ConvertOptions) {
+	opts.VideoCodec = e.videoCodec
+	opts.AudioCodec = e.audioCodec
+	opts.VideoProfile = e.videoProfile
+	opts.PixelFormat = e.pixelFormat
+}
+
+// proResProfiles maps ProRes profiles to prores_ks profile numbers
+var proResProfiles = map[models.ProResProfile]string{
+	models.ProResProxy:    "0",
+	models.ProResLT:       "1",
+	models.ProResStandard: "2",
+	models.ProResHQ:       "3",
+	models.ProRes4444:     "4",
+	models.ProRes4444XQ:   "5",
+}
+
+// dnxhrProfiles maps DNxHR profiles to the dnxhd encoder profile and the
+// pixel format the profile requires
+var dnxhrProfiles = map[models.DNxHRProfile]struct {
+	profile     string
+	pixelFormat string
+}{
+	models.DNxHRLB:  {"dnxhr_lb", "yuv422p"},
+	models.DNxHRSQ:  {"dnxhr_sq", "yuv422p"},
+	models.DNxHRHQ:  {"dnxhr_hq", "yuv422p"},
+	models.DNxHRHQX: {"dnxhr_hqx", "yuv422p10le"},
+	models.DNxHR444: {"dnxhr_444", "yuv444p10le"},
+}
+
+const (
+	// dnxhrMinWidth and dnxhrMinHeight are the smallest frame FFmpeg's DNxHR encoder accepts
+	dnxhrMinWidth  = 256
+	dnxhrMinHeight = 120
+)
+
+// jobEncoder returns the encoder settings for a job's output format. It fails
+// when the job asks for a codec the format, the source or the FFmpeg build
+// can't produce. The probe may be nil if the input couldn't be probed.
+func jobEncoder(ff *ffmpeg.FFmpeg, job models.ConversionJob, outputFormat string, probe *ffmpeg.Probe) (encoderSettings, error) {
+	if job.SocialPreset != "" {
+		return socialEncoder(job, outputFormat)
+	}
+	if job.PreserveAlpha {
+		return alphaEncoder(ff, job, outputFormat)
+	}
+
+	switch job.VideoCodec {
+	case models.CodecDefault:
+		videoCodec, audioCodec := ff.DefaultCodec(outputFormat)
+		return encoderSettings{videoCodec: videoCodec, audioCodec: audioCodec}, nil
+
+	case models.CodecProRes:
+		if outputFormat != "mov" {
+			return encoderSettings{}, fmt.Errorf("ProRes output requires the mov format, not %s", outputFormat)
+		}
+		if !ff.HasEncoder("prores_ks") {
+			return encoderSettings{}, fmt.Errorf("this FFmpeg build has no ProRes encoder")
+		}
+
+		profile := job.ProResProfile
+		if profile == "" {
+			profile = models.ProResHQ
+		}
+		number, ok := proResProfiles[profile]
+		if !ok {
+			return encoderSettings{}, fmt.Errorf("unknown ProRes profile: %s", profile)
+		}
+
+		// 4444 profiles keep full chroma resolution; editors expect PCM audio
+		pixelFormat := "yuv422p10le"
+		if profile == models.ProRes4444 || profile == models.ProRes4444XQ {
+			pixelFormat = "yuv444p10le"
+		}
+		return encoderSettings{
+			videoCodec:   "prores_ks",
+			audioCodec:   "pcm_s16le",
+			videoProfile: number,
+			pixelFormat:  pixelFormat,
+		}, nil
+
+	case models.CodecDNxHR:
+		if outputFormat != "mov" && outputFormat != "mkv" {
+			return encoderSettings{}, fmt.Errorf("DNxHR output requires the mov or mkv format, not %s", outputFormat)
+		}
+		if !ff.HasEncoder("dnxhd") {
+			return encoderSettings{}, fmt.Errorf("this FFmpeg build has no DNxHR encoder")
+		}
+
+		profile := job.DNxHRProfile
+		if profile == "" {
+			profile = models.DNxHRHQ
+		}
+		settings, ok := dnxhrProfiles[profile]
+		if !ok {
+			return encoderSettings{}, fmt.Errorf("unknown DNxHR profile: %s", profile)
+		}
+
+		// Check the frame size up front rather than failing mid-encode
+		if probe != nil && probe.Width > 0 && probe.Height > 0 {
+			if probe.Width < dnxhrMinWidth || probe.Height < dnxhrMinHeight {
+				return encoderSettings{}, fmt.Errorf("DNxHR needs at least %dx%d video, source is %dx%d",
+					dnxhrMinWidth, dnxhrMinHeight, probe.Width, probe.Height)
+			}
+			if probe.Width%2 != 0 || probe.Height%2 != 0 {
+				return encoderSettings{}, fmt.Errorf("DNxHR needs even frame dimensions, source is %dx%d",
+					probe.Width, probe.Height)
+			}
+		}
+
+		return encoderSettings{
+			videoCodec:   "dnxhd",
+			audioCodec:   "pcm_s16le",
+			videoProfile: settings.profile,
+			pixelFormat:  settings.pixelFormat,
+		}, nil
+	}
+
+	return encoderSettings{}, fmt.Errorf("unsupported video codec: %s", job.VideoCodec)
+}
+
+// alphaEncoder returns encoder settings that keep an alpha channel: VP9
+// yuva420p for webm and ProRes 4444 for mov. A 4444 ProRes profile chosen
+// for the job is kept; any other mov codec is replaced since it can't carry alpha.
+func alphaEncoder(ff *ffmpeg.FFmpeg, job models.ConversionJob, outputFormat string) (encoderSettings, error) {
+	switch outputFormat {
+	case "webm":
+		if !ff.HasEncoder("libvpx-vp9") {
+			return encoderSettings{}, fmt.Errorf("this FFmpeg build has no VP9 encoder")
+		}
+		return encoderSettings{
+			videoCodec:  "libvpx-vp9",
+			audioCodec:  "libopus",
+			pixelFormat: "yuva420p",
+		}, nil
+
+	case "mov":
+		if !ff.HasEncoder("prores_ks") {
+			return encoderSettings{}, fmt.Errorf("this FFmpeg build has no ProRes encoder")
+		}
+		profile := models.ProRes4444
+		if job.VideoCodec == models.CodecProRes && job.ProResProfile == models.ProRes4444XQ {
+			profile = models.ProRes4444XQ
+		}
+		return encoderSettings{
+			videoCodec:   "prores_ks",
+			audioCodec:   "pcm_s16le",
+			videoProfile: proResProfiles[profile],
+			pixelFormat:  "yuva444p10le",
+		}, nil
+	}
+
+	return encoderSettings{}, fmt.Errorf("%s output can't carry an alpha channel, use webm or mov", outputFormat)
+}
+
+// supportedVideoCodecs returns the professional codecs FFmpeg can encode for an output format
+func supportedVideoCodecs(ff *ffmpeg.FFmpeg, outputFormat string) []models.VideoCodec {
+	var codecs []models.VideoCodec
+	if outputFormat == "mov" && ff.HasEncoder("prores_ks") {
+		codecs = append(codecs, models.CodecProRes)
+	}
+	if (outputFormat == "mov" || outputFormat == "mkv") && ff.HasEncoder("dnxhd") {
+		codecs = append(codecs, models.CodecDNxHR)
+	}
+	return codecs
+}
@@ -30,7 +30,7 @@ func NewVideoConverter(ff *ffmpeg.FFmpeg, log *logger.Logger) Converter {
 }
 
 // Convert converts a video file to another format
-func (c *videoConverter) Convert(job models.ConversionJob, progressCallback func(progress float64)) (*models.ConversionResult, error) {
+func (c *videoConverter) Convert(ctx context.Context, job models.ConversionJob, progressCallback func(progress float64)) (*models.ConversionResult, error) {
 	c.log.Info("Starting video conversion: %s -> %s", job.InputPath, job.OutputPath)
 	startTime := time.Now()
 
@@ -66,38 +66,15 @@ func (c *videoConverter) Convert(job models.ConversionJob, progressCallback func
 	// Get output format
 	outputFormat := strings.TrimPrefix(strings.ToLower(filepath.Ext(job.OutputPath)), ".")
 
-	// Handle GIF conversion separately
-	if outputFormat == "gif" {
-		err := c.ffmpeg.ConvertToGif(context.Background(), job.InputPath, job.OutputPath, job.OverwriteOutput, progressCallback)
-		if err != nil {
-			result.ErrorMessage = err.Error()
-			c.log.Error("GIF conversion failed: %v", err)
-			return result, err
-		}
-	} else {
-		// Get default codecs for the format
-		videoCodec, audioCodec := ffmpeg.GetDefaultCodec(outputFormat)
-
-		opts := ffmpeg.ConvertOptions{
-			InputPath:  job.InputPath,
-			OutputPath: job.OutputPath,
-			Overwrite:  job.OverwriteOutput,
-			VideoCodec: videoCodec,
-			AudioCodec: audioCodec,
-		}
-
-		err := c.ffmpeg.Convert(context.Background(), opts, progressCallback)
-		if err != nil {
-			result.ErrorMessage = err.Error()
-			c.log.Error("Video conversion failed: %v", err)
-			return result, err
-		}
+	if err := runFFmpegConversion(ctx, c.ffmpeg, c.log, job, outputFormat, result, progressCallback); err != nil {
+		return result, err
 	}
 
 	// Get output file size
 	if stat, err := os.Stat(job.OutputPath); err == nil {
 		result.OutputSize = stat.Size()
 	}
+	describeMedia(c.ffmpeg, result)
 
 	result.Success = true
 	result.Duration = time.Since(startTime).Milliseconds()
@@ -106,6 +83,37 @@ func (c *videoConverter) Convert(job models.ConversionJob, progressCallback func
 	return result, nil
 }
 
+// Preview converts a short slice of a video using FFmpeg
+func (c *videoConverter) Preview(ctx context.Context, job models.ConversionJob, length time.Duration, progressCallback func(progress float64)) (*models.ConversionResult, error) {
+	job.PreviewLength = length
+	return c.Convert(ctx, job, progressCallback)
+}
+
+// EstimateSize predicts the output size of a video from a short trial encode
+func (c *videoConverter) EstimateSize(ctx context.Context, job models.ConversionJob) (*models.SizeEstimate, error) {
+	return estimateSizeBySample(ctx, c.ffmpeg, job, c.Preview)
+}
+
+// Split cuts a video into fixed-length segments using FFmpeg
+func (c *videoConverter) Split(ctx context.Context, job models.ConversionJob, segmentLength time.Duration, progressCallback func(progress float64)) ([]models.ConversionResult, error) {
+	return runFFmpegSplit(ctx, c.ffmpeg, c.log, job, segmentLength, progressCallback)
+}
+
+// PackageStreaming packages a video for adaptive streaming using FFmpeg
+func (c *videoConverter) PackageStreaming(ctx context.Context, job models.ConversionJob, request models.StreamingRequest, progressCallback func(progress float64)) (*models.ConversionResult, error) {
+	return runFFmpegPackage(ctx, c.ffmpeg, c.log, job, request, progressCallback)
+}
+
+// EncodeSequence encodes numbered image files as a video using FFmpeg
+func (c *videoConverter) EncodeSequence(ctx context.Context, job models.ConversionJob, sequence models.ImageSequence, progressCallback func(progress float64)) (*models.ConversionResult, error) {
+	return runFFmpegSequence(ctx, c.ffmpeg, c.log, job, sequence, progressCallback)
+}
+
+// SupportedVideoCodecs returns the professional codecs FFmpeg can encode for an output format
+func (c *videoConverter) SupportedVideoCodecs(outputFormat string) []models.VideoCodec {
+	return supportedVideoCodecs(c.ffmpeg, outputFormat)
+}
+
 // SupportedInputFormats returns the list of supported input video formats
 func (c *videoConverter) SupportedInputFormats() []string {
 	formats := make([]string, 0, len(models.VideoFormats))
@@ -139,3 +147,8 @@ func (c *videoConverter) CanConvert(inputFormat, outputFormat string) bool {
 
 	return false
 }
+
+// Backend reports that jobs are converted with FFmpeg
+func (c *videoConverter) Backend(job models.ConversionJob) string {
+	return "ffmpeg"
+}
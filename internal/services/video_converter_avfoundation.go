@@ -93,6 +93,7 @@ static int convertVideoWithAVFoundation(const char* inputPath, const char* outpu
 import "C"
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -150,7 +151,7 @@ func NewVideoConverter(_ interface{}, log *logger.Logger) Converter {
 }
 
 // Convert converts a video file using AVFoundation
-func (c *avfVideoConverter) Convert(job models.ConversionJob, progressCallback func(progress float64)) (*models.ConversionResult, error) {
+func (c *avfVideoConverter) Convert(ctx context.Context, job models.ConversionJob, progressCallback func(progress float64)) (*models.ConversionResult, error) {
 	c.log.Info("Starting AVFoundation video conversion: %s -> %s", job.InputPath, job.OutputPath)
 	startTime := time.Now()
 
@@ -193,8 +194,25 @@ func (c *avfVideoConverter) Convert(job models.ConversionJob, progressCallback f
 		return result, fmt.Errorf(result.ErrorMessage)
 	}
 
+	// AVAssetExportSession cannot be interrupted from Go once started, so honour
+	// cancellation before handing the job over
+	if err := ctx.Err(); err != nil {
+		result.ErrorMessage = "Conversion was cancelled"
+		c.log.Warn("%s: %s", result.ErrorMessage, job.InputPath)
+		return result, err
+	}
+
 	// Determine the best preset
-	preset := c.getPresetForFormat(outputFormat)
+	preset, err := c.getPreset(job, outputFormat)
+	if err != nil {
+		result.ErrorMessage = err.Error()
+		c.log.Error(result.ErrorMessage)
+		return result, err
+	}
+
+	if job.Log != nil {
+		fmt.Fprintf(job.Log, "AVAssetExportSession preset %s, output %s\n", preset, outputFormat)
+	}
 
 	// Register progress callback
 	var callbackPtr uintptr
@@ -234,6 +252,9 @@ func (c *avfVideoConverter) Convert(job models.ConversionJob, progressCallback f
 		}
 		result.ErrorMessage = errMsg
 		c.log.Error("AVFoundation conversion failed: %s", errMsg)
+		if job.Log != nil {
+			fmt.Fprintf(job.Log, "AVFoundation returned %d: %s\n", ret, errMsg)
+		}
 		return result, fmt.Errorf(errMsg)
 	}
 
@@ -263,10 +284,40 @@ func (c *avfVideoConverter) isAVFoundationFormat(format string) bool {
 	return supported[format]
 }
 
-// getPresetForFormat returns the appropriate AVAssetExportSession preset
-func (c *avfVideoConverter) getPresetForFormat(format string) string {
-	// Use highest quality preset - AVFoundation handles codec selection
-	return "AVAssetExportPresetHighestQuality"
+// getPreset returns the appropriate AVAssetExportSession preset for a job
+func (c *avfVideoConverter) getPreset(job models.ConversionJob, format string) (string, error) {
+	// ProRes 4444 is the only AVFoundation preset that keeps alpha
+	if job.PreserveAlpha {
+		if format != "mov" {
+			return "", fmt.Errorf("%s output can't carry an alpha channel, use mov", format)
+		}
+		return "AVAssetExportPresetAppleProRes4444LPCM", nil
+	}
+
+	switch job.VideoCodec {
+	case models.CodecProRes:
+		if format != "mov" {
+			return "", fmt.Errorf("ProRes output requires the mov format, not %s", format)
+		}
+		// AVFoundation only offers ProRes 422 and 4444 presets, so the
+		// lighter 422 profiles all export as standard 422
+		if job.ProResProfile == models.ProRes4444 || job.ProResProfile == models.ProRes4444XQ {
+			return "AVAssetExportPresetAppleProRes4444LPCM", nil
+		}
+		return "AVAssetExportPresetAppleProRes422LPCM", nil
+	case models.CodecDefault:
+		// Use highest quality preset - AVFoundation handles codec selection
+		return "AVAssetExportPresetHighestQuality", nil
+	}
+	return "", fmt.Errorf("video codec %s is not supported by AVFoundation", job.VideoCodec)
+}
+
+// SupportedVideoCodecs returns the professional codecs AVFoundation can export for an output format
+func (c *avfVideoConverter) SupportedVideoCodecs(outputFormat string) []models.VideoCodec {
+	if outputFormat == "mov" {
+		return []models.VideoCodec{models.CodecProRes}
+	}
+	return nil
 }
 
 // SupportedInputFormats returns the list of supported input video formats
@@ -303,3 +354,8 @@ func (c *avfVideoConverter) CanConvert(inputFormat, outputFormat string) bool {
 	// Check output is supported
 	return c.isAVFoundationFormat(outputFormat)
 }
+
+// Backend reports that jobs are converted with AVFoundation
+func (c *avfVideoConverter) Backend(job models.ConversionJob) string {
+	return "avfoundation"
+}
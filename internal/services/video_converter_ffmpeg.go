@@ -30,7 +30,7 @@ func NewFFmpegVideoConverter(ff *ffmpeg.FFmpeg, log *logger.Logger) Converter {
 }
 
 // Convert converts a video file to another format using FFmpeg
-func (c *ffmpegVideoConverter) Convert(job models.ConversionJob, progressCallback func(progress float64)) (*models.ConversionResult, error) {
+func (c *ffmpegVideoConverter) Convert(ctx context.Context, job models.ConversionJob, progressCallback func(progress float64)) (*models.ConversionResult, error) {
 	c.log.Info("Starting FFmpeg video conversion: %s -> %s", job.InputPath, job.OutputPath)
 	startTime := time.Now()
 
@@ -66,44 +66,482 @@ func (c *ffmpegVideoConverter) Convert(job models.ConversionJob, progressCallbac
 	// Get output format
 	outputFormat := strings.TrimPrefix(strings.ToLower(filepath.Ext(job.OutputPath)), ".")
 
+	if err := runFFmpegConversion(ctx, c.ffmpeg, c.log, job, outputFormat, result, progressCallback); err != nil {
+		return result, err
+	}
+
+	// Get output file size
+	if stat, err := os.Stat(job.OutputPath); err == nil {
+		result.OutputSize = stat.Size()
+	}
+	describeMedia(c.ffmpeg, result)
+
+	result.Success = true
+	result.Duration = time.Since(startTime).Milliseconds()
+
+	c.log.Info("FFmpeg video conversion completed in %dms: %s", result.Duration, job.OutputPath)
+	return result, nil
+}
+
+// runFFmpegConversion runs a validated job through FFmpeg. Jobs whose streams
+// the target container can hold as-is are remuxed with stream copy, falling
+// back to a re-encode if the remux fails. Shared by all FFmpeg-backed converters.
+func runFFmpegConversion(
+	ctx context.Context,
+	ff *ffmpeg.FFmpeg,
+	log *logger.ComponentLogger,
+	job models.ConversionJob,
+	outputFormat string,
+	result *models.ConversionResult,
+	progressCallback func(progress float64),
+) error {
+	if err := checkTrim(job); err != nil {
+		result.ErrorMessage = err.Error()
+		log.Error("%s", result.ErrorMessage)
+		return err
+	}
+
+	probe, probeErr := ff.ProbeFile(job.InputPath)
+	if probeErr != nil {
+		log.Warn("Could not probe input, skipping remux and chapter checks: %v", probeErr)
+		probe = nil
+	}
+	if job.Salvage {
+		log.Info("Salvaging damaged input, decoding past corrupt data")
+	}
+
+	// Camcorder transport streams (AVCHD .mts/.m2ts, broadcast .ts) are
+	// usually 1080i, so deinterlace them unless told otherwise
+	transportStream := isTransportStream(job.InputPath)
+	if transportStream && job.Deinterlace == "" {
+		job.Deinterlace = models.DeinterlaceAuto
+	}
+	if job.Deinterlace == models.DeinterlaceAuto && probe != nil && probe.FieldOrder == "" {
+		if fieldOrder, err := ff.DetectFieldOrder(ctx, job.InputPath); err == nil {
+			probe.FieldOrder = fieldOrder
+		} else {
+			log.Warn("Could not detect field order, assuming progressive: %v", err)
+		}
+	}
+
+	// Trimmed jobs convert part of the input, and size limits apply to that
+	// part's length
+	var inputDuration time.Duration
+	if probe != nil {
+		inputDuration = probe.Duration
+	}
+	startTime, maxDuration := jobSpan(job, inputDuration)
+	if probe != nil && job.PreviewLength == 0 && (startTime > 0 || maxDuration > 0) {
+		probe.Duration -= startTime
+		if maxDuration > 0 && maxDuration < probe.Duration {
+			probe.Duration = maxDuration
+		}
+	}
+
+	// Previews convert a short slice and skip the slow multi-pass effects
+	if job.PreviewLength > 0 {
+		if job.Reverse || job.Stabilize {
+			log.Warn("Reversing and stabilization are skipped in previews")
+			job.Reverse, job.Stabilize = false, false
+		}
+	}
+
 	// Handle GIF conversion separately
 	if outputFormat == "gif" {
-		err := c.ffmpeg.ConvertToGif(context.Background(), job.InputPath, job.OutputPath, job.OverwriteOutput, progressCallback)
+		if job.Stabilize {
+			log.Warn("Stabilization is not supported for GIF output, skipping")
+		}
+		if job.TargetSizeMB > 0 {
+			log.Warn("Target sizes are not supported for GIF output, ignoring")
+		}
+		if speedFactor(job.Speed) != 1 || job.Reverse || job.Overlay != nil {
+			log.Warn("Speed changes, reversing and overlays are not supported for GIF output, skipping")
+		}
+		opts := ffmpeg.ConvertOptions{
+			InputPath:   job.InputPath,
+			OutputPath:  job.OutputPath,
+			Overwrite:   job.OverwriteOutput,
+			StartTime:   startTime,
+			MaxDuration: maxDuration,
+			Salvage:     job.Salvage,
+			Threads:     job.Threads,
+			LowPriority: job.LowPriority,
+			ReadLimit:   job.IOLimit(),
+			Log:         job.Log,
+		}
+
+		err := ff.ConvertToGif(ctx, opts, progressCallback)
 		if err != nil {
 			result.ErrorMessage = err.Error()
-			c.log.Error("GIF conversion failed: %v", err)
-			return result, err
+			log.Error("GIF conversion failed: %v", err)
+			return err
 		}
-	} else {
-		// Get default codecs for the format
-		videoCodec, audioCodec := ffmpeg.GetDefaultCodec(outputFormat)
+		result.Method = models.MethodReencode
+		if probe != nil && probe.Chapters > 0 {
+			result.Chapters = models.ChaptersDropped
+		}
+		return nil
+	}
 
-		opts := ffmpeg.ConvertOptions{
-			InputPath:  job.InputPath,
-			OutputPath: job.OutputPath,
-			Overwrite:  job.OverwriteOutput,
-			VideoCodec: videoCodec,
-			AudioCodec: audioCodec,
+	encoder, err := jobEncoder(ff, job, outputFormat, probe)
+	if err != nil {
+		result.ErrorMessage = err.Error()
+		log.Error("%s", result.ErrorMessage)
+		return err
+	}
+
+	opts := ffmpeg.ConvertOptions{
+		InputPath:     job.InputPath,
+		OutputPath:    job.OutputPath,
+		Overwrite:     job.OverwriteOutput,
+		StartTime:     startTime,
+		MaxDuration:   maxDuration,
+		StreamIndexes: job.Streams,
+		Metadata:      job.Metadata.Tags(),
+		Salvage:       job.Salvage,
+		Threads:       job.Threads,
+		LowPriority:   job.LowPriority,
+		ReadLimit:     job.IOLimit(),
+		Quality:       jobQuality(job),
+		Log:           job.Log,
+	}
+	if job.PreserveAlpha {
+		opts.VideoDecoder = alphaDecoder(log, probe)
+	}
+
+	// Keep every audio track when requested and the container can hold them
+	if job.KeepAllAudio && len(job.Streams) == 0 {
+		if ffmpeg.SupportsMultipleAudio(outputFormat) {
+			opts.AllAudioStreams = true
+		} else {
+			log.Warn("%s output holds a single audio track, keeping only the default track", outputFormat)
+		}
+	}
+
+	// Carry chapter markers over when the target container can hold them
+	if probe != nil && probe.Chapters > 0 {
+		if ffmpeg.SupportsChapters(outputFormat) {
+			opts.MapChapters = true
+		} else {
+			log.Warn("%s output can't carry chapters, dropping %d chapter(s)", outputFormat, probe.Chapters)
 		}
+	}
 
-		err := c.ffmpeg.Convert(context.Background(), opts, progressCallback)
+	opts.VideoFilter = strings.Join(videoFilters(job, probe, log), ",")
+	opts.AudioFilter = strings.Join(audioFilters(job), ",")
+	if speed := speedFactor(job.Speed); speed != 1 {
+		opts.TimeScale = 1 / speed
+	}
+
+	// Presets and target sizes set the bitrates, which every encode below uses
+	if job.SocialPreset != "" {
+		warnings, err := applySocialPreset(job, probe, &opts)
 		if err != nil {
 			result.ErrorMessage = err.Error()
-			c.log.Error("Video conversion failed: %v", err)
-			return result, err
+			log.Error("%s", result.ErrorMessage)
+			return err
+		}
+		for _, warning := range warnings {
+			log.Warn("%s", warning)
+		}
+		result.Warnings = append(result.Warnings, warnings...)
+	}
+	if job.TargetSizeMB > 0 {
+		if err := applyTargetSize(job, probe, &opts); err != nil {
+			result.ErrorMessage = err.Error()
+			log.Error("%s", result.ErrorMessage)
+			return err
 		}
+		log.Info("Fitting output under %.1f MB with %s b/s video", job.TargetSizeMB, opts.VideoBitrate)
 	}
 
-	// Get output file size
-	if stat, err := os.Stat(job.OutputPath); err == nil {
-		result.OutputSize = stat.Size()
+	// Composite a picture-in-picture overlay onto the main video
+	if job.Overlay != nil {
+		if job.Reverse || job.Stabilize || speedFactor(job.Speed) != 1 {
+			result.ErrorMessage = "Overlays can't be combined with speed changes, reversing or stabilization"
+			log.Error("%s", result.ErrorMessage)
+			return fmt.Errorf("%s", result.ErrorMessage)
+		}
+		if _, err := os.Stat(job.Overlay.Path); err != nil {
+			result.ErrorMessage = fmt.Sprintf("Overlay file not found: %s", job.Overlay.Path)
+			log.Error("%s", result.ErrorMessage)
+			return fmt.Errorf("%s", result.ErrorMessage)
+		}
+
+		opts.Overlay = overlayFor(job.Overlay)
+		encoder.apply(&opts)
+
+		err := ff.Convert(ctx, opts, progressCallback)
+		if err != nil {
+			result.ErrorMessage = err.Error()
+			log.Error("Overlay conversion failed: %v", err)
+			return err
+		}
+
+		result.Method = models.MethodReencode
+		result.Chapters = chapterStatus(ff, probe, job.OutputPath)
+		return nil
 	}
 
-	result.Success = true
-	result.Duration = time.Since(startTime).Milliseconds()
+	// Reversing buffers the video in memory and may run in segments
+	if job.Reverse {
+		if job.Stabilize {
+			result.ErrorMessage = "Stabilization can't be combined with reversing"
+			log.Error("%s", result.ErrorMessage)
+			return fmt.Errorf("%s", result.ErrorMessage)
+		}
 
-	c.log.Info("FFmpeg video conversion completed in %dms: %s", result.Duration, job.OutputPath)
-	return result, nil
+		encoder.apply(&opts)
+		opts.MapChapters = false
+
+		err := ff.ConvertReversed(ctx, opts, progressCallback)
+		if err != nil {
+			result.ErrorMessage = err.Error()
+			log.Error("Reversed conversion failed: %v", err)
+			return err
+		}
+
+		result.Method = models.MethodReencode
+		if probe != nil && probe.Chapters > 0 {
+			result.Chapters = models.ChaptersDropped
+		}
+		return nil
+	}
+
+	// Stabilization filters the video, so it always re-encodes
+	if job.Stabilize {
+		encoder.apply(&opts)
+
+		err := ff.ConvertStabilized(ctx, opts, job.StabilizeStrength, progressCallback)
+		if err != nil {
+			result.ErrorMessage = err.Error()
+			log.Error("Stabilized conversion failed: %v", err)
+			return err
+		}
+
+		result.Method = models.MethodReencode
+		result.Chapters = chapterStatus(ff, probe, job.OutputPath)
+		return nil
+	}
+
+	// Transport streams carry AC-3 or LPCM audio that many mp4/mov players
+	// can't play, so copy the video and re-encode only the audio to AAC.
+	// Salvaging, target sizes and quality settings re-encode, as copying
+	// would carry the corrupt data over or keep the source's size.
+	canCopy := job.VideoCodec == models.CodecDefault && opts.VideoFilter == "" && opts.AudioFilter == "" &&
+		!job.Salvage && job.TargetSizeMB == 0 && opts.Quality == 0
+	if canCopy && transportStream && aacContainers[outputFormat] && probe != nil && probe.AudioCodec != "" && probe.AudioCodec != "aac" && ffmpeg.CanCopyVideo(probe, outputFormat) {
+		log.Info("Copying %s video and re-encoding %s audio to AAC for %s", probe.VideoCodec, probe.AudioCodec, outputFormat)
+
+		copyOpts := opts
+		copyOpts.VideoCodec = "copy"
+		copyOpts.AudioCodec = "aac"
+		copyOpts.Overwrite = true
+
+		err := ff.Convert(ctx, copyOpts, progressCallback)
+		if err == nil {
+			result.Method = models.MethodVideoCopy
+			result.Chapters = chapterStatus(ff, probe, job.OutputPath)
+			return nil
+		}
+		if ctx.Err() != nil {
+			result.ErrorMessage = err.Error()
+			return err
+		}
+		log.Warn("Video copy failed, falling back to re-encoding: %v", err)
+		opts.Overwrite = true
+		canCopy = false
+	}
+
+	// Use stream copy when the source codecs fit the target container and
+	// no filters need to be applied
+	if canCopy && ffmpeg.CanRemux(probe, outputFormat, opts.AllAudioStreams) {
+		log.Info("Source codecs (%s/%s) fit %s, remuxing without re-encoding", probe.VideoCodec, probe.AudioCodec, outputFormat)
+
+		remuxOpts := opts
+		remuxOpts.VideoCodec = "copy"
+		remuxOpts.AudioCodec = "copy"
+		remuxOpts.Overwrite = true
+
+		err := ff.Convert(ctx, remuxOpts, progressCallback)
+		if err == nil {
+			result.Method = models.MethodRemux
+			result.Chapters = chapterStatus(ff, probe, job.OutputPath)
+			return nil
+		}
+		if ctx.Err() != nil {
+			result.ErrorMessage = err.Error()
+			return err
+		}
+		log.Warn("Remux failed, falling back to re-encoding: %v", err)
+		opts.Overwrite = true
+	}
+
+	// Encode with the format's default or the requested codec. Two passes
+	// only help when encoding to a bitrate.
+	encoder.apply(&opts)
+	warnQuality(log, opts)
+
+	if job.TwoPass && opts.VideoBitrate != "" && ffmpeg.SupportsTwoPass(opts.VideoCodec) {
+		err = ff.ConvertTwoPass(ctx, opts, progressCallback)
+	} else {
+		if job.TwoPass && opts.VideoBitrate != "" {
+			log.Warn("%s can't encode in two passes, encoding in one", opts.VideoCodec)
+		}
+		err = ff.Convert(ctx, opts, progressCallback)
+	}
+	if err != nil {
+		result.ErrorMessage = err.Error()
+		log.Error("Video conversion failed: %v", err)
+		return err
+	}
+
+	result.Method = models.MethodReencode
+	result.Chapters = chapterStatus(ff, probe, job.OutputPath)
+	return nil
+}
+
+// runFFmpegSplit cuts a video into segments, copying streams when the source
+// codecs fit the target container. Shared by all FFmpeg-backed converters.
+func runFFmpegSplit(
+	ctx context.Context,
+	ff *ffmpeg.FFmpeg,
+	log *logger.ComponentLogger,
+	job models.ConversionJob,
+	segmentLength time.Duration,
+	progressCallback func(progress float64),
+) ([]models.ConversionResult, error) {
+	startTime := time.Now()
+
+	if _, err := os.Stat(job.InputPath); os.IsNotExist(err) {
+		return nil, fmt.Errorf("input file not found: %s", job.InputPath)
+	}
+	if err := os.MkdirAll(filepath.Dir(job.OutputPath), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	if job.TargetSizeMB > 0 {
+		log.Warn("Target sizes are not supported when splitting, ignoring")
+	}
+
+	outputFormat := strings.TrimPrefix(strings.ToLower(filepath.Ext(job.OutputPath)), ".")
+	probe, probeErr := ff.ProbeFile(job.InputPath)
+	if probeErr != nil {
+		probe = nil
+	}
+	encoder, err := jobEncoder(ff, job, outputFormat, probe)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := ffmpeg.ConvertOptions{
+		InputPath:     job.InputPath,
+		OutputPath:    job.OutputPath,
+		Overwrite:     job.OverwriteOutput,
+		StreamIndexes: job.Streams,
+		Metadata:      job.Metadata.Tags(),
+		Salvage:       job.Salvage,
+		Threads:       job.Threads,
+		LowPriority:   job.LowPriority,
+		ReadLimit:     job.IOLimit(),
+		Quality:       jobQuality(job),
+		Log:           job.Log,
+	}
+	if job.PreserveAlpha {
+		opts.VideoDecoder = alphaDecoder(log, probe)
+	}
+	if job.KeepAllAudio && len(job.Streams) == 0 && ffmpeg.SupportsMultipleAudio(outputFormat) {
+		opts.AllAudioStreams = true
+	}
+
+	method := models.MethodReencode
+	if job.VideoCodec == models.CodecDefault && !job.Salvage && opts.Quality == 0 && ffmpeg.CanRemux(probe, outputFormat, opts.AllAudioStreams) {
+		opts.VideoCodec, opts.AudioCodec = "copy", "copy"
+		method = models.MethodRemux
+	} else {
+		encoder.apply(&opts)
+	}
+
+	paths, err := ff.Split(ctx, opts, segmentLength, progressCallback)
+	if err != nil {
+		log.Error("Split failed: %v", err)
+		return nil, err
+	}
+
+	duration := time.Since(startTime).Milliseconds()
+	results := make([]models.ConversionResult, len(paths))
+	for i, path := range paths {
+		results[i] = models.ConversionResult{
+			Success:    true,
+			InputPath:  job.InputPath,
+			OutputPath: path,
+			Duration:   duration,
+			Method:     method,
+		}
+		if stat, err := os.Stat(path); err == nil {
+			results[i].OutputSize = stat.Size()
+		}
+	}
+	return results, nil
+}
+
+// overlayFor converts job overlay options to FFmpeg overlay settings
+func overlayFor(options *models.OverlayOptions) *ffmpeg.Overlay {
+	overlay := &ffmpeg.Overlay{
+		Path:   options.Path,
+		Corner: ffmpeg.OverlayCorner(options.Corner),
+		Scale:  options.Scale,
+		Margin: -1,
+	}
+	if options.Margin != nil {
+		overlay.Margin = *options.Margin
+	}
+	return overlay
+}
+
+// chapterStatus reports whether the source's chapters made it into the output,
+// or an empty status when the source had none
+func chapterStatus(ff *ffmpeg.FFmpeg, source *ffmpeg.Probe, outputPath string) models.ChapterStatus {
+	if source == nil || source.Chapters == 0 {
+		return ""
+	}
+
+	output, err := ff.ProbeFile(outputPath)
+	if err != nil || output.Chapters == 0 {
+		return models.ChaptersDropped
+	}
+	return models.ChaptersPreserved
+}
+
+// Preview converts a short slice of a video using FFmpeg
+func (c *ffmpegVideoConverter) Preview(ctx context.Context, job models.ConversionJob, length time.Duration, progressCallback func(progress float64)) (*models.ConversionResult, error) {
+	job.PreviewLength = length
+	return c.Convert(ctx, job, progressCallback)
+}
+
+// EstimateSize predicts the output size of a video from a short trial encode
+func (c *ffmpegVideoConverter) EstimateSize(ctx context.Context, job models.ConversionJob) (*models.SizeEstimate, error) {
+	return estimateSizeBySample(ctx, c.ffmpeg, job, c.Preview)
+}
+
+// Split cuts a video into fixed-length segments using FFmpeg
+func (c *ffmpegVideoConverter) Split(ctx context.Context, job models.ConversionJob, segmentLength time.Duration, progressCallback func(progress float64)) ([]models.ConversionResult, error) {
+	return runFFmpegSplit(ctx, c.ffmpeg, c.log, job, segmentLength, progressCallback)
+}
+
+// PackageStreaming packages a video for adaptive streaming using FFmpeg
+func (c *ffmpegVideoConverter) PackageStreaming(ctx context.Context, job models.ConversionJob, request models.StreamingRequest, progressCallback func(progress float64)) (*models.ConversionResult, error) {
+	return runFFmpegPackage(ctx, c.ffmpeg, c.log, job, request, progressCallback)
+}
+
+// EncodeSequence encodes numbered image files as a video using FFmpeg
+func (c *ffmpegVideoConverter) EncodeSequence(ctx context.Context, job models.ConversionJob, sequence models.ImageSequence, progressCallback func(progress float64)) (*models.ConversionResult, error) {
+	return runFFmpegSequence(ctx, c.ffmpeg, c.log, job, sequence, progressCallback)
+}
+
+// SupportedVideoCodecs returns the professional codecs FFmpeg can encode for an output format
+func (c *ffmpegVideoConverter) SupportedVideoCodecs(outputFormat string) []models.VideoCodec {
+	return supportedVideoCodecs(c.ffmpeg, outputFormat)
 }
 
 // SupportedInputFormats returns the list of supported input video formats
@@ -139,3 +577,105 @@ func (c *ffmpegVideoConverter) CanConvert(inputFormat, outputFormat string) bool
 
 	return false
 }
+
+// Backend reports that jobs are converted with FFmpeg
+func (c *ffmpegVideoConverter) Backend(job models.ConversionJob) string {
+	return "ffmpeg"
+}
+
+// transportStreamFormats lists the MPEG transport stream extensions written
+// by AVCHD camcorders and broadcast recorders
+var transportStreamFormats = map[string]bool{
+	".mts":  true,
+	".m2ts": true,
+	".ts":   true,
+}
+
+// aacContainers lists the output formats whose players expect AAC audio
+var aacContainers = map[string]bool{
+	"mp4": true,
+	"m4v": true,
+	"mov": true,
+}
+
+// isTransportStream reports whether a file is an MPEG transport stream
+func isTransportStream(path string) bool {
+	return transportStreamFormats[strings.ToLower(filepath.Ext(path))]
+}
+
+// alphaDecoder returns the decoder needed to read a source's alpha channel,
+// or "" for FFmpeg's default. FFmpeg's native VP8/VP9 decoders drop WebM
+// alpha, so libvpx is used for those sources.
+func alphaDecoder(log *logger.ComponentLogger, probe *ffmpeg.Probe) string {
+	if probe == nil {
+		return ""
+	}
+	if !probe.Alpha {
+		log.Warn("Source has no alpha channel (pixel format %s), output will be opaque", probe.PixelFormat)
+		return ""
+	}
+	switch probe.VideoCodec {
+	case "vp8":
+		return "libvpx"
+	case "vp9":
+		return "libvpx-vp9"
+	}
+	return ""
+}
+
+// checkTrim rejects trim ranges that select nothing
+func checkTrim(job models.ConversionJob) error {
+	if job.TrimStart < 0 || job.TrimEnd < 0 || (job.TrimEnd > 0 && job.TrimEnd <= job.TrimStart) {
+		return fmt.Errorf("invalid trim range: %gs to %gs", job.TrimStart, job.TrimEnd)
+	}
+	return nil
+}
+
+// jobSpan returns the part of an input of the given duration a job
+// converts: its trimmed range, or for previews a slice from within that
+// range. A maxDuration of 0 converts to the end of the input.
+func jobSpan(job models.ConversionJob, duration time.Duration) (startTime, maxDuration time.Duration) {
+	startTime = time.Duration(job.TrimStart * float64(time.Second))
+	if job.TrimEnd > 0 {
+		maxDuration = time.Duration((job.TrimEnd - job.TrimStart) * float64(time.Second))
+	}
+	if job.PreviewLength == 0 {
+		return startTime, maxDuration
+	}
+
+	length := duration - startTime
+	if maxDuration > 0 {
+		length = maxDuration
+	}
+	return startTime + previewStart(length, job.PreviewLength), job.PreviewLength
+}
+
+// previewStart returns where a preview slice of the given length starts:
+// the middle of inputs long enough to skip intros, otherwise the beginning
+func previewStart(duration, length time.Duration) time.Duration {
+	if duration <= 2*length {
+		return 0
+	}
+	return (duration - length) / 2
+}
+
+// describeMedia fills a result's bitrate and resolution comparison from
+// probes of its input and output. Probe failures leave the fields empty.
+func describeMedia(ff *ffmpeg.FFmpeg, result *models.ConversionResult) {
+	if input, err := ff.ProbeFile(result.InputPath); err == nil {
+		result.InputBitrate = input.Bitrate
+		result.InputResolution = probeResolution(input)
+	}
+	if output, err := ff.ProbeFile(result.OutputPath); err == nil {
+		result.OutputBitrate = output.Bitrate
+		result.OutputResolution = probeResolution(output)
+	}
+}
+
+// probeResolution formats a probe's frame size, or "" for files without video
+func probeResolution(probe *ffmpeg.Probe) string {
+	if probe.Width == 0 || probe.Height == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%dx%d", probe.Width, probe.Height)
+}
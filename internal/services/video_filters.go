@@ -0,0 +1,167 @@
+package services
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"converzen/internal/logger"
+	"converzen/internal/models"
+	"converzen/pkg/ffmpeg"
+)
+
+// videoFilters returns the FFmpeg video filters a job asks for, in the order
+// they must be applied. The probe may be nil if the input couldn't be probed.
+func videoFilters(job models.ConversionJob, probe *ffmpeg.Probe, log *logger.ComponentLogger) []string {
+	var filters []string
+
+	if filter := deinterlaceFilter(job.Deinterlace, probe, log); filter != "" {
+		filters = append(filters, filter)
+	}
+
+	// Social presets size the frame themselves
+	if (job.VideoMaxWidth > 0 || job.VideoMaxHeight > 0) && job.SocialPreset == "" {
+		filters = append(filters, downscaleFilter(job.VideoMaxWidth, job.VideoMaxHeight))
+	}
+
+	// Burn in before speed changes, so the timecode follows the source
+	filters = append(filters, burnInFilters(job, probe, log)...)
+
+	if speed := speedFactor(job.Speed); speed != 1 {
+		filters = append(filters, fmt.Sprintf("setpts=PTS/%s", formatFactor(speed)))
+	}
+
+	if preset, ok := models.GetSocialPreset(job.SocialPreset); ok {
+		filters = append(filters, socialFilters(preset, probe)...)
+	}
+
+	return filters
+}
+
+// downscaleFilter returns a scale filter fitting the frame within
+// maxWidth x maxHeight, keeping the aspect ratio and even dimensions. Frames
+// already within the limits keep their size. A limit of 0 is ignored.
+func downscaleFilter(maxWidth, maxHeight int) string {
+	width, height := "iw", "ih"
+	if maxWidth > 0 {
+		width = fmt.Sprintf("'min(iw,%d)'", maxWidth)
+	}
+	if maxHeight > 0 {
+		height = fmt.Sprintf("'min(ih,%d)'", maxHeight)
+	}
+	return fmt.Sprintf("scale=w=%s:h=%s:force_original_aspect_ratio=decrease:force_divisible_by=2", width, height)
+}
+
+// audioFilters returns the FFmpeg audio filters a job asks for, in the order
+// they must be applied
+func audioFilters(job models.ConversionJob) []string {
+	var filters []string
+
+	if speed := speedFactor(job.Speed); speed != 1 {
+		filters = append(filters, atempoChain(speed)...)
+	}
+
+	return filters
+}
+
+const (
+	// minSpeed and maxSpeed bound the playback speed multiplier
+	minSpeed = 0.1
+	maxSpeed = 100.0
+)
+
+// speedFactor returns the job's playback speed multiplier clamped to the
+// supported range, treating 0 as unchanged
+func speedFactor(speed float64) float64 {
+	switch {
+	case speed <= 0:
+		return 1
+	case speed < minSpeed:
+		return minSpeed
+	case speed > maxSpeed:
+		return maxSpeed
+	}
+	return speed
+}
+
+// atempoChain returns atempo filters changing audio tempo by factor. A single
+// atempo only accepts 0.5-2.0, so larger changes are chained.
+func atempoChain(factor float64) []string {
+	var chain []string
+	for factor > 2 {
+		chain = append(chain, "atempo=2")
+		factor /= 2
+	}
+	for factor < 0.5 {
+		chain = append(chain, "atempo=0.5")
+		factor /= 0.5
+	}
+	if factor != 1 {
+		chain = append(chain, "atempo="+formatFactor(factor))
+	}
+	return chain
+}
+
+// formatFactor formats a filter multiplier without trailing zeros
+func formatFactor(factor float64) string {
+	return strconv.FormatFloat(factor, 'f', -1, 64)
+}
+
+// defaultTimecodeRate is the frame rate a timecode counts at when the
+// source's frame rate is unknown
+const defaultTimecodeRate = 25
+
+// burnInFilters returns the drawtext filters for a job's burn-in options.
+// Previews start their timecode where the preview starts in the source.
+func burnInFilters(job models.ConversionJob, probe *ffmpeg.Probe, log *logger.ComponentLogger) []string {
+	if job.BurnIn == nil {
+		return nil
+	}
+
+	var filters []string
+	if job.BurnIn.Timecode {
+		rate := float64(defaultTimecodeRate)
+		if probe != nil && probe.FrameRate > 0 {
+			rate = probe.FrameRate
+		} else {
+			log.Warn("Source frame rate unknown, counting timecode at %d fps", defaultTimecodeRate)
+		}
+
+		var duration time.Duration
+		if probe != nil {
+			duration = probe.Duration
+		}
+		start, _ := jobSpan(job, duration)
+		filters = append(filters, ffmpeg.DrawTextFilter(ffmpeg.DrawText{
+			Timecode: ffmpeg.FormatTimecode(start.Seconds(), rate),
+			Rate:     rate,
+		}))
+	}
+	if job.BurnIn.Filename {
+		filters = append(filters, ffmpeg.DrawTextFilter(ffmpeg.DrawText{
+			Text: filepath.Base(job.InputPath),
+			Top:  true,
+		}))
+	}
+	return filters
+}
+
+// deinterlaceFilter returns the deinterlacing filter for a mode, or "" if
+// the video should be left as-is
+func deinterlaceFilter(mode models.DeinterlaceMode, probe *ffmpeg.Probe, log *logger.ComponentLogger) string {
+	switch mode {
+	case models.DeinterlaceAuto:
+		if probe == nil || !probe.Interlaced() {
+			return ""
+		}
+		log.Info("Interlaced source detected (field order %s), deinterlacing", probe.FieldOrder)
+		return "yadif=mode=send_frame:parity=auto"
+	case models.DeinterlaceYadif:
+		return "yadif=mode=send_frame:parity=auto"
+	case models.DeinterlaceBwdif:
+		return "bwdif=mode=send_frame:parity=auto"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,47 @@
+package services
+
+import (
+	"fmt"
+
+	"converzen/internal/logger"
+	"converzen/internal/models"
+	"converzen/pkg/ffmpeg"
+)
+
+// qualityLevels maps quality levels to CRFs on x264's scale
+var qualityLevels = map[models.VideoQuality]int{
+	models.VideoQualityHigh:   ffmpeg.QualityHigh,
+	models.VideoQualityMedium: ffmpeg.QualityMedium,
+	models.VideoQualityLow:    ffmpeg.QualityLow,
+}
+
+// validateQuality checks a quality level and CRF before any file is converted
+func validateQuality(quality models.VideoQuality, crf int) error {
+	if _, ok := qualityLevels[quality]; quality != models.VideoQualityDefault && !ok {
+		return fmt.Errorf("unknown video quality %q", quality)
+	}
+	if crf != 0 && (crf < ffmpeg.MinQuality || crf > ffmpeg.MaxQuality) {
+		return fmt.Errorf("CRF must be between %d and %d, got %d", ffmpeg.MinQuality, ffmpeg.MaxQuality, crf)
+	}
+	return nil
+}
+
+// jobQuality returns the CRF a job encodes video at, or 0 for the
+// encoder's default. An exact CRF overrides the quality level.
+func jobQuality(job models.ConversionJob) int {
+	if job.CRF >= ffmpeg.MinQuality && job.CRF <= ffmpeg.MaxQuality {
+		return job.CRF
+	}
+	return qualityLevels[job.Quality]
+}
+
+// warnQuality logs when an encode can't honour the job's quality setting
+func warnQuality(log *logger.ComponentLogger, opts ffmpeg.ConvertOptions) {
+	switch {
+	case opts.Quality == 0:
+	case opts.VideoBitrate != "":
+		log.Info("Encoding to %s b/s video, ignoring the quality setting", opts.VideoBitrate)
+	case !ffmpeg.SupportsQuality(opts.VideoCodec):
+		log.Warn("%s has no quality setting, encoding at its default", opts.VideoCodec)
+	}
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"embed"
+	"os"
 
 	"github.com/wailsapp/wails/v2"
 	"github.com/wailsapp/wails/v2/pkg/options"
@@ -14,6 +15,11 @@ import (
 var assets embed.FS
 
 func main() {
+	// Command-line subcommands run without the window
+	if code, ok := runCLI(os.Args[1:]); ok {
+		os.Exit(code)
+	}
+
 	// Create an instance of the app structure
 	app := NewApp()
 
@@ -0,0 +1,140 @@
+package calibre
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"converzen/internal/logger"
+)
+
+// Calibre wraps Calibre's ebook-convert command
+type Calibre struct {
+	path string
+	log  *logger.ComponentLogger
+}
+
+// New creates a new Calibre instance
+func New(ebookConvertPath string, log *logger.Logger) *Calibre {
+	return &Calibre{
+		path: ebookConvertPath,
+		log:  log.WithComponent("calibre"),
+	}
+}
+
+// Find returns the path to the ebook-convert executable, checking common
+// install locations and then PATH. It returns an empty string if none is found.
+func Find() string {
+	var candidates []string
+
+	switch runtime.GOOS {
+	case "windows":
+		candidates = []string{
+			filepath.Join(os.Getenv("ProgramFiles"), "Calibre2", "ebook-convert.exe"),
+			filepath.Join(os.Getenv("ProgramFiles(x86)"), "Calibre2", "ebook-convert.exe"),
+		}
+	case "darwin":
+		candidates = []string{
+			"/Applications/calibre.app/Contents/MacOS/ebook-convert",
+		}
+	default:
+		candidates = []string{
+			"/usr/bin/ebook-convert",
+			"/usr/local/bin/ebook-convert",
+			"/opt/calibre/ebook-convert",
+		}
+	}
+
+	for _, path := range candidates {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+
+	if path, err := exec.LookPath("ebook-convert"); err == nil {
+		return path
+	}
+	return ""
+}
+
+// IsAvailable checks if ebook-convert is available on the system
+func (c *Calibre) IsAvailable() bool {
+	if c.path == "" {
+		return false
+	}
+	if err := exec.Command(c.path, "--version").Run(); err != nil {
+		c.log.Error("Calibre is not available: %v", err)
+		return false
+	}
+	c.log.Info("Calibre is available at: %s", c.path)
+	return true
+}
+
+// GetVersion returns the ebook-convert version
+func (c *Calibre) GetVersion() (string, error) {
+	if c.path == "" {
+		return "", fmt.Errorf("Calibre not found")
+	}
+
+	output, err := exec.Command(c.path, "--version").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get Calibre version: %w", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	return strings.TrimSpace(lines[0]), nil
+}
+
+// progressRe matches ebook-convert progress lines, e.g. "34% Running transforms on e-book..."
+var progressRe = regexp.MustCompile(`^(\d{1,3})% `)
+
+// Convert converts an e-book to the format given by outputPath's extension,
+// e.g. "book.mobi". ebook-convert writes to outputPath directly.
+func (c *Calibre) Convert(ctx context.Context, inputPath, outputPath string, progressCallback func(progress float64)) error {
+	if c.path == "" {
+		return fmt.Errorf("Calibre not found")
+	}
+
+	c.log.Info("Converting e-book: %s -> %s", inputPath, outputPath)
+	c.log.Debug("Calibre command: %s %s %s", c.path, inputPath, outputPath)
+
+	cmd := exec.CommandContext(ctx, c.path, inputPath, outputPath)
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("failed to get stdout pipe: %w", err)
+	}
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start ebook-convert: %w", err)
+	}
+
+	scanner := bufio.NewScanner(stdout)
+	for scanner.Scan() {
+		if matches := progressRe.FindStringSubmatch(scanner.Text()); len(matches) == 2 && progressCallback != nil {
+			progress, _ := strconv.Atoi(matches[1])
+			progressCallback(float64(min(progress, 100)))
+		}
+	}
+
+	if err := cmd.Wait(); err != nil {
+		c.log.Error("Calibre conversion failed: %v: %s", err, stderr.String())
+		return fmt.Errorf("conversion failed: %w", err)
+	}
+
+	if _, err := os.Stat(outputPath); err != nil {
+		return fmt.Errorf("Calibre produced no output for %s", filepath.Base(inputPath))
+	}
+
+	c.log.Info("E-book conversion completed: %s", outputPath)
+	return nil
+}
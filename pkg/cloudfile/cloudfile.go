@@ -0,0 +1,57 @@
+// Package cloudfile handles online-only files of cloud storage providers
+// such as iCloud Drive, OneDrive and Dropbox. Their placeholders report
+// the full file size but hold no data until read, so tools that open them
+// see permission errors, time out or read nothing.
+package cloudfile
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+)
+
+// readBufferSize is the size of the chunks a placeholder is read in
+const readBufferSize = 1 << 20 // 1 MiB
+
+// Materialize makes the provider download a placeholder's contents by
+// reading it through, reporting the fraction read (0-1) to progress.
+// Providers may fetch the whole file on the first read, in which case
+// progress jumps once the download completes.
+func Materialize(ctx context.Context, path string, progress func(fraction float64)) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	stat, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	buffer := make([]byte, readBufferSize)
+	var read int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		n, err := file.Read(buffer)
+		read += int64(n)
+		if progress != nil && stat.Size() > 0 {
+			progress(float64(read) / float64(stat.Size()))
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if read < stat.Size() {
+		return errors.New("the download ended early")
+	}
+	return nil
+}
@@ -0,0 +1,16 @@
+package cloudfile
+
+import "syscall"
+
+// sfDataless is the st_flags bit of files whose data is held by a file
+// provider, such as iCloud Drive or apps using File Provider
+const sfDataless = 0x40000000
+
+// IsPlaceholder reports whether path is an online-only (dataless) file
+func IsPlaceholder(path string) (bool, error) {
+	var stat syscall.Stat_t
+	if err := syscall.Stat(path, &stat); err != nil {
+		return false, err
+	}
+	return stat.Flags&sfDataless != 0, nil
+}
@@ -0,0 +1,9 @@
+//go:build !darwin && !windows
+
+package cloudfile
+
+// IsPlaceholder reports whether path is an online-only cloud file. Other
+// platforms have no placeholder files.
+func IsPlaceholder(path string) (bool, error) {
+	return false, nil
+}
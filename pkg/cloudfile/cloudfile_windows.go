@@ -0,0 +1,24 @@
+package cloudfile
+
+import "syscall"
+
+// File attributes of cloud files whose data isn't on disk
+const (
+	fileAttributeOffline            = 0x00001000
+	fileAttributeRecallOnOpen       = 0x00040000
+	fileAttributeRecallOnDataAccess = 0x00400000
+)
+
+// IsPlaceholder reports whether path is an online-only cloud file, e.g. a
+// OneDrive "available when online" file
+func IsPlaceholder(path string) (bool, error) {
+	name, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return false, err
+	}
+	attributes, err := syscall.GetFileAttributes(name)
+	if err != nil {
+		return false, err
+	}
+	return attributes&(fileAttributeOffline|fileAttributeRecallOnOpen|fileAttributeRecallOnDataAccess) != 0, nil
+}
@@ -0,0 +1,79 @@
+package ffmpeg
+
+import (
+	"os/exec"
+	"sync"
+)
+
+// processTracker records running FFmpeg processes so their priority can be
+// changed while they run
+type processTracker struct {
+	mu         sync.Mutex
+	background bool
+	running    map[int]bool // PID -> started at low priority
+}
+
+// SetBackground lowers the priority of running and future FFmpeg processes
+// while the app is in the background, and restores it when the app returns.
+// Processes started at low priority keep it either way.
+func (f *FFmpeg) SetBackground(background bool) {
+	f.processes.mu.Lock()
+	defer f.processes.mu.Unlock()
+
+	if f.processes.background == background {
+		return
+	}
+	f.processes.background = background
+
+	for pid, low := range f.processes.running {
+		if low {
+			continue
+		}
+		if err := setBackgroundPriority(pid, background); err != nil {
+			f.log.Debug("Failed to change priority of FFmpeg process %d: %v", pid, err)
+			continue
+		}
+		f.log.Debug("FFmpeg process %d in background: %t", pid, background)
+	}
+}
+
+// applyPriority lowers the OS priority of a started FFmpeg process when
+// requested or while the app is in the background, and tracks the process
+// until releaseProcess is called
+func (f *FFmpeg) applyPriority(cmd *exec.Cmd, low bool) {
+	if cmd.Process == nil {
+		return
+	}
+
+	f.processes.mu.Lock()
+	defer f.processes.mu.Unlock()
+
+	if f.processes.running == nil {
+		f.processes.running = make(map[int]bool)
+	}
+	f.processes.running[cmd.Process.Pid] = low
+
+	switch {
+	case low:
+		if err := setProcessPriority(cmd.Process.Pid, true); err != nil {
+			f.log.Warn("Failed to lower FFmpeg process priority: %v", err)
+			return
+		}
+		f.log.Debug("Running FFmpeg process %d at low priority", cmd.Process.Pid)
+	case f.processes.background:
+		if err := setBackgroundPriority(cmd.Process.Pid, true); err != nil {
+			f.log.Debug("Failed to lower FFmpeg process priority: %v", err)
+		}
+	}
+}
+
+// releaseProcess stops tracking an FFmpeg process once it has exited
+func (f *FFmpeg) releaseProcess(cmd *exec.Cmd) {
+	if cmd.Process == nil {
+		return
+	}
+
+	f.processes.mu.Lock()
+	defer f.processes.mu.Unlock()
+	delete(f.processes.running, cmd.Process.Pid)
+}
@@ -0,0 +1,21 @@
+//go:build darwin
+
+package ffmpeg
+
+import "syscall"
+
+// Darwin's background scheduling policy, which also throttles disk I/O.
+// Unlike niceness, unprivileged processes can clear it again.
+const (
+	prioDarwinProcess = 4
+	prioDarwinBG      = 0x1000
+)
+
+// setBackgroundPriority moves a process into or out of the background policy
+func setBackgroundPriority(pid int, background bool) error {
+	prio := 0
+	if background {
+		prio = prioDarwinBG
+	}
+	return syscall.Setpriority(prioDarwinProcess, pid, prio)
+}
@@ -0,0 +1,10 @@
+//go:build !darwin
+
+package ffmpeg
+
+// setBackgroundPriority lowers or restores the priority of a process. On
+// Linux, unprivileged processes can't restore a lowered niceness, so a
+// process stays at low priority once the app has been in the background.
+func setBackgroundPriority(pid int, background bool) error {
+	return setProcessPriority(pid, background)
+}
@@ -0,0 +1,142 @@
+package ffmpeg
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Benchmark sample: a 1080p test pattern with film-like grain, generated by
+// FFmpeg's built-in lavfi sources so every machine encodes the same frames
+const (
+	benchmarkSize      = "1920x1080"
+	benchmarkFrameRate = 30
+	benchmarkSeconds   = 4
+	benchmarkFrames    = benchmarkFrameRate * benchmarkSeconds
+	benchmarkSource    = "testsrc2=size=" + benchmarkSize + ":rate=30,noise=alls=12:allf=t+u"
+)
+
+// BenchmarkEncoders lists the H.264 and HEVC encoders worth benchmarking,
+// software encoders first. Hardware encoders FFmpeg was built with may
+// still fail on machines without the hardware.
+var BenchmarkEncoders = []string{
+	"libx264", "h264_videotoolbox", "h264_nvenc", "h264_qsv", "h264_amf",
+	"libx265", "hevc_videotoolbox", "hevc_nvenc", "hevc_qsv", "hevc_amf",
+}
+
+// EncoderCodec returns the codec an encoder produces, "h264" or "hevc",
+// or "" for other encoders
+func EncoderCodec(encoder string) string {
+	switch {
+	case encoder == "libx264" || strings.HasPrefix(encoder, "h264_"):
+		return "h264"
+	case encoder == "libx265" || strings.HasPrefix(encoder, "hevc_"):
+		return "hevc"
+	}
+	return ""
+}
+
+// IsHardwareEncoder reports whether an encoder runs on a GPU or media engine
+func IsHardwareEncoder(encoder string) bool {
+	return strings.HasPrefix(encoder, "h264_") || strings.HasPrefix(encoder, "hevc_")
+}
+
+// BenchmarkResult is one encoder's run over the benchmark sample
+type BenchmarkResult struct {
+	Encoder string
+	Elapsed time.Duration
+	FPS     float64 // Frames encoded per second
+	Speed   float64 // Multiple of real time
+	Size    int64   // Output size in bytes
+	SSIM    float64 // Structural similarity to the sample, 0-1 (1 = identical)
+}
+
+// ssimRe matches the summary of the ssim filter, e.g. "SSIM Y:0.98 ... All:0.978823 (16.74)"
+var ssimRe = regexp.MustCompile(`SSIM .*All:([\d.]+)`)
+
+// CreateBenchmarkSample writes the benchmark sample losslessly to path, a
+// .mkv file, so decoding it costs little next to the encoders measured
+func (f *FFmpeg) CreateBenchmarkSample(ctx context.Context, path string) error {
+	cmd := f.runner.Command(ctx, f.path,
+		"-hide_banner", "-nostdin", "-v", "error", "-y",
+		"-f", "lavfi", "-i", benchmarkSource,
+		"-frames:v", strconv.Itoa(benchmarkFrames),
+		"-c:v", "ffv1", "-pix_fmt", "yuv420p",
+		path,
+	)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("failed to create benchmark sample: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// BenchmarkEncoder encodes the sample at samplePath to outputPath with the
+// encoder's default settings, the ones conversions use, and measures its
+// speed, output size and SSIM against the sample
+func (f *FFmpeg) BenchmarkEncoder(ctx context.Context, samplePath, outputPath, encoder string) (*BenchmarkResult, error) {
+	f.log.Debug("Benchmarking encoder %s", encoder)
+
+	start := time.Now()
+	cmd := f.runner.Command(ctx, f.path,
+		"-hide_banner", "-nostdin", "-v", "error", "-y",
+		"-i", samplePath,
+		"-an", "-c:v", encoder, "-pix_fmt", "yuv420p",
+		outputPath,
+	)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("%s failed: %w: %s", encoder, err, strings.TrimSpace(string(output)))
+	}
+	elapsed := time.Since(start)
+
+	stat, err := os.Stat(outputPath)
+	if err != nil {
+		return nil, fmt.Errorf("%s wrote no output: %w", encoder, err)
+	}
+
+	ssim, err := f.measureSSIM(ctx, outputPath, samplePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BenchmarkResult{
+		Encoder: encoder,
+		Elapsed: elapsed,
+		FPS:     float64(benchmarkFrames) / elapsed.Seconds(),
+		Speed:   float64(benchmarkSeconds) / elapsed.Seconds(),
+		Size:    stat.Size(),
+		SSIM:    ssim,
+	}, nil
+}
+
+// measureSSIM compares an encode with its reference through the ssim filter
+func (f *FFmpeg) measureSSIM(ctx context.Context, encodedPath, referencePath string) (float64, error) {
+	cmd := f.runner.Command(ctx, f.path,
+		"-hide_banner", "-nostdin", "-nostats",
+		"-i", encodedPath, "-i", referencePath,
+		"-lavfi", "[0:v][1:v]ssim",
+		"-f", "null", "-",
+	)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		if ctx.Err() != nil {
+			return 0, ctx.Err()
+		}
+		return 0, fmt.Errorf("SSIM measurement failed: %w", err)
+	}
+
+	matches := ssimRe.FindStringSubmatch(string(output))
+	if len(matches) != 2 {
+		return 0, fmt.Errorf("SSIM measurement produced no summary")
+	}
+	return strconv.ParseFloat(matches[1], 64)
+}
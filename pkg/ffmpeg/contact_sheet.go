@@ -0,0 +1,94 @@
+package ffmpeg
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ContactSheetOptions lays out a contact sheet
+type ContactSheetOptions struct {
+	Columns    int
+	Rows       int
+	Width      int  // Width of each frame in pixels
+	Timestamps bool // Draw each frame's position on it
+}
+
+// contactSheetSpacing is the gap between frames and around the sheet, in pixels
+const contactSheetSpacing = 4
+
+// ContactSheet writes a grid of Columns x Rows frames, evenly spaced through
+// the video, to outputPath as a single image. Each frame is found with its
+// own fast seek, so long recordings aren't decoded in full. The image
+// format follows outputPath's extension.
+func (f *FFmpeg) ContactSheet(ctx context.Context, inputPath, outputPath string, opts ContactSheetOptions) error {
+	probe, err := f.ProbeFile(inputPath)
+	if err != nil {
+		return fmt.Errorf("failed to probe video: %w", err)
+	}
+	if probe.Duration <= 0 || probe.Width <= 0 || probe.Height <= 0 {
+		return fmt.Errorf("the video's duration or frame size is unknown")
+	}
+
+	// Even frame height keeping the source's aspect ratio
+	height := opts.Width * probe.Height / probe.Width
+	height += height % 2
+	count := opts.Columns * opts.Rows
+
+	f.log.Info("Creating %dx%d contact sheet: %s -> %s", opts.Columns, opts.Rows, inputPath, outputPath)
+
+	args := []string{"-y", "-hide_banner", "-nostdin"}
+	var filters, labels []string
+	for i := 0; i < count; i++ {
+		// Take each frame from the middle of its slice of the video
+		at := probe.Duration * time.Duration(2*i+1) / time.Duration(2*count)
+		args = append(args, "-ss", formatSeconds(at), "-i", inputPath)
+
+		chain := []string{
+			"trim=end_frame=1",
+			"setpts=PTS-STARTPTS",
+			fmt.Sprintf("scale=%d:%d:force_original_aspect_ratio=decrease", opts.Width, height),
+			fmt.Sprintf("pad=%d:%d:(ow-iw)/2:(oh-ih)/2", opts.Width, height),
+			"setsar=1",
+		}
+		if opts.Timestamps {
+			chain = append(chain, DrawTextFilter(DrawText{
+				Text:     formatPosition(at),
+				FontSize: max(height/10, 10),
+			}))
+		}
+		label := fmt.Sprintf("[f%d]", i)
+		filters = append(filters, fmt.Sprintf("[%d:v:0]%s%s", i, strings.Join(chain, ","), label))
+		labels = append(labels, label)
+	}
+	filters = append(filters, fmt.Sprintf("%sconcat=n=%d:v=1:a=0,tile=%dx%d:padding=%d:margin=%d[sheet]",
+		strings.Join(labels, ""), count, opts.Columns, opts.Rows, contactSheetSpacing, contactSheetSpacing))
+
+	args = append(args, "-filter_complex", strings.Join(filters, ";"), "-map", "[sheet]", "-frames:v", "1")
+	switch strings.ToLower(filepath.Ext(outputPath)) {
+	case ".jpg", ".jpeg":
+		args = append(args, "-q:v", "2")
+	}
+	args = append(args, "-update", "1", outputPath)
+
+	f.log.Debug("FFmpeg command: %s %s", f.path, strings.Join(args, " "))
+
+	output, err := f.runner.Command(ctx, f.path, args...).CombinedOutput()
+	if err != nil {
+		f.log.Error("Contact sheet failed: %v: %s", err, string(output))
+		return fmt.Errorf("contact sheet failed: %w", err)
+	}
+	return nil
+}
+
+// formatPosition formats a position in a video as H:MM:SS, or M:SS under
+// an hour
+func formatPosition(d time.Duration) string {
+	seconds := int(d.Seconds())
+	if seconds >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", seconds/3600, seconds/60%60, seconds%60)
+	}
+	return fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+}
@@ -0,0 +1,114 @@
+package ffmpeg
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// fontCandidates are common system fonts, per OS, used for burned-in text.
+// FFmpeg falls back to fontconfig's default font when none exists.
+var fontCandidates = map[string][]string{
+	"darwin": {
+		"/System/Library/Fonts/Helvetica.ttc",
+		"/System/Library/Fonts/Supplemental/Arial.ttf",
+		"/Library/Fonts/Arial.ttf",
+	},
+	"windows": {
+		`C:\Windows\Fonts\arial.ttf`,
+		`C:\Windows\Fonts\segoeui.ttf`,
+	},
+	"linux": {
+		"/usr/share/fonts/truetype/dejavu/DejaVuSans.ttf",
+		"/usr/share/fonts/dejavu/DejaVuSans.ttf",
+		"/usr/share/fonts/TTF/DejaVuSans.ttf",
+		"/usr/share/fonts/truetype/liberation/LiberationSans-Regular.ttf",
+	},
+}
+
+// DrawText describes text burned into a video by the drawtext filter
+type DrawText struct {
+	// Text is drawn literally, without drawtext's %{...} expansion
+	Text string
+
+	// Timecode, when set, draws a running timecode starting at this value
+	// ("HH:MM:SS:FF") instead of Text, counting frames at Rate
+	Timecode string
+	Rate     float64
+
+	// Top draws the text at the top of the frame instead of the bottom
+	Top bool
+
+	// FontSize in pixels; 0 sizes the text relative to the frame height
+	FontSize int
+}
+
+// DrawTextFilter returns a drawtext filter drawing white text on a
+// translucent box, centered horizontally. Requires an FFmpeg built with libfreetype.
+func DrawTextFilter(text DrawText) string {
+	options := []string{}
+	if font := defaultFontFile(); font != "" {
+		options = append(options, "fontfile="+filterValue(font))
+	}
+	if text.Timecode != "" {
+		options = append(options,
+			"timecode="+filterValue(text.Timecode),
+			"rate="+strconv.FormatFloat(text.Rate, 'f', -1, 64),
+		)
+	} else {
+		options = append(options, "expansion=none", "text="+filterValue(text.Text))
+	}
+
+	y := "h-th-h/20"
+	if text.Top {
+		y = "h/20"
+	}
+	fontSize := "h/24"
+	if text.FontSize > 0 {
+		fontSize = strconv.Itoa(text.FontSize)
+	}
+	options = append(options,
+		"fontsize="+fontSize,
+		"fontcolor=white",
+		"box=1",
+		"boxcolor=black@0.5",
+		"boxborderw=8",
+		"x=(w-tw)/2",
+		"y="+y,
+	)
+	return "drawtext=" + strings.Join(options, ":")
+}
+
+// defaultFontFile returns the first system font found for this OS, or "" if
+// there is none
+func defaultFontFile() string {
+	for _, path := range fontCandidates[runtime.GOOS] {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+// filterValue quotes an arbitrary string as a filter option value. The
+// filtergraph and the filter's option parser each remove a level of
+// escaping, so special characters are escaped for the option parser first
+// and the result quoted for the filtergraph.
+func filterValue(value string) string {
+	escaped := strings.NewReplacer(`\`, `\\`, `'`, `\'`, `:`, `\:`).Replace(value)
+	return "'" + strings.ReplaceAll(escaped, "'", `'\''`) + "'"
+}
+
+// FormatTimecode formats a position as a non-drop-frame "HH:MM:SS:FF"
+// timecode at the given frame rate
+func FormatTimecode(seconds, rate float64) string {
+	fps := int(rate + 0.5)
+	if fps < 1 {
+		fps = 1
+	}
+	frames := int(seconds*rate + 0.5)
+	return fmt.Sprintf("%02d:%02d:%02d:%02d",
+		frames/(fps*3600), frames/(fps*60)%60, frames/fps%60, frames%fps)
+}
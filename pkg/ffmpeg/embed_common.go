@@ -3,19 +3,27 @@
 package ffmpeg
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"io/fs"
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"sync"
+	"time"
 )
 
 var (
 	extractedPath string
 	extractOnce   sync.Once
 	extractErr    error
+
+	// extractStatus describes what the last extraction found and did
+	extractStatus string
 )
 
 // GetEmbeddedFFmpegPath extracts the embedded FFmpeg binary to the app data directory
@@ -23,13 +31,14 @@ var (
 // and cached for subsequent calls.
 func GetEmbeddedFFmpegPath(appDataDir string) (string, error) {
 	extractOnce.Do(func() {
-		extractedPath, extractErr = extractFFmpeg(appDataDir)
+		extractedPath, extractErr = extractFFmpeg(appDataDir, ExecRunner{})
 	})
 	return extractedPath, extractErr
 }
 
-// extractFFmpeg extracts the appropriate FFmpeg binary for the current platform
-func extractFFmpeg(appDataDir string) (string, error) {
+// extractFFmpeg extracts the appropriate FFmpeg binary for the current
+// platform, validating it with runner
+func extractFFmpeg(appDataDir string, runner Runner) (string, error) {
 	// Check if the embedded binary exists
 	file, err := embeddedBinary.Open(embeddedBinaryPath)
 	if err != nil {
@@ -45,65 +54,159 @@ func extractFFmpeg(appDataDir string) (string, error) {
 
 	destPath := filepath.Join(binDir, embeddedBinaryName)
 
-	// Check if we need to extract (binary doesn't exist or is outdated)
-	if needsExtraction(destPath) {
-		if err := extractBinary(destPath); err != nil {
-			return "", err
-		}
+	checksum, err := expectedChecksum()
+	if err != nil {
+		return "", err
 	}
 
+	// Extract when the binary is missing, outdated, truncated or modified
+	problem := verifyExtracted(destPath, checksum)
+	if problem == "" {
+		extractStatus = "verified " + destPath
+		return destPath, nil
+	}
+
+	if err := extractBinary(destPath, checksum); err != nil {
+		extractStatus = fmt.Sprintf("extraction failed (%s): %v", problem, err)
+		return "", err
+	}
+
+	// Make sure the extracted binary runs on this machine, so a broken or
+	// mismatched build falls back to a system FFmpeg instead
+	if err := validateBinary(runner, destPath); err != nil {
+		os.Remove(destPath)
+		extractStatus = fmt.Sprintf("extracted binary failed validation: %v", err)
+		return "", err
+	}
+
+	extractStatus = fmt.Sprintf("extracted to %s (%s)", destPath, problem)
 	return destPath, nil
 }
 
-// needsExtraction checks if the binary needs to be extracted
-func needsExtraction(destPath string) bool {
+// expectedChecksum reads the SHA-256 of the embedded binary from the manifest
+// compiled in next to it
+func expectedChecksum() (string, error) {
+	manifest, err := embeddedBinary.ReadFile(embeddedChecksumPath)
+	if err != nil {
+		return "", fmt.Errorf("embedded FFmpeg checksum manifest not found: %w", err)
+	}
+
+	// Accept both a bare hash and sha256sum's "<hash>  <file>" format
+	fields := strings.Fields(string(manifest))
+	if len(fields) == 0 || len(fields[0]) != sha256.Size*2 {
+		return "", fmt.Errorf("embedded FFmpeg checksum manifest is malformed")
+	}
+	return strings.ToLower(fields[0]), nil
+}
+
+// verifyExtracted checks an extracted binary against the expected checksum,
+// returning "" if it can be trusted or a description of the problem
+func verifyExtracted(destPath, checksum string) string {
 	destInfo, err := os.Stat(destPath)
 	if os.IsNotExist(err) {
-		return true
+		return "binary missing"
 	}
 	if err != nil {
-		return true
+		return fmt.Sprintf("binary unreadable: %v", err)
 	}
 
-	// Get embedded file info
-	embeddedFile, err := embeddedBinary.Open(embeddedBinaryPath)
+	if embeddedInfo, err := fs.Stat(embeddedBinary, embeddedBinaryPath); err == nil && destInfo.Size() < embeddedInfo.Size() {
+		return fmt.Sprintf("binary truncated to %d of %d bytes", destInfo.Size(), embeddedInfo.Size())
+	}
+
+	actual, err := fileChecksum(destPath)
 	if err != nil {
-		return true
+		return fmt.Sprintf("binary unreadable: %v", err)
 	}
-	defer embeddedFile.Close()
+	if actual != checksum {
+		return "checksum mismatch, binary is outdated or was modified"
+	}
+	return ""
+}
 
-	embeddedInfo, err := embeddedFile.(fs.File).Stat()
+// fileChecksum returns the hex SHA-256 of a file
+func fileChecksum(path string) (string, error) {
+	file, err := os.Open(path)
 	if err != nil {
-		return true
+		return "", err
 	}
+	defer file.Close()
 
-	// Re-extract if sizes differ (simple check for updates)
-	return destInfo.Size() != embeddedInfo.Size()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
 }
 
-// extractBinary extracts the embedded binary to the destination path
-func extractBinary(destPath string) error {
+// extractBinary extracts the embedded binary to the destination path. The
+// binary is written to a temporary file first and renamed into place once its
+// checksum matches, so an interrupted extraction never leaves a truncated
+// binary behind.
+func extractBinary(destPath, checksum string) error {
 	embeddedFile, err := embeddedBinary.Open(embeddedBinaryPath)
 	if err != nil {
 		return fmt.Errorf("failed to open embedded binary: %w", err)
 	}
 	defer embeddedFile.Close()
 
-	// Create destination file with executable permissions
-	destFile, err := os.OpenFile(destPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0755)
+	tempFile, err := os.CreateTemp(filepath.Dir(destPath), embeddedBinaryName+".*.tmp")
 	if err != nil {
 		return fmt.Errorf("failed to create destination file: %w", err)
 	}
-	defer destFile.Close()
+	tempPath := tempFile.Name()
+	defer os.Remove(tempPath)
 
-	// Copy the binary
-	if _, err := io.Copy(destFile, embeddedFile); err != nil {
+	// Copy the binary, hashing it on the way
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(tempFile, hash), embeddedFile); err != nil {
+		tempFile.Close()
+		return fmt.Errorf("failed to extract binary: %w", err)
+	}
+	if err := tempFile.Close(); err != nil {
 		return fmt.Errorf("failed to extract binary: %w", err)
 	}
+	if actual := hex.EncodeToString(hash.Sum(nil)); actual != checksum {
+		return fmt.Errorf("embedded FFmpeg binary does not match its checksum manifest")
+	}
+
+	// CreateTemp creates files without execute permission
+	if runtime.GOOS != "windows" {
+		if err := os.Chmod(tempPath, 0755); err != nil {
+			return fmt.Errorf("failed to make binary executable: %w", err)
+		}
+	}
+
+	// Windows can't rename over an existing file
+	if runtime.GOOS == "windows" {
+		os.Remove(destPath)
+	}
+	if err := os.Rename(tempPath, destPath); err != nil {
+		return fmt.Errorf("failed to install binary: %w", err)
+	}
 
 	return nil
 }
 
+// validationTimeout bounds the first run of an extracted binary
+const validationTimeout = 15 * time.Second
+
+// validateBinary runs an extracted binary with -version to check it works
+// on this machine
+func validateBinary(runner Runner, path string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), validationTimeout)
+	defer cancel()
+
+	output, err := runner.Command(ctx, path, "-hide_banner", "-version").Output()
+	if err != nil {
+		return fmt.Errorf("embedded FFmpeg binary does not run on %s/%s: %w", runtime.GOOS, runtime.GOARCH, err)
+	}
+	if !strings.HasPrefix(string(output), "ffmpeg version") {
+		return fmt.Errorf("embedded FFmpeg binary returned unexpected version output")
+	}
+	return nil
+}
+
 // HasEmbeddedFFmpeg checks if there's an embedded FFmpeg binary for the current platform
 func HasEmbeddedFFmpeg() bool {
 	file, err := embeddedBinary.Open(embeddedBinaryPath)
@@ -122,6 +225,12 @@ func HasEmbeddedFFmpeg() bool {
 	return info.Size() > 1024*1024
 }
 
+// EmbeddedFFmpegStatus describes what GetEmbeddedFFmpegPath found and did,
+// e.g. that the extracted binary was verified or re-extracted and why
+func EmbeddedFFmpegStatus() string {
+	return extractStatus
+}
+
 // GetSupportedPlatforms returns the current platform (only one is embedded per build)
 func GetSupportedPlatforms() []string {
 	return []string{fmt.Sprintf("%s_%s", runtime.GOOS, runtime.GOARCH)}
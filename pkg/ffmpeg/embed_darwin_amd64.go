@@ -6,8 +6,9 @@ import (
 	"embed"
 )
 
-//go:embed binaries/darwin_amd64/ffmpeg
+//go:embed binaries/darwin_amd64/ffmpeg binaries/darwin_amd64/ffmpeg.sha256
 var embeddedBinary embed.FS
 
 const embeddedBinaryPath = "binaries/darwin_amd64/ffmpeg"
+const embeddedChecksumPath = "binaries/darwin_amd64/ffmpeg.sha256"
 const embeddedBinaryName = "ffmpeg"
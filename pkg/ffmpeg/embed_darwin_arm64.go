@@ -6,8 +6,9 @@ import (
 	"embed"
 )
 
-//go:embed binaries/darwin_arm64/ffmpeg
+//go:embed binaries/darwin_arm64/ffmpeg binaries/darwin_arm64/ffmpeg.sha256
 var embeddedBinary embed.FS
 
 const embeddedBinaryPath = "binaries/darwin_arm64/ffmpeg"
+const embeddedChecksumPath = "binaries/darwin_arm64/ffmpeg.sha256"
 const embeddedBinaryName = "ffmpeg"
@@ -6,8 +6,9 @@ import (
 	"embed"
 )
 
-//go:embed binaries/linux_amd64/ffmpeg
+//go:embed binaries/linux_amd64/ffmpeg binaries/linux_amd64/ffmpeg.sha256
 var embeddedBinary embed.FS
 
 const embeddedBinaryPath = "binaries/linux_amd64/ffmpeg"
+const embeddedChecksumPath = "binaries/linux_amd64/ffmpeg.sha256"
 const embeddedBinaryName = "ffmpeg"
@@ -0,0 +1,14 @@
+//go:build embed_ffmpeg && linux && arm64
+
+package ffmpeg
+
+import (
+	"embed"
+)
+
+//go:embed binaries/linux_arm64/ffmpeg binaries/linux_arm64/ffmpeg.sha256
+var embeddedBinary embed.FS
+
+const embeddedBinaryPath = "binaries/linux_arm64/ffmpeg"
+const embeddedChecksumPath = "binaries/linux_arm64/ffmpeg.sha256"
+const embeddedBinaryName = "ffmpeg"
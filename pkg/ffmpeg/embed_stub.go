@@ -14,6 +14,12 @@ func HasEmbeddedFFmpeg() bool {
 	return false
 }
 
+// EmbeddedFFmpegStatus returns "" when FFmpeg is not embedded.
+// Build with -tags embed_ffmpeg to include embedded FFmpeg binaries.
+func EmbeddedFFmpegStatus() string {
+	return ""
+}
+
 // GetSupportedPlatforms returns an empty list when FFmpeg is not embedded.
 // Build with -tags embed_ffmpeg to include embedded FFmpeg binaries.
 func GetSupportedPlatforms() []string {
@@ -6,8 +6,9 @@ import (
 	"embed"
 )
 
-//go:embed binaries/windows_amd64/ffmpeg.exe
+//go:embed binaries/windows_amd64/ffmpeg.exe binaries/windows_amd64/ffmpeg.exe.sha256
 var embeddedBinary embed.FS
 
 const embeddedBinaryPath = "binaries/windows_amd64/ffmpeg.exe"
+const embeddedChecksumPath = "binaries/windows_amd64/ffmpeg.exe.sha256"
 const embeddedBinaryName = "ffmpeg.exe"
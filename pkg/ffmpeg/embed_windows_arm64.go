@@ -0,0 +1,14 @@
+//go:build embed_ffmpeg && windows && arm64
+
+package ffmpeg
+
+import (
+	"embed"
+)
+
+//go:embed binaries/windows_arm64/ffmpeg.exe binaries/windows_arm64/ffmpeg.exe.sha256
+var embeddedBinary embed.FS
+
+const embeddedBinaryPath = "binaries/windows_arm64/ffmpeg.exe"
+const embeddedChecksumPath = "binaries/windows_arm64/ffmpeg.exe.sha256"
+const embeddedBinaryName = "ffmpeg.exe"
@@ -0,0 +1,65 @@
+package ffmpeg
+
+import (
+	"context"
+	"regexp"
+	"sync"
+)
+
+// encoderList caches the encoders the FFmpeg binary was built with
+type encoderList struct {
+	once     sync.Once
+	encoders map[string]bool
+}
+
+// encoderLineRe matches an encoder entry in `ffmpeg -encoders` output,
+// e.g. " V....D prores_ks            Apple ProRes (iCodec Pro) (codec prores)"
+var encoderLineRe = regexp.MustCompile(`(?m)^ [VAS][F.][S.][X.][B.][D.] (\S+)`)
+
+// HasEncoder reports whether FFmpeg was built with the named encoder,
+// e.g. "prores_ks". The encoder list is read once and cached.
+func (f *FFmpeg) HasEncoder(name string) bool {
+	f.encoders.once.Do(func() {
+		f.encoders.encoders = make(map[string]bool)
+
+		output, err := f.runner.Command(context.Background(), f.path, "-hide_banner", "-encoders").Output()
+		if err != nil {
+			f.log.Warn("Could not list FFmpeg encoders: %v", err)
+			return
+		}
+		for _, matches := range encoderLineRe.FindAllStringSubmatch(string(output), -1) {
+			f.encoders.encoders[matches[1]] = true
+		}
+		f.log.Debug("FFmpeg has %d encoders", len(f.encoders.encoders))
+	})
+	return f.encoders.encoders[name]
+}
+
+// preferredEncoders holds the encoders DefaultCodec uses in place of the
+// software encoder of a codec, e.g. "h264_videotoolbox" for "h264"
+type preferredEncoders struct {
+	mu      sync.RWMutex
+	byCodec map[string]string
+}
+
+// SetPreferredEncoders sets the encoder to use per codec ("h264" or "hevc")
+// where the output format's default is that codec's software encoder, e.g.
+// from an encoder benchmark. nil restores the software encoders.
+func (f *FFmpeg) SetPreferredEncoders(encoders map[string]string) {
+	f.preferred.mu.Lock()
+	defer f.preferred.mu.Unlock()
+	f.preferred.byCodec = encoders
+}
+
+// DefaultCodec returns the default codecs for an output format like
+// GetDefaultCodec, with the video encoder replaced by the preferred one
+func (f *FFmpeg) DefaultCodec(format string) (videoCodec, audioCodec string) {
+	videoCodec, audioCodec = GetDefaultCodec(format)
+
+	f.preferred.mu.RLock()
+	defer f.preferred.mu.RUnlock()
+	if encoder := f.preferred.byCodec[EncoderCodec(videoCodec)]; encoder != "" {
+		videoCodec = encoder
+	}
+	return videoCodec, audioCodec
+}
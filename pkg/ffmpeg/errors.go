@@ -0,0 +1,83 @@
+package ffmpeg
+
+import (
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+)
+
+// maxStderrTail bounds the FFmpeg output kept for error messages. FFmpeg
+// prints the reason for a failure last.
+const maxStderrTail = 8 << 10 // 8 KiB
+
+// Error is a failed FFmpeg run with the end of its output, which names the
+// cause, e.g. "Unknown encoder 'libx265'" or "moov atom not found"
+type Error struct {
+	Op     string // What failed, e.g. "conversion"; empty when the caller adds it
+	Err    error  // Usually an *exec.ExitError
+	Output string // The end of FFmpeg's stderr
+}
+
+// Error returns the operation, exit status and FFmpeg's reason
+func (e *Error) Error() string {
+	msg := e.Err.Error()
+	if e.Op != "" {
+		msg = fmt.Sprintf("%s failed: %s", e.Op, msg)
+	}
+	if reason := e.Reason(); reason != "" {
+		msg += ": " + reason
+	}
+	return msg
+}
+
+// Unwrap returns the underlying error
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Reason returns the last line FFmpeg printed before giving up, skipping
+// its generic closing messages, or "" if it printed nothing
+func (e *Error) Reason() string {
+	lines := strings.Split(strings.TrimSpace(e.Output), "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		line := strings.TrimSpace(lines[i])
+		if line == "" || line == "Conversion failed!" || strings.HasPrefix(line, "Exiting normally") {
+			continue
+		}
+		return line
+	}
+	return ""
+}
+
+// stderrTail keeps the last maxStderrTail bytes written to it
+type stderrTail struct {
+	buf []byte
+}
+
+// Write appends p, dropping the oldest output beyond maxStderrTail
+func (t *stderrTail) Write(p []byte) (int, error) {
+	t.buf = append(t.buf, p...)
+	if len(t.buf) > maxStderrTail {
+		t.buf = t.buf[len(t.buf)-maxStderrTail:]
+	}
+	return len(p), nil
+}
+
+// wrap returns err as an *Error carrying the captured output
+func (t *stderrTail) wrap(op string, err error) error {
+	return &Error{Op: op, Err: err, Output: string(t.buf)}
+}
+
+// captureStderr sends a command's stderr to w like attachLog and keeps its
+// end for the error returned when the command fails. w may be nil.
+func captureStderr(cmd *exec.Cmd, w io.Writer) *stderrTail {
+	tail := &stderrTail{}
+	attachLog(cmd, w)
+	if cmd.Stderr != nil {
+		cmd.Stderr = io.MultiWriter(cmd.Stderr, tail)
+	} else {
+		cmd.Stderr = tail
+	}
+	return tail
+}
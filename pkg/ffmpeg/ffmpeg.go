@@ -4,10 +4,14 @@ import (
 	"bufio"
 	"context"
 	"fmt"
+	"io"
+	"os"
 	"os/exec"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"converzen/internal/logger"
@@ -15,15 +19,41 @@ import (
 
 // FFmpeg wraps FFmpeg command execution
 type FFmpeg struct {
-	path string
-	log  *logger.ComponentLogger
+	path   string
+	runner Runner
+	log    *logger.ComponentLogger
+	probes *probeCache
+
+	// ffprobe location, resolved on first use
+	probePath     string
+	probePathOnce sync.Once
+
+	// Available encoders, listed on first use
+	encoders encoderList
+
+	// Encoders replacing the software default per codec
+	preferred preferredEncoders
+
+	// Hardware acceleration mode and detected hardware encoders
+	hw hwState
+
+	// Running processes, whose priority follows the app's focus
+	processes processTracker
 }
 
 // New creates a new FFmpeg instance
 func New(ffmpegPath string, log *logger.Logger) *FFmpeg {
+	return NewWithRunner(ffmpegPath, ExecRunner{}, log)
+}
+
+// NewWithRunner creates an FFmpeg instance whose processes are created by
+// runner, e.g. a fake in tests
+func NewWithRunner(ffmpegPath string, runner Runner, log *logger.Logger) *FFmpeg {
 	return &FFmpeg{
-		path: ffmpegPath,
-		log:  log.WithComponent("ffmpeg"),
+		path:   ffmpegPath,
+		runner: runner,
+		log:    log.WithComponent("ffmpeg"),
+		probes: newProbeCache(probeCacheSize),
 	}
 }
 
@@ -33,16 +63,110 @@ type ConvertOptions struct {
 	OutputPath string
 	Overwrite  bool
 
+	// Portion of the input to convert (zero values convert the whole input)
+	StartTime   time.Duration
+	MaxDuration time.Duration
+
+	// Streams to include, by input stream index (empty uses FFmpeg's default selection)
+	StreamIndexes []int
+
+	// AllAudioStreams keeps every audio stream instead of only the default one.
+	// Ignored when StreamIndexes is set.
+	AllAudioStreams bool
+
+	// Overlay composites a second video on top of the input
+	Overlay *Overlay
+
+	// MapChapters copies the input's chapter markers into the output
+	MapChapters bool
+
+	// Metadata tags written to the output container, e.g. "title"
+	Metadata map[string]string
+
+	// NoVideo drops every video stream, e.g. cover art when converting audio
+	NoVideo bool
+
+	// VideoDecoder forces the decoder for the input's video, e.g. "libvpx-vp9",
+	// which unlike FFmpeg's native VP9 decoder keeps WebM alpha channels
+	VideoDecoder string
+
+	// Sequence reads InputPath as numbered image files, e.g. "frame_%04d.png",
+	// instead of a media file. Only Convert and ConvertTwoPass read sequences.
+	Sequence *Sequence
+
+	// Salvage makes the decoder skip over corrupt data and regenerates
+	// missing timestamps, to recover what it can from a damaged input
+	Salvage bool
+
 	// Video options
 	VideoCodec   string
+	VideoProfile string // Encoder profile passed with -profile:v
+	PixelFormat  string // Output pixel format passed with -pix_fmt
 	VideoBitrate string
 	Resolution   string
 	FrameRate    int
+	VideoFilter  string // Filter chain passed with -vf
+
+	// Quality encodes at a constant quality instead of a bitrate, as a CRF
+	// on x264's 1-51 scale (lower is better) that is mapped to the encoder's
+	// own option. Ignored when VideoBitrate is set; 0 keeps the encoder's
+	// default.
+	Quality int
+
+	// HardwareAcceleration replaces libx264 and libx265 with a hardware
+	// encoder: HWAccelNone, HWAccelAuto or an accelerator such as "nvenc".
+	// Empty uses the mode set with SetHardwareAcceleration. A failed
+	// hardware encode is retried in software.
+	HardwareAcceleration string
+
+	// Two-pass encoding: pass 1 analyses the video into PassLogFile without
+	// writing any output, pass 2 encodes using that analysis. 0 encodes in a
+	// single pass. See ConvertTwoPass.
+	Pass        int
+	PassLogFile string
 
 	// Audio options
 	AudioCodec   string
 	AudioBitrate string
 	SampleRate   int
+	AudioFilter  string // Filter chain passed with -af
+
+	// TimeScale is the output duration relative to the input, for filters that
+	// change playback speed. Used for progress reporting; 0 means unchanged.
+	TimeScale float64
+
+	// Resource limits
+	Threads     int   // Maximum encoder threads (0 lets FFmpeg decide)
+	LowPriority bool  // Run the FFmpeg process at low OS priority
+	ReadLimit   int64 // Approximate input read rate in bytes per second (0 = unlimited)
+
+	// Log receives the FFmpeg command lines and their stderr output, e.g. to
+	// keep a log per conversion. nil discards the output.
+	Log io.Writer
+
+	// hardwareArgs come before the input for the hardware encoder, e.g. the
+	// device to open. Set by Convert.
+	hardwareArgs []string
+}
+
+// formatSeconds formats a duration as seconds for FFmpeg time options
+func formatSeconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', 3, 64)
+}
+
+// metadataArgs returns -metadata flags for the given tags, in a stable order
+func metadataArgs(tags map[string]string) []string {
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	args := make([]string, 0, len(keys)*2)
+	for _, key := range keys {
+		args = append(args, "-metadata", key+"="+tags[key])
+	}
+	return args
 }
 
 // ProgressCallback is called with progress updates (0-100)
@@ -50,7 +174,7 @@ type ProgressCallback func(progress float64)
 
 // IsAvailable checks if FFmpeg is available on the system
 func (f *FFmpeg) IsAvailable() bool {
-	cmd := exec.Command(f.path, "-version")
+	cmd := f.runner.Command(context.Background(), f.path, "-version")
 	err := cmd.Run()
 	available := err == nil
 	if available {
@@ -63,7 +187,7 @@ func (f *FFmpeg) IsAvailable() bool {
 
 // GetVersion returns the FFmpeg version
 func (f *FFmpeg) GetVersion() (string, error) {
-	cmd := exec.Command(f.path, "-version")
+	cmd := f.runner.Command(context.Background(), f.path, "-version")
 	output, err := cmd.Output()
 	if err != nil {
 		return "", fmt.Errorf("failed to get FFmpeg version: %w", err)
@@ -80,12 +204,11 @@ func (f *FFmpeg) GetVersion() (string, error) {
 func (f *FFmpeg) GetDuration(inputPath string) (float64, error) {
 	f.log.Debug("Getting duration for: %s", inputPath)
 
-	cmd := exec.Command(f.path, "-i", inputPath, "-hide_banner")
-	output, _ := cmd.CombinedOutput() // FFmpeg writes info to stderr
+	output := f.probeOutput(inputPath)
 
 	// Parse duration from output: Duration: 00:01:30.50
 	re := regexp.MustCompile(`Duration: (\d{2}):(\d{2}):(\d{2})\.(\d{2})`)
-	matches := re.FindStringSubmatch(string(output))
+	matches := re.FindStringSubmatch(output)
 
 	if len(matches) != 5 {
 		return 0, fmt.Errorf("could not parse duration from FFmpeg output")
@@ -102,19 +225,74 @@ func (f *FFmpeg) GetDuration(inputPath string) (float64, error) {
 	return duration, nil
 }
 
-// Convert performs a video/audio conversion
+// Convert performs a video/audio conversion, with a hardware encoder when
+// opts.HardwareAcceleration asks for one
 func (f *FFmpeg) Convert(ctx context.Context, opts ConvertOptions, progressCallback ProgressCallback) error {
+	hwOpts, ok := f.hardwareOptions(opts)
+	if !ok {
+		return f.convert(ctx, opts, progressCallback)
+	}
+
+	_, statErr := os.Stat(opts.OutputPath)
+	existed := statErr == nil
+
+	f.log.Info("Encoding with %s", hwOpts.VideoCodec)
+	err := f.convert(ctx, hwOpts, progressCallback)
+	if err == nil || ctx.Err() != nil {
+		return err
+	}
+
+	// Drop the partial output, unless it was there before and FFmpeg refused
+	// to overwrite it
+	f.log.Warn("Hardware encoder %s failed, encoding in software: %v", hwOpts.VideoCodec, err)
+	if !existed {
+		os.Remove(opts.OutputPath)
+	}
+	return f.convert(ctx, opts, progressCallback)
+}
+
+// convert runs a single FFmpeg conversion
+func (f *FFmpeg) convert(ctx context.Context, opts ConvertOptions, progressCallback ProgressCallback) error {
 	f.log.Info("Starting conversion: %s -> %s", opts.InputPath, opts.OutputPath)
 
 	// Get input duration for progress calculation
-	duration, err := f.GetDuration(opts.InputPath)
+	duration, err := f.inputDuration(opts)
 	if err != nil {
 		f.log.Warn("Could not get duration, progress will not be reported: %v", err)
 		duration = 0
 	}
+	if opts.StartTime > 0 {
+		duration -= opts.StartTime.Seconds()
+	}
+	if opts.MaxDuration > 0 && (duration <= 0 || opts.MaxDuration.Seconds() < duration) {
+		duration = opts.MaxDuration.Seconds()
+	}
+	if opts.TimeScale > 0 {
+		duration *= opts.TimeScale
+	}
 
-	// Build FFmpeg command
-	args := []string{"-i", opts.InputPath}
+	// Build FFmpeg command, seeking before the input for fast accurate seeks
+	args := []string{}
+	if opts.StartTime > 0 {
+		args = append(args, "-ss", formatSeconds(opts.StartTime))
+	}
+	if opts.VideoDecoder != "" {
+		args = append(args, "-c:v", opts.VideoDecoder)
+	}
+	args = append(args, opts.hardwareArgs...)
+	args = append(args, salvageArgs(opts.Salvage)...)
+	if opts.Sequence != nil {
+		args = append(args, sequenceArgs(opts.Sequence)...)
+	} else {
+		args = append(args, f.readRateArgs(opts.InputPath, opts.ReadLimit)...)
+	}
+	args = append(args, "-i", opts.InputPath)
+	if opts.Overlay != nil {
+		args = append(args, "-i", opts.Overlay.Path)
+	}
+	if opts.MaxDuration > 0 {
+		args = append(args, "-t", formatSeconds(opts.MaxDuration))
+	}
 
 	// Add overwrite flag
 	if opts.Overwrite {
@@ -123,12 +301,39 @@ func (f *FFmpeg) Convert(ctx context.Context, opts ConvertOptions, progressCallb
 		args = append([]string{"-n"}, args...)
 	}
 
+	// Add stream selection; an overlay composes its own video stream
+	if opts.Overlay != nil {
+		args = append(args, f.overlayArgs(opts)...)
+	} else {
+		for _, index := range opts.StreamIndexes {
+			args = append(args, "-map", fmt.Sprintf("0:%d", index))
+		}
+		if len(opts.StreamIndexes) == 0 && opts.AllAudioStreams {
+			args = append(args, "-map", "0:v:0?", "-map", "0:a?")
+		}
+	}
+	if opts.MapChapters {
+		args = append(args, "-map_chapters", "0")
+	}
+	args = append(args, metadataArgs(opts.Metadata)...)
+
 	// Add video options
+	if opts.NoVideo {
+		args = append(args, "-vn")
+	}
 	if opts.VideoCodec != "" {
 		args = append(args, "-c:v", opts.VideoCodec)
 	}
+	if opts.VideoProfile != "" {
+		args = append(args, "-profile:v", opts.VideoProfile)
+	}
+	if opts.PixelFormat != "" {
+		args = append(args, "-pix_fmt", opts.PixelFormat)
+	}
 	if opts.VideoBitrate != "" {
 		args = append(args, "-b:v", opts.VideoBitrate)
+	} else if opts.Quality > 0 {
+		args = append(args, qualityArgs(opts.VideoCodec, opts.Quality)...)
 	}
 	if opts.Resolution != "" {
 		args = append(args, "-s", opts.Resolution)
@@ -136,6 +341,12 @@ func (f *FFmpeg) Convert(ctx context.Context, opts ConvertOptions, progressCallb
 	if opts.FrameRate > 0 {
 		args = append(args, "-r", strconv.Itoa(opts.FrameRate))
 	}
+	if opts.VideoFilter != "" && opts.Overlay == nil {
+		args = append(args, "-vf", opts.VideoFilter)
+	}
+	if opts.Pass > 0 {
+		args = append(args, "-pass", strconv.Itoa(opts.Pass), "-passlogfile", opts.PassLogFile)
+	}
 
 	// Add audio options
 	if opts.AudioCodec != "" {
@@ -147,16 +358,27 @@ func (f *FFmpeg) Convert(ctx context.Context, opts ConvertOptions, progressCallb
 	if opts.SampleRate > 0 {
 		args = append(args, "-ar", strconv.Itoa(opts.SampleRate))
 	}
+	if opts.AudioFilter != "" {
+		args = append(args, "-af", opts.AudioFilter)
+	}
+
+	// Add resource limits
+	args = append(args, threadArgs(opts.Threads)...)
 
 	// Add progress reporting
 	args = append(args, "-progress", "pipe:1", "-nostats")
 
-	// Add output path
-	args = append(args, opts.OutputPath)
+	// Add output path; the first of two passes only writes its analysis
+	if opts.Pass == 1 {
+		args = append(args, "-an", "-f", "null", "-")
+	} else {
+		args = append(args, opts.OutputPath)
+	}
 
 	f.log.Debug("FFmpeg command: %s %s", f.path, strings.Join(args, " "))
 
-	cmd := exec.CommandContext(ctx, f.path, args...)
+	cmd := f.runner.Command(ctx, f.path, args...)
+	stderr := captureStderr(cmd, opts.Log)
 
 	// Get stdout for progress parsing
 	stdout, err := cmd.StdoutPipe()
@@ -169,6 +391,8 @@ func (f *FFmpeg) Convert(ctx context.Context, opts ConvertOptions, progressCallb
 		f.log.Error("Failed to start FFmpeg: %v", err)
 		return fmt.Errorf("failed to start FFmpeg: %w", err)
 	}
+	f.applyPriority(cmd, opts.LowPriority)
+	defer f.releaseProcess(cmd)
 
 	// Parse progress from stdout
 	if duration > 0 && progressCallback != nil {
@@ -193,8 +417,9 @@ func (f *FFmpeg) Convert(ctx context.Context, opts ConvertOptions, progressCallb
 
 	// Wait for completion
 	if err := cmd.Wait(); err != nil {
-		f.log.Error("FFmpeg conversion failed: %v", err)
-		return fmt.Errorf("conversion failed: %w", err)
+		err = stderr.wrap("conversion", err)
+		f.log.Error("FFmpeg %v", err)
+		return err
 	}
 
 	if progressCallback != nil {
@@ -205,29 +430,48 @@ func (f *FFmpeg) Convert(ctx context.Context, opts ConvertOptions, progressCallb
 	return nil
 }
 
-// ConvertToGif converts a video to GIF
-func (f *FFmpeg) ConvertToGif(ctx context.Context, inputPath, outputPath string, overwrite bool, progressCallback ProgressCallback) error {
-	f.log.Info("Converting to GIF: %s -> %s", inputPath, outputPath)
+// ConvertToGif converts a video to GIF. Only the input/output paths, Overwrite,
+// StartTime, MaxDuration, Salvage and resource limit fields of opts are used.
+func (f *FFmpeg) ConvertToGif(ctx context.Context, opts ConvertOptions, progressCallback ProgressCallback) error {
+	f.log.Info("Converting to GIF: %s -> %s", opts.InputPath, opts.OutputPath)
 
 	// Get input duration for progress calculation
-	duration, _ := f.GetDuration(inputPath)
+	duration, _ := f.GetDuration(opts.InputPath)
+	if opts.StartTime > 0 {
+		duration -= opts.StartTime.Seconds()
+	}
+	if opts.MaxDuration > 0 && (duration <= 0 || opts.MaxDuration.Seconds() < duration) {
+		duration = opts.MaxDuration.Seconds()
+	}
 
 	// Build command with palette generation for better quality
 	args := []string{}
-	if overwrite {
+	if opts.Overwrite {
 		args = append(args, "-y")
 	}
+	if opts.StartTime > 0 {
+		args = append(args, "-ss", formatSeconds(opts.StartTime))
+	}
+	args = append(args, salvageArgs(opts.Salvage)...)
+	args = append(args, f.readRateArgs(opts.InputPath, opts.ReadLimit)...)
+	args = append(args, "-i", opts.InputPath)
+	if opts.MaxDuration > 0 {
+		args = append(args, "-t", formatSeconds(opts.MaxDuration))
+	}
 	args = append(args,
-		"-i", inputPath,
 		"-vf", "fps=10,scale=480:-1:flags=lanczos,split[s0][s1];[s0]palettegen[p];[s1][p]paletteuse",
 		"-loop", "0",
+	)
+	args = append(args, threadArgs(opts.Threads)...)
+	args = append(args,
 		"-progress", "pipe:1", "-nostats",
-		outputPath,
+		opts.OutputPath,
 	)
 
 	f.log.Debug("FFmpeg GIF command: %s %s", f.path, strings.Join(args, " "))
 
-	cmd := exec.CommandContext(ctx, f.path, args...)
+	cmd := f.runner.Command(ctx, f.path, args...)
+	stderr := captureStderr(cmd, opts.Log)
 
 	stdout, err := cmd.StdoutPipe()
 	if err != nil {
@@ -237,6 +481,8 @@ func (f *FFmpeg) ConvertToGif(ctx context.Context, inputPath, outputPath string,
 	if err := cmd.Start(); err != nil {
 		return fmt.Errorf("failed to start FFmpeg: %w", err)
 	}
+	f.applyPriority(cmd, opts.LowPriority)
+	defer f.releaseProcess(cmd)
 
 	// Parse progress
 	if duration > 0 && progressCallback != nil {
@@ -260,7 +506,7 @@ func (f *FFmpeg) ConvertToGif(ctx context.Context, inputPath, outputPath string,
 	}
 
 	if err := cmd.Wait(); err != nil {
-		return fmt.Errorf("GIF conversion failed: %w", err)
+		return stderr.wrap("GIF conversion", err)
 	}
 
 	if progressCallback != nil {
@@ -271,6 +517,23 @@ func (f *FFmpeg) ConvertToGif(ctx context.Context, inputPath, outputPath string,
 	return nil
 }
 
+// threadArgs returns the arguments limiting FFmpeg to the given number of threads
+func threadArgs(threads int) []string {
+	if threads <= 0 {
+		return nil
+	}
+	return []string{"-threads", strconv.Itoa(threads)}
+}
+
+// attachLog writes a command line to w and sends the command's stderr there
+func attachLog(cmd *exec.Cmd, w io.Writer) {
+	if w == nil {
+		return
+	}
+	fmt.Fprintf(w, "$ %s\n", strings.Join(cmd.Args, " "))
+	cmd.Stderr = w
+}
+
 // GetDefaultCodec returns the default codec for a given output format
 func GetDefaultCodec(format string) (videoCodec, audioCodec string) {
 	format = strings.TrimPrefix(strings.ToLower(format), ".")
@@ -291,29 +554,112 @@ func GetDefaultCodec(format string) (videoCodec, audioCodec string) {
 	}
 }
 
-// Probe holds media file information
+// GetDefaultAudioCodec returns the default codec for a given audio output format
+func GetDefaultAudioCodec(format string) string {
+	format = strings.TrimPrefix(strings.ToLower(format), ".")
+
+	switch format {
+	case "mp3":
+		return "libmp3lame"
+	case "wav":
+		return "pcm_s16le"
+	case "flac":
+		return "flac"
+	case "aac", "m4a":
+		return "aac"
+	case "ogg":
+		return "libvorbis"
+	case "opus":
+		return "libopus"
+	default:
+		return ""
+	}
+}
+
+// Probe holds media file information. The top-level fields describe the
+// first video and audio streams; Streams describes every stream.
 type Probe struct {
-	Duration   time.Duration
-	Width      int
-	Height     int
-	VideoCodec string
-	AudioCodec string
-	Bitrate    int64
+	Duration   time.Duration `json:"duration"` // Nanoseconds in JSON
+	Width      int           `json:"width"`
+	Height     int           `json:"height"`
+	VideoCodec string        `json:"videoCodec"`
+	AudioCodec string        `json:"audioCodec"`
+	Bitrate    int64         `json:"bitrate"` // Overall bitrate in bits per second, 0 if unknown
+
+	// Container format, e.g. "mov,mp4,m4a,3gp,3g2,mj2"
+	Container string `json:"container"`
+
+	// AudioCodecs lists the codec of every audio stream, in stream order
+	AudioCodecs []string `json:"audioCodecs"`
+
+	// AudioChannels of the first audio stream, 0 if unknown
+	AudioChannels int `json:"audioChannels"`
+
+	// Chapters is the number of chapter markers in the file
+	Chapters int `json:"chapters"`
+
+	// FrameRate of the first video stream in frames per second, 0 if unknown
+	FrameRate float64 `json:"frameRate"`
+
+	// FieldOrder of the first video stream: "progressive", "tt" (top first),
+	// "bb" (bottom first), "tb" or "bt" (coded and displayed fields differ).
+	// Empty when FFmpeg doesn't report it.
+	FieldOrder string `json:"fieldOrder"`
+
+	// PixelFormat of the first video stream, e.g. "yuv420p" or "rgba"
+	PixelFormat string `json:"pixelFormat"`
+
+	// Alpha reports whether the first video stream carries an alpha channel,
+	// either in its pixel format or as a WebM alpha_mode side stream
+	Alpha bool `json:"alpha"`
+
+	// Streams lists every stream with its codec, bitrate and format details
+	Streams []MediaStream `json:"streams"`
+}
+
+// Interlaced reports whether the first video stream is interlaced
+func (p *Probe) Interlaced() bool {
+	return p.FieldOrder != "" && p.FieldOrder != "progressive"
 }
 
-// ProbeFile probes a media file for information
+// ProbeFile probes a media file for information. ffprobe's structured
+// output is used when available; otherwise FFmpeg's file information is
+// parsed, which lacks codec profiles and some stream details.
 func (f *FFmpeg) ProbeFile(inputPath string) (*Probe, error) {
 	f.log.Debug("Probing file: %s", inputPath)
 
-	// Use ffprobe if available, otherwise parse ffmpeg output
-	cmd := exec.Command(f.path, "-i", inputPath, "-hide_banner")
-	output, _ := cmd.CombinedOutput()
+	if probePath := f.ProbePath(); probePath != "" {
+		probe, err := f.probeFFprobe(probePath, inputPath)
+		if err == nil {
+			return probe, nil
+		}
+		f.log.Warn("ffprobe failed, falling back to FFmpeg output: %v", err)
+	}
 
+	output := f.probeOutput(inputPath)
+	probe := parseProbe(output)
+	if streams, err := parseStreamDetails(output); err == nil {
+		probe.Streams = streams
+		for _, s := range streams {
+			if s.Type == "audio" {
+				probe.AudioChannels = s.Channels
+				break
+			}
+		}
+	}
+	return probe, nil
+}
+
+// parseProbe parses FFmpeg's file information output
+func parseProbe(output string) *Probe {
 	probe := &Probe{}
+	if matches := inputFormatRe.FindStringSubmatch(output); len(matches) == 2 {
+		probe.Container = matches[1]
+	}
 
 	// Parse duration
 	durationRe := regexp.MustCompile(`Duration: (\d{2}):(\d{2}):(\d{2})\.(\d{2})`)
-	if matches := durationRe.FindStringSubmatch(string(output)); len(matches) == 5 {
+	if matches := durationRe.FindStringSubmatch(output); len(matches) == 5 {
 		hours, _ := strconv.Atoi(matches[1])
 		minutes, _ := strconv.Atoi(matches[2])
 		seconds, _ := strconv.Atoi(matches[3])
@@ -322,12 +668,122 @@ func (f *FFmpeg) ProbeFile(inputPath string) (*Probe, error) {
 			time.Duration(seconds)*time.Second
 	}
 
-	// Parse resolution
-	resRe := regexp.MustCompile(`(\d{2,5})x(\d{2,5})`)
-	if matches := resRe.FindStringSubmatch(string(output)); len(matches) == 3 {
+	// Parse overall bitrate, e.g. "Duration: 00:01:30.50, start: 0.000000, bitrate: 1205 kb/s"
+	if matches := regexp.MustCompile(`bitrate: (\d+) kb/s`).FindStringSubmatch(output); len(matches) == 2 {
+		kbps, _ := strconv.ParseInt(matches[1], 10, 64)
+		probe.Bitrate = kbps * 1000
+	}
+
+	// Parse resolution from the first video stream, since container
+	// metadata (e.g. MXF UIDs and product versions) can look like a size
+	resRe := regexp.MustCompile(`\b(\d{2,5})x(\d{2,5})\b`)
+	resSource := regexp.MustCompile(`Stream #\d+:\d+.*?: Video: .*`).FindString(output)
+	if resSource == "" {
+		resSource = output
+	}
+	if matches := resRe.FindStringSubmatch(resSource); len(matches) == 3 {
 		probe.Width, _ = strconv.Atoi(matches[1])
 		probe.Height, _ = strconv.Atoi(matches[2])
 	}
 
-	return probe, nil
+	// Parse codecs of the first video and audio streams, e.g.
+	// "Stream #0:0(und): Video: h264 (High) (avc1 / 0x31637661), ..."
+	videoRe := regexp.MustCompile(`Stream #\d+:\d+.*?: Video: (\w+)`)
+	if matches := videoRe.FindStringSubmatch(output); len(matches) == 2 {
+		probe.VideoCodec = matches[1]
+	}
+	audioRe := regexp.MustCompile(`Stream #\d+:\d+.*?: Audio: (\w+)`)
+	for _, matches := range audioRe.FindAllStringSubmatch(output, -1) {
+		probe.AudioCodecs = append(probe.AudioCodecs, matches[1])
+	}
+	if len(probe.AudioCodecs) > 0 {
+		probe.AudioCodec = probe.AudioCodecs[0]
+	}
+
+	// Count chapter markers, e.g. "Chapter #0:1: start 60.000000, end 120.000000"
+	chapterRe := regexp.MustCompile(`Chapter #\d+:\d+`)
+	probe.Chapters = len(chapterRe.FindAllString(output, -1))
+
+	// Parse field order from the first video stream's pixel format details,
+	// e.g. "yuv420p(tv, bt470bg, top first)" or "yuv411p(bottom coded first (swapped))"
+	if video := regexp.MustCompile(`Stream #\d+:\d+.*?: Video: .*`).FindString(output); video != "" {
+		probe.FieldOrder = parseFieldOrder(video)
+
+		// Parse the pixel format following the codec details, e.g. ", yuva444p12le(tv, ...)"
+		if matches := pixelFormatRe.FindStringSubmatch(video); len(matches) == 2 {
+			probe.PixelFormat = matches[1]
+		}
+		probe.Alpha = hasAlpha(probe.PixelFormat) || alphaModeRe.MatchString(output)
+
+		// Parse frame rate, e.g. "29.97 fps" or "25 fps"
+		if matches := regexp.MustCompile(`(\d+(?:\.\d+)?) fps`).FindStringSubmatch(video); len(matches) == 2 {
+			probe.FrameRate, _ = strconv.ParseFloat(matches[1], 64)
+		}
+	}
+
+	return probe
+}
+
+// pixelFormatRe matches the pixel format after a video stream's codec details
+var pixelFormatRe = regexp.MustCompile(`: Video: [^,]+, (\w+)`)
+
+// alphaModeRe matches the stream tag WebM uses to flag VP8/VP9 alpha
+var alphaModeRe = regexp.MustCompile(`(?m)^\s+alpha_mode\s*: 1\s*$`)
+
+// alphaPixelFormats lists pixel format prefixes that carry an alpha channel
+var alphaPixelFormats = []string{"yuva", "rgba", "bgra", "argb", "abgr", "gbrap", "ya8", "ya16", "rgb32", "bgr32", "pal8"}
+
+// hasAlpha reports whether a pixel format carries an alpha channel
+func hasAlpha(pixelFormat string) bool {
+	for _, prefix := range alphaPixelFormats {
+		if strings.HasPrefix(pixelFormat, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldOrderRe matches the field order FFmpeg prints in a video stream's pixel format details
+var fieldOrderRe = regexp.MustCompile(`\b(progressive|top first|bottom first|top coded first|bottom coded first)`)
+
+// parseFieldOrder returns the field order of a video stream description
+// using ffprobe's names ("progressive", "tt", "bb", "tb", "bt"), or ""
+func parseFieldOrder(details string) string {
+	matches := fieldOrderRe.FindStringSubmatch(details)
+	if len(matches) != 2 {
+		return ""
+	}
+	return map[string]string{
+		"progressive":        "progressive",
+		"top first":          "tt",
+		"bottom first":       "bb",
+		"top coded first":    "tb",
+		"bottom coded first": "bt",
+	}[matches[1]]
+}
+
+// probeOutput returns FFmpeg's stream information output for a file.
+// Results are cached per path, modification time and size, so repeated
+// probes of an unchanged file don't launch another FFmpeg process.
+func (f *FFmpeg) probeOutput(inputPath string) string {
+	key, cacheable := f.probes.keyFor(inputPath)
+	if cacheable {
+		if output, ok := f.probes.Get(key); ok {
+			f.log.Debug("Probe cache hit: %s", inputPath)
+			return output
+		}
+	}
+
+	cmd := f.runner.Command(context.Background(), f.path, "-i", inputPath, "-hide_banner")
+	output, _ := cmd.CombinedOutput() // FFmpeg writes info to stderr
+
+	if cacheable && len(output) > 0 {
+		f.probes.Put(key, string(output))
+	}
+	return string(output)
+}
+
+// ClearProbeCache discards all cached probe results
+func (f *FFmpeg) ClearProbeCache() {
+	f.probes.Clear()
 }
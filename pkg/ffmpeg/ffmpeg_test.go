@@ -0,0 +1,240 @@
+package ffmpeg_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"converzen/internal/logger"
+	"converzen/pkg/ffmpeg"
+	"converzen/pkg/ffmpeg/ffmpegtest"
+)
+
+func TestMain(m *testing.M) {
+	ffmpegtest.HelperMain()
+	os.Exit(m.Run())
+}
+
+// probeOutput is the file information FFmpeg prints for a ten second clip
+const probeOutput = `Input #0, mov,mp4,m4a,3gp,3g2,mj2, from 'input.mov':
+  Duration: 00:00:10.00, start: 0.000000, bitrate: 1000 kb/s
+  Stream #0:0(und): Video: h264 (High) (avc1 / 0x31637661), yuv420p, 1920x1080, 900 kb/s, 30 fps
+  Stream #0:1(und): Audio: aac (LC) (mp4a / 0x6134706D), 48000 Hz, stereo, fltp, 96 kb/s
+At least one output file must be specified
+`
+
+// newFFmpeg returns an FFmpeg running its commands through runner
+func newFFmpeg(t *testing.T, runner *ffmpegtest.Runner) *ffmpeg.FFmpeg {
+	t.Helper()
+
+	log, err := logger.NewWithConsole(filepath.Join(t.TempDir(), "test.log"), logger.DEBUG, nil)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	t.Cleanup(func() { log.Close() })
+	return ffmpeg.NewWithRunner("ffmpeg", runner, log)
+}
+
+// isProbe reports whether a call asks FFmpeg for a file's information
+func isProbe(call ffmpegtest.Call) bool {
+	return !slices.Contains(call.Args, "-progress")
+}
+
+// flagValue returns the value after flag in args, "" if flag is missing
+func flagValue(args []string, flag string) string {
+	if i := slices.Index(args, flag); i >= 0 && i+1 < len(args) {
+		return args[i+1]
+	}
+	return ""
+}
+
+func TestConvert(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "input.mov")
+	output := filepath.Join(dir, "output.mp4")
+
+	runner := &ffmpegtest.Runner{Respond: func(call ffmpegtest.Call) ffmpegtest.Response {
+		if isProbe(call) {
+			return ffmpegtest.Response{Stderr: probeOutput, ExitCode: 1}
+		}
+		return ffmpegtest.Response{
+			Stdout: "out_time_ms=5000000\nprogress=continue\nout_time_ms=10000000\nprogress=end\n",
+			Files:  map[string]string{output: "converted"},
+		}
+	}}
+	f := newFFmpeg(t, runner)
+
+	var mu sync.Mutex
+	var progress []float64
+	err := f.Convert(context.Background(), ffmpeg.ConvertOptions{
+		InputPath:  input,
+		OutputPath: output,
+		VideoCodec: "libx264",
+		AudioCodec: "aac",
+		Quality:    ffmpeg.QualityMedium,
+	}, func(p float64) {
+		mu.Lock()
+		progress = append(progress, p)
+		mu.Unlock()
+	})
+	if err != nil {
+		t.Fatalf("Convert failed: %v", err)
+	}
+
+	if data, err := os.ReadFile(output); err != nil || string(data) != "converted" {
+		t.Errorf("output = %q, %v; want the converted file", data, err)
+	}
+
+	calls := runner.Calls()
+	if len(calls) != 2 || !isProbe(calls[0]) {
+		t.Fatalf("calls = %v, want a probe and a conversion", calls)
+	}
+	args := calls[1].Args
+	for flag, want := range map[string]string{"-i": input, "-c:v": "libx264", "-c:a": "aac", "-crf": "23"} {
+		if got := flagValue(args, flag); got != want {
+			t.Errorf("%s = %q, want %q in %v", flag, got, want, args)
+		}
+	}
+	if args[0] != "-n" {
+		t.Errorf("args start with %q, want -n without Overwrite", args[0])
+	}
+	if args[len(args)-1] != output {
+		t.Errorf("last arg = %q, want the output path", args[len(args)-1])
+	}
+
+	// Progress lines still unread when FFmpeg exits are dropped, so only
+	// the final report is certain
+	mu.Lock()
+	defer mu.Unlock()
+	if len(progress) == 0 || progress[len(progress)-1] != 100 {
+		t.Errorf("progress = %v, want a final 100", progress)
+	}
+	for _, p := range progress {
+		if p != 50 && p != 100 {
+			t.Errorf("progress = %v, want 50 and 100 from the progress output", progress)
+			break
+		}
+	}
+}
+
+func TestConvertBitrateOverridesQuality(t *testing.T) {
+	dir := t.TempDir()
+	runner := &ffmpegtest.Runner{}
+	f := newFFmpeg(t, runner)
+
+	err := f.Convert(context.Background(), ffmpeg.ConvertOptions{
+		InputPath:    filepath.Join(dir, "input.mov"),
+		OutputPath:   filepath.Join(dir, "output.webm"),
+		Overwrite:    true,
+		VideoCodec:   "libvpx-vp9",
+		VideoBitrate: "2M",
+		Quality:      ffmpeg.QualityHigh,
+	}, nil)
+	if err != nil {
+		t.Fatalf("Convert failed: %v", err)
+	}
+
+	args := runner.Calls()[len(runner.Calls())-1].Args
+	if args[0] != "-y" {
+		t.Errorf("args start with %q, want -y with Overwrite", args[0])
+	}
+	if got := flagValue(args, "-b:v"); got != "2M" {
+		t.Errorf("-b:v = %q, want 2M", got)
+	}
+	if slices.Contains(args, "-crf") {
+		t.Errorf("args %v set a CRF alongside the bitrate", args)
+	}
+}
+
+func TestConvertFailure(t *testing.T) {
+	dir := t.TempDir()
+	runner := &ffmpegtest.Runner{Respond: func(call ffmpegtest.Call) ffmpegtest.Response {
+		if isProbe(call) {
+			return ffmpegtest.Response{Stderr: probeOutput, ExitCode: 1}
+		}
+		return ffmpegtest.Response{
+			Stderr:   "[mov,mp4,m4a,3gp,3g2,mj2 @ 0x1] moov atom not found\ninput.mov: Invalid data found when processing input\nConversion failed!\n",
+			ExitCode: 1,
+		}
+	}}
+	f := newFFmpeg(t, runner)
+
+	err := f.Convert(context.Background(), ffmpeg.ConvertOptions{
+		InputPath:  filepath.Join(dir, "input.mov"),
+		OutputPath: filepath.Join(dir, "output.mp4"),
+	}, nil)
+
+	var ffmpegErr *ffmpeg.Error
+	if !errors.As(err, &ffmpegErr) {
+		t.Fatalf("err = %v, want an *ffmpeg.Error", err)
+	}
+	if ffmpegErr.Op != "conversion" {
+		t.Errorf("Op = %q, want conversion", ffmpegErr.Op)
+	}
+	if reason := ffmpegErr.Reason(); !strings.Contains(reason, "Invalid data found") {
+		t.Errorf("Reason() = %q, want FFmpeg's last message", reason)
+	}
+}
+
+func TestGetDuration(t *testing.T) {
+	runner := &ffmpegtest.Runner{Respond: func(call ffmpegtest.Call) ffmpegtest.Response {
+		return ffmpegtest.Response{Stderr: probeOutput, ExitCode: 1}
+	}}
+	f := newFFmpeg(t, runner)
+
+	duration, err := f.GetDuration(filepath.Join(t.TempDir(), "input.mov"))
+	if err != nil {
+		t.Fatalf("GetDuration failed: %v", err)
+	}
+	if duration != 10 {
+		t.Errorf("duration = %v, want 10", duration)
+	}
+}
+
+func TestConvertImageSequence(t *testing.T) {
+	dir := t.TempDir()
+	pattern := filepath.Join(dir, "frame_%04d.png")
+	runner := &ffmpegtest.Runner{}
+	f := newFFmpeg(t, runner)
+
+	var mu sync.Mutex
+	var progress []float64
+	err := f.Convert(context.Background(), ffmpeg.ConvertOptions{
+		InputPath:   pattern,
+		OutputPath:  filepath.Join(dir, "overlay.webm"),
+		Sequence:    &ffmpeg.Sequence{FrameRate: 24, StartNumber: 1, Frames: 48},
+		VideoCodec:  "libvpx-vp9",
+		PixelFormat: "yuva420p",
+	}, func(p float64) {
+		mu.Lock()
+		progress = append(progress, p)
+		mu.Unlock()
+	})
+	if err != nil {
+		t.Fatalf("Convert failed: %v", err)
+	}
+
+	// The sequence's length comes from its frames, so it isn't probed
+	calls := runner.Calls()
+	if len(calls) != 1 {
+		t.Fatalf("calls = %v, want only the conversion", calls)
+	}
+	args := calls[0].Args
+	input := slices.Index(args, "-i")
+	for flag, want := range map[string]string{"-f": "image2", "-framerate": "24", "-start_number": "1", "-pix_fmt": "yuva420p"} {
+		if got := flagValue(args, flag); got != want {
+			t.Errorf("%s = %q, want %q in %v", flag, got, want, args)
+		}
+		if flag != "-pix_fmt" && slices.Index(args, flag) > input {
+			t.Errorf("%s comes after -i in %v, want it as an input option", flag, args)
+		}
+	}
+	if got := flagValue(args, "-i"); got != pattern {
+		t.Errorf("-i = %q, want the pattern %q", got, pattern)
+	}
+}
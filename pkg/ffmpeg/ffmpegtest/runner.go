@@ -0,0 +1,99 @@
+// Package ffmpegtest provides a fake ffmpeg.Runner, so code using
+// pkg/ffmpeg can be tested without FFmpeg installed. Faked commands re-run
+// the test binary, which plays back a scripted Response instead of running
+// the tests. Packages using it route the test binary to the fake in TestMain:
+//
+//	func TestMain(m *testing.M) {
+//		ffmpegtest.HelperMain()
+//		os.Exit(m.Run())
+//	}
+package ffmpegtest
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"sync"
+)
+
+// responseEnv carries a faked command's encoded Response to the helper process
+const responseEnv = "FFMPEGTEST_RESPONSE"
+
+// Call is a command line run through a Runner
+type Call struct {
+	Name string
+	Args []string
+}
+
+// Response is what a faked command outputs
+type Response struct {
+	Stdout   string
+	Stderr   string
+	ExitCode int
+
+	// Files are written before the command exits, by path, e.g. the output
+	// FFmpeg would have created
+	Files map[string]string
+}
+
+// Runner is a fake ffmpeg.Runner recording the commands it is asked for
+type Runner struct {
+	// Respond returns the response to a command. nil succeeds without output.
+	Respond func(call Call) Response
+
+	mu    sync.Mutex
+	calls []Call
+}
+
+// Command returns a command playing back the response to name and args
+func (r *Runner) Command(ctx context.Context, name string, args ...string) *exec.Cmd {
+	call := Call{Name: name, Args: args}
+	r.mu.Lock()
+	r.calls = append(r.calls, call)
+	r.mu.Unlock()
+
+	var response Response
+	if r.Respond != nil {
+		response = r.Respond(call)
+	}
+	encoded, _ := json.Marshal(response)
+
+	// The faked command line is kept as the process's arguments, so logs
+	// show what would have run
+	cmd := exec.CommandContext(ctx, os.Args[0])
+	cmd.Args = append([]string{name}, args...)
+	cmd.Env = append(os.Environ(), responseEnv+"="+string(encoded))
+	return cmd
+}
+
+// Calls returns the commands run so far, in order
+func (r *Runner) Calls() []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Call(nil), r.calls...)
+}
+
+// HelperMain plays back a Response and exits when the process was started
+// by a Runner, and returns otherwise. Call it first in TestMain.
+func HelperMain() {
+	encoded, ok := os.LookupEnv(responseEnv)
+	if !ok {
+		return
+	}
+
+	var response Response
+	if err := json.Unmarshal([]byte(encoded), &response); err != nil {
+		os.Stderr.WriteString("ffmpegtest: invalid response: " + err.Error() + "\n")
+		os.Exit(2)
+	}
+	for path, content := range response.Files {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			os.Stderr.WriteString("ffmpegtest: " + err.Error() + "\n")
+			os.Exit(2)
+		}
+	}
+	os.Stdout.WriteString(response.Stdout)
+	os.Stderr.WriteString(response.Stderr)
+	os.Exit(response.ExitCode)
+}
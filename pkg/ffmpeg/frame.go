@@ -0,0 +1,43 @@
+package ffmpeg
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// GrabFrame writes the video frame at a position to outputPath at the
+// source's full resolution. The image format follows outputPath's
+// extension, e.g. ".png". An existing file at outputPath is replaced.
+func (f *FFmpeg) GrabFrame(ctx context.Context, inputPath, outputPath string, at time.Duration) error {
+	f.log.Info("Grabbing frame at %s: %s -> %s", at, inputPath, outputPath)
+
+	args := []string{"-y", "-hide_banner", "-nostdin"}
+	if at > 0 {
+		args = append(args, "-ss", formatSeconds(at))
+	}
+	args = append(args,
+		"-i", inputPath,
+		"-map", "0:v:0",
+		"-frames:v", "1",
+		"-an",
+	)
+
+	// JPEG defaults to a low quality; 2 is near the best of its 2-31 scale
+	switch strings.ToLower(filepath.Ext(outputPath)) {
+	case ".jpg", ".jpeg":
+		args = append(args, "-q:v", "2")
+	}
+	args = append(args, "-update", "1", outputPath)
+
+	f.log.Debug("FFmpeg command: %s %s", f.path, strings.Join(args, " "))
+
+	output, err := f.runner.Command(ctx, f.path, args...).CombinedOutput()
+	if err != nil {
+		f.log.Error("Frame grab failed: %v: %s", err, string(output))
+		return fmt.Errorf("frame grab failed: %w", err)
+	}
+	return nil
+}
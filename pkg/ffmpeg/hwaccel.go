@@ -0,0 +1,215 @@
+package ffmpeg
+
+import (
+	"context"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// Hardware acceleration modes of ConvertOptions.HardwareAcceleration and
+// SetHardwareAcceleration. The others name an accelerator, e.g. "nvenc".
+const (
+	HWAccelNone = "none" // Always encode in software
+	HWAccelAuto = "auto" // Use the first accelerator detected
+)
+
+// vaapiDevice is the DRM render node VAAPI encoders open
+const vaapiDevice = "/dev/dri/renderD128"
+
+// hwAccel describes a family of hardware encoders
+type hwAccel struct {
+	name     string
+	encoders map[string]string // Encoder by codec, "h264" or "hevc"
+
+	// inputArgs come before the input, e.g. the device to open
+	inputArgs []string
+
+	// filter ends the video filter chain, uploading frames to the device
+	filter string
+
+	// pixelFormat replaces yuv420p for encoders that don't accept it
+	pixelFormat string
+}
+
+// hwAccels lists the supported accelerators by name
+var hwAccels = map[string]hwAccel{
+	"videotoolbox": {
+		name:     "videotoolbox",
+		encoders: map[string]string{"h264": "h264_videotoolbox", "hevc": "hevc_videotoolbox"},
+	},
+	"nvenc": {
+		name:     "nvenc",
+		encoders: map[string]string{"h264": "h264_nvenc", "hevc": "hevc_nvenc"},
+	},
+	"qsv": {
+		name:        "qsv",
+		encoders:    map[string]string{"h264": "h264_qsv", "hevc": "hevc_qsv"},
+		pixelFormat: "nv12",
+	},
+	"vaapi": {
+		name:      "vaapi",
+		encoders:  map[string]string{"h264": "h264_vaapi", "hevc": "hevc_vaapi"},
+		inputArgs: []string{"-vaapi_device", vaapiDevice},
+		filter:    "format=nv12,hwupload",
+	},
+}
+
+// IsHWAccel reports whether name is a supported accelerator
+func IsHWAccel(name string) bool {
+	_, ok := hwAccels[name]
+	return ok
+}
+
+// platformHWAccels returns the accelerators worth detecting on this
+// platform, in order of preference
+func platformHWAccels() []string {
+	switch runtime.GOOS {
+	case "darwin":
+		return []string{"videotoolbox"}
+	case "windows":
+		return []string{"nvenc", "qsv"}
+	default:
+		return []string{"nvenc", "qsv", "vaapi"}
+	}
+}
+
+// hwState holds the default acceleration mode and the detected encoders
+type hwState struct {
+	mu   sync.RWMutex
+	mode string
+
+	once     sync.Once
+	detected []string        // Usable accelerators, in order of preference
+	usable   map[string]bool // Usable encoders
+}
+
+// SetHardwareAcceleration sets the mode conversions use when their options
+// don't choose one: HWAccelNone (the default), HWAccelAuto or the name of
+// an accelerator
+func (f *FFmpeg) SetHardwareAcceleration(mode string) {
+	f.hw.mu.Lock()
+	defer f.hw.mu.Unlock()
+	f.hw.mode = mode
+}
+
+// DetectHardwareAcceleration returns the accelerators whose encoders work
+// on this machine, in order of preference. Encoders FFmpeg was built with
+// are tried with a one-frame encode, as they fail without the hardware.
+// Detection runs once; later calls return the cached result.
+func (f *FFmpeg) DetectHardwareAcceleration() []string {
+	f.hw.once.Do(func() {
+		f.hw.usable = make(map[string]bool)
+		for _, name := range platformHWAccels() {
+			accel := hwAccels[name]
+			for _, encoder := range accel.encoders {
+				if f.HasEncoder(encoder) && f.tryEncoder(accel, encoder) {
+					f.hw.usable[encoder] = true
+				}
+			}
+			if f.hw.usable[accel.encoders["h264"]] || f.hw.usable[accel.encoders["hevc"]] {
+				f.hw.detected = append(f.hw.detected, name)
+			}
+		}
+		if len(f.hw.detected) > 0 {
+			f.log.Info("Hardware encoding available: %s", strings.Join(f.hw.detected, ", "))
+		} else {
+			f.log.Info("No hardware encoder available, encoding in software")
+		}
+	})
+	return f.hw.detected
+}
+
+// tryEncoder reports whether an encoder can encode a test frame
+func (f *FFmpeg) tryEncoder(accel hwAccel, encoder string) bool {
+	if accel.name == "vaapi" {
+		if _, err := os.Stat(vaapiDevice); err != nil {
+			return false
+		}
+	}
+
+	args := []string{"-hide_banner", "-nostdin", "-v", "error"}
+	args = append(args, accel.inputArgs...)
+	args = append(args, "-f", "lavfi", "-i", "color=black:size=256x256:rate=30", "-frames:v", "1")
+	if accel.filter != "" {
+		args = append(args, "-vf", accel.filter)
+	}
+	args = append(args, "-c:v", encoder, "-f", "null", "-")
+
+	if output, err := f.runner.Command(context.Background(), f.path, args...).CombinedOutput(); err != nil {
+		f.log.Debug("Encoder %s is unusable: %v: %s", encoder, err, strings.TrimSpace(string(output)))
+		return false
+	}
+	return true
+}
+
+// hardwareOptions returns opts with the software H.264 or HEVC encoder
+// replaced by a hardware encoder, when the acceleration mode asks for one
+// and a usable one was detected. Two-pass encodes, overlays and pixel
+// formats other than 8-bit 4:2:0 stay in software.
+func (f *FFmpeg) hardwareOptions(opts ConvertOptions) (ConvertOptions, bool) {
+	mode := opts.HardwareAcceleration
+	if mode == "" {
+		f.hw.mu.RLock()
+		mode = f.hw.mode
+		f.hw.mu.RUnlock()
+	}
+	if mode == "" || mode == HWAccelNone {
+		return opts, false
+	}
+
+	codec := ""
+	switch opts.VideoCodec {
+	case "libx264":
+		codec = "h264"
+	case "libx265":
+		codec = "hevc"
+	default:
+		return opts, false
+	}
+	if opts.Pass > 0 || opts.Overlay != nil || opts.NoVideo || (opts.PixelFormat != "" && opts.PixelFormat != "yuv420p") {
+		return opts, false
+	}
+
+	// Automatic mode leaves the choice to an encoder benchmark, if one ran
+	if mode == HWAccelAuto {
+		f.preferred.mu.RLock()
+		_, benchmarked := f.preferred.byCodec[codec]
+		f.preferred.mu.RUnlock()
+		if benchmarked {
+			return opts, false
+		}
+	}
+
+	detected := f.DetectHardwareAcceleration()
+	for _, name := range detected {
+		if mode != HWAccelAuto && mode != name {
+			continue
+		}
+		accel := hwAccels[name]
+		encoder := accel.encoders[codec]
+		if !f.hw.usable[encoder] {
+			continue
+		}
+
+		opts.VideoCodec = encoder
+		opts.hardwareArgs = accel.inputArgs
+		if accel.pixelFormat != "" && opts.PixelFormat != "" {
+			opts.PixelFormat = accel.pixelFormat
+		}
+		if accel.filter != "" {
+			opts.PixelFormat = ""
+			if opts.VideoFilter != "" {
+				opts.VideoFilter += ","
+			}
+			opts.VideoFilter += accel.filter
+		}
+		return opts, true
+	}
+
+	if mode != HWAccelAuto {
+		f.log.Warn("Hardware acceleration %q is unavailable, encoding in software", mode)
+	}
+	return opts, false
+}
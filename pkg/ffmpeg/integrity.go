@@ -0,0 +1,127 @@
+package ffmpeg
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// maxIntegrityErrors bounds the decode error messages kept in a report
+const maxIntegrityErrors = 50
+
+// IntegrityReport is the result of decoding a media file in full
+type IntegrityReport struct {
+	Path       string   `json:"path"`
+	OK         bool     `json:"ok"`               // No decode errors were found
+	ErrorCount int      `json:"errorCount"`       // Number of error messages FFmpeg printed
+	Errors     []string `json:"errors,omitempty"` // The first maxIntegrityErrors messages
+}
+
+// salvageArgs returns the input options that make FFmpeg decode past corrupt
+// data instead of giving up: decoding errors are ignored, corrupt packets
+// dropped and missing timestamps regenerated
+func salvageArgs(salvage bool) []string {
+	if !salvage {
+		return nil
+	}
+	return []string{
+		"-err_detect", "ignore_err",
+		"-fflags", "+genpts+discardcorrupt",
+		"-max_error_rate", "1",
+	}
+}
+
+// CheckIntegrity decodes every video and audio stream of a file without
+// writing any output and collects the errors FFmpeg reports. Truncated or
+// damaged files show up as decode errors; a file FFmpeg can't open at all is
+// reported as not OK rather than as an error.
+func (f *FFmpeg) CheckIntegrity(ctx context.Context, inputPath string, progressCallback ProgressCallback) (*IntegrityReport, error) {
+	f.log.Info("Checking integrity: %s", inputPath)
+
+	duration, _ := f.GetDuration(inputPath)
+
+	args := []string{
+		"-hide_banner", "-nostdin", "-v", "error",
+		"-i", inputPath,
+		"-map", "0:v?", "-map", "0:a?",
+		"-progress", "pipe:1", "-nostats",
+		"-f", "null", "-",
+	}
+	f.log.Debug("FFmpeg command: %s %s", f.path, strings.Join(args, " "))
+
+	cmd := f.runner.Command(ctx, f.path, args...)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stdout pipe: %w", err)
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stderr pipe: %w", err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		f.log.Error("Failed to start FFmpeg: %v", err)
+		return nil, fmt.Errorf("failed to start FFmpeg: %w", err)
+	}
+	f.applyPriority(cmd, true)
+	defer f.releaseProcess(cmd)
+
+	go func() {
+		scanner := bufio.NewScanner(stdout)
+		timeRegex := regexp.MustCompile(`out_time_ms=(\d+)`)
+
+		for scanner.Scan() {
+			if duration <= 0 || progressCallback == nil {
+				continue
+			}
+			if matches := timeRegex.FindStringSubmatch(scanner.Text()); len(matches) == 2 {
+				timeMs, _ := strconv.ParseInt(matches[1], 10, 64)
+				progress := (float64(timeMs) / 1000000 / duration) * 100
+				if progress > 100 {
+					progress = 100
+				}
+				progressCallback(progress)
+			}
+		}
+	}()
+
+	report := &IntegrityReport{Path: inputPath}
+	scanner := bufio.NewScanner(stderr)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		report.ErrorCount++
+		if len(report.Errors) < maxIntegrityErrors {
+			report.Errors = append(report.Errors, line)
+		}
+	}
+
+	if err := cmd.Wait(); err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		// FFmpeg exits with an error when the file can't be opened or
+		// decoding fails outright; make sure the report says so
+		if report.ErrorCount == 0 {
+			report.ErrorCount = 1
+			report.Errors = append(report.Errors, fmt.Sprintf("FFmpeg failed: %v", err))
+		}
+	}
+
+	report.OK = report.ErrorCount == 0
+	if progressCallback != nil {
+		progressCallback(100)
+	}
+
+	if report.OK {
+		f.log.Info("No decode errors found: %s", inputPath)
+	} else {
+		f.log.Warn("Found %d decode errors: %s", report.ErrorCount, inputPath)
+	}
+	return report, nil
+}
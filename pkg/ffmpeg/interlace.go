@@ -0,0 +1,54 @@
+package ffmpeg
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// idetFrames is the number of frames the idet filter inspects
+const idetFrames = 300
+
+// idetRe matches the idet filter's multi-frame summary, e.g.
+// "Multi frame detection: TFF:   281 BFF:     0 Progressive:     2 Undetermined:    17"
+var idetRe = regexp.MustCompile(`Multi frame detection: TFF:\s*(\d+) BFF:\s*(\d+) Progressive:\s*(\d+)`)
+
+// DetectFieldOrder decodes the first frames of a video through the idet
+// filter to find out whether it is interlaced. It returns "tt", "bb" or
+// "progressive" like Probe.FieldOrder. Camcorder transport streams often
+// carry interlaced H.264 without flagging it, so the stream header alone
+// can't be trusted.
+func (f *FFmpeg) DetectFieldOrder(ctx context.Context, inputPath string) (string, error) {
+	f.log.Debug("Detecting field order: %s", inputPath)
+
+	cmd := f.runner.Command(ctx, f.path,
+		"-hide_banner", "-nostats",
+		"-i", inputPath,
+		"-map", "0:v:0",
+		"-vf", "idet",
+		"-frames:v", strconv.Itoa(idetFrames),
+		"-an", "-f", "null", "-",
+	)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("field order detection failed: %w", err)
+	}
+
+	matches := idetRe.FindStringSubmatch(string(output))
+	if len(matches) != 4 {
+		return "", fmt.Errorf("field order detection produced no result")
+	}
+	tff, _ := strconv.Atoi(matches[1])
+	bff, _ := strconv.Atoi(matches[2])
+	progressive, _ := strconv.Atoi(matches[3])
+
+	switch {
+	case tff > progressive && tff >= bff:
+		return "tt", nil
+	case bff > progressive:
+		return "bb", nil
+	default:
+		return "progressive", nil
+	}
+}
@@ -0,0 +1,80 @@
+package ffmpeg
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// silenceLevel is reported for silent input, whose loudness and peak FFmpeg
+// prints as -inf. It is the absolute gate of EBU R128.
+const silenceLevel = -70.0
+
+var (
+	integratedRe = regexp.MustCompile(`I:\s+(-?[\d.]+|-inf) LUFS`)
+	rangeRe      = regexp.MustCompile(`LRA:\s+(-?[\d.]+|-inf) LU`)
+	truePeakRe   = regexp.MustCompile(`Peak:\s+(-?[\d.]+|-inf) dBFS`)
+)
+
+// Loudness is an EBU R128 measurement of a file's first audio stream
+type Loudness struct {
+	Integrated float64 // Integrated loudness in LUFS
+	TruePeak   float64 // True peak in dBTP
+	Range      float64 // Loudness range in LU
+}
+
+// MeasureLoudness decodes the first audio stream through the ebur128 filter
+// and returns the integrated loudness, true peak and loudness range from its
+// summary
+func (f *FFmpeg) MeasureLoudness(ctx context.Context, inputPath string) (*Loudness, error) {
+	f.log.Debug("Measuring loudness: %s", inputPath)
+
+	cmd := f.runner.Command(ctx, f.path,
+		"-hide_banner", "-nostats", "-nostdin",
+		"-i", inputPath,
+		"-map", "0:a:0",
+		"-filter:a", "ebur128=peak=true",
+		"-f", "null", "-",
+	)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("loudness measurement failed: %w", err)
+	}
+
+	// The filter logs a running measurement, the summary comes last
+	text := string(output)
+	if i := strings.LastIndex(text, "Summary:"); i >= 0 {
+		text = text[i:]
+	} else {
+		return nil, fmt.Errorf("loudness measurement produced no summary, the file may have no audio")
+	}
+
+	loudness := &Loudness{
+		Integrated: parseLevel(integratedRe, text),
+		Range:      parseLevel(rangeRe, text),
+		TruePeak:   parseLevel(truePeakRe, text),
+	}
+	if loudness.Range == silenceLevel {
+		loudness.Range = 0
+	}
+	return loudness, nil
+}
+
+// parseLevel returns the level matched by re in text, or silenceLevel if
+// it is missing or -inf
+func parseLevel(re *regexp.Regexp, text string) float64 {
+	matches := re.FindStringSubmatch(text)
+	if len(matches) != 2 {
+		return silenceLevel
+	}
+	level, err := strconv.ParseFloat(matches[1], 64)
+	if err != nil || level < silenceLevel {
+		return silenceLevel
+	}
+	return level
+}
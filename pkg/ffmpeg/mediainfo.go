@@ -0,0 +1,285 @@
+package ffmpeg
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// MediaInfo describes a media file's container and streams
+type MediaInfo struct {
+	Path      string  `json:"path"`
+	Container string  `json:"container"`          // Container format, e.g. "mov,mp4,m4a,3gp,3g2,mj2"
+	Duration  float64 `json:"duration"`           // Seconds, 0 if unknown
+	Size      int64   `json:"size"`               // File size in bytes
+	Bitrate   int64   `json:"bitrate,omitempty"`  // Overall bitrate in bits per second
+	Chapters  int     `json:"chapters,omitempty"` // Number of chapter markers
+
+	Streams []MediaStream `json:"streams"`
+}
+
+// MediaStream describes a single stream in detail
+type MediaStream struct {
+	Stream
+
+	Profile string `json:"profile,omitempty"` // Codec profile, e.g. "High" or "Main 10"
+	Bitrate int64  `json:"bitrate,omitempty"` // Bits per second, 0 if unknown
+
+	// Video details
+	FrameRate      float64 `json:"frameRate,omitempty"`
+	PixelFormat    string  `json:"pixelFormat,omitempty"`
+	BitDepth       int     `json:"bitDepth,omitempty"`
+	ColorPrimaries string  `json:"colorPrimaries,omitempty"`
+	ColorTransfer  string  `json:"colorTransfer,omitempty"`
+	HDR            string  `json:"hdr,omitempty"` // "HDR10", "HLG" or "" for SDR
+
+	// Audio details
+	SampleRate    int    `json:"sampleRate,omitempty"`
+	ChannelLayout string `json:"channelLayout,omitempty"`
+}
+
+// ffprobeMediaInfo mirrors the parts of `ffprobe -show_format -show_streams
+// -show_chapters` JSON output used for MediaInfo
+type ffprobeMediaInfo struct {
+	Format struct {
+		FormatName string `json:"format_name"`
+		Duration   string `json:"duration"`
+		Size       string `json:"size"`
+		BitRate    string `json:"bit_rate"`
+	} `json:"format"`
+	Streams []struct {
+		Index            int               `json:"index"`
+		CodecType        string            `json:"codec_type"`
+		CodecName        string            `json:"codec_name"`
+		Profile          string            `json:"profile"`
+		BitRate          string            `json:"bit_rate"`
+		Width            int               `json:"width"`
+		Height           int               `json:"height"`
+		AvgFrameRate     string            `json:"avg_frame_rate"`
+		PixFmt           string            `json:"pix_fmt"`
+		BitsPerRawSample string            `json:"bits_per_raw_sample"`
+		ColorPrimaries   string            `json:"color_primaries"`
+		ColorTransfer    string            `json:"color_transfer"`
+		FieldOrder       string            `json:"field_order"`
+		SampleRate       string            `json:"sample_rate"`
+		Channels         int               `json:"channels"`
+		ChannelLayout    string            `json:"channel_layout"`
+		Tags             map[string]string `json:"tags"`
+		Disposition      map[string]int    `json:"disposition"`
+	} `json:"streams"`
+	Chapters []json.RawMessage `json:"chapters"`
+}
+
+// GetMediaInfo returns detailed information about a media file. ffprobe is
+// used when available; otherwise the details FFmpeg prints are used, which
+// lack profiles, bit depth from raw samples and per-stream bitrates.
+func (f *FFmpeg) GetMediaInfo(inputPath string) (*MediaInfo, error) {
+	f.log.Debug("Getting media info for: %s", inputPath)
+
+	stat, err := os.Stat(inputPath)
+	if err != nil {
+		return nil, fmt.Errorf("file not found: %s", inputPath)
+	}
+
+	if probePath := f.ProbePath(); probePath != "" {
+		info, err := f.getMediaInfoFFprobe(probePath, inputPath)
+		if err == nil {
+			info.Size = stat.Size()
+			return info, nil
+		}
+		f.log.Warn("ffprobe failed, falling back to FFmpeg output: %v", err)
+	}
+
+	info, err := f.getMediaInfoFFmpeg(inputPath)
+	if err != nil {
+		return nil, err
+	}
+	info.Size = stat.Size()
+	return info, nil
+}
+
+// getMediaInfoFFprobe reads media info from ffprobe's JSON output
+func (f *FFmpeg) getMediaInfoFFprobe(probePath, inputPath string) (*MediaInfo, error) {
+	parsed, err := f.ffprobeJSON(probePath, inputPath)
+	if err != nil {
+		return nil, err
+	}
+	return mediaInfoFromFFprobe(parsed, inputPath), nil
+}
+
+// mediaInfoFromFFprobe converts parsed ffprobe output to media info, with
+// one stream per ffprobe stream in the same order
+func mediaInfoFromFFprobe(parsed *ffprobeMediaInfo, inputPath string) *MediaInfo {
+	info := &MediaInfo{
+		Path:      inputPath,
+		Container: parsed.Format.FormatName,
+		Chapters:  len(parsed.Chapters),
+		Streams:   make([]MediaStream, 0, len(parsed.Streams)),
+	}
+	info.Duration, _ = strconv.ParseFloat(parsed.Format.Duration, 64)
+	info.Bitrate, _ = strconv.ParseInt(parsed.Format.BitRate, 10, 64)
+
+	for _, s := range parsed.Streams {
+		stream := MediaStream{
+			Stream: Stream{
+				Index:    s.Index,
+				Type:     s.CodecType,
+				Codec:    s.CodecName,
+				Language: s.Tags["language"],
+				Title:    s.Tags["title"],
+				Channels: s.Channels,
+				Width:    s.Width,
+				Height:   s.Height,
+				Default:  s.Disposition["default"] == 1,
+
+				FieldOrder: strings.TrimPrefix(s.FieldOrder, "unknown"),
+			},
+			Profile:        s.Profile,
+			FrameRate:      parseRational(s.AvgFrameRate),
+			PixelFormat:    s.PixFmt,
+			ColorPrimaries: strings.TrimPrefix(s.ColorPrimaries, "unknown"),
+			ColorTransfer:  strings.TrimPrefix(s.ColorTransfer, "unknown"),
+			ChannelLayout:  s.ChannelLayout,
+		}
+		stream.Bitrate, _ = strconv.ParseInt(s.BitRate, 10, 64)
+		stream.SampleRate, _ = strconv.Atoi(s.SampleRate)
+
+		if s.CodecType == "video" {
+			stream.BitDepth, _ = strconv.Atoi(s.BitsPerRawSample)
+			if stream.BitDepth == 0 {
+				stream.BitDepth = pixelFormatBitDepth(s.PixFmt)
+			}
+			stream.HDR = hdrFormat(stream.ColorTransfer)
+		}
+
+		info.Streams = append(info.Streams, stream)
+	}
+	return info
+}
+
+// getMediaInfoFFmpeg reads media info from FFmpeg's file information output
+func (f *FFmpeg) getMediaInfoFFmpeg(inputPath string) (*MediaInfo, error) {
+	output := f.probeOutput(inputPath)
+
+	streams, err := parseStreamDetails(output)
+	if err != nil {
+		return nil, err
+	}
+	probe := parseProbe(output)
+
+	return &MediaInfo{
+		Path:      inputPath,
+		Container: probe.Container,
+		Duration:  probe.Duration.Seconds(),
+		Bitrate:   probe.Bitrate,
+		Chapters:  probe.Chapters,
+		Streams:   streams,
+	}, nil
+}
+
+// parseStreamDetails parses the streams of FFmpeg's file information
+// output along with the details it prints for each
+func parseStreamDetails(output string) ([]MediaStream, error) {
+	streams, err := parseStreams(output)
+	if err != nil {
+		return nil, err
+	}
+
+	details := make([]MediaStream, 0, len(streams))
+	lines := streamDetailLines(output)
+	for _, s := range streams {
+		stream := MediaStream{Stream: s}
+		line := lines[s.Index]
+
+		switch s.Type {
+		case "video":
+			if matches := pixelFormatRe.FindStringSubmatch(line); len(matches) == 2 {
+				stream.PixelFormat = matches[1]
+				stream.BitDepth = pixelFormatBitDepth(matches[1])
+			}
+			if matches := regexp.MustCompile(`(\d+(?:\.\d+)?) fps`).FindStringSubmatch(line); len(matches) == 2 {
+				stream.FrameRate, _ = strconv.ParseFloat(matches[1], 64)
+			}
+			for _, transfer := range []string{"smpte2084", "arib-std-b67"} {
+				if strings.Contains(line, transfer) {
+					stream.ColorTransfer = transfer
+				}
+			}
+			stream.HDR = hdrFormat(stream.ColorTransfer)
+		case "audio":
+			if matches := regexp.MustCompile(`(\d+) Hz, ([\w.()]+)`).FindStringSubmatch(line); len(matches) == 3 {
+				stream.SampleRate, _ = strconv.Atoi(matches[1])
+				stream.ChannelLayout = matches[2]
+			}
+		}
+		if matches := regexp.MustCompile(`(\d+) kb/s`).FindStringSubmatch(line); len(matches) == 2 {
+			kbps, _ := strconv.ParseInt(matches[1], 10, 64)
+			stream.Bitrate = kbps * 1000
+		}
+
+		details = append(details, stream)
+	}
+	return details, nil
+}
+
+// inputFormatRe matches the container line of FFmpeg's file information,
+// e.g. "Input #0, mov,mp4,m4a,3gp,3g2,mj2, from 'clip.mp4':"
+var inputFormatRe = regexp.MustCompile(`Input #\d+, (.+?), from `)
+
+// streamDetailLines maps stream indexes to their FFmpeg stream lines
+func streamDetailLines(output string) map[int]string {
+	lines := make(map[int]string)
+	for _, line := range strings.Split(output, "\n") {
+		if matches := streamLineRe.FindStringSubmatch(line); len(matches) == 6 {
+			index, _ := strconv.Atoi(matches[1])
+			lines[index] = line
+		}
+	}
+	return lines
+}
+
+// bitDepthRe matches the bit depth suffix of a pixel format, e.g. "10" in "yuv420p10le"
+var bitDepthRe = regexp.MustCompile(`p(\d{1,2})(?:le|be)$`)
+
+// pixelFormatBitDepth returns the bits per component of a pixel format,
+// assuming 8 for formats without a depth suffix
+func pixelFormatBitDepth(pixelFormat string) int {
+	if pixelFormat == "" {
+		return 0
+	}
+	if matches := bitDepthRe.FindStringSubmatch(pixelFormat); len(matches) == 2 {
+		depth, _ := strconv.Atoi(matches[1])
+		return depth
+	}
+	return 8
+}
+
+// hdrFormat names the HDR format signalled by a color transfer characteristic
+func hdrFormat(transfer string) string {
+	switch transfer {
+	case "smpte2084":
+		return "HDR10"
+	case "arib-std-b67":
+		return "HLG"
+	}
+	return ""
+}
+
+// parseRational parses an ffprobe rational such as "30000/1001", returning 0
+// for unknown values like "0/0"
+func parseRational(value string) float64 {
+	num, den, ok := strings.Cut(value, "/")
+	if !ok {
+		f, _ := strconv.ParseFloat(value, 64)
+		return f
+	}
+	n, _ := strconv.ParseFloat(num, 64)
+	d, _ := strconv.ParseFloat(den, 64)
+	if d == 0 {
+		return 0
+	}
+	return n / d
+}
@@ -0,0 +1,80 @@
+package ffmpeg
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// OverlayCorner is the corner of the main video an overlay is placed in
+type OverlayCorner string
+
+const (
+	CornerTopLeft     OverlayCorner = "top-left"
+	CornerTopRight    OverlayCorner = "top-right"
+	CornerBottomLeft  OverlayCorner = "bottom-left"
+	CornerBottomRight OverlayCorner = "bottom-right"
+)
+
+const (
+	// DefaultOverlayScale is the overlay width as a fraction of the main video's width
+	DefaultOverlayScale = 0.25
+
+	// DefaultOverlayMargin is the gap in pixels between the overlay and the frame edge
+	DefaultOverlayMargin = 16
+)
+
+// Overlay describes a picture-in-picture video composited onto the main input
+type Overlay struct {
+	Path   string
+	Corner OverlayCorner
+	Scale  float64 // Overlay width relative to the main video (0 uses DefaultOverlayScale)
+	Margin int     // Pixels from the frame edge (negative uses DefaultOverlayMargin)
+}
+
+// overlayArgs returns the filter graph and stream mapping compositing
+// opts.Overlay onto the main input. opts.VideoFilter is applied to the main
+// video before compositing; the main input's audio is kept.
+func (f *FFmpeg) overlayArgs(opts ConvertOptions) []string {
+	overlay := opts.Overlay
+
+	scale := overlay.Scale
+	if scale <= 0 || scale > 1 {
+		scale = DefaultOverlayScale
+	}
+	margin := overlay.Margin
+	if margin < 0 {
+		margin = DefaultOverlayMargin
+	}
+
+	// Size the overlay from the main video's width when it's known,
+	// otherwise relative to the overlay's own width
+	scaleFilter := fmt.Sprintf("scale=iw*%s:-2", strconv.FormatFloat(scale, 'f', -1, 64))
+	if probe, err := f.ProbeFile(opts.InputPath); err == nil && probe.Width > 0 {
+		width := int(float64(probe.Width)*scale) &^ 1
+		scaleFilter = fmt.Sprintf("scale=%d:-2", width)
+	}
+
+	base := "[0:v]null[base]"
+	if opts.VideoFilter != "" {
+		base = fmt.Sprintf("[0:v]%s[base]", opts.VideoFilter)
+	}
+
+	graph := fmt.Sprintf("%s;[1:v]%s[pip];[base][pip]overlay=%s:eof_action=pass[v]",
+		base, scaleFilter, overlayPosition(overlay.Corner, margin))
+
+	return []string{"-filter_complex", graph, "-map", "[v]", "-map", "0:a?"}
+}
+
+// overlayPosition returns the overlay filter's x:y expression for a corner
+func overlayPosition(corner OverlayCorner, margin int) string {
+	switch corner {
+	case CornerTopLeft:
+		return fmt.Sprintf("x=%d:y=%d", margin, margin)
+	case CornerTopRight:
+		return fmt.Sprintf("x=W-w-%d:y=%d", margin, margin)
+	case CornerBottomLeft:
+		return fmt.Sprintf("x=%d:y=H-h-%d", margin, margin)
+	default:
+		return fmt.Sprintf("x=W-w-%d:y=H-h-%d", margin, margin)
+	}
+}
@@ -0,0 +1,20 @@
+//go:build !windows
+
+package ffmpeg
+
+import "syscall"
+
+// Niceness values used for normal and low priority conversions
+const (
+	normalNiceness = 0
+	lowNiceness    = 10
+)
+
+// setProcessPriority lowers or restores the OS scheduling priority of a process
+func setProcessPriority(pid int, low bool) error {
+	niceness := normalNiceness
+	if low {
+		niceness = lowNiceness
+	}
+	return syscall.Setpriority(syscall.PRIO_PROCESS, pid, niceness)
+}
@@ -0,0 +1,20 @@
+//go:build windows
+
+package ffmpeg
+
+import "golang.org/x/sys/windows"
+
+// setProcessPriority lowers or restores the OS scheduling priority of a process
+func setProcessPriority(pid int, low bool) error {
+	handle, err := windows.OpenProcess(windows.PROCESS_SET_INFORMATION, false, uint32(pid))
+	if err != nil {
+		return err
+	}
+	defer windows.CloseHandle(handle)
+
+	priorityClass := uint32(windows.NORMAL_PRIORITY_CLASS)
+	if low {
+		priorityClass = windows.BELOW_NORMAL_PRIORITY_CLASS
+	}
+	return windows.SetPriorityClass(handle, priorityClass)
+}
@@ -0,0 +1,85 @@
+package ffmpeg
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// probeFFprobe probes a file using ffprobe's JSON output
+func (f *FFmpeg) probeFFprobe(probePath, inputPath string) (*Probe, error) {
+	parsed, err := f.ffprobeJSON(probePath, inputPath)
+	if err != nil {
+		return nil, err
+	}
+	info := mediaInfoFromFFprobe(parsed, inputPath)
+
+	probe := &Probe{
+		Container: info.Container,
+		Bitrate:   info.Bitrate,
+		Chapters:  info.Chapters,
+		Streams:   info.Streams,
+	}
+	seconds, _ := strconv.ParseFloat(parsed.Format.Duration, 64)
+	probe.Duration = time.Duration(seconds * float64(time.Second))
+
+	videoFound := false
+	for i, s := range info.Streams {
+		switch s.Type {
+		case "video":
+			if videoFound {
+				continue
+			}
+			videoFound = true
+			probe.VideoCodec = s.Codec
+			probe.Width = s.Width
+			probe.Height = s.Height
+			probe.FrameRate = s.FrameRate
+			probe.FieldOrder = s.FieldOrder
+			probe.PixelFormat = s.PixelFormat
+			probe.Alpha = hasAlpha(s.PixelFormat) || parsed.Streams[i].Tags["alpha_mode"] == "1"
+		case "audio":
+			if len(probe.AudioCodecs) == 0 {
+				probe.AudioCodec = s.Codec
+				probe.AudioChannels = s.Channels
+			}
+			probe.AudioCodecs = append(probe.AudioCodecs, s.Codec)
+		}
+	}
+	return probe, nil
+}
+
+// ffprobeJSON runs ffprobe on a file and parses its JSON output. Like
+// probeOutput, the output is cached per path, modification time and size.
+func (f *FFmpeg) ffprobeJSON(probePath, inputPath string) (*ffprobeMediaInfo, error) {
+	key, cacheable := f.probes.keyFor(inputPath)
+	key.tool = "ffprobe"
+
+	output, cached := "", false
+	if cacheable {
+		output, cached = f.probes.Get(key)
+	}
+	if cached {
+		f.log.Debug("ffprobe cache hit: %s", inputPath)
+	} else {
+		cmd := f.runner.Command(context.Background(), probePath, "-v", "error", "-print_format", "json",
+			"-show_format", "-show_streams", "-show_chapters", inputPath)
+		raw, err := cmd.Output()
+		if err != nil {
+			return nil, fmt.Errorf("ffprobe failed: %w", err)
+		}
+		output = string(raw)
+	}
+
+	var parsed ffprobeMediaInfo
+	if err := json.Unmarshal([]byte(output), &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse ffprobe output: %w", err)
+	}
+
+	if cacheable && !cached {
+		f.probes.Put(key, output)
+	}
+	return &parsed, nil
+}
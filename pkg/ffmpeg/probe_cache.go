@@ -0,0 +1,95 @@
+package ffmpeg
+
+import (
+	"container/list"
+	"os"
+	"sync"
+	"time"
+)
+
+// probeCacheSize is the maximum number of probe results kept in memory
+const probeCacheSize = 512
+
+// probeKey identifies a specific version of an input file. A changed
+// modification time or size invalidates any cached probe for the path.
+type probeKey struct {
+	path    string
+	modTime time.Time
+	size    int64
+	tool    string // "" for FFmpeg's output, "ffprobe" for ffprobe's JSON
+}
+
+// probeCacheEntry is a cached probe output for one input file and tool
+type probeCacheEntry struct {
+	key    probeKey
+	output string
+}
+
+// probeCache is a bounded LRU cache of FFmpeg probe output, so a batch that
+// estimates, converts and verifies the same files only launches FFmpeg once per file
+type probeCache struct {
+	mu      sync.Mutex
+	entries map[probeKey]*list.Element
+	order   *list.List
+	limit   int
+}
+
+// newProbeCache creates a probe cache holding up to limit entries
+func newProbeCache(limit int) *probeCache {
+	return &probeCache{
+		entries: make(map[probeKey]*list.Element),
+		order:   list.New(),
+		limit:   limit,
+	}
+}
+
+// keyFor returns the cache key for a path, or false if the file can't be stat'ed
+func (c *probeCache) keyFor(path string) (probeKey, bool) {
+	stat, err := os.Stat(path)
+	if err != nil {
+		return probeKey{}, false
+	}
+	return probeKey{path: path, modTime: stat.ModTime(), size: stat.Size()}, true
+}
+
+// Get returns the cached probe output for key
+func (c *probeCache) Get(key probeKey) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return "", false
+	}
+	c.order.MoveToFront(elem)
+	return elem.Value.(*probeCacheEntry).output, true
+}
+
+// Put stores probe output for key, evicting the least recently used entry when full
+func (c *probeCache) Put(key probeKey, output string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		elem.Value.(*probeCacheEntry).output = output
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&probeCacheEntry{key: key, output: output})
+
+	for c.order.Len() > c.limit {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*probeCacheEntry).key)
+	}
+}
+
+// Clear removes all cached probe results
+func (c *probeCache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = make(map[probeKey]*list.Element)
+	c.order.Init()
+}
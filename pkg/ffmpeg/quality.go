@@ -0,0 +1,71 @@
+package ffmpeg
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Quality levels of ConvertOptions.Quality, on x264's CRF scale
+const (
+	QualityHigh   = 18 // Visually transparent for most sources
+	QualityMedium = 23 // x264's default
+	QualityLow    = 28 // Small files with visible artifacts
+
+	// MinQuality and MaxQuality bound the accepted CRF values
+	MinQuality = 1
+	MaxQuality = 51
+)
+
+// qualityArgs returns the options encoding at a quality on x264's CRF scale
+// (lower is better) with an encoder, mapped to the encoder's own scale. It
+// returns nil for encoders without a constant quality mode, such as ProRes,
+// which keep their default.
+func qualityArgs(encoder string, crf int) []string {
+	if crf < MinQuality || crf > MaxQuality {
+		return nil
+	}
+	itoa := strconv.Itoa
+
+	switch {
+	case encoder == "libx264":
+		return []string{"-crf", itoa(crf)}
+	case encoder == "libx265":
+		// x265's CRF 28 looks like x264's 23
+		return []string{"-crf", itoa(min(crf+5, 51))}
+	case encoder == "libvpx-vp9" || encoder == "libaom-av1":
+		// Constant quality needs the bitrate limit lifted
+		return []string{"-crf", itoa(scaleQuality(crf)), "-b:v", "0"}
+	case encoder == "libsvtav1":
+		return []string{"-crf", itoa(scaleQuality(crf))}
+	case strings.HasSuffix(encoder, "_nvenc"):
+		return []string{"-rc", "vbr", "-cq", itoa(crf), "-b:v", "0"}
+	case strings.HasSuffix(encoder, "_qsv"):
+		return []string{"-global_quality", itoa(crf)}
+	case strings.HasSuffix(encoder, "_vaapi"):
+		return []string{"-rc_mode", "CQP", "-qp", itoa(crf)}
+	case strings.HasSuffix(encoder, "_videotoolbox"):
+		// 1-100, higher is better; CRF 23 maps to 60
+		return []string{"-q:v", itoa(max(1, min(100, 129-3*crf)))}
+	case encoder == "mpeg4" || encoder == "mpeg2video" || encoder == "mpeg1video" || encoder == "msmpeg4" ||
+		encoder == "wmv1" || encoder == "wmv2" || encoder == "flv" || encoder == "mjpeg":
+		// 2-31, lower is better; CRF 23 maps to 5
+		return []string{"-q:v", itoa(max(2, min(31, (crf-15)*3/5+1)))}
+	case encoder == "libtheora":
+		// 0-10, higher is better; CRF 23 maps to 6
+		return []string{"-q:v", itoa(max(0, min(10, (38-crf)*2/5)))}
+	}
+	return nil
+}
+
+// scaleQuality maps a CRF from x264's scale to the 0-63 scale of VP9 and
+// AV1 encoders, e.g. 23 to 31
+func scaleQuality(crf int) int {
+	return min(63, int(math.Round(float64(crf)*4/3)))
+}
+
+// SupportsQuality reports whether an encoder has a constant quality mode
+// ConvertOptions.Quality can select
+func SupportsQuality(encoder string) bool {
+	return qualityArgs(encoder, QualityMedium) != nil
+}
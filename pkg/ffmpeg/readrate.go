@@ -0,0 +1,35 @@
+package ffmpeg
+
+import (
+	"os"
+	"strconv"
+)
+
+// readRateArgs returns the -readrate input option limiting how fast FFmpeg
+// reads inputPath to about bytesPerSecond. FFmpeg paces reading as a multiple
+// of real time, so the limit is converted using the input's bitrate. Returns
+// nil without a limit or when the bitrate is unknown.
+func (f *FFmpeg) readRateArgs(inputPath string, bytesPerSecond int64) []string {
+	if bytesPerSecond <= 0 {
+		return nil
+	}
+
+	probe, err := f.ProbeFile(inputPath)
+	if err != nil {
+		return nil
+	}
+	bitrate := probe.Bitrate
+	if bitrate <= 0 && probe.Duration > 0 {
+		if stat, err := os.Stat(inputPath); err == nil {
+			bitrate = int64(float64(stat.Size()*8) / probe.Duration.Seconds())
+		}
+	}
+	if bitrate <= 0 {
+		f.log.Warn("Can't limit the read rate of %s, its bitrate is unknown", inputPath)
+		return nil
+	}
+
+	rate := float64(bytesPerSecond*8) / float64(bitrate)
+	f.log.Debug("Reading %s at %.2fx real time to stay under %d bytes/s", inputPath, rate, bytesPerSecond)
+	return []string{"-readrate", strconv.FormatFloat(rate, 'f', 3, 64)}
+}
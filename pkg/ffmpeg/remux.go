@@ -0,0 +1,112 @@
+package ffmpeg
+
+import "strings"
+
+// containerCodecs lists the video and audio codecs each output container can
+// hold without re-encoding, using FFmpeg's codec names
+var containerCodecs = map[string]struct {
+	video map[string]bool
+	audio map[string]bool
+}{
+	"mp4": {
+		video: codecSet("h264", "hevc", "mpeg4", "av1"),
+		audio: codecSet("aac", "mp3", "ac3", "eac3", "alac", "opus"),
+	},
+	"m4v": {
+		video: codecSet("h264", "hevc", "mpeg4"),
+		audio: codecSet("aac", "ac3", "alac"),
+	},
+	"mov": {
+		video: codecSet("h264", "hevc", "mpeg4", "mpeg2video", "prores", "dnxhd", "mjpeg"),
+		audio: codecSet("aac", "mp3", "ac3", "alac", "pcm_s16le", "pcm_s24le"),
+	},
+	"mkv": {
+		video: codecSet("h264", "hevc", "mpeg4", "mpeg2video", "vp8", "vp9", "av1", "prores", "dnxhd", "mjpeg"),
+		audio: codecSet("aac", "mp3", "ac3", "eac3", "dts", "opus", "vorbis", "flac", "alac", "pcm_s16le", "pcm_s24le"),
+	},
+	"webm": {
+		video: codecSet("vp8", "vp9", "av1"),
+		audio: codecSet("opus", "vorbis"),
+	},
+	"avi": {
+		video: codecSet("mpeg4", "h264", "mjpeg"),
+		audio: codecSet("mp3", "ac3", "pcm_s16le"),
+	},
+}
+
+// codecSet builds a lookup set from codec names
+func codecSet(codecs ...string) map[string]bool {
+	set := make(map[string]bool, len(codecs))
+	for _, codec := range codecs {
+		set[codec] = true
+	}
+	return set
+}
+
+// multiAudioContainers lists the output containers that can hold more than one audio track
+var multiAudioContainers = map[string]bool{
+	"mp4":  true,
+	"m4v":  true,
+	"mov":  true,
+	"mkv":  true,
+	"webm": true,
+}
+
+// SupportsMultipleAudio reports whether an output format can hold several audio tracks
+func SupportsMultipleAudio(format string) bool {
+	return multiAudioContainers[strings.TrimPrefix(strings.ToLower(format), ".")]
+}
+
+// chapterContainers lists the output containers that can carry chapter markers
+var chapterContainers = map[string]bool{
+	"mp4":  true,
+	"m4v":  true,
+	"mov":  true,
+	"mkv":  true,
+	"webm": true,
+}
+
+// SupportsChapters reports whether an output format can carry chapter markers
+func SupportsChapters(format string) bool {
+	return chapterContainers[strings.TrimPrefix(strings.ToLower(format), ".")]
+}
+
+// CanCopyVideo reports whether the probed video stream can be copied into the
+// given output format without re-encoding, regardless of the audio streams
+func CanCopyVideo(probe *Probe, format string) bool {
+	if probe == nil || probe.VideoCodec == "" {
+		return false
+	}
+	codecs, ok := containerCodecs[strings.TrimPrefix(strings.ToLower(format), ".")]
+	return ok && codecs.video[probe.VideoCodec]
+}
+
+// CanRemux reports whether the probed streams can be copied into the given
+// output format without re-encoding. When allAudio is set every audio stream
+// must fit the container, otherwise only the first. Files without a detected
+// video stream are never remuxed.
+func CanRemux(probe *Probe, format string, allAudio bool) bool {
+	if probe == nil || probe.VideoCodec == "" {
+		return false
+	}
+
+	codecs, ok := containerCodecs[strings.TrimPrefix(strings.ToLower(format), ".")]
+	if !ok {
+		return false
+	}
+
+	if !codecs.video[probe.VideoCodec] {
+		return false
+	}
+
+	audio := probe.AudioCodecs
+	if !allAudio && len(audio) > 1 {
+		audio = audio[:1]
+	}
+	for _, codec := range audio {
+		if !codecs.audio[codec] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,146 @@
+package ffmpeg
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"converzen/pkg/scratch"
+)
+
+const (
+	// reverseMemoryBudget bounds the decoded frames the reverse filter may
+	// buffer at once, since it holds an entire (segment of) video in memory
+	reverseMemoryBudget = 1 << 30 // 1 GiB
+
+	// Bounds for the length of each reversed segment
+	minReverseSegment     = 2 * time.Second
+	maxReverseSegment     = 60 * time.Second
+	defaultReverseSegment = 10 * time.Second
+)
+
+// ConvertReversed converts a video played backwards, audio included. The
+// reverse filters buffer their whole input, so inputs longer than a segment
+// that fits reverseMemoryBudget are reversed segment by segment and the
+// segments joined in reverse order. opts.VideoFilter and opts.AudioFilter
+// are applied before reversing.
+func (f *FFmpeg) ConvertReversed(ctx context.Context, opts ConvertOptions, progressCallback ProgressCallback) error {
+	probe, err := f.ProbeFile(opts.InputPath)
+	if err != nil {
+		return fmt.Errorf("failed to probe input: %w", err)
+	}
+
+	reversed := opts
+	reversed.VideoFilter = joinFilters(opts.VideoFilter, "reverse")
+	reversed.AudioFilter = joinFilters(opts.AudioFilter, "areverse")
+
+	// Only the part of the input between StartTime and MaxDuration is reversed
+	length := probe.Duration - opts.StartTime
+	if opts.MaxDuration > 0 && opts.MaxDuration < length {
+		length = opts.MaxDuration
+	}
+
+	segment := reverseSegmentLength(probe)
+	if length <= segment {
+		return f.Convert(ctx, reversed, progressCallback)
+	}
+
+	segments := int(math.Ceil(length.Seconds() / segment.Seconds()))
+	f.log.Info("Reversing %s in %d segments of %s", opts.InputPath, segments, segment)
+
+	tempDir, err := scratch.MkdirTemp("reverse-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	// Reverse each segment, then list them last-to-first for concatenation
+	ext := filepath.Ext(opts.OutputPath)
+	paths := make([]string, segments)
+	for i := 0; i < segments; i++ {
+		segmentOpts := reversed
+		segmentOpts.StartTime = opts.StartTime + time.Duration(i)*segment
+		segmentOpts.MaxDuration = min(segment, length-time.Duration(i)*segment)
+		segmentOpts.OutputPath = filepath.Join(tempDir, fmt.Sprintf("segment_%05d%s", i, ext))
+		segmentOpts.Overwrite = true
+		segmentOpts.Metadata = nil
+		segmentOpts.MapChapters = false
+
+		err := f.Convert(ctx, segmentOpts, func(progress float64) {
+			if progressCallback != nil {
+				progressCallback((float64(i) + progress/100) / float64(segments) * 95)
+			}
+		})
+		if err != nil {
+			return fmt.Errorf("failed to reverse segment %d: %w", i+1, err)
+		}
+		paths[segments-1-i] = segmentOpts.OutputPath
+	}
+
+	if err := f.concat(ctx, paths, opts.OutputPath, opts.Overwrite, opts.Metadata, opts.LowPriority, opts.Log); err != nil {
+		return err
+	}
+
+	if progressCallback != nil {
+		progressCallback(100)
+	}
+	return nil
+}
+
+// reverseSegmentLength returns the longest segment whose decoded frames fit
+// reverseMemoryBudget, assuming 4:2:0 frames at 1.5 bytes per pixel
+func reverseSegmentLength(probe *Probe) time.Duration {
+	if probe.Width == 0 || probe.Height == 0 || probe.FrameRate == 0 {
+		return defaultReverseSegment
+	}
+
+	bytesPerSecond := float64(probe.Width*probe.Height) * 1.5 * probe.FrameRate
+	segment := time.Duration(reverseMemoryBudget / bytesPerSecond * float64(time.Second))
+
+	if segment < minReverseSegment {
+		return minReverseSegment
+	}
+	if segment > maxReverseSegment {
+		return maxReverseSegment
+	}
+	return segment
+}
+
+// concat joins files with identical stream layouts into outputPath without
+// re-encoding, using FFmpeg's concat demuxer
+func (f *FFmpeg) concat(ctx context.Context, paths []string, outputPath string, overwrite bool, metadata map[string]string, lowPriority bool, log io.Writer) error {
+	var list strings.Builder
+	for _, path := range paths {
+		fmt.Fprintf(&list, "file '%s'\n", strings.ReplaceAll(filepath.ToSlash(path), "'", `'\''`))
+	}
+
+	listFile, err := scratch.CreateTemp("concat-*.txt")
+	if err != nil {
+		return fmt.Errorf("failed to create concat list: %w", err)
+	}
+	defer os.Remove(listFile.Name())
+
+	if _, err := listFile.WriteString(list.String()); err != nil {
+		listFile.Close()
+		return fmt.Errorf("failed to write concat list: %w", err)
+	}
+	listFile.Close()
+
+	args := []string{"-n"}
+	if overwrite {
+		args = []string{"-y"}
+	}
+	args = append(args, "-f", "concat", "-safe", "0", "-i", listFile.Name(), "-map", "0", "-c", "copy")
+	args = append(args, metadataArgs(metadata)...)
+	args = append(args, "-progress", "pipe:1", "-nostats", outputPath)
+
+	if err := f.runPass(ctx, args, "", 0, lowPriority, log, nil); err != nil {
+		return fmt.Errorf("failed to join segments: %w", err)
+	}
+	return nil
+}
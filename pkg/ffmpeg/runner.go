@@ -0,0 +1,23 @@
+package ffmpeg
+
+import (
+	"context"
+	"os/exec"
+)
+
+// Runner creates the processes FFmpeg and ffprobe run in. Tests substitute
+// one that records command lines and plays back canned output, see
+// package ffmpegtest.
+type Runner interface {
+	// Command returns the command running name with args, killed when ctx
+	// is cancelled
+	Command(ctx context.Context, name string, args ...string) *exec.Cmd
+}
+
+// ExecRunner runs the real binaries
+type ExecRunner struct{}
+
+// Command returns exec.CommandContext(ctx, name, args...)
+func (ExecRunner) Command(ctx context.Context, name string, args ...string) *exec.Cmd {
+	return exec.CommandContext(ctx, name, args...)
+}
@@ -0,0 +1,46 @@
+package ffmpeg
+
+import (
+	"strconv"
+	"time"
+)
+
+// Sequence describes numbered image files read as the frames of a video,
+// e.g. an animation rendered to PNGs. The input path is a printf pattern
+// naming the frames, such as "frame_%04d.png".
+type Sequence struct {
+	FrameRate   float64 // Frames per second
+	StartNumber int     // Number of the first frame
+	Frames      int     // Number of frames, used for progress reporting
+}
+
+// Duration returns how long the sequence plays at its frame rate
+func (s *Sequence) Duration() time.Duration {
+	if s.FrameRate <= 0 {
+		return 0
+	}
+	return time.Duration(float64(s.Frames) / s.FrameRate * float64(time.Second))
+}
+
+// sequenceArgs returns the input options that read the input as an image
+// sequence, nil when it is a media file
+func sequenceArgs(s *Sequence) []string {
+	if s == nil {
+		return nil
+	}
+	return []string{
+		"-f", "image2",
+		"-framerate", strconv.FormatFloat(s.FrameRate, 'f', -1, 64),
+		"-start_number", strconv.Itoa(s.StartNumber),
+	}
+}
+
+// inputDuration returns the length of opts' input in seconds. An image
+// sequence's length follows from its frame count, as probing the pattern
+// would assume FFmpeg's default frame rate.
+func (f *FFmpeg) inputDuration(opts ConvertOptions) (float64, error) {
+	if opts.Sequence != nil {
+		return opts.Sequence.Duration().Seconds(), nil
+	}
+	return f.GetDuration(opts.InputPath)
+}
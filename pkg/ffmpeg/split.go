@@ -0,0 +1,104 @@
+package ffmpeg
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"converzen/pkg/scratch"
+)
+
+// Split cuts the input into consecutive segments of about segmentLength using
+// FFmpeg's segment muxer. opts.OutputPath is a pattern containing a printf
+// verb for the segment number, e.g. "clip_%03d.mp4". Streams are copied when
+// opts.VideoCodec is "copy", in which case cuts land on the nearest keyframe;
+// otherwise keyframes are forced at each cut. It returns the created files in order.
+func (f *FFmpeg) Split(ctx context.Context, opts ConvertOptions, segmentLength time.Duration, progressCallback ProgressCallback) ([]string, error) {
+	if segmentLength <= 0 {
+		return nil, fmt.Errorf("segment length must be positive")
+	}
+
+	f.log.Info("Splitting %s into %s segments", opts.InputPath, segmentLength)
+
+	listFile, err := scratch.CreateTemp("segments-*.txt")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create segment list: %w", err)
+	}
+	listPath := listFile.Name()
+	listFile.Close()
+	defer os.Remove(listPath)
+
+	args := []string{"-n"}
+	if opts.Overwrite {
+		args = []string{"-y"}
+	}
+	if opts.VideoDecoder != "" {
+		args = append(args, "-c:v", opts.VideoDecoder)
+	}
+	args = append(args, salvageArgs(opts.Salvage)...)
+	args = append(args, f.readRateArgs(opts.InputPath, opts.ReadLimit)...)
+	args = append(args, "-i", opts.InputPath)
+
+	for _, index := range opts.StreamIndexes {
+		args = append(args, "-map", fmt.Sprintf("0:%d", index))
+	}
+	if len(opts.StreamIndexes) == 0 && opts.AllAudioStreams {
+		args = append(args, "-map", "0:v:0?", "-map", "0:a?")
+	}
+	args = append(args, metadataArgs(opts.Metadata)...)
+
+	if opts.VideoCodec != "" {
+		args = append(args, "-c:v", opts.VideoCodec)
+	}
+	if opts.VideoProfile != "" {
+		args = append(args, "-profile:v", opts.VideoProfile)
+	}
+	if opts.PixelFormat != "" {
+		args = append(args, "-pix_fmt", opts.PixelFormat)
+	}
+	if opts.Quality > 0 {
+		args = append(args, qualityArgs(opts.VideoCodec, opts.Quality)...)
+	}
+	if opts.VideoCodec != "copy" {
+		args = append(args, "-force_key_frames", fmt.Sprintf("expr:gte(t,n_forced*%s)", formatSeconds(segmentLength)))
+	}
+	if opts.AudioCodec != "" {
+		args = append(args, "-c:a", opts.AudioCodec)
+	}
+	args = append(args, threadArgs(opts.Threads)...)
+
+	args = append(args,
+		"-f", "segment",
+		"-segment_time", formatSeconds(segmentLength),
+		"-segment_start_number", "1",
+		"-segment_list", listPath,
+		"-segment_list_type", "flat",
+		"-reset_timestamps", "1",
+		"-progress", "pipe:1", "-nostats",
+		opts.OutputPath,
+	)
+
+	if err := f.runPass(ctx, args, opts.InputPath, 0, opts.LowPriority, opts.Log, progressCallback); err != nil {
+		return nil, fmt.Errorf("split failed: %w", err)
+	}
+
+	list, err := os.ReadFile(listPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read segment list: %w", err)
+	}
+
+	// The flat list holds one segment file name per line, relative to the output directory
+	dir := filepath.Dir(opts.OutputPath)
+	var paths []string
+	for _, line := range strings.Split(string(list), "\n") {
+		if name := strings.TrimSpace(line); name != "" {
+			paths = append(paths, filepath.Join(dir, filepath.Base(name)))
+		}
+	}
+
+	f.log.Info("Split completed: %d segments", len(paths))
+	return paths, nil
+}
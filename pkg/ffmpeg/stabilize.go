@@ -0,0 +1,182 @@
+package ffmpeg
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"converzen/pkg/scratch"
+)
+
+const (
+	// DefaultStabilizeStrength is used when no strength is given
+	DefaultStabilizeStrength = 5
+
+	// MaxStabilizeStrength is the strongest supported stabilization
+	MaxStabilizeStrength = 10
+)
+
+// HasFilter reports whether this FFmpeg build includes the named filter
+func (f *FFmpeg) HasFilter(name string) bool {
+	output, err := f.runner.Command(context.Background(), f.path, "-hide_banner", "-filters").Output()
+	if err != nil {
+		return false
+	}
+
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 2 && fields[1] == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ConvertStabilized converts a video with two-pass vidstab stabilization.
+// The first pass analyses camera motion and the second applies the smoothing
+// while encoding, so progress is reported as 0-50 and 50-100 respectively.
+// Strength ranges from 1 (subtle) to MaxStabilizeStrength (aggressive).
+// Any opts.VideoFilter is applied before stabilization in both passes.
+func (f *FFmpeg) ConvertStabilized(ctx context.Context, opts ConvertOptions, strength int, progressCallback ProgressCallback) error {
+	if !f.HasFilter("vidstabdetect") || !f.HasFilter("vidstabtransform") {
+		return fmt.Errorf("stabilization requires an FFmpeg build with libvidstab")
+	}
+
+	if strength <= 0 {
+		strength = DefaultStabilizeStrength
+	}
+	if strength > MaxStabilizeStrength {
+		strength = MaxStabilizeStrength
+	}
+
+	transforms, err := scratch.CreateTemp("vidstab-*.trf")
+	if err != nil {
+		return fmt.Errorf("failed to create stabilization data file: %w", err)
+	}
+	transformsPath := transforms.Name()
+	transforms.Close()
+	defer os.Remove(transformsPath)
+
+	f.log.Info("Analysing camera motion for stabilization (strength %d): %s", strength, opts.InputPath)
+
+	// Pass 1: detect motion and write the transforms file
+	detectFilter := fmt.Sprintf("vidstabdetect=shakiness=%d:accuracy=15:result=%s", strength, filterPath(transformsPath))
+	detectArgs := []string{"-y"}
+	if opts.StartTime > 0 {
+		detectArgs = append(detectArgs, "-ss", formatSeconds(opts.StartTime))
+	}
+	detectArgs = append(detectArgs, salvageArgs(opts.Salvage)...)
+	detectArgs = append(detectArgs, f.readRateArgs(opts.InputPath, opts.ReadLimit)...)
+	detectArgs = append(detectArgs, "-i", opts.InputPath)
+	if opts.MaxDuration > 0 {
+		detectArgs = append(detectArgs, "-t", formatSeconds(opts.MaxDuration))
+	}
+	detectArgs = append(detectArgs,
+		"-vf", joinFilters(opts.VideoFilter, detectFilter),
+		"-an",
+	)
+	detectArgs = append(detectArgs, threadArgs(opts.Threads)...)
+	detectArgs = append(detectArgs, "-progress", "pipe:1", "-nostats", "-f", "null", "-")
+
+	err = f.runPass(ctx, detectArgs, opts.InputPath, opts.TimeScale, opts.LowPriority, opts.Log, func(progress float64) {
+		if progressCallback != nil {
+			progressCallback(progress / 2)
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("stabilization analysis failed: %w", err)
+	}
+
+	// Pass 2: apply the smoothing while encoding, sharpening slightly to
+	// counter the softening introduced by the transform
+	transformFilter := fmt.Sprintf("vidstabtransform=input=%s:smoothing=%d,unsharp=5:5:0.8:3:3:0.4",
+		filterPath(transformsPath), strength*4)
+	opts.VideoFilter = joinFilters(opts.VideoFilter, transformFilter)
+
+	return f.Convert(ctx, opts, func(progress float64) {
+		if progressCallback != nil {
+			progressCallback(50 + progress/2)
+		}
+	})
+}
+
+// runPass runs an FFmpeg command that writes -progress output to stdout,
+// reporting progress (0-100) against the input's duration times timeScale
+func (f *FFmpeg) runPass(ctx context.Context, args []string, inputPath string, timeScale float64, lowPriority bool, log io.Writer, progressCallback ProgressCallback) error {
+	var duration float64
+	if progressCallback != nil {
+		duration, _ = f.GetDuration(inputPath)
+	}
+	if timeScale > 0 {
+		duration *= timeScale
+	}
+
+	f.log.Debug("FFmpeg command: %s %s", f.path, strings.Join(args, " "))
+
+	cmd := f.runner.Command(ctx, f.path, args...)
+	stderr := captureStderr(cmd, log)
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("failed to get stdout pipe: %w", err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start FFmpeg: %w", err)
+	}
+	f.applyPriority(cmd, lowPriority)
+	defer f.releaseProcess(cmd)
+
+	if duration > 0 && progressCallback != nil {
+		go func() {
+			scanner := bufio.NewScanner(stdout)
+			timeRegex := regexp.MustCompile(`out_time_ms=(\d+)`)
+
+			for scanner.Scan() {
+				if matches := timeRegex.FindStringSubmatch(scanner.Text()); len(matches) == 2 {
+					timeMs, _ := strconv.ParseInt(matches[1], 10, 64)
+					progress := (float64(timeMs) / 1000000 / duration) * 100
+					if progress > 100 {
+						progress = 100
+					}
+					progressCallback(progress)
+				}
+			}
+		}()
+	}
+
+	if err := cmd.Wait(); err != nil {
+		return stderr.wrap("", err)
+	}
+
+	if progressCallback != nil {
+		progressCallback(100)
+	}
+	return nil
+}
+
+// joinFilters joins filter chains with commas, skipping empty ones
+func joinFilters(filters ...string) string {
+	nonEmpty := make([]string, 0, len(filters))
+	for _, filter := range filters {
+		if filter != "" {
+			nonEmpty = append(nonEmpty, filter)
+		}
+	}
+	return strings.Join(nonEmpty, ",")
+}
+
+// filterPath quotes a file path for use as a filter option value. Forward
+// slashes work on every platform and the drive colon on Windows must be escaped.
+func filterPath(path string) string {
+	path = filepath.ToSlash(path)
+	path = strings.ReplaceAll(path, "'", `'\''`)
+	path = strings.ReplaceAll(path, ":", `\:`)
+	return "'" + path + "'"
+}
@@ -0,0 +1,137 @@
+package ffmpeg
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// StdioPath is the path that stands for stdin as an input and stdout as
+// an output, as in FFmpeg's own command line
+const StdioPath = "-"
+
+// StreamOptions holds options for converting from or to pipes, which
+// can't be probed or seeked like regular files
+type StreamOptions struct {
+	InputPath  string    // StdioPath reads Input
+	OutputPath string    // StdioPath writes Output
+	Input      io.Reader // Read for a StdioPath input
+	Output     io.Writer // Written for a StdioPath output
+	Overwrite  bool
+
+	// Container formats, e.g. "mov" or "mp4". A pipe's container can't be
+	// detected from its name, so OutputFormat is required; FFmpeg detects
+	// the input's from its contents when InputFormat is empty.
+	InputFormat  string
+	OutputFormat string
+
+	// Log receives the FFmpeg command line and its warnings and errors. nil
+	// discards the output.
+	Log io.Writer
+}
+
+// demuxerNames and muxerNames map file extensions to FFmpeg's names for
+// their formats where they differ
+var (
+	demuxerNames = map[string]string{
+		"mp4": "mov",
+		"m4a": "mov",
+		"mkv": "matroska",
+		"ts":  "mpegts",
+	}
+	muxerNames = map[string]string{
+		"mkv": "matroska",
+		"m4a": "ipod",
+		"aac": "adts",
+		"ts":  "mpegts",
+	}
+)
+
+// fragmentedFormats are the muxers that must write fragmented files to
+// output to a pipe, as they otherwise seek back to write their index
+var fragmentedFormats = map[string]bool{
+	"mp4":  true,
+	"mov":  true,
+	"ipod": true,
+}
+
+// formatName returns FFmpeg's name for a format given by extension
+func formatName(names map[string]string, format string) string {
+	format = strings.TrimPrefix(strings.ToLower(format), ".")
+	if name, ok := names[format]; ok {
+		return name
+	}
+	return format
+}
+
+// isPipe reports whether path is stdio or a named pipe rather than a
+// regular file
+func isPipe(path string) bool {
+	if path == StdioPath {
+		return true
+	}
+	stat, err := os.Stat(path)
+	return err == nil && stat.Mode()&os.ModeNamedPipe != 0
+}
+
+// ConvertStream converts media from a pipe or file to a pipe or file, using
+// the default codecs of the output format. Without a seekable input the
+// duration is unknown, so no progress is reported.
+func (f *FFmpeg) ConvertStream(ctx context.Context, opts StreamOptions) error {
+	if opts.OutputFormat == "" {
+		return fmt.Errorf("an output format is required")
+	}
+	f.log.Info("Starting stream conversion: %s -> %s", opts.InputPath, opts.OutputPath)
+
+	args := []string{"-n"}
+	if opts.Overwrite {
+		args = []string{"-y"}
+	}
+	args = append(args, "-hide_banner", "-loglevel", "warning", "-nostdin")
+	if opts.InputFormat != "" {
+		args = append(args, "-f", formatName(demuxerNames, opts.InputFormat))
+	}
+	args = append(args, "-i", pipeArg(opts.InputPath, "pipe:0"))
+
+	if videoCodec, audioCodec := GetDefaultCodec(opts.OutputFormat); videoCodec != "" {
+		args = append(args, "-c:v", videoCodec, "-c:a", audioCodec)
+	} else if audioCodec := GetDefaultAudioCodec(opts.OutputFormat); audioCodec != "" {
+		args = append(args, "-vn", "-c:a", audioCodec)
+	}
+
+	muxer := formatName(muxerNames, opts.OutputFormat)
+	if fragmentedFormats[muxer] && isPipe(opts.OutputPath) {
+		args = append(args, "-movflags", "frag_keyframe+empty_moov")
+	}
+	args = append(args, "-f", muxer, pipeArg(opts.OutputPath, "pipe:1"))
+
+	f.log.Debug("FFmpeg command: %s %s", f.path, strings.Join(args, " "))
+
+	cmd := f.runner.Command(ctx, f.path, args...)
+	stderr := captureStderr(cmd, opts.Log)
+	if opts.InputPath == StdioPath {
+		cmd.Stdin = opts.Input
+	}
+	if opts.OutputPath == StdioPath {
+		cmd.Stdout = opts.Output
+	}
+
+	if err := cmd.Run(); err != nil {
+		err = stderr.wrap("conversion", err)
+		f.log.Error("FFmpeg stream %v", err)
+		return err
+	}
+
+	f.log.Info("Stream conversion completed successfully")
+	return nil
+}
+
+// pipeArg returns FFmpeg's name for path, pipe for stdio
+func pipeArg(path, pipe string) string {
+	if path == StdioPath {
+		return pipe
+	}
+	return path
+}
@@ -0,0 +1,190 @@
+package ffmpeg
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Manifest names written into a streaming package's directory
+const (
+	HLSMasterPlaylist = "master.m3u8"
+	DASHManifest      = "manifest.mpd"
+)
+
+// Rendition is one quality level of a streaming package
+type Rendition struct {
+	Height       int // Output height; the width follows the aspect ratio. 0 keeps the input's.
+	VideoBitrate int // kbit/s; required, as players pick renditions by bandwidth
+	AudioBitrate int // kbit/s; 0 uses 128
+}
+
+// PackageOptions holds options for packaging a video for adaptive streaming
+type PackageOptions struct {
+	InputPath string
+	OutputDir string
+	Overwrite bool
+
+	// Manifests to write. DASH packages share their fMP4 segments with the
+	// HLS playlists when both are requested.
+	HLS  bool
+	DASH bool
+
+	SegmentLength time.Duration
+	Renditions    []Rendition // At least one
+	Audio         bool        // Whether the input has audio to package
+
+	// Resource limits
+	Threads     int
+	LowPriority bool
+	ReadLimit   int64
+
+	// Log receives the FFmpeg command line and its stderr output. nil
+	// discards the output.
+	Log io.Writer
+}
+
+// defaultStreamingAudioBitrate is used for renditions without an audio
+// bitrate, in kbit/s
+const defaultStreamingAudioBitrate = 128
+
+// PackageStreaming encodes the input into one H.264/AAC stream per
+// rendition, cut into segments of about SegmentLength with keyframes
+// aligned across renditions so players can switch between them, and writes
+// HLS playlists and/or a DASH manifest into OutputDir. It returns the paths
+// of the written master playlist and manifest.
+func (f *FFmpeg) PackageStreaming(ctx context.Context, opts PackageOptions, progressCallback ProgressCallback) ([]string, error) {
+	if !opts.HLS && !opts.DASH {
+		return nil, fmt.Errorf("no streaming format selected")
+	}
+	if len(opts.Renditions) == 0 {
+		return nil, fmt.Errorf("at least one rendition is required")
+	}
+	for _, rendition := range opts.Renditions {
+		if rendition.VideoBitrate <= 0 {
+			return nil, fmt.Errorf("every rendition needs a video bitrate")
+		}
+	}
+	if opts.SegmentLength <= 0 {
+		return nil, fmt.Errorf("segment length must be positive")
+	}
+
+	f.log.Info("Packaging %s for streaming (%d renditions, %s segments)", opts.InputPath, len(opts.Renditions), opts.SegmentLength)
+
+	args := []string{"-n"}
+	if opts.Overwrite {
+		args = []string{"-y"}
+	}
+	args = append(args, f.readRateArgs(opts.InputPath, opts.ReadLimit)...)
+	args = append(args, "-i", opts.InputPath)
+
+	// Scale a copy of the video for each rendition
+	filters := []string{fmt.Sprintf("[0:v]split=%d%s", len(opts.Renditions), renditionLabels(len(opts.Renditions), "s"))}
+	for i, rendition := range opts.Renditions {
+		scale := "null"
+		if rendition.Height > 0 {
+			scale = fmt.Sprintf("scale=-2:%d", rendition.Height)
+		}
+		filters = append(filters, fmt.Sprintf("[s%d]%s[v%d]", i, scale, i))
+	}
+	args = append(args, "-filter_complex", strings.Join(filters, ";"))
+
+	for i := range opts.Renditions {
+		args = append(args, "-map", fmt.Sprintf("[v%d]", i))
+	}
+	if opts.Audio {
+		for range opts.Renditions {
+			args = append(args, "-map", "0:a:0")
+		}
+	}
+
+	segment := formatSeconds(opts.SegmentLength)
+	args = append(args,
+		"-c:v", "libx264",
+		"-pix_fmt", "yuv420p",
+		"-force_key_frames", fmt.Sprintf("expr:gte(t,n_forced*%s)", segment),
+		"-sc_threshold", "0",
+	)
+	// Peaks are capped so segments stay close to the advertised bandwidth
+	for i, rendition := range opts.Renditions {
+		args = append(args,
+			fmt.Sprintf("-b:v:%d", i), fmt.Sprintf("%dk", rendition.VideoBitrate),
+			fmt.Sprintf("-maxrate:v:%d", i), fmt.Sprintf("%dk", rendition.VideoBitrate*107/100),
+			fmt.Sprintf("-bufsize:v:%d", i), fmt.Sprintf("%dk", rendition.VideoBitrate*3/2),
+		)
+		if opts.Audio {
+			bitrate := rendition.AudioBitrate
+			if bitrate <= 0 {
+				bitrate = defaultStreamingAudioBitrate
+			}
+			args = append(args, fmt.Sprintf("-b:a:%d", i), fmt.Sprintf("%dk", bitrate))
+		}
+	}
+	if opts.Audio {
+		args = append(args, "-c:a", "aac", "-ac", "2")
+	}
+	args = append(args, threadArgs(opts.Threads)...)
+	args = append(args, "-progress", "pipe:1", "-nostats")
+
+	var manifests []string
+	if opts.DASH {
+		manifest := filepath.Join(opts.OutputDir, DASHManifest)
+		adaptationSets := "id=0,streams=v"
+		if opts.Audio {
+			adaptationSets += " id=1,streams=a"
+		}
+		args = append(args,
+			"-f", "dash",
+			"-seg_duration", segment,
+			"-use_template", "1",
+			"-use_timeline", "1",
+			"-adaptation_sets", adaptationSets,
+		)
+		if opts.HLS {
+			args = append(args, "-hls_playlist", "1")
+			manifests = append(manifests, filepath.Join(opts.OutputDir, HLSMasterPlaylist))
+		}
+		args = append(args, manifest)
+		manifests = append(manifests, manifest)
+	} else {
+		// Each rendition gets its own playlist, e.g. stream_0.m3u8, and the
+		// master playlist lists them all
+		streams := make([]string, len(opts.Renditions))
+		for i := range opts.Renditions {
+			streams[i] = "v:" + strconv.Itoa(i)
+			if opts.Audio {
+				streams[i] += ",a:" + strconv.Itoa(i)
+			}
+		}
+		args = append(args,
+			"-f", "hls",
+			"-hls_time", segment,
+			"-hls_playlist_type", "vod",
+			"-hls_segment_filename", filepath.Join(opts.OutputDir, "stream_%v_%03d.ts"),
+			"-master_pl_name", HLSMasterPlaylist,
+			"-var_stream_map", strings.Join(streams, " "),
+			filepath.Join(opts.OutputDir, "stream_%v.m3u8"),
+		)
+		manifests = append(manifests, filepath.Join(opts.OutputDir, HLSMasterPlaylist))
+	}
+
+	if err := f.runPass(ctx, args, opts.InputPath, 0, opts.LowPriority, opts.Log, progressCallback); err != nil {
+		return nil, fmt.Errorf("packaging failed: %w", err)
+	}
+
+	f.log.Info("Packaging completed: %s", strings.Join(manifests, ", "))
+	return manifests, nil
+}
+
+// renditionLabels returns n filter pad labels like "[s0][s1]"
+func renditionLabels(n int, prefix string) string {
+	var labels strings.Builder
+	for i := range n {
+		fmt.Fprintf(&labels, "[%s%d]", prefix, i)
+	}
+	return labels.String()
+}
@@ -0,0 +1,194 @@
+package ffmpeg
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// Stream describes a single stream in a media file
+type Stream struct {
+	Index    int    `json:"index"`
+	Type     string `json:"type"` // video, audio, subtitle, data or attachment
+	Codec    string `json:"codec"`
+	Language string `json:"language,omitempty"`
+	Title    string `json:"title,omitempty"`
+	Channels int    `json:"channels,omitempty"`
+	Width    int    `json:"width,omitempty"`
+	Height   int    `json:"height,omitempty"`
+	Default  bool   `json:"default"`
+
+	// FieldOrder of a video stream: "progressive", "tt", "bb", "tb" or "bt"
+	FieldOrder string `json:"fieldOrder,omitempty"`
+}
+
+// ffprobeStreams mirrors the parts of `ffprobe -show_streams` JSON output we use
+type ffprobeStreams struct {
+	Streams []struct {
+		Index       int               `json:"index"`
+		CodecType   string            `json:"codec_type"`
+		CodecName   string            `json:"codec_name"`
+		Channels    int               `json:"channels"`
+		Width       int               `json:"width"`
+		Height      int               `json:"height"`
+		FieldOrder  string            `json:"field_order"`
+		Tags        map[string]string `json:"tags"`
+		Disposition map[string]int    `json:"disposition"`
+	} `json:"streams"`
+}
+
+// ProbePath returns the path to ffprobe, looking next to the FFmpeg binary
+// first and then on PATH. It returns an empty string if ffprobe isn't found.
+func (f *FFmpeg) ProbePath() string {
+	f.probePathOnce.Do(func() {
+		name := "ffprobe"
+		if runtime.GOOS == "windows" {
+			name = "ffprobe.exe"
+		}
+
+		if dir := filepath.Dir(f.path); dir != "." {
+			candidate := filepath.Join(dir, name)
+			if _, err := exec.LookPath(candidate); err == nil {
+				f.probePath = candidate
+				return
+			}
+		}
+
+		if path, err := exec.LookPath(name); err == nil {
+			f.probePath = path
+		}
+	})
+	return f.probePath
+}
+
+// GetStreams lists all streams in a media file. ffprobe is used when available;
+// otherwise the streams are parsed from FFmpeg's file information output.
+func (f *FFmpeg) GetStreams(inputPath string) ([]Stream, error) {
+	f.log.Debug("Listing streams for: %s", inputPath)
+
+	if probePath := f.ProbePath(); probePath != "" {
+		streams, err := f.getStreamsFFprobe(probePath, inputPath)
+		if err == nil {
+			return streams, nil
+		}
+		f.log.Warn("ffprobe failed, falling back to FFmpeg output: %v", err)
+	}
+
+	return parseStreams(f.probeOutput(inputPath))
+}
+
+// getStreamsFFprobe lists streams using ffprobe's JSON output
+func (f *FFmpeg) getStreamsFFprobe(probePath, inputPath string) ([]Stream, error) {
+	cmd := f.runner.Command(context.Background(), probePath, "-v", "error", "-print_format", "json", "-show_streams", inputPath)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("ffprobe failed: %w", err)
+	}
+
+	var parsed ffprobeStreams
+	if err := json.Unmarshal(output, &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse ffprobe output: %w", err)
+	}
+
+	streams := make([]Stream, 0, len(parsed.Streams))
+	for _, s := range parsed.Streams {
+		streams = append(streams, Stream{
+			Index:    s.Index,
+			Type:     s.CodecType,
+			Codec:    s.CodecName,
+			Language: s.Tags["language"],
+			Title:    s.Tags["title"],
+			Channels: s.Channels,
+			Width:    s.Width,
+			Height:   s.Height,
+			Default:  s.Disposition["default"] == 1,
+
+			FieldOrder: strings.TrimPrefix(s.FieldOrder, "unknown"),
+		})
+	}
+	return streams, nil
+}
+
+// streamLineRe matches FFmpeg stream lines such as
+// "Stream #0:1[0x2](eng): Audio: aac (LC), 48000 Hz, stereo, fltp (default)"
+var streamLineRe = regexp.MustCompile(`^\s*Stream #\d+:(\d+)(?:\[\w+\])?(?:\((\w+)\))?: (\w+): (\w+)(.*)$`)
+
+// parseStreams parses the stream list from FFmpeg's file information output
+func parseStreams(output string) ([]Stream, error) {
+	var streams []Stream
+	lines := strings.Split(output, "\n")
+
+	for i, line := range lines {
+		matches := streamLineRe.FindStringSubmatch(line)
+		if len(matches) != 6 {
+			continue
+		}
+
+		index, _ := strconv.Atoi(matches[1])
+		stream := Stream{
+			Index:    index,
+			Type:     strings.ToLower(matches[3]),
+			Codec:    matches[4],
+			Language: matches[2],
+			Default:  strings.Contains(matches[5], "(default)"),
+		}
+		if stream.Language == "und" {
+			stream.Language = ""
+		}
+
+		details := matches[5]
+		switch stream.Type {
+		case "video":
+			if res := regexp.MustCompile(`, (\d{2,5})x(\d{2,5})`).FindStringSubmatch(details); len(res) == 3 {
+				stream.Width, _ = strconv.Atoi(res[1])
+				stream.Height, _ = strconv.Atoi(res[2])
+			}
+			stream.FieldOrder = parseFieldOrder(details)
+		case "audio":
+			stream.Channels = parseChannelLayout(details)
+		}
+
+		// The stream's metadata block follows on indented lines
+		for _, meta := range lines[i+1:] {
+			trimmed := strings.TrimSpace(meta)
+			if strings.HasPrefix(trimmed, "Stream #") || !strings.HasPrefix(meta, "      ") {
+				break
+			}
+			if key, value, ok := strings.Cut(trimmed, ":"); ok && strings.TrimSpace(key) == "title" {
+				stream.Title = strings.TrimSpace(value)
+			}
+		}
+
+		streams = append(streams, stream)
+	}
+
+	if len(streams) == 0 {
+		return nil, fmt.Errorf("no streams found in FFmpeg output")
+	}
+	return streams, nil
+}
+
+// parseChannelLayout returns the channel count from an FFmpeg audio stream description
+func parseChannelLayout(details string) int {
+	switch {
+	case strings.Contains(details, "mono"):
+		return 1
+	case strings.Contains(details, "stereo"):
+		return 2
+	case strings.Contains(details, "5.1"):
+		return 6
+	case strings.Contains(details, "7.1"):
+		return 8
+	}
+	if matches := regexp.MustCompile(`(\d+) channels`).FindStringSubmatch(details); len(matches) == 2 {
+		channels, _ := strconv.Atoi(matches[1])
+		return channels
+	}
+	return 0
+}
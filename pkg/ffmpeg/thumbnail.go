@@ -0,0 +1,38 @@
+package ffmpeg
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Thumbnail writes a single frame of a video, scaled to the given width, to
+// outputPath. The image format follows outputPath's extension, e.g. ".jpg".
+// The frame is taken a tenth of the way in to skip fades from black.
+func (f *FFmpeg) Thumbnail(ctx context.Context, inputPath, outputPath string, width int) error {
+	var seek time.Duration
+	if probe, err := f.ProbeFile(inputPath); err == nil {
+		seek = probe.Duration / 10
+	}
+
+	args := []string{"-y"}
+	if seek > 0 {
+		args = append(args, "-ss", formatSeconds(seek))
+	}
+	args = append(args,
+		"-i", inputPath,
+		"-frames:v", "1",
+		"-vf", fmt.Sprintf("scale=%d:-2", width),
+		"-an",
+		outputPath,
+	)
+
+	f.log.Debug("FFmpeg thumbnail command: %s %v", f.path, args)
+
+	output, err := f.runner.Command(ctx, f.path, args...).CombinedOutput()
+	if err != nil {
+		f.log.Error("Thumbnail extraction failed: %v: %s", err, string(output))
+		return fmt.Errorf("thumbnail extraction failed: %w", err)
+	}
+	return nil
+}
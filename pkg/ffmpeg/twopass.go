@@ -0,0 +1,65 @@
+package ffmpeg
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"converzen/pkg/scratch"
+)
+
+// twoPassEncoders are the video encoders that support FFmpeg's -pass option
+var twoPassEncoders = map[string]bool{
+	"libx264":    true,
+	"libvpx":     true,
+	"libvpx-vp9": true,
+	"libaom-av1": true,
+	"mpeg4":      true,
+	"mpeg2video": true,
+}
+
+// SupportsTwoPass reports whether a video encoder can encode in two passes
+func SupportsTwoPass(videoCodec string) bool {
+	return twoPassEncoders[videoCodec]
+}
+
+// ConvertTwoPass encodes in two passes, which hits opts.VideoBitrate far
+// more closely than a single pass. The first pass only analyses the video,
+// so progress is reported as 0-50 for it and 50-100 for the encode.
+func (f *FFmpeg) ConvertTwoPass(ctx context.Context, opts ConvertOptions, progressCallback ProgressCallback) error {
+	if !SupportsTwoPass(opts.VideoCodec) {
+		return fmt.Errorf("%s can't encode in two passes", opts.VideoCodec)
+	}
+
+	dir, err := scratch.MkdirTemp("twopass-*")
+	if err != nil {
+		return fmt.Errorf("failed to create pass log directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	firstPass := opts
+	firstPass.Pass = 1
+	firstPass.PassLogFile = filepath.Join(dir, "pass")
+	firstPass.Overwrite = true
+
+	f.log.Info("Analysing video for two-pass encoding: %s", opts.InputPath)
+	err = f.Convert(ctx, firstPass, func(progress float64) {
+		if progressCallback != nil {
+			progressCallback(progress / 2)
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("first pass failed: %w", err)
+	}
+
+	secondPass := opts
+	secondPass.Pass = 2
+	secondPass.PassLogFile = firstPass.PassLogFile
+
+	return f.Convert(ctx, secondPass, func(progress float64) {
+		if progressCallback != nil {
+			progressCallback(50 + progress/2)
+		}
+	})
+}
@@ -0,0 +1,100 @@
+package htmlpdf
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"converzen/internal/logger"
+)
+
+// Renderer prints HTML pages to PDF with a headless wkhtmltopdf
+type Renderer struct {
+	path string
+	log  *logger.ComponentLogger
+}
+
+// New creates a new Renderer
+func New(wkhtmltopdfPath string, log *logger.Logger) *Renderer {
+	return &Renderer{
+		path: wkhtmltopdfPath,
+		log:  log.WithComponent("htmlpdf"),
+	}
+}
+
+// Find returns the path to the wkhtmltopdf executable, checking common
+// install locations and then PATH. It returns an empty string if none is found.
+func Find() string {
+	var candidates []string
+
+	switch runtime.GOOS {
+	case "windows":
+		candidates = []string{
+			filepath.Join(os.Getenv("ProgramFiles"), "wkhtmltopdf", "bin", "wkhtmltopdf.exe"),
+		}
+	case "darwin":
+		candidates = []string{
+			"/usr/local/bin/wkhtmltopdf",
+			"/opt/homebrew/bin/wkhtmltopdf",
+		}
+	default:
+		candidates = []string{
+			"/usr/bin/wkhtmltopdf",
+			"/usr/local/bin/wkhtmltopdf",
+		}
+	}
+
+	for _, path := range candidates {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+
+	if path, err := exec.LookPath("wkhtmltopdf"); err == nil {
+		return path
+	}
+	return ""
+}
+
+// IsAvailable checks if wkhtmltopdf is available on the system
+func (r *Renderer) IsAvailable() bool {
+	if r.path == "" {
+		return false
+	}
+	return exec.Command(r.path, "--version").Run() == nil
+}
+
+// GetVersion returns the wkhtmltopdf version
+func (r *Renderer) GetVersion() (string, error) {
+	if r.path == "" {
+		return "", fmt.Errorf("wkhtmltopdf not found")
+	}
+
+	output, err := exec.Command(r.path, "--version").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get wkhtmltopdf version: %w", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// Render prints the HTML file at htmlPath to a PDF at outputPath. Local files
+// referenced by the page, such as images, are allowed.
+func (r *Renderer) Render(ctx context.Context, htmlPath, outputPath string) error {
+	if r.path == "" {
+		return fmt.Errorf("wkhtmltopdf not found")
+	}
+
+	args := []string{"--quiet", "--enable-local-file-access", "--encoding", "utf-8", htmlPath, outputPath}
+	r.log.Debug("wkhtmltopdf command: %s %s", r.path, strings.Join(args, " "))
+
+	cmd := exec.CommandContext(ctx, r.path, args...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		r.log.Error("wkhtmltopdf failed: %v: %s", err, output)
+		return fmt.Errorf("PDF rendering failed: %w", err)
+	}
+	return nil
+}
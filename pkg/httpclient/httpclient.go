@@ -0,0 +1,123 @@
+// Package httpclient creates the HTTP clients of the app's network features,
+// such as update checks, so they all honor the proxy and TLS settings.
+package httpclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+)
+
+// Options configures the clients a Factory creates
+type Options struct {
+	// ProxyURL routes requests through an HTTP, HTTPS or SOCKS5 proxy, e.g.
+	// "socks5://localhost:1080". Empty uses the system's proxy environment
+	// variables (HTTP_PROXY, HTTPS_PROXY and NO_PROXY).
+	ProxyURL string
+
+	// CAFile is a PEM file of certificate authorities trusted in addition to
+	// the system's, e.g. for a TLS-intercepting corporate proxy
+	CAFile string
+}
+
+// Factory creates HTTP clients from the current options. Options can change
+// at runtime; clients created earlier keep the options they were created with.
+type Factory struct {
+	mu        sync.RWMutex
+	transport *http.Transport
+}
+
+// NewFactory creates a Factory. Invalid options return an error and leave a
+// factory with the default options.
+func NewFactory(opts Options) (*Factory, error) {
+	f := &Factory{transport: defaultTransport()}
+	return f, f.SetOptions(opts)
+}
+
+// SetOptions validates and applies new options
+func (f *Factory) SetOptions(opts Options) error {
+	transport, err := newTransport(opts)
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	previous := f.transport
+	f.transport = transport
+	f.mu.Unlock()
+
+	previous.CloseIdleConnections()
+	return nil
+}
+
+// Client returns an HTTP client that gives up on requests after timeout
+// (0 = no timeout)
+func (f *Factory) Client(timeout time.Duration) *http.Client {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return &http.Client{Transport: f.transport, Timeout: timeout}
+}
+
+// Validate checks options without applying them
+func Validate(opts Options) error {
+	_, err := newTransport(opts)
+	return err
+}
+
+// defaultTransport returns a transport with the system's proxy and CAs
+func defaultTransport() *http.Transport {
+	return http.DefaultTransport.(*http.Transport).Clone()
+}
+
+// newTransport creates a transport for the options
+func newTransport(opts Options) (*http.Transport, error) {
+	transport := defaultTransport()
+
+	if opts.ProxyURL != "" {
+		proxy, err := url.Parse(opts.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL: %w", err)
+		}
+		switch proxy.Scheme {
+		case "http", "https", "socks5", "socks5h":
+		default:
+			return nil, fmt.Errorf("unsupported proxy scheme %q, use http, https or socks5", proxy.Scheme)
+		}
+		if proxy.Host == "" {
+			return nil, fmt.Errorf("proxy URL has no host: %s", opts.ProxyURL)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+
+	if opts.CAFile != "" {
+		pool, err := loadCertPool(opts.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+	}
+
+	return transport, nil
+}
+
+// loadCertPool returns the system's CAs plus those in a PEM file
+func loadCertPool(path string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA file: %w", err)
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no PEM certificates found in %s", path)
+	}
+	return pool, nil
+}
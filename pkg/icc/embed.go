@@ -0,0 +1,255 @@
+package icc
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+	"sort"
+)
+
+const (
+	// jpegICCMarker identifies the APP2 segments a JPEG's profile is split
+	// across, each followed by its sequence number and the segment count
+	jpegICCMarker = "ICC_PROFILE\x00"
+
+	// jpegMaxChunk is the most profile data one APP2 segment carries
+	jpegMaxChunk = 65535 - 2 - len(jpegICCMarker) - 2
+
+	// tiffICCTag is the TIFF tag holding the profile
+	tiffICCTag = 34675
+)
+
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// Extract returns the profile embedded in an encoded image, or nil if it
+// has none. format is the file extension without the dot, e.g. "jpg".
+func Extract(format string, data []byte) []byte {
+	switch format {
+	case "jpg", "jpeg":
+		return extractJPEG(data)
+	case "png":
+		return extractPNG(data)
+	case "webp":
+		return extractWebP(data)
+	case "tif", "tiff":
+		return extractTIFF(data)
+	}
+	return nil
+}
+
+// CanEmbed reports whether Embed supports a format
+func CanEmbed(format string) bool {
+	switch format {
+	case "jpg", "jpeg", "png":
+		return true
+	}
+	return false
+}
+
+// Embed returns an encoded image with a profile embedded. The image must
+// have been written by Go's encoder, which embeds no profile of its own.
+func Embed(format string, data, profile []byte) ([]byte, error) {
+	switch format {
+	case "jpg", "jpeg":
+		return embedJPEG(data, profile)
+	case "png":
+		return embedPNG(data, profile)
+	}
+	return nil, errors.New("format can't carry a color profile")
+}
+
+// extractJPEG joins the profile chunks of a JPEG's APP2 segments
+func extractJPEG(data []byte) []byte {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return nil
+	}
+
+	chunks := make(map[int][]byte)
+	for pos := 2; pos+4 <= len(data); {
+		if data[pos] != 0xFF {
+			return nil
+		}
+		marker := data[pos+1]
+		if marker == 0xDA || marker == 0xD9 { // Start of scan, end of image
+			break
+		}
+		length := int(binary.BigEndian.Uint16(data[pos+2:]))
+		if length < 2 || pos+2+length > len(data) {
+			return nil
+		}
+		segment := data[pos+4 : pos+2+length]
+		if marker == 0xE2 && len(segment) > len(jpegICCMarker)+2 && string(segment[:len(jpegICCMarker)]) == jpegICCMarker {
+			chunks[int(segment[len(jpegICCMarker)])] = segment[len(jpegICCMarker)+2:]
+		}
+		pos += 2 + length
+	}
+	if len(chunks) == 0 {
+		return nil
+	}
+
+	seqs := make([]int, 0, len(chunks))
+	for seq := range chunks {
+		seqs = append(seqs, seq)
+	}
+	sort.Ints(seqs)
+
+	var profile []byte
+	for _, seq := range seqs {
+		profile = append(profile, chunks[seq]...)
+	}
+	return profile
+}
+
+// embedJPEG inserts the profile as APP2 segments right after the start of
+// image marker
+func embedJPEG(data, profile []byte) ([]byte, error) {
+	if len(data) < 2 || data[0] != 0xFF || data[1] != 0xD8 {
+		return nil, errors.New("not a JPEG image")
+	}
+	count := (len(profile) + jpegMaxChunk - 1) / jpegMaxChunk
+	if count > 255 {
+		return nil, errors.New("color profile is too large for a JPEG")
+	}
+
+	var out bytes.Buffer
+	out.Write(data[:2])
+	for i := 0; i < count; i++ {
+		chunk := profile[i*jpegMaxChunk : min((i+1)*jpegMaxChunk, len(profile))]
+		out.Write([]byte{0xFF, 0xE2})
+		binary.Write(&out, binary.BigEndian, uint16(2+len(jpegICCMarker)+2+len(chunk)))
+		out.WriteString(jpegICCMarker)
+		out.Write([]byte{byte(i + 1), byte(count)})
+		out.Write(chunk)
+	}
+	out.Write(data[2:])
+	return out.Bytes(), nil
+}
+
+// extractPNG decompresses a PNG's iCCP chunk
+func extractPNG(data []byte) []byte {
+	if !bytes.HasPrefix(data, pngSignature) {
+		return nil
+	}
+
+	for pos := len(pngSignature); pos+8 <= len(data); {
+		length := int(binary.BigEndian.Uint32(data[pos:]))
+		kind := string(data[pos+4 : pos+8])
+		if length < 0 || pos+12+length > len(data) {
+			return nil
+		}
+		if kind == "IDAT" || kind == "IEND" {
+			return nil
+		}
+		if kind == "iCCP" {
+			chunk := data[pos+8 : pos+8+length]
+			name := bytes.IndexByte(chunk, 0)
+			if name < 0 || name+2 > len(chunk) {
+				return nil
+			}
+			r, err := zlib.NewReader(bytes.NewReader(chunk[name+2:]))
+			if err != nil {
+				return nil
+			}
+			defer r.Close()
+			profile, err := io.ReadAll(r)
+			if err != nil {
+				return nil
+			}
+			return profile
+		}
+		pos += 12 + length
+	}
+	return nil
+}
+
+// embedPNG inserts an iCCP chunk after the IHDR chunk
+func embedPNG(data, profile []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, pngSignature) || len(data) < len(pngSignature)+8 {
+		return nil, errors.New("not a PNG image")
+	}
+	ihdrEnd := len(pngSignature) + 12 + int(binary.BigEndian.Uint32(data[len(pngSignature):]))
+	if ihdrEnd > len(data) {
+		return nil, errors.New("truncated PNG image")
+	}
+
+	var chunk bytes.Buffer
+	chunk.WriteString("iCCP")
+	chunk.WriteString("ICC profile\x00\x00") // Name, then zlib compression
+	w := zlib.NewWriter(&chunk)
+	w.Write(profile)
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	out.Write(data[:ihdrEnd])
+	binary.Write(&out, binary.BigEndian, uint32(chunk.Len()-4))
+	out.Write(chunk.Bytes())
+	binary.Write(&out, binary.BigEndian, crc32.ChecksumIEEE(chunk.Bytes()))
+	out.Write(data[ihdrEnd:])
+	return out.Bytes(), nil
+}
+
+// extractWebP returns the ICCP chunk of an extended WebP
+func extractWebP(data []byte) []byte {
+	if len(data) < 12 || string(data[:4]) != "RIFF" || string(data[8:12]) != "WEBP" {
+		return nil
+	}
+
+	for pos := 12; pos+8 <= len(data); {
+		kind := string(data[pos : pos+4])
+		length := int(binary.LittleEndian.Uint32(data[pos+4:]))
+		if length < 0 || pos+8+length > len(data) {
+			return nil
+		}
+		if kind == "ICCP" {
+			return data[pos+8 : pos+8+length]
+		}
+		pos += 8 + length + length%2
+	}
+	return nil
+}
+
+// extractTIFF returns the profile tag of a TIFF's first image
+func extractTIFF(data []byte) []byte {
+	if len(data) < 8 {
+		return nil
+	}
+	var order binary.ByteOrder
+	switch string(data[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return nil
+	}
+
+	ifd := int(order.Uint32(data[4:]))
+	if ifd+2 > len(data) {
+		return nil
+	}
+	entries := int(order.Uint16(data[ifd:]))
+	for i := 0; i < entries; i++ {
+		entry := ifd + 2 + i*12
+		if entry+12 > len(data) {
+			return nil
+		}
+		if order.Uint16(data[entry:]) != tiffICCTag {
+			continue
+		}
+		count := int(order.Uint32(data[entry+4:]))
+		if count <= 4 {
+			return data[entry+8 : entry+8+count]
+		}
+		offset := int(order.Uint32(data[entry+8:]))
+		if offset < 0 || offset+count > len(data) {
+			return nil
+		}
+		return data[offset : offset+count]
+	}
+	return nil
+}
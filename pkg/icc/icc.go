@@ -0,0 +1,159 @@
+// Package icc reads, embeds and applies ICC color profiles of images.
+// Matrix/TRC RGB profiles, such as Display P3 and Adobe RGB (1998), can be
+// converted to sRGB; other profiles can only be carried over as they are.
+package icc
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"unicode/utf16"
+)
+
+// headerSize is the size of the fixed profile header, which the tag table
+// follows
+const headerSize = 128
+
+// Profile is a parsed ICC profile
+type Profile struct {
+	Data        []byte // The profile as embedded
+	Description string // e.g. "Display P3"
+	ColorSpace  string // Data color space, e.g. "RGB" or "CMYK"
+
+	// Matrix/TRC model, set when the profile has one
+	matrix [3][3]float64 // Linear RGB to PCS XYZ (D50)
+	curves [3]curve      // Per-channel tone curves to linear light
+	shaper bool
+}
+
+// Parse parses an ICC profile
+func Parse(data []byte) (*Profile, error) {
+	if len(data) < headerSize+4 || string(data[36:40]) != "acsp" {
+		return nil, errors.New("not an ICC profile")
+	}
+
+	p := &Profile{
+		Data:       data,
+		ColorSpace: strings.TrimSpace(string(data[16:20])),
+	}
+
+	tags := make(map[string][]byte)
+	count := int(binary.BigEndian.Uint32(data[headerSize:]))
+	for i := 0; i < count; i++ {
+		entry := headerSize + 4 + i*12
+		if entry+12 > len(data) {
+			return nil, errors.New("truncated ICC tag table")
+		}
+		offset := int(binary.BigEndian.Uint32(data[entry+4:]))
+		size := int(binary.BigEndian.Uint32(data[entry+8:]))
+		if offset < 0 || size < 0 || offset+size > len(data) {
+			return nil, fmt.Errorf("ICC tag %s is out of bounds", data[entry:entry+4])
+		}
+		tags[string(data[entry:entry+4])] = data[offset : offset+size]
+	}
+
+	p.Description = parseText(tags["desc"])
+	p.shaper = p.ColorSpace == "RGB" && string(data[20:24]) == "XYZ " && p.parseShaper(tags)
+	return p, nil
+}
+
+// parseShaper reads the colorants and tone curves of a matrix/TRC profile,
+// reporting whether the profile has a complete set
+func (p *Profile) parseShaper(tags map[string][]byte) bool {
+	for i, prefix := range []string{"r", "g", "b"} {
+		xyz, ok := parseXYZ(tags[prefix+"XYZ"])
+		if !ok {
+			return false
+		}
+		for row := 0; row < 3; row++ {
+			p.matrix[row][i] = xyz[row]
+		}
+
+		c, ok := parseCurve(tags[prefix+"TRC"])
+		if !ok {
+			return false
+		}
+		p.curves[i] = c
+	}
+	return true
+}
+
+// CanConvert reports whether colors in the profile can be converted to sRGB
+func (p *Profile) CanConvert() bool {
+	return p.shaper
+}
+
+// IsSRGB reports whether the profile describes sRGB, so its colors need no
+// conversion
+func (p *Profile) IsSRGB() bool {
+	if !p.shaper {
+		return false
+	}
+	for row := 0; row < 3; row++ {
+		for col := 0; col < 3; col++ {
+			if math.Abs(p.matrix[row][col]-srgbMatrix[row][col]) > 0.002 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Name returns the profile's description, or a generic name when it has none
+func (p *Profile) Name() string {
+	if p.Description != "" {
+		return p.Description
+	}
+	return p.ColorSpace + " profile"
+}
+
+// parseXYZ reads an XYZType tag
+func parseXYZ(tag []byte) ([3]float64, bool) {
+	var xyz [3]float64
+	if len(tag) < 20 || string(tag[:4]) != "XYZ " {
+		return xyz, false
+	}
+	for i := range xyz {
+		xyz[i] = s15Fixed16(tag[8+i*4:])
+	}
+	return xyz, true
+}
+
+// parseText reads a textDescriptionType (ICC v2) or the first record of a
+// multiLocalizedUnicodeType (ICC v4) tag
+func parseText(tag []byte) string {
+	if len(tag) < 12 {
+		return ""
+	}
+
+	switch string(tag[:4]) {
+	case "desc":
+		n := int(binary.BigEndian.Uint32(tag[8:]))
+		if n <= 0 || 12+n > len(tag) {
+			return ""
+		}
+		return strings.TrimRight(string(tag[12:12+n]), "\x00")
+	case "mluc":
+		if len(tag) < 28 || binary.BigEndian.Uint32(tag[8:]) == 0 {
+			return ""
+		}
+		n := int(binary.BigEndian.Uint32(tag[20:]))
+		offset := int(binary.BigEndian.Uint32(tag[24:]))
+		if offset+n > len(tag) {
+			return ""
+		}
+		units := make([]uint16, n/2)
+		for i := range units {
+			units[i] = binary.BigEndian.Uint16(tag[offset+i*2:])
+		}
+		return strings.TrimRight(string(utf16.Decode(units)), "\x00")
+	}
+	return ""
+}
+
+// s15Fixed16 reads a signed 15.16 fixed-point number
+func s15Fixed16(b []byte) float64 {
+	return float64(int32(binary.BigEndian.Uint32(b))) / 65536
+}
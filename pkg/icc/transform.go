@@ -0,0 +1,224 @@
+package icc
+
+import (
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	"math"
+)
+
+// srgbMatrix is sRGB's linear RGB to PCS XYZ matrix, adapted to D50 as in
+// the sRGB profiles ICC publishes
+var srgbMatrix = [3][3]float64{
+	{0.4360747, 0.3850649, 0.1430804},
+	{0.2225045, 0.7168786, 0.0606169},
+	{0.0139322, 0.0971045, 0.7141733},
+}
+
+// curve is a tone curve from encoded values to linear light, both 0-1
+type curve struct {
+	gamma  float64   // Pure power curve when table and params are empty
+	table  []float64 // Sampled curve, interpolated linearly
+	params []float64 // Parametric curve g, a, b, c, d, e, f
+	kind   int       // Parametric function type, 0-4
+}
+
+// parseCurve reads a curveType or parametricCurveType tag
+func parseCurve(tag []byte) (curve, bool) {
+	if len(tag) < 12 {
+		return curve{}, false
+	}
+
+	switch string(tag[:4]) {
+	case "curv":
+		n := int(binary.BigEndian.Uint32(tag[8:]))
+		switch {
+		case n == 0:
+			return curve{gamma: 1}, true
+		case n == 1 && len(tag) >= 14:
+			return curve{gamma: float64(binary.BigEndian.Uint16(tag[12:])) / 256}, true
+		case len(tag) >= 12+n*2:
+			table := make([]float64, n)
+			for i := range table {
+				table[i] = float64(binary.BigEndian.Uint16(tag[12+i*2:])) / 65535
+			}
+			return curve{table: table}, true
+		}
+	case "para":
+		kind := int(binary.BigEndian.Uint16(tag[8:]))
+		counts := []int{1, 3, 4, 5, 7}
+		if kind >= len(counts) || len(tag) < 12+counts[kind]*4 {
+			return curve{}, false
+		}
+		params := make([]float64, counts[kind])
+		for i := range params {
+			params[i] = s15Fixed16(tag[12+i*4:])
+		}
+		return curve{params: params, kind: kind}, true
+	}
+	return curve{}, false
+}
+
+// apply maps an encoded value to linear light
+func (c curve) apply(x float64) float64 {
+	switch {
+	case c.table != nil:
+		pos := x * float64(len(c.table)-1)
+		i := int(pos)
+		if i >= len(c.table)-1 {
+			return c.table[len(c.table)-1]
+		}
+		frac := pos - float64(i)
+		return c.table[i]*(1-frac) + c.table[i+1]*frac
+	case c.params != nil:
+		return c.parametric(x)
+	}
+	return math.Pow(x, c.gamma)
+}
+
+// parametric evaluates a parametric curve of ICC.1 table 68
+func (c curve) parametric(x float64) float64 {
+	p := c.params
+	g := p[0]
+	switch c.kind {
+	case 0:
+		return math.Pow(x, g)
+	case 1:
+		if x >= -p[2]/p[1] {
+			return math.Pow(p[1]*x+p[2], g)
+		}
+		return 0
+	case 2:
+		if x >= -p[2]/p[1] {
+			return math.Pow(p[1]*x+p[2], g) + p[3]
+		}
+		return p[3]
+	case 3:
+		if x >= p[4] {
+			return math.Pow(p[1]*x+p[2], g)
+		}
+		return p[3] * x
+	default:
+		if x >= p[4] {
+			return math.Pow(p[1]*x+p[2], g) + p[5]
+		}
+		return p[3]*x + p[6]
+	}
+}
+
+// ToSRGB converts an image's colors from the profile to sRGB. Colors
+// outside sRGB's gamut are clipped. 16-bit images stay 16-bit.
+func (p *Profile) ToSRGB(img image.Image) (image.Image, error) {
+	if !p.shaper {
+		return nil, errors.New("only matrix/TRC RGB profiles can be converted")
+	}
+
+	m := multiply(invert(srgbMatrix), p.matrix)
+
+	// Lookup tables from 16-bit encoded values to linear light, and from
+	// linear light back to sRGB-encoded 16-bit values
+	var toLinear [3][]float32
+	for i := range toLinear {
+		toLinear[i] = make([]float32, 65536)
+		for v := range toLinear[i] {
+			toLinear[i][v] = float32(p.curves[i].apply(float64(v) / 65535))
+		}
+	}
+	fromLinear := make([]uint16, 65536)
+	for v := range fromLinear {
+		fromLinear[v] = uint16(math.Round(srgbEncode(float64(v)/65535) * 65535))
+	}
+	encode := func(x float64) uint16 {
+		return fromLinear[int(math.Round(math.Max(0, math.Min(1, x))*65535))]
+	}
+
+	bounds := img.Bounds()
+	deep := isDeep(img)
+	var out draw16
+	if deep {
+		out = image.NewNRGBA64(bounds)
+	} else {
+		out = nrgba{image.NewNRGBA(bounds)}
+	}
+
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := color.NRGBA64Model.Convert(img.At(x, y)).(color.NRGBA64)
+			r := float64(toLinear[0][c.R])
+			g := float64(toLinear[1][c.G])
+			b := float64(toLinear[2][c.B])
+			out.SetNRGBA64(x, y, color.NRGBA64{
+				R: encode(m[0][0]*r + m[0][1]*g + m[0][2]*b),
+				G: encode(m[1][0]*r + m[1][1]*g + m[1][2]*b),
+				B: encode(m[2][0]*r + m[2][1]*g + m[2][2]*b),
+				A: c.A,
+			})
+		}
+	}
+	return out, nil
+}
+
+// draw16 is an image that 16-bit colors are written to
+type draw16 interface {
+	image.Image
+	SetNRGBA64(x, y int, c color.NRGBA64)
+}
+
+// nrgba writes 16-bit colors to an 8-bit image
+type nrgba struct {
+	*image.NRGBA
+}
+
+func (n nrgba) SetNRGBA64(x, y int, c color.NRGBA64) {
+	n.SetNRGBA(x, y, color.NRGBA{R: uint8(c.R >> 8), G: uint8(c.G >> 8), B: uint8(c.B >> 8), A: uint8(c.A >> 8)})
+}
+
+// isDeep reports whether an image has more than 8 bits per channel
+func isDeep(img image.Image) bool {
+	switch img.(type) {
+	case *image.RGBA64, *image.NRGBA64, *image.Gray16:
+		return true
+	}
+	return false
+}
+
+// srgbEncode applies the sRGB transfer function to linear light
+func srgbEncode(x float64) float64 {
+	if x <= 0.0031308 {
+		return 12.92 * x
+	}
+	return 1.055*math.Pow(x, 1/2.4) - 0.055
+}
+
+// multiply returns a×b
+func multiply(a, b [3][3]float64) [3][3]float64 {
+	var m [3][3]float64
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			for k := 0; k < 3; k++ {
+				m[i][j] += a[i][k] * b[k][j]
+			}
+		}
+	}
+	return m
+}
+
+// invert returns the inverse of a non-singular matrix
+func invert(a [3][3]float64) [3][3]float64 {
+	det := a[0][0]*(a[1][1]*a[2][2]-a[1][2]*a[2][1]) -
+		a[0][1]*(a[1][0]*a[2][2]-a[1][2]*a[2][0]) +
+		a[0][2]*(a[1][0]*a[2][1]-a[1][1]*a[2][0])
+
+	var m [3][3]float64
+	m[0][0] = (a[1][1]*a[2][2] - a[1][2]*a[2][1]) / det
+	m[0][1] = (a[0][2]*a[2][1] - a[0][1]*a[2][2]) / det
+	m[0][2] = (a[0][1]*a[1][2] - a[0][2]*a[1][1]) / det
+	m[1][0] = (a[1][2]*a[2][0] - a[1][0]*a[2][2]) / det
+	m[1][1] = (a[0][0]*a[2][2] - a[0][2]*a[2][0]) / det
+	m[1][2] = (a[0][2]*a[1][0] - a[0][0]*a[1][2]) / det
+	m[2][0] = (a[1][0]*a[2][1] - a[1][1]*a[2][0]) / det
+	m[2][1] = (a[0][1]*a[2][0] - a[0][0]*a[2][1]) / det
+	m[2][2] = (a[0][0]*a[1][1] - a[0][1]*a[1][0]) / det
+	return m
+}
@@ -0,0 +1,86 @@
+// Package iolimit limits the rate at which data is read or written, so
+// conversions on network shares and external drives don't saturate the link.
+package iolimit
+
+import (
+	"io"
+	"time"
+)
+
+// chunksPerSecond splits each second's allowance into chunks, so a large
+// read or write is spread out instead of arriving in one burst per second
+const chunksPerSecond = 10
+
+// limiter paces transfers to an average of rate bytes per second since the
+// first transfer
+type limiter struct {
+	rate  int64
+	start time.Time
+	total int64
+}
+
+// chunk returns the largest transfer allowed at once
+func (l *limiter) chunk(n int) int {
+	return min(n, max(1, int(l.rate/chunksPerSecond)))
+}
+
+// wait records n transferred bytes and sleeps until the average rate is back
+// under the limit
+func (l *limiter) wait(n int) {
+	if l.start.IsZero() {
+		l.start = time.Now()
+	}
+	l.total += int64(n)
+
+	due := time.Duration(float64(l.total) / float64(l.rate) * float64(time.Second))
+	if ahead := due - time.Since(l.start); ahead > 0 {
+		time.Sleep(ahead)
+	}
+}
+
+type reader struct {
+	r io.Reader
+	limiter
+}
+
+// Reader returns a reader that reads from r at no more than bytesPerSecond.
+// A limit of 0 or less returns r unchanged.
+func Reader(r io.Reader, bytesPerSecond int64) io.Reader {
+	if bytesPerSecond <= 0 {
+		return r
+	}
+	return &reader{r: r, limiter: limiter{rate: bytesPerSecond}}
+}
+
+func (r *reader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p[:r.chunk(len(p))])
+	r.wait(n)
+	return n, err
+}
+
+type writer struct {
+	w io.Writer
+	limiter
+}
+
+// Writer returns a writer that writes to w at no more than bytesPerSecond.
+// A limit of 0 or less returns w unchanged.
+func Writer(w io.Writer, bytesPerSecond int64) io.Writer {
+	if bytesPerSecond <= 0 {
+		return w
+	}
+	return &writer{w: w, limiter: limiter{rate: bytesPerSecond}}
+}
+
+func (w *writer) Write(p []byte) (int, error) {
+	written := 0
+	for written < len(p) {
+		n, err := w.w.Write(p[written : written+w.chunk(len(p)-written)])
+		written += n
+		w.wait(n)
+		if err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
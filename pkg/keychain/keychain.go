@@ -0,0 +1,20 @@
+// Package keychain stores small secrets, such as encryption keys, in the
+// operating system's credential store: the macOS Keychain, the Windows
+// Credential Manager or the Secret Service (via secret-tool) elsewhere.
+package keychain
+
+import "errors"
+
+// ErrNotFound is returned by Get when no secret is stored for the service
+// and account
+var ErrNotFound = errors.New("secret not found in keychain")
+
+// Get returns the secret stored for a service and account
+func Get(service, account string) (string, error) {
+	return get(service, account)
+}
+
+// Set stores a secret for a service and account, replacing any earlier one
+func Set(service, account, secret string) error {
+	return set(service, account, secret)
+}
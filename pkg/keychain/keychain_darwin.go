@@ -0,0 +1,132 @@
+//go:build darwin && cgo
+
+package keychain
+
+/*
+#cgo LDFLAGS: -framework Security -framework CoreFoundation
+
+#include <Security/Security.h>
+#include <stdlib.h>
+#include <string.h>
+
+// czQuery returns a query for the generic password item of a service and
+// account, which the caller releases
+static CFMutableDictionaryRef czQuery(const char* service, const char* account) {
+    CFMutableDictionaryRef query = CFDictionaryCreateMutable(NULL, 0,
+        &kCFTypeDictionaryKeyCallBacks, &kCFTypeDictionaryValueCallBacks);
+    CFStringRef serviceRef = CFStringCreateWithCString(NULL, service, kCFStringEncodingUTF8);
+    CFStringRef accountRef = CFStringCreateWithCString(NULL, account, kCFStringEncodingUTF8);
+    CFDictionarySetValue(query, kSecClass, kSecClassGenericPassword);
+    CFDictionarySetValue(query, kSecAttrService, serviceRef);
+    CFDictionarySetValue(query, kSecAttrAccount, accountRef);
+    CFRelease(serviceRef);
+    CFRelease(accountRef);
+    return query;
+}
+
+// czGet copies an item's secret into memory the caller frees
+static OSStatus czGet(const char* service, const char* account, void** secret, size_t* length) {
+    CFMutableDictionaryRef query = czQuery(service, account);
+    CFDictionarySetValue(query, kSecReturnData, kCFBooleanTrue);
+    CFDictionarySetValue(query, kSecMatchLimit, kSecMatchLimitOne);
+
+    CFTypeRef result = NULL;
+    OSStatus status = SecItemCopyMatching(query, &result);
+    CFRelease(query);
+    if (status != errSecSuccess) {
+        return status;
+    }
+
+    *length = CFDataGetLength((CFDataRef)result);
+    *secret = malloc(*length + 1);
+    memcpy(*secret, CFDataGetBytePtr((CFDataRef)result), *length);
+    CFRelease(result);
+    return errSecSuccess;
+}
+
+// czSet updates an item's secret, adding the item if there is none
+static OSStatus czSet(const char* service, const char* account, const void* secret, size_t length) {
+    CFMutableDictionaryRef query = czQuery(service, account);
+    CFDataRef data = CFDataCreate(NULL, secret, length);
+    CFMutableDictionaryRef update = CFDictionaryCreateMutable(NULL, 0,
+        &kCFTypeDictionaryKeyCallBacks, &kCFTypeDictionaryValueCallBacks);
+    CFDictionarySetValue(update, kSecValueData, data);
+
+    OSStatus status = SecItemUpdate(query, update);
+    if (status == errSecItemNotFound) {
+        CFDictionarySetValue(query, kSecValueData, data);
+        status = SecItemAdd(query, NULL);
+    }
+    CFRelease(update);
+    CFRelease(data);
+    CFRelease(query);
+    return status;
+}
+
+// czMessage describes a status in memory the caller frees
+static char* czMessage(OSStatus status) {
+    CFStringRef message = SecCopyErrorMessageString(status, NULL);
+    if (message == NULL) {
+        return NULL;
+    }
+    CFIndex size = CFStringGetMaximumSizeForEncoding(CFStringGetLength(message), kCFStringEncodingUTF8) + 1;
+    char* buf = malloc(size);
+    if (!CFStringGetCString(message, buf, size, kCFStringEncodingUTF8)) {
+        buf[0] = 0;
+    }
+    CFRelease(message);
+    return buf;
+}
+*/
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// get reads a generic password item with the Security framework. Items
+// earlier versions stored with security(1) trust only that tool, so macOS
+// asks once before letting the app read them.
+func get(service, account string) (string, error) {
+	cService, cAccount := C.CString(service), C.CString(account)
+	defer C.free(unsafe.Pointer(cService))
+	defer C.free(unsafe.Pointer(cAccount))
+
+	var secret unsafe.Pointer
+	var length C.size_t
+	status := C.czGet(cService, cAccount, &secret, &length)
+	if status == C.errSecItemNotFound {
+		return "", ErrNotFound
+	}
+	if status != C.errSecSuccess {
+		return "", fmt.Errorf("failed to read keychain item: %s", statusMessage(status))
+	}
+	defer C.free(secret)
+	return C.GoStringN((*C.char)(secret), C.int(length)), nil
+}
+
+// set adds or updates a generic password item with the Security framework,
+// so the secret never appears on a command line
+func set(service, account, secret string) error {
+	cService, cAccount := C.CString(service), C.CString(account)
+	defer C.free(unsafe.Pointer(cService))
+	defer C.free(unsafe.Pointer(cAccount))
+	cSecret := C.CBytes([]byte(secret))
+	defer C.free(cSecret)
+
+	if status := C.czSet(cService, cAccount, cSecret, C.size_t(len(secret))); status != C.errSecSuccess {
+		return fmt.Errorf("failed to write keychain item: %s", statusMessage(status))
+	}
+	return nil
+}
+
+// statusMessage describes a Security framework status code
+func statusMessage(status C.OSStatus) string {
+	message := C.czMessage(status)
+	if message == nil {
+		return fmt.Sprintf("OSStatus %d", int(status))
+	}
+	defer C.free(unsafe.Pointer(message))
+	return fmt.Sprintf("%s (OSStatus %d)", C.GoString(message), int(status))
+}
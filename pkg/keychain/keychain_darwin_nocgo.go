@@ -0,0 +1,17 @@
+//go:build darwin && !cgo
+
+package keychain
+
+import "errors"
+
+// errNoCgo is returned on macOS builds without cgo, which can't call the
+// Security framework
+var errNoCgo = errors.New("the macOS keychain requires a cgo build")
+
+func get(service, account string) (string, error) {
+	return "", errNoCgo
+}
+
+func set(service, account, secret string) error {
+	return errNoCgo
+}
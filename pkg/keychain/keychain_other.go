@@ -0,0 +1,35 @@
+//go:build !darwin && !windows
+
+package keychain
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// get looks a secret up in the Secret Service with secret-tool(1), which
+// exits with status 1 and no output when there is none
+func get(service, account string) (string, error) {
+	output, err := exec.Command("secret-tool", "lookup", "service", service, "account", account).Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) == 0 {
+			return "", ErrNotFound
+		}
+		return "", fmt.Errorf("failed to read secret: %w", err)
+	}
+	return strings.TrimSuffix(string(output), "\n"), nil
+}
+
+// set stores a secret in the Secret Service with secret-tool(1), passing
+// it on stdin so it doesn't show up in the process list
+func set(service, account, secret string) error {
+	cmd := exec.Command("secret-tool", "store", "--label", service+" "+account, "service", service, "account", account)
+	cmd.Stdin = strings.NewReader(secret)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to store secret: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package libreoffice
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"converzen/internal/logger"
+	"converzen/pkg/scratch"
+)
+
+// LibreOffice wraps headless LibreOffice (soffice) command execution
+type LibreOffice struct {
+	path string
+	log  *logger.ComponentLogger
+}
+
+// New creates a new LibreOffice instance
+func New(sofficePath string, log *logger.Logger) *LibreOffice {
+	return &LibreOffice{
+		path: sofficePath,
+		log:  log.WithComponent("libreoffice"),
+	}
+}
+
+// Find returns the path to the soffice executable, checking common install
+// locations and then PATH. It returns an empty string if none is found.
+func Find() string {
+	var candidates []string
+
+	switch runtime.GOOS {
+	case "windows":
+		candidates = []string{
+			filepath.Join(os.Getenv("ProgramFiles"), "LibreOffice", "program", "soffice.exe"),
+			filepath.Join(os.Getenv("ProgramFiles(x86)"), "LibreOffice", "program", "soffice.exe"),
+		}
+	case "darwin":
+		candidates = []string{
+			"/Applications/LibreOffice.app/Contents/MacOS/soffice",
+		}
+	default:
+		candidates = []string{
+			"/usr/bin/soffice",
+			"/usr/bin/libreoffice",
+			"/usr/local/bin/soffice",
+			"/opt/libreoffice/program/soffice",
+			"/snap/bin/libreoffice",
+		}
+	}
+
+	for _, path := range candidates {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+
+	for _, name := range []string{"soffice", "libreoffice"} {
+		if path, err := exec.LookPath(name); err == nil {
+			return path
+		}
+	}
+
+	return ""
+}
+
+// IsAvailable checks if LibreOffice is available on the system
+func (l *LibreOffice) IsAvailable() bool {
+	if l.path == "" {
+		return false
+	}
+	if err := exec.Command(l.path, "--version").Run(); err != nil {
+		l.log.Error("LibreOffice is not available: %v", err)
+		return false
+	}
+	l.log.Info("LibreOffice is available at: %s", l.path)
+	return true
+}
+
+// GetVersion returns the LibreOffice version
+func (l *LibreOffice) GetVersion() (string, error) {
+	if l.path == "" {
+		return "", fmt.Errorf("LibreOffice not found")
+	}
+
+	output, err := exec.Command(l.path, "--version").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get LibreOffice version: %w", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// Convert converts a document and writes it to outputPath. convertTo is the
+// target extension, optionally with an export filter, e.g. "pdf" or
+// "pdf:writer_web_pdf_Export". Each conversion uses its own throwaway user
+// profile, so it works while LibreOffice is open and several can run at once.
+func (l *LibreOffice) Convert(ctx context.Context, inputPath, outputPath, convertTo string) error {
+	if l.path == "" {
+		return fmt.Errorf("LibreOffice not found")
+	}
+
+	l.log.Info("Converting document: %s -> %s", inputPath, outputPath)
+
+	workDir, err := scratch.MkdirTemp("soffice-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer os.RemoveAll(workDir)
+
+	profile := url.URL{Scheme: "file", Path: filepath.ToSlash(filepath.Join(workDir, "profile"))}
+	if runtime.GOOS == "windows" {
+		profile.Path = "/" + profile.Path
+	}
+	outDir := filepath.Join(workDir, "out")
+
+	args := []string{
+		"-env:UserInstallation=" + profile.String(),
+		"--headless", "--norestore", "--nologo",
+		"--convert-to", convertTo,
+		"--outdir", outDir,
+		inputPath,
+	}
+	l.log.Debug("LibreOffice command: %s %s", l.path, strings.Join(args, " "))
+
+	cmd := exec.CommandContext(ctx, l.path, args...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		l.log.Error("LibreOffice conversion failed: %v: %s", err, output)
+		return fmt.Errorf("conversion failed: %w", err)
+	}
+
+	// soffice names the output after the input file
+	base := strings.TrimSuffix(filepath.Base(inputPath), filepath.Ext(inputPath))
+	ext, _, _ := strings.Cut(convertTo, ":")
+	converted := filepath.Join(outDir, base+"."+ext)
+	if _, err := os.Stat(converted); err != nil {
+		return fmt.Errorf("LibreOffice produced no output for %s", filepath.Base(inputPath))
+	}
+
+	if err := moveFile(converted, outputPath); err != nil {
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+
+	l.log.Info("Document conversion completed: %s", outputPath)
+	return nil
+}
+
+// moveFile moves src to dst, copying when they are on different volumes
+func moveFile(src, dst string) error {
+	if err := os.Rename(src, dst); err == nil {
+		return nil
+	}
+
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(dst, data, 0644)
+}
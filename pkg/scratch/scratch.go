@@ -0,0 +1,99 @@
+// Package scratch manages the directory where conversions keep temporary
+// files such as two-pass logs, palettes, preview clips and expanded archives.
+package scratch
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Prefix starts the name of every temporary file and directory the app
+// creates, so stale ones can be told apart from other programs' files
+const Prefix = "converzen-"
+
+var (
+	dir string
+	mu  sync.RWMutex
+)
+
+// SetDir sets the scratch directory. An empty path selects the OS temp
+// directory.
+func SetDir(path string) {
+	mu.Lock()
+	defer mu.Unlock()
+	dir = path
+}
+
+// Dir returns the scratch directory, falling back to the OS temp directory
+// when none is set
+func Dir() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	if dir == "" {
+		return os.TempDir()
+	}
+	return dir
+}
+
+// CreateTemp creates a temporary file in the scratch directory. The pattern
+// follows os.CreateTemp and is prefixed with Prefix.
+func CreateTemp(pattern string) (*os.File, error) {
+	return os.CreateTemp(Dir(), Prefix+pattern)
+}
+
+// MkdirTemp creates a temporary directory in the scratch directory. The
+// pattern follows os.MkdirTemp and is prefixed with Prefix.
+func MkdirTemp(pattern string) (string, error) {
+	return os.MkdirTemp(Dir(), Prefix+pattern)
+}
+
+// Path returns the path of a named entry in the scratch directory, for
+// workspaces that are reused rather than created per job
+func Path(name string) string {
+	return filepath.Join(Dir(), Prefix+name)
+}
+
+// Validate checks that a directory exists and temporary files can be
+// created in it
+func Validate(path string) error {
+	if err := os.MkdirAll(path, 0755); err != nil {
+		return err
+	}
+	file, err := os.CreateTemp(path, Prefix+"probe-*")
+	if err != nil {
+		return err
+	}
+	file.Close()
+	return os.Remove(file.Name())
+}
+
+// CleanStale removes the app's temporary files and directories in the
+// scratch directory that haven't been modified for maxAge, e.g. those left
+// behind when the app was killed mid-conversion. It returns the number of
+// entries removed.
+func CleanStale(maxAge time.Duration) (int, error) {
+	root := Dir()
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return 0, err
+	}
+
+	cutoff := time.Now().Add(-maxAge)
+	removed := 0
+	for _, entry := range entries {
+		if !strings.HasPrefix(entry.Name(), Prefix) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || info.ModTime().After(cutoff) {
+			continue
+		}
+		if os.RemoveAll(filepath.Join(root, entry.Name())) == nil {
+			removed++
+		}
+	}
+	return removed, nil
+}
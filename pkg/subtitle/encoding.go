@@ -0,0 +1,59 @@
+package subtitle
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/unicode"
+)
+
+// DefaultLegacyCharset is assumed for files that are neither UTF-8 nor UTF-16
+const DefaultLegacyCharset = "windows-1250"
+
+// legacyCharsets lists the single-byte charsets subtitle files are commonly saved in
+var legacyCharsets = map[string]encoding.Encoding{
+	"windows-1250": charmap.Windows1250, // Central European
+	"windows-1251": charmap.Windows1251, // Cyrillic
+	"windows-1252": charmap.Windows1252, // Western European
+	"iso-8859-2":   charmap.ISO8859_2,
+	"iso-8859-1":   charmap.ISO8859_1,
+}
+
+// DecodeText converts subtitle file contents to UTF-8, returning the charset
+// it was read as. UTF-8 and UTF-16 are detected from byte order marks and
+// validity; anything else is decoded as fallback, or DefaultLegacyCharset
+// if fallback is empty.
+func DecodeText(data []byte, fallback string) (string, string, error) {
+	switch {
+	case bytes.HasPrefix(data, []byte{0xEF, 0xBB, 0xBF}):
+		return string(data[3:]), "utf-8", nil
+	case bytes.HasPrefix(data, []byte{0xFF, 0xFE}):
+		return decodeWith(unicode.UTF16(unicode.LittleEndian, unicode.ExpectBOM), data, "utf-16le")
+	case bytes.HasPrefix(data, []byte{0xFE, 0xFF}):
+		return decodeWith(unicode.UTF16(unicode.BigEndian, unicode.ExpectBOM), data, "utf-16be")
+	case utf8.Valid(data):
+		return string(data), "utf-8", nil
+	}
+
+	if fallback == "" {
+		fallback = DefaultLegacyCharset
+	}
+	enc, ok := legacyCharsets[strings.ToLower(fallback)]
+	if !ok {
+		return "", "", fmt.Errorf("unsupported subtitle charset: %s", fallback)
+	}
+	return decodeWith(enc, data, strings.ToLower(fallback))
+}
+
+// decodeWith decodes data with enc, returning name as the detected charset
+func decodeWith(enc encoding.Encoding, data []byte, name string) (string, string, error) {
+	decoded, err := enc.NewDecoder().Bytes(data)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to decode %s text: %w", name, err)
+	}
+	return string(decoded), name, nil
+}
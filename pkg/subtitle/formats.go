@@ -0,0 +1,280 @@
+package subtitle
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cueTimingRe matches an SRT or WebVTT timing line, e.g. "00:00:01,000 --> 00:00:04,000"
+var cueTimingRe = regexp.MustCompile(`^\s*([\d:.,]+)\s*-->\s*([\d:.,]+)`)
+
+// parseSRT parses SubRip text
+func parseSRT(text string) ([]Cue, error) {
+	var cues []Cue
+	for _, block := range splitBlocks(text) {
+		// The numeric counter is optional in practice
+		if len(block) > 1 && !cueTimingRe.MatchString(block[0]) {
+			block = block[1:]
+		}
+		cue, err := parseTimedBlock(block)
+		if err != nil {
+			return nil, err
+		}
+		cues = append(cues, cue)
+	}
+	return cues, nil
+}
+
+// parseVTT parses WebVTT text, skipping the header, notes and style blocks
+func parseVTT(text string) ([]Cue, error) {
+	if !strings.HasPrefix(text, "WEBVTT") {
+		return nil, fmt.Errorf("missing WEBVTT header")
+	}
+
+	var cues []Cue
+	for _, block := range splitBlocks(text)[1:] {
+		switch {
+		case strings.HasPrefix(block[0], "NOTE"), strings.HasPrefix(block[0], "STYLE"), strings.HasPrefix(block[0], "REGION"):
+			continue
+		case len(block) > 1 && !cueTimingRe.MatchString(block[0]):
+			block = block[1:] // Cue identifier
+		}
+		cue, err := parseTimedBlock(block)
+		if err != nil {
+			return nil, err
+		}
+		cues = append(cues, cue)
+	}
+	return cues, nil
+}
+
+// parseTimedBlock parses a timing line followed by the cue's text lines
+func parseTimedBlock(block []string) (Cue, error) {
+	matches := cueTimingRe.FindStringSubmatch(block[0])
+	if matches == nil {
+		return Cue{}, fmt.Errorf("invalid cue timing: %q", block[0])
+	}
+
+	start, err := parseClock(matches[1])
+	if err != nil {
+		return Cue{}, err
+	}
+	end, err := parseClock(matches[2])
+	if err != nil {
+		return Cue{}, err
+	}
+	return Cue{Start: start, End: end, Text: strings.Join(block[1:], "\n")}, nil
+}
+
+// formatSRT writes cues as SubRip text
+func formatSRT(cues []Cue) string {
+	var b strings.Builder
+	for i, cue := range cues {
+		fmt.Fprintf(&b, "%d\n%s --> %s\n%s\n\n", i+1,
+			formatClock(cue.Start, ",", 3), formatClock(cue.End, ",", 3), cue.Text)
+	}
+	return b.String()
+}
+
+// formatVTT writes cues as WebVTT text
+func formatVTT(cues []Cue) string {
+	var b strings.Builder
+	b.WriteString("WEBVTT\n\n")
+	for _, cue := range cues {
+		fmt.Fprintf(&b, "%s --> %s\n%s\n\n",
+			formatClock(cue.Start, ".", 3), formatClock(cue.End, ".", 3), cue.Text)
+	}
+	return b.String()
+}
+
+// assOverrideRe matches ASS override blocks such as "{\an8}" or "{\i1}"
+var assOverrideRe = regexp.MustCompile(`\{[^}]*\}`)
+
+// assStyleTags maps ASS style overrides to the HTML-style tags used in Cue text
+var assStyleTags = strings.NewReplacer(
+	`{\i1}`, "<i>", `{\i0}`, "</i>",
+	`{\b1}`, "<b>", `{\b0}`, "</b>",
+	`{\u1}`, "<u>", `{\u0}`, "</u>",
+)
+
+// htmlStyleTags maps Cue text tags back to ASS style overrides
+var htmlStyleTags = strings.NewReplacer(
+	"<i>", `{\i1}`, "</i>", `{\i0}`,
+	"<b>", `{\b1}`, "</b>", `{\b0}`,
+	"<u>", `{\u1}`, "</u>", `{\u0}`,
+)
+
+// htmlTagRe matches tags left in Cue text that ASS has no equivalent for
+var htmlTagRe = regexp.MustCompile(`</?[a-zA-Z][^>]*>`)
+
+// parseASS parses the dialogue lines of an ASS or SSA script
+func parseASS(text string) ([]Cue, error) {
+	// Default [Events] field order, used if the script has no Format line
+	fields := []string{"layer", "start", "end", "style", "name", "marginl", "marginr", "marginv", "effect", "text"}
+	inEvents := false
+
+	var cues []Cue
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "[") {
+			inEvents = strings.EqualFold(line, "[Events]")
+			continue
+		}
+		if !inEvents {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		switch strings.TrimSpace(key) {
+		case "Format":
+			fields = fields[:0]
+			for _, field := range strings.Split(value, ",") {
+				fields = append(fields, strings.ToLower(strings.TrimSpace(field)))
+			}
+		case "Dialogue":
+			// Text is always the last field and may itself contain commas
+			values := strings.SplitN(strings.TrimSpace(value), ",", len(fields))
+			if len(values) != len(fields) {
+				return nil, fmt.Errorf("invalid dialogue line: %q", line)
+			}
+
+			var cue Cue
+			for i, field := range fields {
+				var err error
+				switch field {
+				case "start":
+					cue.Start, err = parseClock(values[i])
+				case "end":
+					cue.End, err = parseClock(values[i])
+				case "text":
+					text := assStyleTags.Replace(values[i])
+					text = assOverrideRe.ReplaceAllString(text, "")
+					text = strings.NewReplacer(`\N`, "\n", `\n`, "\n", `\h`, " ").Replace(text)
+					cue.Text = text
+				}
+				if err != nil {
+					return nil, err
+				}
+			}
+			cues = append(cues, cue)
+		}
+	}
+	return cues, nil
+}
+
+// assHeader is the script header written before converted dialogue
+const assHeader = `[Script Info]
+ScriptType: v4.00+
+WrapStyle: 0
+ScaledBorderAndShadow: yes
+PlayResX: 1920
+PlayResY: 1080
+
+[V4+ Styles]
+Format: Name, Fontname, Fontsize, PrimaryColour, SecondaryColour, OutlineColour, BackColour, Bold, Italic, Underline, StrikeOut, ScaleX, ScaleY, Spacing, Angle, BorderStyle, Outline, Shadow, Alignment, MarginL, MarginR, MarginV, Encoding
+Style: Default,Arial,64,&H00FFFFFF,&H000000FF,&H00000000,&H80000000,0,0,0,0,100,100,0,0,1,3,1,2,60,60,50,1
+
+[Events]
+Format: Layer, Start, End, Style, Name, MarginL, MarginR, MarginV, Effect, Text
+`
+
+// formatASS writes cues as an ASS script with a single default style
+func formatASS(cues []Cue) string {
+	var b strings.Builder
+	b.WriteString(assHeader)
+	for _, cue := range cues {
+		text := htmlStyleTags.Replace(cue.Text)
+		text = htmlTagRe.ReplaceAllString(text, "")
+		text = strings.ReplaceAll(text, "\n", `\N`)
+
+		// ASS timestamps have a single-digit hour and centiseconds
+		fmt.Fprintf(&b, "Dialogue: 0,%s,%s,Default,,0,0,0,,%s\n",
+			strings.TrimPrefix(formatClock(cue.Start, ".", 2), "0"),
+			strings.TrimPrefix(formatClock(cue.End, ".", 2), "0"),
+			text)
+	}
+	return b.String()
+}
+
+// microDVDRe matches a MicroDVD line, e.g. "{100}{250}Hello|World"
+var microDVDRe = regexp.MustCompile(`^\{(\d+)\}\{(\d*)\}(.*)$`)
+
+// parseMicroDVD parses frame-based MicroDVD text. A leading "{1}{1}25.000"
+// line declares the frame rate and takes precedence over frameRate.
+func parseMicroDVD(text string, frameRate float64) ([]Cue, error) {
+	if frameRate <= 0 {
+		frameRate = DefaultFrameRate
+	}
+
+	var cues []Cue
+	for i, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		matches := microDVDRe.FindStringSubmatch(line)
+		if matches == nil {
+			return nil, fmt.Errorf("invalid MicroDVD line %d: %q", i+1, line)
+		}
+
+		startFrame, _ := strconv.Atoi(matches[1])
+		endFrame, _ := strconv.Atoi(matches[2])
+		if len(cues) == 0 && startFrame <= 1 && endFrame <= 1 {
+			if fps, err := strconv.ParseFloat(strings.TrimSpace(matches[3]), 64); err == nil && fps > 0 {
+				frameRate = fps
+				continue
+			}
+		}
+
+		// Style codes like "{y:i}" apply to the whole line
+		text := matches[3]
+		italic := strings.Contains(strings.ToLower(text), "{y:i}")
+		text = assOverrideRe.ReplaceAllString(text, "")
+		text = strings.ReplaceAll(text, "|", "\n")
+		if italic {
+			text = "<i>" + text + "</i>"
+		}
+
+		cues = append(cues, Cue{
+			Start: framesToDuration(startFrame, frameRate),
+			End:   framesToDuration(endFrame, frameRate),
+			Text:  text,
+		})
+	}
+	return cues, nil
+}
+
+// formatMicroDVD writes cues as frame-based MicroDVD text, declaring the frame rate on the first line
+func formatMicroDVD(cues []Cue, frameRate float64) string {
+	if frameRate <= 0 {
+		frameRate = DefaultFrameRate
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "{1}{1}%s\n", strconv.FormatFloat(frameRate, 'f', 3, 64))
+	for _, cue := range cues {
+		text := htmlTagRe.ReplaceAllString(cue.Text, "")
+		fmt.Fprintf(&b, "{%d}{%d}%s\n",
+			durationToFrames(cue.Start, frameRate),
+			durationToFrames(cue.End, frameRate),
+			strings.ReplaceAll(text, "\n", "|"))
+	}
+	return b.String()
+}
+
+// framesToDuration converts a frame number to a timestamp
+func framesToDuration(frame int, frameRate float64) time.Duration {
+	return time.Duration(float64(frame) / frameRate * float64(time.Second))
+}
+
+// durationToFrames converts a timestamp to the nearest frame number
+func durationToFrames(d time.Duration, frameRate float64) int {
+	return int(math.Round(d.Seconds() * frameRate))
+}
@@ -0,0 +1,144 @@
+// Package subtitle converts subtitle files between the SubRip (.srt), WebVTT
+// (.vtt), Advanced SubStation Alpha (.ass/.ssa) and MicroDVD (.sub) formats.
+package subtitle
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultFrameRate is used for frame-based MicroDVD files that don't declare
+// their own frame rate
+const DefaultFrameRate = 23.976
+
+// Cue is a single subtitle shown between Start and End. Lines of Text are
+// separated by "\n"; italic, bold and underline use HTML-style tags.
+type Cue struct {
+	Start time.Duration
+	End   time.Duration
+	Text  string
+}
+
+// Formats lists the supported subtitle formats
+var Formats = []string{"srt", "vtt", "ass", "ssa", "sub"}
+
+// Parse reads cues from UTF-8 subtitle text in the given format.
+// frameRate is only used by MicroDVD; 0 uses the file's declared rate or DefaultFrameRate.
+func Parse(text, format string, frameRate float64) ([]Cue, error) {
+	text = strings.ReplaceAll(strings.TrimPrefix(text, "\uFEFF"), "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\r", "\n")
+
+	var cues []Cue
+	var err error
+	switch normalizeFormat(format) {
+	case "srt":
+		cues, err = parseSRT(text)
+	case "vtt":
+		cues, err = parseVTT(text)
+	case "ass", "ssa":
+		cues, err = parseASS(text)
+	case "sub":
+		cues, err = parseMicroDVD(text, frameRate)
+	default:
+		return nil, fmt.Errorf("unsupported subtitle format: %s", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(cues) == 0 {
+		return nil, fmt.Errorf("no subtitles found")
+	}
+	return cues, nil
+}
+
+// Format writes cues as subtitle text in the given format.
+// frameRate is only used by MicroDVD; 0 uses DefaultFrameRate.
+func Format(cues []Cue, format string, frameRate float64) (string, error) {
+	switch normalizeFormat(format) {
+	case "srt":
+		return formatSRT(cues), nil
+	case "vtt":
+		return formatVTT(cues), nil
+	case "ass", "ssa":
+		return formatASS(cues), nil
+	case "sub":
+		return formatMicroDVD(cues, frameRate), nil
+	default:
+		return "", fmt.Errorf("unsupported subtitle format: %s", format)
+	}
+}
+
+// normalizeFormat lowercases a format name and strips a leading dot
+func normalizeFormat(format string) string {
+	return strings.TrimPrefix(strings.ToLower(format), ".")
+}
+
+// splitBlocks splits text into blank-line separated blocks of lines
+func splitBlocks(text string) [][]string {
+	var blocks [][]string
+	var block []string
+	for _, line := range strings.Split(text, "\n") {
+		if strings.TrimSpace(line) == "" {
+			if len(block) > 0 {
+				blocks = append(blocks, block)
+				block = nil
+			}
+			continue
+		}
+		block = append(block, line)
+	}
+	if len(block) > 0 {
+		blocks = append(blocks, block)
+	}
+	return blocks
+}
+
+// parseClock parses an "hh:mm:ss.fff" or "mm:ss.fff" timestamp. The fraction
+// separator may be "." or "," and the fraction may have any number of digits.
+func parseClock(value string) (time.Duration, error) {
+	value = strings.TrimSpace(strings.Replace(value, ",", ".", 1))
+
+	parts := strings.Split(value, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, fmt.Errorf("invalid timestamp: %q", value)
+	}
+
+	var total time.Duration
+	units := []time.Duration{time.Hour, time.Minute}[3-len(parts):]
+	for i, unit := range units {
+		var n int
+		if _, err := fmt.Sscanf(parts[i], "%d", &n); err != nil {
+			return 0, fmt.Errorf("invalid timestamp: %q", value)
+		}
+		total += time.Duration(n) * unit
+	}
+
+	var seconds float64
+	if _, err := fmt.Sscanf(parts[len(parts)-1], "%g", &seconds); err != nil {
+		return 0, fmt.Errorf("invalid timestamp: %q", value)
+	}
+	return total + time.Duration(seconds*float64(time.Second)+0.5), nil
+}
+
+// formatClock formats a timestamp as "hh:mm:ss" followed by sep and the
+// fraction rounded to the given number of digits (2 or 3)
+func formatClock(d time.Duration, sep string, digits int) string {
+	if d < 0 {
+		d = 0
+	}
+	unit := time.Millisecond
+	if digits == 2 {
+		unit = 10 * time.Millisecond
+	}
+	d = d.Round(unit)
+
+	hours := d / time.Hour
+	d -= hours * time.Hour
+	minutes := d / time.Minute
+	d -= minutes * time.Minute
+	seconds := d / time.Second
+	d -= seconds * time.Second
+
+	return fmt.Sprintf("%02d:%02d:%02d%s%0*d", int(hours), int(minutes), int(seconds), sep, digits, int(d/unit))
+}
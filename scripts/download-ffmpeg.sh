@@ -27,7 +27,9 @@ JOBS=$(nproc 2>/dev/null || sysctl -n hw.ncpu 2>/dev/null || echo 4)
 mkdir -p "$BINARIES_DIR/darwin_arm64"
 mkdir -p "$BINARIES_DIR/darwin_amd64"
 mkdir -p "$BINARIES_DIR/linux_amd64"
+mkdir -p "$BINARIES_DIR/linux_arm64"
 mkdir -p "$BINARIES_DIR/windows_amd64"
+mkdir -p "$BINARIES_DIR/windows_arm64"
 mkdir -p "$BINARIES_DIR/source"
 
 echo "=============================================="
@@ -61,6 +63,17 @@ check_build_deps() {
     fi
 }
 
+# Write the checksum manifest embedded next to a binary, which the app
+# verifies the extracted binary against
+write_checksum() {
+    local binary=$1
+    if command -v sha256sum &> /dev/null; then
+        sha256sum "$binary" | cut -d' ' -f1 > "$binary.sha256"
+    else
+        shasum -a 256 "$binary" | cut -d' ' -f1 > "$binary.sha256"
+    fi
+}
+
 # Download and extract FFmpeg source
 # Note: Status messages go to stderr, only the path goes to stdout
 download_source() {
@@ -236,6 +249,7 @@ build_macos() {
         
         # Strip debug symbols to reduce size
         strip "$dest_dir/ffmpeg" 2>/dev/null || true
+        write_checksum "$dest_dir/ffmpeg"
         
         local size=$(du -h "$dest_dir/ffmpeg" | cut -f1)
         echo "  ✓ macOS $arch FFmpeg built successfully ($size)"
@@ -264,6 +278,26 @@ build_linux() {
         echo "  Attempting build anyway..."
     fi
     
+    # Set architecture-specific flags, cross-compiling when the host differs
+    local arch_flags=""
+    case $arch in
+        arm64)
+            arch_flags="--arch=aarch64"
+            if [[ "$(uname -m)" != "aarch64" && "$(uname -m)" != "arm64" ]]; then
+                if ! command -v aarch64-linux-gnu-gcc &> /dev/null; then
+                    echo "  ⚠ aarch64 cross-compiler not found."
+                    echo "  Install with: apt install gcc-aarch64-linux-gnu"
+                    echo "  Skipping linux_$arch..."
+                    return 1
+                fi
+                arch_flags="$arch_flags --enable-cross-compile --cross-prefix=aarch64-linux-gnu-"
+            fi
+            ;;
+        amd64)
+            arch_flags="--arch=x86_64"
+            ;;
+    esac
+    
     mkdir -p "$build_dir"
     cd "$build_dir"
     
@@ -303,7 +337,7 @@ build_linux() {
     "$source_dir/configure" \
         --prefix="$build_dir/install" \
         --target-os=linux \
-        --arch=x86_64 \
+        $arch_flags \
         $configure_opts \
         > configure.log 2>&1 || {
             echo "  ✗ Configure failed. See: $build_dir/configure.log"
@@ -321,6 +355,7 @@ build_linux() {
         cp "ffmpeg" "$dest_dir/ffmpeg"
         chmod +x "$dest_dir/ffmpeg"
         strip "$dest_dir/ffmpeg" 2>/dev/null || true
+        write_checksum "$dest_dir/ffmpeg"
         
         local size=$(du -h "$dest_dir/ffmpeg" | cut -f1)
         echo "  ✓ Linux $arch FFmpeg built successfully ($size)"
@@ -343,18 +378,35 @@ build_windows() {
     echo "Building FFmpeg for Windows $arch..."
     echo "----------------------------------------"
     
-    # Check for MinGW cross-compiler
+    # Check for MinGW cross-compiler (llvm-mingw for ARM64)
     local cross_prefix=""
-    if command -v x86_64-w64-mingw32-gcc &> /dev/null; then
-        cross_prefix="x86_64-w64-mingw32-"
-    elif command -v mingw-w64-gcc &> /dev/null; then
-        cross_prefix="mingw-w64-"
-    else
-        echo "  ⚠ MinGW cross-compiler not found."
-        echo "  Install with: brew install mingw-w64 (macOS) or apt install mingw-w64 (Linux)"
-        echo "  Skipping windows_$arch..."
-        return 1
-    fi
+    local target_arch=""
+    case $arch in
+        arm64)
+            target_arch="aarch64"
+            if command -v aarch64-w64-mingw32-gcc &> /dev/null; then
+                cross_prefix="aarch64-w64-mingw32-"
+            else
+                echo "  ⚠ ARM64 MinGW cross-compiler not found."
+                echo "  Install llvm-mingw: https://github.com/mstorsjo/llvm-mingw"
+                echo "  Skipping windows_$arch..."
+                return 1
+            fi
+            ;;
+        amd64)
+            target_arch="x86_64"
+            if command -v x86_64-w64-mingw32-gcc &> /dev/null; then
+                cross_prefix="x86_64-w64-mingw32-"
+            elif command -v mingw-w64-gcc &> /dev/null; then
+                cross_prefix="mingw-w64-"
+            else
+                echo "  ⚠ MinGW cross-compiler not found."
+                echo "  Install with: brew install mingw-w64 (macOS) or apt install mingw-w64 (Linux)"
+                echo "  Skipping windows_$arch..."
+                return 1
+            fi
+            ;;
+    esac
     
     mkdir -p "$build_dir"
     cd "$build_dir"
@@ -370,7 +422,7 @@ build_windows() {
     "$source_dir/configure" \
         --prefix="$build_dir/install" \
         --target-os=mingw32 \
-        --arch=x86_64 \
+        --arch=$target_arch \
         --cross-prefix="$cross_prefix" \
         --enable-cross-compile \
         $configure_opts \
@@ -389,6 +441,7 @@ build_windows() {
     if [ -f "ffmpeg.exe" ]; then
         cp "ffmpeg.exe" "$dest_dir/ffmpeg.exe"
         ${cross_prefix}strip "$dest_dir/ffmpeg.exe" 2>/dev/null || true
+        write_checksum "$dest_dir/ffmpeg.exe"
         
         local size=$(du -h "$dest_dir/ffmpeg.exe" | cut -f1)
         echo "  ✓ Windows $arch FFmpeg built successfully ($size)"
@@ -415,7 +468,9 @@ print_help() {
     echo "  darwin_arm64    macOS Apple Silicon"
     echo "  darwin_amd64    macOS Intel"
     echo "  linux_amd64     Linux x86_64"
+    echo "  linux_arm64     Linux ARM64 (cross-compiling requires gcc-aarch64-linux-gnu)"
     echo "  windows_amd64   Windows x86_64 (requires MinGW)"
+    echo "  windows_arm64   Windows ARM64 (requires llvm-mingw)"
     echo ""
     echo "If no platforms are specified, builds for current platform only."
     echo ""
@@ -487,10 +542,14 @@ detect_platform() {
         Linux)
             case "$arch" in
                 x86_64) echo "linux_amd64" ;;
+                aarch64|arm64) echo "linux_arm64" ;;
             esac
             ;;
         MINGW*|MSYS*|CYGWIN*)
-            echo "windows_amd64"
+            case "$arch" in
+                aarch64|arm64) echo "windows_arm64" ;;
+                *) echo "windows_amd64" ;;
+            esac
             ;;
     esac
 }
@@ -515,7 +574,7 @@ main() {
                 clean=true
                 shift
                 ;;
-            darwin_arm64|darwin_amd64|linux_amd64|windows_amd64)
+            darwin_arm64|darwin_amd64|linux_amd64|linux_arm64|windows_amd64|windows_arm64)
                 platforms+=("$1")
                 shift
                 ;;
@@ -577,6 +636,13 @@ main() {
                     ((failed++))
                 fi
                 ;;
+            linux_arm64)
+                if build_linux "arm64"; then
+                    ((success++))
+                else
+                    ((failed++))
+                fi
+                ;;
             windows_amd64)
                 if build_windows "amd64"; then
                     ((success++))
@@ -584,6 +650,13 @@ main() {
                     ((failed++))
                 fi
                 ;;
+            windows_arm64)
+                if build_windows "arm64"; then
+                    ((success++))
+                else
+                    ((failed++))
+                fi
+                ;;
         esac
     done
     